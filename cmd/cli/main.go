@@ -98,6 +98,10 @@ func main() {
 			fmt.Printf("│ Relationships: %-5d                │\n", info.RelationshipCount)
 			fmt.Printf("│ Communities:   %-5d                │\n", info.CommunityCount)
 			fmt.Printf("│ VectorDim:     %-5d                │\n", info.VectorDim)
+			fmt.Printf("│ Vectors:       %-5d (%d quantized) │\n", info.VectorMemory.Vectors, info.VectorMemory.QuantizedVectors)
+			fmt.Printf("│ Vector memory: %-8s of %-8s   │\n", formatBytes(info.VectorMemory.Bytes), formatBytes(info.VectorMemory.FullPrecisionBytes))
+			fmt.Printf("│ Tombstones:    %-5d                │\n", info.VectorMemory.Tombstones)
+			fmt.Printf("│ Est. recall:   %-5.3f                │\n", info.VectorMemory.Recall)
 			fmt.Println("└─────────────────────────────────────┘")
 
 		case "ADDDOC":
@@ -315,6 +319,261 @@ func main() {
 				}
 			}
 
+		case "QUERYBATCH":
+			// QUERYBATCH <count> [topK] [parallelism]
+			if len(args) < 1 {
+				fmt.Println("Usage: QUERYBATCH <count> [topK] [parallelism]")
+				continue
+			}
+			count, _ := strconv.Atoi(args[0])
+			topK := 10
+			parallelism := 0
+			if len(args) > 1 {
+				topK, _ = strconv.Atoi(args[1])
+			}
+			if len(args) > 2 {
+				parallelism, _ = strconv.Atoi(args[2])
+			}
+
+			// Generate random query vectors for testing
+			vectors := make([][]float32, count)
+			for i := range vectors {
+				vectors[i] = randomEmbedding(1536)
+			}
+
+			spec := types.QuerySpec{
+				SearchTypes:    []types.SearchType{types.SearchTypeTextUnit, types.SearchTypeEntity, types.SearchTypeCommunity},
+				TopK:           topK,
+				KHops:          2,
+				MaxEntities:    50,
+				MaxTextUnits:   10,
+				MaxCommunities: 5,
+			}
+
+			results, err := c.QueryBatchVectors(spec, vectors, parallelism)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				continue
+			}
+			for i, r := range results {
+				if r.Pack == nil {
+					fmt.Printf("  %d. Error: %s\n", i+1, r.Error)
+					continue
+				}
+				fmt.Printf("  %d. Query ID %d: %d textunits, %d entities, %d communities, %dμs\n", i+1, r.Pack.QueryID,
+					len(r.Pack.TextUnits), len(r.Pack.Entities), len(r.Pack.Communities), r.Pack.Stats.DurationMicros)
+			}
+
+		case "SEARCH":
+			// SEARCH <index> <k> [min_similarity]
+			if len(args) < 2 {
+				fmt.Println("Usage: SEARCH <textunit|entity|community> <k> [min_similarity]")
+				continue
+			}
+			k, _ := strconv.Atoi(args[1])
+			spec := types.SearchSpec{
+				Index:        types.SearchType(strings.ToLower(args[0])),
+				QueryVectors: [][]float32{randomEmbedding(1536)}, // random query vector for testing
+				K:            k,
+			}
+			if len(args) > 2 {
+				minSim, err := strconv.ParseFloat(args[2], 32)
+				if err != nil {
+					fmt.Printf("Error: invalid min_similarity: %s\n", args[2])
+					continue
+				}
+				threshold := float32(minSim)
+				spec.MinSimilarity = &threshold
+			}
+
+			results, err := c.Search(spec)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				continue
+			}
+			if len(results) == 0 || len(results[0]) == 0 {
+				fmt.Println("(empty)")
+				continue
+			}
+			for i, hit := range results[0] {
+				fmt.Printf("%d) id=%d ext=%s sim=%.3f\n", i+1, hit.ID, hit.ExternalID, hit.Similarity)
+			}
+
+		case "PATH":
+			// PATH <source> <target> [k] [WEIGHTED] [DIRECTED]
+			if len(args) < 2 {
+				fmt.Println("Usage: PATH <source_id|title> <target_id|title> [k] [WEIGHTED] [DIRECTED]")
+				continue
+			}
+			var spec types.PathSpec
+			if id, err := strconv.ParseUint(args[0], 10, 64); err == nil {
+				spec.SourceID = id
+			} else {
+				spec.SourceTitle = args[0]
+			}
+			if id, err := strconv.ParseUint(args[1], 10, 64); err == nil {
+				spec.TargetID = id
+			} else {
+				spec.TargetTitle = args[1]
+			}
+			for _, arg := range args[2:] {
+				switch strings.ToUpper(arg) {
+				case "WEIGHTED":
+					spec.Weighted = true
+				case "DIRECTED":
+					spec.Directed = true
+				default:
+					spec.K, _ = strconv.Atoi(arg)
+				}
+			}
+
+			paths, err := c.FindPaths(spec)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				continue
+			}
+			if len(paths) == 0 {
+				fmt.Println("(no path)")
+				continue
+			}
+			for i, p := range paths {
+				var b strings.Builder
+				b.WriteString(p.Entities[0].Title)
+				for j, rel := range p.Relationships {
+					if rel.SourceID == p.Entities[j].ID {
+						fmt.Fprintf(&b, " -[%s]-> %s", rel.Type, p.Entities[j+1].Title)
+					} else {
+						fmt.Fprintf(&b, " <-[%s]- %s", rel.Type, p.Entities[j+1].Title)
+					}
+				}
+				fmt.Printf("%d) cost=%.2f %s\n", i+1, p.Cost, b.String())
+			}
+
+		case "SUBGRAPH":
+			// SUBGRAPH <id[,id...]> [hops] [OUT|IN]
+			if len(args) < 1 {
+				fmt.Println("Usage: SUBGRAPH <id[,id...]> [hops] [OUT|IN]")
+				continue
+			}
+			spec := types.SubgraphSpec{Hops: 1}
+			for _, field := range strings.Split(args[0], ",") {
+				id, _ := strconv.ParseUint(field, 10, 64)
+				spec.EntityIDs = append(spec.EntityIDs, id)
+			}
+			for _, arg := range args[1:] {
+				switch strings.ToUpper(arg) {
+				case "OUT":
+					spec.Direction = types.DirectionOut
+				case "IN":
+					spec.Direction = types.DirectionIn
+				default:
+					spec.Hops, _ = strconv.Atoi(arg)
+				}
+			}
+
+			sub, err := c.Subgraph(spec)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				continue
+			}
+			fmt.Printf("Nodes (%d):\n", len(sub.Nodes))
+			for _, node := range sub.Nodes {
+				fmt.Printf("  [id=%d hop=%d in=%d out=%d] %s (%s)\n",
+					node.Entity.ID, node.Hop, node.InDegree, node.OutDegree, node.Entity.Title, node.Entity.Type)
+			}
+			fmt.Printf("Relationships (%d):\n", len(sub.Relationships))
+			for _, rel := range sub.Relationships {
+				fmt.Printf("  %d -[%s]-> %d\n", rel.SourceID, rel.Type, rel.TargetID)
+			}
+			if sub.Truncated {
+				fmt.Println("(truncated)")
+			}
+
+		case "MATCH":
+			// MATCH <pattern> [WHERE ...] RETURN ... [LIMIT n]
+			result, err := c.GraphQuery(line)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				continue
+			}
+			fmt.Println(strings.Join(result.Columns, "\t"))
+			for _, row := range result.Rows {
+				cells := make([]string, len(row))
+				for i, v := range row {
+					cells[i] = formatGraphValue(v)
+				}
+				fmt.Println(strings.Join(cells, "\t"))
+			}
+			fmt.Printf("(%d rows)\n", len(result.Rows))
+			if result.Truncated {
+				fmt.Println("(truncated)")
+			}
+			fmt.Println("Plan:")
+			for _, step := range result.Plan {
+				fmt.Printf("  %s\n", step)
+			}
+
+		case "GLOBAL":
+			// GLOBAL <level> [token_budget] [batch_tokens]
+			if len(args) < 1 {
+				fmt.Println("Usage: GLOBAL <level> [token_budget] [batch_tokens]")
+				continue
+			}
+			// No query vector from the shell: reports rank by community size
+			var spec types.GlobalSearchSpec
+			spec.Level, _ = strconv.Atoi(args[0])
+			if len(args) > 1 {
+				spec.TokenBudget, _ = strconv.Atoi(args[1])
+			}
+			if len(args) > 2 {
+				spec.BatchTokens, _ = strconv.Atoi(args[2])
+			}
+
+			result, err := c.GlobalSearch(spec)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				continue
+			}
+			if result.Level != result.RequestedLevel {
+				fmt.Printf("Level %d is sparse, searched level %d\n", result.RequestedLevel, result.Level)
+			}
+			fmt.Printf("Candidates: %d, tokens used: %d, skipped: %d\n", result.Candidates, result.TokensUsed, result.Skipped)
+			for i, batch := range result.Batches {
+				fmt.Printf("Batch %d (%d tokens):\n", i+1, batch.Tokens)
+				for _, r := range batch.Reports {
+					fmt.Printf("  #%d [id=%d score=%.4f tokens=%d] %s\n", r.Rank, r.Community.ID, r.Score, r.Tokens, r.Community.Title)
+				}
+			}
+
+		case "RECALL":
+			// RECALL [index] [k] [samples]
+			var spec types.RecallSpec
+			if len(args) > 0 {
+				spec.Index = types.SearchType(strings.ToLower(args[0]))
+			}
+			if len(args) > 1 {
+				spec.K, _ = strconv.Atoi(args[1])
+			}
+			if len(args) > 2 {
+				spec.Samples, _ = strconv.Atoi(args[2])
+			}
+
+			reports, err := c.CheckRecall(spec)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				continue
+			}
+			if len(reports) == 0 {
+				fmt.Println("(empty)")
+				continue
+			}
+			for _, r := range reports {
+				fmt.Printf("%s: recall@%d=%.3f min=%.3f ef=%d queries=%d/%d vectors\n",
+					r.Index, r.K, r.Recall, r.MinRecall, r.Ef, r.Queries, r.Vectors)
+				fmt.Printf("  latency p50=%.0fus p99=%.0fus (exact p50=%.0fus) visited avg=%.0f\n",
+					r.LatencyUs.P50, r.LatencyUs.P99, r.ExactLatencyUs.P50, r.Visited.Avg)
+			}
+
 		case "EXPLAIN":
 			// EXPLAIN <query_id>
 			if len(args) < 1 {
@@ -346,6 +605,24 @@ func main() {
 				fmt.Printf("  Hop %d: %d -[%s]-> %d (weight=%.2f)\n",
 					step.Hop, step.FromEntityID, step.RelType, step.ToEntityID, step.Weight)
 			}
+			if len(explain.Demotions) > 0 {
+				fmt.Printf("\nDemotions (%d):\n", len(explain.Demotions))
+				for i, d := range explain.Demotions {
+					if i >= 10 {
+						fmt.Printf("  ... and %d more\n", len(explain.Demotions)-10)
+						break
+					}
+					newRank := "dropped"
+					if d.NewRank > 0 {
+						newRank = fmt.Sprintf("#%d", d.NewRank)
+					}
+					fmt.Printf("  - [%s] id=%d #%d -> %s (%s", d.Type, d.ID, d.Rank, newRank, d.Reason)
+					if d.SimilarTo != 0 {
+						fmt.Printf(", sim=%.3f to %d", d.Similarity, d.SimilarTo)
+					}
+					fmt.Println(")")
+				}
+			}
 
 		// DEPRECATED: TTL commands removed - session-level management only
 		/*
@@ -386,6 +663,121 @@ func main() {
 				}
 		*/
 
+		case "SLOWLOG":
+			// SLOWLOG GET [count] | LEN | RESET
+			if len(args) < 1 {
+				fmt.Println("Usage: SLOWLOG GET [count] | LEN | RESET")
+				continue
+			}
+			switch strings.ToUpper(args[0]) {
+			case "GET":
+				count := 0
+				if len(args) > 1 {
+					count, _ = strconv.Atoi(args[1])
+				}
+				entries, err := c.SlowLogGet(count)
+				if err != nil {
+					fmt.Printf("Error: %v\n", err)
+					continue
+				}
+				if len(entries) == 0 {
+					fmt.Println("(empty)")
+					continue
+				}
+				for _, e := range entries {
+					fmt.Printf("%d) %s %s %v session=%s key=%s client=%s\n",
+						e.ID, time.Unix(e.Timestamp, 0).Format(time.RFC3339), e.Command, e.Duration,
+						e.SessionID, e.KeyID, e.ClientAddr)
+					if e.Args != "" {
+						fmt.Printf("   args: %s\n", e.Args)
+					}
+					if e.QueryID != 0 {
+						fmt.Printf("   query_id: %d (EXPLAIN %d)\n", e.QueryID, e.QueryID)
+					}
+				}
+			case "LEN":
+				n, err := c.SlowLogLen()
+				if err != nil {
+					fmt.Printf("Error: %v\n", err)
+				} else {
+					fmt.Printf("%d\n", n)
+				}
+			case "RESET":
+				if err := c.SlowLogReset(); err != nil {
+					fmt.Printf("Error: %v\n", err)
+				} else {
+					fmt.Println("OK")
+				}
+			default:
+				fmt.Println("Usage: SLOWLOG GET [count] | LEN | RESET")
+			}
+
+		case "REBUILD":
+			// REBUILD [ASYNC] | REBUILD STATUS [task_id]
+			if len(args) > 0 && strings.ToUpper(args[0]) == "STATUS" {
+				taskID := ""
+				if len(args) > 1 {
+					taskID = args[1]
+				}
+				tasks, err := c.RebuildStatus(taskID)
+				if err != nil {
+					fmt.Printf("Error: %v\n", err)
+					continue
+				}
+				if len(tasks) == 0 {
+					fmt.Println("(empty)")
+				}
+				for _, t := range tasks {
+					fmt.Printf("%s %s index=%s progress=%.0f%%", t.ID, t.Status, t.Index, t.Progress*100)
+					if t.Error != "" {
+						fmt.Printf(" error=%s", t.Error)
+					}
+					fmt.Println()
+				}
+				continue
+			}
+			async := len(args) > 0 && strings.ToUpper(args[0]) == "ASYNC"
+			task, err := c.RebuildIndex(client.RebuildOptions{Async: async})
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+			} else if task != nil {
+				fmt.Printf("OK - Rebuild task %s %s\n", task.ID, task.Status)
+			} else {
+				fmt.Println("OK - Indices rebuilt")
+			}
+
+		case "BULK":
+			// BULK BEGIN | BULK COMMIT [workers] [ASYNC]
+			if len(args) == 0 {
+				fmt.Println("Usage: BULK BEGIN | BULK COMMIT [workers] [ASYNC]")
+				continue
+			}
+			switch strings.ToUpper(args[0]) {
+			case "BEGIN":
+				if err := c.BeginBulkLoad(); err != nil {
+					fmt.Printf("Error: %v\n", err)
+				} else {
+					fmt.Println("OK - Vector indexing deferred until BULK COMMIT")
+				}
+			case "COMMIT":
+				workers, async := 0, false
+				for _, arg := range args[1:] {
+					if strings.ToUpper(arg) == "ASYNC" {
+						async = true
+					} else if n, err := strconv.Atoi(arg); err == nil {
+						workers = n
+					}
+				}
+				task, err := c.CommitBulkLoad(workers, async)
+				if err != nil {
+					fmt.Printf("Error: %v\n", err)
+				} else if task != nil {
+					fmt.Printf("OK - Bulk build task %s %s\n", task.ID, task.Status)
+				}
+			default:
+				fmt.Println("Usage: BULK BEGIN | BULK COMMIT [workers] [ASYNC]")
+			}
+
 		case "SNAPSHOT", "SAVE":
 			if err := c.Save(""); err != nil {
 				fmt.Printf("Error: %v\n", err)
@@ -416,11 +808,28 @@ func printHelp() {
   COMMUNITY COMPUTE [resolution]          Compute communities (Leiden)
 
   QUERY <topK> <hops> [maxEnts] [maxTUs]  Vector + graph query
+  QUERYBATCH <n> [topK] [parallelism]     n random-vector queries in one batch
   EXPLAIN <query_id>                      Explain query path
+  SEARCH <index> <k> [min_similarity]     Nearest neighbors, no graph expansion
+  RECALL [index] [k] [samples]            Compare HNSW recall with exact search
+  PATH <src> <tgt> [k] [WEIGHTED]         Shortest paths between two entities
+  SUBGRAPH <ids> [hops] [OUT|IN]          Neighbourhood of comma-separated entities
+  GLOBAL <level> [budget] [batch]         Community reports batched for map-reduce
+  MATCH <pattern> [WHERE] RETURN [LIMIT]  Graph pattern query, e.g.
+      MATCH (o:organization)-[:REGULATES]->(c) RETURN o, c.title
 
   SETTTL <type> <id> <seconds>            Set TTL
   TTL <type> <id>                         Get remaining TTL
 
+  SLOWLOG GET [count]                     Show slowest recent commands
+  SLOWLOG LEN                             Number of slow log entries
+  SLOWLOG RESET                           Clear the slow log
+
+  REBUILD [ASYNC]                         Rebuild vector indices online
+  REBUILD STATUS [task_id]                Show index rebuild progress
+  BULK BEGIN                              Defer vector indexing for a bulk load
+  BULK COMMIT [workers] [ASYNC]           Build deferred vectors concurrently
+
   SNAPSHOT                                Force snapshot
   HELP                                    Show this help
   QUIT                                    Exit`)
@@ -444,3 +853,35 @@ func randomEmbedding(dim int) []float32 {
 	}
 	return vec
 }
+
+// formatBytes renders a byte count with a binary unit
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// formatGraphValue renders one cell of a graph query row
+func formatGraphValue(v types.GraphValue) string {
+	switch x := v.Value.(type) {
+	case nil:
+		switch {
+		case v.Entity != nil:
+			return fmt.Sprintf("%s (%s) [id=%d]", v.Entity.Title, v.Entity.Type, v.Entity.ID)
+		case v.Relationship != nil:
+			return fmt.Sprintf("%d -[%s]-> %d", v.Relationship.SourceID, v.Relationship.Type, v.Relationship.TargetID)
+		}
+		return "null"
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	default:
+		return fmt.Sprint(x)
+	}
+}
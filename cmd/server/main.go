@@ -4,6 +4,7 @@ package main
 import (
 	"compress/gzip"
 	"context"
+	"errors"
 	"flag"
 	"io"
 	"os"
@@ -32,6 +33,8 @@ func main() {
 	vectorDim := flag.Int("dim", 0, "Vector dimension (override config)")
 	insecure := flag.Bool("insecure", false, "Run in insecure mode (no TLS, no auth) - DEV ONLY")
 	logLevel := flag.String("log-level", "", "Log level (override config)")
+	metricsAddr := flag.String("metrics-addr", "", "Prometheus metrics HTTP address (override config)")
+	adminAddr := flag.String("admin-addr", "", "Admin HTTP address for health, readiness and pprof (override config)")
 	sessionCleanupInterval := flag.Duration("session-cleanup-interval", 60*time.Second, "Session cleanup interval")
 	flag.Parse()
 
@@ -56,6 +59,9 @@ func main() {
 		DataDir:   *dataDir,
 		VectorDim: *vectorDim,
 		LogLevel:  *logLevel,
+
+		MetricsAddr: *metricsAddr,
+		AdminAddr:   *adminAddr,
 	})
 
 	// Initialize logger from config
@@ -172,6 +178,10 @@ func main() {
 		srv.SetWAL(wal)
 	}
 
+	// Per-command instrumentation and limit rejection counters
+	srv.SetMetrics(metricsCollector)
+	srv.SetMemoryTracker(memTracker)
+
 	// Setup snapshot callback - Production-grade implementation
 	srv.SetSnapshotCallback(func(path string) error {
 		if path == "" {
@@ -259,9 +269,15 @@ func main() {
 
 		log.Info("Restoring snapshot from %s", path)
 
-		// Restore engine state
+		// Restore engine state; sessions that fail are skipped
 		if err := eng.Restore(reader); err != nil {
-			return err
+			var partial *engine.SessionRestoreError
+			if !errors.As(err, &partial) {
+				return err
+			}
+			for id, serr := range partial.Failed {
+				log.Warn("Session %s not restored: %v", id, serr)
+			}
 		}
 
 		info := eng.Info()
@@ -277,6 +293,22 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Optional Prometheus endpoint
+	if cfg.Metrics.Addr != "" {
+		if err := srv.StartMetrics(cfg.Metrics.Addr, cfg.Metrics.Path); err != nil {
+			log.Error("Failed to start metrics listener: %v", err)
+			os.Exit(1)
+		}
+	}
+
+	// Optional admin endpoint (healthz, readyz, pprof)
+	if cfg.Admin.Addr != "" {
+		if err := srv.StartAdmin(cfg.Admin.Addr); err != nil {
+			log.Error("Failed to start admin listener: %v", err)
+			os.Exit(1)
+		}
+	}
+
 	// Print info
 	info := eng.Info()
 	log.Info("Server ready!")
@@ -291,6 +323,18 @@ func main() {
 	shutdownHandler.SetTimeout(30 * time.Second)
 
 	// Register shutdown hooks in order
+	shutdownHandler.Register("drain", 5, func(ctx context.Context) error {
+		srv.Drain()
+		if cfg.Admin.DrainDelay > 0 {
+			log.Info("Draining for %s", cfg.Admin.DrainDelay)
+			select {
+			case <-time.After(cfg.Admin.DrainDelay):
+			case <-ctx.Done():
+			}
+		}
+		return nil
+	})
+
 	shutdownHandler.Register("server", 10, func(ctx context.Context) error {
 		srv.Stop()
 		return nil
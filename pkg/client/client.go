@@ -582,6 +582,30 @@ func (c *Client) Ping() error {
 // Session Management Commands
 // =============================================================================
 
+// SessionOptions configures a session created with CreateSession. Zero
+// values use the server defaults: the server vector dimension, cosine
+// similarity and the default HNSW parameters.
+type SessionOptions struct {
+	VectorDim      int
+	TextUnitIndex  types.IndexConfig
+	EntityIndex    types.IndexConfig
+	CommunityIndex types.IndexConfig
+}
+
+// CreateSession explicitly creates the current session with the given
+// options. It fails if the session already exists.
+func (c *Client) CreateSession(opts SessionOptions) error {
+	req := &pb.CreateSessionRequest{
+		SessionId:      c.sessionID,
+		VectorDim:      uint32(opts.VectorDim),
+		TextunitIndex:  indexOptionsPB(opts.TextUnitIndex),
+		EntityIndex:    indexOptionsPB(opts.EntityIndex),
+		CommunityIndex: indexOptionsPB(opts.CommunityIndex),
+	}
+	_, err := c.send(pb.CommandType_CMD_CREATE_SESSION, req)
+	return err
+}
+
 // ListSessions returns all active sessions on the server
 func (c *Client) ListSessions() ([]types.SessionInfo, error) {
 	resp, err := c.send(pb.CommandType_CMD_LIST_SESSIONS, nil)
@@ -607,12 +631,89 @@ func (c *Client) ListSessions() ([]types.SessionInfo, error) {
 			EntityCount:       int(s.EntityCount),
 			RelationshipCount: int(s.RelationshipCount),
 			CommunityCount:    int(s.CommunityCount),
+			VectorDim:         int(s.VectorDim),
+			BulkLoading:       s.BulkLoading,
+			PendingVectors:    int(s.PendingVectors),
+			TextUnitIndex:     indexConfigFromPB(s.TextunitIndex),
+			EntityIndex:       indexConfigFromPB(s.EntityIndex),
+			CommunityIndex:    indexConfigFromPB(s.CommunityIndex),
+			QueryCacheSize:    int(s.QueryCacheSize),
+			QueryCacheTTL:     s.QueryCacheTtl,
+			QueryCacheEntries: int(s.QueryCacheEntries),
+			QueryCacheHits:    s.QueryCacheHits,
+			QueryCacheMisses:  s.QueryCacheMisses,
 		}
 	}
 
 	return sessions, nil
 }
 
+// SessionInfo returns information about the current session
+func (c *Client) SessionInfo() (*types.SessionInfo, error) {
+	resp, err := c.send(pb.CommandType_CMD_SESSION_INFO, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var s pb.SessionInfo
+	if err := proto.Unmarshal(resp.Payload, &s); err != nil {
+		return nil, err
+	}
+	return &types.SessionInfo{
+		ID:                s.SessionId,
+		CreatedAt:         s.CreatedAt,
+		LastAccess:        s.LastAccess,
+		TTL:               s.Ttl,
+		IdleTTL:           s.IdleTtl,
+		DocumentCount:     int(s.DocumentCount),
+		TextUnitCount:     int(s.TextunitCount),
+		EntityCount:       int(s.EntityCount),
+		RelationshipCount: int(s.RelationshipCount),
+		CommunityCount:    int(s.CommunityCount),
+		VectorDim:         int(s.VectorDim),
+		BulkLoading:       s.BulkLoading,
+		PendingVectors:    int(s.PendingVectors),
+		TextUnitIndex:     indexConfigFromPB(s.TextunitIndex),
+		EntityIndex:       indexConfigFromPB(s.EntityIndex),
+		CommunityIndex:    indexConfigFromPB(s.CommunityIndex),
+		QueryCacheSize:    int(s.QueryCacheSize),
+		QueryCacheTTL:     s.QueryCacheTtl,
+		QueryCacheEntries: int(s.QueryCacheEntries),
+		QueryCacheHits:    s.QueryCacheHits,
+		QueryCacheMisses:  s.QueryCacheMisses,
+	}, nil
+}
+
+func indexOptionsPB(config types.IndexConfig) *pb.IndexOptions {
+	return &pb.IndexOptions{
+		Metric:         config.Metric,
+		M:              uint32(config.M),
+		EfConstruction: uint32(config.EfConstruction),
+		EfSearch:       uint32(config.EfSearch),
+
+		Quantization:    config.Quantization,
+		PqSubvectors:    uint32(config.PQSubvectors),
+		KeepFullVectors: config.KeepFullVectors,
+
+		TombstoneRatio: config.TombstoneRatio,
+	}
+}
+
+func indexConfigFromPB(opts *pb.IndexOptions) types.IndexConfig {
+	return types.IndexConfig{
+		Metric:         opts.GetMetric(),
+		M:              int(opts.GetM()),
+		EfConstruction: int(opts.GetEfConstruction()),
+		EfSearch:       int(opts.GetEfSearch()),
+
+		Quantization:    opts.GetQuantization(),
+		PQSubvectors:    int(opts.GetPqSubvectors()),
+		KeepFullVectors: opts.GetKeepFullVectors(),
+
+		TombstoneRatio: opts.GetTombstoneRatio(),
+	}
+}
+
 // DeleteSession deletes a specific session (requires admin permission)
 func (c *Client) DeleteSession(sessionID string) error {
 	// Override client's sessionID temporarily for this admin operation
@@ -634,6 +735,18 @@ func (c *Client) SetSessionTTL(ttl, idleTTL int64) error {
 	return err
 }
 
+// SetQueryCache turns on the query result cache of the current session,
+// holding at most maxEntries results for at most ttl (0 = no expiry), or
+// turns it off when maxEntries is 0
+func (c *Client) SetQueryCache(maxEntries int, ttl time.Duration) error {
+	req := &pb.SetQueryCacheRequest{
+		MaxEntries: uint32(maxEntries),
+		Ttl:        int64(ttl / time.Second),
+	}
+	_, err := c.send(pb.CommandType_CMD_SET_QUERY_CACHE, req)
+	return err
+}
+
 // TouchSession updates last access time for current session
 func (c *Client) TouchSession() error {
 	_, err := c.send(pb.CommandType_CMD_TOUCH_SESSION, nil)
@@ -663,6 +776,14 @@ func (c *Client) Info() (*types.ServerInfo, error) {
 		RelationshipCount: int(infoResp.RelationshipCount),
 		CommunityCount:    int(infoResp.CommunityCount),
 		VectorDim:         int(infoResp.VectorDim),
+		VectorMemory: types.VectorMemory{
+			Vectors:            int(infoResp.VectorCount),
+			QuantizedVectors:   int(infoResp.QuantizedVectorCount),
+			Tombstones:         int(infoResp.VectorTombstones),
+			Bytes:              int64(infoResp.VectorMemoryBytes),
+			FullPrecisionBytes: int64(infoResp.VectorFullPrecisionBytes),
+			Recall:             infoResp.QuantizationRecall,
+		},
 	}, nil
 }
 
@@ -1037,11 +1158,173 @@ func (c *Client) HierarchicalLeidenDefault() (*HierarchicalLeidenResult, error)
 	return c.HierarchicalLeiden(5, 1.0)
 }
 
+// =============================================================================
+// Index Rebuild Commands
+// =============================================================================
+
+// RebuildOptions configures RebuildIndex. A non-nil index configuration
+// replaces that index's configuration (zero fields use the server
+// defaults); nil keeps the current one.
+type RebuildOptions struct {
+	TextUnitIndex  *types.IndexConfig
+	EntityIndex    *types.IndexConfig
+	CommunityIndex *types.IndexConfig
+	Async          bool // return the running task instead of waiting
+}
+
+// RebuildIndex rebuilds the session's vector indices online; queries keep
+// being served while the rebuild runs. It returns the rebuild task, or nil
+// for a synchronous rebuild without new configurations.
+func (c *Client) RebuildIndex(opts RebuildOptions) (*types.RebuildTask, error) {
+	req := &pb.RebuildIndexRequest{Async: opts.Async}
+	if opts.TextUnitIndex != nil {
+		req.TextunitIndex = indexOptionsPB(*opts.TextUnitIndex)
+	}
+	if opts.EntityIndex != nil {
+		req.EntityIndex = indexOptionsPB(*opts.EntityIndex)
+	}
+	if opts.CommunityIndex != nil {
+		req.CommunityIndex = indexOptionsPB(*opts.CommunityIndex)
+	}
+
+	resp, err := c.send(pb.CommandType_CMD_REBUILD_INDEX, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.CmdType != pb.CommandType_CMD_REBUILD_STATUS_RESPONSE {
+		return nil, nil
+	}
+
+	tasks, err := rebuildTasksFromPB(resp.Payload)
+	if err != nil || len(tasks) == 0 {
+		return nil, err
+	}
+	return &tasks[0], nil
+}
+
+// RebuildStatus returns a rebuild task of the session, or all of them
+// when taskID is empty
+func (c *Client) RebuildStatus(taskID string) ([]types.RebuildTask, error) {
+	resp, err := c.send(pb.CommandType_CMD_REBUILD_STATUS, &pb.RebuildStatusRequest{TaskId: taskID})
+	if err != nil {
+		return nil, err
+	}
+	return rebuildTasksFromPB(resp.Payload)
+}
+
+// BeginBulkLoad defers vector index insertion for the session until
+// CommitBulkLoad, so large MSET loads only store objects
+func (c *Client) BeginBulkLoad() error {
+	_, err := c.send(pb.CommandType_CMD_BULK_BEGIN, nil)
+	return err
+}
+
+// CommitBulkLoad builds the vectors deferred since BeginBulkLoad with
+// workers goroutines (0 = one per server CPU). With async it returns the
+// running task, whose progress RebuildStatus reports.
+func (c *Client) CommitBulkLoad(workers int, async bool) (*types.RebuildTask, error) {
+	resp, err := c.send(pb.CommandType_CMD_BULK_COMMIT, &pb.BulkCommitRequest{Workers: int32(workers), Async: async})
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := rebuildTasksFromPB(resp.Payload)
+	if err != nil || len(tasks) == 0 {
+		return nil, err
+	}
+	return &tasks[0], nil
+}
+
+func rebuildTasksFromPB(payload []byte) ([]types.RebuildTask, error) {
+	var statusResp pb.RebuildStatusResponse
+	if err := proto.Unmarshal(payload, &statusResp); err != nil {
+		return nil, err
+	}
+
+	tasks := make([]types.RebuildTask, len(statusResp.Tasks))
+	for i, t := range statusResp.Tasks {
+		tasks[i] = types.RebuildTask{
+			ID:        t.TaskId,
+			SessionID: t.SessionId,
+			Status:    t.Status,
+			Index:     t.Index,
+			Progress:  t.Progress,
+			StartTime: t.StartTime,
+			EndTime:   t.EndTime,
+			Error:     t.Error,
+		}
+	}
+	return tasks, nil
+}
+
 // =============================================================================
 // Query Commands
 // =============================================================================
 
 func (c *Client) Query(spec types.QuerySpec) (*types.ContextPack, error) {
+	resp, err := c.send(pb.CommandType_CMD_QUERY, queryRequestPB(spec))
+	if err != nil {
+		return nil, err
+	}
+
+	var queryResp pb.QueryResponse
+	if err := proto.Unmarshal(resp.Payload, &queryResp); err != nil {
+		return nil, err
+	}
+	return contextPackFromPB(&queryResp), nil
+}
+
+// QueryBatch runs specs concurrently on the server, with up to parallelism
+// at once (0 = one per CPU). Results are in spec order; a failed query
+// carries its error instead of a ContextPack.
+func (c *Client) QueryBatch(specs []types.QuerySpec, parallelism int) ([]types.BatchQueryResult, error) {
+	req := &pb.QueryBatchRequest{
+		Queries:     make([]*pb.QueryRequest, len(specs)),
+		Parallelism: int32(parallelism),
+	}
+	for i, spec := range specs {
+		req.Queries[i] = queryRequestPB(spec)
+	}
+	return c.queryBatch(req)
+}
+
+// QueryBatchVectors runs one query per vector, each with the shared spec
+// and that vector as its query vector
+func (c *Client) QueryBatchVectors(shared types.QuerySpec, vectors [][]float32, parallelism int) ([]types.BatchQueryResult, error) {
+	shared.QueryVector = nil
+	req := &pb.QueryBatchRequest{
+		Shared:       queryRequestPB(shared),
+		QueryVectors: make([]*pb.SearchVector, len(vectors)),
+		Parallelism:  int32(parallelism),
+	}
+	for i, v := range vectors {
+		req.QueryVectors[i] = &pb.SearchVector{Values: v}
+	}
+	return c.queryBatch(req)
+}
+
+func (c *Client) queryBatch(req *pb.QueryBatchRequest) ([]types.BatchQueryResult, error) {
+	resp, err := c.send(pb.CommandType_CMD_QUERY_BATCH, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var batchResp pb.QueryBatchResponse
+	if err := proto.Unmarshal(resp.Payload, &batchResp); err != nil {
+		return nil, err
+	}
+
+	results := make([]types.BatchQueryResult, len(batchResp.Results))
+	for i, r := range batchResp.Results {
+		results[i].Error = r.Error
+		if r.Response != nil {
+			results[i].Pack = contextPackFromPB(r.Response)
+		}
+	}
+	return results, nil
+}
+
+// queryRequestPB converts a query spec for the wire
+func queryRequestPB(spec types.QuerySpec) *pb.QueryRequest {
 	// Convert search types to strings (proto uses repeated string)
 	var searchTypes []string
 	for _, st := range spec.SearchTypes {
@@ -1056,49 +1339,90 @@ func (c *Client) Query(spec types.QuerySpec) (*types.ContextPack, error) {
 		MaxTextunits:   int32(spec.MaxTextUnits),
 		MaxCommunities: int32(spec.MaxCommunities),
 		SearchTypes:    searchTypes,
-	}
 
-	resp, err := c.send(pb.CommandType_CMD_QUERY, req)
-	if err != nil {
-		return nil, err
-	}
+		FilterEntityTypes: spec.FilterEntityTypes,
+		FilterDocumentIds: spec.FilterDocumentIDs,
+		FilterAttrs:       spec.FilterAttrs,
 
-	var queryResp pb.QueryResponse
-	if err := proto.Unmarshal(resp.Payload, &queryResp); err != nil {
-		return nil, err
+		QueryText:     spec.QueryText,
+		Fusion:        string(spec.Fusion),
+		LexicalWeight: spec.LexicalWeight,
+
+		Scoring:     string(spec.Scoring),
+		Decay:       spec.Decay,
+		Aggregation: string(spec.Aggregation),
+
+		Expansion:      string(spec.Expansion),
+		Damping:        spec.Damping,
+		Iterations:     int32(spec.Iterations),
+		Restart:        string(spec.Restart),
+		RestartWeights: spec.RestartWeights,
+
+		TokenBudget:       int32(spec.TokenBudget),
+		EntityShare:       spec.EntityShare,
+		RelationshipShare: spec.RelationshipShare,
+		TextunitShare:     spec.TextUnitShare,
+		CommunityShare:    spec.CommunityShare,
+		ContextFormat:     string(spec.ContextFormat),
+
+		Rerank:         string(spec.Rerank),
+		MmrLambda:      spec.MMRLambda,
+		DedupThreshold: spec.DedupThreshold,
+		MaxPerDocument: int32(spec.MaxPerDocument),
+
+		NoCache: spec.NoCache,
 	}
 
+	return req
+}
+
+// contextPackFromPB converts a query response from the wire
+func contextPackFromPB(resp *pb.QueryResponse) *types.ContextPack {
 	result := &types.ContextPack{
-		QueryID: queryResp.QueryId,
+		QueryID: resp.QueryId,
 		Stats: types.QueryStats{
-			DurationMicros: queryResp.Stats.DurationMicros,
+			DurationMicros: resp.Stats.DurationMicros,
+			CacheHit:       resp.Stats.CacheHit,
 		},
+		Context: resp.Context,
+	}
+	if u := resp.TokenUsage; u != nil {
+		result.TokenUsage = &types.TokenUsage{
+			Entities:      int(u.Entities),
+			Relationships: int(u.Relationships),
+			TextUnits:     int(u.Textunits),
+			Communities:   int(u.Communities),
+			Total:         int(u.Total),
+		}
 	}
 
-	for _, tu := range queryResp.Textunits {
+	for _, tu := range resp.Textunits {
 		result.TextUnits = append(result.TextUnits, types.TextUnitResult{
 			TextUnit:   codec.ProtoToTextUnit(tu.Textunit),
+			Score:      tu.Score,
 			Similarity: tu.Similarity,
 			Hop:        int(tu.Hop),
 		})
 	}
 
-	for _, ent := range queryResp.Entities {
+	for _, ent := range resp.Entities {
 		result.Entities = append(result.Entities, types.EntityResult{
 			Entity:     codec.ProtoToEntity(ent.Entity),
+			Score:      ent.Score,
 			Similarity: ent.Similarity,
 			Hop:        int(ent.Hop),
 		})
 	}
 
-	for _, comm := range queryResp.Communities {
+	for _, comm := range resp.Communities {
 		result.Communities = append(result.Communities, types.CommunityResult{
 			Community:  codec.ProtoToCommunity(comm.Community),
+			Score:      comm.Score,
 			Similarity: comm.Similarity,
 		})
 	}
 
-	for _, rel := range queryResp.Relationships {
+	for _, rel := range resp.Relationships {
 		result.Relationships = append(result.Relationships, types.RelationshipResult{
 			Relationship: codec.ProtoToRelationship(rel.Relationship),
 			SourceTitle:  rel.SourceTitle,
@@ -1106,9 +1430,274 @@ func (c *Client) Query(spec types.QuerySpec) (*types.ContextPack, error) {
 		})
 	}
 
+	return result
+}
+
+// Search runs k-NN or range search on one vector index without graph
+// expansion, returning one hit list per query vector
+func (c *Client) Search(spec types.SearchSpec) ([][]types.SearchHit, error) {
+	req := &pb.SearchRequest{
+		Index:          string(spec.Index),
+		QueryVectors:   make([]*pb.SearchVector, len(spec.QueryVectors)),
+		K:              int32(spec.K),
+		Ef:             int32(spec.Ef),
+		Range:          spec.Range,
+		IncludeObjects: spec.IncludeObjects,
+
+		FilterEntityTypes: spec.FilterEntityTypes,
+		FilterDocumentIds: spec.FilterDocumentIDs,
+		FilterAttrs:       spec.FilterAttrs,
+	}
+	for i, v := range spec.QueryVectors {
+		req.QueryVectors[i] = &pb.SearchVector{Values: v}
+	}
+	if spec.MinSimilarity != nil {
+		req.MinSimilarity = *spec.MinSimilarity
+		req.HasMinSimilarity = true
+	}
+
+	resp, err := c.send(pb.CommandType_CMD_SEARCH, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var searchResp pb.SearchResponse
+	if err := proto.Unmarshal(resp.Payload, &searchResp); err != nil {
+		return nil, err
+	}
+
+	results := make([][]types.SearchHit, len(searchResp.Results))
+	for i, list := range searchResp.Results {
+		hits := make([]types.SearchHit, len(list.Hits))
+		for j, hit := range list.Hits {
+			hits[j] = types.SearchHit{
+				ID:         hit.Id,
+				ExternalID: hit.ExternalId,
+				Similarity: hit.Similarity,
+			}
+			if hit.Textunit != nil {
+				hits[j].TextUnit = codec.ProtoToTextUnit(hit.Textunit)
+			}
+			if hit.Entity != nil {
+				hits[j].Entity = codec.ProtoToEntity(hit.Entity)
+			}
+			if hit.Community != nil {
+				hits[j].Community = codec.ProtoToCommunity(hit.Community)
+			}
+		}
+		results[i] = hits
+	}
+
+	return results, nil
+}
+
+// FindPaths returns the shortest paths between two entities, cheapest
+// first, with the entities and relationships along each path
+func (c *Client) FindPaths(spec types.PathSpec) ([]types.EntityPath, error) {
+	req := &pb.PathRequest{
+		SourceId:    spec.SourceID,
+		SourceTitle: spec.SourceTitle,
+		TargetId:    spec.TargetID,
+		TargetTitle: spec.TargetTitle,
+		K:           int32(spec.K),
+		MaxDepth:    int32(spec.MaxDepth),
+		Directed:    spec.Directed,
+		Weighted:    spec.Weighted,
+		RelTypes:    spec.RelTypes,
+	}
+
+	resp, err := c.send(pb.CommandType_CMD_PATH, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathResp pb.PathResponse
+	if err := proto.Unmarshal(resp.Payload, &pathResp); err != nil {
+		return nil, err
+	}
+
+	paths := make([]types.EntityPath, len(pathResp.Paths))
+	for i, p := range pathResp.Paths {
+		path := types.EntityPath{
+			Entities:      make([]*types.Entity, len(p.Entities)),
+			Relationships: make([]*types.Relationship, len(p.Relationships)),
+			Cost:          p.Cost,
+		}
+		for j, ent := range p.Entities {
+			path.Entities[j] = codec.ProtoToEntity(ent)
+		}
+		for j, rel := range p.Relationships {
+			path.Relationships[j] = codec.ProtoToRelationship(rel)
+		}
+		paths[i] = path
+	}
+	return paths, nil
+}
+
+// Subgraph returns the entities within a hop radius of the given entities
+// and every relationship among them, with degree counts
+func (c *Client) Subgraph(spec types.SubgraphSpec) (*types.Subgraph, error) {
+	req := &pb.SubgraphRequest{
+		EntityIds:   spec.EntityIDs,
+		Hops:        int32(spec.Hops),
+		Direction:   string(spec.Direction),
+		EntityTypes: spec.EntityTypes,
+		RelTypes:    spec.RelTypes,
+		MaxNodes:    int32(spec.MaxNodes),
+		MaxEdges:    int32(spec.MaxEdges),
+	}
+
+	resp, err := c.send(pb.CommandType_CMD_SUBGRAPH, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var subResp pb.SubgraphResponse
+	if err := proto.Unmarshal(resp.Payload, &subResp); err != nil {
+		return nil, err
+	}
+
+	sub := &types.Subgraph{
+		Nodes:         make([]types.SubgraphNode, len(subResp.Nodes)),
+		Relationships: make([]*types.Relationship, len(subResp.Relationships)),
+		Truncated:     subResp.Truncated,
+	}
+	for i, node := range subResp.Nodes {
+		sub.Nodes[i] = types.SubgraphNode{
+			Entity:    codec.ProtoToEntity(node.Entity),
+			Hop:       int(node.Hop),
+			InDegree:  int(node.InDegree),
+			OutDegree: int(node.OutDegree),
+			Degree:    int(node.Degree),
+		}
+	}
+	for i, rel := range subResp.Relationships {
+		sub.Relationships[i] = codec.ProtoToRelationship(rel)
+	}
+	return sub, nil
+}
+
+// GraphQuery runs a Cypher-like pattern query over the session graph,
+// e.g. MATCH (o:organization)-[:REGULATES]->(c:concept) RETURN o, c LIMIT 10
+func (c *Client) GraphQuery(query string) (*types.GraphQueryResult, error) {
+	resp, err := c.send(pb.CommandType_CMD_QUERY_GRAPH, &pb.GraphQueryRequest{Query: query})
+	if err != nil {
+		return nil, err
+	}
+
+	var gqResp pb.GraphQueryResponse
+	if err := proto.Unmarshal(resp.Payload, &gqResp); err != nil {
+		return nil, err
+	}
+
+	result := &types.GraphQueryResult{
+		Columns:   gqResp.Columns,
+		Rows:      make([][]types.GraphValue, len(gqResp.Rows)),
+		Plan:      gqResp.Plan,
+		Truncated: gqResp.Truncated,
+	}
+	for i, row := range gqResp.Rows {
+		result.Rows[i] = make([]types.GraphValue, len(row.Values))
+		for j, v := range row.Values {
+			result.Rows[i][j] = codec.ProtoToGraphValue(v)
+		}
+	}
 	return result, nil
 }
 
+// GlobalSearch ranks the community reports of a level against the query
+// vector and returns those fitting the token budget, grouped into map
+// batches for a map-reduce answer
+func (c *Client) GlobalSearch(spec types.GlobalSearchSpec) (*types.GlobalSearchResult, error) {
+	req := &pb.GlobalSearchRequest{
+		QueryVector:    spec.QueryVector,
+		Level:          int32(spec.Level),
+		MinCommunities: int32(spec.MinCommunities),
+		TokenBudget:    int32(spec.TokenBudget),
+		BatchTokens:    int32(spec.BatchTokens),
+		UseSummary:     spec.UseSummary,
+	}
+
+	resp, err := c.send(pb.CommandType_CMD_GLOBAL_SEARCH, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var gsResp pb.GlobalSearchResponse
+	if err := proto.Unmarshal(resp.Payload, &gsResp); err != nil {
+		return nil, err
+	}
+
+	result := &types.GlobalSearchResult{
+		Level:          int(gsResp.Level),
+		RequestedLevel: int(gsResp.RequestedLevel),
+		Candidates:     int(gsResp.Candidates),
+		Batches:        make([]types.ReportBatch, len(gsResp.Batches)),
+		TokensUsed:     int(gsResp.TokensUsed),
+		Skipped:        int(gsResp.Skipped),
+	}
+	for i, batch := range gsResp.Batches {
+		reports := make([]types.CommunityReport, len(batch.Reports))
+		for j, r := range batch.Reports {
+			reports[j] = types.CommunityReport{
+				Community: codec.ProtoToCommunity(r.Community),
+				Score:     r.Score,
+				Rank:      int(r.Rank),
+				Tokens:    int(r.Tokens),
+			}
+		}
+		result.Batches[i] = types.ReportBatch{Reports: reports, Tokens: int(batch.Tokens)}
+	}
+	return result, nil
+}
+
+// CheckRecall compares HNSW search with exact search on the session's
+// vector indices and reports recall@k, latency and nodes visited for each
+// (admin permission required)
+func (c *Client) CheckRecall(spec types.RecallSpec) ([]types.IndexRecall, error) {
+	req := &pb.RecallCheckRequest{
+		Index:        string(spec.Index),
+		K:            int32(spec.K),
+		Ef:           int32(spec.Ef),
+		Samples:      int32(spec.Samples),
+		QueryVectors: make([]*pb.SearchVector, len(spec.QueryVectors)),
+	}
+	for i, v := range spec.QueryVectors {
+		req.QueryVectors[i] = &pb.SearchVector{Values: v}
+	}
+
+	resp, err := c.send(pb.CommandType_CMD_RECALL_CHECK, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var recallResp pb.RecallCheckResponse
+	if err := proto.Unmarshal(resp.Payload, &recallResp); err != nil {
+		return nil, err
+	}
+
+	reports := make([]types.IndexRecall, len(recallResp.Indices))
+	for i, r := range recallResp.Indices {
+		reports[i] = types.IndexRecall{
+			Index:          types.SearchType(r.Index),
+			Vectors:        int(r.Vectors),
+			Queries:        int(r.Queries),
+			K:              int(r.K),
+			Ef:             int(r.Ef),
+			Recall:         r.Recall,
+			MinRecall:      r.MinRecall,
+			LatencyUs:      distributionFromPB(r.LatencyMicros),
+			ExactLatencyUs: distributionFromPB(r.ExactLatencyMicros),
+			Visited:        distributionFromPB(r.Visited),
+		}
+	}
+	return reports, nil
+}
+
+func distributionFromPB(d *pb.Distribution) types.Distribution {
+	return types.Distribution{Avg: d.GetAvg(), P50: d.GetP50(), P95: d.GetP95(), P99: d.GetP99(), Max: d.GetMax()}
+}
+
 func (c *Client) Explain(queryID uint64) (*types.ExplainPack, error) {
 	req := &pb.ExplainRequest{QueryId: queryID}
 
@@ -1132,6 +1721,7 @@ func (c *Client) Explain(queryID uint64) (*types.ExplainPack, error) {
 			ID:         seed.Id,
 			ExternalID: seed.ExternalId,
 			Similarity: seed.Similarity,
+			Lexical:    seed.Lexical,
 		})
 	}
 
@@ -1143,6 +1733,20 @@ func (c *Client) Explain(queryID uint64) (*types.ExplainPack, error) {
 			RelType:        step.RelType,
 			Weight:         step.Weight,
 			Hop:            int(step.Hop),
+			Cumulative:     step.CumulativeScore,
+		})
+	}
+
+	for _, d := range explainResp.Demotions {
+		result.Demotions = append(result.Demotions, types.Demotion{
+			Type:       types.SearchType(d.Type),
+			ID:         d.Id,
+			ExternalID: d.ExternalId,
+			Reason:     types.DemotionReason(d.Reason),
+			Rank:       int(d.Rank),
+			NewRank:    int(d.NewRank),
+			SimilarTo:  d.SimilarTo,
+			Similarity: d.Similarity,
 		})
 	}
 
@@ -1200,6 +1804,16 @@ func (c *Client) GetTTL(itemType types.ItemType, id uint64) (int64, error) {
 // =============================================================================
 
 func (c *Client) MSetEntities(entities []types.BulkEntityInput) ([]uint64, error) {
+	return c.msetEntities(entities, false)
+}
+
+// MSetEntitiesBulk adds entities and builds their vectors concurrently once
+// all are stored. Within BeginBulkLoad the vectors wait for CommitBulkLoad.
+func (c *Client) MSetEntitiesBulk(entities []types.BulkEntityInput) ([]uint64, error) {
+	return c.msetEntities(entities, true)
+}
+
+func (c *Client) msetEntities(entities []types.BulkEntityInput, bulk bool) ([]uint64, error) {
 	var pbEntities []*pb.AddEntityRequest
 	for _, e := range entities {
 		pbEntities = append(pbEntities, &pb.AddEntityRequest{
@@ -1211,7 +1825,7 @@ func (c *Client) MSetEntities(entities []types.BulkEntityInput) ([]uint64, error
 		})
 	}
 
-	req := &pb.MSetEntitiesRequest{Entities: pbEntities}
+	req := &pb.MSetEntitiesRequest{Entities: pbEntities, Bulk: bulk}
 	resp, err := c.send(pb.CommandType_CMD_MSET_ENTITIES, req)
 	if err != nil {
 		return nil, err
@@ -1313,6 +1927,17 @@ func (c *Client) MGetDocuments(ids []uint64) ([]*types.Document, error) {
 }
 
 func (c *Client) MSetTextUnits(tus []types.BulkTextUnitInput) ([]uint64, error) {
+	return c.msetTextUnits(tus, false)
+}
+
+// MSetTextUnitsBulk adds text units and builds their vectors concurrently
+// once all are stored. Within BeginBulkLoad the vectors wait for
+// CommitBulkLoad.
+func (c *Client) MSetTextUnitsBulk(tus []types.BulkTextUnitInput) ([]uint64, error) {
+	return c.msetTextUnits(tus, true)
+}
+
+func (c *Client) msetTextUnits(tus []types.BulkTextUnitInput, bulk bool) ([]uint64, error) {
 	var pbTUs []*pb.AddTextUnitRequest
 	for _, t := range tus {
 		pbTUs = append(pbTUs, &pb.AddTextUnitRequest{
@@ -1324,7 +1949,7 @@ func (c *Client) MSetTextUnits(tus []types.BulkTextUnitInput) ([]uint64, error)
 		})
 	}
 
-	req := &pb.MSetTextUnitsRequest{Textunits: pbTUs}
+	req := &pb.MSetTextUnitsRequest{Textunits: pbTUs, Bulk: bulk}
 	resp, err := c.send(pb.CommandType_CMD_MSET_TEXTUNITS, req)
 	if err != nil {
 		return nil, err
@@ -1500,3 +2125,70 @@ func (c *Client) BackupStatus() (*BackupStatus, error) {
 		LastSavePath: bsResp.LastSavePath,
 	}, nil
 }
+
+// =============================================================================
+// Slow Log Commands
+// =============================================================================
+
+// SlowLogEntry is a command that exceeded the server's slow log threshold
+type SlowLogEntry struct {
+	ID         uint64
+	Timestamp  int64 // unix seconds
+	Duration   time.Duration
+	SessionID  string
+	KeyID      string
+	Command    string
+	Args       string
+	QueryID    uint64 // for QUERY; pass to Explain
+	ClientAddr string
+}
+
+// SlowLogGet returns up to count slow log entries, newest first
+// (0 = server default, -1 = all)
+func (c *Client) SlowLogGet(count int) ([]SlowLogEntry, error) {
+	resp, err := c.send(pb.CommandType_CMD_SLOWLOG_GET, &pb.SlowLogGetRequest{Count: int32(count)})
+	if err != nil {
+		return nil, err
+	}
+
+	var slResp pb.SlowLogResponse
+	if err := proto.Unmarshal(resp.Payload, &slResp); err != nil {
+		return nil, err
+	}
+
+	entries := make([]SlowLogEntry, 0, len(slResp.Entries))
+	for _, e := range slResp.Entries {
+		entries = append(entries, SlowLogEntry{
+			ID:         e.Id,
+			Timestamp:  e.Timestamp,
+			Duration:   time.Duration(e.DurationMicros) * time.Microsecond,
+			SessionID:  e.SessionId,
+			KeyID:      e.KeyId,
+			Command:    e.Command,
+			Args:       e.Args,
+			QueryID:    e.QueryId,
+			ClientAddr: e.ClientAddr,
+		})
+	}
+	return entries, nil
+}
+
+// SlowLogLen returns the number of entries in the slow log
+func (c *Client) SlowLogLen() (int, error) {
+	resp, err := c.send(pb.CommandType_CMD_SLOWLOG_LEN, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	var slResp pb.SlowLogResponse
+	if err := proto.Unmarshal(resp.Payload, &slResp); err != nil {
+		return 0, err
+	}
+	return int(slResp.Length), nil
+}
+
+// SlowLogReset clears the slow log
+func (c *Client) SlowLogReset() error {
+	_, err := c.send(pb.CommandType_CMD_SLOWLOG_RESET, nil)
+	return err
+}
@@ -2,7 +2,10 @@
 package client
 
 import (
+	"fmt"
 	"net"
+	"reflect"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -655,6 +658,483 @@ func TestClient_Query(t *testing.T) {
 	}
 }
 
+func TestClient_Search(t *testing.T) {
+	ts := startTestServer(t)
+	defer ts.Stop()
+
+	client, err := NewClient(ts.addr, testSessionID)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer closeClient(t, client)
+
+	embedding := make([]float32, 64)
+	for i := range embedding {
+		embedding[i] = float32(i) / 64.0
+	}
+	mustAddEntity(t, client, "ent-001", "Test Entity", "test", "Description", embedding)
+
+	threshold := float32(0.99)
+	results, err := client.Search(types.SearchSpec{
+		Index:          types.SearchTypeEntity,
+		QueryVectors:   [][]float32{embedding},
+		MinSimilarity:  &threshold,
+		Range:          true,
+		IncludeObjects: true,
+	})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 || len(results[0]) != 1 {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	hit := results[0][0]
+	if hit.ExternalID != "ent-001" || hit.Entity == nil || hit.Entity.Type != "test" || hit.Similarity < threshold {
+		t.Errorf("unexpected hit: %+v", hit)
+	}
+
+	if _, err := client.Search(types.SearchSpec{Index: "bogus", QueryVectors: [][]float32{embedding}}); err == nil {
+		t.Error("Search on an unknown index should fail")
+	}
+}
+
+func TestClient_QueryWeightedScoring(t *testing.T) {
+	ts := startTestServer(t)
+	defer ts.Stop()
+
+	client, err := NewClient(ts.addr, testSessionID)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer closeClient(t, client)
+
+	seedVec := make([]float32, 64)
+	seedVec[0] = 1
+	otherVec := make([]float32, 64)
+	otherVec[1] = 1
+	seed := mustAddEntity(t, client, "seed", "Seed", "test", "Description", seedVec)
+	other := mustAddEntity(t, client, "other", "Other", "test", "Description", otherVec)
+	mustAddRelationship(t, client, "rel", seed, other, "r", "", 1)
+
+	result, err := client.Query(types.QuerySpec{
+		QueryVector: seedVec,
+		SearchTypes: []types.SearchType{types.SearchTypeEntity},
+		TopK:        1,
+		KHops:       1,
+		Scoring:     types.ScoringWeighted,
+		Aggregation: types.AggregateSum,
+	})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(result.Entities) != 2 || result.Entities[0].Score <= result.Entities[1].Score || result.Entities[1].Score <= 0 {
+		t.Fatalf("weighted query entities = %+v, want seed above a scored neighbour", result.Entities)
+	}
+
+	explain, err := client.Explain(result.QueryID)
+	if err != nil {
+		t.Fatalf("Explain failed: %v", err)
+	}
+	if len(explain.Traversal) != 1 || explain.Traversal[0].Cumulative != result.Entities[1].Score {
+		t.Errorf("explain traversal = %+v, want cumulative score %v", explain.Traversal, result.Entities[1].Score)
+	}
+}
+
+func TestClient_FindPaths(t *testing.T) {
+	ts := startTestServer(t)
+	defer ts.Stop()
+
+	client, err := NewClient(ts.addr, testSessionID)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer closeClient(t, client)
+
+	a := mustAddEntity(t, client, "a", "A", "test", "Description", make([]float32, 64))
+	b := mustAddEntity(t, client, "b", "B", "test", "Description", make([]float32, 64))
+	c := mustAddEntity(t, client, "c", "C", "test", "Description", make([]float32, 64))
+	mustAddRelationship(t, client, "ab", a, b, "r", "a to b", 1)
+	mustAddRelationship(t, client, "bc", b, c, "r", "b to c", 1)
+	mustAddRelationship(t, client, "ac", a, c, "r", "a to c", 0.25)
+
+	paths, err := client.FindPaths(types.PathSpec{SourceID: a, TargetTitle: "C", K: 2, Weighted: true})
+	if err != nil {
+		t.Fatalf("FindPaths failed: %v", err)
+	}
+	if len(paths) != 2 || paths[0].Cost != 2 || paths[1].Cost != 4 {
+		t.Fatalf("weighted paths = %+v, want costs 2 then 4", paths)
+	}
+	if paths[0].Entities[1].ID != b || paths[0].Relationships[0].Description != "a to b" || paths[1].Relationships[0].Description != "a to c" {
+		t.Errorf("unexpected path details: %+v", paths)
+	}
+}
+
+func TestClient_Subgraph(t *testing.T) {
+	ts := startTestServer(t)
+	defer ts.Stop()
+
+	client, err := NewClient(ts.addr, testSessionID)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer closeClient(t, client)
+
+	a := mustAddEntity(t, client, "a", "A", "person", "Description", make([]float32, 64))
+	b := mustAddEntity(t, client, "b", "B", "person", "Description", make([]float32, 64))
+	c := mustAddEntity(t, client, "c", "C", "org", "Description", make([]float32, 64))
+	mustAddRelationship(t, client, "ab", a, b, "knows", "", 1)
+	mustAddRelationship(t, client, "bc", b, c, "works_at", "", 1)
+
+	sub, err := client.Subgraph(types.SubgraphSpec{EntityIDs: []uint64{a}, Hops: 2, EntityTypes: []string{"person"}})
+	if err != nil {
+		t.Fatalf("Subgraph failed: %v", err)
+	}
+	if len(sub.Nodes) != 2 || sub.Nodes[1].Entity.ID != b || sub.Nodes[1].Hop != 1 || sub.Nodes[1].OutDegree != 1 || sub.Nodes[1].Degree != 1 {
+		t.Fatalf("person subgraph = %+v, want a and b", sub.Nodes)
+	}
+	if len(sub.Relationships) != 1 || sub.Relationships[0].TargetID != b {
+		t.Errorf("person subgraph relationships = %+v, want a -> b", sub.Relationships)
+	}
+
+	sub, err = client.Subgraph(types.SubgraphSpec{EntityIDs: []uint64{a}, Hops: 2})
+	if err != nil || len(sub.Nodes) != 3 || sub.Nodes[2].Entity.ID != c || sub.Nodes[2].Hop != 2 {
+		t.Errorf("unfiltered subgraph = %+v, %v; want c at hop 2", sub, err)
+	}
+}
+
+func TestClient_GraphQuery(t *testing.T) {
+	ts := startTestServer(t)
+	defer ts.Stop()
+
+	client, err := NewClient(ts.addr, testSessionID)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer closeClient(t, client)
+
+	a := mustAddEntity(t, client, "a", "A", "person", "Description", make([]float32, 64))
+	b := mustAddEntity(t, client, "b", "B", "person", "Description", make([]float32, 64))
+	c := mustAddEntity(t, client, "c", "C", "org", "Description", make([]float32, 64))
+	mustAddRelationship(t, client, "ab", a, b, "knows", "", 1)
+	mustAddRelationship(t, client, "bc", b, c, "works_at", "", 1)
+
+	result, err := client.GraphQuery("MATCH (p:person)-[:KNOWS]->(q)-[:WORKS_AT]->(o:org) RETURN p, q.title AS colleague, o.id")
+	if err != nil {
+		t.Fatalf("GraphQuery failed: %v", err)
+	}
+	if want := []string{"p", "colleague", "o.id"}; !reflect.DeepEqual(result.Columns, want) {
+		t.Errorf("columns = %v, want %v", result.Columns, want)
+	}
+	if len(result.Rows) != 1 {
+		t.Fatalf("rows = %+v, want one", result.Rows)
+	}
+	row := result.Rows[0]
+	if row[0].Entity == nil || row[0].Entity.ID != a || row[1].Value != "B" || row[2].Value != float64(c) {
+		t.Errorf("row = %+v, want a, \"B\", %d", row, c)
+	}
+
+	if _, err := client.GraphQuery("MATCH (p) RETURN p LIMIT 0"); err == nil {
+		t.Error("GraphQuery with LIMIT 0 should fail")
+	}
+}
+
+func TestClient_GlobalSearch(t *testing.T) {
+	ts := startTestServer(t)
+	defer ts.Stop()
+
+	client, err := NewClient(ts.addr, testSessionID)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer closeClient(t, client)
+
+	a := mustAddEntity(t, client, "a", "A", "person", "Description", make([]float32, 64))
+	b := mustAddEntity(t, client, "b", "B", "person", "Description", make([]float32, 64))
+	if _, err := client.AddCommunity("big", "Big", "Summary of a and b", "Report", 0, []uint64{a, b}, nil, make([]float32, 64)); err != nil {
+		t.Fatalf("AddCommunity failed: %v", err)
+	}
+	if _, err := client.AddCommunity("small", "Small", "Summary of a", "Report", 0, []uint64{a}, nil, make([]float32, 64)); err != nil {
+		t.Fatalf("AddCommunity failed: %v", err)
+	}
+
+	result, err := client.GlobalSearch(types.GlobalSearchSpec{Level: 0, UseSummary: true})
+	if err != nil {
+		t.Fatalf("GlobalSearch failed: %v", err)
+	}
+	if result.Candidates != 2 || len(result.Batches) != 1 || len(result.Batches[0].Reports) != 2 {
+		t.Fatalf("GlobalSearch = %+v, want both reports in one batch", result)
+	}
+	first := result.Batches[0].Reports[0]
+	if first.Community.Title != "Big" || first.Rank != 1 || first.Tokens != types.EstimateTokens("Big")+types.EstimateTokens("Summary of a and b") {
+		t.Errorf("first report = %+v, want the larger community with summary tokens", first)
+	}
+	if result.TokensUsed != result.Batches[0].Tokens {
+		t.Errorf("tokens used = %d, want the batch total %d", result.TokensUsed, result.Batches[0].Tokens)
+	}
+}
+
+func TestClient_QueryTokenBudget(t *testing.T) {
+	ts := startTestServer(t)
+	defer ts.Stop()
+
+	client, err := NewClient(ts.addr, testSessionID)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer closeClient(t, client)
+
+	vec := make([]float32, 64)
+	vec[0] = 1
+	mustAddEntity(t, client, "a", "A", "person", "Description", vec)
+
+	spec := types.DefaultQuerySpec()
+	spec.QueryVector = vec
+	spec.TokenBudget = 50
+	spec.ContextFormat = types.ContextFormatCSV
+	result, err := client.Query(spec)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	want := types.EstimateTokens("A") + types.EstimateTokens("person") + types.EstimateTokens("Description")
+	if result.TokenUsage == nil || result.TokenUsage.Entities != want || result.TokenUsage.Total != want {
+		t.Errorf("token usage = %+v, want %d entity tokens", result.TokenUsage, want)
+	}
+	if !strings.Contains(result.Context, "-----Entities-----") || !strings.Contains(result.Context, "|A|person|Description") {
+		t.Errorf("context = %q, want a csv entity table", result.Context)
+	}
+}
+
+func TestClient_QueryRerank(t *testing.T) {
+	ts := startTestServer(t)
+	defer ts.Stop()
+
+	client, err := NewClient(ts.addr, testSessionID)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer closeClient(t, client)
+
+	docs := make([]uint64, 2)
+	for i := range docs {
+		if docs[i], err = client.AddDocument(fmt.Sprintf("doc-%d", i), "doc.txt"); err != nil {
+			t.Fatalf("AddDocument failed: %v", err)
+		}
+	}
+	vec := make([]float32, 64)
+	vec[0] = 1
+	for i, docID := range []uint64{docs[0], docs[0], docs[1]} {
+		if _, err := client.AddTextUnit(fmt.Sprintf("tu-%d", i), docID, "text", vec, 1); err != nil {
+			t.Fatalf("AddTextUnit failed: %v", err)
+		}
+	}
+
+	spec := types.DefaultQuerySpec()
+	spec.QueryVector = vec
+	spec.SearchTypes = []types.SearchType{types.SearchTypeTextUnit}
+	spec.MaxPerDocument = 1
+	result, err := client.Query(spec)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(result.TextUnits) != 2 || result.TextUnits[0].TextUnit.DocumentID == result.TextUnits[1].TextUnit.DocumentID {
+		t.Fatalf("Query returned %+v, want one text unit per document", result.TextUnits)
+	}
+
+	explain, err := client.Explain(result.QueryID)
+	if err != nil {
+		t.Fatalf("Explain failed: %v", err)
+	}
+	if len(explain.Demotions) != 1 || explain.Demotions[0].Type != types.SearchTypeTextUnit ||
+		explain.Demotions[0].Reason != types.DemotedDocumentCap || explain.Demotions[0].ExternalID == "" {
+		t.Errorf("Explain demotions = %+v, want one text unit dropped by the document cap", explain.Demotions)
+	}
+}
+
+func TestClient_QueryCache(t *testing.T) {
+	ts := startTestServer(t)
+	defer ts.Stop()
+
+	client, err := NewClient(ts.addr, testSessionID)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer closeClient(t, client)
+
+	vec := make([]float32, 64)
+	vec[0] = 1
+	mustAddEntity(t, client, "e1", "E1", "t", "", vec)
+	if err := client.SetQueryCache(4, time.Minute); err != nil {
+		t.Fatalf("SetQueryCache failed: %v", err)
+	}
+
+	spec := types.DefaultQuerySpec()
+	spec.QueryVector = vec
+	spec.SearchTypes = []types.SearchType{types.SearchTypeEntity}
+	for i, wantHit := range []bool{false, true} {
+		result, err := client.Query(spec)
+		if err != nil {
+			t.Fatalf("Query failed: %v", err)
+		}
+		if result.Stats.CacheHit != wantHit {
+			t.Errorf("query %d: cache hit = %v, want %v", i, result.Stats.CacheHit, wantHit)
+		}
+	}
+	spec.NoCache = true
+	if result, err := client.Query(spec); err != nil || result.Stats.CacheHit {
+		t.Errorf("NoCache query hit the cache (err %v)", err)
+	}
+
+	info, err := client.SessionInfo()
+	if err != nil {
+		t.Fatalf("SessionInfo failed: %v", err)
+	}
+	if info.QueryCacheSize != 4 || info.QueryCacheTTL != 60 || info.QueryCacheHits != 1 || info.QueryCacheMisses != 1 {
+		t.Errorf("SessionInfo cache = %d/%ds, %d hits, %d misses; want 4/60s, 1, 1",
+			info.QueryCacheSize, info.QueryCacheTTL, info.QueryCacheHits, info.QueryCacheMisses)
+	}
+}
+
+func TestClient_QueryBatch(t *testing.T) {
+	ts := startTestServer(t)
+	defer ts.Stop()
+
+	client, err := NewClient(ts.addr, testSessionID)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer closeClient(t, client)
+
+	vecs := make([][]float32, 3)
+	ids := make([]uint64, 3)
+	for i := range vecs {
+		vecs[i] = make([]float32, 64)
+		vecs[i][i] = 1
+		ids[i] = mustAddEntity(t, client, fmt.Sprintf("e%d", i), fmt.Sprintf("E%d", i), "t", "", vecs[i])
+	}
+
+	shared := types.DefaultQuerySpec()
+	shared.SearchTypes = []types.SearchType{types.SearchTypeEntity}
+	shared.TopK = 1
+	specs := make([]types.QuerySpec, len(vecs))
+	for i := range specs {
+		specs[i] = shared
+		specs[i].QueryVector = vecs[i]
+	}
+	specs[2].Rerank = "shuffle"
+
+	results, err := client.QueryBatch(specs, 0)
+	if err != nil {
+		t.Fatalf("QueryBatch failed: %v", err)
+	}
+	if len(results) != 3 || results[2].Pack != nil || results[2].Error == "" {
+		t.Fatalf("QueryBatch returned %+v, want 3 results with the last failed", results)
+	}
+	for i, r := range results[:2] {
+		if r.Pack == nil || len(r.Pack.Entities) == 0 || r.Pack.Entities[0].Entity.ID != ids[i] {
+			t.Errorf("query %d = %+v, want top entity %d", i, r, ids[i])
+		}
+	}
+	if results[0].Pack.QueryID == results[1].Pack.QueryID {
+		t.Error("batch queries share a query ID")
+	}
+
+	results, err = client.QueryBatchVectors(shared, vecs, 2)
+	if err != nil {
+		t.Fatalf("QueryBatchVectors failed: %v", err)
+	}
+	for i, r := range results {
+		if r.Pack == nil || len(r.Pack.Entities) == 0 || r.Pack.Entities[0].Entity.ID != ids[i] {
+			t.Errorf("vector %d = %+v, want top entity %d", i, r, ids[i])
+			continue
+		}
+		if _, err := client.Explain(r.Pack.QueryID); err != nil {
+			t.Errorf("Explain of vector %d failed: %v", i, err)
+		}
+	}
+
+	if _, err := client.QueryBatch(nil, 0); err == nil {
+		t.Error("expected error for an empty batch")
+	}
+}
+
+func TestClient_CheckRecall(t *testing.T) {
+	ts := startTestServer(t)
+	defer ts.Stop()
+
+	client, err := NewClient(ts.addr, testSessionID)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer closeClient(t, client)
+
+	for i := 0; i < 10; i++ {
+		// distinct angles so exact neighbors have no ties
+		embedding := make([]float32, 64)
+		embedding[0] = 1
+		embedding[1] = float32(i)
+		mustAddEntity(t, client, fmt.Sprintf("ent-%d", i), fmt.Sprintf("Entity %d", i), "test", "Description", embedding)
+	}
+
+	reports, err := client.CheckRecall(types.RecallSpec{Index: types.SearchTypeEntity, K: 3})
+	if err != nil {
+		t.Fatalf("CheckRecall failed: %v", err)
+	}
+	if len(reports) != 1 || reports[0].Vectors != 10 || reports[0].Queries != 10 || reports[0].Recall < 0.5 {
+		t.Errorf("unexpected recall reports: %+v", reports)
+	}
+	if reports[0].LatencyUs.Max < reports[0].LatencyUs.P50 {
+		t.Errorf("latency max %.1f below p50 %.1f", reports[0].LatencyUs.Max, reports[0].LatencyUs.P50)
+	}
+}
+
+func TestClient_BulkLoad(t *testing.T) {
+	ts := startTestServer(t)
+	defer ts.Stop()
+
+	client, err := NewClient(ts.addr, testSessionID)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer closeClient(t, client)
+
+	entities := func(prefix string) []types.BulkEntityInput {
+		out := make([]types.BulkEntityInput, 5)
+		for i := range out {
+			embedding := make([]float32, 64)
+			embedding[i] = 1
+			out[i] = types.BulkEntityInput{ExternalID: fmt.Sprintf("%s-%d", prefix, i), Title: fmt.Sprintf("%s %d", prefix, i), Type: "test", Embedding: embedding}
+		}
+		return out
+	}
+	if ids, err := client.MSetEntitiesBulk(entities("req")); err != nil || len(ids) != 5 {
+		t.Fatalf("MSetEntitiesBulk() = %v, %v", ids, err)
+	}
+
+	if err := client.BeginBulkLoad(); err != nil {
+		t.Fatalf("BeginBulkLoad failed: %v", err)
+	}
+	if _, err := client.MSetEntities(entities("ses")); err != nil {
+		t.Fatalf("MSetEntities failed: %v", err)
+	}
+	info, err := client.SessionInfo()
+	if err != nil || !info.BulkLoading || info.PendingVectors != 5 {
+		t.Fatalf("SessionInfo during bulk load = %+v, %v", info, err)
+	}
+	task, err := client.CommitBulkLoad(2, false)
+	if err != nil {
+		t.Fatalf("CommitBulkLoad failed: %v", err)
+	}
+	if task == nil || task.Status != "complete" {
+		t.Errorf("CommitBulkLoad task = %+v, want complete", task)
+	}
+	if info, _ := client.SessionInfo(); info.BulkLoading || info.PendingVectors != 0 {
+		t.Errorf("SessionInfo after commit = %+v", info)
+	}
+	if _, err := client.CommitBulkLoad(0, false); err == nil {
+		t.Error("CommitBulkLoad without a bulk load should fail")
+	}
+}
+
 // =============================================================================
 // Client Operation Tests - TTL
 // =============================================================================
@@ -1985,3 +2465,110 @@ func TestClient_AuthenticatedOperations(t *testing.T) {
 		t.Error("Health status should not be empty")
 	}
 }
+
+// =============================================================================
+// Client Operation Tests - Slow Log
+// =============================================================================
+
+func TestClient_SlowLog(t *testing.T) {
+	srv := server.NewServerWithConfig(engine.NewEngine(64), &config.Config{
+		SlowLog: config.SlowLogConfig{Threshold: time.Nanosecond},
+	})
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to find available port: %v", err)
+	}
+	addr := ln.Addr().String()
+	if err := ln.Close(); err != nil {
+		t.Fatalf("Failed to close listener: %v", err)
+	}
+	if err := srv.Start(addr); err != nil {
+		t.Fatalf("Failed to start server: %v", err)
+	}
+	defer srv.Stop()
+
+	client, err := NewClient(addr, testSessionID)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer closeClient(t, client)
+
+	if err := client.Ping(); err != nil {
+		t.Fatalf("Ping failed: %v", err)
+	}
+	mustAddDocument(t, client, "slow-doc", "slow.txt")
+
+	entries, err := client.SlowLogGet(-1)
+	if err != nil {
+		t.Fatalf("SlowLogGet failed: %v", err)
+	}
+	if len(entries) < 2 {
+		t.Fatalf("expected at least 2 entries, got %d", len(entries))
+	}
+	if entries[0].Command != "ADD_DOCUMENT" || entries[0].SessionID != testSessionID {
+		t.Errorf("unexpected newest entry: %+v", entries[0])
+	}
+
+	if err := client.SlowLogReset(); err != nil {
+		t.Fatalf("SlowLogReset failed: %v", err)
+	}
+	n, err := client.SlowLogLen()
+	if err != nil {
+		t.Fatalf("SlowLogLen failed: %v", err)
+	}
+	if n != 1 { // the RESET itself
+		t.Errorf("expected 1 entry after reset, got %d", n)
+	}
+}
+
+func TestClient_CreateSession(t *testing.T) {
+	ts := startTestServer(t)
+	defer ts.Stop()
+
+	client, err := NewClient(ts.addr, "metric-session")
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer closeClient(t, client)
+
+	if err := client.CreateSession(SessionOptions{EntityIndex: types.IndexConfig{Metric: "bogus"}}); err == nil {
+		t.Error("CreateSession with unknown metric should fail")
+	}
+	if err := client.CreateSession(SessionOptions{TextUnitIndex: types.IndexConfig{Quantization: "int4"}}); err == nil {
+		t.Error("CreateSession with unknown quantization should fail")
+	}
+	if err := client.CreateSession(SessionOptions{
+		VectorDim:     8,
+		EntityIndex:   types.IndexConfig{Metric: "dot", M: 8, EfSearch: 100},
+		TextUnitIndex: types.IndexConfig{Quantization: "pq", PQSubvectors: 4, KeepFullVectors: true},
+	}); err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	info, err := client.SessionInfo()
+	if err != nil {
+		t.Fatalf("SessionInfo failed: %v", err)
+	}
+	if info.VectorDim != 8 || info.EntityIndex.M != 8 || info.EntityIndex.EfSearch != 100 || info.EntityIndex.EfConstruction != 200 {
+		t.Errorf("unexpected session info: %+v", info)
+	}
+	if tu := info.TextUnitIndex; tu.Quantization != "pq" || tu.PQSubvectors != 4 || !tu.KeepFullVectors || info.EntityIndex.Quantization != "none" {
+		t.Errorf("unexpected quantization options: textunit %+v, entity %+v", tu, info.EntityIndex)
+	}
+
+	serverInfo, err := client.Info()
+	if err != nil {
+		t.Fatalf("Info failed: %v", err)
+	}
+	if serverInfo.VectorMemory.Vectors != 0 || serverInfo.VectorMemory.Recall != 1 {
+		t.Errorf("unexpected vector memory for an empty session: %+v", serverInfo.VectorMemory)
+	}
+
+	sessions, err := client.ListSessions()
+	if err != nil {
+		t.Fatalf("ListSessions failed: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].EntityIndex.Metric != "dot" || sessions[0].TextUnitIndex.Metric != "cosine" {
+		t.Errorf("unexpected sessions: %+v", sessions)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/binary"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 
 	"github.com/gibram-io/gibram/pkg/types"
@@ -221,6 +222,50 @@ func ProtoToCommunity(comm *pb.Community) *types.Community {
 	}
 }
 
+// GraphValueToProto converts types.GraphValue to pb.GraphValue. Scalars
+// other than strings, float64 and bools are sent as their string form.
+func GraphValueToProto(v types.GraphValue) *pb.GraphValue {
+	switch x := v.Value; {
+	case v.Entity != nil:
+		return &pb.GraphValue{Kind: "entity", Entity: EntityToProto(v.Entity)}
+	case v.Relationship != nil:
+		return &pb.GraphValue{Kind: "relationship", Relationship: RelationshipToProto(v.Relationship)}
+	case x == nil:
+		return &pb.GraphValue{Kind: "null"}
+	default:
+		switch val := x.(type) {
+		case string:
+			return &pb.GraphValue{Kind: "string", StringValue: val}
+		case float64:
+			return &pb.GraphValue{Kind: "number", NumberValue: val}
+		case bool:
+			return &pb.GraphValue{Kind: "bool", BoolValue: val}
+		}
+		return &pb.GraphValue{Kind: "string", StringValue: fmt.Sprint(x)}
+	}
+}
+
+// ProtoToGraphValue converts pb.GraphValue to types.GraphValue
+func ProtoToGraphValue(v *pb.GraphValue) types.GraphValue {
+	switch v.Kind {
+	case "entity":
+		if v.Entity != nil {
+			return types.GraphValue{Entity: ProtoToEntity(v.Entity)}
+		}
+	case "relationship":
+		if v.Relationship != nil {
+			return types.GraphValue{Relationship: ProtoToRelationship(v.Relationship)}
+		}
+	case "string":
+		return types.GraphValue{Value: v.StringValue}
+	case "number":
+		return types.GraphValue{Value: v.NumberValue}
+	case "bool":
+		return types.GraphValue{Value: v.BoolValue}
+	}
+	return types.GraphValue{}
+}
+
 // =============================================================================
 // Binary WAL Encoding (more compact than JSON)
 // =============================================================================
@@ -247,6 +247,44 @@ func TestProtoToRelationship(t *testing.T) {
 	}
 }
 
+// =============================================================================
+// Test Type Converters: GraphValue
+// =============================================================================
+
+func TestGraphValueRoundTrip(t *testing.T) {
+	values := []types.GraphValue{
+		{Entity: &types.Entity{ID: 1, Title: "ALICE", Type: "person"}},
+		{Relationship: &types.Relationship{ID: 2, SourceID: 1, TargetID: 3, Type: "KNOWS"}},
+		{Value: "text"},
+		{Value: 4.5},
+		{Value: true},
+		{},
+	}
+	kinds := []string{"entity", "relationship", "string", "number", "bool", "null"}
+
+	for i, v := range values {
+		pbv := GraphValueToProto(v)
+		if pbv.Kind != kinds[i] {
+			t.Errorf("value %d: expected kind %s, got %s", i, kinds[i], pbv.Kind)
+		}
+		got := ProtoToGraphValue(pbv)
+		switch {
+		case v.Entity != nil:
+			if got.Entity == nil || got.Entity.ID != v.Entity.ID {
+				t.Errorf("value %d: entity not preserved: %+v", i, got)
+			}
+		case v.Relationship != nil:
+			if got.Relationship == nil || got.Relationship.ID != v.Relationship.ID {
+				t.Errorf("value %d: relationship not preserved: %+v", i, got)
+			}
+		default:
+			if got.Value != v.Value {
+				t.Errorf("value %d: expected %v, got %v", i, v.Value, got.Value)
+			}
+		}
+	}
+}
+
 // =============================================================================
 // Test Type Converters: Community
 // =============================================================================
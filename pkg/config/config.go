@@ -109,6 +109,9 @@ type Config struct {
 	Auth     AuthConfig     `yaml:"auth"`
 	Security SecurityConfig `yaml:"security"`
 	Logging  LoggingConfig  `yaml:"logging"`
+	Metrics  MetricsConfig  `yaml:"metrics"`
+	Admin    AdminConfig    `yaml:"admin"`
+	SlowLog  SlowLogConfig  `yaml:"slowlog"`
 }
 
 // ServerConfig contains server settings
@@ -137,6 +140,11 @@ type APIKeyConfig struct {
 	KeyHash     string   `yaml:"key_hash"`     // Or bcrypt hash (if Key is empty)
 	Permissions []string `yaml:"permissions"`  // admin, write, read
 	ExpiresAt   string   `yaml:"expires_at"`   // Optional: RFC3339 format
+
+	// Optional per-key limits (0 = use security defaults / unlimited)
+	RateLimit     int `yaml:"rate_limit"`     // Cost units per second for this key
+	RateBurst     int `yaml:"rate_burst"`     // Burst allowance for this key
+	MaxConcurrent int `yaml:"max_concurrent"` // Max in-flight commands across connections
 }
 
 // SecurityConfig contains security settings
@@ -147,6 +155,10 @@ type SecurityConfig struct {
 	IdleTimeout    time.Duration `yaml:"idle_timeout"`     // Idle connection timeout
 	UnauthTimeout  time.Duration `yaml:"unauth_timeout"`   // Timeout for unauthenticated
 	MaxConnsPerIP  int           `yaml:"max_conns_per_ip"` // Max connections per IP
+
+	// Pre-auth limits (per client IP)
+	AuthAttemptsPerMin int `yaml:"auth_attempts_per_min"` // AUTH attempts per minute per IP
+	AuthAttemptBurst   int `yaml:"auth_attempt_burst"`    // Burst allowance for AUTH attempts
 }
 
 // LoggingConfig contains logging settings
@@ -157,6 +169,24 @@ type LoggingConfig struct {
 	File   string `yaml:"file"`   // Log file path if output=file
 }
 
+// MetricsConfig contains metrics exporter settings
+type MetricsConfig struct {
+	Addr string `yaml:"addr"` // Prometheus HTTP listen address (empty = disabled)
+	Path string `yaml:"path"` // Scrape path
+}
+
+// AdminConfig contains admin HTTP listener settings
+type AdminConfig struct {
+	Addr       string        `yaml:"addr"`        // Health, readiness and pprof listen address (empty = disabled)
+	DrainDelay time.Duration `yaml:"drain_delay"` // Time between reporting not-ready and closing listeners on shutdown
+}
+
+// SlowLogConfig contains slow command log settings
+type SlowLogConfig struct {
+	Threshold time.Duration `yaml:"threshold"` // Log commands slower than this (negative = disabled)
+	MaxLen    int           `yaml:"max_len"`   // Entries kept in the ring buffer
+}
+
 // =============================================================================
 // Default Configuration
 // =============================================================================
@@ -184,6 +214,9 @@ func DefaultConfig() *Config {
 			IdleTimeout:    300 * time.Second,
 			UnauthTimeout:  10 * time.Second,
 			MaxConnsPerIP:  50,
+
+			AuthAttemptsPerMin: 30,
+			AuthAttemptBurst:   10,
 		},
 		Logging: LoggingConfig{
 			Level:  "info",
@@ -191,6 +224,18 @@ func DefaultConfig() *Config {
 			Output: "stdout",
 			File:   "",
 		},
+		Metrics: MetricsConfig{
+			Addr: "",
+			Path: "/metrics",
+		},
+		Admin: AdminConfig{
+			Addr:       "",
+			DrainDelay: 0,
+		},
+		SlowLog: SlowLogConfig{
+			Threshold: 10 * time.Millisecond,
+			MaxLen:    128,
+		},
 	}
 }
 
@@ -276,6 +321,11 @@ type APIKey struct {
 	Hash        string
 	Permissions map[string]bool
 	ExpiresAt   time.Time
+
+	// Per-key limits (0 = server default / unlimited)
+	RateLimit     int
+	RateBurst     int
+	MaxConcurrent int
 }
 
 // NewAPIKeyStore creates a new API key store from config
@@ -289,10 +339,17 @@ func NewAPIKeyStore(cfg *AuthConfig) (*APIKeyStore, error) {
 			continue
 		}
 
+		if keyCfg.RateLimit < 0 || keyCfg.RateBurst < 0 || keyCfg.MaxConcurrent < 0 {
+			return nil, fmt.Errorf("negative limit for key %s", keyCfg.ID)
+		}
+
 		apiKey := &APIKey{
-			ID:          keyCfg.ID,
-			Hash:        keyCfg.KeyHash,
-			Permissions: make(map[string]bool),
+			ID:            keyCfg.ID,
+			Hash:          keyCfg.KeyHash,
+			Permissions:   make(map[string]bool),
+			RateLimit:     keyCfg.RateLimit,
+			RateBurst:     keyCfg.RateBurst,
+			MaxConcurrent: keyCfg.MaxConcurrent,
 		}
 
 		for _, perm := range keyCfg.Permissions {
@@ -374,6 +431,9 @@ type CLIOverrides struct {
 	VectorDim int
 	Insecure  bool // Disable TLS + Auth (dev only)
 	LogLevel  string
+
+	MetricsAddr string
+	AdminAddr   string
 }
 
 // ApplyOverrides applies CLI overrides to config
@@ -390,6 +450,12 @@ func (cfg *Config) ApplyOverrides(overrides CLIOverrides) {
 	if overrides.LogLevel != "" {
 		cfg.Logging.Level = overrides.LogLevel
 	}
+	if overrides.MetricsAddr != "" {
+		cfg.Metrics.Addr = overrides.MetricsAddr
+	}
+	if overrides.AdminAddr != "" {
+		cfg.Admin.Addr = overrides.AdminAddr
+	}
 }
 
 // IsInsecure returns true if running in insecure mode
@@ -145,6 +145,13 @@ func TestDefaultConfig(t *testing.T) {
 	if cfg.Security.IdleTimeout != 300*time.Second {
 		t.Errorf("expected idle timeout 300s, got %v", cfg.Security.IdleTimeout)
 	}
+	if cfg.Security.MaxConnsPerIP != 50 {
+		t.Errorf("expected max conns per ip 50, got %d", cfg.Security.MaxConnsPerIP)
+	}
+	if cfg.Security.AuthAttemptsPerMin != 30 || cfg.Security.AuthAttemptBurst != 10 {
+		t.Errorf("expected auth attempts 30/min burst 10, got %d/%d",
+			cfg.Security.AuthAttemptsPerMin, cfg.Security.AuthAttemptBurst)
+	}
 	if cfg.Logging.Level != "info" {
 		t.Errorf("expected log level info, got %s", cfg.Logging.Level)
 	}
@@ -154,6 +161,15 @@ func TestDefaultConfig(t *testing.T) {
 	if cfg.Logging.Output != "stdout" {
 		t.Errorf("expected log output stdout, got %s", cfg.Logging.Output)
 	}
+	if cfg.Metrics.Addr != "" || cfg.Metrics.Path != "/metrics" {
+		t.Errorf("expected metrics disabled on /metrics, got %q %q", cfg.Metrics.Addr, cfg.Metrics.Path)
+	}
+	if cfg.Admin.Addr != "" || cfg.Admin.DrainDelay != 0 {
+		t.Errorf("expected admin disabled, got %q %s", cfg.Admin.Addr, cfg.Admin.DrainDelay)
+	}
+	if cfg.SlowLog.Threshold != 10*time.Millisecond || cfg.SlowLog.MaxLen != 128 {
+		t.Errorf("expected slowlog 10ms/128, got %s/%d", cfg.SlowLog.Threshold, cfg.SlowLog.MaxLen)
+	}
 }
 
 // =============================================================================
@@ -344,6 +360,38 @@ func TestNewAPIKeyStore_WithExpiry(t *testing.T) {
 	}
 }
 
+func TestNewAPIKeyStore_PerKeyLimits(t *testing.T) {
+	hash, _ := HashAPIKey("test-key")
+
+	cfg := &AuthConfig{
+		Keys: []APIKeyConfig{
+			{
+				ID:            "limited-key",
+				KeyHash:       hash,
+				Permissions:   []string{PermRead},
+				RateLimit:     50,
+				RateBurst:     5,
+				MaxConcurrent: 2,
+			},
+		},
+	}
+
+	store, err := NewAPIKeyStore(cfg)
+	if err != nil {
+		t.Fatalf("failed to create API key store: %v", err)
+	}
+
+	key := store.keys[hash]
+	if key.RateLimit != 50 || key.RateBurst != 5 || key.MaxConcurrent != 2 {
+		t.Errorf("expected limits 50/5/2, got %d/%d/%d", key.RateLimit, key.RateBurst, key.MaxConcurrent)
+	}
+
+	cfg.Keys[0].MaxConcurrent = -1
+	if _, err := NewAPIKeyStore(cfg); err == nil {
+		t.Error("expected error for negative limit")
+	}
+}
+
 func TestNewAPIKeyStore_InvalidExpiry(t *testing.T) {
 	hash, _ := HashAPIKey("test-key")
 
@@ -527,10 +575,20 @@ func TestApplyOverrides(t *testing.T) {
 		DataDir:   "/custom/data",
 		VectorDim: 512,
 		LogLevel:  "warn",
+
+		MetricsAddr: "127.0.0.1:9161",
+		AdminAddr:   "127.0.0.1:9162",
 	}
 
 	cfg.ApplyOverrides(overrides)
 
+	if cfg.Metrics.Addr != "127.0.0.1:9161" {
+		t.Errorf("expected metrics addr 127.0.0.1:9161, got %s", cfg.Metrics.Addr)
+	}
+	if cfg.Admin.Addr != "127.0.0.1:9162" {
+		t.Errorf("expected admin addr 127.0.0.1:9162, got %s", cfg.Admin.Addr)
+	}
+
 	if cfg.Server.Addr != ":7777" {
 		t.Errorf("expected addr :7777, got %s", cfg.Server.Addr)
 	}
@@ -28,6 +28,21 @@ const (
 	TaskStatusFailed
 )
 
+// String returns the status name used on the wire
+func (s TaskStatus) String() string {
+	switch s {
+	case TaskStatusPending:
+		return "pending"
+	case TaskStatusRunning:
+		return "running"
+	case TaskStatusComplete:
+		return "complete"
+	case TaskStatusFailed:
+		return "failed"
+	}
+	return fmt.Sprintf("status(%d)", int(s))
+}
+
 // CommunityTask represents an async community detection task
 type CommunityTask struct {
 	ID          string
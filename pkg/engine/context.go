@@ -0,0 +1,235 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gibram-io/gibram/pkg/types"
+)
+
+// =============================================================================
+// Context Packing - Token-Budgeted, Prompt-Ready Query Context
+// =============================================================================
+
+// contextShares is the split of a query token budget between result
+// sections, normalized to sum to 1
+type contextShares struct {
+	entities      float64
+	relationships float64
+	textUnits     float64
+	communities   float64
+}
+
+// newContextShares checks the token budget, shares and context format of
+// spec and returns the normalized shares
+func newContextShares(spec types.QuerySpec) (contextShares, error) {
+	if spec.TokenBudget < 0 || spec.TokenBudget > types.MaxContextTokenBudget {
+		return contextShares{}, fmt.Errorf("token budget must be between 0 and %d, got %d", types.MaxContextTokenBudget, spec.TokenBudget)
+	}
+	switch spec.ContextFormat {
+	case "", types.ContextFormatMarkdown, types.ContextFormatCSV:
+	default:
+		return contextShares{}, fmt.Errorf("unknown context format: %q (want markdown or csv)", spec.ContextFormat)
+	}
+
+	sh := contextShares{
+		entities:      float64(spec.EntityShare),
+		relationships: float64(spec.RelationshipShare),
+		textUnits:     float64(spec.TextUnitShare),
+		communities:   float64(spec.CommunityShare),
+	}
+	if sh.entities < 0 || sh.relationships < 0 || sh.textUnits < 0 || sh.communities < 0 {
+		return contextShares{}, fmt.Errorf("token budget shares must not be negative")
+	}
+	sum := sh.entities + sh.relationships + sh.textUnits + sh.communities
+	if sum == 0 {
+		return contextShares{
+			entities:      types.DefaultEntityShare,
+			relationships: types.DefaultRelationshipShare,
+			textUnits:     types.DefaultTextUnitShare,
+			communities:   types.DefaultCommunityShare,
+		}, nil
+	}
+	sh.entities /= sum
+	sh.relationships /= sum
+	sh.textUnits /= sum
+	sh.communities /= sum
+	return sh, nil
+}
+
+// packContext trims the score-ordered lists of pack to budget tokens.
+// Sections are filled in the order communities, entities, relationships,
+// text units; each gets its share plus whatever the sections before it
+// left unused. Only relationships between packed entities are kept,
+// ranked by the scores of their endpoints.
+func packContext(pack *types.ContextPack, budget int, shares contextShares) {
+	usage := &types.TokenUsage{}
+	carry := 0
+	allot := func(share float64) int {
+		return int(float64(budget)*share) + carry
+	}
+
+	limit := allot(shares.communities)
+	pack.Communities, usage.Communities = packByTokens(pack.Communities, limit, func(cr types.CommunityResult) int {
+		return communityTokens(cr.Community)
+	})
+	carry = limit - usage.Communities
+
+	limit = allot(shares.entities)
+	pack.Entities, usage.Entities = packByTokens(pack.Entities, limit, func(er types.EntityResult) int {
+		return entityTokens(er.Entity)
+	})
+	carry = limit - usage.Entities
+
+	scores := make(map[uint64]float32, len(pack.Entities))
+	for _, er := range pack.Entities {
+		scores[er.Entity.ID] = er.Score
+	}
+	rels := make([]types.RelationshipResult, 0, len(pack.Relationships))
+	for _, rr := range pack.Relationships {
+		_, src := scores[rr.Relationship.SourceID]
+		_, dst := scores[rr.Relationship.TargetID]
+		if src && dst {
+			rels = append(rels, rr)
+		}
+	}
+	sort.Slice(rels, func(i, j int) bool {
+		a, b := rels[i].Relationship, rels[j].Relationship
+		sa, sb := scores[a.SourceID]+scores[a.TargetID], scores[b.SourceID]+scores[b.TargetID]
+		if sa != sb {
+			return sa > sb
+		}
+		if a.Weight != b.Weight {
+			return a.Weight > b.Weight
+		}
+		return a.ID < b.ID
+	})
+	limit = allot(shares.relationships)
+	pack.Relationships, usage.Relationships = packByTokens(rels, limit, relationshipTokens)
+
+	// Text units take the rest, so no token is lost to rounding
+	limit = budget - usage.Communities - usage.Entities - usage.Relationships
+	pack.TextUnits, usage.TextUnits = packByTokens(pack.TextUnits, limit, func(tr types.TextUnitResult) int {
+		return textUnitTokens(tr.TextUnit)
+	})
+
+	usage.Total = usage.Entities + usage.Relationships + usage.TextUnits + usage.Communities
+	pack.TokenUsage = usage
+}
+
+// packByTokens keeps items, in order, while they fit in budget tokens.
+// An item too large for what is left is skipped so smaller ones after it
+// may still fit.
+func packByTokens[T any](items []T, budget int, tokens func(T) int) ([]T, int) {
+	kept := make([]T, 0, len(items))
+	used := 0
+	for _, item := range items {
+		n := tokens(item)
+		if used+n > budget {
+			continue
+		}
+		kept = append(kept, item)
+		used += n
+	}
+	return kept, used
+}
+
+func entityTokens(ent *types.Entity) int {
+	return types.EstimateTokens(ent.Title) + types.EstimateTokens(ent.Type) + types.EstimateTokens(ent.Description)
+}
+
+func relationshipTokens(rr types.RelationshipResult) int {
+	return types.EstimateTokens(rr.SourceTitle) + types.EstimateTokens(rr.TargetTitle) +
+		types.EstimateTokens(rr.Relationship.Type) + types.EstimateTokens(rr.Relationship.Description)
+}
+
+// textUnitTokens prefers the tokenizer count stored with the text unit
+func textUnitTokens(tu *types.TextUnit) int {
+	if tu.TokenCount > 0 {
+		return tu.TokenCount
+	}
+	return types.EstimateTokens(tu.Content)
+}
+
+func communityTokens(comm *types.Community) int {
+	return types.EstimateTokens(comm.Title) + types.EstimateTokens(reportText(comm, false))
+}
+
+// reportText is the report of a community: its full content, or its
+// summary when useSummary is set or there is no full content
+func reportText(comm *types.Community, useSummary bool) string {
+	if (useSummary && comm.Summary != "") || comm.FullContent == "" {
+		return comm.Summary
+	}
+	return comm.FullContent
+}
+
+// =============================================================================
+// Context Rendering
+// =============================================================================
+
+// cellReplacer keeps a field on one line and inside its table cell
+var cellReplacer = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ", "|", `\|`)
+
+// renderContext renders pack the way GraphRAG local search builds its
+// prompt context: reports, entities, relationships and sources, each a
+// table under its section name. Empty sections are left out.
+func renderContext(pack *types.ContextPack, format types.ContextFormat) string {
+	var b strings.Builder
+	table := func(name string, header []string, rows [][]string) {
+		if len(rows) == 0 {
+			return
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		if format == types.ContextFormatMarkdown {
+			b.WriteString("## " + name + "\n\n")
+			b.WriteString("| " + strings.Join(header, " | ") + " |\n")
+			b.WriteString("|" + strings.Repeat(" --- |", len(header)) + "\n")
+			for _, row := range rows {
+				b.WriteString("| " + strings.Join(row, " | ") + " |\n")
+			}
+			return
+		}
+		b.WriteString("-----" + name + "-----\n")
+		b.WriteString(strings.Join(header, "|") + "\n")
+		for _, row := range rows {
+			b.WriteString(strings.Join(row, "|") + "\n")
+		}
+	}
+	id := func(id uint64) string { return strconv.FormatUint(id, 10) }
+
+	rows := make([][]string, 0, len(pack.Communities))
+	for _, cr := range pack.Communities {
+		rows = append(rows, []string{id(cr.Community.ID), cellReplacer.Replace(cr.Community.Title),
+			cellReplacer.Replace(reportText(cr.Community, false))})
+	}
+	table("Reports", []string{"id", "title", "content"}, rows)
+
+	rows = make([][]string, 0, len(pack.Entities))
+	for _, er := range pack.Entities {
+		rows = append(rows, []string{id(er.Entity.ID), cellReplacer.Replace(er.Entity.Title),
+			cellReplacer.Replace(er.Entity.Type), cellReplacer.Replace(er.Entity.Description)})
+	}
+	table("Entities", []string{"id", "entity", "type", "description"}, rows)
+
+	rows = make([][]string, 0, len(pack.Relationships))
+	for _, rr := range pack.Relationships {
+		rel := rr.Relationship
+		rows = append(rows, []string{id(rel.ID), cellReplacer.Replace(rr.SourceTitle), cellReplacer.Replace(rr.TargetTitle),
+			cellReplacer.Replace(rel.Type), cellReplacer.Replace(rel.Description),
+			strconv.FormatFloat(float64(rel.Weight), 'g', -1, 32)})
+	}
+	table("Relationships", []string{"id", "source", "target", "type", "description", "weight"}, rows)
+
+	rows = make([][]string, 0, len(pack.TextUnits))
+	for _, tr := range pack.TextUnits {
+		rows = append(rows, []string{id(tr.TextUnit.ID), cellReplacer.Replace(tr.TextUnit.Content)})
+	}
+	table("Sources", []string{"id", "text"}, rows)
+
+	return b.String()
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -26,6 +27,7 @@ var (
 	ErrSessionRequired = errors.New("session_id is required")
 	ErrSessionNotFound = errors.New("session not found")
 	ErrSessionExpired  = errors.New("session expired")
+	ErrSessionExists   = errors.New("session already exists")
 )
 
 // =============================================================================
@@ -35,6 +37,7 @@ var (
 const (
 	MaxQueryLogEntries = 10000
 	MaxSessions        = 10000 // Maximum concurrent sessions (DoS protection)
+	MaxVectorDim       = 16384 // Maximum per-session vector dimension
 )
 
 type queryLogLRU struct {
@@ -67,9 +70,11 @@ func (c *queryLogLRU) Set(id uint64, log *queryLog) {
 		spec:      log.spec,
 		seeds:     make([]types.SeedInfo, len(log.seeds)),
 		traversal: make([]types.TraversalStep, len(log.traversal)),
+		demotions: make([]types.Demotion, len(log.demotions)),
 	}
 	copy(logCopy.seeds, log.seeds)
 	copy(logCopy.traversal, log.traversal)
+	copy(logCopy.demotions, log.demotions)
 
 	// If already exists, move to front
 	if elem, ok := c.items[id]; ok {
@@ -109,9 +114,11 @@ func (c *queryLogLRU) Get(id uint64) (*queryLog, bool) {
 		spec:      log.spec,
 		seeds:     make([]types.SeedInfo, len(log.seeds)),
 		traversal: make([]types.TraversalStep, len(log.traversal)),
+		demotions: make([]types.Demotion, len(log.demotions)),
 	}
 	copy(logCopy.seeds, log.seeds)
 	copy(logCopy.traversal, log.traversal)
+	copy(logCopy.demotions, log.demotions)
 	c.mu.RUnlock()
 
 	return logCopy, true
@@ -146,6 +153,9 @@ type Engine struct {
 	cleanupInterval time.Duration
 	stopCleanup     chan struct{}
 	cleanupWg       sync.WaitGroup
+
+	// Online vector index rebuilds
+	rebuilds rebuildTasks
 }
 
 type queryLog struct {
@@ -153,6 +163,7 @@ type queryLog struct {
 	spec      types.QuerySpec
 	seeds     []types.SeedInfo
 	traversal []types.TraversalStep
+	demotions []types.Demotion
 }
 
 // NewEngine creates a new session-based GibRAM engine
@@ -202,6 +213,40 @@ func (e *Engine) getOrCreateSession(sessionID string) (*store.SessionStore, erro
 	return sess, nil
 }
 
+// CreateSession explicitly creates a session with the given vector dimension
+// and index options. Sessions created implicitly by a first write use the
+// engine dimension and DefaultSessionOptions.
+func (e *Engine) CreateSession(sessionID string, opts store.SessionOptions) error {
+	if sessionID == "" {
+		return ErrSessionRequired
+	}
+	if opts.VectorDim > MaxVectorDim {
+		return fmt.Errorf("vector_dim %d exceeds maximum %d", opts.VectorDim, MaxVectorDim)
+	}
+	if opts.VectorDim == 0 {
+		opts.VectorDim = e.vectorDim // validate quantization against the effective dimension
+	}
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if sess, ok := e.sessions[sessionID]; ok {
+		if !sess.IsExpired() {
+			return ErrSessionExists
+		}
+		delete(e.sessions, sessionID)
+	}
+	if len(e.sessions) >= MaxSessions {
+		return fmt.Errorf("max sessions limit reached (%d)", MaxSessions)
+	}
+
+	e.sessions[sessionID] = store.NewSessionStoreWithOptions(sessionID, e.vectorDim, opts)
+	return nil
+}
+
 // getSession gets an existing session (does not auto-create)
 func (e *Engine) getSession(sessionID string) (*store.SessionStore, error) {
 	if sessionID == "" {
@@ -462,6 +507,14 @@ func (e *Engine) UpdateEntityDescription(sessionID string, id uint64, descriptio
 	return sess.UpdateEntityDescription(id, description, embedding)
 }
 
+func (e *Engine) SetEntityAttrs(sessionID string, id uint64, attrs map[string]string) bool {
+	sess, err := e.getSession(sessionID)
+	if err != nil {
+		return false
+	}
+	return sess.SetEntityAttrs(id, attrs)
+}
+
 func (e *Engine) DeleteEntity(sessionID string, id uint64) bool {
 	sess, err := e.getSession(sessionID)
 	if err != nil {
@@ -639,6 +692,32 @@ func (e *Engine) Query(sessionID string, spec types.QuerySpec) (*types.ContextPa
 	if err != nil {
 		return nil, err
 	}
+	return e.runQuery(sess, sessionID, spec)
+}
+
+// runQuery runs spec on sess through the session query cache, if any
+func (e *Engine) runQuery(sess *store.SessionStore, sessionID string, spec types.QuerySpec) (*types.ContextPack, error) {
+	cache := sess.QueryCache()
+	if cache == nil || spec.NoCache {
+		return e.query(sess, sessionID, spec)
+	}
+	return e.cachedQuery(sess, cache, sessionID, spec)
+}
+
+// query runs the query pipeline on sess
+func (e *Engine) query(sess *store.SessionStore, sessionID string, spec types.QuerySpec) (*types.ContextPack, error) {
+	scorer, err := newExpansionScorer(spec)
+	if err != nil {
+		return nil, err
+	}
+	shares, err := newContextShares(spec)
+	if err != nil {
+		return nil, err
+	}
+	rr, err := newReranker(spec)
+	if err != nil {
+		return nil, err
+	}
 
 	startTime := time.Now()
 
@@ -659,85 +738,84 @@ func (e *Engine) Query(sessionID string, spec types.QuerySpec) (*types.ContextPa
 
 	stats := types.QueryStats{}
 
+	filter := newQueryFilter(sess, spec.FilterEntityTypes, spec.FilterDocumentIDs, spec.FilterAttrs)
+
 	// Get indexes
 	textUnitIndex := sess.GetTextUnitIndex()
 	entityIndex := sess.GetEntityIndex()
 	communityIndex := sess.GetCommunityIndex()
 
-	// Phase 1: Vector search on selected indices
+	// Phase 1: Vector (and, with query text, lexical) search on selected indices
 	for _, searchType := range spec.SearchTypes {
 		switch searchType {
 		case types.SearchTypeTextUnit:
-			if textUnitIndex != nil {
-				results := textUnitIndex.Search(spec.QueryVector, spec.TopK)
-				stats.TextUnitsSearched = textUnitIndex.Count()
-
-				for _, r := range results {
-					if tu, ok := sess.GetTextUnit(r.ID); ok {
-						textUnitResults[r.ID] = &types.TextUnitResult{
-							TextUnit:   tu,
-							Score:      r.Similarity,
-							Similarity: r.Similarity,
-							Hop:        0,
-						}
-
-						qlog.seeds = append(qlog.seeds, types.SeedInfo{
-							Type:       types.SearchTypeTextUnit,
-							ID:         r.ID,
-							ExternalID: tu.ExternalID,
-							Similarity: r.Similarity,
-							LinkedIDs:  tu.EntityIDs,
-						})
+			hits := searchSeeds(textUnitIndex, sess.GetTextUnitLexicalIndex(), spec, filter.textUnitFilter())
+			stats.TextUnitsSearched = textUnitIndex.Count()
+
+			for _, h := range hits {
+				if tu, ok := sess.GetTextUnit(h.ID); ok {
+					textUnitResults[h.ID] = &types.TextUnitResult{
+						TextUnit:   tu,
+						Score:      h.Score,
+						Similarity: h.Similarity,
+						Hop:        0,
 					}
+
+					qlog.seeds = append(qlog.seeds, types.SeedInfo{
+						Type:       types.SearchTypeTextUnit,
+						ID:         h.ID,
+						ExternalID: tu.ExternalID,
+						Similarity: h.Similarity,
+						Lexical:    h.Lexical,
+						LinkedIDs:  tu.EntityIDs,
+					})
 				}
 			}
 
 		case types.SearchTypeEntity:
-			if entityIndex != nil {
-				results := entityIndex.Search(spec.QueryVector, spec.TopK)
-				stats.EntitiesSearched = entityIndex.Count()
-
-				for _, r := range results {
-					if ent, ok := sess.GetEntity(r.ID); ok {
-						entityResults[r.ID] = &types.EntityResult{
-							Entity:     ent,
-							Score:      r.Similarity,
-							Similarity: r.Similarity,
-							Hop:        0,
-						}
-
-						qlog.seeds = append(qlog.seeds, types.SeedInfo{
-							Type:       types.SearchTypeEntity,
-							ID:         r.ID,
-							ExternalID: ent.ExternalID,
-							Similarity: r.Similarity,
-							LinkedIDs:  ent.TextUnitIDs,
-						})
+			hits := searchSeeds(entityIndex, sess.GetEntityLexicalIndex(), spec, filter.entityFilter())
+			stats.EntitiesSearched = entityIndex.Count()
+
+			for _, h := range hits {
+				if ent, ok := sess.GetEntity(h.ID); ok {
+					entityResults[h.ID] = &types.EntityResult{
+						Entity:     ent,
+						Score:      h.Score,
+						Similarity: h.Similarity,
+						Hop:        0,
 					}
+
+					qlog.seeds = append(qlog.seeds, types.SeedInfo{
+						Type:       types.SearchTypeEntity,
+						ID:         h.ID,
+						ExternalID: ent.ExternalID,
+						Similarity: h.Similarity,
+						Lexical:    h.Lexical,
+						LinkedIDs:  ent.TextUnitIDs,
+					})
 				}
 			}
 
 		case types.SearchTypeCommunity:
-			if communityIndex != nil {
-				results := communityIndex.Search(spec.QueryVector, spec.TopK)
-				stats.CommunitiesSearched = communityIndex.Count()
-
-				for _, r := range results {
-					if comm, ok := sess.GetCommunity(r.ID); ok {
-						communityResults[r.ID] = &types.CommunityResult{
-							Community:  comm,
-							Score:      r.Similarity,
-							Similarity: r.Similarity,
-						}
-
-						qlog.seeds = append(qlog.seeds, types.SeedInfo{
-							Type:       types.SearchTypeCommunity,
-							ID:         r.ID,
-							ExternalID: comm.ExternalID,
-							Similarity: r.Similarity,
-							LinkedIDs:  comm.EntityIDs,
-						})
+			hits := searchSeeds(communityIndex, sess.GetCommunityLexicalIndex(), spec, nil)
+			stats.CommunitiesSearched = communityIndex.Count()
+
+			for _, h := range hits {
+				if comm, ok := sess.GetCommunity(h.ID); ok {
+					communityResults[h.ID] = &types.CommunityResult{
+						Community:  comm,
+						Score:      h.Score,
+						Similarity: h.Similarity,
 					}
+
+					qlog.seeds = append(qlog.seeds, types.SeedInfo{
+						Type:       types.SearchTypeCommunity,
+						ID:         h.ID,
+						ExternalID: comm.ExternalID,
+						Similarity: h.Similarity,
+						Lexical:    h.Lexical,
+						LinkedIDs:  comm.EntityIDs,
+					})
 				}
 			}
 		}
@@ -745,22 +823,34 @@ func (e *Engine) Query(sessionID string, spec types.QuerySpec) (*types.ContextPa
 
 	// Phase 2: Graph expansion from entity seeds
 	if spec.KHops > 0 {
-		// Collect seed entity IDs
+		// Collect seed entity IDs with the best score of the seed that
+		// led to them
 		seedEntityIDs := make([]uint64, 0)
+		seedScores := make(map[uint64]float32)
+		addSeed := func(eid uint64, score float32) {
+			if current, ok := seedScores[eid]; !ok || score > current {
+				seedScores[eid] = score
+			}
+			seedEntityIDs = append(seedEntityIDs, eid)
+		}
 
 		// From direct entity search
-		for eid := range entityResults {
-			seedEntityIDs = append(seedEntityIDs, eid)
+		for eid, er := range entityResults {
+			addSeed(eid, er.Score)
 		}
 
 		// From text unit links
 		for _, tur := range textUnitResults {
-			seedEntityIDs = append(seedEntityIDs, tur.TextUnit.EntityIDs...)
+			for _, eid := range tur.TextUnit.EntityIDs {
+				addSeed(eid, tur.Score)
+			}
 		}
 
 		// From community members
 		for _, cr := range communityResults {
-			seedEntityIDs = append(seedEntityIDs, cr.Community.EntityIDs...)
+			for _, eid := range cr.Community.EntityIDs {
+				addSeed(eid, cr.Score)
+			}
 		}
 
 		// BFS traversal using session's relationship store
@@ -771,41 +861,51 @@ func (e *Engine) Query(sessionID string, spec types.QuerySpec) (*types.ContextPa
 			spec.KHops,
 			spec.MaxEntities,
 		)
+		var entityScores map[uint64]float32
+		if scorer.expansion == types.ExpansionPPR {
+			entityScores = scorer.pageRank(seedScores, visitedIDs, traversal, relAdapter)
+		} else {
+			entityScores = scorer.scoreEntities(seedScores, hopMap, traversal, relAdapter)
+		}
 
 		stats.EdgesScanned = len(traversal)
 		qlog.traversal = traversal
 
-		// Add discovered entities
+		// Add discovered entities; PageRank rescores the seeds too
 		for _, eid := range visitedIDs {
-			if _, exists := entityResults[eid]; !exists {
-				if ent, ok := sess.GetEntity(eid); ok {
-					hop := hopMap[eid]
-					score := float32(1.0 / float64(1+hop))
-
+			if er, exists := entityResults[eid]; exists && scorer.expansion == types.ExpansionPPR {
+				er.Score = entityScores[eid]
+			} else if !exists {
+				if ent, ok := sess.GetEntity(eid); ok && filter.matchEntity(ent) {
 					entityResults[eid] = &types.EntityResult{
 						Entity:     ent,
-						Score:      score,
+						Score:      entityScores[eid],
 						Similarity: 0,
-						Hop:        hop,
+						Hop:        hopMap[eid],
 					}
 				}
 			}
 		}
 
-		// Collect text units from discovered entities
+		// Collect text units from discovered entities; a text unit linked
+		// to several entities aggregates their scores
 		for _, er := range entityResults {
 			for _, tuID := range er.Entity.TextUnitIDs {
-				if _, exists := textUnitResults[tuID]; !exists {
-					if tu, ok := sess.GetTextUnit(tuID); ok {
-						hop := er.Hop + 1
-						score := float32(1.0 / float64(1+hop))
-
-						textUnitResults[tuID] = &types.TextUnitResult{
-							TextUnit:   tu,
-							Score:      score,
-							Similarity: 0,
-							Hop:        hop,
-						}
+				existing, exists := textUnitResults[tuID]
+				if exists && existing.Hop == 0 {
+					continue // vector seed keeps its own score
+				}
+				if exists {
+					existing.Score = scorer.combine(existing.Score, scorer.textUnitScore(er), true)
+					existing.Hop = min(existing.Hop, er.Hop+1)
+					continue
+				}
+				if tu, ok := sess.GetTextUnit(tuID); ok && filter.matchTextUnit(tu) {
+					textUnitResults[tuID] = &types.TextUnitResult{
+						TextUnit:   tu,
+						Score:      scorer.textUnitScore(er),
+						Similarity: 0,
+						Hop:        er.Hop + 1,
 					}
 				}
 			}
@@ -844,7 +944,16 @@ func (e *Engine) Query(sessionID string, spec types.QuerySpec) (*types.ContextPa
 		}
 	}
 
-	// Phase 4: Sort and limit results
+	// Phase 4: Sort, re-rank and limit results, by count or, with a token
+	// budget, by packing below
+	budgeted := spec.TokenBudget > 0
+	rerankLimit := func(max, n int) int {
+		if budgeted {
+			return n
+		}
+		return max
+	}
+	var demotions []types.Demotion
 	textUnitList := make([]types.TextUnitResult, 0, len(textUnitResults))
 	for _, tur := range textUnitResults {
 		textUnitList = append(textUnitList, *tur)
@@ -852,7 +961,11 @@ func (e *Engine) Query(sessionID string, spec types.QuerySpec) (*types.ContextPa
 	sort.Slice(textUnitList, func(i, j int) bool {
 		return textUnitList[i].Score > textUnitList[j].Score
 	})
-	if len(textUnitList) > spec.MaxTextUnits {
+	if rr.active() {
+		textUnitList, demotions = rr.rerankTextUnits(textUnitList, rerankLimit(spec.MaxTextUnits, len(textUnitList)), textUnitIndex)
+		qlog.demotions = append(qlog.demotions, demotions...)
+	}
+	if !budgeted && len(textUnitList) > spec.MaxTextUnits {
 		textUnitList = textUnitList[:spec.MaxTextUnits]
 	}
 
@@ -863,7 +976,11 @@ func (e *Engine) Query(sessionID string, spec types.QuerySpec) (*types.ContextPa
 	sort.Slice(entityList, func(i, j int) bool {
 		return entityList[i].Score > entityList[j].Score
 	})
-	if len(entityList) > spec.MaxEntities {
+	if rr.active() {
+		entityList, demotions = rr.rerankEntities(entityList, rerankLimit(spec.MaxEntities, len(entityList)), entityIndex)
+		qlog.demotions = append(qlog.demotions, demotions...)
+	}
+	if !budgeted && len(entityList) > spec.MaxEntities {
 		entityList = entityList[:spec.MaxEntities]
 	}
 
@@ -874,23 +991,35 @@ func (e *Engine) Query(sessionID string, spec types.QuerySpec) (*types.ContextPa
 	sort.Slice(communityList, func(i, j int) bool {
 		return communityList[i].Score > communityList[j].Score
 	})
-	if len(communityList) > spec.MaxCommunities {
+	if rr.active() {
+		communityList, demotions = rr.rerankCommunities(communityList, rerankLimit(spec.MaxCommunities, len(communityList)), communityIndex)
+		qlog.demotions = append(qlog.demotions, demotions...)
+	}
+	if !budgeted && len(communityList) > spec.MaxCommunities {
 		communityList = communityList[:spec.MaxCommunities]
 	}
 
-	stats.DurationMicros = time.Since(startTime).Microseconds()
-
-	// Save query log
-	e.queryLogs.Set(queryID, qlog)
-
-	return &types.ContextPack{
+	pack := &types.ContextPack{
 		QueryID:       queryID,
 		TextUnits:     textUnitList,
 		Entities:      entityList,
 		Communities:   communityList,
 		Relationships: relationshipResults,
-		Stats:         stats,
-	}, nil
+	}
+	if budgeted {
+		packContext(pack, spec.TokenBudget, shares)
+	}
+	if spec.ContextFormat != "" {
+		pack.Context = renderContext(pack, spec.ContextFormat)
+	}
+
+	stats.DurationMicros = time.Since(startTime).Microseconds()
+	pack.Stats = stats
+
+	// Save query log
+	e.queryLogs.Set(queryID, qlog)
+
+	return pack, nil
 }
 
 // =============================================================================
@@ -907,6 +1036,7 @@ func (e *Engine) Explain(queryID uint64) (*types.ExplainPack, bool) {
 		QueryID:   queryID,
 		Seeds:     qlog.seeds,
 		Traversal: qlog.traversal,
+		Demotions: qlog.demotions,
 	}, true
 }
 
@@ -919,6 +1049,7 @@ func (e *Engine) Info() types.ServerInfo {
 	defer e.mu.RUnlock()
 
 	var docCount, tuCount, entCount, relCount, commCount int
+	mem := types.VectorMemory{Recall: 1}
 	for _, sess := range e.sessions {
 		if !sess.IsExpired() {
 			docCount += sess.DocumentCount()
@@ -926,6 +1057,7 @@ func (e *Engine) Info() types.ServerInfo {
 			entCount += sess.EntityCount()
 			relCount += sess.RelationshipCount()
 			commCount += sess.CommunityCount()
+			mem.Add(sess.VectorMemory())
 		}
 	}
 
@@ -938,6 +1070,7 @@ func (e *Engine) Info() types.ServerInfo {
 		CommunityCount:    commCount,
 		VectorDim:         e.vectorDim,
 		SessionCount:      len(e.sessions),
+		VectorMemory:      mem,
 	}
 }
 
@@ -955,58 +1088,12 @@ func (e *Engine) InfoForSession(sessionID string) (types.ServerInfo, error) {
 		EntityCount:       sess.EntityCount(),
 		RelationshipCount: sess.RelationshipCount(),
 		CommunityCount:    sess.CommunityCount(),
-		VectorDim:         e.vectorDim,
+		VectorDim:         sess.VectorDim(),
 		SessionCount:      1,
+		VectorMemory:      sess.VectorMemory(),
 	}, nil
 }
 
-// =============================================================================
-// Index Operations
-// =============================================================================
-
-// RebuildVectorIndices rebuilds all vector indices for a session
-func (e *Engine) RebuildVectorIndices(sessionID string) error {
-	sess, err := e.getSession(sessionID)
-	if err != nil {
-		return err
-	}
-
-	// Get vectors from current indices before recreating
-	tuIdx := sess.GetTextUnitIndex()
-	entIdx := sess.GetEntityIndex()
-	commIdx := sess.GetCommunityIndex()
-
-	textUnitVectors := tuIdx.GetAllVectors()
-	entityVectors := entIdx.GetAllVectors()
-	communityVectors := commIdx.GetAllVectors()
-
-	// Clear and rebuild
-	sess.Clear()
-
-	// Re-add vectors
-	newTuIdx := sess.GetTextUnitIndex()
-	newEntIdx := sess.GetEntityIndex()
-	newCommIdx := sess.GetCommunityIndex()
-
-	for id, vec := range textUnitVectors {
-		if err := newTuIdx.Add(id, vec); err != nil {
-			return err
-		}
-	}
-	for id, vec := range entityVectors {
-		if err := newEntIdx.Add(id, vec); err != nil {
-			return err
-		}
-	}
-	for id, vec := range communityVectors {
-		if err := newCommIdx.Add(id, vec); err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
 // =============================================================================
 // Bulk Operations
 // =============================================================================
@@ -1197,7 +1284,29 @@ func (e *Engine) Snapshot(w io.Writer) error {
 	return encoder.Encode(snapshot)
 }
 
-// Restore deserializes engine state from a reader
+// SessionRestoreError reports the sessions Restore skipped. The other
+// sessions of the snapshot were restored.
+type SessionRestoreError struct {
+	Failed map[string]error // session ID -> reason
+}
+
+// Error implements the error interface
+func (e *SessionRestoreError) Error() string {
+	ids := make([]string, 0, len(e.Failed))
+	for id := range e.Failed {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = fmt.Sprintf("session %s: %v", id, e.Failed[id])
+	}
+	return fmt.Sprintf("%d session(s) not restored: %s", len(ids), strings.Join(parts, "; "))
+}
+
+// Restore deserializes engine state from a reader. A session that fails
+// to restore is skipped and reported in a *SessionRestoreError; the
+// remaining sessions still replace the current state.
 func (e *Engine) Restore(r io.Reader) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
@@ -1208,23 +1317,36 @@ func (e *Engine) Restore(r io.Reader) error {
 		return fmt.Errorf("decode snapshot: %w", err)
 	}
 
-	// Validate snapshot
-	if snapshot.VectorDim != e.vectorDim {
-		return fmt.Errorf("vector dimension mismatch: snapshot=%d, engine=%d", snapshot.VectorDim, e.vectorDim)
+	// Sessions carry their own dimension; older snapshots only record the
+	// dimension of the engine that wrote them
+	defaultDim := snapshot.VectorDim
+	if defaultDim <= 0 {
+		defaultDim = e.vectorDim
 	}
 
-	// Clear current state
-	e.sessions = make(map[string]*store.SessionStore)
-
-	// Restore sessions
+	// Restore sessions, validating each against its own dimension
+	sessions := make(map[string]*store.SessionStore, len(snapshot.Sessions))
+	var failed map[string]error
 	for id, sessSnapshot := range snapshot.Sessions {
-		sess := store.NewSessionStore(id, e.vectorDim)
-		if err := sess.RestoreFromSnapshot(sessSnapshot); err != nil {
-			return fmt.Errorf("restore session %s: %w", id, err)
+		sess := store.NewSessionStore(id, defaultDim)
+		err := fmt.Errorf("empty session snapshot")
+		if sessSnapshot != nil {
+			err = sess.RestoreFromSnapshot(sessSnapshot)
 		}
-		e.sessions[id] = sess
+		if err != nil {
+			if failed == nil {
+				failed = make(map[string]error)
+			}
+			failed[id] = err
+			continue
+		}
+		sessions[id] = sess
 	}
+	e.sessions = sessions
 
+	if len(failed) > 0 {
+		return &SessionRestoreError{Failed: failed}
+	}
 	return nil
 }
 
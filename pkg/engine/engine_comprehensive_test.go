@@ -3,12 +3,20 @@ package engine
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
 	"math/rand"
+	"reflect"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/gibram-io/gibram/pkg/graph"
+	"github.com/gibram-io/gibram/pkg/store"
 	"github.com/gibram-io/gibram/pkg/types"
+	"github.com/gibram-io/gibram/pkg/vector"
 )
 
 // =============================================================================
@@ -522,6 +530,269 @@ func TestEngine_RebuildVectorIndices(t *testing.T) {
 	if result.QueryID == 0 {
 		t.Error("Should get valid query result after rebuild")
 	}
+	if len(result.Entities) == 0 || result.Entities[0].Entity.ExternalID != "ent-1" {
+		t.Errorf("rebuild lost the entity: %+v", result.Entities)
+	}
+	if _, ok := e.GetDocument(testSessionID, doc.ID); !ok {
+		t.Error("rebuild should not drop session data")
+	}
+}
+
+func TestEngine_StartIndexRebuild(t *testing.T) {
+	e := NewEngine(testVectorDim)
+	for i := 0; i < 300; i++ {
+		mustAddEntity(t, e, testSessionID, "ent-"+itoa(i), "E"+itoa(i), "test", "Desc", distinctVector(testVectorDim))
+	}
+
+	if _, err := e.StartIndexRebuild("missing", store.DefaultSessionOptions()); err == nil {
+		t.Error("StartIndexRebuild on a missing session should fail")
+	}
+	bad := store.DefaultSessionOptions()
+	bad.VectorDim = testVectorDim * 2
+	if _, err := e.StartIndexRebuild(testSessionID, bad); err == nil {
+		t.Error("StartIndexRebuild changing the vector dimension should fail")
+	}
+
+	opts := store.DefaultSessionOptions()
+	opts.EntityIndex.SetM(8)
+	opts.EntityIndex.Metric = vector.MetricL2
+	opts.EntityIndex.EfSearch = 400 // exhaustive, so the late entity is always found
+	task, err := e.StartIndexRebuild(testSessionID, opts)
+	if err != nil {
+		t.Fatalf("StartIndexRebuild failed: %v", err)
+	}
+	if _, err := e.StartIndexRebuild(testSessionID, opts); err != ErrRebuildInProgress && err != nil {
+		t.Errorf("concurrent StartIndexRebuild = %v, want ErrRebuildInProgress or a finished first rebuild", err)
+	}
+
+	// Queries and writes are served while the rebuild runs
+	spec := types.DefaultQuerySpec()
+	spec.QueryVector = distinctVector(testVectorDim)
+	if _, err := e.Query(testSessionID, spec); err != nil {
+		t.Errorf("Query during rebuild failed: %v", err)
+	}
+	lateVec := distinctVector(testVectorDim)
+	late := mustAddEntity(t, e, testSessionID, "ent-late", "Late", "test", "Desc", lateVec)
+
+	done, err := e.WaitRebuild(task.ID)
+	if err != nil {
+		t.Fatalf("WaitRebuild failed: %v", err)
+	}
+	if done.Status != TaskStatusComplete || done.Progress != 1 || done.Error != nil {
+		t.Errorf("rebuild task = %+v, want complete", done)
+	}
+	if tasks := e.RebuildTasks(testSessionID); len(tasks) == 0 || tasks[0].ID != task.ID {
+		t.Errorf("RebuildTasks = %+v, want %s first", tasks, task.ID)
+	}
+
+	info, err := e.GetSessionInfo(testSessionID)
+	if err != nil {
+		t.Fatalf("GetSessionInfo failed: %v", err)
+	}
+	if info.EntityIndex.M != 8 || info.EntityIndex.Metric != "l2" || info.EntityIndexSize != 301 {
+		t.Errorf("entity index after rebuild = %+v, size %d", info.EntityIndex, info.EntityIndexSize)
+	}
+	sess, _ := e.GetSession(testSessionID)
+	if results := sess.GetEntityIndex().Search(lateVec, 1); len(results) != 1 || results[0].ID != late.ID {
+		t.Errorf("entity added during rebuild not found: %+v", results)
+	}
+}
+
+func TestEngine_BulkLoad(t *testing.T) {
+	e := NewEngine(testVectorDim)
+	if _, err := e.CommitBulkLoad(testSessionID, 0); err == nil {
+		t.Error("CommitBulkLoad on a missing session should fail")
+	}
+	if wasBulk, err := e.BeginBulkLoad(testSessionID); err != nil || wasBulk {
+		t.Fatalf("BeginBulkLoad() = %v, %v", wasBulk, err)
+	}
+	if wasBulk, _ := e.BeginBulkLoad(testSessionID); !wasBulk {
+		t.Error("second BeginBulkLoad should report the session as bulk loading")
+	}
+
+	const n = 1500
+	inputs := make([]types.BulkEntityInput, n)
+	for i := range inputs {
+		inputs[i] = types.BulkEntityInput{ExternalID: "ent-" + itoa(i), Title: "E" + itoa(i), Type: "test", Embedding: distinctVector(testVectorDim)}
+	}
+	ids, err := e.MSetEntities(testSessionID, inputs)
+	if err != nil || len(ids) != n {
+		t.Fatalf("MSetEntities() = %d ids, %v", len(ids), err)
+	}
+	sess, _ := e.GetSession(testSessionID)
+	if got := sess.GetEntityIndex().Count(); got != 0 {
+		t.Errorf("entity index has %d vectors during the bulk load, want 0", got)
+	}
+
+	if _, err := e.CommitBulkLoad(testSessionID, MaxBulkWorkers+1); err == nil {
+		t.Error("CommitBulkLoad with too many workers should fail")
+	}
+	task, err := e.CommitBulkLoad(testSessionID, 4)
+	if err != nil {
+		t.Fatalf("CommitBulkLoad failed: %v", err)
+	}
+	if !strings.HasPrefix(task.ID, "bulk_") {
+		t.Errorf("task ID = %q, want a bulk_ prefix", task.ID)
+	}
+	done, err := e.WaitRebuild(task.ID)
+	if err != nil {
+		t.Fatalf("WaitRebuild failed: %v", err)
+	}
+	if done.Status != TaskStatusComplete || done.Progress != 1 || done.Index != store.IndexEntity {
+		t.Errorf("bulk task = %+v, want complete", done)
+	}
+	if got := sess.GetEntityIndex().Count(); got != n {
+		t.Errorf("entity index has %d vectors after the build, want %d", got, n)
+	}
+	if _, err := e.CommitBulkLoad(testSessionID, 0); err != ErrNotBulkLoading {
+		t.Errorf("CommitBulkLoad after the build = %v, want ErrNotBulkLoading", err)
+	}
+}
+
+func TestEngine_DeleteEntityTombstones(t *testing.T) {
+	e := NewEngine(testVectorDim)
+	var ents []*types.Entity
+	deletedVec := distinctVector(testVectorDim)
+	ents = append(ents, mustAddEntity(t, e, testSessionID, "ent-0", "E0", "test", "Desc", deletedVec))
+	for i := 1; i < 10; i++ {
+		ents = append(ents, mustAddEntity(t, e, testSessionID, "ent-"+itoa(i), "E"+itoa(i), "test", "Desc", distinctVector(testVectorDim)))
+	}
+
+	// Below the default tombstone ratio the deleted vector is only tombstoned
+	if !e.DeleteEntity(testSessionID, ents[0].ID) {
+		t.Fatal("DeleteEntity failed")
+	}
+	if mem := e.Info().VectorMemory; mem.Vectors != 9 || mem.Tombstones != 1 {
+		t.Errorf("vector memory after delete = %d vectors, %d tombstones", mem.Vectors, mem.Tombstones)
+	}
+	sess, _ := e.GetSession(testSessionID)
+	idx := sess.GetEntityIndex()
+	for _, r := range idx.Search(deletedVec, 10) {
+		if r.ID == ents[0].ID {
+			t.Error("deleted entity returned by vector search")
+		}
+	}
+
+	if purged := idx.Compact(); purged != 1 {
+		t.Errorf("Compact() = %d, want 1", purged)
+	}
+	if mem := e.Info().VectorMemory; mem.Vectors != 9 || mem.Tombstones != 0 {
+		t.Errorf("vector memory after compaction = %d vectors, %d tombstones", mem.Vectors, mem.Tombstones)
+	}
+}
+
+func TestEngine_CheckRecall(t *testing.T) {
+	e := NewEngine(testVectorDim)
+	for i := 0; i < 60; i++ {
+		mustAddEntity(t, e, testSessionID, "ent-"+itoa(i), "E"+itoa(i), "test", "Desc", distinctVector(testVectorDim))
+	}
+
+	if _, err := e.CheckRecall("missing", types.RecallSpec{}); err == nil {
+		t.Error("CheckRecall on a missing session should fail")
+	}
+	if _, err := e.CheckRecall(testSessionID, types.RecallSpec{Index: "bogus"}); err == nil {
+		t.Error("CheckRecall on an unknown index should fail")
+	}
+
+	// Only the entity index holds vectors
+	reports, err := e.CheckRecall(testSessionID, types.RecallSpec{K: 5, Ef: 60, Samples: 20})
+	if err != nil {
+		t.Fatalf("CheckRecall failed: %v", err)
+	}
+	if len(reports) != 1 || reports[0].Index != types.SearchTypeEntity {
+		t.Fatalf("reports = %+v, want the entity index only", reports)
+	}
+	r := reports[0]
+	if r.Vectors != 60 || r.Queries != 20 || r.K != 5 || r.Ef != 60 || r.Recall < 0.9 || r.Visited.Avg == 0 {
+		t.Errorf("entity report = %+v", r)
+	}
+
+	reports, err = e.CheckRecall(testSessionID, types.RecallSpec{Index: types.SearchTypeCommunity})
+	if err != nil || len(reports) != 1 || reports[0].Queries != 0 {
+		t.Errorf("CheckRecall on the empty community index = %+v, %v", reports, err)
+	}
+	spec := types.RecallSpec{Index: types.SearchTypeEntity, QueryVectors: [][]float32{make([]float32, 3)}}
+	if _, err := e.CheckRecall(testSessionID, spec); err == nil {
+		t.Error("CheckRecall with a wrong query dimension should fail")
+	}
+}
+
+func TestEngine_Search(t *testing.T) {
+	e := NewEngine(testVectorDim)
+	vecs := make([][]float32, 40)
+	for i := range vecs {
+		vecs[i] = distinctVector(testVectorDim)
+		entType := "person"
+		if i%2 == 1 {
+			entType = "organization"
+		}
+		mustAddEntity(t, e, testSessionID, "ent-"+itoa(i), "E"+itoa(i), entType, "Desc", vecs[i])
+	}
+
+	spec := types.SearchSpec{
+		Index:        types.SearchTypeEntity,
+		QueryVectors: [][]float32{vecs[3], vecs[8]},
+		K:            5,
+		Ef:           100,
+	}
+	results, err := e.Search(testSessionID, spec)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 2 || len(results[0]) != 5 || len(results[1]) != 5 {
+		t.Fatalf("unexpected result shape: %+v", results)
+	}
+	if results[0][0].ExternalID != "ent-3" || results[1][0].ExternalID != "ent-8" || results[0][0].Entity != nil {
+		t.Errorf("unexpected top hits: %+v, %+v", results[0][0], results[1][0])
+	}
+
+	// Deduplication: only near-identical vectors pass the threshold
+	threshold := float32(0.999)
+	spec.MinSimilarity = &threshold
+	spec.IncludeObjects = true
+	results, err = e.Search(testSessionID, spec)
+	if err != nil {
+		t.Fatalf("Search with threshold failed: %v", err)
+	}
+	if len(results[0]) != 1 || results[0][0].Entity == nil || results[0][0].Entity.ExternalID != "ent-3" {
+		t.Errorf("thresholded search = %+v", results[0])
+	}
+
+	// Range search with filters
+	threshold = -1
+	results, err = e.Search(testSessionID, types.SearchSpec{
+		Index:             types.SearchTypeEntity,
+		QueryVectors:      [][]float32{vecs[0]},
+		MinSimilarity:     &threshold,
+		Range:             true,
+		FilterEntityTypes: []string{"Person"},
+		IncludeObjects:    true,
+	})
+	if err != nil {
+		t.Fatalf("range Search failed: %v", err)
+	}
+	if len(results[0]) != 20 {
+		t.Errorf("range search returned %d hits, want 20", len(results[0]))
+	}
+	for _, hit := range results[0] {
+		if hit.Entity.Type != "person" {
+			t.Errorf("filtered hit has type %q", hit.Entity.Type)
+		}
+	}
+
+	bad := []types.SearchSpec{
+		{Index: "relationship", QueryVectors: [][]float32{vecs[0]}},
+		{Index: types.SearchTypeEntity},
+		{Index: types.SearchTypeEntity, QueryVectors: [][]float32{{1, 2}}},
+		{Index: types.SearchTypeEntity, QueryVectors: [][]float32{vecs[0]}, Range: true},
+		{Index: types.SearchTypeEntity, QueryVectors: [][]float32{vecs[0]}, FilterDocumentIDs: []uint64{1}},
+		{Index: types.SearchTypeCommunity, QueryVectors: [][]float32{vecs[0]}, FilterEntityTypes: []string{"person"}},
+	}
+	for i, spec := range bad {
+		if _, err := e.Search(testSessionID, spec); err == nil {
+			t.Errorf("bad spec %d: expected error", i)
+		}
+	}
 }
 
 func TestEngine_Clear(t *testing.T) {
@@ -580,9 +851,363 @@ func TestEngine_SnapshotRestoreVectorMismatch(t *testing.T) {
 		t.Fatalf("Snapshot failed: %v", err)
 	}
 
-	err := e2.Restore(&buf)
-	if err == nil {
-		t.Error("Restore should fail with dimension mismatch")
+	// Sessions keep the dimension they were written with
+	if err := e2.Restore(&buf); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	info, err := e2.GetSessionInfo(testSessionID)
+	if err != nil {
+		t.Fatalf("GetSessionInfo failed: %v", err)
+	}
+	if info.VectorDim != testVectorDim {
+		t.Errorf("VectorDim after restore = %d, want %d", info.VectorDim, testVectorDim)
+	}
+}
+
+func TestEngine_CreateSessionVectorDim(t *testing.T) {
+	e1 := NewEngine(testVectorDim)
+
+	opts := store.DefaultSessionOptions()
+	opts.VectorDim = 8
+	opts.EntityIndex.SetM(8)
+	opts.EntityIndex.EfSearch = 100
+	if err := e1.CreateSession("small", opts); err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	mustAddEntity(t, e1, "small", "ent-1", "Entity", "test", "Desc", randomVector(8))
+	if _, err := e1.AddEntity("small", "ent-2", "Entity", "test", "Desc", randomVector(testVectorDim)); err == nil {
+		t.Error("AddEntity with the engine dimension should fail in an 8-dim session")
+	}
+	mustAddEntity(t, e1, testSessionID, "ent-1", "Entity", "test", "Desc", randomVector(testVectorDim))
+
+	bad := store.DefaultSessionOptions()
+	bad.EntityIndex.M = 1
+	if err := e1.CreateSession("bad", bad); err == nil {
+		t.Error("CreateSession with m=1 should fail")
+	}
+	bad = store.DefaultSessionOptions()
+	bad.VectorDim = MaxVectorDim + 1
+	if err := e1.CreateSession("bad", bad); err == nil {
+		t.Error("CreateSession above MaxVectorDim should fail")
+	}
+
+	var buf bytes.Buffer
+	if err := e1.Snapshot(&buf); err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+	data := buf.Bytes()
+
+	e2 := NewEngine(testVectorDim)
+	if err := e2.Restore(bytes.NewReader(data)); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	info, err := e2.GetSessionInfo("small")
+	if err != nil {
+		t.Fatalf("GetSessionInfo failed: %v", err)
+	}
+	if info.VectorDim != 8 || info.EntityIndex.M != 8 || info.EntityIndex.EfSearch != 100 {
+		t.Errorf("options after restore = dim %d, %+v", info.VectorDim, info.EntityIndex)
+	}
+
+	// A session whose vectors do not match its own dimension is skipped
+	// and reported; the other sessions are restored
+	var snapshot EngineSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		t.Fatalf("Unmarshal snapshot: %v", err)
+	}
+	snapshot.Sessions["small"].Options.VectorDim = 16
+	corrupt, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Fatalf("Marshal snapshot: %v", err)
+	}
+	e3 := NewEngine(testVectorDim)
+	err = e3.Restore(bytes.NewReader(corrupt))
+	var partial *SessionRestoreError
+	if !errors.As(err, &partial) || len(partial.Failed) != 1 || partial.Failed["small"] == nil {
+		t.Fatalf("Restore with a mismatched session = %v, want a SessionRestoreError for small", err)
+	}
+	if _, err := e3.GetSessionInfo("small"); err == nil {
+		t.Error("session with mismatched vectors should not be restored")
+	}
+	if info, err := e3.GetSessionInfo(testSessionID); err != nil || info.EntityCount != 1 {
+		t.Errorf("other sessions should be restored: %+v, %v", info, err)
+	}
+}
+
+func TestEngine_CreateSessionMetrics(t *testing.T) {
+	e1 := NewEngine(testVectorDim)
+
+	opts := store.DefaultSessionOptions()
+	opts.EntityIndex.Metric = vector.MetricDot
+	if err := e1.CreateSession(testSessionID, opts); err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	if err := e1.CreateSession(testSessionID, opts); err != ErrSessionExists {
+		t.Errorf("CreateSession on existing session = %v, want ErrSessionExists", err)
+	}
+
+	mustAddEntity(t, e1, testSessionID, "ent-1", "Entity", "test", "Desc", randomVector(testVectorDim))
+
+	var buf bytes.Buffer
+	if err := e1.Snapshot(&buf); err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+	e2 := NewEngine(testVectorDim)
+	if err := e2.Restore(&buf); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+
+	info, err := e2.GetSessionInfo(testSessionID)
+	if err != nil {
+		t.Fatalf("GetSessionInfo failed: %v", err)
+	}
+	if info.EntityIndex.Metric != "dot" || info.TextUnitIndex.Metric != "cosine" {
+		t.Errorf("metrics after restore = entity %q, textunit %q; want dot, cosine", info.EntityIndex.Metric, info.TextUnitIndex.Metric)
+	}
+}
+
+func TestEngine_QuantizedSessionSnapshot(t *testing.T) {
+	e1 := NewEngine(testVectorDim)
+
+	opts := store.DefaultSessionOptions()
+	opts.EntityIndex.Quantization = vector.QuantizationScalar8
+	opts.EntityIndex.QuantTrainSize = 50
+	if err := e1.CreateSession(testSessionID, opts); err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	bad := store.DefaultSessionOptions()
+	bad.EntityIndex.Quantization = vector.QuantizationProduct
+	bad.EntityIndex.PQSubvectors = testVectorDim + 1
+	if err := e1.CreateSession("bad", bad); err == nil {
+		t.Error("CreateSession with pq_subvectors not dividing the dimension should fail")
+	}
+
+	for i := 0; i < 80; i++ {
+		mustAddEntity(t, e1, testSessionID, "ent-"+itoa(i), "E"+itoa(i), "test", "Desc", distinctVector(testVectorDim))
+	}
+
+	// The quantizer is trained in the background
+	deadline := time.Now().Add(5 * time.Second)
+	for e1.Info().VectorMemory.QuantizedVectors < 80 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	mem := e1.Info().VectorMemory
+	if mem.Vectors != 80 || mem.QuantizedVectors != 80 || mem.Bytes >= mem.FullPrecisionBytes/2 {
+		t.Errorf("vector memory = %+v, want 80 quantized vectors in under half the full-precision bytes", mem)
+	}
+	if mem.Recall <= 0 || mem.Recall > 1 {
+		t.Errorf("recall = %v, want in (0, 1]", mem.Recall)
+	}
+
+	var buf bytes.Buffer
+	if err := e1.Snapshot(&buf); err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+	var snapshot EngineSnapshot
+	if err := json.Unmarshal(buf.Bytes(), &snapshot); err != nil {
+		t.Fatalf("Unmarshal snapshot: %v", err)
+	}
+	sessSnap := snapshot.Sessions[testSessionID]
+	if sessSnap.EntityQuantized == nil || len(sessSnap.EntityQuantized.Codes) != 80 || len(sessSnap.EntityVectors) != 0 {
+		t.Fatalf("snapshot should hold 80 entity codes and no float vectors, got %d vectors", len(sessSnap.EntityVectors))
+	}
+
+	e2 := NewEngine(testVectorDim)
+	if err := e2.Restore(&buf); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if got := e2.Info().VectorMemory; got != mem {
+		t.Errorf("vector memory after restore = %+v, want %+v", got, mem)
+	}
+	sess, err := e2.GetSession(testSessionID)
+	if err != nil {
+		t.Fatalf("GetSession failed: %v", err)
+	}
+	if got := sess.Snapshot().EntityQuantized.Codes; !reflect.DeepEqual(got, sessSnap.EntityQuantized.Codes) {
+		t.Error("entity codes changed across restore")
+	}
+}
+
+func TestEngine_QueryFilters(t *testing.T) {
+	e := NewEngine(testVectorDim)
+	query := randomVector(testVectorDim)
+
+	doc1 := mustAddDocument(t, e, testSessionID, "doc-1", "a.pdf")
+	doc2 := mustAddDocument(t, e, testSessionID, "doc-2", "b.pdf")
+	for i := 0; i < 40; i++ {
+		entType := "concept"
+		if i%4 == 0 {
+			entType = "person"
+		}
+		ent := mustAddEntity(t, e, testSessionID, "ent-"+itoa(i), "E"+itoa(i), entType, "desc", randomVector(testVectorDim))
+		if !e.SetEntityAttrs(testSessionID, ent.ID, map[string]string{"lang": []string{"en", "id"}[i%2]}) {
+			t.Fatalf("SetEntityAttrs(%d) failed", ent.ID)
+		}
+
+		docID := doc1.ID
+		if i%2 == 1 {
+			docID = doc2.ID
+		}
+		mustAddTextUnit(t, e, testSessionID, "tu-"+itoa(i), docID, "content", randomVector(testVectorDim), 10)
+	}
+
+	spec := types.DefaultQuerySpec()
+	spec.QueryVector = query
+	spec.TopK = 5
+	spec.KHops = 0
+	spec.FilterEntityTypes = []string{"Person"}
+	spec.FilterAttrs = map[string]string{"lang": "en"}
+	spec.FilterDocumentIDs = []uint64{doc2.ID}
+
+	result, err := e.Query(testSessionID, spec)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(result.Entities) != 5 {
+		t.Errorf("expected 5 filtered entities, got %d", len(result.Entities))
+	}
+	for _, er := range result.Entities {
+		if er.Entity.Type != "person" || er.Entity.Attrs["lang"] != "en" {
+			t.Errorf("entity %s (%s, %v) does not pass the filters", er.Entity.ExternalID, er.Entity.Type, er.Entity.Attrs)
+		}
+	}
+	if len(result.TextUnits) != 5 {
+		t.Errorf("expected 5 scoped text units, got %d", len(result.TextUnits))
+	}
+	for _, tur := range result.TextUnits {
+		if tur.TextUnit.DocumentID != doc2.ID {
+			t.Errorf("text unit %s is outside the document scope", tur.TextUnit.ExternalID)
+		}
+	}
+}
+
+// Filtered searches must not take session locks inside an index search;
+// writers take the session lock first and the index lock second
+func TestEngine_FilteredSearchConcurrentWrites(t *testing.T) {
+	e := NewEngine(testVectorDim)
+	doc := mustAddDocument(t, e, testSessionID, "doc", "a.pdf")
+	for i := 0; i < 20; i++ {
+		mustAddEntity(t, e, testSessionID, "seed-"+itoa(i), "Seed "+itoa(i), "person", "lender", randomVector(testVectorDim))
+		mustAddTextUnit(t, e, testSessionID, "seed-tu-"+itoa(i), doc.ID, "a lender", randomVector(testVectorDim), 2)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var wg sync.WaitGroup
+		for w := 0; w < 8; w++ {
+			wg.Add(2)
+			go func(w int) {
+				defer wg.Done()
+				for i := 0; i < 50; i++ {
+					id := itoa(w) + "-" + itoa(i)
+					e.AddEntity(testSessionID, "ent-"+id, "E"+id, "person", "lender", randomVector(testVectorDim))
+					e.AddTextUnit(testSessionID, "tu-"+id, doc.ID, "a lender", randomVector(testVectorDim), 2)
+				}
+			}(w)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 50; i++ {
+					spec := types.DefaultQuerySpec()
+					spec.QueryVector = randomVector(testVectorDim)
+					spec.QueryText = "lender"
+					spec.FilterEntityTypes = []string{"person"}
+					spec.FilterDocumentIDs = []uint64{doc.ID}
+					if _, err := e.Query(testSessionID, spec); err != nil {
+						t.Errorf("Query failed: %v", err)
+						return
+					}
+					if _, err := e.Search(testSessionID, types.SearchSpec{
+						Index:             types.SearchTypeEntity,
+						QueryVectors:      [][]float32{spec.QueryVector},
+						FilterEntityTypes: []string{"person"},
+					}); err != nil {
+						t.Errorf("Search failed: %v", err)
+						return
+					}
+				}
+			}()
+		}
+		wg.Wait()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(30 * time.Second):
+		t.Fatal("filtered searches deadlocked with concurrent writes")
+	}
+}
+
+func TestEngine_HybridQuery(t *testing.T) {
+	e := NewEngine(testVectorDim)
+
+	doc := mustAddDocument(t, e, testSessionID, "doc-1", "regs.pdf")
+	for i := 0; i < 30; i++ {
+		mustAddTextUnit(t, e, testSessionID, "tu-"+itoa(i), doc.ID, "general banking text number "+itoa(i), randomVector(testVectorDim), 10)
+	}
+	target := mustAddTextUnit(t, e, testSessionID, "tu-reg", doc.ID, "Regulation PBI 23/2021 on payment systems", randomVector(testVectorDim), 10)
+	ent := mustAddEntity(t, e, testSessionID, "ent-bbca", "BBCA.JK", "organization", "listed bank", randomVector(testVectorDim))
+
+	// Text only: no query vector at all
+	spec := types.DefaultQuerySpec()
+	spec.KHops = 0
+	spec.QueryText = "pbi 23/2021"
+	spec.SearchTypes = []types.SearchType{types.SearchTypeTextUnit}
+	result, err := e.Query(testSessionID, spec)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(result.TextUnits) == 0 || result.TextUnits[0].TextUnit.ID != target.ID || result.TextUnits[0].Score != 1 {
+		t.Fatalf("text-only query = %+v, want %d first with score 1", result.TextUnits, target.ID)
+	}
+
+	// RRF and weighted fusion both surface the lexical match among vector hits
+	for _, fusion := range []types.FusionMode{types.FusionRRF, types.FusionWeighted} {
+		spec = types.DefaultQuerySpec()
+		spec.KHops = 0
+		spec.QueryVector = randomVector(testVectorDim)
+		spec.QueryText = "BBCA"
+		spec.Fusion = fusion
+		spec.SearchTypes = []types.SearchType{types.SearchTypeEntity}
+		result, err = e.Query(testSessionID, spec)
+		if err != nil {
+			t.Fatalf("%s: Query failed: %v", fusion, err)
+		}
+		if len(result.Entities) == 0 || result.Entities[0].Entity.ID != ent.ID {
+			t.Errorf("%s: expected %d ranked first, got %+v", fusion, ent.ID, result.Entities)
+		}
+		for _, er := range result.Entities {
+			if er.Score <= 0 || er.Score > 1 {
+				t.Errorf("%s: seed score %f outside (0, 1]", fusion, er.Score)
+			}
+		}
+	}
+
+	// Lexical indices follow deletes and snapshots
+	e.DeleteTextUnit(testSessionID, target.ID)
+	var buf bytes.Buffer
+	if err := e.Snapshot(&buf); err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+	e2 := NewEngine(testVectorDim)
+	if err := e2.Restore(&buf); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	spec = types.DefaultQuerySpec()
+	spec.KHops = 0
+	spec.QueryText = "banking 7"
+	spec.SearchTypes = []types.SearchType{types.SearchTypeTextUnit, types.SearchTypeEntity}
+	result, err = e2.Query(testSessionID, spec)
+	if err != nil {
+		t.Fatalf("Query after restore failed: %v", err)
+	}
+	if len(result.TextUnits) == 0 || result.TextUnits[0].TextUnit.ExternalID != "tu-7" {
+		t.Errorf("restored lexical index: got %+v, want tu-7 first", result.TextUnits)
+	}
+	for _, tur := range result.TextUnits {
+		if tur.TextUnit.ID == target.ID {
+			t.Error("deleted text unit still matches")
+		}
 	}
 }
 
@@ -592,6 +1217,738 @@ func TestEngine_SnapshotRestoreVectorMismatch(t *testing.T) {
 
 // generateSemanticVector creates a pseudo-semantic vector for testing
 // In production, this would be a real embedding from OpenAI/etc.
+func TestEngine_QueryScoring(t *testing.T) {
+	e := NewEngine(testVectorDim)
+	axis := func(weights ...float32) []float32 {
+		v := make([]float32, testVectorDim)
+		copy(v, weights)
+		return v
+	}
+
+	// best seeds A (similarity 1) and B (similarity 0.2); X is a strong
+	// and Y a weak neighbour of A, Z a strong neighbour of B, W is shared
+	a := mustAddEntity(t, e, testSessionID, "a", "A", "t", "", axis(1))
+	b := mustAddEntity(t, e, testSessionID, "b", "B", "t", "", axis(0.2, 0.98))
+	x := mustAddEntity(t, e, testSessionID, "x", "X", "t", "", axis(0, 0, 1))
+	y := mustAddEntity(t, e, testSessionID, "y", "Y", "t", "", axis(0, 0, 0, 1))
+	z := mustAddEntity(t, e, testSessionID, "z", "Z", "t", "", axis(0, 0, 0, 0, 1))
+	w := mustAddEntity(t, e, testSessionID, "w", "W", "t", "", axis(0, 0, 0, 0, 0, 1))
+	mustAddRelationship(t, e, testSessionID, "ax", a.ID, x.ID, "r", "", 1)
+	mustAddRelationship(t, e, testSessionID, "ay", a.ID, y.ID, "r", "", 0.1)
+	mustAddRelationship(t, e, testSessionID, "bz", b.ID, z.ID, "r", "", 1)
+	mustAddRelationship(t, e, testSessionID, "aw", a.ID, w.ID, "r", "", 1)
+	mustAddRelationship(t, e, testSessionID, "bw", b.ID, w.ID, "r", "", 1)
+
+	query := func(scoring types.ScoringStrategy, aggregation types.ScoreAggregation) (map[uint64]float32, *types.ContextPack) {
+		t.Helper()
+		spec := types.DefaultQuerySpec()
+		spec.QueryVector = axis(1)
+		spec.SearchTypes = []types.SearchType{types.SearchTypeEntity}
+		spec.TopK = 2
+		spec.KHops = 1
+		spec.Scoring = scoring
+		spec.Aggregation = aggregation
+		result, err := e.Query(testSessionID, spec)
+		if err != nil {
+			t.Fatalf("Query(%s, %s) failed: %v", scoring, aggregation, err)
+		}
+		scores := make(map[uint64]float32)
+		for _, er := range result.Entities {
+			scores[er.Entity.ID] = er.Score
+		}
+		return scores, result
+	}
+
+	// Hop scoring cannot tell the neighbours apart
+	scores, _ := query("", "")
+	if scores[x.ID] != 0.5 || scores[y.ID] != 0.5 || scores[z.ID] != 0.5 {
+		t.Errorf("hop scores = %v, want 0.5 for every 1-hop neighbour", scores)
+	}
+
+	// Weighted scoring ranks the strong neighbour of the best seed first
+	scores, result := query(types.ScoringWeighted, "")
+	if !(scores[x.ID] > scores[z.ID] && scores[z.ID] > scores[y.ID]) {
+		t.Errorf("weighted scores x=%v z=%v y=%v, want x > z > y", scores[x.ID], scores[z.ID], scores[y.ID])
+	}
+	if want := scores[a.ID] * types.DefaultScoreDecay; scores[x.ID] != want || scores[w.ID] != want {
+		t.Errorf("max aggregation: x=%v w=%v, want %v", scores[x.ID], scores[w.ID], want)
+	}
+	explain, ok := e.Explain(result.QueryID)
+	if !ok || len(explain.Traversal) == 0 {
+		t.Fatal("Explain returned no traversal")
+	}
+	for _, step := range explain.Traversal {
+		if step.Cumulative <= 0 || step.Cumulative > scores[step.ToEntityID] {
+			t.Errorf("step %d->%d cumulative %v, entity score %v", step.FromEntityID, step.ToEntityID, step.Cumulative, scores[step.ToEntityID])
+		}
+	}
+
+	// Sum aggregation rewards the entity reached from both seeds
+	scores, _ = query(types.ScoringWeighted, types.AggregateSum)
+	if want := (scores[a.ID] + scores[b.ID]) * types.DefaultScoreDecay; math.Abs(float64(scores[w.ID]-want)) > 1e-6 || scores[w.ID] <= scores[x.ID] {
+		t.Errorf("sum aggregation: w=%v x=%v, want w=%v above x", scores[w.ID], scores[x.ID], want)
+	}
+
+	for _, spec := range []types.QuerySpec{
+		{QueryVector: axis(1), Scoring: "pagerank"},
+		{QueryVector: axis(1), Aggregation: "avg"},
+		{QueryVector: axis(1), Decay: 1.5},
+	} {
+		if _, err := e.Query(testSessionID, spec); err == nil {
+			t.Errorf("Query(%+v) should fail", spec)
+		}
+	}
+}
+
+func TestEngine_QueryPersonalizedPageRank(t *testing.T) {
+	e := NewEngine(testVectorDim)
+	seedVec := make([]float32, testVectorDim)
+	seedVec[0] = 1
+	other := func(i int) []float32 {
+		v := make([]float32, testVectorDim)
+		v[1+i] = 1
+		return v
+	}
+
+	// P is two hops from the seed along three paths, Q along one
+	doc := mustAddDocument(t, e, testSessionID, "doc", "a.txt")
+	seed := mustAddEntity(t, e, testSessionID, "seed", "Seed", "t", "", seedVec)
+	p := mustAddEntity(t, e, testSessionID, "p", "P", "t", "", other(0))
+	q := mustAddEntity(t, e, testSessionID, "q", "Q", "t", "", other(1))
+	for i, mid := range []string{"a", "b", "c", "d"} {
+		m := mustAddEntity(t, e, testSessionID, mid, mid, "t", "", other(2+i))
+		mustAddRelationship(t, e, testSessionID, "seed-"+mid, seed.ID, m.ID, "r", "", 1)
+		target := p
+		if mid == "d" {
+			target = q
+		}
+		mustAddRelationship(t, e, testSessionID, mid+"-"+target.ExternalID, m.ID, target.ID, "r", "", 1)
+	}
+	tuP := mustAddTextUnit(t, e, testSessionID, "tu-p", doc.ID, "about p", other(10), 5)
+	tuQ := mustAddTextUnit(t, e, testSessionID, "tu-q", doc.ID, "about q", other(11), 5)
+	e.LinkTextUnitToEntity(testSessionID, tuP.ID, p.ID)
+	e.LinkTextUnitToEntity(testSessionID, tuQ.ID, q.ID)
+
+	query := func(mutate func(*types.QuerySpec)) (*types.ContextPack, map[uint64]float32, map[uint64]float32) {
+		t.Helper()
+		spec := types.DefaultQuerySpec()
+		spec.QueryVector = seedVec
+		spec.SearchTypes = []types.SearchType{types.SearchTypeEntity}
+		spec.TopK = 1
+		spec.Expansion = types.ExpansionPPR
+		mutate(&spec)
+		result, err := e.Query(testSessionID, spec)
+		if err != nil {
+			t.Fatalf("Query failed: %v", err)
+		}
+		ents := make(map[uint64]float32)
+		for _, er := range result.Entities {
+			ents[er.Entity.ID] = er.Score
+		}
+		tus := make(map[uint64]float32)
+		for _, tur := range result.TextUnits {
+			tus[tur.TextUnit.ID] = tur.Score
+		}
+		return result, ents, tus
+	}
+
+	_, ents, _ := query(func(spec *types.QuerySpec) { spec.Expansion = types.ExpansionBFS })
+	if ents[p.ID] != ents[q.ID] {
+		t.Errorf("bfs scores p=%v q=%v, want a tie", ents[p.ID], ents[q.ID])
+	}
+
+	result, ents, tus := query(func(*types.QuerySpec) {})
+	if ents[seed.ID] != 1 || !(ents[p.ID] > ents[q.ID]) || ents[q.ID] <= 0 {
+		t.Errorf("ppr scores seed=%v p=%v q=%v, want seed 1 and p > q > 0", ents[seed.ID], ents[p.ID], ents[q.ID])
+	}
+	if tus[tuP.ID] != ents[p.ID] || tus[tuQ.ID] != ents[q.ID] {
+		t.Errorf("text unit scores %v, want the scores of their entities", tus)
+	}
+	explain, _ := e.Explain(result.QueryID)
+	for _, step := range explain.Traversal {
+		if step.Cumulative != ents[step.ToEntityID] {
+			t.Errorf("step to %d cumulative %v, want %v", step.ToEntityID, step.Cumulative, ents[step.ToEntityID])
+		}
+	}
+
+	// An explicit restart distribution moves the walk to Q
+	_, ents, _ = query(func(spec *types.QuerySpec) { spec.RestartWeights = map[uint64]float32{q.ID: 1} })
+	if ents[q.ID] != 1 || !(ents[q.ID] > ents[p.ID]) {
+		t.Errorf("restart at q: p=%v q=%v, want q best", ents[p.ID], ents[q.ID])
+	}
+
+	for _, mutate := range []func(*types.QuerySpec){
+		func(spec *types.QuerySpec) { spec.Expansion = "dfs" },
+		func(spec *types.QuerySpec) { spec.Restart = "random" },
+		func(spec *types.QuerySpec) { spec.Damping = 1 },
+		func(spec *types.QuerySpec) { spec.Iterations = types.MaxPPRIterations + 1 },
+		func(spec *types.QuerySpec) { spec.RestartWeights = map[uint64]float32{q.ID: -1} },
+	} {
+		spec := types.DefaultQuerySpec()
+		spec.QueryVector = seedVec
+		mutate(&spec)
+		if _, err := e.Query(testSessionID, spec); err == nil {
+			t.Errorf("Query with expansion %q restart %q damping %v iterations %d should fail",
+				spec.Expansion, spec.Restart, spec.Damping, spec.Iterations)
+		}
+	}
+}
+
+func TestEngine_FindPaths(t *testing.T) {
+	e := NewEngine(testVectorDim)
+
+	// alice -knows-> bob -works_at-> acme, alice -invested_in-> acme (weak)
+	alice := mustAddEntity(t, e, testSessionID, "alice", "Alice", "person", "", distinctVector(testVectorDim))
+	bob := mustAddEntity(t, e, testSessionID, "bob", "Bob", "person", "", distinctVector(testVectorDim))
+	acme := mustAddEntity(t, e, testSessionID, "acme", "Acme", "org", "", distinctVector(testVectorDim))
+	mustAddEntity(t, e, testSessionID, "carol", "Carol", "person", "", distinctVector(testVectorDim))
+	mustAddRelationship(t, e, testSessionID, "r1", alice.ID, bob.ID, "KNOWS", "old friends", 1)
+	mustAddRelationship(t, e, testSessionID, "r2", bob.ID, acme.ID, "WORKS_AT", "engineer", 1)
+	mustAddRelationship(t, e, testSessionID, "r3", alice.ID, acme.ID, "INVESTED_IN", "seed round", 0.2)
+
+	paths, err := e.FindPaths(testSessionID, types.PathSpec{SourceTitle: "alice", TargetTitle: "ACME", K: 5})
+	if err != nil {
+		t.Fatalf("FindPaths failed: %v", err)
+	}
+	if len(paths) != 2 || len(paths[0].Relationships) != 1 || paths[0].Relationships[0].Description != "seed round" || paths[1].Cost != 2 {
+		t.Fatalf("fewest-hop paths = %+v, want the direct investment first", paths)
+	}
+
+	paths, err = e.FindPaths(testSessionID, types.PathSpec{SourceID: acme.ID, TargetID: alice.ID, Weighted: true})
+	if err != nil {
+		t.Fatalf("weighted FindPaths failed: %v", err)
+	}
+	if len(paths) != 1 || len(paths[0].Entities) != 3 || paths[0].Entities[1].ID != bob.ID {
+		t.Errorf("weighted path = %+v, want acme-bob-alice", paths)
+	}
+
+	for name, spec := range map[string]types.PathSpec{
+		"directed":    {SourceID: acme.ID, TargetID: alice.ID, Directed: true},
+		"rel types":   {SourceID: alice.ID, TargetID: acme.ID, RelTypes: []string{"knows"}},
+		"max depth":   {SourceID: alice.ID, TargetID: acme.ID, RelTypes: []string{"knows", "works_at"}, MaxDepth: 1},
+		"unconnected": {SourceID: alice.ID, TargetTitle: "Carol"},
+	} {
+		paths, err := e.FindPaths(testSessionID, spec)
+		if err != nil || len(paths) != 0 {
+			t.Errorf("%s: got %+v, %v; want no paths", name, paths, err)
+		}
+	}
+
+	for name, spec := range map[string]types.PathSpec{
+		"missing source": {SourceTitle: "Dave", TargetID: acme.ID},
+		"missing target": {SourceID: alice.ID, TargetID: 999},
+		"no target":      {SourceID: alice.ID},
+		"k":              {SourceID: alice.ID, TargetID: acme.ID, K: types.MaxPaths + 1},
+		"depth":          {SourceID: alice.ID, TargetID: acme.ID, MaxDepth: types.MaxPathDepth + 1},
+	} {
+		if _, err := e.FindPaths(testSessionID, spec); err == nil {
+			t.Errorf("%s: FindPaths should fail", name)
+		}
+	}
+}
+
+func TestEngine_Subgraph(t *testing.T) {
+	e := NewEngine(testVectorDim)
+
+	// e -> a -> b -> c, a -> d, b -> d; d is an organization
+	ents := make(map[string]*types.Entity)
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		entType := "person"
+		if name == "d" {
+			entType = "org"
+		}
+		ents[name] = mustAddEntity(t, e, testSessionID, name, name, entType, "", distinctVector(testVectorDim))
+	}
+	for _, r := range [][3]string{{"a", "b", "KNOWS"}, {"b", "c", "KNOWS"}, {"a", "d", "OWNS"}, {"e", "a", "KNOWS"}, {"b", "d", "WORKS_AT"}} {
+		mustAddRelationship(t, e, testSessionID, r[0]+r[1], ents[r[0]].ID, ents[r[1]].ID, r[2], "", 1)
+	}
+	names := func(sub *types.Subgraph) string {
+		var b strings.Builder
+		for _, n := range sub.Nodes {
+			b.WriteString(n.Entity.ExternalID)
+		}
+		return b.String()
+	}
+
+	sub, err := e.Subgraph(testSessionID, types.SubgraphSpec{EntityIDs: []uint64{ents["a"].ID}, Hops: 1})
+	if err != nil {
+		t.Fatalf("Subgraph failed: %v", err)
+	}
+	if len(sub.Nodes) != 4 || sub.Nodes[0].Entity.ID != ents["a"].ID || sub.Nodes[0].Hop != 0 || len(sub.Relationships) != 4 || sub.Truncated {
+		t.Fatalf("1-hop subgraph = %s with %d relationships, want a first, 4 nodes and 4 relationships", names(sub), len(sub.Relationships))
+	}
+	for _, n := range sub.Nodes {
+		want := map[string][3]int{"a": {1, 2, 3}, "b": {1, 2, 2}, "d": {2, 0, 2}, "e": {0, 1, 1}}[n.Entity.ExternalID]
+		if got := [3]int{n.InDegree, n.OutDegree, n.Degree}; got != want {
+			t.Errorf("%s: in/out/subgraph degree = %v, want %v", n.Entity.ExternalID, got, want)
+		}
+	}
+
+	for name, tt := range map[string]struct {
+		spec  types.SubgraphSpec
+		nodes int
+		rels  int
+	}{
+		"outgoing":     {types.SubgraphSpec{Hops: 1, Direction: types.DirectionOut}, 3, 3},
+		"incoming":     {types.SubgraphSpec{Hops: 2, Direction: types.DirectionIn}, 2, 1},
+		"rel types":    {types.SubgraphSpec{Hops: 2, RelTypes: []string{"knows"}}, 4, 3},
+		"entity types": {types.SubgraphSpec{Hops: 1, EntityTypes: []string{"Person"}}, 3, 2},
+		"seeds only":   {types.SubgraphSpec{}, 1, 0},
+	} {
+		tt.spec.EntityIDs = []uint64{ents["a"].ID}
+		sub, err := e.Subgraph(testSessionID, tt.spec)
+		if err != nil {
+			t.Errorf("%s: Subgraph failed: %v", name, err)
+			continue
+		}
+		if len(sub.Nodes) != tt.nodes || len(sub.Relationships) != tt.rels {
+			t.Errorf("%s: got nodes %s and %d relationships, want %d and %d", name, names(sub), len(sub.Relationships), tt.nodes, tt.rels)
+		}
+	}
+
+	// b and d are both one hop out; the cut keeps the lower ID every time
+	for i := 0; i < 20; i++ {
+		sub, err = e.Subgraph(testSessionID, types.SubgraphSpec{EntityIDs: []uint64{ents["a"].ID}, Hops: 2, MaxNodes: 2})
+		if err != nil || names(sub) != "ab" || !sub.Truncated {
+			t.Fatalf("node limit: got %+v, %v; want a and b, truncated", sub, err)
+		}
+	}
+	sub, err = e.Subgraph(testSessionID, types.SubgraphSpec{EntityIDs: []uint64{ents["a"].ID}, Hops: 1, MaxEdges: 1})
+	if err != nil || len(sub.Relationships) != 1 || !sub.Truncated {
+		t.Errorf("edge limit: got %+v, %v; want one relationship, truncated", sub, err)
+	}
+
+	for name, spec := range map[string]types.SubgraphSpec{
+		"no entities":    {},
+		"missing entity": {EntityIDs: []uint64{999}},
+		"hops":           {EntityIDs: []uint64{ents["a"].ID}, Hops: types.MaxSubgraphHops + 1},
+		"direction":      {EntityIDs: []uint64{ents["a"].ID}, Direction: "sideways"},
+		"max nodes":      {EntityIDs: []uint64{ents["a"].ID, ents["b"].ID}, MaxNodes: 1},
+	} {
+		if _, err := e.Subgraph(testSessionID, spec); err == nil {
+			t.Errorf("%s: Subgraph should fail", name)
+		}
+	}
+}
+
+func TestEngine_GraphQuery(t *testing.T) {
+	e := NewEngine(testVectorDim)
+
+	alice := mustAddEntity(t, e, testSessionID, "alice", "Alice", "person", "", distinctVector(testVectorDim))
+	bi := mustAddEntity(t, e, testSessionID, "bi", "Bank Indonesia", "organization", "", distinctVector(testVectorDim))
+	rate := mustAddEntity(t, e, testSessionID, "rate", "Policy Rate", "concept", "", distinctVector(testVectorDim))
+	mustAddRelationship(t, e, testSessionID, "works", alice.ID, bi.ID, "WORKS_AT", "", 1)
+	mustAddRelationship(t, e, testSessionID, "sets", bi.ID, rate.ID, "REGULATES", "", 1)
+
+	result, err := e.GraphQuery(testSessionID,
+		"MATCH (p:person)-[:WORKS_AT]->(o:organization)-[r:REGULATES]->(c:concept) RETURN p.title, o, c LIMIT 5")
+	if err != nil {
+		t.Fatalf("GraphQuery failed: %v", err)
+	}
+	if len(result.Rows) != 1 || result.Rows[0][0].Value != "ALICE" || result.Rows[0][1].Entity.ID != bi.ID || result.Rows[0][2].Entity.ID != rate.ID {
+		t.Fatalf("GraphQuery rows = %+v, want alice, bank indonesia, policy rate", result.Rows)
+	}
+	if len(result.Plan) == 0 || !strings.HasPrefix(result.Plan[0], "NodeByType") {
+		t.Errorf("plan = %v, want a type index scan first", result.Plan)
+	}
+
+	if _, err := e.GraphQuery(testSessionID, "MATCH (p) RETURN q"); err == nil {
+		t.Error("GraphQuery with an undefined variable should fail")
+	}
+	if _, err := e.GraphQuery("no-such-session", "MATCH (p) RETURN p"); err == nil {
+		t.Error("GraphQuery on a missing session should fail")
+	}
+}
+
+func TestEngine_GlobalSearch(t *testing.T) {
+	e := NewEngine(testVectorDim)
+
+	// Each report is 1 title token + 10 content tokens
+	content := strings.Repeat("abcd", 10)
+	mustAddCommunity(t, e, testSessionID, "root", "R0", "root summary", content, 0, []uint64{1, 2, 3}, nil, distinctVector(testVectorDim))
+	vecs := make([][]float32, 3)
+	comms := make([]*types.Community, 3)
+	for i := range comms {
+		vecs[i] = distinctVector(testVectorDim)
+		comms[i] = mustAddCommunity(t, e, testSessionID, "c"+itoa(i), "C"+itoa(i), "s", content, 1, []uint64{uint64(i + 1)}, nil, vecs[i])
+	}
+
+	result, err := e.GlobalSearch(testSessionID, types.GlobalSearchSpec{
+		QueryVector: vecs[2],
+		Level:       1,
+		TokenBudget: 33,
+		BatchTokens: 22,
+	})
+	if err != nil {
+		t.Fatalf("GlobalSearch failed: %v", err)
+	}
+	if result.Level != 1 || result.Candidates != 3 || result.TokensUsed != 33 || result.Skipped != 0 {
+		t.Fatalf("GlobalSearch = level %d, %d candidates, %d tokens, %d skipped; want 1, 3, 33, 0",
+			result.Level, result.Candidates, result.TokensUsed, result.Skipped)
+	}
+	if len(result.Batches) != 2 || len(result.Batches[0].Reports) != 2 || result.Batches[0].Tokens != 22 {
+		t.Fatalf("batches = %+v, want 2 reports then 1", result.Batches)
+	}
+	top := result.Batches[0].Reports[0]
+	if top.Community.ID != comms[2].ID || top.Rank != 1 || math.Abs(float64(top.Score)-1) > 1e-4 {
+		t.Errorf("top report = %s rank %d score %f, want C2 ranked first with score 1", top.Community.Title, top.Rank, top.Score)
+	}
+
+	// The budget skips reports that no longer fit
+	result, err = e.GlobalSearch(testSessionID, types.GlobalSearchSpec{QueryVector: vecs[2], Level: 1, TokenBudget: 30})
+	if err != nil || result.TokensUsed != 22 || result.Skipped != 1 || len(result.Batches) != 1 {
+		t.Errorf("budgeted GlobalSearch = %+v, %v; want 2 reports, 1 skipped", result, err)
+	}
+
+	// Sparse or missing levels fall back to the nearest populated one
+	result, err = e.GlobalSearch(testSessionID, types.GlobalSearchSpec{Level: 0, MinCommunities: 2})
+	if err != nil || result.Level != 1 || result.RequestedLevel != 0 {
+		t.Errorf("sparse level fallback = %+v, %v; want level 1", result, err)
+	}
+	result, err = e.GlobalSearch(testSessionID, types.GlobalSearchSpec{Level: 5})
+	if err != nil || result.Level != 1 {
+		t.Errorf("missing level fallback = %+v, %v; want level 1", result, err)
+	}
+
+	// Without a query vector larger communities rank first
+	result, err = e.GlobalSearch(testSessionID, types.GlobalSearchSpec{Level: 0})
+	if err != nil || result.Level != 0 || result.Batches[0].Reports[0].Community.Title != "R0" {
+		t.Errorf("level 0 GlobalSearch = %+v, %v; want R0", result, err)
+	}
+
+	if _, err := e.GlobalSearch(testSessionID, types.GlobalSearchSpec{QueryVector: []float32{1}}); err == nil {
+		t.Error("GlobalSearch with a wrong vector dimension should fail")
+	}
+	e2 := NewEngine(testVectorDim)
+	mustAddEntity(t, e2, testSessionID, "a", "A", "person", "", distinctVector(testVectorDim))
+	if _, err := e2.GlobalSearch(testSessionID, types.GlobalSearchSpec{}); err == nil {
+		t.Error("GlobalSearch without communities should fail")
+	}
+}
+
+func TestEngine_QueryTokenBudget(t *testing.T) {
+	e := NewEngine(testVectorDim)
+
+	// A chain a -> b -> c; each entity costs 2 tokens, each relationship 3
+	doc := mustAddDocument(t, e, testSessionID, "doc", "a.txt")
+	seedVec := distinctVector(testVectorDim)
+	a := mustAddEntity(t, e, testSessionID, "a", "A", "t", "", seedVec)
+	b := mustAddEntity(t, e, testSessionID, "b", "B", "t", "", distinctVector(testVectorDim))
+	c := mustAddEntity(t, e, testSessionID, "c", "C", "t", "", distinctVector(testVectorDim))
+	ab := mustAddRelationship(t, e, testSessionID, "ab", a.ID, b.ID, "r", "", 1)
+	mustAddRelationship(t, e, testSessionID, "bc", b.ID, c.ID, "r", "", 1)
+	tuA := mustAddTextUnit(t, e, testSessionID, "tu-a", doc.ID, "about a", distinctVector(testVectorDim), 30)
+	tuB := mustAddTextUnit(t, e, testSessionID, "tu-b", doc.ID, "about b", distinctVector(testVectorDim), 20)
+	tuC := mustAddTextUnit(t, e, testSessionID, "tu-c", doc.ID, "about c\nwith a | pipe", distinctVector(testVectorDim), 3)
+	e.LinkTextUnitToEntity(testSessionID, tuA.ID, a.ID)
+	e.LinkTextUnitToEntity(testSessionID, tuB.ID, b.ID)
+	e.LinkTextUnitToEntity(testSessionID, tuC.ID, c.ID)
+
+	spec := types.DefaultQuerySpec()
+	spec.QueryVector = seedVec
+	spec.SearchTypes = []types.SearchType{types.SearchTypeEntity}
+	spec.TopK = 1
+	spec.TokenBudget = 40
+	spec.EntityShare = 4
+	spec.RelationshipShare = 3
+	spec.TextUnitShare = 33
+	spec.ContextFormat = types.ContextFormatCSV
+	result, err := e.Query(testSessionID, spec)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+
+	want := types.TokenUsage{Entities: 4, Relationships: 3, TextUnits: 33, Total: 40}
+	if result.TokenUsage == nil || *result.TokenUsage != want {
+		t.Fatalf("token usage = %+v, want %+v", result.TokenUsage, want)
+	}
+	if len(result.Entities) != 2 || result.Entities[0].Entity.ID != a.ID || result.Entities[1].Entity.ID != b.ID {
+		t.Errorf("entities = %+v, want A and B", result.Entities)
+	}
+	// b -> c is dropped with c; the 20 token text unit no longer fits
+	if len(result.Relationships) != 1 || result.Relationships[0].Relationship.ID != ab.ID {
+		t.Errorf("relationships = %+v, want only a -> b", result.Relationships)
+	}
+	if len(result.TextUnits) != 2 || result.TextUnits[0].TextUnit.ID != tuA.ID || result.TextUnits[1].TextUnit.ID != tuC.ID {
+		t.Errorf("text units = %+v, want tu-a and tu-c", result.TextUnits)
+	}
+
+	for _, part := range []string{
+		"-----Entities-----\nid|entity|type|description\n" + itoa(int(a.ID)) + "|A|t|\n",
+		"-----Relationships-----\nid|source|target|type|description|weight\n" + itoa(int(ab.ID)) + "|A|B|r||1\n",
+		"-----Sources-----\nid|text\n",
+		"|about c with a \\| pipe\n",
+	} {
+		if !strings.Contains(result.Context, part) {
+			t.Errorf("csv context missing %q:\n%s", part, result.Context)
+		}
+	}
+
+	spec.ContextFormat = types.ContextFormatMarkdown
+	result, err = e.Query(testSessionID, spec)
+	if err != nil || !strings.Contains(result.Context, "## Entities\n\n| id | entity | type | description |\n| --- | --- | --- | --- |\n") {
+		t.Errorf("markdown context = %q, %v", result.Context, err)
+	}
+
+	// Without a budget the count limits apply and nothing is rendered
+	spec.TokenBudget = 0
+	spec.ContextFormat = ""
+	result, err = e.Query(testSessionID, spec)
+	if err != nil || result.TokenUsage != nil || result.Context != "" || len(result.Entities) != 3 {
+		t.Errorf("unbudgeted query = %+v, %v; want 3 entities and no context", result, err)
+	}
+
+	for _, bad := range []func(*types.QuerySpec){
+		func(s *types.QuerySpec) { s.TokenBudget = -1 },
+		func(s *types.QuerySpec) { s.TokenBudget = types.MaxContextTokenBudget + 1 },
+		func(s *types.QuerySpec) { s.EntityShare = -1 },
+		func(s *types.QuerySpec) { s.ContextFormat = "html" },
+	} {
+		s := spec
+		bad(&s)
+		if _, err := e.Query(testSessionID, s); err == nil {
+			t.Errorf("Query with %+v should fail", s)
+		}
+	}
+}
+
+func TestEngine_QueryRerank(t *testing.T) {
+	e := NewEngine(testVectorDim)
+	axes := func(weights ...float32) []float32 {
+		v := make([]float32, testVectorDim)
+		copy(v, weights)
+		return v
+	}
+
+	// tu2 nearly duplicates tu1; tu3 and tu4 are less relevant but diverse
+	docA := mustAddDocument(t, e, testSessionID, "doc-a", "a.txt")
+	docB := mustAddDocument(t, e, testSessionID, "doc-b", "b.txt")
+	tu1 := mustAddTextUnit(t, e, testSessionID, "tu1", docA.ID, "one", axes(1), 1)
+	tu2 := mustAddTextUnit(t, e, testSessionID, "tu2", docA.ID, "one again", axes(1, 0.05), 1)
+	tu3 := mustAddTextUnit(t, e, testSessionID, "tu3", docB.ID, "three", axes(0.6, 0, 0.8), 1)
+	tu4 := mustAddTextUnit(t, e, testSessionID, "tu4", docB.ID, "four", axes(0.5, 0, 0, 0.866), 1)
+
+	query := func(mutate func(*types.QuerySpec)) ([]uint64, []types.Demotion) {
+		t.Helper()
+		spec := types.DefaultQuerySpec()
+		spec.QueryVector = axes(1)
+		spec.SearchTypes = []types.SearchType{types.SearchTypeTextUnit}
+		spec.MaxTextUnits = 3
+		mutate(&spec)
+		result, err := e.Query(testSessionID, spec)
+		if err != nil {
+			t.Fatalf("Query failed: %v", err)
+		}
+		ids := make([]uint64, len(result.TextUnits))
+		for i, tr := range result.TextUnits {
+			ids[i] = tr.TextUnit.ID
+		}
+		explain, _ := e.Explain(result.QueryID)
+		return ids, explain.Demotions
+	}
+
+	ids, demotions := query(func(*types.QuerySpec) {})
+	if !reflect.DeepEqual(ids, []uint64{tu1.ID, tu2.ID, tu3.ID}) || len(demotions) != 0 {
+		t.Errorf("score order = %v, %+v; want tu1, tu2, tu3 and no demotions", ids, demotions)
+	}
+
+	ids, demotions = query(func(spec *types.QuerySpec) { spec.DedupThreshold = 0.95 })
+	if !reflect.DeepEqual(ids, []uint64{tu1.ID, tu3.ID, tu4.ID}) {
+		t.Errorf("dedup order = %v, want tu1, tu3, tu4", ids)
+	}
+	if len(demotions) != 1 || demotions[0].ID != tu2.ID || demotions[0].Reason != types.DemotedDuplicate ||
+		demotions[0].SimilarTo != tu1.ID || demotions[0].Similarity < 0.95 || demotions[0].NewRank != 0 {
+		t.Errorf("dedup demotions = %+v, want tu2 dropped as a duplicate of tu1", demotions)
+	}
+
+	ids, demotions = query(func(spec *types.QuerySpec) { spec.MaxPerDocument = 1 })
+	if !reflect.DeepEqual(ids, []uint64{tu1.ID, tu3.ID}) || len(demotions) != 2 {
+		t.Errorf("capped order = %v, %+v; want tu1, tu3 with two demotions", ids, demotions)
+	}
+	for _, d := range demotions {
+		if d.Reason != types.DemotedDocumentCap {
+			t.Errorf("capped demotion = %+v, want document_cap", d)
+		}
+	}
+
+	// MMR ranks the diverse text units ahead of the near duplicate
+	ids, demotions = query(func(spec *types.QuerySpec) {
+		spec.Rerank = types.RerankMMR
+		spec.MMRLambda = 0.3
+		spec.MaxTextUnits = 4
+	})
+	if !reflect.DeepEqual(ids, []uint64{tu1.ID, tu4.ID, tu3.ID, tu2.ID}) {
+		t.Errorf("mmr order = %v, want tu1, tu4, tu3, tu2", ids)
+	}
+	if len(demotions) != 1 || demotions[0].ID != tu2.ID || demotions[0].Reason != types.DemotedMMR ||
+		demotions[0].Rank != 2 || demotions[0].NewRank != 4 || demotions[0].SimilarTo != tu1.ID {
+		t.Errorf("mmr demotions = %+v, want tu2 moved from 2 to 4", demotions)
+	}
+	_, demotions = query(func(spec *types.QuerySpec) {
+		spec.Rerank = types.RerankMMR
+		spec.MMRLambda = 0.3
+	})
+	if len(demotions) != 1 || demotions[0].ID != tu2.ID || demotions[0].NewRank != 0 {
+		t.Errorf("mmr demotions = %+v, want tu2 pushed out", demotions)
+	}
+
+	for _, bad := range []func(*types.QuerySpec){
+		func(s *types.QuerySpec) { s.Rerank = "random" },
+		func(s *types.QuerySpec) { s.MMRLambda = 1.5 },
+		func(s *types.QuerySpec) { s.DedupThreshold = -0.1 },
+		func(s *types.QuerySpec) { s.MaxPerDocument = -1 },
+	} {
+		spec := types.DefaultQuerySpec()
+		spec.QueryVector = axes(1)
+		bad(&spec)
+		if _, err := e.Query(testSessionID, spec); err == nil {
+			t.Errorf("Query with %+v should fail", spec)
+		}
+	}
+}
+
+func TestEngine_QueryCache(t *testing.T) {
+	e := NewEngine(testVectorDim)
+	seedVec := distinctVector(testVectorDim)
+	a := mustAddEntity(t, e, testSessionID, "a", "A", "t", "", seedVec)
+
+	if err := e.SetQueryCache(testSessionID, 10, 0); err != nil {
+		t.Fatalf("SetQueryCache failed: %v", err)
+	}
+	spec := types.DefaultQuerySpec()
+	spec.QueryVector = seedVec
+	spec.SearchTypes = []types.SearchType{types.SearchTypeEntity}
+
+	first, err := e.Query(testSessionID, spec)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if first.Stats.CacheHit {
+		t.Error("first query should miss the cache")
+	}
+	second, err := e.Query(testSessionID, spec)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if !second.Stats.CacheHit {
+		t.Error("repeated query should hit the cache")
+	}
+	if second.QueryID == first.QueryID {
+		t.Error("cache hit should get its own query ID")
+	}
+	if !reflect.DeepEqual(second.Entities, first.Entities) || len(second.Entities) != 1 || second.Entities[0].Entity.ID != a.ID {
+		t.Errorf("cached entities = %+v, want %+v", second.Entities, first.Entities)
+	}
+	if _, ok := e.Explain(second.QueryID); !ok {
+		t.Error("cache hit should be explainable")
+	}
+
+	spec.NoCache = true
+	if result, err := e.Query(testSessionID, spec); err != nil || result.Stats.CacheHit {
+		t.Errorf("no_cache query hit the cache (err %v)", err)
+	}
+	spec.NoCache = false
+
+	// Any write invalidates the cached results
+	mustAddEntity(t, e, testSessionID, "b", "B", "t", "", distinctVector(testVectorDim))
+	third, err := e.Query(testSessionID, spec)
+	if err != nil || third.Stats.CacheHit {
+		t.Errorf("query after a write hit the cache (err %v)", err)
+	}
+
+	info, err := e.GetSessionInfo(testSessionID)
+	if err != nil {
+		t.Fatalf("GetSessionInfo failed: %v", err)
+	}
+	if info.QueryCacheSize != 10 || info.QueryCacheHits != 1 || info.QueryCacheMisses != 2 {
+		t.Errorf("cache info = size %d, hits %d, misses %d; want 10, 1, 2",
+			info.QueryCacheSize, info.QueryCacheHits, info.QueryCacheMisses)
+	}
+
+	if err := e.SetQueryCache(testSessionID, -1, 0); err == nil {
+		t.Error("expected error for a negative cache size")
+	}
+	if err := e.SetQueryCache(testSessionID, 10, -time.Second); err == nil {
+		t.Error("expected error for a negative ttl")
+	}
+	if err := e.SetQueryCache(testSessionID, 0, 0); err != nil {
+		t.Fatalf("disabling the cache failed: %v", err)
+	}
+	if result, err := e.Query(testSessionID, spec); err != nil || result.Stats.CacheHit {
+		t.Errorf("query with the cache off hit the cache (err %v)", err)
+	}
+}
+
+func TestEngine_QueryBatch(t *testing.T) {
+	e := NewEngine(testVectorDim)
+	vecs := make([][]float32, 3)
+	ents := make([]*types.Entity, 3)
+	for i := range vecs {
+		vecs[i] = distinctVector(testVectorDim)
+		ents[i] = mustAddEntity(t, e, testSessionID, "e"+itoa(i), "E"+itoa(i), "t", "", vecs[i])
+	}
+
+	specs := make([]types.QuerySpec, 4)
+	for i := range specs {
+		specs[i] = types.DefaultQuerySpec()
+		specs[i].SearchTypes = []types.SearchType{types.SearchTypeEntity}
+		specs[i].TopK = 1
+		specs[i].KHops = 0
+		specs[i].QueryVector = vecs[i%len(vecs)]
+	}
+	specs[3].Rerank = "shuffle"
+
+	results, err := e.QueryBatch(testSessionID, specs, 2)
+	if err != nil {
+		t.Fatalf("QueryBatch failed: %v", err)
+	}
+	if len(results) != len(specs) {
+		t.Fatalf("QueryBatch returned %d results, want %d", len(results), len(specs))
+	}
+	seen := make(map[uint64]bool)
+	for i, r := range results[:3] {
+		if r.Error != "" || r.Pack == nil {
+			t.Fatalf("query %d failed: %s", i, r.Error)
+		}
+		if len(r.Pack.Entities) == 0 || r.Pack.Entities[0].Entity.ID != ents[i].ID {
+			t.Errorf("query %d: top entity = %+v, want %s", i, r.Pack.Entities, ents[i].ExternalID)
+		}
+		if seen[r.Pack.QueryID] {
+			t.Errorf("query %d reuses query ID %d", i, r.Pack.QueryID)
+		}
+		seen[r.Pack.QueryID] = true
+		if _, ok := e.Explain(r.Pack.QueryID); !ok {
+			t.Errorf("query %d: Explain(%d) not found", i, r.Pack.QueryID)
+		}
+	}
+	if results[3].Pack != nil || !strings.Contains(results[3].Error, "rerank") {
+		t.Errorf("invalid query result = %+v, want a rerank error", results[3])
+	}
+
+	// Parallelism 0 picks a worker count; a single query runs alone
+	if results, err := e.QueryBatch(testSessionID, specs[:1], 0); err != nil || len(results) != 1 || results[0].Pack == nil {
+		t.Errorf("QueryBatch(parallelism 0) = %+v, %v", results, err)
+	}
+
+	tooMany := make([]types.QuerySpec, types.MaxBatchQueries+1)
+	for _, tc := range []struct {
+		name        string
+		session     string
+		specs       []types.QuerySpec
+		parallelism int
+	}{
+		{"empty", testSessionID, nil, 0},
+		{"too many", testSessionID, tooMany, 0},
+		{"negative parallelism", testSessionID, specs, -1},
+		{"parallelism over max", testSessionID, specs, types.MaxBatchParallelism + 1},
+		{"unknown session", "missing", specs, 0},
+	} {
+		if _, err := e.QueryBatch(tc.session, tc.specs, tc.parallelism); err == nil {
+			t.Errorf("%s: expected error", tc.name)
+		}
+	}
+}
+
 func generateSemanticVector(text string) []float32 {
 	v := make([]float32, testVectorDim)
 	// Use text hash to create deterministic but varied vectors
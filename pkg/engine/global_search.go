@@ -0,0 +1,181 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/gibram-io/gibram/pkg/store"
+	"github.com/gibram-io/gibram/pkg/types"
+	"github.com/gibram-io/gibram/pkg/vector"
+)
+
+// =============================================================================
+// Global Search - Map-Reduce over Community Reports
+// =============================================================================
+
+// GlobalSearch ranks every community of a level by similarity to the
+// query vector, selects reports by rank until the token budget is spent
+// and groups them into map batches of at most spec.BatchTokens. Reports
+// too large for the remaining budget are skipped so smaller ones may
+// still fit. When the level has fewer than spec.MinCommunities
+// communities the nearest level that has enough is searched instead.
+func (e *Engine) GlobalSearch(sessionID string, spec types.GlobalSearchSpec) (*types.GlobalSearchResult, error) {
+	sess, err := e.getSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateGlobalSearchSpec(&spec, sess.VectorDim()); err != nil {
+		return nil, err
+	}
+
+	level, ok := fallbackLevel(sess.CommunityLevelCounts(), spec.Level, spec.MinCommunities)
+	if !ok {
+		return nil, fmt.Errorf("session has no communities; compute communities first")
+	}
+	reports := rankCommunities(sess, sess.GetCommunitiesByLevel(level), spec)
+
+	result := &types.GlobalSearchResult{
+		Level:          level,
+		RequestedLevel: spec.Level,
+		Candidates:     len(reports),
+		Batches:        []types.ReportBatch{},
+	}
+	var batch types.ReportBatch
+	for _, r := range reports {
+		if result.TokensUsed+r.Tokens > spec.TokenBudget {
+			result.Skipped++
+			continue
+		}
+		if len(batch.Reports) > 0 && batch.Tokens+r.Tokens > spec.BatchTokens {
+			result.Batches = append(result.Batches, batch)
+			batch = types.ReportBatch{}
+		}
+		batch.Reports = append(batch.Reports, r)
+		batch.Tokens += r.Tokens
+		result.TokensUsed += r.Tokens
+	}
+	if len(batch.Reports) > 0 {
+		result.Batches = append(result.Batches, batch)
+	}
+	return result, nil
+}
+
+// validateGlobalSearchSpec checks spec and fills in defaults
+func validateGlobalSearchSpec(spec *types.GlobalSearchSpec, dim int) error {
+	if len(spec.QueryVector) > 0 && len(spec.QueryVector) != dim {
+		return fmt.Errorf("query vector has dimension %d, want %d", len(spec.QueryVector), dim)
+	}
+	if spec.Level < 0 {
+		return fmt.Errorf("level must not be negative, got %d", spec.Level)
+	}
+	if spec.MinCommunities < 0 {
+		return fmt.Errorf("min_communities must not be negative, got %d", spec.MinCommunities)
+	}
+	if spec.TokenBudget < 0 || spec.TokenBudget > types.MaxGlobalTokenBudget {
+		return fmt.Errorf("token budget must be between 0 and %d, got %d", types.MaxGlobalTokenBudget, spec.TokenBudget)
+	}
+	if spec.BatchTokens < 0 {
+		return fmt.Errorf("batch tokens must not be negative, got %d", spec.BatchTokens)
+	}
+	if spec.MinCommunities == 0 {
+		spec.MinCommunities = 1
+	}
+	if spec.TokenBudget == 0 {
+		spec.TokenBudget = types.DefaultGlobalTokenBudget
+	}
+	if spec.BatchTokens == 0 {
+		spec.BatchTokens = types.DefaultGlobalBatchTokens
+	}
+	return nil
+}
+
+// fallbackLevel returns the level closest to want, lower first on ties,
+// with at least minCount communities, or the most populated level when
+// none has that many
+func fallbackLevel(counts map[int]int, want, minCount int) (int, bool) {
+	levels := make([]int, 0, len(counts))
+	for level, n := range counts {
+		if n > 0 {
+			levels = append(levels, level)
+		}
+	}
+	if len(levels) == 0 {
+		return 0, false
+	}
+	sort.Slice(levels, func(i, j int) bool {
+		di, dj := abs(levels[i]-want), abs(levels[j]-want)
+		if di != dj {
+			return di < dj
+		}
+		return levels[i] < levels[j]
+	})
+
+	best := levels[0]
+	for _, level := range levels {
+		if counts[level] >= minCount {
+			return level, true
+		}
+		if counts[level] > counts[best] {
+			best = level
+		}
+	}
+	return best, true
+}
+
+// rankCommunities scores communities by exact similarity to the query
+// vector, best first. Communities without an embedding score 0; ties,
+// and every community when there is no query vector, rank larger
+// communities first.
+func rankCommunities(sess *store.SessionStore, comms []*types.Community, spec types.GlobalSearchSpec) []types.CommunityReport {
+	scores := make(map[uint64]float32, len(comms))
+	if len(spec.QueryVector) > 0 && len(comms) > 0 {
+		inLevel := make(map[uint64]bool, len(comms))
+		for _, comm := range comms {
+			inLevel[comm.ID] = true
+		}
+		// A range search without a real floor returns every filtered
+		// vector; with ef covering the index it is a single exact scan
+		floor := float32(-math.MaxFloat32)
+		idx := sess.GetCommunityIndex()
+		hits := idx.SearchWithParams(spec.QueryVector, vector.SearchParams{
+			Ef:            idx.Count(),
+			MinSimilarity: &floor,
+			Range:         true,
+			Filter:        func(id uint64) bool { return inLevel[id] },
+		})
+		for _, h := range hits {
+			scores[h.ID] = h.Similarity
+		}
+	}
+
+	reports := make([]types.CommunityReport, len(comms))
+	for i, comm := range comms {
+		reports[i] = types.CommunityReport{
+			Community: comm,
+			Score:     scores[comm.ID],
+			Tokens:    types.EstimateTokens(comm.Title) + types.EstimateTokens(reportText(comm, spec.UseSummary)),
+		}
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		a, b := reports[i], reports[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if len(a.Community.EntityIDs) != len(b.Community.EntityIDs) {
+			return len(a.Community.EntityIDs) > len(b.Community.EntityIDs)
+		}
+		return a.Community.ID < b.Community.ID
+	})
+	for i := range reports {
+		reports[i].Rank = i + 1
+	}
+	return reports
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,24 @@
+package engine
+
+import (
+	"github.com/gibram-io/gibram/pkg/pattern"
+	"github.com/gibram-io/gibram/pkg/types"
+)
+
+// =============================================================================
+// Graph Pattern Query
+// =============================================================================
+
+// GraphQuery runs a Cypher-like pattern query, such as
+//
+//	MATCH (p:person)-[:WORKS_AT]->(o)-[:REGULATES]->(c:concept)
+//	WHERE o.attrs.sector = "bank" RETURN p, c.title LIMIT 10
+//
+// over the entities and relationships of a session
+func (e *Engine) GraphQuery(sessionID, query string) (*types.GraphQueryResult, error) {
+	sess, err := e.getSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return pattern.Run(sess, query)
+}
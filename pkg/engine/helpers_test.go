@@ -1,11 +1,22 @@
 package engine
 
 import (
+	"math/rand"
 	"testing"
 
 	"github.com/gibram-io/gibram/pkg/types"
 )
 
+// distinctVector returns a pseudo-random vector. randomVector returns the
+// same vector every time, which is useless for ranking.
+func distinctVector(dim int) []float32 {
+	v := make([]float32, dim)
+	for i := range v {
+		v[i] = rand.Float32()*2 - 1
+	}
+	return v
+}
+
 func mustAddDocument(tb testing.TB, e *Engine, sessionID, extID, filename string) *types.Document {
 	tb.Helper()
 	doc, err := e.AddDocument(sessionID, extID, filename)
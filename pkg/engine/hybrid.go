@@ -0,0 +1,113 @@
+package engine
+
+import (
+	"sort"
+
+	"github.com/gibram-io/gibram/pkg/lexical"
+	"github.com/gibram-io/gibram/pkg/types"
+	"github.com/gibram-io/gibram/pkg/vector"
+)
+
+// =============================================================================
+// Hybrid Lexical + Vector Seeds
+// =============================================================================
+
+// rrfK dampens the contribution of top ranks in reciprocal rank fusion
+const rrfK = 60
+
+// seedHit is a first-phase hit on one index, before graph expansion
+type seedHit struct {
+	ID         uint64
+	Score      float32 // ranking score, fused for hybrid queries
+	Similarity float32 // vector similarity (0 = lexical only)
+	Lexical    float32 // BM25 score (0 = vector only)
+}
+
+// searchSeeds runs vector search and, when spec.QueryText is set, BM25
+// search on one object type and fuses the two rankings. Seed scores stay
+// in (0, 1] so they remain comparable with graph expansion scores.
+func searchSeeds(idx vector.Index, lex *lexical.Index, spec types.QuerySpec, filter vector.Filter) []seedHit {
+	var vecHits []vector.SearchResult
+	if idx != nil && len(spec.QueryVector) > 0 {
+		vecHits = idx.SearchFiltered(spec.QueryVector, spec.TopK, filter)
+	}
+
+	var metric vector.Metric
+	if idx != nil {
+		metric = idx.Metric()
+	}
+
+	if spec.QueryText == "" || lex == nil {
+		hits := make([]seedHit, len(vecHits))
+		for i, r := range vecHits {
+			hits[i] = seedHit{ID: r.ID, Score: metric.Score(r.Similarity), Similarity: r.Similarity}
+		}
+		return hits
+	}
+
+	lexHits := lex.Search(spec.QueryText, spec.TopK, filter)
+
+	byID := make(map[uint64]*seedHit, len(vecHits)+len(lexHits))
+	hit := func(id uint64) *seedHit {
+		h := byID[id]
+		if h == nil {
+			h = &seedHit{ID: id}
+			byID[id] = h
+		}
+		return h
+	}
+
+	if spec.Fusion == types.FusionWeighted {
+		w := spec.LexicalWeight
+		if w <= 0 || w > 1 {
+			w = types.DefaultLexicalWeight
+		}
+		for _, r := range vecHits {
+			h := hit(r.ID)
+			h.Similarity = r.Similarity
+			h.Score += (1 - w) * metric.Score(r.Similarity)
+		}
+		if len(lexHits) > 0 {
+			top := lexHits[0].Score // BM25 is unbounded; normalize by the best hit
+			for _, r := range lexHits {
+				h := hit(r.ID)
+				h.Lexical = r.Score
+				h.Score += w * r.Score / top
+			}
+		}
+	} else {
+		for rank, r := range vecHits {
+			h := hit(r.ID)
+			h.Similarity = r.Similarity
+			h.Score += 1 / float32(rrfK+rank+1)
+		}
+		for rank, r := range lexHits {
+			h := hit(r.ID)
+			h.Lexical = r.Score
+			h.Score += 1 / float32(rrfK+rank+1)
+		}
+	}
+
+	hits := make([]seedHit, 0, len(byID))
+	for _, h := range byID {
+		hits = append(hits, *h)
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID < hits[j].ID
+	})
+	if len(hits) > spec.TopK {
+		hits = hits[:spec.TopK]
+	}
+
+	// RRF scores are tiny; rescale so the best seed scores 1
+	if spec.Fusion != types.FusionWeighted && len(hits) > 0 {
+		top := hits[0].Score
+		for i := range hits {
+			hits[i].Score /= top
+		}
+	}
+	return hits
+}
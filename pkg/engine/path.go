@@ -0,0 +1,97 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gibram-io/gibram/pkg/graph"
+	"github.com/gibram-io/gibram/pkg/store"
+	"github.com/gibram-io/gibram/pkg/types"
+)
+
+// =============================================================================
+// Path - Shortest Paths between Entities
+// =============================================================================
+
+// FindPaths returns up to spec.K shortest loopless paths from the source to
+// the target entity, cheapest first, with the entities and relationships
+// along each path. No path within the depth limit is not an error.
+func (e *Engine) FindPaths(sessionID string, spec types.PathSpec) ([]types.EntityPath, error) {
+	sess, err := e.getSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if spec.K < 0 || spec.K > types.MaxPaths {
+		return nil, fmt.Errorf("k must be between 0 and %d, got %d", types.MaxPaths, spec.K)
+	}
+	if spec.MaxDepth < 0 || spec.MaxDepth > types.MaxPathDepth {
+		return nil, fmt.Errorf("max_depth must be between 0 and %d, got %d", types.MaxPathDepth, spec.MaxDepth)
+	}
+	source, err := resolveEntity(sess, "source", spec.SourceID, spec.SourceTitle)
+	if err != nil {
+		return nil, err
+	}
+	target, err := resolveEntity(sess, "target", spec.TargetID, spec.TargetTitle)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := graph.PathOptions{
+		K:        spec.K,
+		MaxDepth: spec.MaxDepth,
+		Directed: spec.Directed,
+		Weighted: spec.Weighted,
+	}
+	if opts.MaxDepth == 0 {
+		opts.MaxDepth = types.DefaultPathDepth
+	}
+	if len(spec.RelTypes) > 0 {
+		allowed := make(map[string]bool, len(spec.RelTypes))
+		for _, t := range spec.RelTypes {
+			allowed[strings.ToUpper(t)] = true
+		}
+		opts.Allow = func(rel *types.Relationship) bool {
+			return allowed[strings.ToUpper(rel.Type)]
+		}
+	}
+
+	found := graph.ShortestPaths(source.ID, target.ID, &sessionRelAdapter{sess: sess}, opts)
+	paths := make([]types.EntityPath, 0, len(found))
+	for _, p := range found {
+		path := types.EntityPath{
+			Entities:      make([]*types.Entity, 0, len(p.EntityIDs)),
+			Relationships: p.Relationships,
+			Cost:          p.Cost,
+		}
+		for _, id := range p.EntityIDs {
+			ent, ok := sess.GetEntity(id)
+			if !ok {
+				// Deleted while searching; the path no longer exists
+				path.Entities = nil
+				break
+			}
+			path.Entities = append(path.Entities, ent)
+		}
+		if path.Entities != nil {
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
+
+// resolveEntity looks up an entity by ID or, when id is 0, by title
+func resolveEntity(sess *store.SessionStore, role string, id uint64, title string) (*types.Entity, error) {
+	if id != 0 {
+		if ent, ok := sess.GetEntity(id); ok {
+			return ent, nil
+		}
+		return nil, fmt.Errorf("%s entity %d not found", role, id)
+	}
+	if title == "" {
+		return nil, fmt.Errorf("%s entity ID or title is required", role)
+	}
+	if ent, ok := sess.GetEntityByTitle(title); ok {
+		return ent, nil
+	}
+	return nil, fmt.Errorf("%s entity %q not found", role, title)
+}
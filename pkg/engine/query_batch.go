@@ -0,0 +1,62 @@
+package engine
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/gibram-io/gibram/pkg/types"
+)
+
+// =============================================================================
+// Query Batch - Many Queries of One Session, Run Concurrently
+// =============================================================================
+
+// QueryBatch runs specs on one session with up to parallelism queries at
+// once (0 = one per CPU). The session is looked up once for the batch.
+// Results are in spec order; a query that fails records its error and
+// does not stop the others. Each ContextPack has its own query ID.
+func (e *Engine) QueryBatch(sessionID string, specs []types.QuerySpec, parallelism int) ([]types.BatchQueryResult, error) {
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("at least one query is required")
+	}
+	if len(specs) > types.MaxBatchQueries {
+		return nil, fmt.Errorf("too many queries: %d (max %d)", len(specs), types.MaxBatchQueries)
+	}
+	if parallelism < 0 || parallelism > types.MaxBatchParallelism {
+		return nil, fmt.Errorf("parallelism must be between 0 and %d, got %d", types.MaxBatchParallelism, parallelism)
+	}
+	sess, err := e.getSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if parallelism == 0 {
+		parallelism = min(runtime.GOMAXPROCS(0), types.MaxBatchParallelism)
+	}
+	parallelism = min(parallelism, len(specs))
+
+	results := make([]types.BatchQueryResult, len(specs))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < parallelism; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				pack, err := e.runQuery(sess, sessionID, specs[i])
+				if err != nil {
+					results[i].Error = err.Error()
+					continue
+				}
+				results[i].Pack = pack
+			}
+		}()
+	}
+	for i := range specs {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return results, nil
+}
@@ -0,0 +1,107 @@
+package engine
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/gibram-io/gibram/pkg/store"
+	"github.com/gibram-io/gibram/pkg/types"
+)
+
+// =============================================================================
+// Query Cache - Per-Session Result Cache
+// =============================================================================
+
+// MaxQueryCacheEntries bounds the query cache of one session
+const MaxQueryCacheEntries = 100000
+
+// cachedResult is a query result kept in a session query cache with the
+// log that explains it
+type cachedResult struct {
+	pack *types.ContextPack
+	log  *queryLog
+}
+
+// SetQueryCache turns on the query result cache of a session, holding at
+// most maxEntries results for at most ttl (0 = no expiry), or turns it off
+// when maxEntries is 0. Reconfiguring empties the cache.
+func (e *Engine) SetQueryCache(sessionID string, maxEntries int, ttl time.Duration) error {
+	if maxEntries < 0 || maxEntries > MaxQueryCacheEntries {
+		return fmt.Errorf("query cache size must be between 0 and %d, got %d", MaxQueryCacheEntries, maxEntries)
+	}
+	if ttl < 0 {
+		return fmt.Errorf("query cache ttl must not be negative, got %s", ttl)
+	}
+	sess, err := e.getSession(sessionID)
+	if err != nil {
+		return err
+	}
+	sess.SetQueryCache(maxEntries, ttl)
+	return nil
+}
+
+// cachedQuery serves spec from cache while the session is at the epoch
+// the result was computed at, and otherwise runs and caches it. A hit
+// gets a fresh query ID that explains like the cached result.
+func (e *Engine) cachedQuery(sess *store.SessionStore, cache *store.QueryCache, sessionID string, spec types.QuerySpec) (*types.ContextPack, error) {
+	startTime := time.Now()
+	key, err := queryCacheKey(spec)
+	if err != nil {
+		return e.query(sess, sessionID, spec)
+	}
+
+	epoch := sess.Epoch()
+	if v, ok := cache.Get(key, epoch); ok {
+		hit := v.(*cachedResult)
+		queryID := atomic.AddUint64(&e.queryIDGen, 1)
+		e.queryLogs.Set(queryID, hit.log)
+
+		pack := copyContextPack(hit.pack)
+		pack.QueryID = queryID
+		pack.Stats.CacheHit = true
+		pack.Stats.DurationMicros = time.Since(startTime).Microseconds()
+		return pack, nil
+	}
+
+	pack, err := e.query(sess, sessionID, spec)
+	if err != nil {
+		return nil, err
+	}
+	// A write during the query leaves the result at no single epoch
+	if sess.Epoch() == epoch {
+		if qlog, ok := e.queryLogs.Get(pack.QueryID); ok {
+			cache.Put(key, epoch, &cachedResult{pack: copyContextPack(pack), log: qlog})
+		}
+	}
+	return pack, nil
+}
+
+// queryCacheKey hashes every field of spec that shapes the result,
+// including the query vector
+func queryCacheKey(spec types.QuerySpec) (string, error) {
+	spec.NoCache = false
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return string(sum[:]), nil
+}
+
+// copyContextPack copies the result lists of pack so the cached pack and
+// the ones handed out do not share them
+func copyContextPack(pack *types.ContextPack) *types.ContextPack {
+	c := *pack
+	c.TextUnits = append(make([]types.TextUnitResult, 0, len(pack.TextUnits)), pack.TextUnits...)
+	c.Entities = append(make([]types.EntityResult, 0, len(pack.Entities)), pack.Entities...)
+	c.Communities = append(make([]types.CommunityResult, 0, len(pack.Communities)), pack.Communities...)
+	c.Relationships = append(make([]types.RelationshipResult, 0, len(pack.Relationships)), pack.Relationships...)
+	if pack.TokenUsage != nil {
+		usage := *pack.TokenUsage
+		c.TokenUsage = &usage
+	}
+	return &c
+}
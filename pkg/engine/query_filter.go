@@ -0,0 +1,98 @@
+package engine
+
+import (
+	"strings"
+
+	"github.com/gibram-io/gibram/pkg/store"
+	"github.com/gibram-io/gibram/pkg/types"
+	"github.com/gibram-io/gibram/pkg/vector"
+)
+
+// =============================================================================
+// Query Filters
+// =============================================================================
+
+// queryFilter evaluates the filters of a QuerySpec or SearchSpec against
+// session objects.
+// It is passed to filtered vector search and re-checked for results found
+// by graph expansion.
+type queryFilter struct {
+	sess        *store.SessionStore
+	entityTypes map[string]bool   // lowercased; nil = any type
+	documentIDs map[uint64]bool   // nil = any document
+	attrs       map[string]string // all must match; nil = no constraint
+}
+
+func newQueryFilter(sess *store.SessionStore, entityTypes []string, documentIDs []uint64, attrs map[string]string) *queryFilter {
+	f := &queryFilter{sess: sess}
+	if len(entityTypes) > 0 {
+		f.entityTypes = make(map[string]bool, len(entityTypes))
+		for _, t := range entityTypes {
+			f.entityTypes[strings.ToLower(t)] = true
+		}
+	}
+	if len(documentIDs) > 0 {
+		f.documentIDs = make(map[uint64]bool, len(documentIDs))
+		for _, id := range documentIDs {
+			f.documentIDs[id] = true
+		}
+	}
+	if len(attrs) > 0 {
+		f.attrs = attrs
+	}
+	return f
+}
+
+// filtersEntities reports whether any entity filter is set
+func (f *queryFilter) filtersEntities() bool {
+	return f.entityTypes != nil || f.attrs != nil
+}
+
+// matchEntity reports whether an entity passes the type and attribute filters
+func (f *queryFilter) matchEntity(ent *types.Entity) bool {
+	if f.entityTypes != nil && !f.entityTypes[strings.ToLower(ent.Type)] {
+		return false
+	}
+	for k, v := range f.attrs {
+		if ent.Attrs[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// matchTextUnit reports whether a text unit passes the document scope
+func (f *queryFilter) matchTextUnit(tu *types.TextUnit) bool {
+	return f.documentIDs == nil || f.documentIDs[tu.DocumentID]
+}
+
+// entityFilter resolves the entity filters to the IDs they admit and
+// returns a vector filter over that set (nil = none). The set is taken
+// under one session read lock before the search starts: a filter that
+// called the store from inside an index search would take the session
+// lock while holding the index lock, the reverse of the order writes use.
+func (f *queryFilter) entityFilter() vector.Filter {
+	if !f.filtersEntities() {
+		return nil
+	}
+	entTypes := make([]string, 0, len(f.entityTypes))
+	for t := range f.entityTypes {
+		entTypes = append(entTypes, t)
+	}
+	allowed := f.sess.EntityIDsWhere(entTypes, f.matchEntity)
+	return func(id uint64) bool { return allowed[id] }
+}
+
+// textUnitFilter resolves the document scope to the text unit IDs it
+// admits and returns a vector filter over that set (nil = none)
+func (f *queryFilter) textUnitFilter() vector.Filter {
+	if f.documentIDs == nil {
+		return nil
+	}
+	docIDs := make([]uint64, 0, len(f.documentIDs))
+	for id := range f.documentIDs {
+		docIDs = append(docIDs, id)
+	}
+	allowed := f.sess.TextUnitIDsInDocuments(docIDs)
+	return func(id uint64) bool { return allowed[id] }
+}
@@ -0,0 +1,273 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gibram-io/gibram/pkg/store"
+	"github.com/gibram-io/gibram/pkg/vector"
+)
+
+// =============================================================================
+// Online Index Rebuild
+// =============================================================================
+
+// MaxRebuildTasks bounds the finished rebuild tasks kept for status queries
+const MaxRebuildTasks = 64
+
+var ErrRebuildInProgress = errors.New("index rebuild already in progress for session")
+
+// RebuildTask tracks an online rebuild or a bulk load build of a session's
+// vector indices
+type RebuildTask struct {
+	ID        string
+	SessionID string
+	Status    TaskStatus
+	Index     string  // index being built (last one when finished)
+	Progress  float64 // 0.0 to 1.0 across all indices
+	StartTime time.Time
+	EndTime   time.Time
+	Error     error
+
+	done chan struct{} // closed when the task finishes
+}
+
+// rebuildTasks is the registry of rebuild tasks
+type rebuildTasks struct {
+	mu    sync.RWMutex
+	tasks map[string]*RebuildTask
+	seq   uint64
+}
+
+// RebuildVectorIndices rebuilds all vector indices for a session with
+// their current configuration. Searches and writes continue during the
+// rebuild; it returns once the rebuilt indices are swapped in.
+func (e *Engine) RebuildVectorIndices(sessionID string) error {
+	sess, err := e.getSession(sessionID)
+	if err != nil {
+		return err
+	}
+	task, err := e.newRebuildTask(sessionID, "rebuild")
+	if err != nil {
+		return err
+	}
+	e.runRebuild(task, sess, sess.Options())
+	e.rebuilds.mu.RLock()
+	defer e.rebuilds.mu.RUnlock()
+	return task.Error
+}
+
+// StartIndexRebuild rebuilds a session's vector indices in the background
+// with opts, which may change HNSW parameters, metrics and quantization but
+// not the vector dimension. Progress is reported by RebuildStatus.
+func (e *Engine) StartIndexRebuild(sessionID string, opts store.SessionOptions) (*RebuildTask, error) {
+	sess, err := e.getSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	dim := sess.VectorDim()
+	if opts.VectorDim != 0 && opts.VectorDim != dim {
+		return nil, fmt.Errorf("vector_dim cannot change on rebuild (session has %d)", dim)
+	}
+	opts.VectorDim = dim
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	task, err := e.newRebuildTask(sessionID, "rebuild")
+	if err != nil {
+		return nil, err
+	}
+	snapshot := *task
+	go e.runRebuild(task, sess, opts)
+	return &snapshot, nil
+}
+
+// RebuildStatus returns a copy of a rebuild task
+func (e *Engine) RebuildStatus(taskID string) (*RebuildTask, error) {
+	e.rebuilds.mu.RLock()
+	defer e.rebuilds.mu.RUnlock()
+
+	task, ok := e.rebuilds.tasks[taskID]
+	if !ok {
+		return nil, ErrTaskNotFound
+	}
+	snapshot := *task
+	return &snapshot, nil
+}
+
+// WaitRebuild blocks until a rebuild task finishes and returns its copy
+func (e *Engine) WaitRebuild(taskID string) (*RebuildTask, error) {
+	task, err := e.RebuildStatus(taskID)
+	if err != nil {
+		return nil, err
+	}
+	<-task.done
+	return e.RebuildStatus(taskID)
+}
+
+// RebuildTasks returns copies of a session's rebuild tasks, oldest first
+func (e *Engine) RebuildTasks(sessionID string) []*RebuildTask {
+	e.rebuilds.mu.RLock()
+	defer e.rebuilds.mu.RUnlock()
+
+	tasks := make([]*RebuildTask, 0)
+	for _, task := range e.rebuilds.tasks {
+		if task.SessionID == sessionID {
+			snapshot := *task
+			tasks = append(tasks, &snapshot)
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].StartTime.Before(tasks[j].StartTime)
+	})
+	return tasks
+}
+
+// newRebuildTask registers a running task whose ID starts with kind,
+// refusing a second concurrent rebuild of the same session
+func (e *Engine) newRebuildTask(sessionID, kind string) (*RebuildTask, error) {
+	e.rebuilds.mu.Lock()
+	defer e.rebuilds.mu.Unlock()
+
+	if e.rebuilds.tasks == nil {
+		e.rebuilds.tasks = make(map[string]*RebuildTask)
+	}
+	for _, task := range e.rebuilds.tasks {
+		if task.SessionID == sessionID && task.Status == TaskStatusRunning {
+			return nil, ErrRebuildInProgress
+		}
+	}
+	e.pruneRebuildTasksLocked()
+
+	e.rebuilds.seq++
+	task := &RebuildTask{
+		ID:        fmt.Sprintf("%s_%s_%d", kind, sessionID, e.rebuilds.seq),
+		SessionID: sessionID,
+		Status:    TaskStatusRunning,
+		StartTime: time.Now(),
+		done:      make(chan struct{}),
+	}
+	e.rebuilds.tasks[task.ID] = task
+	return task, nil
+}
+
+// pruneRebuildTasksLocked drops the oldest finished tasks beyond
+// MaxRebuildTasks
+func (e *Engine) pruneRebuildTasksLocked() {
+	finished := make([]*RebuildTask, 0)
+	for _, task := range e.rebuilds.tasks {
+		if task.Status != TaskStatusRunning {
+			finished = append(finished, task)
+		}
+	}
+	if len(finished) < MaxRebuildTasks {
+		return
+	}
+	sort.Slice(finished, func(i, j int) bool {
+		return finished[i].EndTime.Before(finished[j].EndTime)
+	})
+	for _, task := range finished[:len(finished)-MaxRebuildTasks+1] {
+		delete(e.rebuilds.tasks, task.ID)
+	}
+}
+
+// runRebuild rebuilds each index of sess in turn and records the outcome
+func (e *Engine) runRebuild(task *RebuildTask, sess *store.SessionStore, opts store.SessionOptions) {
+	configs := map[string]vector.HNSWConfig{
+		store.IndexTextUnit:  opts.TextUnitIndex,
+		store.IndexEntity:    opts.EntityIndex,
+		store.IndexCommunity: opts.CommunityIndex,
+	}
+
+	var err error
+	for i, name := range store.IndexNames {
+		e.rebuilds.mu.Lock()
+		task.Index = name
+		e.rebuilds.mu.Unlock()
+
+		progress := func(done, total int) {
+			e.rebuilds.mu.Lock()
+			task.Progress = (float64(i) + float64(done)/float64(total)) / float64(len(store.IndexNames))
+			e.rebuilds.mu.Unlock()
+		}
+		if err = sess.RebuildIndex(name, configs[name], progress); err != nil {
+			err = fmt.Errorf("%s index: %w", name, err)
+			break
+		}
+	}
+	e.finishRebuild(task, err)
+}
+
+// finishRebuild records the outcome of a task
+func (e *Engine) finishRebuild(task *RebuildTask, err error) {
+	e.rebuilds.mu.Lock()
+	defer e.rebuilds.mu.Unlock()
+	defer close(task.done)
+	task.EndTime = time.Now()
+	if err != nil {
+		task.Status = TaskStatusFailed
+		task.Error = err
+		return
+	}
+	task.Status = TaskStatusComplete
+	task.Progress = 1.0
+}
+
+// =============================================================================
+// Bulk Load
+// =============================================================================
+
+// MaxBulkWorkers bounds the goroutines of a bulk load build
+const MaxBulkWorkers = 256
+
+var ErrNotBulkLoading = errors.New("session is not bulk loading")
+
+// BeginBulkLoad defers vector index insertion for a session until
+// CommitBulkLoad, so large MSET loads only store objects. It reports
+// whether the session was already bulk loading.
+func (e *Engine) BeginBulkLoad(sessionID string) (bool, error) {
+	sess, err := e.getOrCreateSession(sessionID)
+	if err != nil {
+		return false, err
+	}
+	return sess.BeginBulkLoad(), nil
+}
+
+// CommitBulkLoad ends a session's bulk load and builds the deferred vectors
+// in the background with workers goroutines (0 = one per CPU). The indices
+// stay searchable during the build; progress is reported by RebuildStatus.
+func (e *Engine) CommitBulkLoad(sessionID string, workers int) (*RebuildTask, error) {
+	sess, err := e.getSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if workers < 0 || workers > MaxBulkWorkers {
+		return nil, fmt.Errorf("workers must be between 0 and %d, got %d", MaxBulkWorkers, workers)
+	}
+	if !sess.BulkLoading() {
+		return nil, ErrNotBulkLoading
+	}
+
+	task, err := e.newRebuildTask(sessionID, "bulk")
+	if err != nil {
+		return nil, err
+	}
+	snapshot := *task
+	go e.runBulkBuild(task, sess, workers)
+	return &snapshot, nil
+}
+
+// runBulkBuild builds the deferred vectors of sess and records the outcome
+func (e *Engine) runBulkBuild(task *RebuildTask, sess *store.SessionStore, workers int) {
+	err := sess.BuildPendingVectors(workers, func(index string, done, total int) {
+		e.rebuilds.mu.Lock()
+		task.Index = index
+		task.Progress = float64(done) / float64(total)
+		e.rebuilds.mu.Unlock()
+	})
+	e.finishRebuild(task, err)
+}
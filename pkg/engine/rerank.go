@@ -0,0 +1,236 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/gibram-io/gibram/pkg/types"
+	"github.com/gibram-io/gibram/pkg/vector"
+)
+
+// =============================================================================
+// Re-ranking - MMR Diversity, De-duplication and Per-Document Caps
+// =============================================================================
+
+// reranker re-orders the score-sorted result lists of a query
+type reranker struct {
+	mode           types.RerankMode
+	lambda         float32
+	dedupThreshold float32
+	maxPerDocument int
+}
+
+// newReranker validates the re-ranking options of spec and fills in
+// defaults
+func newReranker(spec types.QuerySpec) (reranker, error) {
+	r := reranker{
+		mode:           spec.Rerank,
+		lambda:         spec.MMRLambda,
+		dedupThreshold: spec.DedupThreshold,
+		maxPerDocument: spec.MaxPerDocument,
+	}
+	switch r.mode {
+	case "":
+		r.mode = types.RerankNone
+	case types.RerankNone, types.RerankMMR:
+	default:
+		return r, fmt.Errorf("unknown rerank mode: %q (want none or mmr)", spec.Rerank)
+	}
+	if r.lambda < 0 || r.lambda > 1 {
+		return r, fmt.Errorf("mmr_lambda must be between 0 and 1, got %g", spec.MMRLambda)
+	}
+	if r.lambda == 0 {
+		r.lambda = types.DefaultMMRLambda
+	}
+	if r.mode == types.RerankNone {
+		r.lambda = 1 // pure score order
+	}
+	if r.dedupThreshold < 0 || r.dedupThreshold > 1 {
+		return r, fmt.Errorf("dedup_threshold must be between 0 and 1, got %g", spec.DedupThreshold)
+	}
+	if r.maxPerDocument < 0 {
+		return r, fmt.Errorf("max_per_document must not be negative, got %d", spec.MaxPerDocument)
+	}
+	return r, nil
+}
+
+// active reports whether any re-ranking option is set
+func (r reranker) active() bool {
+	return r.mode == types.RerankMMR || r.dedupThreshold > 0 || r.maxPerDocument > 0
+}
+
+// needsVectors reports whether re-ranking compares embeddings
+func (r reranker) needsVectors() bool {
+	return r.mode == types.RerankMMR || r.dedupThreshold > 0
+}
+
+// rerankItem is one result of a list being re-ranked
+type rerankItem struct {
+	id         uint64
+	externalID string
+	documentID uint64 // text units only, for the per-document cap
+	score      float32
+	vec        []float32 // nil when the result has no stored embedding
+}
+
+// rerank greedily selects up to k of items, which are sorted by score,
+// returning their positions in the new order and a demotion for every
+// result dropped or moved down. Each step picks the result maximizing
+// lambda*relevance - (1-lambda)*similarity to the results already
+// selected, with relevance the score min-max normalized over items.
+// A picked result is dropped instead when its document is at the cap or
+// it is a near duplicate of a selected result.
+func (r reranker) rerank(kind types.SearchType, items []rerankItem, k int, metric vector.Metric) ([]int, []types.Demotion) {
+	n := len(items)
+	rel := make([]float32, n)
+	if n > 0 {
+		lo, hi := items[n-1].score, items[0].score
+		for i, it := range items {
+			rel[i] = 1
+			if hi > lo {
+				rel[i] = (it.score - lo) / (hi - lo)
+			}
+		}
+	}
+
+	alive := make([]bool, n)
+	for i := range alive {
+		alive[i] = true
+	}
+	maxSim := make([]float32, n) // similarity to the nearest selected result
+	nearest := make([]int, n)    // position of that result, -1 = none yet
+	demoted := make([]bool, n)   // already has a demotion
+	for i := range nearest {
+		nearest[i] = -1
+	}
+	perDocument := make(map[uint64]int)
+	order := make([]int, 0, min(k, n))
+	var demotions []types.Demotion
+	demote := func(i int, reason types.DemotionReason, newRank int) {
+		d := types.Demotion{
+			Type:       kind,
+			ID:         items[i].id,
+			ExternalID: items[i].externalID,
+			Reason:     reason,
+			Rank:       i + 1,
+			NewRank:    newRank,
+		}
+		if reason != types.DemotedDocumentCap && nearest[i] >= 0 {
+			d.SimilarTo = items[nearest[i]].id
+			d.Similarity = maxSim[i]
+		}
+		demotions = append(demotions, d)
+		demoted[i] = true
+	}
+
+	for len(order) < k {
+		best := -1
+		var bestValue float32
+		for i := 0; i < n; i++ {
+			if !alive[i] {
+				continue
+			}
+			value := r.lambda * rel[i]
+			if nearest[i] >= 0 {
+				value -= (1 - r.lambda) * maxSim[i]
+			}
+			if best < 0 || value > bestValue {
+				best, bestValue = i, value
+			}
+		}
+		if best < 0 {
+			break
+		}
+		alive[best] = false
+
+		it := items[best]
+		if r.maxPerDocument > 0 && kind == types.SearchTypeTextUnit && perDocument[it.documentID] >= r.maxPerDocument {
+			demote(best, types.DemotedDocumentCap, 0)
+			continue
+		}
+		if r.dedupThreshold > 0 && nearest[best] >= 0 && maxSim[best] >= r.dedupThreshold {
+			demote(best, types.DemotedDuplicate, 0)
+			continue
+		}
+		order = append(order, best)
+		perDocument[it.documentID]++
+		if len(order) > best+1 && r.mode == types.RerankMMR {
+			demote(best, types.DemotedMMR, len(order))
+		}
+
+		if it.vec == nil {
+			continue
+		}
+		for i := 0; i < n; i++ {
+			if !alive[i] || items[i].vec == nil {
+				continue
+			}
+			if sim := metric.Similarity(items[i].vec, it.vec); nearest[i] < 0 || sim > maxSim[i] {
+				maxSim[i], nearest[i] = sim, best
+			}
+		}
+	}
+
+	// Results within the first k by score that MMR pushed out entirely
+	if r.mode == types.RerankMMR {
+		for i := 0; i < min(k, n); i++ {
+			if alive[i] && !demoted[i] {
+				demote(i, types.DemotedMMR, 0)
+			}
+		}
+	}
+	return order, demotions
+}
+
+// rerankTextUnits re-ranks list, sorted by score, keeping up to k
+func (r reranker) rerankTextUnits(list []types.TextUnitResult, k int, idx vector.Index) ([]types.TextUnitResult, []types.Demotion) {
+	items := make([]rerankItem, len(list))
+	for i, tr := range list {
+		items[i] = rerankItem{id: tr.TextUnit.ID, externalID: tr.TextUnit.ExternalID, documentID: tr.TextUnit.DocumentID, score: tr.Score}
+	}
+	order, demotions := r.rerank(types.SearchTypeTextUnit, r.withVectors(items, idx), k, idx.Metric())
+	kept := make([]types.TextUnitResult, len(order))
+	for i, pos := range order {
+		kept[i] = list[pos]
+	}
+	return kept, demotions
+}
+
+// rerankEntities re-ranks list, sorted by score, keeping up to k
+func (r reranker) rerankEntities(list []types.EntityResult, k int, idx vector.Index) ([]types.EntityResult, []types.Demotion) {
+	items := make([]rerankItem, len(list))
+	for i, er := range list {
+		items[i] = rerankItem{id: er.Entity.ID, externalID: er.Entity.ExternalID, score: er.Score}
+	}
+	order, demotions := r.rerank(types.SearchTypeEntity, r.withVectors(items, idx), k, idx.Metric())
+	kept := make([]types.EntityResult, len(order))
+	for i, pos := range order {
+		kept[i] = list[pos]
+	}
+	return kept, demotions
+}
+
+// rerankCommunities re-ranks list, sorted by score, keeping up to k
+func (r reranker) rerankCommunities(list []types.CommunityResult, k int, idx vector.Index) ([]types.CommunityResult, []types.Demotion) {
+	items := make([]rerankItem, len(list))
+	for i, cr := range list {
+		items[i] = rerankItem{id: cr.Community.ID, externalID: cr.Community.ExternalID, score: cr.Score}
+	}
+	order, demotions := r.rerank(types.SearchTypeCommunity, r.withVectors(items, idx), k, idx.Metric())
+	kept := make([]types.CommunityResult, len(order))
+	for i, pos := range order {
+		kept[i] = list[pos]
+	}
+	return kept, demotions
+}
+
+// withVectors loads the stored embedding of each item when re-ranking
+// compares them
+func (r reranker) withVectors(items []rerankItem, idx vector.Index) []rerankItem {
+	if !r.needsVectors() {
+		return items
+	}
+	for i := range items {
+		items[i].vec, _ = idx.Vector(items[i].id)
+	}
+	return items
+}
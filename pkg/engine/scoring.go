@@ -0,0 +1,258 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gibram-io/gibram/pkg/graph"
+	"github.com/gibram-io/gibram/pkg/types"
+)
+
+// =============================================================================
+// Graph Expansion Scoring
+// =============================================================================
+
+// expansionScorer scores entities and text units reached by graph
+// expansion according to the query's expansion mode and scoring strategy
+type expansionScorer struct {
+	strategy    types.ScoringStrategy
+	decay       float32
+	aggregation types.ScoreAggregation
+
+	expansion      types.ExpansionMode
+	damping        float64
+	iterations     int
+	restart        types.RestartMode
+	restartWeights map[uint64]float32
+}
+
+// newExpansionScorer validates the scoring options of spec and fills in
+// defaults
+func newExpansionScorer(spec types.QuerySpec) (expansionScorer, error) {
+	sc := expansionScorer{
+		strategy:    spec.Scoring,
+		decay:       spec.Decay,
+		aggregation: spec.Aggregation,
+
+		expansion:      spec.Expansion,
+		damping:        float64(spec.Damping),
+		iterations:     spec.Iterations,
+		restart:        spec.Restart,
+		restartWeights: spec.RestartWeights,
+	}
+	switch sc.strategy {
+	case "":
+		sc.strategy = types.ScoringHop
+	case types.ScoringHop, types.ScoringWeighted:
+	default:
+		return sc, fmt.Errorf("unknown scoring strategy: %q (want hop or weighted)", spec.Scoring)
+	}
+	switch sc.aggregation {
+	case "":
+		sc.aggregation = types.AggregateMax
+	case types.AggregateMax, types.AggregateSum:
+	default:
+		return sc, fmt.Errorf("unknown score aggregation: %q (want max or sum)", spec.Aggregation)
+	}
+	if sc.decay < 0 || sc.decay > 1 {
+		return sc, fmt.Errorf("decay must be between 0 and 1, got %g", spec.Decay)
+	}
+	if sc.decay == 0 {
+		sc.decay = types.DefaultScoreDecay
+	}
+
+	switch sc.expansion {
+	case "":
+		sc.expansion = types.ExpansionBFS
+	case types.ExpansionBFS, types.ExpansionPPR:
+	default:
+		return sc, fmt.Errorf("unknown expansion mode: %q (want bfs or ppr)", spec.Expansion)
+	}
+	switch sc.restart {
+	case "":
+		sc.restart = types.RestartScore
+	case types.RestartScore, types.RestartUniform:
+	default:
+		return sc, fmt.Errorf("unknown restart distribution: %q (want score or uniform)", spec.Restart)
+	}
+	if sc.damping < 0 || sc.damping >= 1 {
+		return sc, fmt.Errorf("damping must be at least 0 and below 1, got %g", spec.Damping)
+	}
+	if sc.damping == 0 {
+		sc.damping = types.DefaultPPRDamping
+	}
+	if sc.iterations < 0 || sc.iterations > types.MaxPPRIterations {
+		return sc, fmt.Errorf("iterations must be between 0 and %d, got %d", types.MaxPPRIterations, spec.Iterations)
+	}
+	if sc.iterations == 0 {
+		sc.iterations = types.DefaultPPRIterations
+	}
+	for id, w := range sc.restartWeights {
+		if w < 0 {
+			return sc, fmt.Errorf("restart weight of entity %d is negative", id)
+		}
+	}
+	return sc, nil
+}
+
+// combine aggregates a new path score into the current score of a node
+func (sc expansionScorer) combine(current, path float32, seen bool) float32 {
+	if !seen {
+		return path
+	}
+	if sc.strategy == types.ScoringWeighted && sc.aggregation == types.AggregateSum {
+		return current + path
+	}
+	return max(current, path)
+}
+
+// textUnitScore returns the score of a text unit linked to an entity
+// found by expansion
+func (sc expansionScorer) textUnitScore(er *types.EntityResult) float32 {
+	if sc.expansion == types.ExpansionPPR {
+		return er.Score
+	}
+	if sc.strategy == types.ScoringWeighted {
+		return er.Score * sc.decay
+	}
+	return float32(1.0 / float64(2+er.Hop))
+}
+
+// scoreEntities returns the score of every entity visited by expansion
+// and records the cumulative score of each traversal step. Weighted scores
+// flow from the seeds along shortest paths only: each relationship from
+// hop h to hop h+1 carries score x decay x weight, with the weight
+// normalized by the strongest relationship of its source entity.
+func (sc expansionScorer) scoreEntities(
+	seeds map[uint64]float32,
+	hops map[uint64]int,
+	traversal []types.TraversalStep,
+	rels graph.RelationshipStore,
+) map[uint64]float32 {
+	scores := make(map[uint64]float32, len(hops))
+	if sc.strategy == types.ScoringHop {
+		for id, hop := range hops {
+			scores[id] = float32(1.0 / float64(1+hop))
+		}
+		for i := range traversal {
+			traversal[i].Cumulative = float32(1.0 / float64(1+traversal[i].Hop))
+		}
+		return scores
+	}
+
+	ids := make([]uint64, 0, len(hops))
+	for id := range hops {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if hops[ids[i]] != hops[ids[j]] {
+			return hops[ids[i]] < hops[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+
+	for id, score := range seeds {
+		if hops[id] == 0 {
+			scores[id] = score
+		}
+	}
+
+	maxWeight := make(map[uint64]float32)
+	for _, id := range ids {
+		from, ok := scores[id]
+		if !ok {
+			continue
+		}
+		out := rels.GetOutgoing(id)
+		in := rels.GetIncoming(id)
+		top := strongest(out, in)
+		maxWeight[id] = top
+
+		next := hops[id] + 1
+		propagate := func(rel *types.Relationship, to uint64) {
+			if hop, ok := hops[to]; !ok || hop != next {
+				return
+			}
+			current, seen := scores[to]
+			scores[to] = sc.combine(current, from*sc.decay*normalizedWeight(rel.Weight, top), seen)
+		}
+		for _, rel := range out {
+			propagate(rel, rel.TargetID)
+		}
+		for _, rel := range in {
+			propagate(rel, rel.SourceID)
+		}
+	}
+
+	for i := range traversal {
+		step := &traversal[i]
+		step.Cumulative = scores[step.FromEntityID] * sc.decay * normalizedWeight(step.Weight, maxWeight[step.FromEntityID])
+	}
+	return scores
+}
+
+// pageRank ranks the entities visited by expansion with personalized
+// PageRank restarting at the seeds. Scores are scaled so the best entity
+// scores 1; each traversal step records the score of the entity it
+// reaches.
+func (sc expansionScorer) pageRank(
+	seeds map[uint64]float32,
+	visited []uint64,
+	traversal []types.TraversalStep,
+	rels graph.RelationshipStore,
+) map[uint64]float32 {
+	restart := make(map[uint64]float64, len(seeds))
+	switch {
+	case len(sc.restartWeights) > 0:
+		for id, w := range sc.restartWeights {
+			restart[id] = float64(w)
+		}
+	case sc.restart == types.RestartUniform:
+		for id := range seeds {
+			restart[id] = 1
+		}
+	default:
+		for id, score := range seeds {
+			restart[id] = float64(score)
+		}
+	}
+
+	ranks := graph.PersonalizedPageRank(visited, rels, restart, sc.damping, sc.iterations)
+	top := 0.0
+	for _, rank := range ranks {
+		top = max(top, rank)
+	}
+	scores := make(map[uint64]float32, len(ranks))
+	for id, rank := range ranks {
+		if top > 0 {
+			scores[id] = float32(rank / top)
+		}
+	}
+	for i := range traversal {
+		traversal[i].Cumulative = scores[traversal[i].ToEntityID]
+	}
+	return scores
+}
+
+// strongest returns the largest relationship weight among rel lists
+func strongest(lists ...[]*types.Relationship) float32 {
+	var top float32
+	for _, list := range lists {
+		for _, rel := range list {
+			top = max(top, rel.Weight)
+		}
+	}
+	return top
+}
+
+// normalizedWeight scales w into [0, 1] by the strongest weight of its
+// entity. Unweighted graphs (every weight zero) count each edge as 1.
+func normalizedWeight(w, top float32) float32 {
+	if top <= 0 {
+		return 1
+	}
+	if w <= 0 {
+		return 0
+	}
+	return w / top
+}
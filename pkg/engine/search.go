@@ -0,0 +1,206 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/gibram-io/gibram/pkg/store"
+	"github.com/gibram-io/gibram/pkg/types"
+	"github.com/gibram-io/gibram/pkg/vector"
+)
+
+// =============================================================================
+// Search - k-NN and Range Search without Graph Expansion
+// =============================================================================
+
+// Search runs k-NN or range search on one vector index for each query
+// vector and returns one hit list per vector, most similar first. Unlike
+// Query it does no graph expansion or context assembly.
+func (e *Engine) Search(sessionID string, spec types.SearchSpec) ([][]types.SearchHit, error) {
+	sess, err := e.getSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateSearchSpec(spec, sess.VectorDim()); err != nil {
+		return nil, err
+	}
+
+	filter := newQueryFilter(sess, spec.FilterEntityTypes, spec.FilterDocumentIDs, spec.FilterAttrs)
+	params := vector.SearchParams{
+		K:             spec.K,
+		Ef:            spec.Ef,
+		MinSimilarity: spec.MinSimilarity,
+		Range:         spec.Range,
+	}
+	if params.K == 0 {
+		params.K = types.DefaultSearchK
+		if spec.Range {
+			params.K = types.MaxSearchK
+		}
+	}
+
+	idx := sessionIndex(sess, spec.Index)
+	switch spec.Index {
+	case types.SearchTypeTextUnit:
+		params.Filter = filter.textUnitFilter()
+	case types.SearchTypeEntity:
+		params.Filter = filter.entityFilter()
+	}
+
+	results := make([][]types.SearchHit, len(spec.QueryVectors))
+	for i, query := range spec.QueryVectors {
+		found := idx.SearchWithParams(query, params)
+		hits := make([]types.SearchHit, 0, len(found))
+		for _, r := range found {
+			if hit, ok := searchHit(sess, spec, r); ok {
+				hits = append(hits, hit)
+			}
+		}
+		results[i] = hits
+	}
+	return results, nil
+}
+
+// sessionIndex returns the vector index of sess searched by t
+func sessionIndex(sess *store.SessionStore, t types.SearchType) vector.Index {
+	switch t {
+	case types.SearchTypeTextUnit:
+		return sess.GetTextUnitIndex()
+	case types.SearchTypeEntity:
+		return sess.GetEntityIndex()
+	default:
+		return sess.GetCommunityIndex()
+	}
+}
+
+// validateSearchSpec checks a SearchSpec against the session's vector dimension
+func validateSearchSpec(spec types.SearchSpec, dim int) error {
+	if err := validateSearchType(spec.Index); err != nil {
+		return err
+	}
+	if len(spec.QueryVectors) == 0 {
+		return fmt.Errorf("at least one query vector is required")
+	}
+	if len(spec.QueryVectors) > types.MaxSearchVectors {
+		return fmt.Errorf("too many query vectors: %d (max %d)", len(spec.QueryVectors), types.MaxSearchVectors)
+	}
+	for i, query := range spec.QueryVectors {
+		if len(query) != dim {
+			return fmt.Errorf("query vector %d has dimension %d, want %d", i, len(query), dim)
+		}
+	}
+	if spec.K < 0 || spec.K > types.MaxSearchK {
+		return fmt.Errorf("k must be between 0 and %d, got %d", types.MaxSearchK, spec.K)
+	}
+	if spec.Ef < 0 || spec.Ef > vector.MaxHNSWEf {
+		return fmt.Errorf("ef must be between 0 and %d, got %d", vector.MaxHNSWEf, spec.Ef)
+	}
+	if spec.Range && spec.MinSimilarity == nil {
+		return fmt.Errorf("range search requires min_similarity")
+	}
+	if (len(spec.FilterEntityTypes) > 0 || len(spec.FilterAttrs) > 0) && spec.Index != types.SearchTypeEntity {
+		return fmt.Errorf("entity type and attribute filters apply to the entity index only")
+	}
+	if len(spec.FilterDocumentIDs) > 0 && spec.Index != types.SearchTypeTextUnit {
+		return fmt.Errorf("document filters apply to the textunit index only")
+	}
+	return nil
+}
+
+// validateSearchType checks that t names a vector index
+func validateSearchType(t types.SearchType) error {
+	switch t {
+	case types.SearchTypeTextUnit, types.SearchTypeEntity, types.SearchTypeCommunity:
+		return nil
+	}
+	return fmt.Errorf("unknown search index: %q (want textunit, entity or community)", t)
+}
+
+// searchHit resolves a vector hit to its object; ok is false when the
+// object was deleted after the search
+func searchHit(sess *store.SessionStore, spec types.SearchSpec, r vector.SearchResult) (types.SearchHit, bool) {
+	hit := types.SearchHit{ID: r.ID, Similarity: r.Similarity}
+	switch spec.Index {
+	case types.SearchTypeTextUnit:
+		tu, ok := sess.GetTextUnit(r.ID)
+		if !ok {
+			return hit, false
+		}
+		hit.ExternalID = tu.ExternalID
+		if spec.IncludeObjects {
+			hit.TextUnit = tu
+		}
+	case types.SearchTypeEntity:
+		ent, ok := sess.GetEntity(r.ID)
+		if !ok {
+			return hit, false
+		}
+		hit.ExternalID = ent.ExternalID
+		if spec.IncludeObjects {
+			hit.Entity = ent
+		}
+	case types.SearchTypeCommunity:
+		comm, ok := sess.GetCommunity(r.ID)
+		if !ok {
+			return hit, false
+		}
+		hit.ExternalID = comm.ExternalID
+		if spec.IncludeObjects {
+			hit.Community = comm
+		}
+	}
+	return hit, true
+}
+
+// =============================================================================
+// Recall Self-Check
+// =============================================================================
+
+// CheckRecall compares HNSW search with exact search on a session's vector
+// indices and reports recall@k, latency and nodes visited for each. Stored
+// vectors are sampled as queries unless query vectors are given. Empty
+// indices are skipped unless named.
+func (e *Engine) CheckRecall(sessionID string, spec types.RecallSpec) ([]types.IndexRecall, error) {
+	sess, err := e.getSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	names := []types.SearchType{types.SearchTypeTextUnit, types.SearchTypeEntity, types.SearchTypeCommunity}
+	if spec.Index != "" {
+		if err := validateSearchType(spec.Index); err != nil {
+			return nil, err
+		}
+		names = []types.SearchType{spec.Index}
+	}
+
+	params := vector.RecallParams{
+		K:       spec.K,
+		Ef:      spec.Ef,
+		Samples: spec.Samples,
+		Queries: spec.QueryVectors,
+	}
+	results := make([]types.IndexRecall, 0, len(names))
+	for _, name := range names {
+		idx := sessionIndex(sess, name)
+		count := idx.Count()
+		if count == 0 && spec.Index == "" {
+			continue
+		}
+		report, err := idx.CheckRecall(params)
+		if err != nil {
+			return nil, fmt.Errorf("%s index: %w", name, err)
+		}
+		results = append(results, types.IndexRecall{
+			Index:          name,
+			Vectors:        count,
+			Queries:        report.Queries,
+			K:              report.K,
+			Ef:             report.Ef,
+			Recall:         report.Recall,
+			MinRecall:      report.MinRecall,
+			LatencyUs:      types.Distribution(report.Micros),
+			ExactLatencyUs: types.Distribution(report.ExactMicros),
+			Visited:        types.Distribution(report.Visited),
+		})
+	}
+	return results, nil
+}
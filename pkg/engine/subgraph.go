@@ -0,0 +1,200 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gibram-io/gibram/pkg/graph"
+	"github.com/gibram-io/gibram/pkg/store"
+	"github.com/gibram-io/gibram/pkg/types"
+)
+
+// =============================================================================
+// Subgraph - Ego Network Extraction
+// =============================================================================
+
+// Subgraph returns the entities within spec.Hops of the requested entities
+// and every relationship between them, with degree counts. Expansion
+// follows spec.Direction and only passes through relationships and
+// entities matching the type filters; the requested entities are always
+// included.
+func (e *Engine) Subgraph(sessionID string, spec types.SubgraphSpec) (*types.Subgraph, error) {
+	sess, err := e.getSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateSubgraphSpec(&spec); err != nil {
+		return nil, err
+	}
+	for _, id := range spec.EntityIDs {
+		if _, ok := sess.GetEntity(id); !ok {
+			return nil, fmt.Errorf("entity %d not found", id)
+		}
+	}
+
+	rels := newFilteredRelStore(sess, spec)
+
+	// One node over the limit tells whether the neighbourhood was cut
+	visited, hops, _ := graph.BFSTraversal(spec.EntityIDs, rels, spec.Hops, spec.MaxNodes+1)
+	// Same-hop nodes are ordered by ID so a cut is deterministic
+	sort.Slice(visited, func(i, j int) bool {
+		if hops[visited[i]] != hops[visited[j]] {
+			return hops[visited[i]] < hops[visited[j]]
+		}
+		return visited[i] < visited[j]
+	})
+	result := &types.Subgraph{}
+	if len(visited) > spec.MaxNodes {
+		visited = visited[:spec.MaxNodes] // the farthest, highest IDs go
+		result.Truncated = true
+	}
+
+	inSubgraph := make(map[uint64]bool, len(visited))
+	for _, id := range visited {
+		inSubgraph[id] = true
+	}
+
+	// Induced relationships, whatever the expansion direction
+	degree := make(map[uint64]int, len(visited))
+	for _, id := range visited {
+		for _, rel := range rels.matching(sess.GetOutgoingRelationships(id)) {
+			if !inSubgraph[rel.TargetID] {
+				continue
+			}
+			if len(result.Relationships) == spec.MaxEdges {
+				result.Truncated = true
+				break
+			}
+			result.Relationships = append(result.Relationships, rel)
+			degree[rel.SourceID]++
+			if rel.TargetID != rel.SourceID {
+				degree[rel.TargetID]++
+			}
+		}
+	}
+
+	result.Nodes = make([]types.SubgraphNode, 0, len(visited))
+	for _, id := range visited {
+		ent, ok := sess.GetEntity(id)
+		if !ok {
+			continue // deleted while extracting
+		}
+		result.Nodes = append(result.Nodes, types.SubgraphNode{
+			Entity:    ent,
+			Hop:       hops[id],
+			InDegree:  len(sess.GetIncomingRelationships(id)),
+			OutDegree: len(sess.GetOutgoingRelationships(id)),
+			Degree:    degree[id],
+		})
+	}
+	return result, nil
+}
+
+// validateSubgraphSpec checks spec and fills in defaults
+func validateSubgraphSpec(spec *types.SubgraphSpec) error {
+	if len(spec.EntityIDs) == 0 {
+		return fmt.Errorf("at least one entity ID is required")
+	}
+	if spec.Hops < 0 || spec.Hops > types.MaxSubgraphHops {
+		return fmt.Errorf("hops must be between 0 and %d, got %d", types.MaxSubgraphHops, spec.Hops)
+	}
+	switch spec.Direction {
+	case "":
+		spec.Direction = types.DirectionBoth
+	case types.DirectionBoth, types.DirectionOut, types.DirectionIn:
+	default:
+		return fmt.Errorf("unknown direction: %q (want both, out or in)", spec.Direction)
+	}
+	if spec.MaxNodes < 0 || spec.MaxNodes > types.MaxSubgraphNodes {
+		return fmt.Errorf("max_nodes must be between 0 and %d, got %d", types.MaxSubgraphNodes, spec.MaxNodes)
+	}
+	if spec.MaxNodes == 0 {
+		spec.MaxNodes = types.DefaultSubgraphNodes
+	}
+	if len(spec.EntityIDs) > spec.MaxNodes {
+		return fmt.Errorf("too many entity IDs: %d (max_nodes %d)", len(spec.EntityIDs), spec.MaxNodes)
+	}
+	if spec.MaxEdges < 0 || spec.MaxEdges > types.MaxSubgraphEdges {
+		return fmt.Errorf("max_edges must be between 0 and %d, got %d", types.MaxSubgraphEdges, spec.MaxEdges)
+	}
+	if spec.MaxEdges == 0 {
+		spec.MaxEdges = types.DefaultSubgraphEdges
+	}
+	return nil
+}
+
+// filteredRelStore restricts a session's relationships to one direction
+// and to the relationship and entity types of a SubgraphSpec, so BFS
+// expansion only walks matching edges
+type filteredRelStore struct {
+	sessionRelAdapter
+	direction   types.Direction
+	relTypes    map[string]bool // upper-cased; nil = all
+	entityTypes map[string]bool // upper-cased; nil = all
+}
+
+func newFilteredRelStore(sess *store.SessionStore, spec types.SubgraphSpec) *filteredRelStore {
+	return &filteredRelStore{
+		sessionRelAdapter: sessionRelAdapter{sess: sess},
+		direction:         spec.Direction,
+		relTypes:          upperSet(spec.RelTypes),
+		entityTypes:       upperSet(spec.EntityTypes),
+	}
+}
+
+func (f *filteredRelStore) GetOutgoing(entityID uint64) []*types.Relationship {
+	if f.direction == types.DirectionIn {
+		return nil
+	}
+	return f.reaching(f.sess.GetOutgoingRelationships(entityID), func(rel *types.Relationship) uint64 { return rel.TargetID })
+}
+
+func (f *filteredRelStore) GetIncoming(entityID uint64) []*types.Relationship {
+	if f.direction == types.DirectionOut {
+		return nil
+	}
+	return f.reaching(f.sess.GetIncomingRelationships(entityID), func(rel *types.Relationship) uint64 { return rel.SourceID })
+}
+
+// matching keeps the relationships of an allowed type
+func (f *filteredRelStore) matching(rels []*types.Relationship) []*types.Relationship {
+	if f.relTypes == nil {
+		return rels
+	}
+	kept := rels[:0:0]
+	for _, rel := range rels {
+		if f.relTypes[strings.ToUpper(rel.Type)] {
+			kept = append(kept, rel)
+		}
+	}
+	return kept
+}
+
+// reaching keeps the matching relationships whose far end is an entity of
+// an allowed type
+func (f *filteredRelStore) reaching(rels []*types.Relationship, far func(*types.Relationship) uint64) []*types.Relationship {
+	rels = f.matching(rels)
+	if f.entityTypes == nil {
+		return rels
+	}
+	kept := rels[:0:0]
+	for _, rel := range rels {
+		if ent, ok := f.sess.GetEntity(far(rel)); ok && f.entityTypes[strings.ToUpper(ent.Type)] {
+			kept = append(kept, rel)
+		}
+	}
+	return kept
+}
+
+// upperSet returns the upper-cased values as a set, or nil when empty
+func upperSet(values []string) map[string]bool {
+	if len(values) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[strings.ToUpper(v)] = true
+	}
+	return set
+}
@@ -2,6 +2,8 @@
 package graph
 
 import (
+	"math"
+	"reflect"
 	"sync"
 	"testing"
 
@@ -430,6 +432,107 @@ func TestPageRank_SingleNode(t *testing.T) {
 	}
 }
 
+func TestPersonalizedPageRank(t *testing.T) {
+	_, relStore, entityIDs := createTestGraph()
+
+	// Restarting at node 1 favours it and its neighbours over node 5
+	scores := PersonalizedPageRank(entityIDs, relStore, map[uint64]float64{1: 1}, 0.85, 50)
+	sum := 0.0
+	for _, s := range scores {
+		sum += s
+	}
+	if math.Abs(sum-1) > 1e-6 {
+		t.Errorf("PersonalizedPageRank scores sum = %f, want 1", sum)
+	}
+	if !(scores[1] > scores[2] && scores[2] > scores[5]) {
+		t.Errorf("scores %v, want 1 > 2 > 5 when restarting at 1", scores)
+	}
+
+	// Restarting at node 5 reverses the ranking
+	scores = PersonalizedPageRank(entityIDs, relStore, map[uint64]float64{5: 1}, 0.85, 50)
+	if !(scores[5] > scores[1]) {
+		t.Errorf("scores %v, want 5 > 1 when restarting at 5", scores)
+	}
+
+	// Heavier relationships attract more of the walk
+	relStore.Add(&types.Relationship{ID: 10, SourceID: 1, TargetID: 5, Weight: 8})
+	scores = PersonalizedPageRank(entityIDs, relStore, map[uint64]float64{1: 1}, 0.85, 50)
+	if !(scores[5] > scores[2]) {
+		t.Errorf("scores %v, want heavy neighbour 5 above 2", scores)
+	}
+
+	// No restart weights inside the set falls back to a uniform restart
+	scores = PersonalizedPageRank([]uint64{1, 2}, newMockRelationshipStore(), map[uint64]float64{9: 1}, 0.85, 10)
+	if scores[1] != 0.5 || scores[2] != 0.5 {
+		t.Errorf("isolated nodes with uniform restart = %v, want 0.5 each", scores)
+	}
+	if PersonalizedPageRank(nil, relStore, nil, 0.85, 10) != nil {
+		t.Error("PersonalizedPageRank() of no entities should return nil")
+	}
+}
+
+func TestShortestPaths(t *testing.T) {
+	// 1 -> 2 -> 4, 1 -> 3 -> 4, a weak direct 1 -> 4, and 4 -> 5
+	relStore := newMockRelationshipStore()
+	for _, r := range []struct {
+		id, from, to uint64
+		relType      string
+		weight       float32
+	}{
+		{1, 1, 2, "knows", 1},
+		{2, 2, 4, "knows", 1},
+		{3, 1, 3, "works_with", 0.5},
+		{4, 3, 4, "works_with", 1},
+		{5, 1, 4, "mentions", 0.1},
+		{6, 4, 5, "knows", 1},
+	} {
+		relStore.Add(&types.Relationship{ID: r.id, SourceID: r.from, TargetID: r.to, Type: r.relType, Weight: r.weight})
+	}
+	relIDs := func(p Path) []uint64 {
+		ids := make([]uint64, len(p.Relationships))
+		for i, rel := range p.Relationships {
+			ids[i] = rel.ID
+		}
+		return ids
+	}
+
+	tests := []struct {
+		name   string
+		from   uint64
+		to     uint64
+		opts   PathOptions
+		expect [][]uint64 // relationship IDs of each path
+	}{
+		{"fewest hops", 1, 4, PathOptions{}, [][]uint64{{5}}},
+		{"weighted", 1, 4, PathOptions{Weighted: true}, [][]uint64{{1, 2}}},
+		{"k paths", 1, 4, PathOptions{K: 3, Weighted: true}, [][]uint64{{1, 2}, {3, 4}, {5}}},
+		{"max depth", 1, 5, PathOptions{Weighted: true, MaxDepth: 2}, [][]uint64{{5, 6}}},
+		{"type filter", 1, 4, PathOptions{K: 5, Allow: func(rel *types.Relationship) bool { return rel.Type == "knows" }}, [][]uint64{{1, 2}}},
+		{"undirected", 5, 1, PathOptions{}, [][]uint64{{6, 5}}},
+		{"directed", 5, 1, PathOptions{Directed: true}, nil},
+	}
+	for _, tt := range tests {
+		paths := ShortestPaths(tt.from, tt.to, relStore, tt.opts)
+		if len(paths) != len(tt.expect) {
+			t.Errorf("%s: got %d paths, want %d", tt.name, len(paths), len(tt.expect))
+			continue
+		}
+		for i, p := range paths {
+			if !reflect.DeepEqual(relIDs(p), tt.expect[i]) {
+				t.Errorf("%s: path %d = %v, want %v", tt.name, i, relIDs(p), tt.expect[i])
+			}
+			if p.EntityIDs[0] != tt.from || p.EntityIDs[len(p.EntityIDs)-1] != tt.to || len(p.EntityIDs) != len(p.Relationships)+1 {
+				t.Errorf("%s: path %d entities %v do not run from %d to %d", tt.name, i, p.EntityIDs, tt.from, tt.to)
+			}
+		}
+	}
+
+	paths := ShortestPaths(1, 4, relStore, PathOptions{K: 3, Weighted: true})
+	if paths[0].Cost != 2 || paths[1].Cost != 3 || math.Abs(paths[2].Cost-10) > 1e-6 {
+		t.Errorf("weighted costs = %v, %v, %v; want 2, 3, 10", paths[0].Cost, paths[1].Cost, paths[2].Cost)
+	}
+}
+
 // =============================================================================
 // Connected Components Tests
 // =============================================================================
@@ -0,0 +1,268 @@
+package graph
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+
+	"github.com/gibram-io/gibram/pkg/types"
+)
+
+// =============================================================================
+// Path Finding - Shortest and K-Shortest Paths
+// =============================================================================
+
+// PathOptions configures a path search between two entities
+type PathOptions struct {
+	K        int                            // number of paths to return (0 = 1)
+	MaxDepth int                            // max relationships per path (0 = unlimited)
+	Directed bool                           // follow relationships from source to target only
+	Weighted bool                           // cost 1/weight per relationship instead of 1
+	Allow    func(*types.Relationship) bool // relationships that may be traversed (nil = all)
+}
+
+// Path is a walk from a source to a target entity. EntityIDs has one more
+// element than Relationships.
+type Path struct {
+	EntityIDs     []uint64
+	Relationships []*types.Relationship
+	Cost          float64
+}
+
+// ShortestPaths returns up to opts.K loopless paths from source to target,
+// cheapest first, using Dijkstra for the first path and Yen's algorithm for
+// the rest. In weighted mode relationships without a positive weight are
+// not traversed.
+func ShortestPaths(source, target uint64, relStore RelationshipStore, opts PathOptions) []Path {
+	k := opts.K
+	if k <= 0 {
+		k = 1
+	}
+	maxDepth := opts.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = math.MaxInt32
+	}
+
+	s := &pathSearch{relStore: relStore, opts: opts, target: target}
+	first, ok := s.dijkstra(source, maxDepth, nil, nil)
+	if !ok {
+		return nil
+	}
+	paths := []Path{first}
+	seen := map[string]bool{pathKey(first): true}
+	var candidates []Path
+
+	for len(paths) < k {
+		prev := paths[len(paths)-1]
+		for i := range prev.Relationships {
+			spur := prev.EntityIDs[i]
+			rootRels := prev.Relationships[:i]
+
+			// Forbid the next relationship of every found path sharing
+			// this root, and the root's entities other than the spur
+			blockedRels := make(map[uint64]bool)
+			for _, p := range paths {
+				if len(p.Relationships) > i && sameRelationships(p.Relationships[:i], rootRels) {
+					blockedRels[p.Relationships[i].ID] = true
+				}
+			}
+			blockedNodes := make(map[uint64]bool, i)
+			for _, id := range prev.EntityIDs[:i] {
+				blockedNodes[id] = true
+			}
+
+			spurPath, ok := s.dijkstra(spur, maxDepth-i, blockedNodes, blockedRels)
+			if !ok {
+				continue
+			}
+			candidate := Path{
+				EntityIDs:     append(append([]uint64(nil), prev.EntityIDs[:i]...), spurPath.EntityIDs...),
+				Relationships: append(append([]*types.Relationship(nil), rootRels...), spurPath.Relationships...),
+				Cost:          spurPath.Cost,
+			}
+			for _, rel := range rootRels {
+				candidate.Cost += s.cost(rel)
+			}
+			if key := pathKey(candidate); !seen[key] {
+				seen[key] = true
+				candidates = append(candidates, candidate)
+			}
+		}
+		if len(candidates) == 0 {
+			break
+		}
+		sort.SliceStable(candidates, func(a, b int) bool {
+			if candidates[a].Cost != candidates[b].Cost {
+				return candidates[a].Cost < candidates[b].Cost
+			}
+			return len(candidates[a].Relationships) < len(candidates[b].Relationships)
+		})
+		paths = append(paths, candidates[0])
+		candidates = candidates[1:]
+	}
+	return paths
+}
+
+// pathSearch holds the fixed inputs of one ShortestPaths call
+type pathSearch struct {
+	relStore RelationshipStore
+	opts     PathOptions
+	target   uint64
+}
+
+// cost returns the cost of traversing rel, or +Inf when it is impassable
+func (s *pathSearch) cost(rel *types.Relationship) float64 {
+	if !s.opts.Weighted {
+		return 1
+	}
+	if rel.Weight <= 0 {
+		return math.Inf(1)
+	}
+	return 1 / float64(rel.Weight)
+}
+
+// pathState is a Dijkstra label: an entity reached after depth hops
+type pathState struct {
+	entity uint64
+	depth  int
+}
+
+// pathItem is a queued label with the relationship it was reached by
+type pathItem struct {
+	state pathState
+	cost  float64
+	prev  *pathItem
+	rel   *types.Relationship
+	dead  bool // dominated by a label found later
+}
+
+// dijkstra finds the cheapest path from start to the search target with at
+// most maxDepth relationships, avoiding blocked entities and relationships.
+// An entity keeps every label not beaten on both cost and depth, so the
+// depth limit cannot hide a longer but cheaper prefix.
+func (s *pathSearch) dijkstra(start uint64, maxDepth int, blockedNodes, blockedRels map[uint64]bool) (Path, bool) {
+	root := &pathItem{state: pathState{start, 0}}
+	labels := map[uint64][]*pathItem{start: {root}}
+	queue := &pathQueue{root}
+
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(*pathItem)
+		if item.dead {
+			continue
+		}
+		if item.state.entity == s.target {
+			return item.path(), true
+		}
+		if item.state.depth >= maxDepth {
+			continue
+		}
+		onPath := item.entities()
+
+		visit := func(rel *types.Relationship, next uint64) {
+			if blockedRels[rel.ID] || blockedNodes[next] || onPath[next] {
+				return
+			}
+			if s.opts.Allow != nil && !s.opts.Allow(rel) {
+				return
+			}
+			c := s.cost(rel)
+			if math.IsInf(c, 1) {
+				return
+			}
+			label := &pathItem{state: pathState{next, item.state.depth + 1}, cost: item.cost + c, prev: item, rel: rel}
+			for _, other := range labels[next] {
+				if other.state.depth <= label.state.depth && other.cost <= label.cost {
+					return
+				}
+			}
+			kept := labels[next][:0]
+			for _, other := range labels[next] {
+				if label.state.depth <= other.state.depth && label.cost <= other.cost {
+					other.dead = true
+				} else {
+					kept = append(kept, other)
+				}
+			}
+			labels[next] = append(kept, label)
+			heap.Push(queue, label)
+		}
+		for _, rel := range s.relStore.GetOutgoing(item.state.entity) {
+			visit(rel, rel.TargetID)
+		}
+		if !s.opts.Directed {
+			for _, rel := range s.relStore.GetIncoming(item.state.entity) {
+				visit(rel, rel.SourceID)
+			}
+		}
+	}
+	return Path{}, false
+}
+
+// entities returns the set of entities on the path to item
+func (item *pathItem) entities() map[uint64]bool {
+	set := make(map[uint64]bool, item.state.depth+1)
+	for it := item; it != nil; it = it.prev {
+		set[it.state.entity] = true
+	}
+	return set
+}
+
+// path unwinds item into a Path
+func (item *pathItem) path() Path {
+	p := Path{
+		EntityIDs:     make([]uint64, item.state.depth+1),
+		Relationships: make([]*types.Relationship, item.state.depth),
+		Cost:          item.cost,
+	}
+	for it := item; it != nil; it = it.prev {
+		p.EntityIDs[it.state.depth] = it.state.entity
+		if it.rel != nil {
+			p.Relationships[it.state.depth-1] = it.rel
+		}
+	}
+	return p
+}
+
+// pathQueue is a min-heap of labels by cost, then depth
+type pathQueue []*pathItem
+
+func (q pathQueue) Len() int { return len(q) }
+func (q pathQueue) Less(i, j int) bool {
+	if q[i].cost != q[j].cost {
+		return q[i].cost < q[j].cost
+	}
+	return q[i].state.depth < q[j].state.depth
+}
+func (q pathQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *pathQueue) Push(x any)   { *q = append(*q, x.(*pathItem)) }
+func (q *pathQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+// sameRelationships reports whether a and b hold the same relationships
+// in order
+func sameRelationships(a, b []*types.Relationship) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].ID != b[i].ID {
+			return false
+		}
+	}
+	return true
+}
+
+// pathKey identifies a path by its relationship IDs
+func pathKey(p Path) string {
+	key := make([]byte, 0, len(p.Relationships)*8)
+	for _, rel := range p.Relationships {
+		for shift := 0; shift < 64; shift += 8 {
+			key = append(key, byte(rel.ID>>shift))
+		}
+	}
+	return string(key)
+}
@@ -0,0 +1,252 @@
+// Package lexical provides a BM25 full-text index for hybrid retrieval
+package lexical
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// =============================================================================
+// BM25 Index
+// =============================================================================
+
+// BM25 defaults
+const (
+	DefaultK1 = 1.2  // term frequency saturation
+	DefaultB  = 0.75 // document length normalization
+)
+
+// Result is a lexical search hit. Score is the raw BM25 score.
+type Result struct {
+	ID    uint64
+	Score float32
+}
+
+// Index is an inverted index over short texts, ranked with BM25.
+// It is safe for concurrent use.
+type Index struct {
+	mu       sync.RWMutex
+	k1       float64
+	b        float64
+	postings map[string]map[uint64]uint32 // term -> id -> term frequency
+	docTerms map[uint64][]string          // id -> distinct terms (for removal)
+	docLen   map[uint64]uint32            // id -> token count
+	totalLen uint64
+}
+
+// NewIndex creates an empty index with the default BM25 parameters
+func NewIndex() *Index {
+	return &Index{
+		k1:       DefaultK1,
+		b:        DefaultB,
+		postings: make(map[string]map[uint64]uint32),
+		docTerms: make(map[uint64][]string),
+		docLen:   make(map[uint64]uint32),
+	}
+}
+
+// Add indexes the concatenation of texts under id, replacing any previous
+// text for id
+func (x *Index) Add(id uint64, texts ...string) {
+	tf := make(map[string]uint32)
+	var length uint32
+	for _, text := range texts {
+		for _, tok := range Tokenize(text) {
+			tf[tok]++
+			length++
+		}
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	x.removeLocked(id)
+	if length == 0 {
+		return
+	}
+
+	terms := make([]string, 0, len(tf))
+	for term, n := range tf {
+		p := x.postings[term]
+		if p == nil {
+			p = make(map[uint64]uint32)
+			x.postings[term] = p
+		}
+		p[id] = n
+		terms = append(terms, term)
+	}
+	x.docTerms[id] = terms
+	x.docLen[id] = length
+	x.totalLen += uint64(length)
+}
+
+// Remove drops id from the index
+func (x *Index) Remove(id uint64) bool {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return x.removeLocked(id)
+}
+
+func (x *Index) removeLocked(id uint64) bool {
+	terms, ok := x.docTerms[id]
+	if !ok {
+		return false
+	}
+	for _, term := range terms {
+		p := x.postings[term]
+		delete(p, id)
+		if len(p) == 0 {
+			delete(x.postings, term)
+		}
+	}
+	x.totalLen -= uint64(x.docLen[id])
+	delete(x.docTerms, id)
+	delete(x.docLen, id)
+	return true
+}
+
+// Count returns the number of indexed texts
+func (x *Index) Count() int {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	return len(x.docLen)
+}
+
+// Search returns up to k ids ranked by BM25 score for query. Only ids
+// passing filter are scored (nil = all).
+func (x *Index) Search(query string, k int, filter func(id uint64) bool) []Result {
+	terms := uniqueTerms(Tokenize(query))
+	if len(terms) == 0 || k <= 0 {
+		return nil
+	}
+
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	n := float64(len(x.docLen))
+	if n == 0 {
+		return nil
+	}
+	avgLen := float64(x.totalLen) / n
+
+	scores := make(map[uint64]float64)
+	for _, term := range terms {
+		p := x.postings[term]
+		if len(p) == 0 {
+			continue
+		}
+		df := float64(len(p))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		for id, tf := range p {
+			if filter != nil && !filter(id) {
+				continue
+			}
+			f := float64(tf)
+			norm := x.k1 * (1 - x.b + x.b*float64(x.docLen[id])/avgLen)
+			scores[id] += idf * f * (x.k1 + 1) / (f + norm)
+		}
+	}
+
+	results := make([]Result, 0, len(scores))
+	for id, score := range scores {
+		results = append(results, Result{ID: id, Score: float32(score)})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].ID < results[j].ID
+	})
+	if len(results) > k {
+		results = results[:k]
+	}
+	return results
+}
+
+// =============================================================================
+// Persisted State
+// =============================================================================
+
+// State is the persisted form of an index: the term frequencies of every
+// indexed text. Restoring it does not re-tokenize the source objects.
+type State struct {
+	Terms map[uint64]map[string]uint32 `json:"terms"` // id -> term -> frequency
+}
+
+// State returns a copy of the indexed term frequencies
+func (x *Index) State() *State {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	state := &State{Terms: make(map[uint64]map[string]uint32, len(x.docTerms))}
+	for id, terms := range x.docTerms {
+		tf := make(map[string]uint32, len(terms))
+		for _, term := range terms {
+			tf[term] = x.postings[term][id]
+		}
+		state.Terms[id] = tf
+	}
+	return state
+}
+
+// Restore replaces the contents of the index with a persisted state
+func (x *Index) Restore(state *State) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	x.postings = make(map[string]map[uint64]uint32)
+	x.docTerms = make(map[uint64][]string, len(state.Terms))
+	x.docLen = make(map[uint64]uint32, len(state.Terms))
+	x.totalLen = 0
+	for id, tf := range state.Terms {
+		var length uint32
+		terms := make([]string, 0, len(tf))
+		for term, n := range tf {
+			if n == 0 {
+				continue
+			}
+			p := x.postings[term]
+			if p == nil {
+				p = make(map[uint64]uint32)
+				x.postings[term] = p
+			}
+			p[id] = n
+			terms = append(terms, term)
+			length += n
+		}
+		if length == 0 {
+			continue
+		}
+		x.docTerms[id] = terms
+		x.docLen[id] = length
+		x.totalLen += uint64(length)
+	}
+}
+
+// =============================================================================
+// Tokenizer
+// =============================================================================
+
+// Tokenize lowercases text and splits it into runs of letters and digits.
+// Identifiers such as "PBI/2024" or "BBCA.JK" become their parts, so they
+// still match when punctuated differently.
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func uniqueTerms(tokens []string) []string {
+	seen := make(map[string]bool, len(tokens))
+	out := tokens[:0]
+	for _, tok := range tokens {
+		if !seen[tok] {
+			seen[tok] = true
+			out = append(out, tok)
+		}
+	}
+	return out
+}
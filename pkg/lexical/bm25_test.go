@@ -0,0 +1,103 @@
+// Package lexical provides BM25 index tests
+package lexical
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	got := Tokenize("PBI No. 23/2021, BBCA.JK — Bank Indonesia")
+	want := []string{"pbi", "no", "23", "2021", "bbca", "jk", "bank", "indonesia"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Tokenize() = %v, want %v", got, want)
+	}
+}
+
+func TestIndex_Search(t *testing.T) {
+	idx := NewIndex()
+	idx.Add(1, "Bank Indonesia raised the policy rate")
+	idx.Add(2, "Regulation PBI 23/2021 on payment systems")
+	idx.Add(3, "The bank", "announced a payment system reform")
+	idx.Add(4, "")
+
+	if idx.Count() != 3 {
+		t.Errorf("Count() = %d, want 3 (empty texts are not indexed)", idx.Count())
+	}
+
+	results := idx.Search("PBI 23/2021", 10, nil)
+	if len(results) != 1 || results[0].ID != 2 {
+		t.Fatalf("Search(PBI 23/2021) = %+v, want only ID 2", results)
+	}
+
+	// Rarer terms weigh more: "indonesia" only appears in 1
+	results = idx.Search("bank indonesia", 10, nil)
+	if len(results) != 2 || results[0].ID != 1 {
+		t.Errorf("Search(bank indonesia) = %+v, want ID 1 first of 2", results)
+	}
+
+	results = idx.Search("payment", 10, func(id uint64) bool { return id != 2 })
+	if len(results) != 1 || results[0].ID != 3 {
+		t.Errorf("filtered Search(payment) = %+v, want only ID 3", results)
+	}
+
+	if results := idx.Search("payment", 1, nil); len(results) != 1 {
+		t.Errorf("Search with k=1 returned %d results", len(results))
+	}
+	if results := idx.Search("   ", 10, nil); results != nil {
+		t.Errorf("empty query returned %+v", results)
+	}
+}
+
+func TestIndex_AddReplacesAndRemove(t *testing.T) {
+	idx := NewIndex()
+	idx.Add(1, "alpha beta")
+	idx.Add(1, "gamma")
+
+	if results := idx.Search("alpha", 10, nil); len(results) != 0 {
+		t.Errorf("replaced text still matches: %+v", results)
+	}
+	if results := idx.Search("gamma", 10, nil); len(results) != 1 {
+		t.Errorf("new text does not match: %+v", results)
+	}
+
+	if !idx.Remove(1) {
+		t.Error("Remove() = false, want true")
+	}
+	if idx.Remove(1) {
+		t.Error("second Remove() = true, want false")
+	}
+	if idx.Count() != 0 || len(idx.postings) != 0 || idx.totalLen != 0 {
+		t.Errorf("index not empty after Remove: count=%d terms=%d len=%d", idx.Count(), len(idx.postings), idx.totalLen)
+	}
+}
+
+func TestIndex_StateRoundTrip(t *testing.T) {
+	idx := NewIndex()
+	idx.Add(1, "Regulation PBI/2024 on payment systems")
+	idx.Add(2, "payment payment gateway")
+	idx.Add(3, "unrelated text")
+	idx.Remove(3)
+
+	data, err := json.Marshal(idx.State())
+	if err != nil {
+		t.Fatalf("Marshal() error: %v", err)
+	}
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		t.Fatalf("Unmarshal() error: %v", err)
+	}
+	restored := NewIndex()
+	restored.Add(9, "stale")
+	restored.Restore(&state)
+
+	if restored.Count() != 2 || restored.totalLen != idx.totalLen {
+		t.Errorf("restored count=%d len=%d, want 2 and %d", restored.Count(), restored.totalLen, idx.totalLen)
+	}
+	for _, query := range []string{"payment", "pbi 2024", "gateway", "stale"} {
+		if got, want := restored.Search(query, 10, nil), idx.Search(query, 10, nil); !reflect.DeepEqual(got, want) {
+			t.Errorf("Search(%q) after restore = %+v, want %+v", query, got, want)
+		}
+	}
+}
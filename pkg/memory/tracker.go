@@ -72,6 +72,11 @@ func (t *Tracker) GetStats() (stats runtime.MemStats, lastCheck time.Time) {
 	return t.lastStats, t.lastCheck
 }
 
+// MaxBytes returns the configured memory limit (0 = unlimited)
+func (t *Tracker) MaxBytes() int64 {
+	return t.maxBytes
+}
+
 // ForceGC forces garbage collection
 func (t *Tracker) ForceGC() {
 	runtime.GC()
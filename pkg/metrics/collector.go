@@ -37,8 +37,21 @@ func (c *Collector) Gauge(name string, value int64) {
 
 // Histogram records a value in a histogram
 func (c *Collector) Histogram(name string, value float64) {
+	c.histogram(name).Record(value)
+}
+
+// MergeHistogram merges a locally recorded histogram into a named histogram
+func (c *Collector) MergeHistogram(name string, h *Histogram) {
+	c.histogram(name).Merge(h)
+}
+
+// histogram returns the named histogram, creating it on first use
+func (c *Collector) histogram(name string) *Histogram {
+	if h, ok := c.histos.Load(name); ok {
+		return h.(*Histogram)
+	}
 	h, _ := c.histos.LoadOrStore(name, NewHistogram())
-	h.(*Histogram).Record(value)
+	return h.(*Histogram)
 }
 
 // GetCounter returns current counter value
@@ -68,6 +81,15 @@ func (c *Collector) GetHistogram(name string) *HistogramStats {
 	return h.(*Histogram).Stats()
 }
 
+// GetHistogramWindow returns histogram stats over the recent window
+func (c *Collector) GetHistogramWindow(name string) *HistogramStats {
+	h, ok := c.histos.Load(name)
+	if !ok {
+		return nil
+	}
+	return h.(*Histogram).WindowStats()
+}
+
 // Snapshot returns all metrics as a snapshot
 func (c *Collector) Snapshot() *Snapshot {
 	snap := &Snapshot{
@@ -4,75 +4,359 @@ package metrics
 import (
 	"math"
 	"sort"
-	"sync"
+	"sync/atomic"
+	"time"
 )
 
-// Histogram tracks distribution of values
+// DefaultBuckets are the upper bounds used for exported histogram buckets.
+// Values are in microseconds, matching Timer.
+var DefaultBuckets = []float64{
+	50, 100, 250, 500,
+	1000, 2500, 5000, 10000, 25000, 50000,
+	100000, 250000, 500000, 1000000, 2500000, 5000000, 10000000,
+}
+
+// =============================================================================
+// Log-Linear Bucket Layout
+// =============================================================================
+
+// Values are bucketed by binary exponent, and each power of two is split
+// into subBuckets linear sub-buckets. A bucket is at most 1/subBuckets of
+// its lower bound wide, so quantiles are within ~3% of the true value.
+// Bucket 0 holds zero and negative values; positive values outside
+// [2^minExponent, 2^maxExponent) are clamped to the first/last bucket.
+// Min, max, sum and count are always exact.
+const (
+	subBuckets  = 16
+	minExponent = -20 // ~1e-6
+	maxExponent = 44  // ~1.7e13 (200 days in microseconds)
+	numBuckets  = (maxExponent-minExponent)*subBuckets + 1
+)
+
+// bucketFor returns the log-linear bucket index for a non-NaN value
+func bucketFor(v float64) int {
+	if !(v > 0) {
+		return 0
+	}
+	if math.IsInf(v, 1) {
+		return numBuckets - 1
+	}
+
+	frac, exp := math.Frexp(v) // v = frac * 2^exp, frac in [0.5, 1)
+	if exp <= minExponent {
+		return 1
+	}
+	if exp > maxExponent {
+		return numBuckets - 1
+	}
+	sub := int((frac - 0.5) * 2 * subBuckets)
+	return 1 + (exp-minExponent-1)*subBuckets + sub
+}
+
+// bucketValue returns the representative (midpoint) value of a bucket
+func bucketValue(i int) float64 {
+	if i == 0 {
+		return 0
+	}
+	i--
+	exp := minExponent + 1 + i/subBuckets
+	sub := float64(i % subBuckets)
+	lo := math.Ldexp(0.5+sub/(2*subBuckets), exp)
+	hi := math.Ldexp(0.5+(sub+1)/(2*subBuckets), exp)
+	return (lo + hi) / 2
+}
+
+// =============================================================================
+// Histogram
+// =============================================================================
+
+// Window configuration for windowed percentiles
+const (
+	windowSlots   = 4
+	DefaultWindow = time.Minute
+)
+
+// Histogram tracks a distribution of values in constant memory.
+// Record is lock-free and safe for concurrent use. NaN values are ignored.
 type Histogram struct {
-	mu     sync.Mutex
-	values []float64
-	count  int64
-	sum    float64
-	min    float64
-	max    float64
+	counts [numBuckets]atomic.Int64
+	count  atomic.Int64
+	sum    atomic.Uint64 // float64 bits
+	min    atomic.Uint64 // float64 bits
+	max    atomic.Uint64 // float64 bits
+
+	// Exact counts per DefaultBuckets bound (+Inf last) for export
+	buckets []atomic.Int64
+
+	// Ring of sub-histograms covering the most recent window
+	window    [windowSlots]windowSlot
+	slotNanos int64
 }
 
-// NewHistogram creates a new histogram
+// windowSlot holds the samples recorded during one slot interval
+type windowSlot struct {
+	epoch  atomic.Int64 // slot interval number (unix nanos / slotNanos)
+	counts [numBuckets]atomic.Uint32
+	count  atomic.Int64
+	sum    atomic.Uint64 // float64 bits
+}
+
+// NewHistogram creates a new histogram with the default percentile window
 func NewHistogram() *Histogram {
-	return &Histogram{
-		values: make([]float64, 0, 1000),
-		min:    math.MaxFloat64,
-		max:    -math.MaxFloat64,
+	return NewHistogramWithWindow(DefaultWindow)
+}
+
+// NewHistogramWithWindow creates a histogram whose windowed statistics
+// cover roughly the last window duration
+func NewHistogramWithWindow(window time.Duration) *Histogram {
+	slotNanos := int64(window) / windowSlots
+	if slotNanos <= 0 {
+		slotNanos = int64(DefaultWindow) / windowSlots
+	}
+
+	h := &Histogram{
+		buckets:   make([]atomic.Int64, len(DefaultBuckets)+1),
+		slotNanos: slotNanos,
+	}
+	h.min.Store(math.Float64bits(math.Inf(1)))
+	h.max.Store(math.Float64bits(math.Inf(-1)))
+	for i := range h.window {
+		h.window[i].epoch.Store(math.MinInt64)
 	}
+	return h
 }
 
 // Record records a value
 func (h *Histogram) Record(value float64) {
-	h.mu.Lock()
-	defer h.mu.Unlock()
+	h.recordAt(value, time.Now())
+}
+
+func (h *Histogram) recordAt(value float64, now time.Time) {
+	if math.IsNaN(value) {
+		return
+	}
+
+	idx := bucketFor(value)
+	h.counts[idx].Add(1)
+	h.count.Add(1)
+	addFloat(&h.sum, value)
+	minFloat(&h.min, value)
+	maxFloat(&h.max, value)
+	h.buckets[bucketIndex(value)].Add(1)
+
+	slot := h.slotFor(now.UnixNano() / h.slotNanos)
+	slot.counts[idx].Add(1)
+	slot.count.Add(1)
+	addFloat(&slot.sum, value)
+}
 
-	h.values = append(h.values, value)
-	h.count++
-	h.sum += value
+// slotFor returns the window slot for epoch, rotating it if it still holds
+// an older interval. Samples racing with a rotation may be dropped from the
+// window; cumulative statistics are unaffected.
+func (h *Histogram) slotFor(epoch int64) *windowSlot {
+	slot := &h.window[epoch%windowSlots]
+	for {
+		current := slot.epoch.Load()
+		if current >= epoch {
+			return slot
+		}
+		if slot.epoch.CompareAndSwap(current, epoch) {
+			slot.reset()
+			return slot
+		}
+	}
+}
 
-	if value < h.min {
-		h.min = value
+func (s *windowSlot) reset() {
+	for i := range s.counts {
+		s.counts[i].Store(0)
 	}
-	if value > h.max {
-		h.max = value
+	s.count.Store(0)
+	s.sum.Store(0)
+}
+
+// Merge adds all samples recorded in other into h. Window slots are merged
+// when they cover the same interval.
+func (h *Histogram) Merge(other *Histogram) {
+	if other == nil || other == h {
+		return
 	}
 
-	// Keep only last 10000 values for percentile calculations
-	if len(h.values) > 10000 {
-		h.values = h.values[len(h.values)-10000:]
+	for i := range other.counts {
+		if n := other.counts[i].Load(); n != 0 {
+			h.counts[i].Add(n)
+		}
+	}
+	for i := range other.buckets {
+		if i < len(h.buckets) {
+			h.buckets[i].Add(other.buckets[i].Load())
+		}
+	}
+	h.count.Add(other.count.Load())
+	addFloat(&h.sum, math.Float64frombits(other.sum.Load()))
+	minFloat(&h.min, math.Float64frombits(other.min.Load()))
+	maxFloat(&h.max, math.Float64frombits(other.max.Load()))
+
+	if h.slotNanos != other.slotNanos {
+		return
+	}
+	for i := range other.window {
+		src := &other.window[i]
+		epoch := src.epoch.Load()
+		if epoch == math.MinInt64 {
+			continue
+		}
+		dst := h.slotFor(epoch)
+		if dst.epoch.Load() != epoch {
+			continue // h already moved past this interval
+		}
+		for b := range src.counts {
+			if n := src.counts[b].Load(); n != 0 {
+				dst.counts[b].Add(n)
+			}
+		}
+		dst.count.Add(src.count.Load())
+		addFloat(&dst.sum, math.Float64frombits(src.sum.Load()))
 	}
 }
 
-// Stats returns histogram statistics
-func (h *Histogram) Stats() *HistogramStats {
-	h.mu.Lock()
-	defer h.mu.Unlock()
+// Quantile returns the estimated q-quantile (0..1) of all recorded values
+func (h *Histogram) Quantile(q float64) float64 {
+	count := h.count.Load()
+	if count == 0 {
+		return 0
+	}
+	counts := make([]int64, numBuckets)
+	for i := range h.counts {
+		counts[i] = h.counts[i].Load()
+	}
+	return h.clamp(quantile(counts, count, q))
+}
 
-	if h.count == 0 {
+// Stats returns statistics over all recorded values
+func (h *Histogram) Stats() *HistogramStats {
+	count := h.count.Load()
+	if count == 0 {
 		return &HistogramStats{}
 	}
 
-	// Sort for percentiles
-	sorted := make([]float64, len(h.values))
-	copy(sorted, h.values)
-	sort.Float64s(sorted)
+	counts := make([]int64, numBuckets)
+	var total int64
+	for i := range h.counts {
+		counts[i] = h.counts[i].Load()
+		total += counts[i]
+	}
+
+	// Cumulative bucket counts over all recorded values
+	buckets := make([]Bucket, len(DefaultBuckets))
+	var cumulative int64
+	for i, bound := range DefaultBuckets {
+		cumulative += h.buckets[i].Load()
+		buckets[i] = Bucket{UpperBound: bound, Count: cumulative}
+	}
+
+	sum := math.Float64frombits(h.sum.Load())
+	return &HistogramStats{
+		Count:   count,
+		Sum:     sum,
+		Min:     math.Float64frombits(h.min.Load()),
+		Max:     math.Float64frombits(h.max.Load()),
+		Avg:     sum / float64(count),
+		P50:     h.clamp(quantile(counts, total, 0.50)),
+		P90:     h.clamp(quantile(counts, total, 0.90)),
+		P95:     h.clamp(quantile(counts, total, 0.95)),
+		P99:     h.clamp(quantile(counts, total, 0.99)),
+		Buckets: buckets,
+	}
+}
+
+// WindowStats returns statistics over values recorded during roughly the
+// last window (see NewHistogramWithWindow). Min and Max are estimated
+// from bucket bounds; Buckets is not populated.
+func (h *Histogram) WindowStats() *HistogramStats {
+	return h.windowStatsAt(time.Now())
+}
+
+func (h *Histogram) windowStatsAt(now time.Time) *HistogramStats {
+	current := now.UnixNano() / h.slotNanos
+	counts := make([]int64, numBuckets)
+	var count int64
+	var sum float64
+
+	for i := range h.window {
+		slot := &h.window[i]
+		epoch := slot.epoch.Load()
+		if epoch > current || epoch <= current-windowSlots {
+			continue
+		}
+		for b := range slot.counts {
+			counts[b] += int64(slot.counts[b].Load())
+		}
+		count += slot.count.Load()
+		sum += math.Float64frombits(slot.sum.Load())
+	}
+
+	var total int64
+	lo, hi := -1, -1
+	for i, n := range counts {
+		if n == 0 {
+			continue
+		}
+		total += n
+		if lo < 0 {
+			lo = i
+		}
+		hi = i
+	}
+	if total == 0 {
+		return &HistogramStats{}
+	}
 
 	return &HistogramStats{
-		Count: h.count,
-		Sum:   h.sum,
-		Min:   h.min,
-		Max:   h.max,
-		Avg:   h.sum / float64(h.count),
-		P50:   percentile(sorted, 0.50),
-		P90:   percentile(sorted, 0.90),
-		P95:   percentile(sorted, 0.95),
-		P99:   percentile(sorted, 0.99),
+		Count: count,
+		Sum:   sum,
+		Min:   h.clamp(bucketValue(lo)),
+		Max:   h.clamp(bucketValue(hi)),
+		Avg:   sum / float64(count),
+		P50:   h.clamp(quantile(counts, total, 0.50)),
+		P90:   h.clamp(quantile(counts, total, 0.90)),
+		P95:   h.clamp(quantile(counts, total, 0.95)),
+		P99:   h.clamp(quantile(counts, total, 0.99)),
+	}
+}
+
+// clamp bounds an estimate by the exact observed min and max
+func (h *Histogram) clamp(v float64) float64 {
+	min := math.Float64frombits(h.min.Load())
+	max := math.Float64frombits(h.max.Load())
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
 	}
+	return v
+}
+
+// quantile returns the representative value of the bucket holding the
+// q-quantile rank
+func quantile(counts []int64, total int64, q float64) float64 {
+	if total == 0 {
+		return 0
+	}
+	rank := int64(math.Ceil(q * float64(total)))
+	if rank < 1 {
+		rank = 1
+	}
+
+	var cumulative int64
+	for i, n := range counts {
+		cumulative += n
+		if cumulative >= rank {
+			return bucketValue(i)
+		}
+	}
+	return bucketValue(len(counts) - 1)
 }
 
 // HistogramStats holds computed histogram statistics
@@ -86,12 +370,50 @@ type HistogramStats struct {
 	P90   float64
 	P95   float64
 	P99   float64
+
+	// Buckets holds cumulative counts per DefaultBuckets bound
+	// (the +Inf bucket equals Count)
+	Buckets []Bucket
 }
 
-func percentile(sorted []float64, p float64) float64 {
-	if len(sorted) == 0 {
-		return 0
+// Bucket is a cumulative histogram bucket
+type Bucket struct {
+	UpperBound float64
+	Count      int64
+}
+
+// bucketIndex returns the DefaultBuckets slot for value (len = +Inf)
+func bucketIndex(value float64) int {
+	return sort.SearchFloat64s(DefaultBuckets, value)
+}
+
+// =============================================================================
+// Atomic float helpers
+// =============================================================================
+
+func addFloat(a *atomic.Uint64, v float64) {
+	for {
+		old := a.Load()
+		if a.CompareAndSwap(old, math.Float64bits(math.Float64frombits(old)+v)) {
+			return
+		}
+	}
+}
+
+func minFloat(a *atomic.Uint64, v float64) {
+	for {
+		old := a.Load()
+		if v >= math.Float64frombits(old) || a.CompareAndSwap(old, math.Float64bits(v)) {
+			return
+		}
+	}
+}
+
+func maxFloat(a *atomic.Uint64, v float64) {
+	for {
+		old := a.Load()
+		if v <= math.Float64frombits(old) || a.CompareAndSwap(old, math.Float64bits(v)) {
+			return
+		}
 	}
-	idx := int(float64(len(sorted)-1) * p)
-	return sorted[idx]
 }
@@ -3,6 +3,9 @@ package metrics
 
 import (
 	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -112,21 +115,97 @@ func TestHistogram_Stats_Percentiles(t *testing.T) {
 func TestHistogram_LargeDataset(t *testing.T) {
 	h := NewHistogram()
 
-	// Record more than 10000 values (tests truncation)
+	// Record more values than the old sample buffer held
 	for i := 0; i < 15000; i++ {
 		h.Record(float64(i))
 	}
 
 	stats := h.Stats()
 
-	// Should have recorded 15000 values but only keep last 10000
 	if stats.Count != 15000 {
 		t.Errorf("Count = %d, want 15000", stats.Count)
 	}
 
-	// Percentiles should still work on truncated data
-	if stats.P50 <= 0 {
-		t.Error("P50 should be positive")
+	// Percentiles cover every recorded value, not just the most recent ones
+	if math.Abs(stats.P50-7500)/7500 > 0.05 {
+		t.Errorf("P50 = %f, expected around 7500", stats.P50)
+	}
+}
+
+func TestHistogram_Merge(t *testing.T) {
+	a := NewHistogram()
+	b := NewHistogram()
+	for i := 1; i <= 50; i++ {
+		a.Record(float64(i))
+	}
+	for i := 51; i <= 100; i++ {
+		b.Record(float64(i))
+	}
+
+	a.Merge(b)
+	stats := a.Stats()
+	if stats.Count != 100 || stats.Sum != 5050 {
+		t.Errorf("Count/Sum = %d/%f, want 100/5050", stats.Count, stats.Sum)
+	}
+	if stats.Min != 1 || stats.Max != 100 {
+		t.Errorf("Min/Max = %f/%f, want 1/100", stats.Min, stats.Max)
+	}
+	if stats.P50 < 45 || stats.P50 > 55 {
+		t.Errorf("P50 = %f, expected around 50", stats.P50)
+	}
+	if last := stats.Buckets[1]; last.Count != 100 {
+		t.Errorf("le=100 bucket = %d, want 100", last.Count)
+	}
+	if w := a.WindowStats(); w.Count != 100 {
+		t.Errorf("Window Count = %d, want 100", w.Count)
+	}
+
+	a.Merge(nil)
+	a.Merge(a)
+	if a.Stats().Count != 100 {
+		t.Error("Merging nil or self should be a no-op")
+	}
+}
+
+func TestHistogram_WindowStats(t *testing.T) {
+	h := NewHistogramWithWindow(4 * time.Second) // 1s slots
+	base := time.Unix(1000, 0)
+
+	for i := 0; i < 100; i++ {
+		h.recordAt(1000, base)
+	}
+	for i := 0; i < 10; i++ {
+		h.recordAt(10, base.Add(2*time.Second))
+	}
+
+	w := h.windowStatsAt(base.Add(2 * time.Second))
+	if w.Count != 110 {
+		t.Errorf("Window Count = %d, want 110", w.Count)
+	}
+
+	// After the first interval leaves the window only the recent values remain
+	w = h.windowStatsAt(base.Add(5 * time.Second))
+	if w.Count != 10 {
+		t.Fatalf("Window Count = %d, want 10", w.Count)
+	}
+	if math.Abs(w.P99-10) > 1 {
+		t.Errorf("Window P99 = %f, expected around 10", w.P99)
+	}
+
+	// Cumulative stats keep everything
+	if stats := h.Stats(); stats.Count != 110 || stats.P50 < 900 {
+		t.Errorf("Cumulative Count/P50 = %d/%f", stats.Count, stats.P50)
+	}
+
+	// A slot reused for a later interval is reset first
+	h.recordAt(5, base.Add(4*time.Second))
+	w = h.windowStatsAt(base.Add(4 * time.Second))
+	if w.Count != 11 {
+		t.Errorf("Window Count after rotation = %d, want 11", w.Count)
+	}
+
+	if empty := NewHistogram().WindowStats(); empty.Count != 0 {
+		t.Error("Empty window should have zero count")
 	}
 }
 
@@ -160,6 +239,12 @@ func TestHistogram_Concurrent(t *testing.T) {
 	if stats.Count != n {
 		t.Errorf("Count = %d, want %d", stats.Count, n)
 	}
+	if stats.Sum != float64(n*(n-1)/2) {
+		t.Errorf("Sum = %f, want %d", stats.Sum, n*(n-1)/2)
+	}
+	if stats.Buckets[len(stats.Buckets)-1].Count != n {
+		t.Error("Concurrent records lost from buckets")
+	}
 }
 
 func TestHistogram_NegativeValues(t *testing.T) {
@@ -234,36 +319,42 @@ func TestHistogramStats_Structure(t *testing.T) {
 }
 
 // =============================================================================
-// Percentile Function Tests
+// Log-Linear Bucket Tests
 // =============================================================================
 
-func TestPercentile_Empty(t *testing.T) {
-	result := percentile([]float64{}, 0.5)
-	if result != 0 {
-		t.Errorf("percentile(empty) = %f, want 0", result)
+func TestQuantile_Empty(t *testing.T) {
+	if q := quantile(make([]int64, numBuckets), 0, 0.5); q != 0 {
+		t.Errorf("quantile(empty) = %f, want 0", q)
 	}
 }
 
-func TestPercentile_Single(t *testing.T) {
-	result := percentile([]float64{42.0}, 0.5)
-	if result != 42.0 {
-		t.Errorf("percentile([42], 0.5) = %f, want 42.0", result)
+func TestBucketFor_RelativeError(t *testing.T) {
+	for _, v := range []float64{0.001, 0.5, 1, 3, 42, 1000, 123456, 9.9e9} {
+		got := bucketValue(bucketFor(v))
+		if rel := math.Abs(got-v) / v; rel > 1.0/subBuckets {
+			t.Errorf("bucketValue(bucketFor(%g)) = %g, relative error %.3f", v, got, rel)
+		}
 	}
 }
 
-func TestPercentile_Various(t *testing.T) {
-	data := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
-
-	// P0 should be first element
-	p0 := percentile(data, 0.0)
-	if p0 != 1.0 {
-		t.Errorf("P0 = %f, want 1.0", p0)
+func TestBucketFor_Bounds(t *testing.T) {
+	if bucketFor(0) != 0 || bucketFor(-5) != 0 || bucketFor(math.Inf(-1)) != 0 {
+		t.Error("Zero and negative values should use bucket 0")
+	}
+	if bucketFor(1e-30) != 1 {
+		t.Error("Tiny values should clamp to the first positive bucket")
+	}
+	if bucketFor(1e30) != numBuckets-1 || bucketFor(math.Inf(1)) != numBuckets-1 {
+		t.Error("Huge values should clamp to the last bucket")
 	}
 
-	// P100 should be last element (approximately)
-	p100 := percentile(data, 1.0)
-	if p100 != 10.0 {
-		t.Errorf("P100 = %f, want 10.0", p100)
+	prev := -1
+	for v := 1e-6; v < 1e13; v *= 1.01 {
+		idx := bucketFor(v)
+		if idx < prev {
+			t.Fatalf("bucketFor not monotonic at %g", v)
+		}
+		prev = idx
 	}
 }
 
@@ -710,3 +801,120 @@ func TestHistogram_InfNaN(t *testing.T) {
 	// Just verify it doesn't panic and returns something
 	_ = stats
 }
+
+// =============================================================================
+// Prometheus Exposition Tests
+// =============================================================================
+
+func TestHistogram_Buckets(t *testing.T) {
+	h := NewHistogram()
+	h.Record(10)  // <= 50
+	h.Record(50)  // <= 50 (inclusive)
+	h.Record(700) // <= 1000
+	h.Record(2e7) // +Inf only
+	h.Record(math.NaN())
+
+	stats := h.Stats()
+	if len(stats.Buckets) != len(DefaultBuckets) {
+		t.Fatalf("Buckets = %d, want %d", len(stats.Buckets), len(DefaultBuckets))
+	}
+	if stats.Buckets[0].Count != 2 {
+		t.Errorf("le=50 count = %d, want 2", stats.Buckets[0].Count)
+	}
+	last := stats.Buckets[len(stats.Buckets)-1]
+	if last.Count != 3 {
+		t.Errorf("le=%v count = %d, want 3", last.UpperBound, last.Count)
+	}
+	for i := 1; i < len(stats.Buckets); i++ {
+		if stats.Buckets[i].Count < stats.Buckets[i-1].Count {
+			t.Fatal("Bucket counts must be cumulative")
+		}
+	}
+}
+
+func TestLabeled(t *testing.T) {
+	tests := []struct {
+		got  string
+		want string
+	}{
+		{Labeled("requests"), "requests"},
+		{Labeled("requests", "cmd", "QUERY"), `requests{cmd="QUERY"}`},
+		{Labeled("requests", "cmd", "QUERY", "session", "a"), `requests{cmd="QUERY",session="a"}`},
+		{Labeled("requests", "bad-key", `a"b\c`), `requests{bad_key="a\"b\\c"}`},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("Labeled() = %s, want %s", tt.got, tt.want)
+		}
+	}
+}
+
+func TestWritePrometheus(t *testing.T) {
+	c := NewCollector()
+	c.Counter(Labeled("server.commands_total", "cmd", "PING"), 3)
+	c.Counter(Labeled("server.commands_total", "cmd", "QUERY"), 1)
+	c.Gauge("engine.sessions", 2)
+	c.Histogram(Labeled("server.latency_us", "cmd", "PING"), 75)
+
+	var buf strings.Builder
+	if err := WritePrometheus(&buf, c.Snapshot(), "gibram"); err != nil {
+		t.Fatalf("WritePrometheus() error = %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"# TYPE gibram_server_commands_total counter\n",
+		`gibram_server_commands_total{cmd="PING"} 3` + "\n",
+		`gibram_server_commands_total{cmd="QUERY"} 1` + "\n",
+		"# TYPE gibram_engine_sessions gauge\ngibram_engine_sessions 2\n",
+		"# TYPE gibram_server_latency_seconds histogram\n",
+		`gibram_server_latency_seconds_bucket{cmd="PING",le="5e-05"} 0` + "\n",
+		`gibram_server_latency_seconds_bucket{cmd="PING",le="0.0001"} 1` + "\n",
+		`gibram_server_latency_seconds_bucket{cmd="PING",le="+Inf"} 1` + "\n",
+		`gibram_server_latency_seconds_sum{cmd="PING"} 7.5e-05` + "\n",
+		`gibram_server_latency_seconds_count{cmd="PING"} 1` + "\n",
+		"# TYPE gibram_uptime_seconds gauge\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q\n%s", want, out)
+		}
+	}
+
+	// Each family is declared once
+	if n := strings.Count(out, "# TYPE gibram_server_commands_total"); n != 1 {
+		t.Errorf("TYPE line written %d times, want 1", n)
+	}
+}
+
+func TestPrometheusHandler(t *testing.T) {
+	c := NewCollector()
+	c.Counter("requests_total", 1)
+
+	handler := PrometheusHandler(c, "test", func(snap *Snapshot) {
+		snap.Gauges[Labeled("live", "source", "scrape")] = 7
+	})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != PrometheusContentType {
+		t.Errorf("Content-Type = %s", ct)
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, "test_requests_total 1\n") || !strings.Contains(body, `test_live{source="scrape"} 7`) {
+		t.Errorf("unexpected body:\n%s", body)
+	}
+
+	// Scrape-time values are not stored in the collector
+	if c.GetGauge(Labeled("live", "source", "scrape")) != 0 {
+		t.Error("collect callback should not modify the collector")
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/metrics", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST status = %d, want 405", rec.Code)
+	}
+}
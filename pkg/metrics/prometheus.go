@@ -0,0 +1,241 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// Labeled Metric Names
+// =============================================================================
+
+// Labeled returns a metric name carrying Prometheus labels, e.g.
+// Labeled("server.commands_total", "cmd", "QUERY") returns
+// `server.commands_total{cmd="QUERY"}`. Pairs are given as key, value.
+// The result can be used with Counter, Gauge and Histogram like any name.
+func Labeled(name string, kv ...string) string {
+	if len(kv) < 2 {
+		return name
+	}
+
+	var b strings.Builder
+	b.WriteString(name)
+	b.WriteByte('{')
+	for i := 0; i+1 < len(kv); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(sanitizeName(kv[i]))
+		b.WriteString(`="`)
+		b.WriteString(escapeLabelValue(kv[i+1]))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// splitName splits a (possibly labeled) metric name into its base name
+// and label list without braces
+func splitName(name string) (base, labels string) {
+	idx := strings.IndexByte(name, '{')
+	if idx < 0 || !strings.HasSuffix(name, "}") {
+		return name, ""
+	}
+	return name[:idx], name[idx+1 : len(name)-1]
+}
+
+// sanitizeName maps a dotted metric name to a valid Prometheus name
+func sanitizeName(name string) string {
+	var b strings.Builder
+	b.Grow(len(name))
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_', r == ':':
+			b.WriteRune(r)
+		case r >= '0' && r <= '9' && i > 0:
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(v string) string {
+	return labelValueEscaper.Replace(v)
+}
+
+// =============================================================================
+// Prometheus Text Exposition
+// =============================================================================
+
+// metricFamily groups samples that share a base name
+type metricFamily struct {
+	name    string
+	kind    string // counter, gauge, histogram
+	samples []familySample
+}
+
+type familySample struct {
+	labels string
+	value  float64
+	histo  *HistogramStats
+}
+
+// WritePrometheus writes a snapshot in the Prometheus text exposition
+// format (version 0.0.4). Dotted names are converted to underscores and
+// prefixed with namespace. Histograms recorded in microseconds (names
+// ending in _us) are exported in seconds as _seconds. Families are
+// written in sorted order.
+func WritePrometheus(w io.Writer, snap *Snapshot, namespace string) error {
+	families := make(map[string]*metricFamily)
+	add := func(name, kind string, sample familySample) {
+		base, labels := splitName(name)
+		if kind == "histogram" && strings.HasSuffix(base, microsSuffix) {
+			base = strings.TrimSuffix(base, microsSuffix) + "_seconds"
+			sample.histo = histogramSeconds(sample.histo)
+		}
+		full := sanitizeName(base)
+		if namespace != "" {
+			full = sanitizeName(namespace) + "_" + full
+		}
+		fam, ok := families[full]
+		if !ok {
+			fam = &metricFamily{name: full, kind: kind}
+			families[full] = fam
+		}
+		sample.labels = labels
+		fam.samples = append(fam.samples, sample)
+	}
+
+	for name, v := range snap.Counters {
+		add(name, "counter", familySample{value: float64(v)})
+	}
+	for name, v := range snap.Gauges {
+		add(name, "gauge", familySample{value: float64(v)})
+	}
+	for name, h := range snap.Histograms {
+		add(name, "histogram", familySample{histo: h})
+	}
+	add("uptime_seconds", "gauge", familySample{value: snap.Uptime.Seconds()})
+
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	bw := bufio.NewWriter(w)
+	for _, name := range names {
+		fam := families[name]
+		sort.Slice(fam.samples, func(i, j int) bool { return fam.samples[i].labels < fam.samples[j].labels })
+
+		fmt.Fprintf(bw, "# TYPE %s %s\n", fam.name, fam.kind)
+		for _, sample := range fam.samples {
+			if fam.kind == "histogram" {
+				writeHistogram(bw, fam.name, sample.labels, sample.histo)
+				continue
+			}
+			fmt.Fprintf(bw, "%s%s %s\n", fam.name, braced(sample.labels), formatFloat(sample.value))
+		}
+	}
+	return bw.Flush()
+}
+
+// microsSuffix marks histograms recorded in microseconds
+const microsSuffix = "_us"
+
+// histogramSeconds converts the exported fields of a microsecond
+// histogram to seconds
+func histogramSeconds(h *HistogramStats) *HistogramStats {
+	if h == nil {
+		return nil
+	}
+	c := *h
+	c.Sum /= 1e6
+	c.Buckets = make([]Bucket, len(h.Buckets))
+	for i, b := range h.Buckets {
+		c.Buckets[i] = Bucket{UpperBound: b.UpperBound / 1e6, Count: b.Count}
+	}
+	return &c
+}
+
+func writeHistogram(w io.Writer, name, labels string, h *HistogramStats) {
+	if h == nil {
+		h = &HistogramStats{}
+	}
+	for _, b := range h.Buckets {
+		fmt.Fprintf(w, "%s_bucket%s %d\n", name, braced(joinLabels(labels, `le="`+formatFloat(b.UpperBound)+`"`)), b.Count)
+	}
+	fmt.Fprintf(w, "%s_bucket%s %d\n", name, braced(joinLabels(labels, `le="+Inf"`)), h.Count)
+	fmt.Fprintf(w, "%s_sum%s %s\n", name, braced(labels), formatFloat(h.Sum))
+	fmt.Fprintf(w, "%s_count%s %d\n", name, braced(labels), h.Count)
+}
+
+func joinLabels(a, b string) string {
+	if a == "" {
+		return b
+	}
+	return a + "," + b
+}
+
+func braced(labels string) string {
+	if labels == "" {
+		return ""
+	}
+	return "{" + labels + "}"
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// =============================================================================
+// HTTP Handler
+// =============================================================================
+
+// PrometheusContentType is the content type of the text exposition format
+const PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// PrometheusHandler serves the collector in the Prometheus text format.
+// If collect is non-nil it is called on every scrape to add point-in-time
+// values (e.g. engine gauges) to the snapshot. Those values are not
+// stored in the collector, so they disappear once their source is gone.
+func PrometheusHandler(c *Collector, namespace string, collect func(snap *Snapshot)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		snap := c.Snapshot()
+		if collect != nil {
+			collect(snap)
+		}
+
+		w.Header().Set("Content-Type", PrometheusContentType)
+		if err := WritePrometheus(w, snap, namespace); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// DefaultScrapeTimeout bounds how long a metrics HTTP request may take
+const DefaultScrapeTimeout = 10 * time.Second
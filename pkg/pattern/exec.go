@@ -0,0 +1,341 @@
+package pattern
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gibram-io/gibram/pkg/types"
+)
+
+// =============================================================================
+// Executor
+// =============================================================================
+
+// errLimit stops execution once one row past the limit is found
+var errLimit = errors.New("limit reached")
+
+// Run parses, plans and executes a query over g
+func Run(g Graph, src string) (*types.GraphQueryResult, error) {
+	q, err := Parse(src)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := Compile(q, g)
+	if err != nil {
+		return nil, err
+	}
+	return plan.Execute(g)
+}
+
+// Execute runs the plan over g. Rows follow the plan's enumeration order;
+// a relationship is bound at most once per row. Execution fails when it
+// examines more than types.MaxGraphQueryWork candidate bindings.
+func (p *Plan) Execute(g Graph) (*types.GraphQueryResult, error) {
+	return p.execute(g, types.MaxGraphQueryWork)
+}
+
+func (p *Plan) execute(g Graph, maxWork int) (*types.GraphQueryResult, error) {
+	ex := &executor{
+		plan:    p,
+		g:       g,
+		b:       &binding{slots: p.slots, values: make([]any, p.nslots), usedRels: make(map[uint64]bool)},
+		maxWork: maxWork,
+	}
+	if p.distinct {
+		ex.seen = make(map[string]bool)
+	}
+	err := ex.run(0)
+	result := &types.GraphQueryResult{Columns: p.columns, Rows: ex.rows, Plan: p.Describe()}
+	switch {
+	case errors.Is(err, errLimit):
+		result.Rows = result.Rows[:p.limit]
+		result.Truncated = true
+	case err != nil:
+		return nil, err
+	}
+	if result.Rows == nil {
+		result.Rows = [][]types.GraphValue{}
+	}
+	return result, nil
+}
+
+// binding holds the values of the variables bound so far
+type binding struct {
+	slots    map[string]int
+	values   []any // *types.Entity, *types.Relationship or nil
+	usedRels map[uint64]bool
+}
+
+func (b *binding) value(name string) any {
+	if i, ok := b.slots[name]; ok {
+		return b.values[i]
+	}
+	return nil
+}
+
+type executor struct {
+	plan    *Plan
+	g       Graph
+	b       *binding
+	rows    [][]types.GraphValue
+	seen    map[string]bool // DISTINCT row keys
+	work    int
+	maxWork int
+}
+
+// tick counts one candidate binding against the work budget
+func (ex *executor) tick() error {
+	ex.work++
+	if ex.work > ex.maxWork {
+		return fmt.Errorf("query examined more than %d candidates; narrow the pattern or add selective properties", ex.maxWork)
+	}
+	return nil
+}
+
+func (ex *executor) run(i int) error {
+	if i == len(ex.plan.steps) {
+		return ex.emit()
+	}
+	st := ex.plan.steps[i]
+	if st.scan != nil {
+		return ex.scan(i, st)
+	}
+	return ex.expand(i, st)
+}
+
+func (ex *executor) scan(i int, st step) error {
+	node := st.scan.node
+	for _, ent := range ex.candidates(st.scan) {
+		if err := ex.tick(); err != nil {
+			return err
+		}
+		if !node.matches(ent) {
+			continue
+		}
+		ex.b.values[node.slot] = ent
+		if err := ex.filterAndContinue(i, st); err != nil {
+			return err
+		}
+	}
+	ex.b.values[node.slot] = nil
+	return nil
+}
+
+func (ex *executor) expand(i int, st step) error {
+	es := st.expand
+	from := ex.b.values[es.from.slot].(*types.Entity)
+	dir := es.direction()
+
+	try := func(rel *types.Relationship, other uint64) error {
+		if err := ex.tick(); err != nil {
+			return err
+		}
+		if ex.b.usedRels[rel.ID] || !es.edge.matches(rel) {
+			return nil
+		}
+		if es.into {
+			if ex.b.values[es.to.slot].(*types.Entity).ID != other {
+				return nil
+			}
+		} else {
+			ent, ok := ex.g.GetEntity(other)
+			if !ok || !es.to.matches(ent) {
+				return nil
+			}
+			ex.b.values[es.to.slot] = ent
+		}
+		ex.b.values[es.edge.slot] = rel
+		ex.b.usedRels[rel.ID] = true
+		err := ex.filterAndContinue(i, st)
+		delete(ex.b.usedRels, rel.ID)
+		ex.b.values[es.edge.slot] = nil
+		if !es.into {
+			ex.b.values[es.to.slot] = nil
+		}
+		return err
+	}
+
+	if dir != DirLeft {
+		for _, rel := range ex.g.GetOutgoingRelationships(from.ID) {
+			if err := try(rel, rel.TargetID); err != nil {
+				return err
+			}
+		}
+	}
+	if dir != DirRight {
+		for _, rel := range ex.g.GetIncomingRelationships(from.ID) {
+			if dir == DirBoth && rel.SourceID == rel.TargetID {
+				continue // a self-loop was already seen as outgoing
+			}
+			if err := try(rel, rel.SourceID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// filterAndContinue runs the step's filters and, if they pass, the rest
+// of the plan
+func (ex *executor) filterAndContinue(i int, st step) error {
+	for _, f := range st.filters {
+		if !truthy(f.eval(ex.b)) {
+			return nil
+		}
+	}
+	return ex.run(i + 1)
+}
+
+// candidates enumerates the entities a scan step considers, in ID order
+func (ex *executor) candidates(s *scanStep) []*types.Entity {
+	var ents []*types.Entity
+	switch s.seek {
+	case seekID:
+		if ent, ok := ex.g.GetEntity(s.key.(uint64)); ok {
+			ents = append(ents, ent)
+		}
+		return ents
+	case seekTitle:
+		if ent, ok := ex.g.GetEntityByTitle(s.key.(string)); ok {
+			ents = append(ents, ent)
+		}
+		return ents
+	case seekExternalID:
+		if ent, ok := ex.g.GetEntityByExternalID(s.key.(string)); ok {
+			ents = append(ents, ent)
+		}
+		return ents
+	case seekType:
+		for _, l := range s.labels {
+			ents = append(ents, ex.g.GetEntitiesByType(l)...)
+		}
+	default:
+		ents = ex.g.GetAllEntities()
+	}
+	sort.Slice(ents, func(i, j int) bool { return ents[i].ID < ents[j].ID })
+	return ents
+}
+
+// emit projects the current binding into a row
+func (ex *executor) emit() error {
+	row := make([]types.GraphValue, len(ex.plan.returns))
+	for i, e := range ex.plan.returns {
+		row[i] = toGraphValue(e.eval(ex.b))
+	}
+	if ex.seen != nil {
+		key := rowKey(row)
+		if ex.seen[key] {
+			return nil
+		}
+		ex.seen[key] = true
+	}
+	ex.rows = append(ex.rows, row)
+	if len(ex.rows) > ex.plan.limit {
+		return errLimit
+	}
+	return nil
+}
+
+func toGraphValue(v any) types.GraphValue {
+	switch x := v.(type) {
+	case *types.Entity:
+		return types.GraphValue{Entity: x}
+	case *types.Relationship:
+		return types.GraphValue{Relationship: x}
+	case []any:
+		return types.GraphValue{Value: formatLiteral(x)}
+	}
+	return types.GraphValue{Value: v}
+}
+
+// rowKey identifies a row for DISTINCT
+func rowKey(row []types.GraphValue) string {
+	parts := make([]string, len(row))
+	for i, v := range row {
+		switch {
+		case v.Entity != nil:
+			parts[i] = fmt.Sprintf("e%d", v.Entity.ID)
+		case v.Relationship != nil:
+			parts[i] = fmt.Sprintf("r%d", v.Relationship.ID)
+		default:
+			parts[i] = fmt.Sprintf("%T:%s", v.Value, formatLiteral(v.Value))
+		}
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// =============================================================================
+// Pattern Constraints
+// =============================================================================
+
+// matches checks the labels and inline properties of a node
+func (n *nodeSpec) matches(ent *types.Entity) bool {
+	entType := strings.ToLower(ent.Type)
+	for _, labels := range n.labelSets {
+		found := false
+		for _, l := range labels {
+			if l == entType {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for _, pm := range n.props {
+		if !pm.Attr {
+			// Titles are stored uppercased and types compare like labels
+			switch s, _ := pm.Value.(string); pm.Key {
+			case "title":
+				if ent.Title != strings.ToUpper(strings.TrimSpace(s)) {
+					return false
+				}
+				continue
+			case "type":
+				if !strings.EqualFold(ent.Type, s) {
+					return false
+				}
+				continue
+			}
+		}
+		if !propMatches(entityProperty(ent, pm.Key, pm.Attr), pm.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// matches checks the types and inline properties of a relationship
+func (e *edgeSpec) matches(rel *types.Relationship) bool {
+	if len(e.types) > 0 {
+		relType := strings.ToUpper(rel.Type)
+		found := false
+		for _, t := range e.types {
+			if t == relType {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for _, pm := range e.props {
+		if !propMatches(relationshipProperty(rel, pm.Key), pm.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// propMatches compares a property with an inline value; null matches a
+// missing property
+func propMatches(actual, want any) bool {
+	if want == nil || actual == nil {
+		return want == nil && actual == nil
+	}
+	return equal(actual, want)
+}
@@ -0,0 +1,313 @@
+package pattern
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gibram-io/gibram/pkg/types"
+)
+
+// =============================================================================
+// Expressions
+// =============================================================================
+
+// attrsPrefix marks a property as an attribute: a.attrs.sector
+const attrsPrefix = "attrs"
+
+// Expr is a WHERE or RETURN expression. Values are strings, float64
+// numbers, bools, nil, []any lists, *types.Entity and *types.Relationship.
+// Comparisons involving a missing value are false, so NOT of such a
+// comparison is true.
+type Expr interface {
+	eval(b *binding) any
+	vars(add func(string)) // reports the variables the expression reads
+	String() string
+}
+
+type varExpr struct{ name string }
+
+func (e *varExpr) eval(b *binding) any { return b.value(e.name) }
+
+func (e *varExpr) vars(add func(string)) { add(e.name) }
+
+func (e *varExpr) String() string { return e.name }
+
+type propExpr struct {
+	variable string
+	key      string
+	attr     bool
+}
+
+func (e *propExpr) eval(b *binding) any {
+	switch v := b.value(e.variable).(type) {
+	case *types.Entity:
+		return entityProperty(v, e.key, e.attr)
+	case *types.Relationship:
+		return relationshipProperty(v, e.key)
+	}
+	return nil
+}
+
+func (e *propExpr) vars(add func(string)) { add(e.variable) }
+
+func (e *propExpr) String() string {
+	if e.attr {
+		return e.variable + "." + attrsPrefix + "." + e.key
+	}
+	return e.variable + "." + e.key
+}
+
+type literalExpr struct{ value any }
+
+func (e *literalExpr) eval(*binding) any { return e.value }
+
+func (e *literalExpr) vars(func(string)) {}
+
+func (e *literalExpr) String() string { return formatLiteral(e.value) }
+
+type listExpr struct{ values []any }
+
+func (e *listExpr) eval(*binding) any { return e.values }
+
+func (e *listExpr) vars(func(string)) {}
+
+func (e *listExpr) String() string {
+	parts := make([]string, len(e.values))
+	for i, v := range e.values {
+		parts[i] = formatLiteral(v)
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+type logicalExpr struct {
+	op          string // AND, OR
+	left, right Expr
+}
+
+func (e *logicalExpr) eval(b *binding) any {
+	l := truthy(e.left.eval(b))
+	if e.op == "AND" {
+		return l && truthy(e.right.eval(b))
+	}
+	return l || truthy(e.right.eval(b))
+}
+
+func (e *logicalExpr) vars(add func(string)) {
+	e.left.vars(add)
+	e.right.vars(add)
+}
+
+func (e *logicalExpr) String() string {
+	return "(" + e.left.String() + " " + e.op + " " + e.right.String() + ")"
+}
+
+type notExpr struct{ inner Expr }
+
+func (e *notExpr) eval(b *binding) any { return !truthy(e.inner.eval(b)) }
+
+func (e *notExpr) vars(add func(string)) { e.inner.vars(add) }
+
+func (e *notExpr) String() string { return "NOT " + e.inner.String() }
+
+type isNullExpr struct {
+	inner  Expr
+	negate bool
+}
+
+func (e *isNullExpr) eval(b *binding) any { return (e.inner.eval(b) == nil) != e.negate }
+
+func (e *isNullExpr) vars(add func(string)) { e.inner.vars(add) }
+
+func (e *isNullExpr) String() string {
+	if e.negate {
+		return e.inner.String() + " IS NOT NULL"
+	}
+	return e.inner.String() + " IS NULL"
+}
+
+type compareExpr struct {
+	op          string // = <> < <= > >= IN CONTAINS, STARTS WITH, ENDS WITH
+	left, right Expr
+}
+
+func (e *compareExpr) eval(b *binding) any {
+	l, r := e.left.eval(b), e.right.eval(b)
+	if l == nil || r == nil {
+		return false
+	}
+	switch e.op {
+	case "=":
+		return equal(l, r)
+	case "<>":
+		return !equal(l, r)
+	case "IN":
+		list, ok := r.([]any)
+		if !ok {
+			return false
+		}
+		for _, item := range list {
+			if item != nil && equal(l, item) {
+				return true
+			}
+		}
+		return false
+	case "CONTAINS", "STARTS WITH", "ENDS WITH":
+		ls, lok := l.(string)
+		rs, rok := r.(string)
+		if !lok || !rok {
+			return false
+		}
+		switch e.op {
+		case "CONTAINS":
+			return strings.Contains(ls, rs)
+		case "STARTS WITH":
+			return strings.HasPrefix(ls, rs)
+		}
+		return strings.HasSuffix(ls, rs)
+	}
+
+	c, ok := compare(l, r)
+	if !ok {
+		return false
+	}
+	switch e.op {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	}
+	return c >= 0
+}
+
+func (e *compareExpr) vars(add func(string)) {
+	e.left.vars(add)
+	e.right.vars(add)
+}
+
+func (e *compareExpr) String() string {
+	return e.left.String() + " " + e.op + " " + e.right.String()
+}
+
+// =============================================================================
+// Values
+// =============================================================================
+
+// entityProperty returns a builtin field or, for any other key or when
+// attr is set, an attribute of ent; nil when absent
+func entityProperty(ent *types.Entity, key string, attr bool) any {
+	if !attr {
+		switch key {
+		case "id":
+			return float64(ent.ID)
+		case "title":
+			return ent.Title
+		case "type":
+			return ent.Type
+		case "description":
+			return ent.Description
+		case "external_id":
+			return ent.ExternalID
+		}
+	}
+	if v, ok := ent.Attrs[key]; ok {
+		return v
+	}
+	return nil
+}
+
+// relationshipProperty returns a field of rel; nil for unknown keys
+func relationshipProperty(rel *types.Relationship, key string) any {
+	switch key {
+	case "id":
+		return float64(rel.ID)
+	case "type":
+		return rel.Type
+	case "weight":
+		return float64(rel.Weight)
+	case "description":
+		return rel.Description
+	case "external_id":
+		return rel.ExternalID
+	case "source_id":
+		return float64(rel.SourceID)
+	case "target_id":
+		return float64(rel.TargetID)
+	}
+	return nil
+}
+
+func truthy(v any) bool {
+	b, ok := v.(bool)
+	return ok && b
+}
+
+// equal compares two non-nil values. Attributes are strings, so a string
+// equals a number it parses to.
+func equal(l, r any) bool {
+	switch lv := l.(type) {
+	case *types.Entity:
+		rv, ok := r.(*types.Entity)
+		return ok && lv.ID == rv.ID
+	case *types.Relationship:
+		rv, ok := r.(*types.Relationship)
+		return ok && lv.ID == rv.ID
+	case bool:
+		rv, ok := r.(bool)
+		return ok && lv == rv
+	}
+	c, ok := compare(l, r)
+	return ok && c == 0
+}
+
+// compare orders two numbers or two strings, converting a numeric string
+// compared with a number
+func compare(l, r any) (int, bool) {
+	ls, lstr := l.(string)
+	rs, rstr := r.(string)
+	if lstr && rstr {
+		return strings.Compare(ls, rs), true
+	}
+	ln, lok := toNumber(l)
+	rn, rok := toNumber(r)
+	if !lok || !rok {
+		return 0, false
+	}
+	switch {
+	case ln < rn:
+		return -1, true
+	case ln > rn:
+		return 1, true
+	}
+	return 0, true
+}
+
+func toNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func formatLiteral(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return strconv.Quote(x)
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case bool:
+		if x {
+			return "TRUE"
+		}
+		return "FALSE"
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,710 @@
+// Package pattern provides a Cypher-like graph pattern query language
+// executed over a session's entities and relationships
+package pattern
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// =============================================================================
+// Lexer
+// =============================================================================
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokPunct
+)
+
+type token struct {
+	kind   tokenKind
+	text   string // identifier, unquoted string, number or punctuation
+	quoted bool   // identifier written in backticks (never a keyword)
+	pos    int
+}
+
+// twoCharPunct are the punctuation tokens longer than one character
+var twoCharPunct = []string{"<>", "!=", "<=", ">="}
+
+// lex splits a query into tokens
+func lex(src string) ([]token, error) {
+	var toks []token
+	i := 0
+	for i < len(src) {
+		c := rune(src[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '"' || c == '\'':
+			text, n, err := lexString(src[i:])
+			if err != nil {
+				return nil, fmt.Errorf("at offset %d: %w", i, err)
+			}
+			toks = append(toks, token{kind: tokString, text: text, pos: i})
+			i += n
+		case c == '`':
+			end := strings.IndexByte(src[i+1:], '`')
+			if end < 0 {
+				return nil, fmt.Errorf("at offset %d: unterminated backtick identifier", i)
+			}
+			toks = append(toks, token{kind: tokIdent, text: src[i+1 : i+1+end], quoted: true, pos: i})
+			i += end + 2
+		case c >= '0' && c <= '9':
+			start := i
+			for i < len(src) && (src[i] >= '0' && src[i] <= '9' || src[i] == '.' && i+1 < len(src) && src[i+1] >= '0' && src[i+1] <= '9') {
+				i++
+			}
+			toks = append(toks, token{kind: tokNumber, text: src[start:i], pos: start})
+		case isIdentByte(src[i]) && !(c >= '0' && c <= '9'):
+			start := i
+			for i < len(src) && isIdentByte(src[i]) {
+				i++
+			}
+			toks = append(toks, token{kind: tokIdent, text: src[start:i], pos: start})
+		default:
+			punct := string(c)
+			for _, p := range twoCharPunct {
+				if strings.HasPrefix(src[i:], p) {
+					punct = p
+					break
+				}
+			}
+			if !strings.Contains("()[]{}:,.|-<>=*", punct) && len(punct) == 1 {
+				return nil, fmt.Errorf("at offset %d: unexpected character %q", i, c)
+			}
+			toks = append(toks, token{kind: tokPunct, text: punct, pos: i})
+			i += len(punct)
+		}
+	}
+	return append(toks, token{kind: tokEOF, pos: len(src)}), nil
+}
+
+// isIdentByte reports whether b may appear in an identifier. Bytes of
+// multi-byte UTF-8 sequences are accepted so names may be non-ASCII.
+func isIdentByte(b byte) bool {
+	return b == '_' || b >= 0x80 || unicode.IsLetter(rune(b)) || unicode.IsDigit(rune(b))
+}
+
+// lexString reads a quoted string literal, returning its value and length
+func lexString(src string) (string, int, error) {
+	quote := src[0]
+	var b strings.Builder
+	for i := 1; i < len(src); i++ {
+		switch c := src[i]; c {
+		case quote:
+			return b.String(), i + 1, nil
+		case '\\':
+			i++
+			if i == len(src) {
+				break
+			}
+			switch src[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			default:
+				b.WriteByte(src[i])
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string")
+}
+
+// =============================================================================
+// Syntax Tree
+// =============================================================================
+
+// Direction of a relationship pattern as written, left to right
+type Direction int
+
+const (
+	DirBoth  Direction = iota // (a)-[]-(b)
+	DirRight                  // (a)-[]->(b)
+	DirLeft                   // (a)<-[]-(b)
+)
+
+// Query is a parsed pattern query
+type Query struct {
+	Paths    []PathPattern
+	Where    Expr // nil = no filter
+	Distinct bool
+	Return   []ReturnItem // empty with Star set = every named variable
+	Star     bool
+	Limit    int // 0 = not given
+}
+
+// PathPattern is a chain of node patterns joined by relationships.
+// Nodes has one more element than Rels.
+type PathPattern struct {
+	Nodes []NodePattern
+	Rels  []RelPattern
+}
+
+// NodePattern matches one entity: (var:Label|Label {key: value})
+type NodePattern struct {
+	Var    string // empty = anonymous
+	Labels []string
+	Props  []PropMatch
+}
+
+// RelPattern matches one relationship: -[var:TYPE|TYPE {key: value}]->
+type RelPattern struct {
+	Var   string // empty = anonymous
+	Types []string
+	Props []PropMatch
+	Dir   Direction
+}
+
+// PropMatch is an inline property equality
+type PropMatch struct {
+	Key   string
+	Attr  bool // written as attrs.key
+	Value any
+}
+
+// ReturnItem is one projected column
+type ReturnItem struct {
+	Expr  Expr
+	Alias string
+}
+
+// Column returns the column name of the item
+func (r ReturnItem) Column() string {
+	if r.Alias != "" {
+		return r.Alias
+	}
+	return r.Expr.String()
+}
+
+// =============================================================================
+// Parser
+// =============================================================================
+
+// Parse parses a query of the form
+//
+//	MATCH pattern[, pattern...] [WHERE expr] RETURN [DISTINCT] item[, item...] [LIMIT n]
+func Parse(src string) (*Query, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	q, err := p.query()
+	if err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+type parser struct {
+	toks []token
+	pos  int
+}
+
+func (p *parser) peek() token { return p.toks[p.pos] }
+
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+// errorf reports a syntax error at the current token
+func (p *parser) errorf(format string, args ...any) error {
+	t := p.peek()
+	near := t.text
+	if t.kind == tokEOF {
+		near = "end of query"
+	}
+	return fmt.Errorf("syntax error at offset %d near %q: %s", t.pos, near, fmt.Sprintf(format, args...))
+}
+
+// isKeyword reports whether the current token is the keyword kw
+func (p *parser) isKeyword(kw string) bool {
+	t := p.peek()
+	return t.kind == tokIdent && !t.quoted && strings.EqualFold(t.text, kw)
+}
+
+// acceptKeyword consumes the keyword kw if it is next
+func (p *parser) acceptKeyword(kw string) bool {
+	if p.isKeyword(kw) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expectKeyword(kw string) error {
+	if !p.acceptKeyword(kw) {
+		return p.errorf("expected %s", kw)
+	}
+	return nil
+}
+
+func (p *parser) isPunct(s string) bool {
+	t := p.peek()
+	return t.kind == tokPunct && t.text == s
+}
+
+func (p *parser) acceptPunct(s string) bool {
+	if p.isPunct(s) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expectPunct(s string) error {
+	if !p.acceptPunct(s) {
+		return p.errorf("expected %q", s)
+	}
+	return nil
+}
+
+// keywords cannot be used as unquoted names
+var keywords = map[string]bool{
+	"MATCH": true, "WHERE": true, "RETURN": true, "LIMIT": true, "DISTINCT": true,
+	"AND": true, "OR": true, "NOT": true, "AS": true, "IN": true, "IS": true,
+	"NULL": true, "TRUE": true, "FALSE": true, "CONTAINS": true, "STARTS": true,
+	"ENDS": true, "WITH": true,
+}
+
+// name reads an identifier that is not a keyword
+func (p *parser) name(what string) (string, error) {
+	t := p.peek()
+	if t.kind != tokIdent || (!t.quoted && keywords[strings.ToUpper(t.text)]) {
+		return "", p.errorf("expected %s", what)
+	}
+	p.pos++
+	return t.text, nil
+}
+
+func (p *parser) query() (*Query, error) {
+	q := &Query{}
+	if err := p.expectKeyword("MATCH"); err != nil {
+		return nil, err
+	}
+	for {
+		path, err := p.path()
+		if err != nil {
+			return nil, err
+		}
+		q.Paths = append(q.Paths, path)
+		if !p.acceptPunct(",") {
+			break
+		}
+	}
+
+	if p.acceptKeyword("WHERE") {
+		where, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		q.Where = where
+	}
+
+	if err := p.expectKeyword("RETURN"); err != nil {
+		return nil, err
+	}
+	q.Distinct = p.acceptKeyword("DISTINCT")
+	if p.acceptPunct("*") {
+		q.Star = true
+	} else {
+		for {
+			item, err := p.returnItem()
+			if err != nil {
+				return nil, err
+			}
+			q.Return = append(q.Return, item)
+			if !p.acceptPunct(",") {
+				break
+			}
+		}
+	}
+
+	if p.acceptKeyword("LIMIT") {
+		n, err := strconv.Atoi(p.peek().text)
+		if p.peek().kind != tokNumber || err != nil || n <= 0 {
+			return nil, p.errorf("LIMIT must be a positive integer")
+		}
+		p.pos++
+		q.Limit = n
+	}
+	if p.peek().kind != tokEOF {
+		return nil, p.errorf("unexpected input after query")
+	}
+	return q, nil
+}
+
+func (p *parser) path() (PathPattern, error) {
+	var path PathPattern
+	node, err := p.node()
+	if err != nil {
+		return path, err
+	}
+	path.Nodes = append(path.Nodes, node)
+	for p.isPunct("-") || p.isPunct("<") {
+		rel, err := p.rel()
+		if err != nil {
+			return path, err
+		}
+		node, err := p.node()
+		if err != nil {
+			return path, err
+		}
+		path.Rels = append(path.Rels, rel)
+		path.Nodes = append(path.Nodes, node)
+	}
+	return path, nil
+}
+
+func (p *parser) node() (NodePattern, error) {
+	var node NodePattern
+	if err := p.expectPunct("("); err != nil {
+		return node, err
+	}
+	if p.peek().kind == tokIdent {
+		v, err := p.name("variable")
+		if err != nil {
+			return node, err
+		}
+		node.Var = v
+	}
+	if p.acceptPunct(":") {
+		labels, err := p.alternatives("label")
+		if err != nil {
+			return node, err
+		}
+		node.Labels = labels
+	}
+	if p.isPunct("{") {
+		props, err := p.props()
+		if err != nil {
+			return node, err
+		}
+		node.Props = props
+	}
+	return node, p.expectPunct(")")
+}
+
+// rel parses -[...]-, -[...]->, <-[...]- and the bracketless forms
+func (p *parser) rel() (RelPattern, error) {
+	var rel RelPattern
+	left := p.acceptPunct("<")
+	if err := p.expectPunct("-"); err != nil {
+		return rel, err
+	}
+	if p.acceptPunct("[") {
+		if p.peek().kind == tokIdent {
+			v, err := p.name("variable")
+			if err != nil {
+				return rel, err
+			}
+			rel.Var = v
+		}
+		if p.acceptPunct(":") {
+			relTypes, err := p.alternatives("relationship type")
+			if err != nil {
+				return rel, err
+			}
+			rel.Types = relTypes
+		}
+		if p.isPunct("{") {
+			props, err := p.props()
+			if err != nil {
+				return rel, err
+			}
+			rel.Props = props
+		}
+		if err := p.expectPunct("]"); err != nil {
+			return rel, err
+		}
+	}
+	if err := p.expectPunct("-"); err != nil {
+		return rel, err
+	}
+	right := p.acceptPunct(">")
+	switch {
+	case left && right:
+		return rel, p.errorf("relationship cannot point both ways")
+	case left:
+		rel.Dir = DirLeft
+	case right:
+		rel.Dir = DirRight
+	default:
+		rel.Dir = DirBoth
+	}
+	return rel, nil
+}
+
+// alternatives parses name|name|..., allowing a colon before each
+// alternative as Cypher does for relationship types
+func (p *parser) alternatives(what string) ([]string, error) {
+	var names []string
+	for {
+		n, err := p.name(what)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, n)
+		if !p.acceptPunct("|") {
+			return names, nil
+		}
+		p.acceptPunct(":")
+	}
+}
+
+func (p *parser) props() ([]PropMatch, error) {
+	var props []PropMatch
+	if err := p.expectPunct("{"); err != nil {
+		return nil, err
+	}
+	for !p.isPunct("}") {
+		var pm PropMatch
+		if p.peek().kind == tokString {
+			pm.Key = p.next().text
+		} else {
+			key, err := p.name("property name")
+			if err != nil {
+				return nil, err
+			}
+			pm.Key = key
+			if key == attrsPrefix && p.acceptPunct(".") {
+				if pm.Key, err = p.name("attribute name"); err != nil {
+					return nil, err
+				}
+				pm.Attr = true
+			}
+		}
+		if err := p.expectPunct(":"); err != nil {
+			return nil, err
+		}
+		v, err := p.literal()
+		if err != nil {
+			return nil, err
+		}
+		pm.Value = v
+		props = append(props, pm)
+		if !p.acceptPunct(",") {
+			break
+		}
+	}
+	return props, p.expectPunct("}")
+}
+
+func (p *parser) returnItem() (ReturnItem, error) {
+	e, err := p.expr()
+	if err != nil {
+		return ReturnItem{}, err
+	}
+	item := ReturnItem{Expr: e}
+	if p.acceptKeyword("AS") {
+		if item.Alias, err = p.name("column alias"); err != nil {
+			return item, err
+		}
+	}
+	return item, nil
+}
+
+// literal parses a string, number, boolean or null
+func (p *parser) literal() (any, error) {
+	t := p.peek()
+	switch {
+	case t.kind == tokString:
+		p.pos++
+		return t.text, nil
+	case t.kind == tokNumber || (t.kind == tokPunct && t.text == "-"):
+		return p.number()
+	case p.acceptKeyword("TRUE"):
+		return true, nil
+	case p.acceptKeyword("FALSE"):
+		return false, nil
+	case p.acceptKeyword("NULL"):
+		return nil, nil
+	}
+	return nil, p.errorf("expected a literal value")
+}
+
+func (p *parser) number() (float64, error) {
+	sign := 1.0
+	if p.acceptPunct("-") {
+		sign = -1
+	}
+	t := p.peek()
+	if t.kind != tokNumber {
+		return 0, p.errorf("expected a number")
+	}
+	p.pos++
+	f, err := strconv.ParseFloat(t.text, 64)
+	if err != nil {
+		return 0, p.errorf("invalid number %q", t.text)
+	}
+	return sign * f, nil
+}
+
+// expr parses a boolean expression, lowest precedence first:
+// OR, AND, NOT, then comparisons
+func (p *parser) expr() (Expr, error) {
+	left, err := p.andExpr()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("OR") {
+		right, err := p.andExpr()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalExpr{op: "OR", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) andExpr() (Expr, error) {
+	left, err := p.notExpr()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("AND") {
+		right, err := p.notExpr()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalExpr{op: "AND", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) notExpr() (Expr, error) {
+	if p.acceptKeyword("NOT") {
+		inner, err := p.notExpr()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpr{inner: inner}, nil
+	}
+	return p.comparison()
+}
+
+func (p *parser) comparison() (Expr, error) {
+	left, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+
+	t := p.peek()
+	if t.kind == tokPunct {
+		switch t.text {
+		case "=", "<>", "!=", "<", "<=", ">", ">=":
+			p.pos++
+			right, err := p.operand()
+			if err != nil {
+				return nil, err
+			}
+			op := t.text
+			if op == "!=" {
+				op = "<>"
+			}
+			return &compareExpr{op: op, left: left, right: right}, nil
+		}
+	}
+
+	switch {
+	case p.acceptKeyword("CONTAINS"):
+		return p.stringOp("CONTAINS", left)
+	case p.acceptKeyword("STARTS"):
+		if err := p.expectKeyword("WITH"); err != nil {
+			return nil, err
+		}
+		return p.stringOp("STARTS WITH", left)
+	case p.acceptKeyword("ENDS"):
+		if err := p.expectKeyword("WITH"); err != nil {
+			return nil, err
+		}
+		return p.stringOp("ENDS WITH", left)
+	case p.acceptKeyword("IN"):
+		right, err := p.operand()
+		if err != nil {
+			return nil, err
+		}
+		return &compareExpr{op: "IN", left: left, right: right}, nil
+	case p.acceptKeyword("IS"):
+		negate := p.acceptKeyword("NOT")
+		if err := p.expectKeyword("NULL"); err != nil {
+			return nil, err
+		}
+		return &isNullExpr{inner: left, negate: negate}, nil
+	}
+	return left, nil
+}
+
+func (p *parser) stringOp(op string, left Expr) (Expr, error) {
+	right, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	return &compareExpr{op: op, left: left, right: right}, nil
+}
+
+// operand parses a literal, list, variable, property or parenthesized
+// expression
+func (p *parser) operand() (Expr, error) {
+	if p.acceptPunct("(") {
+		inner, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		return inner, p.expectPunct(")")
+	}
+	if p.acceptPunct("[") {
+		list := &listExpr{}
+		for !p.isPunct("]") {
+			v, err := p.literal()
+			if err != nil {
+				return nil, err
+			}
+			list.values = append(list.values, v)
+			if !p.acceptPunct(",") {
+				break
+			}
+		}
+		return list, p.expectPunct("]")
+	}
+
+	t := p.peek()
+	if t.kind == tokIdent && (t.quoted || !keywords[strings.ToUpper(t.text)]) {
+		p.pos++
+		if !p.acceptPunct(".") {
+			return &varExpr{name: t.text}, nil
+		}
+		key, err := p.name("property name")
+		if err != nil {
+			return nil, err
+		}
+		prop := &propExpr{variable: t.text, key: key}
+		if key == attrsPrefix && p.acceptPunct(".") {
+			if prop.key, err = p.name("attribute name"); err != nil {
+				return nil, err
+			}
+			prop.attr = true
+		}
+		return prop, nil
+	}
+
+	v, err := p.literal()
+	if err != nil {
+		return nil, p.errorf("expected a value, variable or property")
+	}
+	return &literalExpr{value: v}, nil
+}
@@ -0,0 +1,283 @@
+// Package pattern provides graph pattern query tests
+package pattern
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gibram-io/gibram/pkg/store"
+	"github.com/gibram-io/gibram/pkg/types"
+)
+
+// newTestGraph builds:
+//
+//	Alice -WORKS_AT-> Bank Indonesia -REGULATES(2)-> Payments
+//	Bob   -WORKS_AT-> OJK            -REGULATES(1)-> Payments
+//	Alice -KNOWS-> Bob, Bank Indonesia -TARGETS-> Inflation
+func newTestGraph(t *testing.T) *store.SessionStore {
+	t.Helper()
+	s := store.NewSessionStore("pattern-test", 8)
+	add := func(title, entType string, attrs map[string]string) uint64 {
+		ent, err := s.AddEntity("", title, entType, title+" description", nil)
+		if err != nil {
+			t.Fatalf("AddEntity(%s) error: %v", title, err)
+		}
+		if !s.SetEntityAttrs(ent.ID, attrs) {
+			t.Fatalf("SetEntityAttrs(%s) failed", title)
+		}
+		return ent.ID
+	}
+	link := func(src, tgt uint64, relType string, weight float32) {
+		if _, err := s.AddRelationship("", src, tgt, relType, "", weight); err != nil {
+			t.Fatalf("AddRelationship(%s) error: %v", relType, err)
+		}
+	}
+
+	alice := add("Alice", "person", map[string]string{"role": "governor", "age": "52"})
+	bob := add("Bob", "person", map[string]string{"age": "41"})
+	bi := add("Bank Indonesia", "organization", map[string]string{"sector": "bank"})
+	ojk := add("OJK", "organization", nil)
+	payments := add("Payments", "concept", nil)
+	inflation := add("Inflation", "concept", nil)
+
+	link(alice, bi, "WORKS_AT", 1)
+	link(bob, ojk, "WORKS_AT", 1)
+	link(bi, payments, "REGULATES", 2)
+	link(ojk, payments, "REGULATES", 1)
+	link(bi, inflation, "TARGETS", 1)
+	link(alice, bob, "KNOWS", 1)
+	return s
+}
+
+// column returns the display values of one column
+func column(res *types.GraphQueryResult, col int) []string {
+	out := make([]string, len(res.Rows))
+	for i, row := range res.Rows {
+		switch v := row[col]; {
+		case v.Entity != nil:
+			out[i] = v.Entity.Title
+		case v.Relationship != nil:
+			out[i] = v.Relationship.Type
+		default:
+			out[i] = formatLiteral(v.Value)
+		}
+	}
+	return out
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, src := range []string{
+		"",
+		"RETURN a",
+		"MATCH (a RETURN a",
+		"MATCH (a)<-[r]->(b) RETURN a",
+		"MATCH (a) WHERE a.title = RETURN a",
+		"MATCH (a) RETURN a LIMIT 0",
+		"MATCH (a) RETURN a extra",
+		`MATCH (a {title: "unterminated}) RETURN a`,
+		"MATCH (match) RETURN match",
+	} {
+		if _, err := Parse(src); err == nil {
+			t.Errorf("Parse(%q) should fail", src)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	g := newTestGraph(t)
+	tests := []struct {
+		name  string
+		query string
+		want  [][]string // columns of the expected rows
+	}{
+		{
+			name:  "organizations regulating a concept",
+			query: "MATCH (o:organization)-[:REGULATES]->(c:Concept) RETURN o, c.title",
+			want:  [][]string{{"BANK INDONESIA", "OJK"}, {`"PAYMENTS"`, `"PAYMENTS"`}},
+		},
+		{
+			name:  "incoming and undirected",
+			query: "MATCH (c {title: 'payments'})<-[:REGULATES]-(o)-[]-(p:person) RETURN DISTINCT p",
+			want:  [][]string{{"ALICE", "BOB"}},
+		},
+		{
+			name:  "where with attrs and boolean logic",
+			query: "MATCH (p:person) WHERE p.age > 45 OR NOT p.attrs.role IS NULL RETURN p",
+			want:  [][]string{{"ALICE"}},
+		},
+		{
+			name:  "string operators and IN",
+			query: `MATCH (e) WHERE e.title STARTS WITH "B" AND e.type IN ["person", "organization"] RETURN e.title`,
+			want:  [][]string{{`"BOB"`, `"BANK INDONESIA"`}},
+		},
+		{
+			name:  "shared variables across patterns",
+			query: "MATCH (a:person)-[:WORKS_AT]->(o), (o)-[:TARGETS]->(c) RETURN a, c",
+			want:  [][]string{{"ALICE"}, {"INFLATION"}},
+		},
+		{
+			name:  "relationships bound once per row",
+			query: "MATCH (a)-[r1]-(b)-[r2]-(a) RETURN a",
+			want:  [][]string{{}},
+		},
+		{
+			name:  "limit",
+			query: "MATCH (e) RETURN e.id LIMIT 2",
+			want:  [][]string{{"1", "2"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Run(g, tt.query)
+			if err != nil {
+				t.Fatalf("Run() error: %v", err)
+			}
+			for col, want := range tt.want {
+				if got := column(res, col); !reflect.DeepEqual(got, want) && !(len(got) == 0 && len(want) == 0) {
+					t.Errorf("column %s = %v, want %v", res.Columns[col], got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRun_PersonToRegulatedConcept(t *testing.T) {
+	g := newTestGraph(t)
+	res, err := Run(g, `MATCH (p:person {role: "governor"})-[:WORKS_AT]->(o)-[r:regulates|targets]->(c)
+		WHERE r.weight >= 1 RETURN p.title AS person, r, c LIMIT 10`)
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if want := []string{"person", "r", "c"}; !reflect.DeepEqual(res.Columns, want) {
+		t.Errorf("Columns = %v, want %v", res.Columns, want)
+	}
+	if got, want := column(res, 1), []string{"REGULATES", "TARGETS"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("relationships = %v, want %v", got, want)
+	}
+	if got, want := column(res, 2), []string{"PAYMENTS", "INFLATION"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("concepts = %v, want %v", got, want)
+	}
+	if res.Truncated {
+		t.Error("result should not be truncated")
+	}
+}
+
+func TestRun_Truncated(t *testing.T) {
+	g := newTestGraph(t)
+	res, err := Run(g, "MATCH (e) RETURN e LIMIT 4")
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if len(res.Rows) != 4 || !res.Truncated {
+		t.Errorf("got %d rows, truncated=%v; want 4 rows, truncated", len(res.Rows), res.Truncated)
+	}
+
+	res, err = Run(g, "MATCH (e) RETURN e LIMIT 6")
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if len(res.Rows) != 6 || res.Truncated {
+		t.Errorf("got %d rows, truncated=%v; want 6 rows, not truncated", len(res.Rows), res.Truncated)
+	}
+}
+
+func TestCompile_Errors(t *testing.T) {
+	g := newTestGraph(t)
+	for _, src := range []string{
+		"MATCH (a) RETURN b",
+		"MATCH (a) WHERE b.title = 'x' RETURN a",
+		"MATCH (a)-[a]->(b) RETURN a",
+		"MATCH (a)-[r]->(b), (c)-[r]->(d) RETURN r",
+		"MATCH (a)-[r]->(b), (r) RETURN r",
+		"MATCH () RETURN *",
+		"MATCH (a) RETURN a LIMIT 100000",
+	} {
+		q, err := Parse(src)
+		if err != nil {
+			t.Fatalf("Parse(%q) error: %v", src, err)
+		}
+		if _, err := Compile(q, g); err == nil {
+			t.Errorf("Compile(%q) should fail", src)
+		}
+	}
+}
+
+func TestCompile_Plan(t *testing.T) {
+	g := newTestGraph(t)
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{
+			// Starts from the title lookup, not the larger type index
+			query: `MATCH (p:person)-[r:WORKS_AT]->(o:organization) WHERE o.title = "OJK" AND p.age > 30 RETURN p`,
+			want: []string{
+				`NodeByTitle(o) title="OJK" est=1`,
+				"Filter(o.title = \"OJK\")",
+				"Expand(o)<-[r:WORKS_AT]-(p)",
+				"Filter(p.age > 30)",
+				"Project(p)",
+				"Limit(100)",
+			},
+		},
+		{
+			// Concepts and persons tie (2) and come before unlabelled o (6);
+			// the closing edge is only checked
+			query: "MATCH (c:concept)<-[:REGULATES]-(o)<-[:WORKS_AT]-(p:person), (p)-[k:KNOWS]-(c) RETURN DISTINCT o",
+			want: []string{
+				"NodeByType(c:concept) est=2",
+				"Expand(c)-[k:KNOWS]-(p)",
+				"Expand(c)<-[1r:REGULATES]-(o)",
+				"ExpandInto(o)<-[2r:WORKS_AT]-(p)",
+				"Distinct",
+				"Project(o)",
+				"Limit(100)",
+			},
+		},
+		{
+			query: "MATCH (a {id: 3}), (b) RETURN * LIMIT 5",
+			want: []string{
+				"NodeByID(a) id=3 est=1",
+				"AllNodesScan(b) est=6",
+				"Project(a, b)",
+				"Limit(5)",
+			},
+		},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.query)
+		if err != nil {
+			t.Fatalf("Parse(%q) error: %v", tt.query, err)
+		}
+		plan, err := Compile(q, g)
+		if err != nil {
+			t.Fatalf("Compile(%q) error: %v", tt.query, err)
+		}
+		if got := plan.Describe(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Describe(%q) =\n%s\nwant\n%s", tt.query, strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+		}
+	}
+}
+
+func TestExecute_WorkBudget(t *testing.T) {
+	g := newTestGraph(t)
+	q, err := Parse("MATCH (a), (b), (c) RETURN a, b, c LIMIT 10000")
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	plan, err := Compile(q, g)
+	if err != nil {
+		t.Fatalf("Compile() error: %v", err)
+	}
+	if _, err := plan.execute(g, 50); err == nil {
+		t.Error("execute() should fail when the work budget is exceeded")
+	}
+	res, err := plan.execute(g, 1000)
+	if err != nil {
+		t.Fatalf("execute() error: %v", err)
+	}
+	if len(res.Rows) != 216 {
+		t.Errorf("got %d rows, want 6^3", len(res.Rows))
+	}
+}
@@ -0,0 +1,481 @@
+package pattern
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gibram-io/gibram/pkg/types"
+)
+
+// =============================================================================
+// Query Planner
+// =============================================================================
+
+// Graph is the data a query runs over. *store.SessionStore implements it.
+type Graph interface {
+	GetEntity(id uint64) (*types.Entity, bool)
+	GetEntityByTitle(title string) (*types.Entity, bool)
+	GetEntityByExternalID(extID string) (*types.Entity, bool)
+	GetEntitiesByType(entType string) []*types.Entity
+	EntityTypeCount(entType string) int
+	GetAllEntities() []*types.Entity
+	EntityCount() int
+	GetOutgoingRelationships(entityID uint64) []*types.Relationship
+	GetIncomingRelationships(entityID uint64) []*types.Relationship
+}
+
+// nodeSpec gathers the constraints of every occurrence of a node variable
+type nodeSpec struct {
+	name      string
+	slot      int
+	labelSets [][]string // lowercased; the entity type must be in every set
+	props     []PropMatch
+}
+
+// edgeSpec is one relationship pattern between two node variables
+type edgeSpec struct {
+	name        string
+	slot        int
+	left, right *nodeSpec
+	dir         Direction
+	types       []string // uppercased alternatives; empty = any type
+	props       []PropMatch
+}
+
+type seekKind int
+
+const (
+	seekAll seekKind = iota
+	seekID
+	seekTitle
+	seekExternalID
+	seekType
+)
+
+// scanStep binds a node from an index or a full scan
+type scanStep struct {
+	node     *nodeSpec
+	seek     seekKind
+	key      any      // id (uint64), title or external ID
+	labels   []string // seekType
+	estimate int
+}
+
+// expandStep binds an edge, and its far node unless into is set, from the
+// adjacency lists of an already bound node
+type expandStep struct {
+	edge     *edgeSpec
+	from, to *nodeSpec
+	into     bool
+}
+
+type step struct {
+	scan    *scanStep
+	expand  *expandStep
+	filters []Expr // WHERE conjuncts whose variables are all bound here
+}
+
+// Plan is a compiled query ready to execute
+type Plan struct {
+	steps    []step
+	slots    map[string]int
+	nslots   int
+	columns  []string
+	returns  []Expr
+	distinct bool
+	limit    int
+}
+
+// Compile validates q and orders its patterns for execution over g. Each
+// connected pattern starts from its most selective node, looked up by id,
+// title or external_id when one is fixed, otherwise by the type index,
+// otherwise by a full scan, and grows through the adjacency lists towards
+// the most selective unbound node. WHERE conjuncts run as soon as their
+// variables are bound.
+func Compile(q *Query, g Graph) (*Plan, error) {
+	c := &compiler{nodes: make(map[string]*nodeSpec), slots: make(map[string]int)}
+	if err := c.collect(q); err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{slots: c.slots, nslots: len(c.slots), distinct: q.Distinct, limit: q.Limit}
+	if plan.limit == 0 {
+		plan.limit = types.DefaultGraphQueryLimit
+	}
+	if plan.limit > types.MaxGraphQueryLimit {
+		return nil, fmt.Errorf("LIMIT %d exceeds maximum %d", plan.limit, types.MaxGraphQueryLimit)
+	}
+
+	var conjuncts []Expr
+	if q.Where != nil {
+		if err := c.checkVars(q.Where, "WHERE"); err != nil {
+			return nil, err
+		}
+		conjuncts = splitAnd(q.Where, nil)
+	}
+
+	if q.Star {
+		for _, name := range c.named {
+			plan.columns = append(plan.columns, name)
+			plan.returns = append(plan.returns, &varExpr{name: name})
+		}
+		if len(plan.returns) == 0 {
+			return nil, fmt.Errorf("RETURN * needs at least one named variable")
+		}
+	}
+	for _, item := range q.Return {
+		if err := c.checkVars(item.Expr, "RETURN"); err != nil {
+			return nil, err
+		}
+		plan.columns = append(plan.columns, item.Column())
+		plan.returns = append(plan.returns, item.Expr)
+	}
+
+	plan.steps = c.order(g, conjuncts)
+	plan.pushDown(conjuncts)
+	return plan, nil
+}
+
+// compiler collects variables from the MATCH patterns
+type compiler struct {
+	nodes     map[string]*nodeSpec
+	nodeOrder []*nodeSpec
+	edges     []*edgeSpec
+	slots     map[string]int
+	named     []string // user variables in order of appearance
+	anonymous int
+}
+
+func (c *compiler) collect(q *Query) error {
+	edgeNames := make(map[string]bool)
+	for _, path := range q.Paths {
+		nodes := make([]*nodeSpec, len(path.Nodes))
+		for i, np := range path.Nodes {
+			name := np.Var
+			if name == "" {
+				name = c.anonymousName("n")
+			} else if edgeNames[name] {
+				return fmt.Errorf("variable %s is used for both a relationship and a node", name)
+			}
+			node, ok := c.nodes[name]
+			if !ok {
+				node = &nodeSpec{name: name, slot: c.slot(name, np.Var != "")}
+				c.nodes[name] = node
+				c.nodeOrder = append(c.nodeOrder, node)
+			}
+			if len(np.Labels) > 0 {
+				labels := make([]string, 0, len(np.Labels))
+				for _, l := range np.Labels {
+					labels = appendUnique(labels, strings.ToLower(l))
+				}
+				node.labelSets = append(node.labelSets, labels)
+			}
+			node.props = append(node.props, np.Props...)
+			nodes[i] = node
+		}
+
+		for i, rp := range path.Rels {
+			name := rp.Var
+			if name == "" {
+				name = c.anonymousName("r")
+			} else if _, ok := c.nodes[name]; ok || edgeNames[name] {
+				return fmt.Errorf("relationship variable %s is already defined", name)
+			}
+			edgeNames[name] = true
+			edge := &edgeSpec{
+				name:  name,
+				slot:  c.slot(name, rp.Var != ""),
+				left:  nodes[i],
+				right: nodes[i+1],
+				dir:   rp.Dir,
+				props: rp.Props,
+			}
+			for _, t := range rp.Types {
+				edge.types = appendUnique(edge.types, strings.ToUpper(t))
+			}
+			c.edges = append(c.edges, edge)
+		}
+	}
+	for name := range edgeNames {
+		if _, ok := c.nodes[name]; ok {
+			return fmt.Errorf("variable %s is used for both a relationship and a node", name)
+		}
+	}
+	return nil
+}
+
+// anonymousName names an unnamed pattern element; user names cannot start
+// with a digit, so these never clash
+func (c *compiler) anonymousName(prefix string) string {
+	c.anonymous++
+	return strconv.Itoa(c.anonymous) + prefix
+}
+
+func (c *compiler) slot(name string, named bool) int {
+	if named {
+		c.named = append(c.named, name)
+	}
+	c.slots[name] = len(c.slots)
+	return c.slots[name]
+}
+
+// checkVars reports a variable of e missing from MATCH
+func (c *compiler) checkVars(e Expr, clause string) error {
+	var missing string
+	e.vars(func(name string) {
+		if _, ok := c.slots[name]; !ok && missing == "" {
+			missing = name
+		}
+	})
+	if missing != "" {
+		return fmt.Errorf("variable %s in %s is not defined in MATCH", missing, clause)
+	}
+	return nil
+}
+
+// order chooses the scans and expansions that bind every variable
+func (c *compiler) order(g Graph, conjuncts []Expr) []step {
+	scans := make(map[*nodeSpec]*scanStep, len(c.nodeOrder))
+	for _, node := range c.nodeOrder {
+		scans[node] = chooseScan(node, conjuncts, g)
+	}
+
+	bound := make(map[*nodeSpec]bool, len(c.nodeOrder))
+	done := make([]bool, len(c.edges))
+	var steps []step
+	for {
+		// Expand-into first: it only checks, never multiplies rows
+		next, nextScore := -1, 0
+		for i, edge := range c.edges {
+			if done[i] {
+				continue
+			}
+			var score int
+			switch {
+			case bound[edge.left] && bound[edge.right]:
+				score = -1
+			case bound[edge.left]:
+				score = scans[edge.right].estimate
+			case bound[edge.right]:
+				score = scans[edge.left].estimate
+			default:
+				continue
+			}
+			if next < 0 || score < nextScore {
+				next, nextScore = i, score
+			}
+		}
+		if next >= 0 {
+			edge := c.edges[next]
+			done[next] = true
+			ex := &expandStep{edge: edge, from: edge.left, to: edge.right}
+			if !bound[edge.left] {
+				ex.from, ex.to = edge.right, edge.left
+			}
+			ex.into = bound[ex.to]
+			bound[ex.to] = true
+			steps = append(steps, step{expand: ex})
+			continue
+		}
+
+		// Nothing reachable: start the cheapest unbound pattern
+		var best *scanStep
+		for _, node := range c.nodeOrder {
+			if !bound[node] && (best == nil || scans[node].estimate < best.estimate) {
+				best = scans[node]
+			}
+		}
+		if best == nil {
+			return steps
+		}
+		bound[best.node] = true
+		steps = append(steps, step{scan: best})
+	}
+}
+
+// chooseScan picks the cheapest way to enumerate candidates for node
+func chooseScan(node *nodeSpec, conjuncts []Expr, g Graph) *scanStep {
+	var fixed []PropMatch
+	for _, pm := range node.props {
+		if !pm.Attr {
+			fixed = append(fixed, pm)
+		}
+	}
+	for _, e := range conjuncts {
+		if pm, ok := equalityOn(e, node.name); ok {
+			fixed = append(fixed, pm)
+		}
+	}
+
+	for _, kind := range []seekKind{seekID, seekTitle, seekExternalID} {
+		for _, pm := range fixed {
+			if key, ok := seekKey(kind, pm); ok {
+				return &scanStep{node: node, seek: kind, key: key, estimate: 1}
+			}
+		}
+	}
+
+	labelSets := node.labelSets
+	for _, pm := range fixed {
+		if s, ok := pm.Value.(string); ok && pm.Key == "type" {
+			labelSets = append(labelSets, []string{strings.ToLower(s)})
+		}
+	}
+	best := &scanStep{node: node, seek: seekAll, estimate: g.EntityCount()}
+	for _, labels := range labelSets {
+		n := 0
+		for _, l := range labels {
+			n += g.EntityTypeCount(l)
+		}
+		if n < best.estimate || best.seek == seekAll {
+			best = &scanStep{node: node, seek: seekType, labels: labels, estimate: n}
+		}
+	}
+	return best
+}
+
+// seekKey returns the lookup key of pm for an index seek of kind
+func seekKey(kind seekKind, pm PropMatch) (any, bool) {
+	switch kind {
+	case seekID:
+		if f, ok := pm.Value.(float64); ok && pm.Key == "id" && f >= 0 && f == float64(uint64(f)) {
+			return uint64(f), true
+		}
+	case seekTitle:
+		if s, ok := pm.Value.(string); ok && pm.Key == "title" {
+			return s, true
+		}
+	case seekExternalID:
+		if s, ok := pm.Value.(string); ok && pm.Key == "external_id" {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+// equalityOn matches a conjunct of the form variable.key = literal
+func equalityOn(e Expr, variable string) (PropMatch, bool) {
+	cmp, ok := e.(*compareExpr)
+	if !ok || cmp.op != "=" {
+		return PropMatch{}, false
+	}
+	prop, pok := cmp.left.(*propExpr)
+	lit, lok := cmp.right.(*literalExpr)
+	if !pok || !lok {
+		prop, pok = cmp.right.(*propExpr)
+		lit, lok = cmp.left.(*literalExpr)
+	}
+	if !pok || !lok || prop.variable != variable || prop.attr {
+		return PropMatch{}, false
+	}
+	return PropMatch{Key: prop.key, Value: lit.value}, true
+}
+
+// splitAnd flattens the top-level AND chain of e
+func splitAnd(e Expr, out []Expr) []Expr {
+	if l, ok := e.(*logicalExpr); ok && l.op == "AND" {
+		return splitAnd(l.right, splitAnd(l.left, out))
+	}
+	return append(out, e)
+}
+
+// pushDown attaches each conjunct to the first step binding all of its
+// variables
+func (p *Plan) pushDown(conjuncts []Expr) {
+	boundAt := make(map[string]int, p.nslots)
+	for i, st := range p.steps {
+		if st.scan != nil {
+			boundAt[st.scan.node.name] = i
+		} else {
+			boundAt[st.expand.edge.name] = i
+			if !st.expand.into {
+				boundAt[st.expand.to.name] = i
+			}
+		}
+	}
+	for _, e := range conjuncts {
+		at := 0
+		e.vars(func(name string) { at = max(at, boundAt[name]) })
+		p.steps[at].filters = append(p.steps[at].filters, e)
+	}
+}
+
+// Columns returns the result column names
+func (p *Plan) Columns() []string { return p.columns }
+
+// Describe returns one line per plan operation
+func (p *Plan) Describe() []string {
+	var lines []string
+	for _, st := range p.steps {
+		if st.scan != nil {
+			lines = append(lines, st.scan.describe())
+		} else {
+			lines = append(lines, st.expand.describe())
+		}
+		for _, f := range st.filters {
+			lines = append(lines, "Filter("+f.String()+")")
+		}
+	}
+	if p.distinct {
+		lines = append(lines, "Distinct")
+	}
+	lines = append(lines, "Project("+strings.Join(p.columns, ", ")+")")
+	lines = append(lines, fmt.Sprintf("Limit(%d)", p.limit))
+	return lines
+}
+
+func (s *scanStep) describe() string {
+	switch s.seek {
+	case seekID:
+		return fmt.Sprintf("NodeByID(%s) id=%d est=1", s.node.name, s.key)
+	case seekTitle:
+		return fmt.Sprintf("NodeByTitle(%s) title=%q est=1", s.node.name, s.key)
+	case seekExternalID:
+		return fmt.Sprintf("NodeByExternalID(%s) external_id=%q est=1", s.node.name, s.key)
+	case seekType:
+		return fmt.Sprintf("NodeByType(%s:%s) est=%d", s.node.name, strings.Join(s.labels, "|"), s.estimate)
+	}
+	return fmt.Sprintf("AllNodesScan(%s) est=%d", s.node.name, s.estimate)
+}
+
+func (ex *expandStep) describe() string {
+	op := "Expand"
+	if ex.into {
+		op = "ExpandInto"
+	}
+	rel := ex.edge.name
+	if len(ex.edge.types) > 0 {
+		rel += ":" + strings.Join(ex.edge.types, "|")
+	}
+	var arrow string
+	switch ex.direction() {
+	case DirRight:
+		arrow = "-[" + rel + "]->"
+	case DirLeft:
+		arrow = "<-[" + rel + "]-"
+	default:
+		arrow = "-[" + rel + "]-"
+	}
+	return fmt.Sprintf("%s(%s)%s(%s)", op, ex.from.name, arrow, ex.to.name)
+}
+
+// direction returns the edge direction seen from ex.from
+func (ex *expandStep) direction() Direction {
+	if ex.from == ex.edge.left || ex.edge.dir == DirBoth {
+		return ex.edge.dir
+	}
+	if ex.edge.dir == DirRight {
+		return DirLeft
+	}
+	return DirRight
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, x := range list {
+		if x == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
@@ -0,0 +1,243 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gibram-io/gibram/pkg/config"
+	"github.com/gibram-io/gibram/pkg/logging"
+	"github.com/gibram-io/gibram/pkg/memory"
+	"github.com/gibram-io/gibram/pkg/types"
+)
+
+// =============================================================================
+// Readiness
+// =============================================================================
+
+// Readiness reasons reported by /readyz
+const (
+	ReadyOK         = "ready"
+	ReadyStarting   = "starting"
+	ReadyRecovering = "recovering"
+	ReadyDraining   = "draining"
+)
+
+// BeginRecovery marks the server not ready while a snapshot restore or WAL
+// replay runs. The returned function ends the recovery; it is safe to call
+// more than once.
+func (s *Server) BeginRecovery() func() {
+	s.recovering.Add(1)
+	var once sync.Once
+	return func() {
+		once.Do(func() { s.recovering.Add(-1) })
+	}
+}
+
+// Drain marks the server not ready so load balancers stop routing new
+// clients. Existing connections are served until Stop.
+func (s *Server) Drain() {
+	s.draining.Store(true)
+}
+
+// Readiness reports whether the server should receive traffic and why not
+func (s *Server) Readiness() (bool, string) {
+	switch {
+	case s.draining.Load():
+		return false, ReadyDraining
+	case s.recovering.Load() > 0:
+		return false, ReadyRecovering
+	case !s.started.Load():
+		return false, ReadyStarting
+	}
+	return true, ReadyOK
+}
+
+// SetMemoryTracker sets the memory tracker reported by the admin status
+func (s *Server) SetMemoryTracker(tracker *memory.Tracker) {
+	s.memTracker = tracker
+}
+
+// =============================================================================
+// Admin HTTP Listener
+// =============================================================================
+
+// StartAdmin starts the admin HTTP listener. /healthz and /readyz are open;
+// /debug/pprof/ and /admin/status require an admin API key (when auth is
+// enabled) passed as "Authorization: Bearer <key>" or "X-API-Key: <key>".
+// The listener is stopped by Stop.
+func (s *Server) StartAdmin(addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", s.handleHealthz)
+	mux.HandleFunc("/readyz", s.handleReadyz)
+	mux.Handle("/admin/status", s.requireAdmin(http.HandlerFunc(s.handleAdminStatus)))
+	mux.Handle("/debug/pprof/", s.requireAdmin(http.HandlerFunc(pprof.Index)))
+	mux.Handle("/debug/pprof/cmdline", s.requireAdmin(http.HandlerFunc(pprof.Cmdline)))
+	mux.Handle("/debug/pprof/profile", s.requireAdmin(http.HandlerFunc(pprof.Profile)))
+	mux.Handle("/debug/pprof/symbol", s.requireAdmin(http.HandlerFunc(pprof.Symbol)))
+	mux.Handle("/debug/pprof/trace", s.requireAdmin(http.HandlerFunc(pprof.Trace)))
+
+	// No write timeout: CPU profiles and traces stream for their duration
+	s.adminHTTP = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	s.adminAddr = ln.Addr().String()
+
+	go func() {
+		if err := s.adminHTTP.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logging.Error("Admin listener error: %v", err)
+		}
+	}()
+
+	logging.Info("  Admin: http://%s (healthz, readyz, pprof)", s.adminAddr)
+	return nil
+}
+
+// AdminAddr returns the bound address of the admin listener, if any
+func (s *Server) AdminAddr() string {
+	return s.adminAddr
+}
+
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write([]byte("ok\n"))
+}
+
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	ready, reason := s.Readiness()
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if !ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_, _ = w.Write([]byte(reason + "\n"))
+}
+
+// requireAdmin wraps a handler with an admin API key check. Every attempt
+// is charged against the per-IP AUTH limiter, as bcrypt validation is
+// deliberately slow and must not become a brute-force oracle.
+func (s *Server) requireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.apiKeyStore == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ip := r.RemoteAddr
+		if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+			ip = host
+		}
+		if !s.ipLimits.allowAuth(ip, s.authRate, s.authBurst) {
+			s.countRejection(MetricAuthLimitRejected)
+			http.Error(w, "too many authentication attempts", http.StatusTooManyRequests)
+			return
+		}
+
+		key := requestAPIKey(r)
+		if key == "" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="gibram-admin"`)
+			http.Error(w, "authentication required", http.StatusUnauthorized)
+			return
+		}
+		apiKey, err := s.apiKeyStore.Validate(key)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if !apiKey.HasPermission(config.PermAdmin) {
+			http.Error(w, "permission denied: requires 'admin' permission", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requestAPIKey extracts an API key from the Authorization or X-API-Key header
+func requestAPIKey(r *http.Request) string {
+	const prefix = "bearer "
+	if auth := r.Header.Get("Authorization"); len(auth) > len(prefix) && strings.EqualFold(auth[:len(prefix)], prefix) {
+		return strings.TrimSpace(auth[len(prefix):])
+	}
+	return r.Header.Get("X-API-Key")
+}
+
+// =============================================================================
+// Admin Status
+// =============================================================================
+
+// AdminStatus is the JSON document served at /admin/status
+type AdminStatus struct {
+	Ready         bool             `json:"ready"`
+	ReadyReason   string           `json:"ready_reason"`
+	UptimeSeconds int64            `json:"uptime_seconds"`
+	Connections   int64            `json:"connections"`
+	Info          types.ServerInfo `json:"info"`
+	Backup        BackupStatus     `json:"backup"`
+	Memory        *MemoryStatus    `json:"memory,omitempty"`
+}
+
+// BackupStatus mirrors the BACKUP_STATUS command response
+type BackupStatus struct {
+	InProgress   bool   `json:"in_progress"`
+	Type         string `json:"type,omitempty"`
+	StartTime    int64  `json:"start_time,omitempty"`
+	LastSaveTime int64  `json:"last_save_time,omitempty"`
+	LastSavePath string `json:"last_save_path,omitempty"`
+}
+
+// MemoryStatus holds the memory tracker's last observation
+type MemoryStatus struct {
+	MaxBytes    int64  `json:"max_bytes"`
+	AllocBytes  uint64 `json:"alloc_bytes"`
+	SysBytes    uint64 `json:"sys_bytes"`
+	HeapObjects uint64 `json:"heap_objects"`
+	NumGC       uint32 `json:"num_gc"`
+	LastCheck   int64  `json:"last_check,omitempty"` // unix seconds
+}
+
+// adminStatus assembles the current admin status
+func (s *Server) adminStatus() AdminStatus {
+	ready, reason := s.Readiness()
+	status := AdminStatus{
+		Ready:         ready,
+		ReadyReason:   reason,
+		UptimeSeconds: int64(time.Since(s.startTime).Seconds()),
+		Connections:   s.activeConns.Load(),
+		Info:          s.engine.Info(),
+		Backup:        s.backupStatus(),
+	}
+
+	if s.memTracker != nil {
+		stats, lastCheck := s.memTracker.GetStats()
+		status.Memory = &MemoryStatus{
+			MaxBytes:    s.memTracker.MaxBytes(),
+			AllocBytes:  stats.Alloc,
+			SysBytes:    stats.Sys,
+			HeapObjects: stats.HeapObjects,
+			NumGC:       stats.NumGC,
+		}
+		if !lastCheck.IsZero() {
+			status.Memory.LastCheck = lastCheck.Unix()
+		}
+	}
+	return status
+}
+
+func (s *Server) handleAdminStatus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(s.adminStatus()); err != nil {
+		logging.Error("Admin status encode error: %v", err)
+	}
+}
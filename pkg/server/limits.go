@@ -0,0 +1,372 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gibram-io/gibram/pkg/config"
+	pb "github.com/gibram-io/gibram/proto/gibrampb"
+	"golang.org/x/time/rate"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// =============================================================================
+// Command Cost Weights
+// =============================================================================
+
+// Cost units charged against a key's rate limiter. Commands not listed
+// cost one unit; batch commands cost one unit per item.
+const (
+	costDefault   = 1
+	costQuery     = 5
+	costList      = 5
+	costBackup    = 20
+	costGraph     = 20
+	costCommunity = 50
+	costRebuild   = 50
+)
+
+// commandCosts holds fixed weights for commands that are expensive
+// independent of their payload size
+var commandCosts = map[pb.CommandType]int{
+	pb.CommandType_CMD_QUERY:               costQuery,
+	pb.CommandType_CMD_PATH:                costGraph,
+	pb.CommandType_CMD_SUBGRAPH:            costGraph,
+	pb.CommandType_CMD_QUERY_GRAPH:         costGraph,
+	pb.CommandType_CMD_LIST_ENTITIES:       costList,
+	pb.CommandType_CMD_LIST_RELATIONSHIPS:  costList,
+	pb.CommandType_CMD_SAVE:                costBackup,
+	pb.CommandType_CMD_BGSAVE:              costBackup,
+	pb.CommandType_CMD_BGRESTORE:           costBackup,
+	pb.CommandType_CMD_COMPUTE_COMMUNITIES: costCommunity,
+	pb.CommandType_CMD_HIERARCHICAL_LEIDEN: costCommunity,
+	pb.CommandType_CMD_GLOBAL_SEARCH:       costCommunity,
+	pb.CommandType_CMD_REBUILD_INDEX:       costRebuild,
+	pb.CommandType_CMD_RECALL_CHECK:        costRebuild,
+}
+
+// itemCost weights a batch command by the elements of its repeated fields
+type itemCost struct {
+	cost   int                // units per element
+	fields []protowire.Number // repeated fields counted
+	packed bool               // fields are packed varints (ID lists)
+}
+
+// itemCosts holds the weights of batch commands
+var itemCosts = map[pb.CommandType]itemCost{
+	pb.CommandType_CMD_MSET_ENTITIES:      {cost: costDefault, fields: []protowire.Number{1}},
+	pb.CommandType_CMD_MSET_DOCUMENTS:     {cost: costDefault, fields: []protowire.Number{1}},
+	pb.CommandType_CMD_MSET_TEXTUNITS:     {cost: costDefault, fields: []protowire.Number{1}},
+	pb.CommandType_CMD_MSET_RELATIONSHIPS: {cost: costDefault, fields: []protowire.Number{1}},
+	pb.CommandType_CMD_MGET_ENTITIES:      {cost: costDefault, fields: []protowire.Number{1}, packed: true},
+	pb.CommandType_CMD_MGET_DOCUMENTS:     {cost: costDefault, fields: []protowire.Number{1}, packed: true},
+	pb.CommandType_CMD_MGET_TEXTUNITS:     {cost: costDefault, fields: []protowire.Number{1}, packed: true},
+	pb.CommandType_CMD_MGET_RELATIONSHIPS: {cost: costDefault, fields: []protowire.Number{1}, packed: true},
+	pb.CommandType_CMD_QUERY_BATCH:        {cost: costQuery, fields: []protowire.Number{1, 3}},
+	pb.CommandType_CMD_SEARCH:             {cost: costQuery, fields: []protowire.Number{2}},
+}
+
+// commandCost returns the rate-limit cost of a command. Batch commands are
+// weighted by item count, query batches by their queries and pipelines by
+// the sum of their commands. Items are counted from the wire encoding
+// without decoding the payload, which the handler decodes once.
+// Payloads that fail to parse cost the default; the handler reports the error.
+func commandCost(env *pb.Envelope) int {
+	if cost, ok := commandCosts[env.CmdType]; ok {
+		return cost
+	}
+
+	items := -1
+	if ic, ok := itemCosts[env.CmdType]; ok {
+		if n := countElements(env.Payload, ic.fields, ic.packed); n >= 0 {
+			items = n * ic.cost
+		}
+	} else if env.CmdType == pb.CommandType_CMD_PIPELINE {
+		items = pipelineCost(env.Payload)
+	}
+
+	if items < costDefault {
+		return costDefault
+	}
+	return items
+}
+
+// countElements counts the elements of the given repeated fields in an
+// encoded message, or returns -1 if it does not parse
+func countElements(payload []byte, fields []protowire.Number, packed bool) int {
+	count := 0
+	for len(payload) > 0 {
+		num, typ, n := protowire.ConsumeTag(payload)
+		if n < 0 {
+			return -1
+		}
+		payload = payload[n:]
+		n = protowire.ConsumeFieldValue(num, typ, payload)
+		if n < 0 {
+			return -1
+		}
+		if slices.Contains(fields, num) {
+			switch {
+			case packed && typ == protowire.BytesType:
+				v, _ := protowire.ConsumeBytes(payload[:n])
+				for _, b := range v {
+					if b < 0x80 { // last byte of a varint
+						count++
+					}
+				}
+			default:
+				count++
+			}
+		}
+		payload = payload[n:]
+	}
+	return count
+}
+
+// pipelineCost sums the costs of the commands of an encoded
+// PipelineRequest, reading only their types and payloads, or returns -1
+// if it does not parse. Nested pipelines are rejected by the handler, so
+// they cost the default without being walked; recursing would let one
+// frame of nested envelopes exhaust the stack.
+func pipelineCost(payload []byte) int {
+	total := 0
+	for len(payload) > 0 {
+		num, typ, n := protowire.ConsumeTag(payload)
+		if n < 0 {
+			return -1
+		}
+		payload = payload[n:]
+		n = protowire.ConsumeFieldValue(num, typ, payload)
+		if n < 0 {
+			return -1
+		}
+		if num == 1 && typ == protowire.BytesType {
+			cmd, _ := protowire.ConsumeBytes(payload[:n])
+			env, ok := envelopeHeader(cmd)
+			if !ok {
+				return -1
+			}
+			if env.CmdType == pb.CommandType_CMD_PIPELINE {
+				total += costDefault
+			} else {
+				total += commandCost(env)
+			}
+		}
+		payload = payload[n:]
+	}
+	return total
+}
+
+// envelopeHeader reads the command type and payload of an encoded
+// Envelope; the payload aliases data
+func envelopeHeader(data []byte) (*pb.Envelope, bool) {
+	env := &pb.Envelope{}
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return nil, false
+		}
+		data = data[n:]
+		n = protowire.ConsumeFieldValue(num, typ, data)
+		if n < 0 {
+			return nil, false
+		}
+		switch {
+		case num == 3 && typ == protowire.VarintType:
+			v, _ := protowire.ConsumeVarint(data[:n])
+			env.CmdType = pb.CommandType(v)
+		case num == 4 && typ == protowire.BytesType:
+			env.Payload, _ = protowire.ConsumeBytes(data[:n])
+		}
+		data = data[n:]
+	}
+	return env, true
+}
+
+// =============================================================================
+// Per-Key Limits
+// =============================================================================
+
+// keyLimiter returns the shared rate limiter for an API key, creating it
+// from the key's own limits or the server defaults
+func (s *Server) keyLimiter(apiKey *config.APIKey) *rate.Limiter {
+	if limiter, ok := s.rateLimiters.Load(apiKey.ID); ok {
+		return limiter.(*rate.Limiter)
+	}
+
+	limit := s.rateLimit
+	if apiKey.RateLimit > 0 {
+		limit = apiKey.RateLimit
+	}
+	burst := s.rateBurst
+	if apiKey.RateBurst > 0 {
+		burst = apiKey.RateBurst
+	}
+
+	limiter, _ := s.rateLimiters.LoadOrStore(apiKey.ID, rate.NewLimiter(rate.Limit(limit), burst))
+	return limiter.(*rate.Limiter)
+}
+
+// keySlots returns the concurrency semaphore for an API key, or nil if the
+// key has no concurrency cap
+func (s *Server) keySlots(apiKey *config.APIKey) chan struct{} {
+	if apiKey.MaxConcurrent <= 0 {
+		return nil
+	}
+	slots, _ := s.concurrencySlots.LoadOrStore(apiKey.ID, make(chan struct{}, apiKey.MaxConcurrent))
+	return slots.(chan struct{})
+}
+
+// acquireSlots takes up to n free concurrency slots without waiting and
+// returns how many it took. Release them with releaseSlots.
+func acquireSlots(slots chan struct{}, n int) int {
+	taken := 0
+	for ; taken < n; taken++ {
+		select {
+		case slots <- struct{}{}:
+		default:
+			return taken
+		}
+	}
+	return taken
+}
+
+// releaseSlots returns n slots taken by acquireSlots
+func releaseSlots(slots chan struct{}, n int) {
+	for i := 0; i < n; i++ {
+		<-slots
+	}
+}
+
+// allowCost charges cost units against a limiter. A command costing more
+// than the burst could never be paid for; it is rejected without taking
+// any units so the client can split it.
+func allowCost(limiter *rate.Limiter, cost int) error {
+	if burst := limiter.Burst(); cost > burst {
+		return fmt.Errorf("command cost %d exceeds rate limit burst %d; split the batch", cost, burst)
+	}
+	if !limiter.AllowN(time.Now(), cost) {
+		return errRateLimited
+	}
+	return nil
+}
+
+// errRateLimited rejects a command while the key's bucket refills
+var errRateLimited = errors.New("rate limit exceeded")
+
+// =============================================================================
+// Per-IP Limits
+// =============================================================================
+
+// maxAuthLimiters bounds the per-IP AUTH limiter table before idle
+// entries are pruned
+const maxAuthLimiters = 10000
+
+// ipLimits tracks connection counts and AUTH attempt limiters per client IP
+type ipLimits struct {
+	mu           sync.Mutex
+	conns        map[string]int
+	authLimiters map[string]*rate.Limiter
+}
+
+func newIPLimits() *ipLimits {
+	return &ipLimits{
+		conns:        make(map[string]int),
+		authLimiters: make(map[string]*rate.Limiter),
+	}
+}
+
+// acquireConn registers a connection from ip. It returns false if ip already
+// holds max connections (max <= 0 means unlimited).
+func (l *ipLimits) acquireConn(ip string, max int) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if max > 0 && l.conns[ip] >= max {
+		return false
+	}
+	l.conns[ip]++
+	return true
+}
+
+// releaseConn unregisters a connection from ip
+func (l *ipLimits) releaseConn(ip string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conns[ip] <= 1 {
+		delete(l.conns, ip)
+		return
+	}
+	l.conns[ip]--
+}
+
+// connCount returns the number of open connections from ip
+func (l *ipLimits) connCount(ip string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.conns[ip]
+}
+
+// allowAuth charges one AUTH attempt for ip
+func (l *ipLimits) allowAuth(ip string, limit rate.Limit, burst int) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limiter, ok := l.authLimiters[ip]
+	if !ok {
+		if len(l.authLimiters) >= maxAuthLimiters {
+			l.pruneAuthLimitersLocked()
+		}
+		limiter = rate.NewLimiter(limit, burst)
+		l.authLimiters[ip] = limiter
+	}
+	return limiter.Allow()
+}
+
+// pruneAuthLimitersLocked drops limiters whose bucket has refilled, since
+// those are indistinguishable from a fresh limiter
+func (l *ipLimits) pruneAuthLimitersLocked() {
+	now := time.Now()
+	for ip, limiter := range l.authLimiters {
+		if limiter.TokensAt(now) >= float64(limiter.Burst()) {
+			delete(l.authLimiters, ip)
+		}
+	}
+}
+
+// remoteIP extracts the host part of a connection's remote address
+func remoteIP(conn net.Conn) string {
+	addr := conn.RemoteAddr()
+	if addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
+
+// =============================================================================
+// Rejection Metrics
+// =============================================================================
+
+// Metric names for limit rejections
+const (
+	MetricRateLimitRejected   = "server.rejected_rate_limit_total"
+	MetricConcurrencyRejected = "server.rejected_concurrency_total"
+	MetricConnLimitRejected   = "server.rejected_conns_per_ip_total"
+	MetricAuthLimitRejected   = "server.rejected_auth_rate_total"
+)
+
+// countRejection increments a rejection counter if metrics are configured
+func (s *Server) countRejection(name string) {
+	if s.metrics != nil {
+		s.metrics.Counter(name, 1)
+	}
+}
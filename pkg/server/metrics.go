@@ -0,0 +1,186 @@
+package server
+
+import (
+	"cmp"
+	"errors"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gibram-io/gibram/pkg/logging"
+	"github.com/gibram-io/gibram/pkg/metrics"
+	"github.com/gibram-io/gibram/pkg/types"
+	pb "github.com/gibram-io/gibram/proto/gibrampb"
+)
+
+// =============================================================================
+// Metrics Names
+// =============================================================================
+
+// Metric names recorded by the server. Dotted names are exported with a
+// "gibram_" prefix and underscores (e.g. gibram_server_commands_total).
+const (
+	MetricCommands       = "server.commands_total"
+	MetricCommandErrors  = "server.command_errors_total"
+	MetricCommandLatency = "server.command_latency_us"
+	MetricConnections    = "server.connections_total"
+
+	MetricActiveConns       = "server.connections_active"
+	MetricSessions          = "engine.sessions"
+	MetricSessionObjects    = "engine.session_objects"
+	MetricTopSessionObjects = "engine.top_session_objects"
+	MetricVectorIndexSize   = "engine.vector_index_size"
+	MetricQueryCacheEntries = "engine.query_cache_entries"
+	MetricQueryCacheHits    = "engine.query_cache_hits_total"
+	MetricQueryCacheMisses  = "engine.query_cache_misses_total"
+	MetricWALCurrentLSN     = "wal.current_lsn"
+	MetricWALFlushedLSN     = "wal.flushed_lsn"
+	MetricWALSizeBytes      = "wal.size_bytes"
+	MetricWALSegments       = "wal.segments"
+	MetricBackupRunning     = "backup.in_progress"
+	MetricBackupLastSave    = "backup.last_save_timestamp_seconds"
+)
+
+// MetricsNamespace prefixes all exported metric names
+const MetricsNamespace = "gibram"
+
+// maxSessionMetrics bounds the sessions exported with a session label; the
+// largest sessions by object count are exported
+const maxSessionMetrics = 10
+
+// commandName returns the short label for a command type (e.g. "QUERY")
+func commandName(cmd pb.CommandType) string {
+	return strings.TrimPrefix(cmd.String(), "CMD_")
+}
+
+// recordCommand records count, errors and latency for one command
+func (s *Server) recordCommand(cmd, result pb.CommandType, elapsed time.Duration) {
+	name := commandName(cmd)
+	s.metrics.Counter(metrics.Labeled(MetricCommands, "cmd", name), 1)
+	if result == pb.CommandType_CMD_ERROR {
+		s.metrics.Counter(metrics.Labeled(MetricCommandErrors, "cmd", name), 1)
+	}
+	s.metrics.Histogram(metrics.Labeled(MetricCommandLatency, "cmd", name), float64(elapsed.Microseconds()))
+}
+
+// collectMetrics adds point-in-time server, engine, WAL and backup values
+// to a metrics snapshot at scrape time
+func (s *Server) collectMetrics(snap *metrics.Snapshot) {
+	snap.Gauges[MetricActiveConns] = s.activeConns.Load()
+
+	// Aggregates over all sessions, plus per-session counts for the largest
+	// few: a label per session would grow without bound
+	sessions := s.engine.ListSessions()
+	snap.Gauges[MetricSessions] = int64(len(sessions))
+	objects := map[string]int64{"document": 0, "textunit": 0, "entity": 0, "relationship": 0, "community": 0}
+	indices := map[string]int64{"textunit": 0, "entity": 0, "community": 0}
+	var cacheEntries, cacheHits, cacheMisses int64
+	for _, info := range sessions {
+		objects["document"] += int64(info.DocumentCount)
+		objects["textunit"] += int64(info.TextUnitCount)
+		objects["entity"] += int64(info.EntityCount)
+		objects["relationship"] += int64(info.RelationshipCount)
+		objects["community"] += int64(info.CommunityCount)
+
+		indices["textunit"] += int64(info.TextUnitIndexSize)
+		indices["entity"] += int64(info.EntityIndexSize)
+		indices["community"] += int64(info.CommunityIndexSize)
+
+		cacheEntries += int64(info.QueryCacheEntries)
+		cacheHits += int64(info.QueryCacheHits)
+		cacheMisses += int64(info.QueryCacheMisses)
+	}
+	for kind, count := range objects {
+		snap.Gauges[metrics.Labeled(MetricSessionObjects, "type", kind)] = count
+	}
+	for index, size := range indices {
+		snap.Gauges[metrics.Labeled(MetricVectorIndexSize, "index", index)] = size
+	}
+	slices.SortFunc(sessions, func(a, b types.SessionInfo) int {
+		if c := cmp.Compare(sessionObjects(b), sessionObjects(a)); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	for _, info := range sessions[:min(len(sessions), maxSessionMetrics)] {
+		for kind, count := range map[string]int{
+			"document":     info.DocumentCount,
+			"textunit":     info.TextUnitCount,
+			"entity":       info.EntityCount,
+			"relationship": info.RelationshipCount,
+			"community":    info.CommunityCount,
+		} {
+			snap.Gauges[metrics.Labeled(MetricTopSessionObjects, "session", info.ID, "type", kind)] = int64(count)
+		}
+	}
+	snap.Gauges[MetricQueryCacheEntries] = cacheEntries
+	snap.Counters[MetricQueryCacheHits] = cacheHits
+	snap.Counters[MetricQueryCacheMisses] = cacheMisses
+
+	if s.wal != nil {
+		snap.Gauges[MetricWALCurrentLSN] = int64(s.wal.CurrentLSN())
+		snap.Gauges[MetricWALFlushedLSN] = int64(s.wal.FlushedLSN())
+		snap.Gauges[MetricWALSizeBytes] = s.wal.TotalSize()
+		snap.Gauges[MetricWALSegments] = int64(s.wal.SegmentCount())
+	}
+
+	var running int64
+	if s.backupInProgress.Load() {
+		running = 1
+	}
+	snap.Gauges[MetricBackupRunning] = running
+	if last := s.lastSaveTime.Load(); last > 0 {
+		snap.Gauges[MetricBackupLastSave] = last
+	}
+}
+
+// sessionObjects returns the number of objects stored in a session
+func sessionObjects(info types.SessionInfo) int {
+	return info.DocumentCount + info.TextUnitCount + info.EntityCount + info.RelationshipCount + info.CommunityCount
+}
+
+// =============================================================================
+// Metrics HTTP Listener
+// =============================================================================
+
+// StartMetrics starts an HTTP listener serving Prometheus metrics on path.
+// SetMetrics must be called first. The listener is stopped by Stop.
+func (s *Server) StartMetrics(addr, path string) error {
+	if s.metrics == nil {
+		return errors.New("metrics collector not configured")
+	}
+	if path == "" {
+		path = "/metrics"
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(path, metrics.PrometheusHandler(s.metrics, MetricsNamespace, s.collectMetrics))
+
+	s.metricsHTTP = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: metrics.DefaultScrapeTimeout,
+		WriteTimeout:      metrics.DefaultScrapeTimeout,
+	}
+	s.metricsAddr = ln.Addr().String()
+
+	go func() {
+		if err := s.metricsHTTP.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logging.Error("Metrics listener error: %v", err)
+		}
+	}()
+
+	logging.Info("  Metrics: http://%s%s", s.metricsAddr, path)
+	return nil
+}
+
+// MetricsAddr returns the bound address of the metrics listener, if any
+func (s *Server) MetricsAddr() string {
+	return s.metricsAddr
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"net/http"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -13,7 +15,11 @@ import (
 	"github.com/gibram-io/gibram/pkg/codec"
 	"github.com/gibram-io/gibram/pkg/config"
 	"github.com/gibram-io/gibram/pkg/engine"
+	"github.com/gibram-io/gibram/pkg/memory"
+	"github.com/gibram-io/gibram/pkg/metrics"
 	pb "github.com/gibram-io/gibram/proto/gibrampb"
+	"golang.org/x/time/rate"
+	"google.golang.org/protobuf/encoding/protowire"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -3824,3 +3830,1701 @@ func TestServerIntegration_HierarchicalLeidenWithData(t *testing.T) {
 		t.Logf("Hierarchical Leiden found %d total communities", leidenResp.TotalCommunities)
 	}
 }
+
+// =============================================================================
+// Rate Limit, Concurrency and Per-IP Limit Tests
+// =============================================================================
+
+func TestCommandCost(t *testing.T) {
+	entities := make([]*pb.AddEntityRequest, 25)
+	for i := range entities {
+		entities[i] = &pb.AddEntityRequest{ExternalId: itoa(i)}
+	}
+
+	pipeline := &pb.PipelineRequest{
+		Commands: []*pb.Envelope{
+			{CmdType: pb.CommandType_CMD_PING},
+			{CmdType: pb.CommandType_CMD_QUERY},
+			{CmdType: pb.CommandType_CMD_MGET_ENTITIES, Payload: marshalPayload(t, &pb.MGetEntitiesRequest{Ids: []uint64{1, 2, 3}})},
+		},
+	}
+
+	tests := []struct {
+		name string
+		env  *pb.Envelope
+		want int
+	}{
+		{"ping", &pb.Envelope{CmdType: pb.CommandType_CMD_PING}, 1},
+		{"get", &pb.Envelope{CmdType: pb.CommandType_CMD_GET_ENTITY}, 1},
+		{"query", &pb.Envelope{CmdType: pb.CommandType_CMD_QUERY}, costQuery},
+		{"leiden", &pb.Envelope{CmdType: pb.CommandType_CMD_HIERARCHICAL_LEIDEN}, costCommunity},
+		{"global search", &pb.Envelope{CmdType: pb.CommandType_CMD_GLOBAL_SEARCH}, costCommunity},
+		{"recall check", &pb.Envelope{CmdType: pb.CommandType_CMD_RECALL_CHECK}, costRebuild},
+		{"path", &pb.Envelope{CmdType: pb.CommandType_CMD_PATH}, costGraph},
+		{"subgraph", &pb.Envelope{CmdType: pb.CommandType_CMD_SUBGRAPH}, costGraph},
+		{"query graph", &pb.Envelope{CmdType: pb.CommandType_CMD_QUERY_GRAPH}, costGraph},
+		{"mset", &pb.Envelope{CmdType: pb.CommandType_CMD_MSET_ENTITIES, Payload: marshalPayload(t, &pb.MSetEntitiesRequest{Entities: entities})}, 25},
+		{"mset empty", &pb.Envelope{CmdType: pb.CommandType_CMD_MSET_ENTITIES}, 1},
+		{"mset invalid", &pb.Envelope{CmdType: pb.CommandType_CMD_MSET_ENTITIES, Payload: []byte{0xff, 0xff}}, 1},
+		{"pipeline", &pb.Envelope{CmdType: pb.CommandType_CMD_PIPELINE, Payload: marshalPayload(t, pipeline)}, 1 + costQuery + 3},
+		{"query batch", &pb.Envelope{CmdType: pb.CommandType_CMD_QUERY_BATCH, Payload: marshalPayload(t, &pb.QueryBatchRequest{
+			Queries:      []*pb.QueryRequest{{}, {}},
+			QueryVectors: []*pb.SearchVector{{}},
+		})}, 3 * costQuery},
+		{"search", &pb.Envelope{CmdType: pb.CommandType_CMD_SEARCH, Payload: marshalPayload(t, &pb.SearchRequest{
+			Index:        "entity",
+			QueryVectors: []*pb.SearchVector{{Values: []float32{1, 0}}, {Values: []float32{0, 1}}},
+		})}, 2 * costQuery},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := commandCost(tt.env); got != tt.want {
+				t.Errorf("commandCost() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func marshalPayload(t *testing.T, m proto.Message) []byte {
+	t.Helper()
+	data, err := proto.Marshal(m)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	return data
+}
+
+// nestedPipeline encodes depth pipelines each holding the next, written
+// outermost first so building it takes linear time
+func nestedPipeline(depth int) []byte {
+	pipelineType := protowire.AppendVarint(protowire.AppendTag(nil, 3, protowire.VarintType), uint64(pb.CommandType_CMD_PIPELINE))
+	// sizes[i] is the size of the pipeline payload i levels from the inside
+	sizes := make([]int, depth+1)
+	for i := 1; i <= depth; i++ {
+		env := len(pipelineType) + protowire.SizeTag(4) + protowire.SizeBytes(sizes[i-1])
+		sizes[i] = protowire.SizeTag(1) + protowire.SizeBytes(env)
+	}
+	buf := make([]byte, 0, sizes[depth])
+	for i := depth; i >= 1; i-- {
+		env := len(pipelineType) + protowire.SizeTag(4) + protowire.SizeBytes(sizes[i-1])
+		buf = protowire.AppendVarint(protowire.AppendTag(buf, 1, protowire.BytesType), uint64(env))
+		buf = append(buf, pipelineType...)
+		buf = protowire.AppendVarint(protowire.AppendTag(buf, 4, protowire.BytesType), uint64(sizes[i-1]))
+	}
+	return buf
+}
+
+func TestCommandCost_NestedPipeline(t *testing.T) {
+	// Deep nesting is not walked, so it cannot exhaust the stack
+	deep := &pb.Envelope{CmdType: pb.CommandType_CMD_PIPELINE, Payload: nestedPipeline(1 << 20)}
+	if got := commandCost(deep); got != costDefault {
+		t.Errorf("commandCost(deeply nested pipeline) = %d, want %d", got, costDefault)
+	}
+
+	// A nested pipeline costs the default; its siblings are still charged
+	inner := &pb.Envelope{CmdType: pb.CommandType_CMD_PIPELINE, Payload: marshalPayload(t, &pb.PipelineRequest{
+		Commands: []*pb.Envelope{{CmdType: pb.CommandType_CMD_QUERY}},
+	})}
+	outer := &pb.Envelope{CmdType: pb.CommandType_CMD_PIPELINE, Payload: marshalPayload(t, &pb.PipelineRequest{
+		Commands: []*pb.Envelope{inner, {CmdType: pb.CommandType_CMD_QUERY}},
+	})}
+	if got := commandCost(outer); got != costDefault+costQuery {
+		t.Errorf("commandCost(pipeline with a nested pipeline) = %d, want %d", got, costDefault+costQuery)
+	}
+
+	srv := NewServer(engine.NewEngine(testVectorDim))
+	cmdType, payload := srv.handlePipeline(outer, &connState{})
+	var resp pb.PipelineResponse
+	mustUnmarshal(t, payload, &resp)
+	if cmdType != pb.CommandType_CMD_PIPELINE_RESPONSE || len(resp.Responses) != 2 {
+		t.Fatalf("PIPELINE = %v with %d responses, want 2", cmdType, len(resp.Responses))
+	}
+	if resp.Responses[0].CmdType != pb.CommandType_CMD_ERROR {
+		t.Errorf("nested pipeline = %v, want an error", resp.Responses[0].CmdType)
+	}
+}
+
+func TestAllowCost_RejectsAboveBurst(t *testing.T) {
+	limiter := rate.NewLimiter(rate.Limit(1), 5)
+	if err := allowCost(limiter, 100); err == nil || !strings.Contains(err.Error(), "exceeds rate limit burst") {
+		t.Fatalf("cost above burst = %v, want a burst error", err)
+	}
+	if err := allowCost(limiter, 5); err != nil {
+		t.Errorf("rejected oversized cost should not drain the bucket: %v", err)
+	}
+	if err := allowCost(limiter, 1); err != errRateLimited {
+		t.Errorf("drained bucket = %v, want %v", err, errRateLimited)
+	}
+}
+
+func TestKeyLimiter_PerKeyOverrides(t *testing.T) {
+	srv := NewServer(engine.NewEngine(testVectorDim))
+
+	custom := &config.APIKey{ID: "custom", RateLimit: 7, RateBurst: 3}
+	limiter := srv.keyLimiter(custom)
+	if limiter.Limit() != rate.Limit(7) || limiter.Burst() != 3 {
+		t.Errorf("Expected per-key limit 7/3, got %v/%d", limiter.Limit(), limiter.Burst())
+	}
+	if srv.keyLimiter(custom) != limiter {
+		t.Error("Limiter should be shared across connections of the same key")
+	}
+
+	plain := &config.APIKey{ID: "plain"}
+	limiter = srv.keyLimiter(plain)
+	if limiter.Limit() != rate.Limit(DefaultRateLimit) || limiter.Burst() != DefaultRateBurst {
+		t.Errorf("Expected server defaults, got %v/%d", limiter.Limit(), limiter.Burst())
+	}
+
+	if srv.keySlots(plain) != nil {
+		t.Error("Key without max_concurrent should have no slots")
+	}
+	slots := srv.keySlots(&config.APIKey{ID: "capped", MaxConcurrent: 2})
+	if cap(slots) != 2 {
+		t.Errorf("Expected 2 slots, got %d", cap(slots))
+	}
+}
+
+func TestIPLimits_Conns(t *testing.T) {
+	l := newIPLimits()
+
+	if !l.acquireConn("10.0.0.1", 2) || !l.acquireConn("10.0.0.1", 2) {
+		t.Fatal("First two connections should be accepted")
+	}
+	if l.acquireConn("10.0.0.1", 2) {
+		t.Error("Third connection should be rejected")
+	}
+	if !l.acquireConn("10.0.0.2", 2) {
+		t.Error("Other IPs should not be affected")
+	}
+
+	l.releaseConn("10.0.0.1")
+	if !l.acquireConn("10.0.0.1", 2) {
+		t.Error("Connection should be accepted after release")
+	}
+
+	l.releaseConn("10.0.0.2")
+	if l.connCount("10.0.0.2") != 0 {
+		t.Error("Released IP should have no connections")
+	}
+	if !l.acquireConn("10.0.0.3", 0) {
+		t.Error("max <= 0 should mean unlimited")
+	}
+}
+
+func TestIPLimits_AuthPrune(t *testing.T) {
+	l := newIPLimits()
+	for i := 0; i < maxAuthLimiters; i++ {
+		l.authLimiters[itoa(i)] = rate.NewLimiter(rate.Inf, 1)
+	}
+	if !l.allowAuth("fresh", rate.Limit(1), 1) {
+		t.Fatal("First attempt should be allowed")
+	}
+	if len(l.authLimiters) != 1 {
+		t.Errorf("Idle limiters should be pruned, %d remain", len(l.authLimiters))
+	}
+}
+
+func TestServerIntegration_PerKeyRateLimit(t *testing.T) {
+	srv, addr, apiKey := createLimitTestServer(t, func(cfg *config.Config) {
+		cfg.Auth.Keys[0].RateLimit = 1
+		cfg.Auth.Keys[0].RateBurst = 3
+	})
+	defer srv.Stop()
+	collector := srv.metrics
+
+	conn := dialAndAuth(t, addr, apiKey)
+	defer closeSilently(conn)
+
+	// A 3-item MSET drains the whole burst
+	mset := &pb.MSetDocumentsRequest{Documents: []*pb.AddDocumentRequest{
+		{ExternalId: "rl-1", Filename: "a.txt"},
+		{ExternalId: "rl-2", Filename: "b.txt"},
+		{ExternalId: "rl-3", Filename: "c.txt"},
+	}}
+	resp := mustSendCommand(t, conn, pb.CommandType_CMD_MSET_DOCUMENTS, mset)
+	if resp.CmdType == pb.CommandType_CMD_ERROR {
+		var errResp pb.Error
+		mustUnmarshal(t, resp.Payload, &errResp)
+		t.Fatalf("MSET should pass: %s", errResp.Message)
+	}
+
+	resp = mustSendCommand(t, conn, pb.CommandType_CMD_PING, nil)
+	if resp.CmdType != pb.CommandType_CMD_ERROR {
+		t.Fatalf("Expected rate limit error, got %v", resp.CmdType)
+	}
+	var errResp pb.Error
+	mustUnmarshal(t, resp.Payload, &errResp)
+	if errResp.Message != "rate limit exceeded" {
+		t.Errorf("Unexpected error: %s", errResp.Message)
+	}
+	if got := collector.GetCounter(MetricRateLimitRejected); got != 1 {
+		t.Errorf("Expected 1 rate limit rejection, got %d", got)
+	}
+}
+
+func TestServerIntegration_MaxConnsPerIP(t *testing.T) {
+	eng := engine.NewEngine(testVectorDim)
+	srv := NewServerWithConfig(eng, &config.Config{
+		Security: config.SecurityConfig{MaxConnsPerIP: 2},
+	})
+	collector := metrics.NewCollector()
+	srv.SetMetrics(collector)
+	addr := startTestServer(t, srv)
+	defer srv.Stop()
+
+	var conns []net.Conn
+	for i := 0; i < 2; i++ {
+		conn, err := net.DialTimeout("tcp", addr, 5*time.Second)
+		if err != nil {
+			t.Fatalf("Failed to connect: %v", err)
+		}
+		conns = append(conns, conn)
+		if resp := mustSendCommand(t, conn, pb.CommandType_CMD_PING, nil); resp.CmdType != pb.CommandType_CMD_PONG {
+			t.Fatalf("Expected PONG on connection %d, got %v", i, resp.CmdType)
+		}
+	}
+
+	extra, err := net.DialTimeout("tcp", addr, 5*time.Second)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer closeSilently(extra)
+	resp, err := sendCommand(extra, pb.CommandType_CMD_PING, nil)
+	if err != nil {
+		t.Fatalf("Expected rejection envelope, got error: %v", err)
+	}
+	if resp.CmdType != pb.CommandType_CMD_ERROR {
+		t.Fatalf("Expected connection to be rejected, got %v", resp.CmdType)
+	}
+	if got := collector.GetCounter(MetricConnLimitRejected); got != 1 {
+		t.Errorf("Expected 1 connection rejection, got %d", got)
+	}
+	// Rejected connections are not counted as accepted or active
+	if got := collector.GetCounter(MetricConnections); got != 2 {
+		t.Errorf("Expected 2 accepted connections, got %d", got)
+	}
+	if got := srv.activeConns.Load(); got != 2 {
+		t.Errorf("Expected 2 active connections, got %d", got)
+	}
+
+	// Closing a connection frees a slot
+	closeSilently(conns[0])
+	deadline := time.Now().Add(2 * time.Second)
+	for srv.ipLimits.connCount("127.0.0.1") >= 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	conn, err := net.DialTimeout("tcp", addr, 5*time.Second)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer closeSilently(conn)
+	if resp := mustSendCommand(t, conn, pb.CommandType_CMD_PING, nil); resp.CmdType != pb.CommandType_CMD_PONG {
+		t.Errorf("Expected PONG after slot freed, got %v", resp.CmdType)
+	}
+	closeSilently(conns[1])
+}
+
+func TestServerIntegration_AuthAttemptLimit(t *testing.T) {
+	srv, addr, apiKey := createLimitTestServer(t, func(cfg *config.Config) {
+		cfg.Security.AuthAttemptsPerMin = 1
+		cfg.Security.AuthAttemptBurst = 2
+	})
+	defer srv.Stop()
+	collector := srv.metrics
+
+	// Two wrong guesses use up the burst
+	for i := 0; i < 2; i++ {
+		conn, err := net.DialTimeout("tcp", addr, 5*time.Second)
+		if err != nil {
+			t.Fatalf("Failed to connect: %v", err)
+		}
+		resp := mustSendCommand(t, conn, pb.CommandType_CMD_AUTH, &pb.AuthRequest{ApiKey: "wrong"})
+		if resp.CmdType != pb.CommandType_CMD_AUTH_RESPONSE {
+			t.Fatalf("Expected auth response, got %v", resp.CmdType)
+		}
+		closeSilently(conn)
+	}
+
+	// Even the correct key is now throttled for this IP
+	conn, err := net.DialTimeout("tcp", addr, 5*time.Second)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer closeSilently(conn)
+	resp := mustSendCommand(t, conn, pb.CommandType_CMD_AUTH, &pb.AuthRequest{ApiKey: apiKey})
+	if resp.CmdType != pb.CommandType_CMD_ERROR {
+		t.Fatalf("Expected throttled auth, got %v", resp.CmdType)
+	}
+	if got := collector.GetCounter(MetricAuthLimitRejected); got != 1 {
+		t.Errorf("Expected 1 auth rejection, got %d", got)
+	}
+}
+
+// createLimitTestServer starts an auth-enabled server; configure may adjust
+// the config (e.g. limits) before the server is created
+func createLimitTestServer(t *testing.T, configure func(cfg *config.Config)) (*Server, string, string) {
+	t.Helper()
+	apiKey, err := config.GenerateAPIKey()
+	if err != nil {
+		t.Fatalf("Failed to generate API key: %v", err)
+	}
+	hashedKey, err := config.HashAPIKey(apiKey)
+	if err != nil {
+		t.Fatalf("Failed to hash API key: %v", err)
+	}
+
+	cfg := &config.Config{
+		Auth: config.AuthConfig{
+			Keys: []config.APIKeyConfig{
+				{
+					ID:          "limit-key",
+					KeyHash:     hashedKey,
+					Permissions: []string{config.PermAdmin},
+				},
+			},
+		},
+	}
+	if configure != nil {
+		configure(cfg)
+	}
+
+	// The collector is set before Start; connections read it on accept
+	srv := NewServerWithConfig(engine.NewEngine(testVectorDim), cfg)
+	srv.SetMetrics(metrics.NewCollector())
+	addr := startTestServer(t, srv)
+	return srv, addr, apiKey
+}
+
+// startTestServer starts srv on a free local port and returns its address
+func startTestServer(t *testing.T, srv *Server) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to find available port: %v", err)
+	}
+	addr := ln.Addr().String()
+	closeSilently(ln)
+
+	if err := srv.Start(addr); err != nil {
+		t.Fatalf("Failed to start server: %v", err)
+	}
+	return addr
+}
+
+// dialAndAuth connects to addr and authenticates with apiKey
+func dialAndAuth(t *testing.T, addr, apiKey string) net.Conn {
+	t.Helper()
+	conn, err := net.DialTimeout("tcp", addr, 5*time.Second)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	resp := mustSendCommand(t, conn, pb.CommandType_CMD_AUTH, &pb.AuthRequest{ApiKey: apiKey})
+	var authResp pb.AuthResponse
+	mustUnmarshal(t, resp.Payload, &authResp)
+	if !authResp.Success {
+		t.Fatalf("Auth failed: %s", authResp.Message)
+	}
+	return conn
+}
+
+// =============================================================================
+// Prometheus Metrics Endpoint Tests
+// =============================================================================
+
+func TestServerMetrics_RequiresCollector(t *testing.T) {
+	srv := NewServer(engine.NewEngine(testVectorDim))
+	if err := srv.StartMetrics("127.0.0.1:0", ""); err == nil {
+		t.Error("StartMetrics should fail without a collector")
+	}
+}
+
+func TestServerIntegration_PrometheusEndpoint(t *testing.T) {
+	srv := NewServer(engine.NewEngine(testVectorDim))
+	srv.SetMetrics(metrics.NewCollector())
+	addr := startTestServer(t, srv)
+	defer srv.Stop()
+
+	if err := srv.StartMetrics("127.0.0.1:0", "/metrics"); err != nil {
+		t.Fatalf("StartMetrics failed: %v", err)
+	}
+
+	conn, err := net.DialTimeout("tcp", addr, 5*time.Second)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer closeSilently(conn)
+
+	mustSendCommand(t, conn, pb.CommandType_CMD_PING, nil)
+	mustSendCommand(t, conn, pb.CommandType_CMD_ADD_DOCUMENT, &pb.AddDocumentRequest{ExternalId: "m-doc", Filename: "m.txt"})
+	mustSendCommand(t, conn, pb.CommandType_CMD_ADD_ENTITY, &pb.AddEntityRequest{
+		ExternalId: "m-ent", Title: "Metrics Entity", Type: "test", Embedding: make([]float32, testVectorDim),
+	})
+	mustSendCommand(t, conn, pb.CommandType_CMD_GET_DOCUMENT, &pb.GetByIDRequest{Id: 999999})
+
+	resp, err := http.Get("http://" + srv.MetricsAddr() + "/metrics")
+	if err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	defer closeSilently(resp.Body)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Read body failed: %v", err)
+	}
+	out := string(body)
+
+	for _, want := range []string{
+		`gibram_server_commands_total{cmd="PING"} 1`,
+		`gibram_server_command_errors_total{cmd="GET_DOCUMENT"} 1`,
+		`gibram_server_command_latency_seconds_count{cmd="ADD_ENTITY"} 1`,
+		`gibram_server_connections_active 1`,
+		`gibram_engine_sessions 1`,
+		`gibram_engine_session_objects{type="document"} 1`,
+		`gibram_engine_vector_index_size{index="entity"} 1`,
+		`gibram_backup_in_progress 0`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("scrape missing %q", want)
+		}
+	}
+	want := `gibram_engine_top_session_objects{session="` + testSessionID + `",type="document"} 1`
+	if !strings.Contains(out, want) {
+		t.Errorf("scrape missing %q", want)
+	}
+}
+
+func TestCollectMetrics_BoundsSessionLabels(t *testing.T) {
+	eng := engine.NewEngine(testVectorDim)
+	srv := NewServer(eng)
+	for i := 0; i < maxSessionMetrics+5; i++ {
+		id := fmt.Sprintf("s-%02d", i)
+		// Session s-NN holds NN+1 documents
+		for j := 0; j <= i; j++ {
+			if _, err := eng.AddDocument(id, fmt.Sprintf("doc-%d", j), "doc.txt"); err != nil {
+				t.Fatalf("AddDocument failed: %v", err)
+			}
+		}
+	}
+
+	snap := &metrics.Snapshot{Counters: map[string]int64{}, Gauges: map[string]int64{}}
+	srv.collectMetrics(snap)
+
+	sessions := map[string]bool{}
+	for name := range snap.Gauges {
+		if strings.HasPrefix(name, MetricTopSessionObjects+"{") {
+			sessions[strings.SplitN(strings.TrimPrefix(name, MetricTopSessionObjects+`{session="`), `"`, 2)[0]] = true
+		}
+	}
+	if len(sessions) != maxSessionMetrics {
+		t.Errorf("exported %d sessions, want %d", len(sessions), maxSessionMetrics)
+	}
+	if !sessions[fmt.Sprintf("s-%02d", maxSessionMetrics+4)] || sessions["s-00"] {
+		t.Errorf("exported sessions %v, want the largest", sessions)
+	}
+	if got := snap.Gauges[metrics.Labeled(MetricSessionObjects, "type", "document")]; got != int64((maxSessionMetrics+5)*(maxSessionMetrics+6)/2) {
+		t.Errorf("aggregate documents = %d", got)
+	}
+}
+
+// =============================================================================
+// Admin Endpoint Tests
+// =============================================================================
+
+// adminGet issues a GET against the admin listener with an optional API key
+func adminGet(t *testing.T, srv *Server, path, apiKey string) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, "http://"+srv.AdminAddr()+path, nil)
+	if err != nil {
+		t.Fatalf("NewRequest failed: %v", err)
+	}
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET %s failed: %v", path, err)
+	}
+	defer closeSilently(resp.Body)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Read body failed: %v", err)
+	}
+	return resp.StatusCode, string(body)
+}
+
+func TestServerReadiness(t *testing.T) {
+	srv := NewServer(engine.NewEngine(testVectorDim))
+	if ready, reason := srv.Readiness(); ready || reason != ReadyStarting {
+		t.Errorf("before Start: got %v %q, want not ready %q", ready, reason, ReadyStarting)
+	}
+
+	startTestServer(t, srv)
+	defer srv.Stop()
+	if ready, reason := srv.Readiness(); !ready || reason != ReadyOK {
+		t.Errorf("after Start: got %v %q, want ready", ready, reason)
+	}
+
+	end := srv.BeginRecovery()
+	if ready, reason := srv.Readiness(); ready || reason != ReadyRecovering {
+		t.Errorf("during recovery: got %v %q, want not ready %q", ready, reason, ReadyRecovering)
+	}
+	end()
+	end() // idempotent
+	if ready, _ := srv.Readiness(); !ready {
+		t.Error("expected ready after recovery ended")
+	}
+
+	srv.Drain()
+	if ready, reason := srv.Readiness(); ready || reason != ReadyDraining {
+		t.Errorf("after Drain: got %v %q, want not ready %q", ready, reason, ReadyDraining)
+	}
+}
+
+func TestServerIntegration_AdminHealthAndReadiness(t *testing.T) {
+	srv := NewServer(engine.NewEngine(testVectorDim))
+	if err := srv.StartAdmin("127.0.0.1:0"); err != nil {
+		t.Fatalf("StartAdmin failed: %v", err)
+	}
+
+	if code, _ := adminGet(t, srv, "/healthz", ""); code != http.StatusOK {
+		t.Errorf("healthz: expected 200, got %d", code)
+	}
+	if code, body := adminGet(t, srv, "/readyz", ""); code != http.StatusServiceUnavailable || !strings.Contains(body, ReadyStarting) {
+		t.Errorf("readyz before Start: got %d %q", code, body)
+	}
+
+	startTestServer(t, srv)
+	defer srv.Stop()
+	if code, _ := adminGet(t, srv, "/readyz", ""); code != http.StatusOK {
+		t.Errorf("readyz after Start: expected 200, got %d", code)
+	}
+
+	end := srv.BeginRecovery()
+	if code, body := adminGet(t, srv, "/readyz", ""); code != http.StatusServiceUnavailable || !strings.Contains(body, ReadyRecovering) {
+		t.Errorf("readyz during recovery: got %d %q", code, body)
+	}
+	end()
+
+	// No auth configured: status and pprof are open
+	if code, body := adminGet(t, srv, "/admin/status", ""); code != http.StatusOK || !strings.Contains(body, `"ready": true`) {
+		t.Errorf("admin status: got %d %q", code, body)
+	}
+	if code, _ := adminGet(t, srv, "/debug/pprof/goroutine?debug=1", ""); code != http.StatusOK {
+		t.Errorf("pprof: expected 200, got %d", code)
+	}
+}
+
+// Run with -race: BGSAVE records its state from a background goroutine
+// while admin status reads it
+func TestServerIntegration_AdminStatusDuringBGSave(t *testing.T) {
+	srv := NewServer(engine.NewEngine(testVectorDim))
+	var wg sync.WaitGroup
+	srv.SetSnapshotCallback(func(path string) error {
+		defer wg.Done()
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	})
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to find available port: %v", err)
+	}
+	addr := ln.Addr().String()
+	closeSilently(ln)
+	if err := srv.Start(addr); err != nil {
+		t.Fatalf("Failed to start server: %v", err)
+	}
+	defer srv.Stop()
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("Dial error: %v", err)
+	}
+	defer closeSilently(conn)
+
+	wg.Add(1)
+	resp := mustSendCommand(t, conn, pb.CommandType_CMD_BGSAVE, &pb.SaveRequest{Path: "/tmp/gibram_status_test"})
+	if resp.CmdType != pb.CommandType_CMD_OK {
+		t.Fatalf("BGSAVE failed: %s", resp.Payload)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		status := srv.adminStatus().Backup
+		if !status.InProgress && status.LastSaveTime > 0 {
+			if status.Type != "save" || status.StartTime == 0 || status.LastSavePath != "/tmp/gibram_status_test" {
+				t.Errorf("backup status after BGSAVE = %+v", status)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("BGSAVE did not finish, status = %+v", status)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	wg.Wait()
+}
+
+func TestServerIntegration_AdminRequiresAdminKey(t *testing.T) {
+	var readKey string
+	srv, _, adminKey := createLimitTestServer(t, func(cfg *config.Config) {
+		key, err := config.GenerateAPIKey()
+		if err != nil {
+			t.Fatalf("Failed to generate API key: %v", err)
+		}
+		hash, err := config.HashAPIKey(key)
+		if err != nil {
+			t.Fatalf("Failed to hash API key: %v", err)
+		}
+		readKey = key
+		cfg.Auth.Keys = append(cfg.Auth.Keys, config.APIKeyConfig{
+			ID:          "read-key",
+			KeyHash:     hash,
+			Permissions: []string{config.PermRead},
+		})
+	})
+	defer srv.Stop()
+	srv.SetMemoryTracker(memory.NewTracker(1 << 30))
+	if err := srv.StartAdmin("127.0.0.1:0"); err != nil {
+		t.Fatalf("StartAdmin failed: %v", err)
+	}
+
+	if code, _ := adminGet(t, srv, "/healthz", ""); code != http.StatusOK {
+		t.Errorf("healthz must stay open, got %d", code)
+	}
+	if code, _ := adminGet(t, srv, "/debug/pprof/", ""); code != http.StatusUnauthorized {
+		t.Errorf("pprof without key: expected 401, got %d", code)
+	}
+	if code, _ := adminGet(t, srv, "/debug/pprof/", "not-a-key"); code != http.StatusUnauthorized {
+		t.Errorf("pprof with bad key: expected 401, got %d", code)
+	}
+	if code, _ := adminGet(t, srv, "/admin/status", readKey); code != http.StatusForbidden {
+		t.Errorf("status with read key: expected 403, got %d", code)
+	}
+	if code, _ := adminGet(t, srv, "/debug/pprof/", adminKey); code != http.StatusOK {
+		t.Errorf("pprof with admin key: expected 200, got %d", code)
+	}
+	code, body := adminGet(t, srv, "/admin/status", adminKey)
+	if code != http.StatusOK || !strings.Contains(body, `"max_bytes": 1073741824`) {
+		t.Errorf("status with admin key: got %d %q", code, body)
+	}
+}
+
+// =============================================================================
+// Slow Log Tests
+// =============================================================================
+
+func TestSlowLog_RingBuffer(t *testing.T) {
+	l := newSlowLog(0, 3)
+	for i := 0; i < 5; i++ {
+		l.add(&pb.SlowLogEntry{Command: itoa(i)})
+	}
+
+	if l.len() != 3 {
+		t.Fatalf("expected 3 entries, got %d", l.len())
+	}
+	entries := l.get(-1)
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+	for i, want := range []uint64{4, 3, 2} {
+		if entries[i].Id != want {
+			t.Errorf("entry %d: expected id %d, got %d", i, want, entries[i].Id)
+		}
+	}
+	if got := l.get(1); len(got) != 1 || got[0].Id != 4 {
+		t.Errorf("get(1) should return the newest entry, got %v", got)
+	}
+
+	l.reset()
+	if l.len() != 0 || len(l.get(-1)) != 0 {
+		t.Error("expected empty slow log after reset")
+	}
+	l.add(&pb.SlowLogEntry{})
+	if got := l.get(-1); got[0].Id != 5 {
+		t.Errorf("entry IDs should keep increasing after reset, got %d", got[0].Id)
+	}
+}
+
+func TestSlowLog_Threshold(t *testing.T) {
+	if newSlowLog(-1, 0).slow(time.Hour) {
+		t.Error("negative threshold should disable the slow log")
+	}
+	l := newSlowLog(time.Millisecond, 0)
+	if l.slow(time.Microsecond) || !l.slow(2*time.Millisecond) {
+		t.Error("threshold comparison is wrong")
+	}
+}
+
+func TestCommandArgs(t *testing.T) {
+	query := &pb.Envelope{
+		CmdType: pb.CommandType_CMD_QUERY,
+		Payload: marshalPayload(t, &pb.QueryRequest{
+			QueryVector:       make([]float32, 8),
+			SearchTypes:       []string{"entity", "textunit"},
+			TopK:              7,
+			KHops:             2,
+			FilterEntityTypes: []string{"person"},
+		}),
+	}
+	args := commandArgs(query)
+	for _, want := range []string{"dim=8", "types=entity,textunit", "top_k=7", "k_hops=2", "entity_types=person"} {
+		if !strings.Contains(args, want) {
+			t.Errorf("query args %q missing %q", args, want)
+		}
+	}
+
+	mget := &pb.Envelope{
+		CmdType: pb.CommandType_CMD_MGET_ENTITIES,
+		Payload: marshalPayload(t, &pb.MGetEntitiesRequest{Ids: []uint64{1, 2, 3}}),
+	}
+	if args := commandArgs(mget); args != "items=3" {
+		t.Errorf("expected items=3, got %q", args)
+	}
+
+	if args := commandArgs(&pb.Envelope{CmdType: pb.CommandType_CMD_PING}); args != "" {
+		t.Errorf("expected no args for PING, got %q", args)
+	}
+}
+
+func TestResponseQueryID(t *testing.T) {
+	payload := marshalPayload(t, &pb.QueryResponse{
+		QueryId:  42,
+		Entities: []*pb.EntityResult{{Similarity: 0.5}},
+		Stats:    &pb.QueryStats{DurationMicros: 10},
+	})
+	if id := responseQueryID(payload); id != 42 {
+		t.Errorf("expected query id 42, got %d", id)
+	}
+	if id := responseQueryID([]byte{0xff}); id != 0 {
+		t.Errorf("expected 0 for malformed payload, got %d", id)
+	}
+}
+
+func TestServerIntegration_SlowLog(t *testing.T) {
+	srv := NewServerWithConfig(engine.NewEngine(testVectorDim), &config.Config{
+		SlowLog: config.SlowLogConfig{Threshold: time.Nanosecond, MaxLen: 16},
+	})
+	addr := startTestServer(t, srv)
+	defer srv.Stop()
+
+	conn, err := net.DialTimeout("tcp", addr, 5*time.Second)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer closeSilently(conn)
+
+	queryResp := mustSendCommand(t, conn, pb.CommandType_CMD_QUERY, &pb.QueryRequest{
+		QueryVector: make([]float32, testVectorDim),
+		SearchTypes: []string{"entity"},
+		TopK:        3,
+	})
+	var qResp pb.QueryResponse
+	mustUnmarshal(t, queryResp.Payload, &qResp)
+
+	resp := mustSendCommand(t, conn, pb.CommandType_CMD_SLOWLOG_GET, &pb.SlowLogGetRequest{Count: -1})
+	if resp.CmdType != pb.CommandType_CMD_SLOWLOG_RESPONSE {
+		t.Fatalf("expected SLOWLOG_RESPONSE, got %v", resp.CmdType)
+	}
+	var slResp pb.SlowLogResponse
+	mustUnmarshal(t, resp.Payload, &slResp)
+	if len(slResp.Entries) == 0 {
+		t.Fatal("expected slow log entries")
+	}
+	entry := slResp.Entries[0]
+	if entry.Command != "QUERY" || entry.QueryId != qResp.QueryId || entry.SessionId != testSessionID {
+		t.Errorf("unexpected entry: %v", entry)
+	}
+	if !strings.Contains(entry.Args, "top_k=3") || entry.ClientAddr != "127.0.0.1" {
+		t.Errorf("unexpected entry args/client: %q %q", entry.Args, entry.ClientAddr)
+	}
+
+	mustSendCommand(t, conn, pb.CommandType_CMD_SLOWLOG_RESET, nil)
+	resp = mustSendCommand(t, conn, pb.CommandType_CMD_SLOWLOG_LEN, nil)
+	mustUnmarshal(t, resp.Payload, &slResp)
+	// The RESET command itself is logged after it runs
+	if slResp.Length != 1 {
+		t.Errorf("expected 1 entry after reset, got %d", slResp.Length)
+	}
+}
+
+// =============================================================================
+// Session Index Options Tests
+// =============================================================================
+
+func TestServer_CreateSessionMetrics(t *testing.T) {
+	srv, addr := createTestServer(t)
+	defer srv.Stop()
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("Dial error: %v", err)
+	}
+	defer closeSilently(conn)
+
+	resp := mustSendCommand(t, conn, pb.CommandType_CMD_CREATE_SESSION, &pb.CreateSessionRequest{
+		EntityIndex: &pb.IndexOptions{Metric: "manhattan"},
+	})
+	if resp.CmdType != pb.CommandType_CMD_ERROR {
+		t.Fatalf("expected error for unknown metric, got %v", resp.CmdType)
+	}
+
+	resp = mustSendCommand(t, conn, pb.CommandType_CMD_CREATE_SESSION, &pb.CreateSessionRequest{
+		EntityIndex:    &pb.IndexOptions{Metric: "dot"},
+		CommunityIndex: &pb.IndexOptions{Metric: "l2"},
+	})
+	if resp.CmdType != pb.CommandType_CMD_OK {
+		t.Fatalf("CREATE_SESSION failed: %v", resp.CmdType)
+	}
+
+	resp = mustSendCommand(t, conn, pb.CommandType_CMD_CREATE_SESSION, &pb.CreateSessionRequest{})
+	if resp.CmdType != pb.CommandType_CMD_ERROR {
+		t.Errorf("expected error creating an existing session, got %v", resp.CmdType)
+	}
+
+	resp = mustSendCommand(t, conn, pb.CommandType_CMD_SESSION_INFO, nil)
+	var info pb.SessionInfo
+	mustUnmarshal(t, resp.Payload, &info)
+	if info.TextunitIndex.GetMetric() != "cosine" || info.EntityIndex.GetMetric() != "dot" || info.CommunityIndex.GetMetric() != "l2" {
+		t.Errorf("unexpected metrics: textunit=%q entity=%q community=%q",
+			info.TextunitIndex.GetMetric(), info.EntityIndex.GetMetric(), info.CommunityIndex.GetMetric())
+	}
+}
+
+func TestServer_QueryHybridValidation(t *testing.T) {
+	srv, addr := createTestServer(t)
+	defer srv.Stop()
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("Dial error: %v", err)
+	}
+	defer closeSilently(conn)
+
+	resp := mustSendCommand(t, conn, pb.CommandType_CMD_ADD_DOCUMENT, &pb.AddDocumentRequest{ExternalId: "doc-1", Filename: "a.txt"})
+	if resp.CmdType != pb.CommandType_CMD_OK {
+		t.Fatalf("ADD_DOCUMENT failed: %v", resp.CmdType)
+	}
+
+	for _, req := range []*pb.QueryRequest{
+		{},
+		{QueryText: "bank", Fusion: "max"},
+		{QueryText: "bank", Fusion: "weighted", LexicalWeight: 2},
+	} {
+		resp = mustSendCommand(t, conn, pb.CommandType_CMD_QUERY, req)
+		if resp.CmdType != pb.CommandType_CMD_ERROR {
+			t.Errorf("query %v: expected error, got %v", req, resp.CmdType)
+		}
+	}
+
+	resp = mustSendCommand(t, conn, pb.CommandType_CMD_QUERY, &pb.QueryRequest{QueryText: "bank", Fusion: "weighted"})
+	if resp.CmdType != pb.CommandType_CMD_QUERY_RESPONSE {
+		t.Errorf("text-only query failed: %v", resp.CmdType)
+	}
+}
+
+func TestServer_QueryWeightedScoring(t *testing.T) {
+	srv, addr := createTestServer(t)
+	defer srv.Stop()
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("Dial error: %v", err)
+	}
+	defer closeSilently(conn)
+
+	ids := make([]uint64, 2)
+	for i := range ids {
+		embedding := make([]float32, testVectorDim)
+		embedding[i] = 1
+		resp := mustSendCommand(t, conn, pb.CommandType_CMD_ADD_ENTITY, &pb.AddEntityRequest{
+			ExternalId: fmt.Sprintf("ent-%d", i), Title: fmt.Sprintf("Entity %d", i), Type: "t", Embedding: embedding,
+		})
+		var id pb.OkWithID
+		mustUnmarshal(t, resp.Payload, &id)
+		ids[i] = id.Id
+	}
+	mustSendCommand(t, conn, pb.CommandType_CMD_ADD_RELATIONSHIP, &pb.AddRelationshipRequest{
+		ExternalId: "rel-1", SourceId: ids[0], TargetId: ids[1], Type: "r", Weight: 2,
+	})
+
+	query := &pb.QueryRequest{
+		QueryVector: append([]float32{1}, make([]float32, testVectorDim-1)...),
+		SearchTypes: []string{"entity"},
+		TopK:        1,
+		KHops:       1,
+		Scoring:     "weighted",
+		Decay:       0.25,
+	}
+	resp := mustSendCommand(t, conn, pb.CommandType_CMD_QUERY, query)
+	if resp.CmdType != pb.CommandType_CMD_QUERY_RESPONSE {
+		t.Fatalf("QUERY failed: %v", resp.CmdType)
+	}
+	var result pb.QueryResponse
+	mustUnmarshal(t, resp.Payload, &result)
+	if len(result.Entities) != 2 || result.Entities[1].Hop != 1 || result.Entities[1].Score != result.Entities[0].Score*0.25 {
+		t.Fatalf("weighted query entities = %v, want the neighbour at a quarter of the seed score", result.Entities)
+	}
+
+	resp = mustSendCommand(t, conn, pb.CommandType_CMD_EXPLAIN, &pb.ExplainRequest{QueryId: result.QueryId})
+	var explain pb.ExplainResponse
+	mustUnmarshal(t, resp.Payload, &explain)
+	if len(explain.Traversal) != 1 || explain.Traversal[0].CumulativeScore != result.Entities[1].Score {
+		t.Errorf("explain traversal = %v, want cumulative score %v", explain.Traversal, result.Entities[1].Score)
+	}
+
+	query.Scoring = "pagerank"
+	resp = mustSendCommand(t, conn, pb.CommandType_CMD_QUERY, query)
+	if resp.CmdType != pb.CommandType_CMD_ERROR {
+		t.Errorf("unknown scoring strategy: expected error, got %v", resp.CmdType)
+	}
+
+	// Personalized PageRank restarting at the neighbour ranks it first
+	query.Scoring = ""
+	query.Expansion = "ppr"
+	query.RestartWeights = map[uint64]float32{ids[1]: 1}
+	resp = mustSendCommand(t, conn, pb.CommandType_CMD_QUERY, query)
+	if resp.CmdType != pb.CommandType_CMD_QUERY_RESPONSE {
+		t.Fatalf("ppr QUERY failed: %v", resp.CmdType)
+	}
+	mustUnmarshal(t, resp.Payload, &result)
+	if len(result.Entities) != 2 || result.Entities[0].Entity.Id != ids[1] || result.Entities[0].Score != 1 {
+		t.Errorf("ppr query entities = %v, want %d first with score 1", result.Entities, ids[1])
+	}
+}
+
+func TestServer_Path(t *testing.T) {
+	srv, addr := createTestServer(t)
+	defer srv.Stop()
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("Dial error: %v", err)
+	}
+	defer closeSilently(conn)
+
+	ids := make([]uint64, 3)
+	for i := range ids {
+		resp := mustSendCommand(t, conn, pb.CommandType_CMD_ADD_ENTITY, &pb.AddEntityRequest{
+			ExternalId: fmt.Sprintf("ent-%d", i), Title: fmt.Sprintf("Entity %d", i), Type: "t", Embedding: make([]float32, testVectorDim),
+		})
+		var id pb.OkWithID
+		mustUnmarshal(t, resp.Payload, &id)
+		ids[i] = id.Id
+	}
+	for i := 0; i < 2; i++ {
+		mustSendCommand(t, conn, pb.CommandType_CMD_ADD_RELATIONSHIP, &pb.AddRelationshipRequest{
+			ExternalId: fmt.Sprintf("rel-%d", i), SourceId: ids[i], TargetId: ids[i+1], Type: "next", Description: fmt.Sprintf("step %d", i),
+		})
+	}
+
+	resp := mustSendCommand(t, conn, pb.CommandType_CMD_PATH, &pb.PathRequest{SourceTitle: "entity 0", TargetId: ids[2]})
+	if resp.CmdType != pb.CommandType_CMD_PATH_RESPONSE {
+		t.Fatalf("PATH failed: %v", resp.CmdType)
+	}
+	var result pb.PathResponse
+	mustUnmarshal(t, resp.Payload, &result)
+	if len(result.Paths) != 1 {
+		t.Fatalf("PATH returned %d paths, want 1", len(result.Paths))
+	}
+	p := result.Paths[0]
+	if len(p.Entities) != 3 || p.Entities[2].Id != ids[2] || len(p.Relationships) != 2 || p.Relationships[1].Description != "step 1" || p.Cost != 2 {
+		t.Errorf("unexpected path: %v", p)
+	}
+
+	resp = mustSendCommand(t, conn, pb.CommandType_CMD_PATH, &pb.PathRequest{SourceId: ids[2], TargetId: ids[0], Directed: true})
+	mustUnmarshal(t, resp.Payload, &result)
+	if resp.CmdType != pb.CommandType_CMD_PATH_RESPONSE || len(result.Paths) != 0 {
+		t.Errorf("directed PATH against the edges = %v, %v; want no paths", resp.CmdType, result.Paths)
+	}
+
+	resp = mustSendCommand(t, conn, pb.CommandType_CMD_PATH, &pb.PathRequest{SourceTitle: "nobody", TargetId: ids[0]})
+	if resp.CmdType != pb.CommandType_CMD_ERROR {
+		t.Errorf("PATH from an unknown title: expected error, got %v", resp.CmdType)
+	}
+}
+
+func TestServer_Subgraph(t *testing.T) {
+	srv, addr := createTestServer(t)
+	defer srv.Stop()
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("Dial error: %v", err)
+	}
+	defer closeSilently(conn)
+
+	// A star: entity 0 points at 1, 2 and 3
+	ids := make([]uint64, 4)
+	for i := range ids {
+		resp := mustSendCommand(t, conn, pb.CommandType_CMD_ADD_ENTITY, &pb.AddEntityRequest{
+			ExternalId: fmt.Sprintf("ent-%d", i), Title: fmt.Sprintf("Entity %d", i), Type: "t", Embedding: make([]float32, testVectorDim),
+		})
+		var id pb.OkWithID
+		mustUnmarshal(t, resp.Payload, &id)
+		ids[i] = id.Id
+	}
+	for i := 1; i < len(ids); i++ {
+		mustSendCommand(t, conn, pb.CommandType_CMD_ADD_RELATIONSHIP, &pb.AddRelationshipRequest{
+			ExternalId: fmt.Sprintf("rel-%d", i), SourceId: ids[0], TargetId: ids[i], Type: "has",
+		})
+	}
+
+	resp := mustSendCommand(t, conn, pb.CommandType_CMD_SUBGRAPH, &pb.SubgraphRequest{EntityIds: []uint64{ids[0]}, Hops: 1})
+	if resp.CmdType != pb.CommandType_CMD_SUBGRAPH_RESPONSE {
+		t.Fatalf("SUBGRAPH failed: %v", resp.CmdType)
+	}
+	var result pb.SubgraphResponse
+	mustUnmarshal(t, resp.Payload, &result)
+	if len(result.Nodes) != 4 || len(result.Relationships) != 3 || result.Truncated {
+		t.Fatalf("SUBGRAPH = %d nodes, %d relationships; want 4 and 3", len(result.Nodes), len(result.Relationships))
+	}
+	if hub := result.Nodes[0]; hub.Entity.Id != ids[0] || hub.OutDegree != 3 || hub.Degree != 3 || hub.Hop != 0 {
+		t.Errorf("hub node = %v, want out degree 3 at hop 0", hub)
+	}
+
+	resp = mustSendCommand(t, conn, pb.CommandType_CMD_SUBGRAPH, &pb.SubgraphRequest{EntityIds: []uint64{ids[1]}, Hops: 1, Direction: "out"})
+	mustUnmarshal(t, resp.Payload, &result)
+	if len(result.Nodes) != 1 || len(result.Relationships) != 0 {
+		t.Errorf("outgoing SUBGRAPH of a leaf = %v, want the leaf alone", result.Nodes)
+	}
+
+	resp = mustSendCommand(t, conn, pb.CommandType_CMD_SUBGRAPH, &pb.SubgraphRequest{EntityIds: []uint64{ids[0]}, MaxNodes: 2, Hops: 1})
+	mustUnmarshal(t, resp.Payload, &result)
+	if len(result.Nodes) != 2 || len(result.Relationships) != 1 || !result.Truncated {
+		t.Errorf("limited SUBGRAPH = %d nodes, %d relationships, truncated %v; want 2, 1, true", len(result.Nodes), len(result.Relationships), result.Truncated)
+	}
+
+	resp = mustSendCommand(t, conn, pb.CommandType_CMD_SUBGRAPH, &pb.SubgraphRequest{})
+	if resp.CmdType != pb.CommandType_CMD_ERROR {
+		t.Errorf("SUBGRAPH without entities: expected error, got %v", resp.CmdType)
+	}
+}
+
+func TestServer_GraphQuery(t *testing.T) {
+	srv, addr := createTestServer(t)
+	defer srv.Stop()
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("Dial error: %v", err)
+	}
+	defer closeSilently(conn)
+
+	ids := make([]uint64, 3)
+	for i, ent := range [][2]string{{"Alice", "person"}, {"Bank Indonesia", "organization"}, {"Payments", "concept"}} {
+		resp := mustSendCommand(t, conn, pb.CommandType_CMD_ADD_ENTITY, &pb.AddEntityRequest{
+			ExternalId: fmt.Sprintf("ent-%d", i), Title: ent[0], Type: ent[1], Embedding: make([]float32, testVectorDim),
+		})
+		var id pb.OkWithID
+		mustUnmarshal(t, resp.Payload, &id)
+		ids[i] = id.Id
+	}
+	mustSendCommand(t, conn, pb.CommandType_CMD_ADD_RELATIONSHIP, &pb.AddRelationshipRequest{
+		ExternalId: "rel-1", SourceId: ids[0], TargetId: ids[1], Type: "WORKS_AT", Weight: 1,
+	})
+	mustSendCommand(t, conn, pb.CommandType_CMD_ADD_RELATIONSHIP, &pb.AddRelationshipRequest{
+		ExternalId: "rel-2", SourceId: ids[1], TargetId: ids[2], Type: "REGULATES", Weight: 0.5,
+	})
+
+	resp := mustSendCommand(t, conn, pb.CommandType_CMD_QUERY_GRAPH, &pb.GraphQueryRequest{
+		Query: "MATCH (o:organization)-[r:REGULATES]->(c:concept) RETURN o, r, c.title, r.weight",
+	})
+	if resp.CmdType != pb.CommandType_CMD_QUERY_GRAPH_RESPONSE {
+		t.Fatalf("QUERY_GRAPH failed: %v", resp.CmdType)
+	}
+	var result pb.GraphQueryResponse
+	mustUnmarshal(t, resp.Payload, &result)
+	if len(result.Columns) != 4 || len(result.Rows) != 1 || len(result.Plan) == 0 {
+		t.Fatalf("QUERY_GRAPH = %v columns, %d rows, plan %v; want 4 columns and 1 row", result.Columns, len(result.Rows), result.Plan)
+	}
+	row := result.Rows[0].Values
+	if row[0].Kind != "entity" || row[0].Entity.Id != ids[1] {
+		t.Errorf("column o = %v, want entity %d", row[0], ids[1])
+	}
+	if row[1].Kind != "relationship" || row[1].Relationship.Type != "REGULATES" {
+		t.Errorf("column r = %v, want the REGULATES relationship", row[1])
+	}
+	if row[2].Kind != "string" || row[2].StringValue != "PAYMENTS" {
+		t.Errorf("column c.title = %v, want PAYMENTS", row[2])
+	}
+	if row[3].Kind != "number" || row[3].NumberValue != 0.5 {
+		t.Errorf("column r.weight = %v, want 0.5", row[3])
+	}
+
+	resp = mustSendCommand(t, conn, pb.CommandType_CMD_QUERY_GRAPH, &pb.GraphQueryRequest{Query: "MATCH (a RETURN a"})
+	if resp.CmdType != pb.CommandType_CMD_ERROR {
+		t.Errorf("QUERY_GRAPH with a syntax error: expected error, got %v", resp.CmdType)
+	}
+}
+
+func TestServer_GlobalSearch(t *testing.T) {
+	srv, addr := createTestServer(t)
+	defer srv.Stop()
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("Dial error: %v", err)
+	}
+	defer closeSilently(conn)
+
+	// Three level-1 communities along distinct axes
+	for i := 0; i < 3; i++ {
+		vec := make([]float32, testVectorDim)
+		vec[i] = 1
+		resp := mustSendCommand(t, conn, pb.CommandType_CMD_ADD_COMMUNITY, &pb.AddCommunityRequest{
+			ExternalId: fmt.Sprintf("comm-%d", i), Title: fmt.Sprintf("C%d", i), Summary: "summary",
+			FullContent: strings.Repeat("word ", 20), Level: 1, Embedding: vec,
+		})
+		if resp.CmdType == pb.CommandType_CMD_ERROR {
+			t.Fatalf("ADD_COMMUNITY failed: %s", resp.Payload)
+		}
+	}
+
+	query := make([]float32, testVectorDim)
+	query[1] = 1
+	resp := mustSendCommand(t, conn, pb.CommandType_CMD_GLOBAL_SEARCH, &pb.GlobalSearchRequest{
+		QueryVector: query, Level: 1, BatchTokens: 30,
+	})
+	if resp.CmdType != pb.CommandType_CMD_GLOBAL_SEARCH_RESPONSE {
+		t.Fatalf("GLOBAL_SEARCH failed: %v", resp.CmdType)
+	}
+	var result pb.GlobalSearchResponse
+	mustUnmarshal(t, resp.Payload, &result)
+	if result.Level != 1 || result.Candidates != 3 || len(result.Batches) != 3 {
+		t.Fatalf("GLOBAL_SEARCH = level %d, %d candidates, %d batches; want 1, 3, 3", result.Level, result.Candidates, len(result.Batches))
+	}
+	if top := result.Batches[0].Reports[0]; top.Community.Title != "C1" || top.Rank != 1 || top.Score < 0.99 {
+		t.Errorf("top report = %v, want C1 with score 1", top)
+	}
+
+	resp = mustSendCommand(t, conn, pb.CommandType_CMD_GLOBAL_SEARCH, &pb.GlobalSearchRequest{Level: 3})
+	mustUnmarshal(t, resp.Payload, &result)
+	if result.Level != 1 || result.RequestedLevel != 3 {
+		t.Errorf("GLOBAL_SEARCH of a missing level searched %d, want fallback to 1", result.Level)
+	}
+
+	resp = mustSendCommand(t, conn, pb.CommandType_CMD_GLOBAL_SEARCH, &pb.GlobalSearchRequest{TokenBudget: -1})
+	if resp.CmdType != pb.CommandType_CMD_ERROR {
+		t.Errorf("GLOBAL_SEARCH with a negative budget: expected error, got %v", resp.CmdType)
+	}
+}
+
+func TestServer_QueryTokenBudget(t *testing.T) {
+	srv, addr := createTestServer(t)
+	defer srv.Stop()
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("Dial error: %v", err)
+	}
+	defer closeSilently(conn)
+
+	vec := make([]float32, testVectorDim)
+	vec[0] = 1
+	resp := mustSendCommand(t, conn, pb.CommandType_CMD_ADD_ENTITY, &pb.AddEntityRequest{
+		ExternalId: "bank", Title: "Bank", Type: "org", Description: "a lender", Embedding: vec,
+	})
+	if resp.CmdType == pb.CommandType_CMD_ERROR {
+		t.Fatalf("ADD_ENTITY failed: %s", resp.Payload)
+	}
+
+	resp = mustSendCommand(t, conn, pb.CommandType_CMD_QUERY, &pb.QueryRequest{
+		QueryVector: vec, SearchTypes: []string{"entity"}, TokenBudget: 100, ContextFormat: "markdown",
+	})
+	if resp.CmdType != pb.CommandType_CMD_QUERY_RESPONSE {
+		t.Fatalf("QUERY failed: %s", resp.Payload)
+	}
+	var result pb.QueryResponse
+	mustUnmarshal(t, resp.Payload, &result)
+	if result.TokenUsage == nil || result.TokenUsage.Entities != 4 || result.TokenUsage.Total != 4 {
+		t.Errorf("token usage = %v, want 4 entity tokens", result.TokenUsage)
+	}
+	if !strings.Contains(result.Context, "| BANK | org | a lender |") {
+		t.Errorf("context = %q, want a markdown row for BANK", result.Context)
+	}
+
+	resp = mustSendCommand(t, conn, pb.CommandType_CMD_QUERY, &pb.QueryRequest{QueryVector: vec, ContextFormat: "html"})
+	if resp.CmdType != pb.CommandType_CMD_ERROR {
+		t.Errorf("QUERY with an unknown context format = %v, want an error", resp.CmdType)
+	}
+}
+
+func TestServer_QueryRerank(t *testing.T) {
+	srv, addr := createTestServer(t)
+	defer srv.Stop()
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("Dial error: %v", err)
+	}
+	defer closeSilently(conn)
+
+	resp := mustSendCommand(t, conn, pb.CommandType_CMD_ADD_DOCUMENT, &pb.AddDocumentRequest{ExternalId: "doc", Filename: "doc.txt"})
+	var doc pb.OkWithID
+	mustUnmarshal(t, resp.Payload, &doc)
+
+	// Two identical chunks of one document
+	vec := make([]float32, testVectorDim)
+	vec[0] = 1
+	for _, extID := range []string{"chunk-1", "chunk-2"} {
+		resp = mustSendCommand(t, conn, pb.CommandType_CMD_ADD_TEXTUNIT, &pb.AddTextUnitRequest{
+			ExternalId: extID, DocumentId: doc.Id, Content: "same text", Embedding: vec, TokenCount: 2,
+		})
+		if resp.CmdType == pb.CommandType_CMD_ERROR {
+			t.Fatalf("ADD_TEXTUNIT failed: %s", resp.Payload)
+		}
+	}
+
+	resp = mustSendCommand(t, conn, pb.CommandType_CMD_QUERY, &pb.QueryRequest{
+		QueryVector: vec, SearchTypes: []string{"textunit"}, Rerank: "mmr", DedupThreshold: 0.99,
+	})
+	if resp.CmdType != pb.CommandType_CMD_QUERY_RESPONSE {
+		t.Fatalf("QUERY failed: %s", resp.Payload)
+	}
+	var result pb.QueryResponse
+	mustUnmarshal(t, resp.Payload, &result)
+	if len(result.Textunits) != 1 {
+		t.Fatalf("QUERY returned %d text units, want the duplicate dropped", len(result.Textunits))
+	}
+
+	resp = mustSendCommand(t, conn, pb.CommandType_CMD_EXPLAIN, &pb.ExplainRequest{QueryId: result.QueryId})
+	var explain pb.ExplainResponse
+	mustUnmarshal(t, resp.Payload, &explain)
+	if len(explain.Demotions) != 1 || explain.Demotions[0].Reason != "duplicate" ||
+		explain.Demotions[0].SimilarTo != result.Textunits[0].Textunit.Id {
+		t.Errorf("EXPLAIN demotions = %v, want one duplicate of the kept text unit", explain.Demotions)
+	}
+
+	resp = mustSendCommand(t, conn, pb.CommandType_CMD_QUERY, &pb.QueryRequest{QueryVector: vec, Rerank: "shuffle"})
+	if resp.CmdType != pb.CommandType_CMD_ERROR {
+		t.Errorf("QUERY with an unknown rerank mode = %v, want an error", resp.CmdType)
+	}
+}
+
+func TestServer_QueryCache(t *testing.T) {
+	srv, addr := createTestServer(t)
+	defer srv.Stop()
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("Dial error: %v", err)
+	}
+	defer closeSilently(conn)
+
+	vec := make([]float32, testVectorDim)
+	vec[0] = 1
+	resp := mustSendCommand(t, conn, pb.CommandType_CMD_ADD_ENTITY, &pb.AddEntityRequest{
+		ExternalId: "e1", Title: "E1", Type: "t", Embedding: vec,
+	})
+	if resp.CmdType == pb.CommandType_CMD_ERROR {
+		t.Fatalf("ADD_ENTITY failed: %s", resp.Payload)
+	}
+
+	resp = mustSendCommand(t, conn, pb.CommandType_CMD_SET_QUERY_CACHE, &pb.SetQueryCacheRequest{MaxEntries: 8, Ttl: 60})
+	if resp.CmdType != pb.CommandType_CMD_OK {
+		t.Fatalf("SET_QUERY_CACHE failed: %s", resp.Payload)
+	}
+
+	query := &pb.QueryRequest{QueryVector: vec, SearchTypes: []string{"entity"}}
+	for i, wantHit := range []bool{false, true} {
+		resp = mustSendCommand(t, conn, pb.CommandType_CMD_QUERY, query)
+		if resp.CmdType != pb.CommandType_CMD_QUERY_RESPONSE {
+			t.Fatalf("QUERY failed: %s", resp.Payload)
+		}
+		var result pb.QueryResponse
+		mustUnmarshal(t, resp.Payload, &result)
+		if result.Stats.GetCacheHit() != wantHit || len(result.Entities) != 1 {
+			t.Errorf("query %d: cache_hit = %v with %d entities, want %v with 1",
+				i, result.Stats.GetCacheHit(), len(result.Entities), wantHit)
+		}
+	}
+
+	resp = mustSendCommand(t, conn, pb.CommandType_CMD_SESSION_INFO, nil)
+	var info pb.SessionInfo
+	mustUnmarshal(t, resp.Payload, &info)
+	if info.QueryCacheSize != 8 || info.QueryCacheTtl != 60 || info.QueryCacheEntries != 1 ||
+		info.QueryCacheHits != 1 || info.QueryCacheMisses != 1 {
+		t.Errorf("SESSION_INFO cache = %d/%ds, %d entries, %d hits, %d misses; want 8/60s, 1, 1, 1",
+			info.QueryCacheSize, info.QueryCacheTtl, info.QueryCacheEntries, info.QueryCacheHits, info.QueryCacheMisses)
+	}
+
+	resp = mustSendCommand(t, conn, pb.CommandType_CMD_SET_QUERY_CACHE, &pb.SetQueryCacheRequest{Ttl: -1})
+	if resp.CmdType != pb.CommandType_CMD_ERROR {
+		t.Errorf("SET_QUERY_CACHE with a negative ttl = %v, want an error", resp.CmdType)
+	}
+}
+
+func TestServer_QueryBatch(t *testing.T) {
+	srv, addr := createTestServer(t)
+	defer srv.Stop()
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("Dial error: %v", err)
+	}
+	defer closeSilently(conn)
+
+	vecs := make([][]float32, 2)
+	ids := make([]uint64, 2)
+	for i := range vecs {
+		vecs[i] = make([]float32, testVectorDim)
+		vecs[i][i] = 1
+		resp := mustSendCommand(t, conn, pb.CommandType_CMD_ADD_ENTITY, &pb.AddEntityRequest{
+			ExternalId: itoa(i), Title: "E" + itoa(i), Type: "t", Embedding: vecs[i],
+		})
+		var ok pb.OkWithID
+		mustUnmarshal(t, resp.Payload, &ok)
+		ids[i] = ok.Id
+	}
+
+	// Two full queries, one without a vector, then one query per shared vector
+	resp := mustSendCommand(t, conn, pb.CommandType_CMD_QUERY_BATCH, &pb.QueryBatchRequest{
+		Queries: []*pb.QueryRequest{
+			{QueryVector: vecs[0], SearchTypes: []string{"entity"}, TopK: 1},
+			{SearchTypes: []string{"entity"}},
+		},
+		Shared:       &pb.QueryRequest{SearchTypes: []string{"entity"}, TopK: 1},
+		QueryVectors: []*pb.SearchVector{{Values: vecs[1]}, {Values: vecs[0]}},
+		Parallelism:  2,
+	})
+	if resp.CmdType != pb.CommandType_CMD_QUERY_BATCH_RESPONSE {
+		t.Fatalf("QUERY_BATCH failed: %s", resp.Payload)
+	}
+	var batch pb.QueryBatchResponse
+	mustUnmarshal(t, resp.Payload, &batch)
+	if len(batch.Results) != 4 {
+		t.Fatalf("QUERY_BATCH returned %d results, want 4", len(batch.Results))
+	}
+	if batch.Results[1].Response != nil || !strings.Contains(batch.Results[1].Error, "query_vector") {
+		t.Errorf("query without a vector = %v, want an error", batch.Results[1])
+	}
+	queryIDs := make(map[uint64]bool)
+	for i, want := range map[int]uint64{0: ids[0], 2: ids[1], 3: ids[0]} {
+		r := batch.Results[i].GetResponse()
+		if r == nil || len(r.Entities) == 0 || r.Entities[0].Entity.Id != want {
+			t.Errorf("result %d = %v, want top entity %d", i, batch.Results[i], want)
+			continue
+		}
+		queryIDs[r.QueryId] = true
+
+		resp = mustSendCommand(t, conn, pb.CommandType_CMD_EXPLAIN, &pb.ExplainRequest{QueryId: r.QueryId})
+		if resp.CmdType != pb.CommandType_CMD_EXPLAIN_RESPONSE {
+			t.Errorf("EXPLAIN of result %d failed: %s", i, resp.Payload)
+		}
+	}
+	if len(queryIDs) != 3 {
+		t.Errorf("results share query IDs: %v", queryIDs)
+	}
+
+	for name, req := range map[string]*pb.QueryBatchRequest{
+		"empty":              {},
+		"vectors, no shared": {QueryVectors: []*pb.SearchVector{{Values: vecs[0]}}},
+		"bad parallelism":    {Queries: []*pb.QueryRequest{{QueryVector: vecs[0]}}, Parallelism: -1},
+	} {
+		resp = mustSendCommand(t, conn, pb.CommandType_CMD_QUERY_BATCH, req)
+		if resp.CmdType != pb.CommandType_CMD_ERROR {
+			t.Errorf("%s: QUERY_BATCH = %v, want an error", name, resp.CmdType)
+		}
+	}
+}
+
+func TestServer_QueryBatchConcurrencySlots(t *testing.T) {
+	srv := NewServer(engine.NewEngine(testVectorDim))
+	vec := make([]float32, testVectorDim)
+	vec[0] = 1
+	if _, err := srv.engine.AddEntity("batch", "e1", "E1", "t", "", vec); err != nil {
+		t.Fatalf("AddEntity failed: %v", err)
+	}
+
+	// Cap 3: the batch holds one slot and another command a second, so
+	// only one further worker can run
+	state := &connState{slots: make(chan struct{}, 3)}
+	state.slots <- struct{}{}
+	state.slots <- struct{}{}
+	env := &pb.Envelope{SessionId: "batch", Payload: marshalPayload(t, &pb.QueryBatchRequest{
+		Shared:       &pb.QueryRequest{SearchTypes: []string{"entity"}, TopK: 1},
+		QueryVectors: []*pb.SearchVector{{Values: vec}, {Values: vec}, {Values: vec}, {Values: vec}},
+		Parallelism:  8,
+	})}
+	cmdType, payload := srv.handleQueryBatch(env, state)
+	if cmdType != pb.CommandType_CMD_QUERY_BATCH_RESPONSE {
+		t.Fatalf("QUERY_BATCH failed: %s", payload)
+	}
+	var batch pb.QueryBatchResponse
+	mustUnmarshal(t, payload, &batch)
+	for i, r := range batch.Results {
+		if len(r.GetResponse().GetEntities()) != 1 {
+			t.Errorf("result %d = %v, want one entity", i, r)
+		}
+	}
+	if len(state.slots) != 2 {
+		t.Errorf("batch left %d slots taken, want the 2 held before", len(state.slots))
+	}
+
+	if taken := acquireSlots(state.slots, 5); taken != 1 {
+		t.Errorf("acquireSlots(5) with one free slot = %d, want 1", taken)
+	}
+	if taken := acquireSlots(state.slots, -1); taken != 0 {
+		t.Errorf("acquireSlots(-1) = %d, want 0", taken)
+	}
+	releaseSlots(state.slots, 3)
+	if len(state.slots) != 0 {
+		t.Errorf("%d slots taken after release, want 0", len(state.slots))
+	}
+}
+
+func TestServer_RebuildIndexOnline(t *testing.T) {
+	srv, addr := createTestServer(t)
+	defer srv.Stop()
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("Dial error: %v", err)
+	}
+	defer closeSilently(conn)
+
+	resp := mustSendCommand(t, conn, pb.CommandType_CMD_CREATE_SESSION, &pb.CreateSessionRequest{})
+	if resp.CmdType != pb.CommandType_CMD_OK {
+		t.Fatalf("CREATE_SESSION failed: %v", resp.CmdType)
+	}
+
+	resp = mustSendCommand(t, conn, pb.CommandType_CMD_REBUILD_INDEX, &pb.RebuildIndexRequest{
+		EntityIndex: &pb.IndexOptions{Quantization: "int4"},
+	})
+	if resp.CmdType != pb.CommandType_CMD_ERROR {
+		t.Errorf("expected error for unknown quantization, got %v", resp.CmdType)
+	}
+
+	resp = mustSendCommand(t, conn, pb.CommandType_CMD_REBUILD_INDEX, &pb.RebuildIndexRequest{
+		EntityIndex: &pb.IndexOptions{M: 8, Metric: "dot"},
+		Async:       true,
+	})
+	if resp.CmdType != pb.CommandType_CMD_REBUILD_STATUS_RESPONSE {
+		t.Fatalf("async REBUILD_INDEX failed: %v", resp.CmdType)
+	}
+	var started pb.RebuildStatusResponse
+	mustUnmarshal(t, resp.Payload, &started)
+	if len(started.Tasks) != 1 || started.Tasks[0].TaskId == "" {
+		t.Fatalf("unexpected rebuild response: %v", started.Tasks)
+	}
+
+	var task *pb.RebuildTask
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		resp = mustSendCommand(t, conn, pb.CommandType_CMD_REBUILD_STATUS, &pb.RebuildStatusRequest{TaskId: started.Tasks[0].TaskId})
+		var status pb.RebuildStatusResponse
+		mustUnmarshal(t, resp.Payload, &status)
+		if len(status.Tasks) == 1 && status.Tasks[0].Status != "running" {
+			task = status.Tasks[0]
+			break
+		}
+	}
+	if task == nil || task.Status != "complete" || task.Progress != 1 || task.EndTime == 0 {
+		t.Fatalf("rebuild did not complete: %v", task)
+	}
+
+	resp = mustSendCommand(t, conn, pb.CommandType_CMD_SESSION_INFO, nil)
+	var info pb.SessionInfo
+	mustUnmarshal(t, resp.Payload, &info)
+	if info.EntityIndex.GetM() != 8 || info.EntityIndex.GetMetric() != "dot" || info.TextunitIndex.GetMetric() != "cosine" {
+		t.Errorf("unexpected options after rebuild: entity %v, textunit %v", info.EntityIndex, info.TextunitIndex)
+	}
+
+	resp = mustSendCommand(t, conn, pb.CommandType_CMD_REBUILD_STATUS, &pb.RebuildStatusRequest{TaskId: "rebuild_missing_1"})
+	if resp.CmdType != pb.CommandType_CMD_ERROR {
+		t.Errorf("expected error for unknown task, got %v", resp.CmdType)
+	}
+}
+
+func TestServer_BulkLoad(t *testing.T) {
+	srv, addr := createTestServer(t)
+	defer srv.Stop()
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("Dial error: %v", err)
+	}
+	defer closeSilently(conn)
+
+	resp := mustSendCommand(t, conn, pb.CommandType_CMD_CREATE_SESSION, &pb.CreateSessionRequest{})
+	if resp.CmdType != pb.CommandType_CMD_OK {
+		t.Fatalf("CREATE_SESSION failed: %v", resp.CmdType)
+	}
+	entities := func(prefix string, n int) []*pb.AddEntityRequest {
+		out := make([]*pb.AddEntityRequest, n)
+		for i := range out {
+			embedding := make([]float32, testVectorDim)
+			embedding[i%testVectorDim] = float32(i + 1)
+			out[i] = &pb.AddEntityRequest{ExternalId: fmt.Sprintf("%s%d", prefix, i), Title: fmt.Sprintf("%s %d", prefix, i), Type: "t", Embedding: embedding}
+		}
+		return out
+	}
+	sessionInfo := func() *pb.SessionInfo {
+		resp := mustSendCommand(t, conn, pb.CommandType_CMD_SESSION_INFO, nil)
+		var info pb.SessionInfo
+		mustUnmarshal(t, resp.Payload, &info)
+		return &info
+	}
+
+	// Per request: the vectors are built before the response
+	resp = mustSendCommand(t, conn, pb.CommandType_CMD_MSET_ENTITIES, &pb.MSetEntitiesRequest{Entities: entities("req", 10), Bulk: true})
+	if resp.CmdType != pb.CommandType_CMD_ENTITIES_RESPONSE {
+		t.Fatalf("bulk MSET_ENTITIES failed: %v", resp.CmdType)
+	}
+	if info := sessionInfo(); info.EntityCount != 10 || info.BulkLoading || info.PendingVectors != 0 {
+		t.Errorf("after bulk MSET: %d entities, bulk %v, %d pending; want 10, false, 0", info.EntityCount, info.BulkLoading, info.PendingVectors)
+	}
+
+	// Per session: the vectors are built on commit
+	resp = mustSendCommand(t, conn, pb.CommandType_CMD_BULK_COMMIT, &pb.BulkCommitRequest{})
+	if resp.CmdType != pb.CommandType_CMD_ERROR {
+		t.Errorf("expected error for commit without a bulk load, got %v", resp.CmdType)
+	}
+	resp = mustSendCommand(t, conn, pb.CommandType_CMD_BULK_BEGIN, nil)
+	if resp.CmdType != pb.CommandType_CMD_OK {
+		t.Fatalf("BULK_BEGIN failed: %v", resp.CmdType)
+	}
+	resp = mustSendCommand(t, conn, pb.CommandType_CMD_MSET_ENTITIES, &pb.MSetEntitiesRequest{Entities: entities("ses", 20), Bulk: true})
+	if resp.CmdType != pb.CommandType_CMD_ENTITIES_RESPONSE {
+		t.Fatalf("MSET_ENTITIES during bulk load failed: %v", resp.CmdType)
+	}
+	if info := sessionInfo(); info.EntityCount != 30 || !info.BulkLoading || info.PendingVectors != 20 {
+		t.Errorf("during bulk load: %d entities, bulk %v, %d pending; want 30, true, 20", info.EntityCount, info.BulkLoading, info.PendingVectors)
+	}
+	resp = mustSendCommand(t, conn, pb.CommandType_CMD_BULK_COMMIT, &pb.BulkCommitRequest{Workers: 2})
+	if resp.CmdType != pb.CommandType_CMD_REBUILD_STATUS_RESPONSE {
+		t.Fatalf("BULK_COMMIT failed: %v", resp.CmdType)
+	}
+	var status pb.RebuildStatusResponse
+	mustUnmarshal(t, resp.Payload, &status)
+	if len(status.Tasks) != 1 || status.Tasks[0].Status != "complete" || status.Tasks[0].Progress != 1 {
+		t.Errorf("unexpected bulk commit response: %v", status.Tasks)
+	}
+	if info := sessionInfo(); info.BulkLoading || info.PendingVectors != 0 {
+		t.Errorf("after commit: bulk %v, %d pending; want false, 0", info.BulkLoading, info.PendingVectors)
+	}
+
+	resp = mustSendCommand(t, conn, pb.CommandType_CMD_REBUILD_STATUS, &pb.RebuildStatusRequest{})
+	mustUnmarshal(t, resp.Payload, &status)
+	if len(status.Tasks) != 2 {
+		t.Errorf("REBUILD_STATUS lists %d tasks, want 2 bulk builds", len(status.Tasks))
+	}
+}
+
+func TestServer_RecallCheck(t *testing.T) {
+	srv, addr := createTestServer(t)
+	defer srv.Stop()
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("Dial error: %v", err)
+	}
+	defer closeSilently(conn)
+
+	for i := 0; i < 30; i++ {
+		embedding := make([]float32, testVectorDim)
+		embedding[i%testVectorDim] = 1
+		embedding[(i+1)%testVectorDim] = float32(i)
+		resp := mustSendCommand(t, conn, pb.CommandType_CMD_ADD_ENTITY, &pb.AddEntityRequest{
+			ExternalId: fmt.Sprintf("ent-%d", i), Title: fmt.Sprintf("Entity %d", i), Type: "t", Embedding: embedding,
+		})
+		if resp.CmdType != pb.CommandType_CMD_ENTITY_RESPONSE && resp.CmdType != pb.CommandType_CMD_OK {
+			t.Fatalf("ADD_ENTITY failed: %v", resp.CmdType)
+		}
+	}
+
+	resp := mustSendCommand(t, conn, pb.CommandType_CMD_RECALL_CHECK, &pb.RecallCheckRequest{K: 3, Samples: 10})
+	if resp.CmdType != pb.CommandType_CMD_RECALL_CHECK_RESPONSE {
+		t.Fatalf("RECALL_CHECK failed: %v", resp.CmdType)
+	}
+	var result pb.RecallCheckResponse
+	mustUnmarshal(t, resp.Payload, &result)
+	if len(result.Indices) != 1 {
+		t.Fatalf("RECALL_CHECK reported %d indices, want the entity index", len(result.Indices))
+	}
+	r := result.Indices[0]
+	if r.Index != "entity" || r.Vectors != 30 || r.Queries != 10 || r.K != 3 || r.Recall < 0.5 || r.MinRecall > r.Recall || r.Visited.GetAvg() == 0 {
+		t.Errorf("unexpected entity recall: %v", r)
+	}
+
+	resp = mustSendCommand(t, conn, pb.CommandType_CMD_RECALL_CHECK, &pb.RecallCheckRequest{
+		Index:        "entity",
+		QueryVectors: []*pb.SearchVector{{Values: []float32{1, 2}}},
+	})
+	if resp.CmdType != pb.CommandType_CMD_ERROR {
+		t.Errorf("expected error for a wrong query dimension, got %v", resp.CmdType)
+	}
+}
+
+func TestServer_Search(t *testing.T) {
+	srv, addr := createTestServer(t)
+	defer srv.Stop()
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("Dial error: %v", err)
+	}
+	defer closeSilently(conn)
+
+	oneHot := func(i int) []float32 {
+		v := make([]float32, testVectorDim)
+		v[i] = 1
+		return v
+	}
+	for i := 0; i < 3; i++ {
+		resp := mustSendCommand(t, conn, pb.CommandType_CMD_ADD_ENTITY, &pb.AddEntityRequest{
+			ExternalId: fmt.Sprintf("search-ent-%d", i),
+			Title:      fmt.Sprintf("Search Entity %d", i),
+			Type:       "person",
+			Embedding:  oneHot(i),
+		})
+		if resp.CmdType != pb.CommandType_CMD_OK {
+			t.Fatalf("ADD_ENTITY failed: %v", resp.CmdType)
+		}
+	}
+
+	resp := mustSendCommand(t, conn, pb.CommandType_CMD_SEARCH, &pb.SearchRequest{
+		Index:        "entity",
+		QueryVectors: []*pb.SearchVector{{Values: oneHot(1)}, {Values: oneHot(2)}},
+		K:            2,
+	})
+	if resp.CmdType != pb.CommandType_CMD_SEARCH_RESPONSE {
+		t.Fatalf("SEARCH failed: %v", resp.CmdType)
+	}
+	var searchResp pb.SearchResponse
+	mustUnmarshal(t, resp.Payload, &searchResp)
+	if len(searchResp.Results) != 2 || len(searchResp.Results[0].Hits) != 2 {
+		t.Fatalf("unexpected results: %v", searchResp.Results)
+	}
+	if searchResp.Results[0].Hits[0].ExternalId != "search-ent-1" || searchResp.Results[1].Hits[0].ExternalId != "search-ent-2" {
+		t.Errorf("unexpected top hits: %v", searchResp.Results)
+	}
+	if searchResp.Results[0].Hits[0].Entity != nil {
+		t.Error("objects returned without include_objects")
+	}
+
+	resp = mustSendCommand(t, conn, pb.CommandType_CMD_SEARCH, &pb.SearchRequest{
+		Index:            "entity",
+		QueryVectors:     []*pb.SearchVector{{Values: oneHot(0)}},
+		K:                3,
+		MinSimilarity:    0.5,
+		HasMinSimilarity: true,
+		IncludeObjects:   true,
+	})
+	searchResp.Reset()
+	mustUnmarshal(t, resp.Payload, &searchResp)
+	if len(searchResp.Results) != 1 || len(searchResp.Results[0].Hits) != 1 {
+		t.Fatalf("threshold search: unexpected results: %v", searchResp.Results)
+	}
+	if ent := searchResp.Results[0].Hits[0].Entity; ent == nil || ent.ExternalId != "search-ent-0" {
+		t.Errorf("include_objects did not return the entity: %v", ent)
+	}
+
+	resp = mustSendCommand(t, conn, pb.CommandType_CMD_SEARCH, &pb.SearchRequest{
+		Index:        "entity",
+		QueryVectors: []*pb.SearchVector{{Values: oneHot(0)}},
+		Range:        true,
+	})
+	if resp.CmdType != pb.CommandType_CMD_ERROR {
+		t.Errorf("range search without min_similarity: expected error, got %v", resp.CmdType)
+	}
+}
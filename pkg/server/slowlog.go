@@ -0,0 +1,273 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/gibram-io/gibram/proto/gibrampb"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+// =============================================================================
+// Slow Log
+// =============================================================================
+
+// Slow log defaults
+const (
+	DefaultSlowLogThreshold = 10 * time.Millisecond
+	DefaultSlowLogMaxLen    = 128
+	defaultSlowLogGetCount  = 10
+	maxSlowLogArgsLen       = 256
+)
+
+// slowLog is a bounded ring buffer of commands that exceeded a latency
+// threshold. Entries are returned newest first.
+type slowLog struct {
+	mu        sync.Mutex
+	threshold time.Duration // negative = disabled
+	entries   []*pb.SlowLogEntry
+	next      int // ring write position
+	count     int
+	nextID    uint64
+}
+
+func newSlowLog(threshold time.Duration, maxLen int) *slowLog {
+	if maxLen <= 0 {
+		maxLen = DefaultSlowLogMaxLen
+	}
+	return &slowLog{
+		threshold: threshold,
+		entries:   make([]*pb.SlowLogEntry, maxLen),
+	}
+}
+
+// slow reports whether a command that took elapsed should be logged
+func (l *slowLog) slow(elapsed time.Duration) bool {
+	return l.threshold >= 0 && elapsed >= l.threshold
+}
+
+// add appends an entry, evicting the oldest when the buffer is full
+func (l *slowLog) add(entry *pb.SlowLogEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry.Id = l.nextID
+	l.nextID++
+	l.entries[l.next] = entry
+	l.next = (l.next + 1) % len(l.entries)
+	if l.count < len(l.entries) {
+		l.count++
+	}
+}
+
+// get returns up to n entries, newest first (n < 0 = all)
+func (l *slowLog) get(n int) []*pb.SlowLogEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if n < 0 || n > l.count {
+		n = l.count
+	}
+	out := make([]*pb.SlowLogEntry, 0, n)
+	for i := 1; i <= n; i++ {
+		idx := (l.next - i + len(l.entries)) % len(l.entries)
+		out = append(out, l.entries[idx])
+	}
+	return out
+}
+
+// len returns the number of entries held
+func (l *slowLog) len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.count
+}
+
+// reset drops all entries. Entry IDs keep increasing.
+func (l *slowLog) reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for i := range l.entries {
+		l.entries[i] = nil
+	}
+	l.next = 0
+	l.count = 0
+}
+
+// recordSlow adds a command to the slow log if it exceeded the threshold
+func (s *Server) recordSlow(env, response *pb.Envelope, state *connState, start time.Time, elapsed time.Duration) {
+	if !s.slowLog.slow(elapsed) {
+		return
+	}
+
+	entry := &pb.SlowLogEntry{
+		Timestamp:      start.Unix(),
+		DurationMicros: elapsed.Microseconds(),
+		SessionId:      env.SessionId,
+		Command:        commandName(env.CmdType),
+		Args:           commandArgs(env),
+	}
+	if state != nil {
+		entry.ClientAddr = state.remoteIP
+		if state.apiKey != nil {
+			entry.KeyId = state.apiKey.ID
+		}
+	}
+	if env.CmdType == pb.CommandType_CMD_QUERY && response.CmdType == pb.CommandType_CMD_QUERY_RESPONSE {
+		entry.QueryId = responseQueryID(response.Payload)
+	}
+	s.slowLog.add(entry)
+}
+
+// responseQueryID reads query_id (field 1) from an encoded QueryResponse
+// without decoding the result sets
+func responseQueryID(payload []byte) uint64 {
+	for len(payload) > 0 {
+		num, typ, n := protowire.ConsumeTag(payload)
+		if n < 0 {
+			return 0
+		}
+		payload = payload[n:]
+		if num == 1 && typ == protowire.VarintType {
+			v, n := protowire.ConsumeVarint(payload)
+			if n < 0 {
+				return 0
+			}
+			return v
+		}
+		n = protowire.ConsumeFieldValue(num, typ, payload)
+		if n < 0 {
+			return 0
+		}
+		payload = payload[n:]
+	}
+	return 0
+}
+
+// commandArgs summarizes a command's arguments for the slow log. Vectors
+// and bulk payloads are reduced to sizes.
+func commandArgs(env *pb.Envelope) string {
+	var args string
+	switch env.CmdType {
+	case pb.CommandType_CMD_QUERY:
+		var req pb.QueryRequest
+		if proto.Unmarshal(env.Payload, &req) != nil {
+			break
+		}
+		args = fmt.Sprintf("dim=%d types=%s top_k=%d k_hops=%d max_entities=%d max_textunits=%d max_communities=%d",
+			len(req.QueryVector), strings.Join(req.SearchTypes, ","), req.TopK, req.KHops,
+			req.MaxEntities, req.MaxTextunits, req.MaxCommunities)
+		if len(req.SeedEntityIds) > 0 {
+			args += fmt.Sprintf(" seeds=%d", len(req.SeedEntityIds))
+		}
+		if len(req.FilterEntityTypes) > 0 {
+			args += " entity_types=" + strings.Join(req.FilterEntityTypes, ",")
+		}
+		if len(req.FilterRelTypes) > 0 {
+			args += " rel_types=" + strings.Join(req.FilterRelTypes, ",")
+		}
+		if len(req.FilterDocumentIds) > 0 {
+			args += fmt.Sprintf(" documents=%d", len(req.FilterDocumentIds))
+		}
+		if len(req.FilterAttrs) > 0 {
+			args += fmt.Sprintf(" attrs=%d", len(req.FilterAttrs))
+		}
+		if req.QueryText != "" {
+			args += fmt.Sprintf(" text=%q", req.QueryText)
+		}
+	case pb.CommandType_CMD_EXPLAIN:
+		var req pb.ExplainRequest
+		if proto.Unmarshal(env.Payload, &req) == nil {
+			args = fmt.Sprintf("query_id=%d", req.QueryId)
+		}
+	case pb.CommandType_CMD_MSET_ENTITIES, pb.CommandType_CMD_MSET_DOCUMENTS,
+		pb.CommandType_CMD_MSET_TEXTUNITS, pb.CommandType_CMD_MSET_RELATIONSHIPS,
+		pb.CommandType_CMD_MGET_ENTITIES, pb.CommandType_CMD_MGET_DOCUMENTS,
+		pb.CommandType_CMD_MGET_TEXTUNITS, pb.CommandType_CMD_MGET_RELATIONSHIPS:
+		args = fmt.Sprintf("items=%d", commandCost(env))
+	case pb.CommandType_CMD_PIPELINE:
+		var req pb.PipelineRequest
+		if proto.Unmarshal(env.Payload, &req) == nil {
+			args = fmt.Sprintf("commands=%d", len(req.Commands))
+		}
+	case pb.CommandType_CMD_QUERY_BATCH:
+		var req pb.QueryBatchRequest
+		if proto.Unmarshal(env.Payload, &req) == nil {
+			args = fmt.Sprintf("queries=%d vectors=%d parallelism=%d", len(req.Queries), len(req.QueryVectors), req.Parallelism)
+		}
+	case pb.CommandType_CMD_LIST_ENTITIES:
+		var req pb.ListEntitiesRequest
+		if proto.Unmarshal(env.Payload, &req) == nil {
+			args = fmt.Sprintf("cursor=%d limit=%d", req.Cursor, req.Limit)
+		}
+	case pb.CommandType_CMD_LIST_RELATIONSHIPS:
+		var req pb.ListRelationshipsRequest
+		if proto.Unmarshal(env.Payload, &req) == nil {
+			args = fmt.Sprintf("cursor=%d limit=%d", req.Cursor, req.Limit)
+		}
+	case pb.CommandType_CMD_COMPUTE_COMMUNITIES:
+		var req pb.ComputeCommunitiesRequest
+		if proto.Unmarshal(env.Payload, &req) == nil {
+			args = fmt.Sprintf("resolution=%g iterations=%d", req.Resolution, req.Iterations)
+		}
+	case pb.CommandType_CMD_HIERARCHICAL_LEIDEN:
+		var req pb.HierarchicalLeidenRequest
+		if proto.Unmarshal(env.Payload, &req) == nil {
+			args = fmt.Sprintf("max_levels=%d resolution=%g", req.MaxLevels, req.Resolution)
+		}
+	case pb.CommandType_CMD_SAVE, pb.CommandType_CMD_BGSAVE:
+		var req pb.SaveRequest
+		if proto.Unmarshal(env.Payload, &req) == nil {
+			args = "path=" + req.Path
+		}
+	case pb.CommandType_CMD_BGRESTORE:
+		var req pb.RestoreRequest
+		if proto.Unmarshal(env.Payload, &req) == nil {
+			args = "path=" + req.Path
+		}
+	}
+
+	if args == "" && len(env.Payload) > 0 {
+		args = fmt.Sprintf("bytes=%d", len(env.Payload))
+	}
+	if len(args) > maxSlowLogArgsLen {
+		args = args[:maxSlowLogArgsLen] + "..."
+	}
+	return args
+}
+
+// =============================================================================
+// SLOWLOG Handlers
+// =============================================================================
+
+func (s *Server) handleSlowLogGet(env *pb.Envelope) (pb.CommandType, []byte) {
+	var req pb.SlowLogGetRequest
+	if err := proto.Unmarshal(env.Payload, &req); err != nil {
+		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+	}
+
+	count := int(req.Count)
+	if count == 0 {
+		count = defaultSlowLogGetCount
+	}
+	resp := &pb.SlowLogResponse{
+		Entries: s.slowLog.get(count),
+		Length:  int32(s.slowLog.len()),
+	}
+	data, _ := proto.Marshal(resp)
+	return pb.CommandType_CMD_SLOWLOG_RESPONSE, data
+}
+
+func (s *Server) handleSlowLogReset() (pb.CommandType, []byte) {
+	s.slowLog.reset()
+	return pb.CommandType_CMD_OK, s.okPayload(0)
+}
+
+func (s *Server) handleSlowLogLen() (pb.CommandType, []byte) {
+	data, _ := proto.Marshal(&pb.SlowLogResponse{Length: int32(s.slowLog.len())})
+	return pb.CommandType_CMD_SLOWLOG_RESPONSE, data
+}
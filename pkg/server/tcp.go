@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"net/http"
+	"runtime"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -18,7 +20,11 @@ import (
 	"github.com/gibram-io/gibram/pkg/engine"
 	"github.com/gibram-io/gibram/pkg/graph"
 	"github.com/gibram-io/gibram/pkg/logging"
+	"github.com/gibram-io/gibram/pkg/memory"
+	"github.com/gibram-io/gibram/pkg/metrics"
+	"github.com/gibram-io/gibram/pkg/store"
 	"github.com/gibram-io/gibram/pkg/types"
+	"github.com/gibram-io/gibram/pkg/vector"
 	pb "github.com/gibram-io/gibram/proto/gibrampb"
 	"golang.org/x/time/rate"
 	"google.golang.org/protobuf/proto"
@@ -41,7 +47,13 @@ var commandPermissions = map[pb.CommandType]string{
 	pb.CommandType_CMD_GET_RELATIONSHIP:    config.PermRead,
 	pb.CommandType_CMD_GET_COMMUNITY:       config.PermRead,
 	pb.CommandType_CMD_QUERY:               config.PermRead,
+	pb.CommandType_CMD_QUERY_BATCH:         config.PermRead,
 	pb.CommandType_CMD_EXPLAIN:             config.PermRead,
+	pb.CommandType_CMD_SEARCH:              config.PermRead,
+	pb.CommandType_CMD_PATH:                config.PermRead,
+	pb.CommandType_CMD_SUBGRAPH:            config.PermRead,
+	pb.CommandType_CMD_GLOBAL_SEARCH:       config.PermRead,
+	pb.CommandType_CMD_QUERY_GRAPH:         config.PermRead,
 	pb.CommandType_CMD_MGET_ENTITIES:       config.PermRead,
 	pb.CommandType_CMD_MGET_DOCUMENTS:      config.PermRead,
 	pb.CommandType_CMD_MGET_TEXTUNITS:      config.PermRead,
@@ -69,10 +81,14 @@ var commandPermissions = map[pb.CommandType]string{
 	pb.CommandType_CMD_HIERARCHICAL_LEIDEN:  config.PermWrite,
 	pb.CommandType_CMD_SET_SESSION_TTL:      config.PermWrite,
 	pb.CommandType_CMD_TOUCH_SESSION:        config.PermWrite,
+	pb.CommandType_CMD_SET_QUERY_CACHE:      config.PermWrite,
+	pb.CommandType_CMD_CREATE_SESSION:       config.PermWrite,
 	pb.CommandType_CMD_MSET_ENTITIES:        config.PermWrite,
 	pb.CommandType_CMD_MSET_DOCUMENTS:       config.PermWrite,
 	pb.CommandType_CMD_MSET_TEXTUNITS:       config.PermWrite,
 	pb.CommandType_CMD_MSET_RELATIONSHIPS:   config.PermWrite,
+	pb.CommandType_CMD_BULK_BEGIN:           config.PermWrite,
+	pb.CommandType_CMD_BULK_COMMIT:          config.PermWrite,
 	pb.CommandType_CMD_PIPELINE:             config.PermWrite,
 
 	// Admin operations
@@ -80,10 +96,15 @@ var commandPermissions = map[pb.CommandType]string{
 	pb.CommandType_CMD_BGSAVE:         config.PermAdmin,
 	pb.CommandType_CMD_BGRESTORE:      config.PermAdmin,
 	pb.CommandType_CMD_REBUILD_INDEX:  config.PermAdmin,
+	pb.CommandType_CMD_REBUILD_STATUS: config.PermRead,
 	pb.CommandType_CMD_WAL_CHECKPOINT: config.PermAdmin,
 	pb.CommandType_CMD_WAL_TRUNCATE:   config.PermAdmin,
 	pb.CommandType_CMD_WAL_ROTATE:     config.PermAdmin,
 	pb.CommandType_CMD_DELETE_SESSION: config.PermAdmin,
+	pb.CommandType_CMD_SLOWLOG_GET:    config.PermAdmin,
+	pb.CommandType_CMD_SLOWLOG_RESET:  config.PermAdmin,
+	pb.CommandType_CMD_SLOWLOG_LEN:    config.PermAdmin,
+	pb.CommandType_CMD_RECALL_CHECK:   config.PermAdmin,
 }
 
 // =============================================================================
@@ -98,6 +119,9 @@ const (
 	DefaultUnauthTimeout = 10 * time.Second
 	DefaultRateLimit     = 1000
 	DefaultRateBurst     = 100
+
+	DefaultAuthAttemptsPerMin = 30
+	DefaultAuthAttemptBurst   = 10
 )
 
 // =============================================================================
@@ -115,15 +139,34 @@ type Server struct {
 	requestID atomic.Uint64
 
 	// Security
-	apiKeyStore  *config.APIKeyStore
-	rateLimiters sync.Map // map[keyID]*rate.Limiter
+	apiKeyStore      *config.APIKeyStore
+	rateLimiters     sync.Map // map[keyID]*rate.Limiter
+	concurrencySlots sync.Map // map[keyID]chan struct{}
+	ipLimits         *ipLimits
+
+	// Metrics (optional)
+	metrics     *metrics.Collector
+	metricsHTTP *http.Server
+	metricsAddr string
+	activeConns atomic.Int64
+
+	// Slow command log
+	slowLog *slowLog
+
+	// Admin listener and readiness
+	adminHTTP  *http.Server
+	adminAddr  string
+	memTracker *memory.Tracker
+	started    atomic.Bool
+	recovering atomic.Int32
+	draining   atomic.Bool
 
 	// Backup state
 	backupInProgress atomic.Bool
-	backupType       string
-	backupStartTime  int64
-	lastSaveTime     int64
-	lastSavePath     string
+	backupType       atomic.Value // string, read by admin status
+	backupStartTime  atomic.Int64
+	lastSaveTime     atomic.Int64 // written by the BGSAVE goroutine
+	lastSavePath     atomic.Value // string
 
 	// Snapshot callback (accepts path)
 	snapshotFn func(path string) error
@@ -138,6 +181,9 @@ type Server struct {
 	unauthTimeout time.Duration
 	rateLimit     int
 	rateBurst     int
+	maxConnsPerIP int
+	authRate      rate.Limit
+	authBurst     int
 }
 
 // NewServer creates a new Protobuf server
@@ -157,6 +203,10 @@ func NewServerWithConfig(eng *engine.Engine, cfg *config.Config) *Server {
 		unauthTimeout: DefaultUnauthTimeout,
 		rateLimit:     DefaultRateLimit,
 		rateBurst:     DefaultRateBurst,
+		ipLimits:      newIPLimits(),
+		authRate:      rate.Every(time.Minute / DefaultAuthAttemptsPerMin),
+		authBurst:     DefaultAuthAttemptBurst,
+		slowLog:       newSlowLog(DefaultSlowLogThreshold, DefaultSlowLogMaxLen),
 	}
 
 	// Apply config if provided
@@ -176,6 +226,22 @@ func NewServerWithConfig(eng *engine.Engine, cfg *config.Config) *Server {
 		if cfg.Security.RateBurst > 0 {
 			s.rateBurst = cfg.Security.RateBurst
 		}
+		if cfg.Security.MaxConnsPerIP > 0 {
+			s.maxConnsPerIP = cfg.Security.MaxConnsPerIP
+		}
+		if cfg.Security.AuthAttemptsPerMin > 0 {
+			s.authRate = rate.Every(time.Minute / time.Duration(cfg.Security.AuthAttemptsPerMin))
+		}
+		if cfg.Security.AuthAttemptBurst > 0 {
+			s.authBurst = cfg.Security.AuthAttemptBurst
+		}
+		if cfg.SlowLog.Threshold != 0 || cfg.SlowLog.MaxLen > 0 {
+			threshold := DefaultSlowLogThreshold
+			if cfg.SlowLog.Threshold != 0 {
+				threshold = cfg.SlowLog.Threshold
+			}
+			s.slowLog = newSlowLog(threshold, cfg.SlowLog.MaxLen)
+		}
 
 		// Setup API key store
 		if cfg.HasAuth() {
@@ -211,6 +277,12 @@ func (s *Server) GetWAL() *backup.WAL {
 	return s.wal
 }
 
+// SetMetrics sets the metrics collector for command instrumentation and
+// limit rejections. It must be called before Start.
+func (s *Server) SetMetrics(collector *metrics.Collector) {
+	s.metrics = collector
+}
+
 // Start starts the server
 func (s *Server) Start(addr string) error {
 	var ln net.Listener
@@ -263,14 +335,32 @@ func (s *Server) Start(addr string) error {
 	}
 	logging.Info("  Max frame size: %d bytes", s.maxFrameSize)
 	logging.Info("  Rate limit: %d req/s (burst: %d)", s.rateLimit, s.rateBurst)
+	if s.maxConnsPerIP > 0 {
+		logging.Info("  Max connections per IP: %d", s.maxConnsPerIP)
+	}
+	if s.slowLog.threshold >= 0 {
+		logging.Info("  Slow log: > %s (max %d entries)", s.slowLog.threshold, len(s.slowLog.entries))
+	}
 
+	s.started.Store(true)
 	go s.acceptLoop()
 	return nil
 }
 
 // Stop stops the server
 func (s *Server) Stop() {
+	s.Drain()
 	close(s.stopCh)
+	if s.adminHTTP != nil {
+		if err := s.adminHTTP.Close(); err != nil {
+			logging.Error("Admin listener close error: %v", err)
+		}
+	}
+	if s.metricsHTTP != nil {
+		if err := s.metricsHTTP.Close(); err != nil {
+			logging.Error("Metrics listener close error: %v", err)
+		}
+	}
 	if s.listener != nil {
 		if err := s.listener.Close(); err != nil {
 			logging.Error("Listener close error: %v", err)
@@ -301,6 +391,8 @@ type connState struct {
 	authenticated bool
 	apiKey        *config.APIKey
 	limiter       *rate.Limiter
+	slots         chan struct{} // per-key concurrency semaphore (nil = unlimited)
+	remoteIP      string
 }
 
 func (s *Server) handleConnection(conn net.Conn) {
@@ -312,7 +404,21 @@ func (s *Server) handleConnection(conn net.Conn) {
 	}()
 
 	reader := bufio.NewReader(conn)
-	state := &connState{}
+	state := &connState{remoteIP: remoteIP(conn)}
+
+	// Enforce per-IP connection limit; rejected connections are not counted
+	if !s.ipLimits.acquireConn(state.remoteIP, s.maxConnsPerIP) {
+		s.countRejection(MetricConnLimitRejected)
+		s.writeRejection(conn, 0, "too many connections from this address")
+		return
+	}
+	defer s.ipLimits.releaseConn(state.remoteIP)
+
+	s.activeConns.Add(1)
+	defer s.activeConns.Add(-1)
+	if s.metrics != nil {
+		s.metrics.Counter(MetricConnections, 1)
+	}
 
 	// If auth is required, set short timeout for unauthenticated connections
 	if s.apiKeyStore != nil {
@@ -355,6 +461,13 @@ func (s *Server) handleConnection(conn net.Conn) {
 				return
 			}
 
+			// Slow down brute forcing: limit AUTH attempts per IP
+			if !s.ipLimits.allowAuth(state.remoteIP, s.authRate, s.authBurst) {
+				s.countRejection(MetricAuthLimitRejected)
+				s.writeRejection(conn, env.RequestId, "too many authentication attempts")
+				return
+			}
+
 			// Handle auth
 			response := s.handleAuth(env.Payload, state)
 			if err := s.writeEnvelope(conn, response); err != nil {
@@ -375,19 +488,28 @@ func (s *Server) handleConnection(conn net.Conn) {
 			continue
 		}
 
-		// Rate limiting (per API key)
-		if state.limiter != nil && !state.limiter.Allow() {
-			response := &pb.Envelope{
-				Version:   ProtocolVersion,
-				RequestId: env.RequestId,
-				CmdType:   pb.CommandType_CMD_ERROR,
-				Payload:   s.errorPayload("rate limit exceeded"),
+		// Rate limiting (per API key, weighted by command cost)
+		if state.limiter != nil {
+			if err := allowCost(state.limiter, commandCost(env)); err != nil {
+				s.countRejection(MetricRateLimitRejected)
+				if err := s.writeRejection(conn, env.RequestId, err.Error()); err != nil {
+					return
+				}
+				continue
 			}
-			if err := s.writeEnvelope(conn, response); err != nil {
-				logging.Error("Write rate limit response error: %v", err)
-				return
+		}
+
+		// Concurrency cap (per API key, across connections)
+		if state.slots != nil {
+			select {
+			case state.slots <- struct{}{}:
+			default:
+				s.countRejection(MetricConcurrencyRejected)
+				if err := s.writeRejection(conn, env.RequestId, "concurrency limit exceeded"); err != nil {
+					return
+				}
+				continue
 			}
-			continue
 		}
 
 		// Reset idle timeout
@@ -400,6 +522,9 @@ func (s *Server) handleConnection(conn net.Conn) {
 
 		// Process and send response
 		response := s.processEnvelope(env, state)
+		if state.slots != nil {
+			<-state.slots
+		}
 		if err := s.writeEnvelope(conn, response); err != nil {
 			logging.Error("Write response error: %v", err)
 			return
@@ -432,14 +557,9 @@ func (s *Server) handleAuth(payload []byte, state *connState) *pb.Envelope {
 	state.authenticated = true
 	state.apiKey = apiKey
 
-	// Get or create rate limiter for this API key
-	if limiter, ok := s.rateLimiters.Load(apiKey.ID); ok {
-		state.limiter = limiter.(*rate.Limiter)
-	} else {
-		limiter := rate.NewLimiter(rate.Limit(s.rateLimit), s.rateBurst)
-		s.rateLimiters.Store(apiKey.ID, limiter)
-		state.limiter = limiter
-	}
+	// Get or create rate limiter and concurrency slots for this API key
+	state.limiter = s.keyLimiter(apiKey)
+	state.slots = s.keySlots(apiKey)
 
 	// Build permissions list
 	var perms []string
@@ -457,6 +577,21 @@ func (s *Server) handleAuth(payload []byte, state *connState) *pb.Envelope {
 	return response
 }
 
+// writeRejection sends an error envelope for a request rejected by a limit
+func (s *Server) writeRejection(w io.Writer, requestID uint64, msg string) error {
+	response := &pb.Envelope{
+		Version:   ProtocolVersion,
+		RequestId: requestID,
+		CmdType:   pb.CommandType_CMD_ERROR,
+		Payload:   s.errorPayload(msg),
+	}
+	if err := s.writeEnvelope(w, response); err != nil {
+		logging.Error("Write rejection response error: %v", err)
+		return err
+	}
+	return nil
+}
+
 func (s *Server) readEnvelope(r io.Reader) (*pb.Envelope, error) {
 	// Read codec type (1 byte)
 	var codecByte [1]byte
@@ -546,6 +681,15 @@ func (s *Server) processEnvelope(env *pb.Envelope, state *connState) *pb.Envelop
 		RequestId: reqID,
 	}
 
+	start := time.Now()
+	defer func() {
+		elapsed := time.Since(start)
+		if s.metrics != nil {
+			s.recordCommand(env.CmdType, response.CmdType, elapsed)
+		}
+		s.recordSlow(env, response, state, start, elapsed)
+	}()
+
 	// RBAC: Check permission for this command
 	if state.apiKey != nil {
 		requiredPerm, hasMapping := commandPermissions[env.CmdType]
@@ -571,6 +715,9 @@ func (s *Server) processEnvelope(env *pb.Envelope, state *connState) *pb.Envelop
 		response.Payload = s.handleHealth()
 
 	// Session management commands
+	case pb.CommandType_CMD_CREATE_SESSION:
+		response.CmdType, response.Payload = s.handleCreateSession(env)
+
 	case pb.CommandType_CMD_LIST_SESSIONS:
 		response.CmdType, response.Payload = s.handleListSessions()
 
@@ -586,6 +733,9 @@ func (s *Server) processEnvelope(env *pb.Envelope, state *connState) *pb.Envelop
 	case pb.CommandType_CMD_TOUCH_SESSION:
 		response.CmdType, response.Payload = s.handleTouchSession(env)
 
+	case pb.CommandType_CMD_SET_QUERY_CACHE:
+		response.CmdType, response.Payload = s.handleSetQueryCache(env)
+
 	// Document operations (require session)
 	case pb.CommandType_CMD_ADD_DOCUMENT:
 		response.CmdType, response.Payload = s.handleAddDocument(env)
@@ -654,9 +804,21 @@ func (s *Server) processEnvelope(env *pb.Envelope, state *connState) *pb.Envelop
 	// Query operations (require session)
 	case pb.CommandType_CMD_QUERY:
 		response.CmdType, response.Payload = s.handleQuery(env)
+	case pb.CommandType_CMD_QUERY_BATCH:
+		response.CmdType, response.Payload = s.handleQueryBatch(env, state)
 
 	case pb.CommandType_CMD_EXPLAIN:
 		response.CmdType, response.Payload = s.handleExplain(env)
+	case pb.CommandType_CMD_SEARCH:
+		response.CmdType, response.Payload = s.handleSearch(env)
+	case pb.CommandType_CMD_PATH:
+		response.CmdType, response.Payload = s.handlePath(env)
+	case pb.CommandType_CMD_SUBGRAPH:
+		response.CmdType, response.Payload = s.handleSubgraph(env)
+	case pb.CommandType_CMD_QUERY_GRAPH:
+		response.CmdType, response.Payload = s.handleGraphQuery(env)
+	case pb.CommandType_CMD_GLOBAL_SEARCH:
+		response.CmdType, response.Payload = s.handleGlobalSearch(env)
 
 	// Bulk operations (require session)
 	case pb.CommandType_CMD_MSET_ENTITIES:
@@ -689,6 +851,12 @@ func (s *Server) processEnvelope(env *pb.Envelope, state *connState) *pb.Envelop
 	case pb.CommandType_CMD_LIST_RELATIONSHIPS:
 		response.CmdType, response.Payload = s.handleListRelationships(env)
 
+	case pb.CommandType_CMD_BULK_BEGIN:
+		response.CmdType, response.Payload = s.handleBulkBegin(env)
+
+	case pb.CommandType_CMD_BULK_COMMIT:
+		response.CmdType, response.Payload = s.handleBulkCommit(env)
+
 	// Pipeline (require session)
 	case pb.CommandType_CMD_PIPELINE:
 		response.CmdType, response.Payload = s.handlePipeline(env, state)
@@ -715,6 +883,8 @@ func (s *Server) processEnvelope(env *pb.Envelope, state *connState) *pb.Envelop
 	// Index management (require session)
 	case pb.CommandType_CMD_REBUILD_INDEX:
 		response.CmdType, response.Payload = s.handleRebuildIndex(env)
+	case pb.CommandType_CMD_REBUILD_STATUS:
+		response.CmdType, response.Payload = s.handleRebuildStatus(env)
 
 	// WAL operations (no session)
 	case pb.CommandType_CMD_WAL_CHECKPOINT:
@@ -726,6 +896,19 @@ func (s *Server) processEnvelope(env *pb.Envelope, state *connState) *pb.Envelop
 	case pb.CommandType_CMD_WAL_ROTATE:
 		response.CmdType, response.Payload = s.handleWALRotate()
 
+	// Diagnostics (no session)
+	case pb.CommandType_CMD_SLOWLOG_GET:
+		response.CmdType, response.Payload = s.handleSlowLogGet(env)
+
+	case pb.CommandType_CMD_SLOWLOG_RESET:
+		response.CmdType, response.Payload = s.handleSlowLogReset()
+
+	case pb.CommandType_CMD_SLOWLOG_LEN:
+		response.CmdType, response.Payload = s.handleSlowLogLen()
+
+	case pb.CommandType_CMD_RECALL_CHECK:
+		response.CmdType, response.Payload = s.handleRecallCheck(env)
+
 	default:
 		response.CmdType = pb.CommandType_CMD_ERROR
 		response.Payload = s.errorPayload(fmt.Sprintf("unknown command: %d", env.CmdType))
@@ -760,6 +943,7 @@ func (s *Server) handleInfo(env *pb.Envelope) []byte {
 			VectorDim:         int32(info.VectorDim),
 			SessionCount:      int32(info.SessionCount),
 		}
+		setVectorMemoryPB(resp, info.VectorMemory)
 		data, _ := proto.Marshal(resp)
 		return data
 	}
@@ -776,10 +960,21 @@ func (s *Server) handleInfo(env *pb.Envelope) []byte {
 		VectorDim:         int32(info.VectorDim),
 		SessionCount:      int32(info.SessionCount),
 	}
+	setVectorMemoryPB(resp, info.VectorMemory)
 	data, _ := proto.Marshal(resp)
 	return data
 }
 
+// setVectorMemoryPB copies vector memory statistics into an INFO response
+func setVectorMemoryPB(resp *pb.InfoResponse, mem types.VectorMemory) {
+	resp.VectorCount = uint64(mem.Vectors)
+	resp.QuantizedVectorCount = uint64(mem.QuantizedVectors)
+	resp.VectorTombstones = uint64(mem.Tombstones)
+	resp.VectorMemoryBytes = uint64(mem.Bytes)
+	resp.VectorFullPrecisionBytes = uint64(mem.FullPrecisionBytes)
+	resp.QuantizationRecall = mem.Recall
+}
+
 func (s *Server) handleHealth() []byte {
 	backupStatus := "not_configured"
 	if s.snapshotFn != nil {
@@ -805,6 +1000,92 @@ func (s *Server) handleHealth() []byte {
 // Session Management Handlers
 // =============================================================================
 
+func (s *Server) handleCreateSession(env *pb.Envelope) (pb.CommandType, []byte) {
+	var req pb.CreateSessionRequest
+	if err := proto.Unmarshal(env.Payload, &req); err != nil {
+		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+	}
+
+	sessionID := req.SessionId
+	if sessionID == "" {
+		id, err := s.getSessionID(env)
+		if err != nil {
+			return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+		}
+		sessionID = id
+	}
+
+	opts := store.DefaultSessionOptions()
+	opts.VectorDim = int(req.VectorDim)
+	if err := applyIndexOptionsPB(&opts, req.TextunitIndex, req.EntityIndex, req.CommunityIndex); err != nil {
+		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+	}
+
+	if err := s.engine.CreateSession(sessionID, opts); err != nil {
+		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+	}
+	return pb.CommandType_CMD_OK, s.okPayload(0)
+}
+
+// applyIndexOptionsPB replaces the configuration of each index given on
+// the wire, starting from the defaults; nil options leave an index as is
+func applyIndexOptionsPB(opts *store.SessionOptions, textunit, entity, community *pb.IndexOptions) error {
+	for _, idx := range []struct {
+		name string
+		req  *pb.IndexOptions
+		cfg  *vector.HNSWConfig
+	}{
+		{store.IndexTextUnit, textunit, &opts.TextUnitIndex},
+		{store.IndexEntity, entity, &opts.EntityIndex},
+		{store.IndexCommunity, community, &opts.CommunityIndex},
+	} {
+		if idx.req == nil {
+			continue
+		}
+		cfg := vector.DefaultHNSWConfig()
+		metric, err := vector.ParseMetric(idx.req.Metric)
+		if err != nil {
+			return fmt.Errorf("%s index: %w", idx.name, err)
+		}
+		cfg.Metric = metric
+		if idx.req.M > 0 {
+			cfg.SetM(int(idx.req.M))
+		}
+		if idx.req.EfConstruction > 0 {
+			cfg.EfConstruction = int(idx.req.EfConstruction)
+		}
+		if idx.req.EfSearch > 0 {
+			cfg.EfSearch = int(idx.req.EfSearch)
+		}
+		quant, err := vector.ParseQuantization(idx.req.Quantization)
+		if err != nil {
+			return fmt.Errorf("%s index: %w", idx.name, err)
+		}
+		cfg.Quantization = quant
+		cfg.PQSubvectors = int(idx.req.PqSubvectors)
+		cfg.KeepFullVectors = idx.req.KeepFullVectors
+		cfg.TombstoneRatio = idx.req.TombstoneRatio
+		*idx.cfg = cfg
+	}
+	return nil
+}
+
+// indexOptionsPB converts an index configuration for the wire
+func indexOptionsPB(config types.IndexConfig) *pb.IndexOptions {
+	return &pb.IndexOptions{
+		Metric:         config.Metric,
+		M:              uint32(config.M),
+		EfConstruction: uint32(config.EfConstruction),
+		EfSearch:       uint32(config.EfSearch),
+
+		Quantization:    config.Quantization,
+		PqSubvectors:    uint32(config.PQSubvectors),
+		KeepFullVectors: config.KeepFullVectors,
+
+		TombstoneRatio: config.TombstoneRatio,
+	}
+}
+
 func (s *Server) handleListSessions() (pb.CommandType, []byte) {
 	sessions := s.engine.ListSessions()
 
@@ -824,6 +1105,17 @@ func (s *Server) handleListSessions() (pb.CommandType, []byte) {
 			EntityCount:       uint64(sess.EntityCount),
 			RelationshipCount: uint64(sess.RelationshipCount),
 			CommunityCount:    uint64(sess.CommunityCount),
+			VectorDim:         uint32(sess.VectorDim),
+			BulkLoading:       sess.BulkLoading,
+			PendingVectors:    uint64(sess.PendingVectors),
+			TextunitIndex:     indexOptionsPB(sess.TextUnitIndex),
+			EntityIndex:       indexOptionsPB(sess.EntityIndex),
+			CommunityIndex:    indexOptionsPB(sess.CommunityIndex),
+			QueryCacheSize:    uint32(sess.QueryCacheSize),
+			QueryCacheTtl:     sess.QueryCacheTTL,
+			QueryCacheEntries: uint64(sess.QueryCacheEntries),
+			QueryCacheHits:    sess.QueryCacheHits,
+			QueryCacheMisses:  sess.QueryCacheMisses,
 		}
 	}
 
@@ -853,6 +1145,17 @@ func (s *Server) handleSessionInfo(env *pb.Envelope) (pb.CommandType, []byte) {
 		EntityCount:       uint64(info.EntityCount),
 		RelationshipCount: uint64(info.RelationshipCount),
 		CommunityCount:    uint64(info.CommunityCount),
+		VectorDim:         uint32(info.VectorDim),
+		BulkLoading:       info.BulkLoading,
+		PendingVectors:    uint64(info.PendingVectors),
+		TextunitIndex:     indexOptionsPB(info.TextUnitIndex),
+		EntityIndex:       indexOptionsPB(info.EntityIndex),
+		CommunityIndex:    indexOptionsPB(info.CommunityIndex),
+		QueryCacheSize:    uint32(info.QueryCacheSize),
+		QueryCacheTtl:     info.QueryCacheTTL,
+		QueryCacheEntries: uint64(info.QueryCacheEntries),
+		QueryCacheHits:    info.QueryCacheHits,
+		QueryCacheMisses:  info.QueryCacheMisses,
 	}
 
 	data, _ := proto.Marshal(resp)
@@ -904,6 +1207,24 @@ func (s *Server) handleTouchSession(env *pb.Envelope) (pb.CommandType, []byte) {
 	return pb.CommandType_CMD_OK, s.okPayload(0)
 }
 
+func (s *Server) handleSetQueryCache(env *pb.Envelope) (pb.CommandType, []byte) {
+	sessionID, err := s.getSessionID(env)
+	if err != nil {
+		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+	}
+
+	var req pb.SetQueryCacheRequest
+	if err := proto.Unmarshal(env.Payload, &req); err != nil {
+		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+	}
+
+	if err := s.engine.SetQueryCache(sessionID, int(req.MaxEntries), time.Duration(req.Ttl)*time.Second); err != nil {
+		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+	}
+
+	return pb.CommandType_CMD_OK, s.okPayload(0)
+}
+
 // =============================================================================
 // Document Handlers
 // =============================================================================
@@ -1378,7 +1699,102 @@ func (s *Server) handleQuery(env *pb.Envelope) (pb.CommandType, []byte) {
 		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
 	}
 
-	// Convert to types.QuerySpec
+	spec, err := querySpecFromPB(&req)
+	if err != nil {
+		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+	}
+
+	result, err := s.engine.Query(sessionID, spec)
+	if err != nil {
+		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+	}
+
+	data, _ := proto.Marshal(queryResponsePB(result))
+	return pb.CommandType_CMD_QUERY_RESPONSE, data
+}
+
+func (s *Server) handleQueryBatch(env *pb.Envelope, state *connState) (pb.CommandType, []byte) {
+	sessionID, err := s.getSessionID(env)
+	if err != nil {
+		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+	}
+
+	var req pb.QueryBatchRequest
+	if err := proto.Unmarshal(env.Payload, &req); err != nil {
+		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+	}
+	if len(req.QueryVectors) > 0 && req.Shared == nil {
+		return pb.CommandType_CMD_ERROR, s.errorPayload("query_vectors require a shared spec")
+	}
+	n := len(req.Queries) + len(req.QueryVectors)
+	if n > types.MaxBatchQueries {
+		return pb.CommandType_CMD_ERROR, s.errorPayload(fmt.Sprintf("too many queries: %d (max %d)", n, types.MaxBatchQueries))
+	}
+
+	// A query that does not convert fails alone, like one the engine rejects
+	specs := make([]types.QuerySpec, 0, n)
+	specErrs := make(map[int]string)
+	for i := 0; i < n; i++ {
+		var r *pb.QueryRequest
+		if i < len(req.Queries) {
+			r = req.Queries[i]
+		} else {
+			r = proto.Clone(req.Shared).(*pb.QueryRequest)
+			r.QueryVector = req.QueryVectors[i-len(req.Queries)].GetValues()
+		}
+		spec, err := querySpecFromPB(r)
+		if err != nil {
+			specErrs[i] = err.Error()
+			continue
+		}
+		specs = append(specs, spec)
+	}
+
+	// The batch runs in this command's concurrency slot; each further
+	// worker needs a free slot of the key, so a batch cannot exceed the
+	// key's concurrency cap. Out-of-range values are left to the engine.
+	parallelism := int(req.Parallelism)
+	if state.slots != nil && parallelism >= 0 && parallelism <= types.MaxBatchParallelism {
+		want := parallelism
+		if want == 0 {
+			want = min(runtime.GOMAXPROCS(0), types.MaxBatchParallelism)
+		}
+		extra := acquireSlots(state.slots, min(want, len(specs))-1)
+		defer releaseSlots(state.slots, extra)
+		parallelism = extra + 1
+	}
+
+	var results []types.BatchQueryResult
+	if len(specs) > 0 || n == 0 {
+		results, err = s.engine.QueryBatch(sessionID, specs, parallelism)
+		if err != nil {
+			return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+		}
+	}
+
+	resp := &pb.QueryBatchResponse{Results: make([]*pb.QueryBatchResult, n)}
+	next := 0
+	for i := range resp.Results {
+		if msg, ok := specErrs[i]; ok {
+			resp.Results[i] = &pb.QueryBatchResult{Error: msg}
+			continue
+		}
+		result := results[next]
+		next++
+		if result.Pack == nil {
+			resp.Results[i] = &pb.QueryBatchResult{Error: result.Error}
+			continue
+		}
+		resp.Results[i] = &pb.QueryBatchResult{Response: queryResponsePB(result.Pack)}
+	}
+
+	data, _ := proto.Marshal(resp)
+	return pb.CommandType_CMD_QUERY_BATCH_RESPONSE, data
+}
+
+// querySpecFromPB converts a query request from the wire, applying the
+// server defaults
+func querySpecFromPB(req *pb.QueryRequest) (types.QuerySpec, error) {
 	spec := types.QuerySpec{
 		QueryVector:    req.QueryVector,
 		TopK:           int(req.TopK),
@@ -1386,6 +1802,50 @@ func (s *Server) handleQuery(env *pb.Envelope) (pb.CommandType, []byte) {
 		MaxEntities:    int(req.MaxEntities),
 		MaxTextUnits:   int(req.MaxTextunits),
 		MaxCommunities: int(req.MaxCommunities),
+
+		FilterEntityTypes: req.FilterEntityTypes,
+		FilterDocumentIDs: req.FilterDocumentIds,
+		FilterAttrs:       req.FilterAttrs,
+
+		QueryText:     req.QueryText,
+		Fusion:        types.FusionMode(req.Fusion),
+		LexicalWeight: req.LexicalWeight,
+
+		Scoring:     types.ScoringStrategy(req.Scoring),
+		Decay:       req.Decay,
+		Aggregation: types.ScoreAggregation(req.Aggregation),
+
+		Expansion:      types.ExpansionMode(req.Expansion),
+		Damping:        req.Damping,
+		Iterations:     int(req.Iterations),
+		Restart:        types.RestartMode(req.Restart),
+		RestartWeights: req.RestartWeights,
+
+		TokenBudget:       int(req.TokenBudget),
+		EntityShare:       req.EntityShare,
+		RelationshipShare: req.RelationshipShare,
+		TextUnitShare:     req.TextunitShare,
+		CommunityShare:    req.CommunityShare,
+		ContextFormat:     types.ContextFormat(req.ContextFormat),
+
+		Rerank:         types.RerankMode(req.Rerank),
+		MMRLambda:      req.MmrLambda,
+		DedupThreshold: req.DedupThreshold,
+		MaxPerDocument: int(req.MaxPerDocument),
+
+		NoCache: req.NoCache,
+	}
+
+	switch spec.Fusion {
+	case "", types.FusionRRF, types.FusionWeighted:
+	default:
+		return spec, fmt.Errorf("unknown fusion mode: %s (want rrf or weighted)", req.Fusion)
+	}
+	if spec.LexicalWeight < 0 || spec.LexicalWeight > 1 {
+		return spec, fmt.Errorf("lexical_weight must be between 0 and 1")
+	}
+	if len(spec.QueryVector) == 0 && spec.QueryText == "" {
+		return spec, fmt.Errorf("query_vector or query_text is required")
 	}
 
 	// Convert search types
@@ -1417,19 +1877,29 @@ func (s *Server) handleQuery(env *pb.Envelope) (pb.CommandType, []byte) {
 		}
 	}
 
-	result, err := s.engine.Query(sessionID, spec)
-	if err != nil {
-		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
-	}
+	return spec, nil
+}
 
-	// Convert to protobuf response
+// queryResponsePB converts a query result for the wire
+func queryResponsePB(result *types.ContextPack) *pb.QueryResponse {
 	resp := &pb.QueryResponse{
 		QueryId: result.QueryID,
 		Stats: &pb.QueryStats{
 			DurationMicros:  result.Stats.DurationMicros,
 			VectorSearches:  int32(result.Stats.TextUnitsSearched + result.Stats.EntitiesSearched + result.Stats.CommunitiesSearched),
 			GraphTraversals: int32(result.Stats.EdgesScanned),
+			CacheHit:        result.Stats.CacheHit,
 		},
+		Context: result.Context,
+	}
+	if u := result.TokenUsage; u != nil {
+		resp.TokenUsage = &pb.TokenUsage{
+			Entities:      int32(u.Entities),
+			Relationships: int32(u.Relationships),
+			Textunits:     int32(u.TextUnits),
+			Communities:   int32(u.Communities),
+			Total:         int32(u.Total),
+		}
 	}
 
 	for _, tu := range result.TextUnits {
@@ -1437,6 +1907,7 @@ func (s *Server) handleQuery(env *pb.Envelope) (pb.CommandType, []byte) {
 			Textunit:   codec.TextUnitToProto(tu.TextUnit),
 			Similarity: tu.Similarity,
 			Hop:        int32(tu.Hop),
+			Score:      tu.Score,
 		})
 	}
 
@@ -1445,6 +1916,7 @@ func (s *Server) handleQuery(env *pb.Envelope) (pb.CommandType, []byte) {
 			Entity:     codec.EntityToProto(ent.Entity),
 			Similarity: ent.Similarity,
 			Hop:        int32(ent.Hop),
+			Score:      ent.Score,
 		})
 	}
 
@@ -1452,6 +1924,7 @@ func (s *Server) handleQuery(env *pb.Envelope) (pb.CommandType, []byte) {
 		resp.Communities = append(resp.Communities, &pb.CommunityResult{
 			Community:  codec.CommunityToProto(comm.Community),
 			Similarity: comm.Similarity,
+			Score:      comm.Score,
 		})
 	}
 
@@ -1463,8 +1936,292 @@ func (s *Server) handleQuery(env *pb.Envelope) (pb.CommandType, []byte) {
 		})
 	}
 
+	return resp
+}
+
+func (s *Server) handleSearch(env *pb.Envelope) (pb.CommandType, []byte) {
+	sessionID, err := s.getSessionID(env)
+	if err != nil {
+		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+	}
+
+	var req pb.SearchRequest
+	if err := proto.Unmarshal(env.Payload, &req); err != nil {
+		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+	}
+
+	spec := types.SearchSpec{
+		Index:          types.SearchType(req.Index),
+		QueryVectors:   make([][]float32, len(req.QueryVectors)),
+		K:              int(req.K),
+		Ef:             int(req.Ef),
+		Range:          req.Range,
+		IncludeObjects: req.IncludeObjects,
+
+		FilterEntityTypes: req.FilterEntityTypes,
+		FilterDocumentIDs: req.FilterDocumentIds,
+		FilterAttrs:       req.FilterAttrs,
+	}
+	for i, v := range req.QueryVectors {
+		spec.QueryVectors[i] = v.GetValues()
+	}
+	if req.HasMinSimilarity {
+		spec.MinSimilarity = &req.MinSimilarity
+	}
+
+	results, err := s.engine.Search(sessionID, spec)
+	if err != nil {
+		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+	}
+
+	resp := &pb.SearchResponse{Results: make([]*pb.SearchHits, len(results))}
+	for i, hits := range results {
+		list := &pb.SearchHits{Hits: make([]*pb.SearchHit, len(hits))}
+		for j, hit := range hits {
+			list.Hits[j] = &pb.SearchHit{
+				Id:         hit.ID,
+				ExternalId: hit.ExternalID,
+				Similarity: hit.Similarity,
+			}
+			if hit.TextUnit != nil {
+				list.Hits[j].Textunit = codec.TextUnitToProto(hit.TextUnit)
+			}
+			if hit.Entity != nil {
+				list.Hits[j].Entity = codec.EntityToProto(hit.Entity)
+			}
+			if hit.Community != nil {
+				list.Hits[j].Community = codec.CommunityToProto(hit.Community)
+			}
+		}
+		resp.Results[i] = list
+	}
+
 	data, _ := proto.Marshal(resp)
-	return pb.CommandType_CMD_QUERY_RESPONSE, data
+	return pb.CommandType_CMD_SEARCH_RESPONSE, data
+}
+
+func (s *Server) handlePath(env *pb.Envelope) (pb.CommandType, []byte) {
+	sessionID, err := s.getSessionID(env)
+	if err != nil {
+		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+	}
+
+	var req pb.PathRequest
+	if err := proto.Unmarshal(env.Payload, &req); err != nil {
+		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+	}
+
+	paths, err := s.engine.FindPaths(sessionID, types.PathSpec{
+		SourceID:    req.SourceId,
+		SourceTitle: req.SourceTitle,
+		TargetID:    req.TargetId,
+		TargetTitle: req.TargetTitle,
+		K:           int(req.K),
+		MaxDepth:    int(req.MaxDepth),
+		Directed:    req.Directed,
+		Weighted:    req.Weighted,
+		RelTypes:    req.RelTypes,
+	})
+	if err != nil {
+		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+	}
+
+	resp := &pb.PathResponse{Paths: make([]*pb.EntityPath, len(paths))}
+	for i, p := range paths {
+		path := &pb.EntityPath{
+			Entities:      make([]*pb.Entity, len(p.Entities)),
+			Relationships: make([]*pb.Relationship, len(p.Relationships)),
+			Cost:          p.Cost,
+		}
+		for j, ent := range p.Entities {
+			path.Entities[j] = codec.EntityToProto(ent)
+		}
+		for j, rel := range p.Relationships {
+			path.Relationships[j] = codec.RelationshipToProto(rel)
+		}
+		resp.Paths[i] = path
+	}
+
+	data, _ := proto.Marshal(resp)
+	return pb.CommandType_CMD_PATH_RESPONSE, data
+}
+
+func (s *Server) handleSubgraph(env *pb.Envelope) (pb.CommandType, []byte) {
+	sessionID, err := s.getSessionID(env)
+	if err != nil {
+		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+	}
+
+	var req pb.SubgraphRequest
+	if err := proto.Unmarshal(env.Payload, &req); err != nil {
+		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+	}
+
+	sub, err := s.engine.Subgraph(sessionID, types.SubgraphSpec{
+		EntityIDs:   req.EntityIds,
+		Hops:        int(req.Hops),
+		Direction:   types.Direction(req.Direction),
+		EntityTypes: req.EntityTypes,
+		RelTypes:    req.RelTypes,
+		MaxNodes:    int(req.MaxNodes),
+		MaxEdges:    int(req.MaxEdges),
+	})
+	if err != nil {
+		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+	}
+
+	resp := &pb.SubgraphResponse{
+		Nodes:         make([]*pb.SubgraphNode, len(sub.Nodes)),
+		Relationships: make([]*pb.Relationship, len(sub.Relationships)),
+		Truncated:     sub.Truncated,
+	}
+	for i, node := range sub.Nodes {
+		resp.Nodes[i] = &pb.SubgraphNode{
+			Entity:    codec.EntityToProto(node.Entity),
+			Hop:       int32(node.Hop),
+			InDegree:  int32(node.InDegree),
+			OutDegree: int32(node.OutDegree),
+			Degree:    int32(node.Degree),
+		}
+	}
+	for i, rel := range sub.Relationships {
+		resp.Relationships[i] = codec.RelationshipToProto(rel)
+	}
+
+	data, _ := proto.Marshal(resp)
+	return pb.CommandType_CMD_SUBGRAPH_RESPONSE, data
+}
+
+func (s *Server) handleGraphQuery(env *pb.Envelope) (pb.CommandType, []byte) {
+	sessionID, err := s.getSessionID(env)
+	if err != nil {
+		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+	}
+
+	var req pb.GraphQueryRequest
+	if err := proto.Unmarshal(env.Payload, &req); err != nil {
+		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+	}
+
+	result, err := s.engine.GraphQuery(sessionID, req.Query)
+	if err != nil {
+		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+	}
+
+	resp := &pb.GraphQueryResponse{
+		Columns:   result.Columns,
+		Rows:      make([]*pb.GraphRow, len(result.Rows)),
+		Plan:      result.Plan,
+		Truncated: result.Truncated,
+	}
+	for i, row := range result.Rows {
+		values := make([]*pb.GraphValue, len(row))
+		for j, v := range row {
+			values[j] = codec.GraphValueToProto(v)
+		}
+		resp.Rows[i] = &pb.GraphRow{Values: values}
+	}
+
+	data, _ := proto.Marshal(resp)
+	return pb.CommandType_CMD_QUERY_GRAPH_RESPONSE, data
+}
+
+func (s *Server) handleGlobalSearch(env *pb.Envelope) (pb.CommandType, []byte) {
+	sessionID, err := s.getSessionID(env)
+	if err != nil {
+		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+	}
+
+	var req pb.GlobalSearchRequest
+	if err := proto.Unmarshal(env.Payload, &req); err != nil {
+		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+	}
+
+	result, err := s.engine.GlobalSearch(sessionID, types.GlobalSearchSpec{
+		QueryVector:    req.QueryVector,
+		Level:          int(req.Level),
+		MinCommunities: int(req.MinCommunities),
+		TokenBudget:    int(req.TokenBudget),
+		BatchTokens:    int(req.BatchTokens),
+		UseSummary:     req.UseSummary,
+	})
+	if err != nil {
+		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+	}
+
+	resp := &pb.GlobalSearchResponse{
+		Level:          int32(result.Level),
+		RequestedLevel: int32(result.RequestedLevel),
+		Candidates:     int32(result.Candidates),
+		Batches:        make([]*pb.ReportBatch, len(result.Batches)),
+		TokensUsed:     int32(result.TokensUsed),
+		Skipped:        int32(result.Skipped),
+	}
+	for i, batch := range result.Batches {
+		reports := make([]*pb.CommunityReport, len(batch.Reports))
+		for j, r := range batch.Reports {
+			reports[j] = &pb.CommunityReport{
+				Community: codec.CommunityToProto(r.Community),
+				Score:     r.Score,
+				Rank:      int32(r.Rank),
+				Tokens:    int32(r.Tokens),
+			}
+		}
+		resp.Batches[i] = &pb.ReportBatch{Reports: reports, Tokens: int32(batch.Tokens)}
+	}
+
+	data, _ := proto.Marshal(resp)
+	return pb.CommandType_CMD_GLOBAL_SEARCH_RESPONSE, data
+}
+
+func (s *Server) handleRecallCheck(env *pb.Envelope) (pb.CommandType, []byte) {
+	sessionID, err := s.getSessionID(env)
+	if err != nil {
+		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+	}
+
+	var req pb.RecallCheckRequest
+	if err := proto.Unmarshal(env.Payload, &req); err != nil {
+		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+	}
+
+	spec := types.RecallSpec{
+		Index:   types.SearchType(req.Index),
+		K:       int(req.K),
+		Ef:      int(req.Ef),
+		Samples: int(req.Samples),
+	}
+	for _, v := range req.QueryVectors {
+		spec.QueryVectors = append(spec.QueryVectors, v.GetValues())
+	}
+
+	reports, err := s.engine.CheckRecall(sessionID, spec)
+	if err != nil {
+		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+	}
+
+	resp := &pb.RecallCheckResponse{Indices: make([]*pb.IndexRecall, len(reports))}
+	for i, r := range reports {
+		resp.Indices[i] = &pb.IndexRecall{
+			Index:              string(r.Index),
+			Vectors:            uint64(r.Vectors),
+			Queries:            int32(r.Queries),
+			K:                  int32(r.K),
+			Ef:                 int32(r.Ef),
+			Recall:             r.Recall,
+			MinRecall:          r.MinRecall,
+			LatencyMicros:      distributionPB(r.LatencyUs),
+			ExactLatencyMicros: distributionPB(r.ExactLatencyUs),
+			Visited:            distributionPB(r.Visited),
+		}
+	}
+
+	data, _ := proto.Marshal(resp)
+	return pb.CommandType_CMD_RECALL_CHECK_RESPONSE, data
+}
+
+func distributionPB(d types.Distribution) *pb.Distribution {
+	return &pb.Distribution{Avg: d.Avg, P50: d.P50, P95: d.P95, P99: d.P99, Max: d.Max}
 }
 
 func (s *Server) handleExplain(env *pb.Envelope) (pb.CommandType, []byte) {
@@ -1488,17 +2245,32 @@ func (s *Server) handleExplain(env *pb.Envelope) (pb.CommandType, []byte) {
 			Id:         seed.ID,
 			ExternalId: seed.ExternalID,
 			Similarity: seed.Similarity,
+			Lexical:    seed.Lexical,
 		})
 	}
 
 	for _, step := range explain.Traversal {
 		resp.Traversal = append(resp.Traversal, &pb.TraversalStep{
-			FromEntityId:   step.FromEntityID,
-			ToEntityId:     step.ToEntityID,
-			RelationshipId: step.RelationshipID,
-			RelType:        step.RelType,
-			Weight:         step.Weight,
-			Hop:            int32(step.Hop),
+			FromEntityId:    step.FromEntityID,
+			ToEntityId:      step.ToEntityID,
+			RelationshipId:  step.RelationshipID,
+			RelType:         step.RelType,
+			Weight:          step.Weight,
+			Hop:             int32(step.Hop),
+			CumulativeScore: step.Cumulative,
+		})
+	}
+
+	for _, d := range explain.Demotions {
+		resp.Demotions = append(resp.Demotions, &pb.Demotion{
+			Type:       string(d.Type),
+			Id:         d.ID,
+			ExternalId: d.ExternalID,
+			Reason:     string(d.Reason),
+			Rank:       int32(d.Rank),
+			NewRank:    int32(d.NewRank),
+			SimilarTo:  d.SimilarTo,
+			Similarity: d.Similarity,
 		})
 	}
 
@@ -1532,7 +2304,9 @@ func (s *Server) handleMSetEntities(env *pb.Envelope) (pb.CommandType, []byte) {
 		}
 	}
 
-	ids, err := s.engine.MSetEntities(sessionID, inputs)
+	ids, err := s.bulkMSet(sessionID, req.Bulk, func() ([]uint64, error) {
+		return s.engine.MSetEntities(sessionID, inputs)
+	})
 	if err != nil {
 		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
 	}
@@ -1542,6 +2316,35 @@ func (s *Server) handleMSetEntities(env *pb.Envelope) (pb.CommandType, []byte) {
 	return pb.CommandType_CMD_ENTITIES_RESPONSE, data
 }
 
+// bulkMSet runs an MSET, deferring its vectors and building them
+// concurrently afterwards when bulk is set. Inside a session bulk load the
+// vectors stay pending for CMD_BULK_COMMIT.
+func (s *Server) bulkMSet(sessionID string, bulk bool, mset func() ([]uint64, error)) ([]uint64, error) {
+	if !bulk {
+		return mset()
+	}
+	wasBulk, err := s.engine.BeginBulkLoad(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := mset()
+	if wasBulk {
+		return ids, err
+	}
+
+	task, commitErr := s.engine.CommitBulkLoad(sessionID, 0)
+	if commitErr == nil {
+		task, commitErr = s.engine.WaitRebuild(task.ID)
+	}
+	if commitErr == nil && task.Error != nil {
+		commitErr = task.Error
+	}
+	if err == nil {
+		err = commitErr
+	}
+	return ids, err
+}
+
 func (s *Server) handleMGetEntities(env *pb.Envelope) (pb.CommandType, []byte) {
 	sessionID, err := s.getSessionID(env)
 	if err != nil {
@@ -1671,7 +2474,9 @@ func (s *Server) handleMSetTextUnits(env *pb.Envelope) (pb.CommandType, []byte)
 		}
 	}
 
-	ids, err := s.engine.MSetTextUnits(sessionID, inputs)
+	ids, err := s.bulkMSet(sessionID, req.Bulk, func() ([]uint64, error) {
+		return s.engine.MSetTextUnits(sessionID, inputs)
+	})
 	if err != nil {
 		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
 	}
@@ -1804,6 +2609,15 @@ func (s *Server) handlePipeline(env *pb.Envelope, state *connState) (pb.CommandT
 
 	responses := make([]*pb.Envelope, 0, len(req.Commands))
 	for _, cmd := range req.Commands {
+		if cmd.CmdType == pb.CommandType_CMD_PIPELINE {
+			responses = append(responses, &pb.Envelope{
+				Version:   ProtocolVersion,
+				RequestId: cmd.RequestId,
+				CmdType:   pb.CommandType_CMD_ERROR,
+				Payload:   s.errorPayload("nested pipelines are not supported"),
+			})
+			continue
+		}
 		resp := s.processEnvelope(cmd, state)
 		responses = append(responses, resp)
 	}
@@ -1840,8 +2654,8 @@ func (s *Server) handleBGSave(payload []byte) (pb.CommandType, []byte) {
 	}
 
 	s.backupInProgress.Store(true)
-	s.backupType = "save"
-	s.backupStartTime = time.Now().Unix()
+	s.backupType.Store("save")
+	s.backupStartTime.Store(time.Now().Unix())
 
 	go func() {
 		defer s.backupInProgress.Store(false)
@@ -1851,8 +2665,7 @@ func (s *Server) handleBGSave(payload []byte) (pb.CommandType, []byte) {
 			return
 		}
 
-		s.lastSaveTime = time.Now().Unix()
-		s.lastSavePath = savePath
+		s.recordSave(savePath)
 		logging.Info("Background save completed to %s", savePath)
 	}()
 
@@ -1881,16 +2694,42 @@ func (s *Server) handleSave(payload []byte) (pb.CommandType, []byte) {
 		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
 	}
 
-	s.lastSaveTime = time.Now().Unix()
-	s.lastSavePath = savePath
+	s.recordSave(savePath)
 
 	return pb.CommandType_CMD_OK, s.okPayload(0)
 }
 
+// recordSave notes a completed save
+func (s *Server) recordSave(path string) {
+	s.lastSavePath.Store(path)
+	s.lastSaveTime.Store(time.Now().Unix())
+}
+
+// backupStatus returns the backup state. The fields are read atomically
+// one by one, since a background save updates them while it runs.
+func (s *Server) backupStatus() BackupStatus {
+	backupType, _ := s.backupType.Load().(string)
+	lastTime, lastPath := s.lastSave()
+	return BackupStatus{
+		InProgress:   s.backupInProgress.Load(),
+		Type:         backupType,
+		StartTime:    s.backupStartTime.Load(),
+		LastSaveTime: lastTime,
+		LastSavePath: lastPath,
+	}
+}
+
+// lastSave returns the time and path of the last completed save
+func (s *Server) lastSave() (int64, string) {
+	path, _ := s.lastSavePath.Load().(string)
+	return s.lastSaveTime.Load(), path
+}
+
 func (s *Server) handleLastSave() (pb.CommandType, []byte) {
+	lastTime, lastPath := s.lastSave()
 	resp := &pb.LastSaveResponse{
-		Timestamp: s.lastSaveTime,
-		Path:      s.lastSavePath,
+		Timestamp: lastTime,
+		Path:      lastPath,
 	}
 	data, _ := proto.Marshal(resp)
 	return pb.CommandType_CMD_BACKUP_RESPONSE, data
@@ -1911,11 +2750,13 @@ func (s *Server) handleBGRestore(payload []byte) (pb.CommandType, []byte) {
 	}
 
 	s.backupInProgress.Store(true)
-	s.backupType = "restore"
-	s.backupStartTime = time.Now().Unix()
+	s.backupType.Store("restore")
+	s.backupStartTime.Store(time.Now().Unix())
+	endRecovery := s.BeginRecovery()
 
 	go func() {
 		defer s.backupInProgress.Store(false)
+		defer endRecovery()
 
 		if err := s.restoreFn(req.Path); err != nil {
 			logging.Error("Background restore failed: %v", err)
@@ -1929,12 +2770,13 @@ func (s *Server) handleBGRestore(payload []byte) (pb.CommandType, []byte) {
 }
 
 func (s *Server) handleBackupStatus() (pb.CommandType, []byte) {
+	status := s.backupStatus()
 	resp := &pb.BackupStatusResponse{
-		InProgress:   s.backupInProgress.Load(),
-		Type:         s.backupType,
-		StartTime:    s.backupStartTime,
-		LastSaveTime: s.lastSaveTime,
-		LastSavePath: s.lastSavePath,
+		InProgress:   status.InProgress,
+		Type:         status.Type,
+		StartTime:    status.StartTime,
+		LastSaveTime: status.LastSaveTime,
+		LastSavePath: status.LastSavePath,
 	}
 	data, _ := proto.Marshal(resp)
 	return pb.CommandType_CMD_BACKUP_RESPONSE, data
@@ -1965,13 +2807,132 @@ func (s *Server) handleRebuildIndex(env *pb.Envelope) (pb.CommandType, []byte) {
 		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
 	}
 
-	if err := s.engine.RebuildVectorIndices(sessionID); err != nil {
+	var req pb.RebuildIndexRequest
+	if err := proto.Unmarshal(env.Payload, &req); err != nil {
 		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
 	}
 
+	// Without options, rebuild synchronously with the current configuration
+	if !req.Async && req.TextunitIndex == nil && req.EntityIndex == nil && req.CommunityIndex == nil {
+		if err := s.engine.RebuildVectorIndices(sessionID); err != nil {
+			return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+		}
+		return pb.CommandType_CMD_OK, s.okPayload(0)
+	}
+
+	sess, err := s.engine.GetSession(sessionID)
+	if err != nil {
+		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+	}
+	opts := sess.Options()
+	if err := applyIndexOptionsPB(&opts, req.TextunitIndex, req.EntityIndex, req.CommunityIndex); err != nil {
+		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+	}
+
+	task, err := s.engine.StartIndexRebuild(sessionID, opts)
+	if err != nil {
+		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+	}
+	if !req.Async {
+		if task, err = s.engine.WaitRebuild(task.ID); err != nil {
+			return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+		}
+		if task.Error != nil {
+			return pb.CommandType_CMD_ERROR, s.errorPayload(task.Error.Error())
+		}
+	}
+
+	data, _ := proto.Marshal(&pb.RebuildStatusResponse{Tasks: []*pb.RebuildTask{rebuildTaskPB(task)}})
+	return pb.CommandType_CMD_REBUILD_STATUS_RESPONSE, data
+}
+
+func (s *Server) handleRebuildStatus(env *pb.Envelope) (pb.CommandType, []byte) {
+	sessionID, err := s.getSessionID(env)
+	if err != nil {
+		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+	}
+
+	var req pb.RebuildStatusRequest
+	if err := proto.Unmarshal(env.Payload, &req); err != nil {
+		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+	}
+
+	resp := &pb.RebuildStatusResponse{}
+	if req.TaskId != "" {
+		task, err := s.engine.RebuildStatus(req.TaskId)
+		if err != nil || task.SessionID != sessionID {
+			return pb.CommandType_CMD_ERROR, s.errorPayload(engine.ErrTaskNotFound.Error())
+		}
+		resp.Tasks = append(resp.Tasks, rebuildTaskPB(task))
+	} else {
+		for _, task := range s.engine.RebuildTasks(sessionID) {
+			resp.Tasks = append(resp.Tasks, rebuildTaskPB(task))
+		}
+	}
+
+	data, _ := proto.Marshal(resp)
+	return pb.CommandType_CMD_REBUILD_STATUS_RESPONSE, data
+}
+
+func (s *Server) handleBulkBegin(env *pb.Envelope) (pb.CommandType, []byte) {
+	sessionID, err := s.getSessionID(env)
+	if err != nil {
+		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+	}
+
+	if _, err := s.engine.BeginBulkLoad(sessionID); err != nil {
+		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+	}
 	return pb.CommandType_CMD_OK, s.okPayload(0)
 }
 
+func (s *Server) handleBulkCommit(env *pb.Envelope) (pb.CommandType, []byte) {
+	sessionID, err := s.getSessionID(env)
+	if err != nil {
+		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+	}
+
+	var req pb.BulkCommitRequest
+	if err := proto.Unmarshal(env.Payload, &req); err != nil {
+		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+	}
+
+	task, err := s.engine.CommitBulkLoad(sessionID, int(req.Workers))
+	if err != nil {
+		return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+	}
+	if !req.Async {
+		if task, err = s.engine.WaitRebuild(task.ID); err != nil {
+			return pb.CommandType_CMD_ERROR, s.errorPayload(err.Error())
+		}
+		if task.Error != nil {
+			return pb.CommandType_CMD_ERROR, s.errorPayload(task.Error.Error())
+		}
+	}
+
+	data, _ := proto.Marshal(&pb.RebuildStatusResponse{Tasks: []*pb.RebuildTask{rebuildTaskPB(task)}})
+	return pb.CommandType_CMD_REBUILD_STATUS_RESPONSE, data
+}
+
+// rebuildTaskPB converts a rebuild task for the wire
+func rebuildTaskPB(task *engine.RebuildTask) *pb.RebuildTask {
+	out := &pb.RebuildTask{
+		TaskId:    task.ID,
+		SessionId: task.SessionID,
+		Status:    task.Status.String(),
+		Index:     task.Index,
+		Progress:  task.Progress,
+		StartTime: task.StartTime.UnixNano(),
+	}
+	if !task.EndTime.IsZero() {
+		out.EndTime = task.EndTime.UnixNano()
+	}
+	if task.Error != nil {
+		out.Error = task.Error.Error()
+	}
+	return out
+}
+
 // =============================================================================
 // WAL Operation Handlers
 // =============================================================================
@@ -4,6 +4,8 @@
 package simd
 
 import (
+	"math"
+
 	"golang.org/x/sys/cpu"
 )
 
@@ -128,26 +130,10 @@ func l2NormAVX2(a []float32) float32 {
 	return float32Sqrt(sum)
 }
 
-// float32Sqrt is a helper for fast square root
+// float32Sqrt computes a square root; the compiler lowers math.Sqrt to SQRTSD
 func float32Sqrt(x float32) float32 {
-	// Use x86 SQRTSS instruction via assembly
-	// For now, use Go's built-in sqrt
-	return float32(fastSqrt(float64(x)))
-}
-
-// fastSqrt provides a fast square root approximation
-func fastSqrt(x float64) float64 {
-	// This could be optimized with assembly VSQRTSD
-	// For now, use standard library
 	if x <= 0 {
 		return 0
 	}
-	
-	// Fast inverse square root approximation (Quake III algorithm adapted)
-	// For production, we'd use hardware SQRT instruction
-	half := x * 0.5
-	i := int64(0x5fe6eb50c7b537a9 - (int64(x) >> 1))
-	y := float64(i)
-	y = y * (1.5 - (half * y * y))
-	return x * y
+	return float32(math.Sqrt(float64(x)))
 }
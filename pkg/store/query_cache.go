@@ -0,0 +1,120 @@
+package store
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// =============================================================================
+// Query Cache - Per-Session Results, Invalidated by the Mutation Epoch
+// =============================================================================
+
+// QueryCache is an LRU cache of query results with an optional TTL. Each
+// entry records the session epoch it was computed at and is only served
+// while the session is still at that epoch, so any write invalidates it.
+type QueryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	ttl        time.Duration // 0 = no expiry
+	items      map[string]*list.Element
+	order      *list.List // front = most recent
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+type queryCacheEntry struct {
+	key     string
+	epoch   uint64
+	expires time.Time // zero = never
+	value   any
+}
+
+// NewQueryCache creates a cache holding at most maxEntries results, each
+// for at most ttl (0 = until evicted or invalidated)
+func NewQueryCache(maxEntries int, ttl time.Duration) *QueryCache {
+	return &QueryCache{
+		maxEntries: maxEntries,
+		ttl:        ttl,
+		items:      make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+// Get returns the value cached for key at epoch, dropping it when it is
+// expired or was computed at another epoch
+func (c *QueryCache) Get(key string, epoch uint64) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		c.misses.Add(1)
+		return nil, false
+	}
+	entry := elem.Value.(*queryCacheEntry)
+	if entry.epoch != epoch || (!entry.expires.IsZero() && time.Now().After(entry.expires)) {
+		c.removeLocked(elem)
+		c.misses.Add(1)
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	c.hits.Add(1)
+	return entry.value, true
+}
+
+// Put caches value for key at epoch, evicting the least recently used
+// entries beyond the size bound
+func (c *QueryCache) Put(key string, epoch uint64, value any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &queryCacheEntry{key: key, epoch: epoch, value: value}
+	if c.ttl > 0 {
+		entry.expires = time.Now().Add(c.ttl)
+	}
+	if elem, ok := c.items[key]; ok {
+		elem.Value = entry
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.items[key] = c.order.PushFront(entry)
+	for c.order.Len() > c.maxEntries {
+		c.removeLocked(c.order.Back())
+	}
+}
+
+func (c *QueryCache) removeLocked(elem *list.Element) {
+	delete(c.items, elem.Value.(*queryCacheEntry).key)
+	c.order.Remove(elem)
+}
+
+// Len returns the number of cached entries, including stale ones not yet
+// dropped
+func (c *QueryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// MaxEntries returns the size bound of the cache
+func (c *QueryCache) MaxEntries() int {
+	return c.maxEntries
+}
+
+// TTL returns how long entries are served, 0 = no expiry
+func (c *QueryCache) TTL() time.Duration {
+	return c.ttl
+}
+
+// Hits returns how many lookups were served from the cache
+func (c *QueryCache) Hits() uint64 {
+	return c.hits.Load()
+}
+
+// Misses returns how many lookups found no valid entry
+func (c *QueryCache) Misses() uint64 {
+	return c.misses.Load()
+}
@@ -2,11 +2,15 @@
 package store
 
 import (
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/gibram-io/gibram/pkg/lexical"
 	"github.com/gibram-io/gibram/pkg/types"
 	"github.com/gibram-io/gibram/pkg/vector"
 )
@@ -37,6 +41,7 @@ type SessionStore struct {
 	entities   map[uint64]*types.Entity
 	entByExtID map[string]uint64
 	entByTitle map[string]uint64
+	entByType  map[string]map[uint64]struct{} // lowercased type -> entity IDs
 
 	relationships     map[uint64]*types.Relationship
 	relByExtID        map[string]uint64
@@ -53,14 +58,117 @@ type SessionStore struct {
 	entityIndex    vector.Index
 	communityIndex vector.Index
 	vectorDim      int
+	options        SessionOptions
+
+	// Bulk load: vectors deferred by index name until BuildPendingVectors
+	bulkLoad        bool
+	pendingVectors  map[string]map[uint64][]float32
+	buildingVectors map[string]map[uint64][]float32 // taken by a running build
+
+	// Full-text indices (per-session, kept in sync with object text)
+	textUnitLexical  *lexical.Index // TextUnit.Content
+	entityLexical    *lexical.Index // Entity.Title + Description
+	communityLexical *lexical.Index // Community.Title + Summary
+
+	// Mutation epoch, replaced after every write, and the optional cache
+	// of query results computed at an epoch
+	epoch      atomic.Uint64
+	queryCache atomic.Pointer[QueryCache]
 }
 
-// NewSessionStore creates a new session store
+// epochSource hands out mutation epochs. Epochs are unique across
+// sessions, so a session recreated under the same ID never repeats one.
+var epochSource atomic.Uint64
+
+// Vector index names
+const (
+	IndexTextUnit  = "textunit"
+	IndexEntity    = "entity"
+	IndexCommunity = "community"
+)
+
+// IndexNames lists the vector indices of a session
+var IndexNames = []string{IndexTextUnit, IndexEntity, IndexCommunity}
+
+// SessionOptions configures the vector indices of a session
+type SessionOptions struct {
+	VectorDim      int               `json:"vector_dim,omitempty"` // 0 = engine default
+	TextUnitIndex  vector.HNSWConfig `json:"textunit_index"`
+	EntityIndex    vector.HNSWConfig `json:"entity_index"`
+	CommunityIndex vector.HNSWConfig `json:"community_index"`
+}
+
+// Validate checks the dimension and the configuration of every index
+func (o SessionOptions) Validate() error {
+	if o.VectorDim < 0 {
+		return fmt.Errorf("invalid vector_dim: %d", o.VectorDim)
+	}
+	for _, idx := range []struct {
+		name   string
+		config vector.HNSWConfig
+	}{
+		{IndexTextUnit, o.TextUnitIndex},
+		{IndexEntity, o.EntityIndex},
+		{IndexCommunity, o.CommunityIndex},
+	} {
+		if err := idx.config.Validate(); err != nil {
+			return fmt.Errorf("%s index: %w", idx.name, err)
+		}
+		if o.VectorDim > 0 {
+			if err := idx.config.ValidateDimension(o.VectorDim); err != nil {
+				return fmt.Errorf("%s index: %w", idx.name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// indexConfigInfo describes an index configuration for SessionInfo
+func indexConfigInfo(config vector.HNSWConfig) types.IndexConfig {
+	return types.IndexConfig{
+		Metric:         config.Metric.String(),
+		M:              config.M,
+		EfConstruction: config.EfConstruction,
+		EfSearch:       config.EfSearch,
+
+		Quantization:    config.Quantization.String(),
+		PQSubvectors:    config.PQSubvectors,
+		KeepFullVectors: config.KeepFullVectors,
+
+		TombstoneRatio: config.TombstoneRatio,
+	}
+}
+
+// DefaultSessionOptions returns the default index configuration
+func DefaultSessionOptions() SessionOptions {
+	return SessionOptions{
+		TextUnitIndex:  vector.DefaultHNSWConfig(),
+		EntityIndex:    vector.DefaultHNSWConfig(),
+		CommunityIndex: vector.DefaultHNSWConfig(),
+	}
+}
+
+// NewSessionStore creates a new session store with default index options
 func NewSessionStore(sessionID string, vectorDim int) *SessionStore {
-	return &SessionStore{
+	return NewSessionStoreWithOptions(sessionID, vectorDim, DefaultSessionOptions())
+}
+
+// NewSessionStoreWithOptions creates a new session store with the given
+// index options. A non-zero opts.VectorDim overrides vectorDim.
+func NewSessionStoreWithOptions(sessionID string, vectorDim int, opts SessionOptions) *SessionStore {
+	if opts.VectorDim > 0 {
+		vectorDim = opts.VectorDim
+	}
+	opts.VectorDim = vectorDim
+	s := &SessionStore{
 		session:   types.NewSession(sessionID),
 		idGen:     types.NewIDGenerator(),
 		vectorDim: vectorDim,
+		options:   opts,
+
+		textUnitLexical:  lexical.NewIndex(),
+		entityLexical:    lexical.NewIndex(),
+		communityLexical: lexical.NewIndex(),
 
 		// Documents
 		documents:     make(map[uint64]*types.Document),
@@ -76,6 +184,7 @@ func NewSessionStore(sessionID string, vectorDim int) *SessionStore {
 		entities:   make(map[uint64]*types.Entity),
 		entByExtID: make(map[string]uint64),
 		entByTitle: make(map[string]uint64),
+		entByType:  make(map[string]map[uint64]struct{}),
 
 		// Relationships
 		relationships:     make(map[uint64]*types.Relationship),
@@ -89,6 +198,8 @@ func NewSessionStore(sessionID string, vectorDim int) *SessionStore {
 		commByExtID: make(map[string]uint64),
 		commByLevel: make(map[int][]uint64),
 	}
+	s.bumpEpoch()
+	return s
 }
 
 // =============================================================================
@@ -125,6 +236,35 @@ func (s *SessionStore) SetIdleTTL(idleTTL int64) {
 	s.session.SetIdleTTL(idleTTL)
 }
 
+// Epoch returns the mutation epoch of the session. It changes after every
+// write, so results computed at one epoch are current while it lasts.
+func (s *SessionStore) Epoch() uint64 {
+	return s.epoch.Load()
+}
+
+// bumpEpoch moves the session to a new epoch once a write is complete;
+// writers call it before releasing the write lock
+func (s *SessionStore) bumpEpoch() {
+	s.epoch.Store(epochSource.Add(1))
+}
+
+// QueryCache returns the query result cache of the session, nil when
+// caching is off
+func (s *SessionStore) QueryCache() *QueryCache {
+	return s.queryCache.Load()
+}
+
+// SetQueryCache replaces the query result cache with an empty one holding
+// at most maxEntries results for at most ttl (0 = no expiry). maxEntries
+// 0 turns caching off.
+func (s *SessionStore) SetQueryCache(maxEntries int, ttl time.Duration) {
+	if maxEntries <= 0 {
+		s.queryCache.Store(nil)
+		return
+	}
+	s.queryCache.Store(NewQueryCache(maxEntries, ttl))
+}
+
 // GetInfo returns session info with counts
 func (s *SessionStore) GetInfo() types.SessionInfo {
 	s.mu.RLock()
@@ -136,9 +276,70 @@ func (s *SessionStore) GetInfo() types.SessionInfo {
 	info.EntityCount = len(s.entities)
 	info.RelationshipCount = len(s.relationships)
 	info.CommunityCount = len(s.communities)
+	if s.textUnitIndex != nil {
+		info.TextUnitIndexSize = s.textUnitIndex.Count()
+	}
+	if s.entityIndex != nil {
+		info.EntityIndexSize = s.entityIndex.Count()
+	}
+	if s.communityIndex != nil {
+		info.CommunityIndexSize = s.communityIndex.Count()
+	}
+	info.BulkLoading = s.bulkLoad
+	for _, name := range IndexNames {
+		info.PendingVectors += len(s.pendingVectors[name]) + len(s.buildingVectors[name])
+	}
+	info.VectorDim = s.vectorDim
+	info.TextUnitIndex = indexConfigInfo(s.options.TextUnitIndex)
+	info.EntityIndex = indexConfigInfo(s.options.EntityIndex)
+	info.CommunityIndex = indexConfigInfo(s.options.CommunityIndex)
+	if cache := s.QueryCache(); cache != nil {
+		info.QueryCacheSize = cache.MaxEntries()
+		info.QueryCacheTTL = int64(cache.TTL() / time.Second)
+		info.QueryCacheEntries = cache.Len()
+		info.QueryCacheHits = cache.Hits()
+		info.QueryCacheMisses = cache.Misses()
+	}
 	return info
 }
 
+// VectorMemory reports the memory held by the session's vector indices
+func (s *SessionStore) VectorMemory() types.VectorMemory {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	mem := types.VectorMemory{Recall: 1}
+	for _, idx := range []vector.Index{s.textUnitIndex, s.entityIndex, s.communityIndex} {
+		if idx == nil {
+			continue
+		}
+		stats := idx.MemoryStats()
+		mem.Add(types.VectorMemory{
+			Vectors:            stats.Vectors,
+			QuantizedVectors:   stats.QuantizedVectors,
+			Tombstones:         stats.Tombstones,
+			Bytes:              stats.Bytes,
+			FullPrecisionBytes: stats.FullPrecisionBytes,
+			Recall:             stats.Recall,
+		})
+	}
+	return mem
+}
+
+// VectorDim returns the session's vector dimension
+func (s *SessionStore) VectorDim() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.vectorDim
+}
+
+// Options returns the session's index options
+func (s *SessionStore) Options() SessionOptions {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.options
+}
+
 // GetIDGenerator returns the ID generator
 func (s *SessionStore) GetIDGenerator() *types.IDGenerator {
 	return s.idGen
@@ -150,21 +351,21 @@ func (s *SessionStore) GetIDGenerator() *types.IDGenerator {
 
 func (s *SessionStore) getTextUnitIndex() vector.Index {
 	if s.textUnitIndex == nil {
-		s.textUnitIndex = vector.NewHNSWIndex(s.vectorDim, vector.DefaultHNSWConfig())
+		s.textUnitIndex = vector.NewHNSWIndex(s.vectorDim, s.options.TextUnitIndex)
 	}
 	return s.textUnitIndex
 }
 
 func (s *SessionStore) getEntityIndex() vector.Index {
 	if s.entityIndex == nil {
-		s.entityIndex = vector.NewHNSWIndex(s.vectorDim, vector.DefaultHNSWConfig())
+		s.entityIndex = vector.NewHNSWIndex(s.vectorDim, s.options.EntityIndex)
 	}
 	return s.entityIndex
 }
 
 func (s *SessionStore) getCommunityIndex() vector.Index {
 	if s.communityIndex == nil {
-		s.communityIndex = vector.NewHNSWIndex(s.vectorDim, vector.DefaultHNSWConfig())
+		s.communityIndex = vector.NewHNSWIndex(s.vectorDim, s.options.CommunityIndex)
 	}
 	return s.communityIndex
 }
@@ -190,6 +391,286 @@ func (s *SessionStore) GetCommunityIndex() vector.Index {
 	return s.getCommunityIndex()
 }
 
+// indexSlot returns the index field and configuration for an index name
+func (s *SessionStore) indexSlot(name string) (*vector.Index, *vector.HNSWConfig, error) {
+	switch name {
+	case IndexTextUnit:
+		return &s.textUnitIndex, &s.options.TextUnitIndex, nil
+	case IndexEntity:
+		return &s.entityIndex, &s.options.EntityIndex, nil
+	case IndexCommunity:
+		return &s.communityIndex, &s.options.CommunityIndex, nil
+	}
+	return nil, nil, fmt.Errorf("unknown vector index: %q", name)
+}
+
+// RebuildIndex rebuilds the named vector index online with config and
+// records config in the session options. Searches and writes continue on
+// the current index until the rebuilt one is swapped in.
+func (s *SessionStore) RebuildIndex(name string, config vector.HNSWConfig, progress vector.ProgressFunc) error {
+	s.mu.Lock()
+	slot, cfg, err := s.indexSlot(name)
+	if err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	if err := config.ValidateDimension(s.vectorDim); err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	idx := *slot
+	if idx == nil {
+		// Not created yet; it will be created with the new configuration
+		*cfg = config
+		s.mu.Unlock()
+		return nil
+	}
+	s.mu.Unlock()
+
+	if err := idx.RebuildWithConfig(config, progress); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.bumpEpoch()
+	if *slot == idx {
+		*cfg = config
+	}
+	return nil
+}
+
+// =============================================================================
+// Bulk Load
+// =============================================================================
+
+// ErrBulkBuildInProgress is returned when pending vectors are already being built
+var ErrBulkBuildInProgress = errors.New("bulk index build already in progress")
+
+// getIndex returns the named vector index, creating it if needed
+func (s *SessionStore) getIndex(name string) vector.Index {
+	switch name {
+	case IndexTextUnit:
+		return s.getTextUnitIndex()
+	case IndexEntity:
+		return s.getEntityIndex()
+	default:
+		return s.getCommunityIndex()
+	}
+}
+
+// addVector inserts embedding into the named index, or defers it while
+// bulk loading; caller holds the write lock
+func (s *SessionStore) addVector(name string, id uint64, embedding []float32) error {
+	if s.bulkLoad {
+		return s.deferVector(name, id, embedding)
+	}
+	return s.getIndex(name).Add(id, embedding)
+}
+
+// deferVector records embedding for the next bulk build, replacing any
+// deferred vector of id; caller holds the write lock
+func (s *SessionStore) deferVector(name string, id uint64, embedding []float32) error {
+	if len(embedding) != s.vectorDim {
+		return fmt.Errorf("vector dimension mismatch: expected %d, got %d", s.vectorDim, len(embedding))
+	}
+	if s.pendingVectors == nil {
+		s.pendingVectors = make(map[string]map[uint64][]float32)
+	}
+	if s.pendingVectors[name] == nil {
+		s.pendingVectors[name] = make(map[uint64][]float32)
+	}
+	vec := make([]float32, len(embedding))
+	copy(vec, embedding)
+	s.pendingVectors[name][id] = vec
+	return nil
+}
+
+// deferred reports whether the vector of id is pending or being built;
+// caller holds the lock
+func (s *SessionStore) deferred(name string, id uint64) bool {
+	if _, ok := s.pendingVectors[name][id]; ok {
+		return true
+	}
+	_, ok := s.buildingVectors[name][id]
+	return ok
+}
+
+// dropPending discards a deferred vector; caller holds the write lock
+func (s *SessionStore) dropPending(name string, id uint64) {
+	delete(s.pendingVectors[name], id)
+}
+
+// hasObject reports whether the object owning a vector still exists;
+// caller holds the lock
+func (s *SessionStore) hasObject(name string, id uint64) bool {
+	var ok bool
+	switch name {
+	case IndexTextUnit:
+		_, ok = s.textUnits[id]
+	case IndexEntity:
+		_, ok = s.entities[id]
+	case IndexCommunity:
+		_, ok = s.communities[id]
+	}
+	return ok
+}
+
+// BeginBulkLoad defers vector index insertion until BuildPendingVectors.
+// Objects are stored and searchable lexically right away; their vectors
+// are not searchable until built. It reports whether the session was
+// already bulk loading.
+func (s *SessionStore) BeginBulkLoad() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wasBulk := s.bulkLoad
+	s.bulkLoad = true
+	return wasBulk
+}
+
+// BulkLoading reports whether vector insertion is deferred
+func (s *SessionStore) BulkLoading() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.bulkLoad
+}
+
+// PendingVectors returns the number of deferred vectors, including those
+// being built
+func (s *SessionStore) PendingVectors() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	n := 0
+	for _, name := range IndexNames {
+		n += len(s.pendingVectors[name]) + len(s.buildingVectors[name])
+	}
+	return n
+}
+
+// BuildPendingVectors ends the bulk load and inserts the deferred vectors
+// into each index with workers goroutines (<= 0 = GOMAXPROCS). Searches and
+// writes continue during the build. progress reports vectors built out of
+// all deferred vectors. On failure the unbuilt vectors stay pending.
+func (s *SessionStore) BuildPendingVectors(workers int, progress func(index string, done, total int)) error {
+	s.mu.Lock()
+	if s.buildingVectors != nil {
+		s.mu.Unlock()
+		return ErrBulkBuildInProgress
+	}
+	batch := s.pendingVectors
+	s.pendingVectors = nil
+	s.bulkLoad = false
+	s.buildingVectors = batch
+	total := 0
+	indices := make(map[string]vector.Index)
+	for _, name := range IndexNames {
+		if len(batch[name]) > 0 {
+			total += len(batch[name])
+			indices[name] = s.getIndex(name)
+		}
+	}
+	s.mu.Unlock()
+
+	built := make([]string, 0, len(indices))
+	base := 0
+	var err error
+	for _, name := range IndexNames {
+		pending := batch[name]
+		if len(pending) == 0 {
+			continue
+		}
+		ids := make([]uint64, 0, len(pending))
+		for id := range pending {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		vectors := make([][]float32, len(ids))
+		for i, id := range ids {
+			vectors[i] = pending[id]
+		}
+
+		var report vector.ProgressFunc
+		if progress != nil {
+			offset, name := base, name
+			report = func(done, n int) {
+				progress(name, offset+done*len(ids)/n, total)
+			}
+		}
+		if err = indices[name].AddBatch(ids, vectors, workers, report); err != nil {
+			err = fmt.Errorf("%s index: %w", name, err)
+			break
+		}
+		built = append(built, name)
+		base += len(ids)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.bumpEpoch()
+	s.buildingVectors = nil
+
+	// Objects deleted during the build had no vector in the index to
+	// remove, and updates made during the build were deferred
+	for _, name := range built {
+		idx := indices[name]
+		for id := range batch[name] {
+			if !s.hasObject(name, id) {
+				idx.Remove(id)
+				continue
+			}
+			if vec, ok := s.pendingVectors[name][id]; ok {
+				idx.Remove(id)
+				if err := idx.Add(id, vec); err != nil {
+					return err
+				}
+				delete(s.pendingVectors[name], id)
+			}
+		}
+		delete(batch, name)
+	}
+	if err != nil {
+		// Keep the unbuilt vectors pending; later updates take precedence
+		if s.pendingVectors == nil {
+			s.pendingVectors = make(map[string]map[uint64][]float32)
+		}
+		for name, pending := range batch {
+			if s.pendingVectors[name] == nil {
+				s.pendingVectors[name] = make(map[uint64][]float32)
+			}
+			for id, vec := range pending {
+				if s.hasObject(name, id) && !s.deferred(name, id) {
+					s.pendingVectors[name][id] = vec
+				}
+			}
+		}
+		s.bulkLoad = true
+	}
+	return err
+}
+
+// GetTextUnitLexicalIndex returns the text unit full-text index
+func (s *SessionStore) GetTextUnitLexicalIndex() *lexical.Index {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.textUnitLexical
+}
+
+// GetEntityLexicalIndex returns the entity full-text index
+func (s *SessionStore) GetEntityLexicalIndex() *lexical.Index {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.entityLexical
+}
+
+// GetCommunityLexicalIndex returns the community full-text index
+func (s *SessionStore) GetCommunityLexicalIndex() *lexical.Index {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.communityLexical
+}
+
 // =============================================================================
 // Document Operations
 // =============================================================================
@@ -198,6 +679,7 @@ func (s *SessionStore) GetCommunityIndex() vector.Index {
 func (s *SessionStore) AddDocument(extID, filename string) (*types.Document, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	defer s.bumpEpoch()
 
 	if _, exists := s.docByExtID[extID]; exists {
 		return nil, fmt.Errorf("document with external_id %s already exists", extID)
@@ -242,6 +724,7 @@ func (s *SessionStore) GetDocumentByExternalID(extID string) (*types.Document, b
 func (s *SessionStore) DeleteDocument(id uint64) bool {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	defer s.bumpEpoch()
 
 	doc, ok := s.documents[id]
 	if !ok {
@@ -283,6 +766,7 @@ func (s *SessionStore) DocumentCount() int {
 func (s *SessionStore) AddTextUnit(extID string, docID uint64, content string, embedding []float32, tokenCount int) (*types.TextUnit, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	defer s.bumpEpoch()
 
 	if _, exists := s.tuByExtID[extID]; exists {
 		return nil, fmt.Errorf("textunit with external_id %s already exists", extID)
@@ -295,12 +779,13 @@ func (s *SessionStore) AddTextUnit(extID string, docID uint64, content string, e
 
 	// Add to vector index
 	if len(embedding) > 0 {
-		if err := s.getTextUnitIndex().Add(tu.ID, embedding); err != nil {
+		if err := s.addVector(IndexTextUnit, tu.ID, embedding); err != nil {
 			delete(s.textUnits, tu.ID)
 			delete(s.tuByExtID, extID)
 			return nil, err
 		}
 	}
+	s.textUnitLexical.Add(tu.ID, content)
 
 	s.session.Touch()
 	return tu, nil
@@ -333,10 +818,28 @@ func (s *SessionStore) GetTextUnitsByDocumentID(docID uint64) []*types.TextUnit
 	return result
 }
 
+// TextUnitIDsInDocuments returns the IDs of the text units of the given
+// documents, collected under one read lock
+func (s *SessionStore) TextUnitIDsInDocuments(docIDs []uint64) map[uint64]bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := make(map[uint64]bool)
+	for _, docID := range docIDs {
+		for _, id := range s.tuByDocID[docID] {
+			if _, ok := s.textUnits[id]; ok {
+				ids[id] = true
+			}
+		}
+	}
+	return ids
+}
+
 // DeleteTextUnit removes a text unit
 func (s *SessionStore) DeleteTextUnit(id uint64) bool {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	defer s.bumpEpoch()
 
 	tu, ok := s.textUnits[id]
 	if !ok {
@@ -359,6 +862,8 @@ func (s *SessionStore) DeleteTextUnit(id uint64) bool {
 	if s.textUnitIndex != nil {
 		s.textUnitIndex.Remove(id)
 	}
+	s.dropPending(IndexTextUnit, id)
+	s.textUnitLexical.Remove(id)
 
 	s.session.Touch()
 	return true
@@ -368,6 +873,7 @@ func (s *SessionStore) DeleteTextUnit(id uint64) bool {
 func (s *SessionStore) LinkTextUnitToEntity(tuID, entityID uint64) bool {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	defer s.bumpEpoch()
 
 	tu, ok := s.textUnits[tuID]
 	if !ok {
@@ -413,6 +919,7 @@ func (s *SessionStore) TextUnitCount() int {
 func (s *SessionStore) AddEntity(extID, title, entType, description string, embedding []float32) (*types.Entity, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	defer s.bumpEpoch()
 
 	normalizedTitle := strings.ToUpper(strings.TrimSpace(title))
 
@@ -431,16 +938,19 @@ func (s *SessionStore) AddEntity(extID, title, entType, description string, embe
 	if extID != "" {
 		s.entByExtID[extID] = ent.ID
 	}
+	s.indexEntityType(ent)
 
 	// Add to vector index
 	if len(embedding) > 0 {
-		if err := s.getEntityIndex().Add(ent.ID, embedding); err != nil {
+		if err := s.addVector(IndexEntity, ent.ID, embedding); err != nil {
 			delete(s.entities, ent.ID)
 			delete(s.entByTitle, normalizedTitle)
 			delete(s.entByExtID, extID)
+			s.unindexEntityType(ent)
 			return nil, err
 		}
 	}
+	s.entityLexical.Add(ent.ID, ent.Title, ent.Description)
 
 	s.session.Touch()
 	return ent, nil
@@ -471,10 +981,97 @@ func (s *SessionStore) GetEntityByTitle(title string) (*types.Entity, bool) {
 	return s.entities[id], true
 }
 
+// GetEntityByExternalID retrieves an entity by external ID
+func (s *SessionStore) GetEntityByExternalID(extID string) (*types.Entity, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	id, ok := s.entByExtID[extID]
+	if !ok {
+		return nil, false
+	}
+	return s.entities[id], true
+}
+
+// GetEntitiesByType returns the entities of a type (case-insensitive) in
+// ID order
+func (s *SessionStore) GetEntitiesByType(entType string) []*types.Entity {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := s.entByType[normalizeEntityType(entType)]
+	result := make([]*types.Entity, 0, len(ids))
+	for id := range ids {
+		result = append(result, s.entities[id])
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+// EntityTypeCount returns the number of entities of a type (case-insensitive)
+func (s *SessionStore) EntityTypeCount(entType string) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.entByType[normalizeEntityType(entType)])
+}
+
+// EntityIDsWhere returns the IDs of the entities of the given types
+// (case-insensitive; none = any type) for which match holds (nil = all),
+// all evaluated under one read lock. match must not call the store.
+func (s *SessionStore) EntityIDsWhere(entTypes []string, match func(*types.Entity) bool) map[uint64]bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := make(map[uint64]bool)
+	consider := func(ent *types.Entity) {
+		if match == nil || match(ent) {
+			ids[ent.ID] = true
+		}
+	}
+	if len(entTypes) == 0 {
+		for _, ent := range s.entities {
+			consider(ent)
+		}
+		return ids
+	}
+	for _, t := range entTypes {
+		for id := range s.entByType[normalizeEntityType(t)] {
+			consider(s.entities[id])
+		}
+	}
+	return ids
+}
+
+// indexEntityType adds ent to the type index. Caller must hold s.mu.
+func (s *SessionStore) indexEntityType(ent *types.Entity) {
+	key := normalizeEntityType(ent.Type)
+	ids, ok := s.entByType[key]
+	if !ok {
+		ids = make(map[uint64]struct{})
+		s.entByType[key] = ids
+	}
+	ids[ent.ID] = struct{}{}
+}
+
+// unindexEntityType removes ent from the type index. Caller must hold s.mu.
+func (s *SessionStore) unindexEntityType(ent *types.Entity) {
+	key := normalizeEntityType(ent.Type)
+	delete(s.entByType[key], ent.ID)
+	if len(s.entByType[key]) == 0 {
+		delete(s.entByType, key)
+	}
+}
+
+// normalizeEntityType returns the type index key of an entity type
+func normalizeEntityType(entType string) string {
+	return strings.ToLower(strings.TrimSpace(entType))
+}
+
 // UpdateEntityDescription updates an entity's description
 func (s *SessionStore) UpdateEntityDescription(id uint64, description string, embedding []float32) bool {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	defer s.bumpEpoch()
 
 	ent, ok := s.entities[id]
 	if !ok {
@@ -482,9 +1079,14 @@ func (s *SessionStore) UpdateEntityDescription(id uint64, description string, em
 	}
 
 	ent.Description = description
+	s.entityLexical.Add(id, ent.Title, description)
 
-	// Update vector index
-	if len(embedding) > 0 && s.entityIndex != nil {
+	// Update vector index, or the deferred vector while bulk loading
+	if len(embedding) > 0 && s.deferred(IndexEntity, id) {
+		if err := s.deferVector(IndexEntity, id, embedding); err != nil {
+			return false
+		}
+	} else if len(embedding) > 0 && s.entityIndex != nil {
 		s.entityIndex.Remove(id)
 		if err := s.entityIndex.Add(id, embedding); err != nil {
 			return false
@@ -495,10 +1097,36 @@ func (s *SessionStore) UpdateEntityDescription(id uint64, description string, em
 	return true
 }
 
+// SetEntityAttrs replaces an entity's attributes. The map is copied and
+// swapped in whole, so readers holding the previous map are unaffected.
+func (s *SessionStore) SetEntityAttrs(id uint64, attrs map[string]string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.bumpEpoch()
+
+	ent, ok := s.entities[id]
+	if !ok {
+		return false
+	}
+
+	var copied map[string]string
+	if len(attrs) > 0 {
+		copied = make(map[string]string, len(attrs))
+		for k, v := range attrs {
+			copied[k] = v
+		}
+	}
+	ent.Attrs = copied
+
+	s.session.Touch()
+	return true
+}
+
 // DeleteEntity removes an entity
 func (s *SessionStore) DeleteEntity(id uint64) bool {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	defer s.bumpEpoch()
 
 	ent, ok := s.entities[id]
 	if !ok {
@@ -507,11 +1135,14 @@ func (s *SessionStore) DeleteEntity(id uint64) bool {
 
 	delete(s.entByTitle, ent.Title)
 	delete(s.entByExtID, ent.ExternalID)
+	s.unindexEntityType(ent)
 	delete(s.entities, id)
 
 	if s.entityIndex != nil {
 		s.entityIndex.Remove(id)
 	}
+	s.dropPending(IndexEntity, id)
+	s.entityLexical.Remove(id)
 
 	s.session.Touch()
 	return true
@@ -585,6 +1216,7 @@ func (s *SessionStore) makeRelKey(sourceID, targetID uint64) string {
 func (s *SessionStore) AddRelationship(extID string, sourceID, targetID uint64, relType, description string, weight float32) (*types.Relationship, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	defer s.bumpEpoch()
 
 	key := s.makeRelKey(sourceID, targetID)
 	if _, exists := s.relBySourceTarget[key]; exists {
@@ -697,6 +1329,7 @@ func (s *SessionStore) GetNeighbors(entityID uint64) []uint64 {
 func (s *SessionStore) DeleteRelationship(id uint64) bool {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	defer s.bumpEpoch()
 
 	rel, ok := s.relationships[id]
 	if !ok {
@@ -795,6 +1428,7 @@ func (s *SessionStore) RelationshipCount() int {
 func (s *SessionStore) AddCommunity(extID, title, summary, fullContent string, level int, entityIDs, relIDs []uint64, embedding []float32) (*types.Community, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	defer s.bumpEpoch()
 
 	if extID != "" {
 		if _, exists := s.commByExtID[extID]; exists {
@@ -811,12 +1445,13 @@ func (s *SessionStore) AddCommunity(extID, title, summary, fullContent string, l
 
 	// Add to vector index
 	if len(embedding) > 0 {
-		if err := s.getCommunityIndex().Add(comm.ID, embedding); err != nil {
+		if err := s.addVector(IndexCommunity, comm.ID, embedding); err != nil {
 			delete(s.communities, comm.ID)
 			delete(s.commByExtID, extID)
 			return nil, err
 		}
 	}
+	s.communityLexical.Add(comm.ID, title, summary)
 
 	s.session.Touch()
 	return comm, nil
@@ -849,10 +1484,27 @@ func (s *SessionStore) GetCommunitiesByLevel(level int) []*types.Community {
 	return result
 }
 
+// CommunityLevelCounts returns the number of communities at each level
+func (s *SessionStore) CommunityLevelCounts() map[int]int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	counts := make(map[int]int, len(s.commByLevel))
+	for level, ids := range s.commByLevel {
+		for _, id := range ids {
+			if _, ok := s.communities[id]; ok {
+				counts[level]++
+			}
+		}
+	}
+	return counts
+}
+
 // DeleteCommunity removes a community
 func (s *SessionStore) DeleteCommunity(id uint64) bool {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	defer s.bumpEpoch()
 
 	comm, ok := s.communities[id]
 	if !ok {
@@ -875,6 +1527,8 @@ func (s *SessionStore) DeleteCommunity(id uint64) bool {
 	if s.communityIndex != nil {
 		s.communityIndex.Remove(id)
 	}
+	s.dropPending(IndexCommunity, id)
+	s.communityLexical.Remove(id)
 
 	s.session.Touch()
 	return true
@@ -884,14 +1538,17 @@ func (s *SessionStore) DeleteCommunity(id uint64) bool {
 func (s *SessionStore) ClearCommunities() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	defer s.bumpEpoch()
 
 	s.communities = make(map[uint64]*types.Community)
 	s.commByExtID = make(map[string]uint64)
 	s.commByLevel = make(map[int][]uint64)
 
 	if s.communityIndex != nil {
-		s.communityIndex = vector.NewHNSWIndex(s.vectorDim, vector.DefaultHNSWConfig())
+		s.communityIndex = vector.NewHNSWIndex(s.vectorDim, s.options.CommunityIndex)
 	}
+	delete(s.pendingVectors, IndexCommunity)
+	s.communityLexical = lexical.NewIndex()
 }
 
 // GetAllCommunities returns all communities
@@ -921,6 +1578,7 @@ func (s *SessionStore) CommunityCount() int {
 func (s *SessionStore) Clear() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	defer s.bumpEpoch()
 
 	s.documents = make(map[uint64]*types.Document)
 	s.docByExtID = make(map[string]uint64)
@@ -933,6 +1591,7 @@ func (s *SessionStore) Clear() {
 	s.entities = make(map[uint64]*types.Entity)
 	s.entByExtID = make(map[string]uint64)
 	s.entByTitle = make(map[string]uint64)
+	s.entByType = make(map[string]map[uint64]struct{})
 
 	s.relationships = make(map[uint64]*types.Relationship)
 	s.relByExtID = make(map[string]uint64)
@@ -944,10 +1603,14 @@ func (s *SessionStore) Clear() {
 	s.commByExtID = make(map[string]uint64)
 	s.commByLevel = make(map[int][]uint64)
 
-	// Reset vector indices
+	// Reset vector and full-text indices
 	s.textUnitIndex = nil
 	s.entityIndex = nil
 	s.communityIndex = nil
+	s.pendingVectors = nil
+	s.textUnitLexical = lexical.NewIndex()
+	s.entityLexical = lexical.NewIndex()
+	s.communityLexical = lexical.NewIndex()
 
 	// Reset ID generator
 	s.idGen = types.NewIDGenerator()
@@ -970,6 +1633,19 @@ type SessionSnapshot struct {
 	TextUnitVectors  map[uint64][]float32  `json:"text_unit_vectors"`
 	EntityVectors    map[uint64][]float32  `json:"entity_vectors"`
 	CommunityVectors map[uint64][]float32  `json:"community_vectors"`
+	Options          *SessionOptions       `json:"options,omitempty"` // nil in older snapshots (defaults)
+
+	// Trained quantizers and the codes of vectors kept only in quantized
+	// form (those vectors are absent from the *Vectors maps)
+	TextUnitQuantized  *vector.QuantizedState `json:"text_unit_quantized,omitempty"`
+	EntityQuantized    *vector.QuantizedState `json:"entity_quantized,omitempty"`
+	CommunityQuantized *vector.QuantizedState `json:"community_quantized,omitempty"`
+
+	// Full-text indices; nil in older snapshots, which rebuild them from
+	// the object text on restore
+	TextUnitLexical  *lexical.State `json:"text_unit_lexical,omitempty"`
+	EntityLexical    *lexical.State `json:"entity_lexical,omitempty"`
+	CommunityLexical *lexical.State `json:"community_lexical,omitempty"`
 }
 
 // Snapshot creates a snapshot of the session
@@ -977,6 +1653,8 @@ func (s *SessionStore) Snapshot() *SessionSnapshot {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
+	// A copy, so a later RebuildIndex does not change the snapshot
+	opts := s.options
 	snapshot := &SessionSnapshot{
 		SessionID:        s.session.ID,
 		Session:          s.session,
@@ -986,6 +1664,10 @@ func (s *SessionStore) Snapshot() *SessionSnapshot {
 		Relationships:    s.GetAllRelationships(),
 		Communities:      s.GetAllCommunities(),
 		IDGeneratorState: make(map[string]uint64),
+		Options:          &opts,
+		TextUnitLexical:  s.textUnitLexical.State(),
+		EntityLexical:    s.entityLexical.State(),
+		CommunityLexical: s.communityLexical.State(),
 	}
 
 	// Save ID generator state
@@ -998,22 +1680,96 @@ func (s *SessionStore) Snapshot() *SessionSnapshot {
 
 	// Save vector indices
 	if s.textUnitIndex != nil {
-		snapshot.TextUnitVectors = s.textUnitIndex.GetAllVectors()
+		snapshot.TextUnitVectors, snapshot.TextUnitQuantized = snapshotIndex(s.textUnitIndex)
 	}
 	if s.entityIndex != nil {
-		snapshot.EntityVectors = s.entityIndex.GetAllVectors()
+		snapshot.EntityVectors, snapshot.EntityQuantized = snapshotIndex(s.entityIndex)
 	}
 	if s.communityIndex != nil {
-		snapshot.CommunityVectors = s.communityIndex.GetAllVectors()
+		snapshot.CommunityVectors, snapshot.CommunityQuantized = snapshotIndex(s.communityIndex)
 	}
 
+	// Deferred vectors are restored into the index directly
+	snapshot.TextUnitVectors = s.withDeferred(IndexTextUnit, snapshot.TextUnitVectors, snapshot.TextUnitQuantized)
+	snapshot.EntityVectors = s.withDeferred(IndexEntity, snapshot.EntityVectors, snapshot.EntityQuantized)
+	snapshot.CommunityVectors = s.withDeferred(IndexCommunity, snapshot.CommunityVectors, snapshot.CommunityQuantized)
+
 	return snapshot
 }
 
+// withDeferred adds the pending and building vectors of the named index to
+// vectors; a vector already in the index takes precedence. Caller holds
+// the lock.
+func (s *SessionStore) withDeferred(name string, vectors map[uint64][]float32, state *vector.QuantizedState) map[uint64][]float32 {
+	for _, deferred := range []map[uint64][]float32{s.pendingVectors[name], s.buildingVectors[name]} {
+		for id, vec := range deferred {
+			if !s.hasObject(name, id) {
+				continue
+			}
+			if state != nil {
+				if _, ok := state.Codes[id]; ok {
+					continue
+				}
+			}
+			if vectors == nil {
+				vectors = make(map[uint64][]float32)
+			}
+			if _, ok := vectors[id]; !ok {
+				vectors[id] = vec
+			}
+		}
+	}
+	return vectors
+}
+
+// quantizedIndex is implemented by indices that persist quantized codes
+type quantizedIndex interface {
+	QuantizedState() *vector.QuantizedState
+	RestoreQuantized(state *vector.QuantizedState) error
+}
+
+// snapshotIndex returns the full-precision vectors of idx and, when it is
+// quantized, its quantizer state. Vectors held only as codes are persisted
+// as codes rather than as decoded floats.
+func snapshotIndex(idx vector.Index) (map[uint64][]float32, *vector.QuantizedState) {
+	vectors := idx.GetAllVectors()
+	qi, ok := idx.(quantizedIndex)
+	if !ok {
+		return vectors, nil
+	}
+	state := qi.QuantizedState()
+	if state != nil {
+		for id := range state.Codes {
+			delete(vectors, id)
+		}
+	}
+	return vectors, state
+}
+
+// restoreIndex installs a persisted quantizer and re-adds vectors to idx
+func restoreIndex(idx vector.Index, vectors map[uint64][]float32, state *vector.QuantizedState) error {
+	if state != nil {
+		qi, ok := idx.(quantizedIndex)
+		if !ok {
+			return fmt.Errorf("index does not support quantization")
+		}
+		if err := qi.RestoreQuantized(state); err != nil {
+			return err
+		}
+	}
+	for id, vec := range vectors {
+		if err := idx.Add(id, vec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // RestoreFromSnapshot restores a session from a snapshot
 func (s *SessionStore) RestoreFromSnapshot(snapshot *SessionSnapshot) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	defer s.bumpEpoch()
 
 	// Restore session metadata
 	s.session = snapshot.Session
@@ -1044,9 +1800,11 @@ func (s *SessionStore) RestoreFromSnapshot(snapshot *SessionSnapshot) error {
 	s.entities = make(map[uint64]*types.Entity)
 	s.entByExtID = make(map[string]uint64)
 	s.entByTitle = make(map[string]uint64)
+	s.entByType = make(map[string]map[uint64]struct{})
 	for _, ent := range snapshot.Entities {
 		s.entities[ent.ID] = ent
 		s.entByTitle[ent.Title] = ent.ID
+		s.indexEntityType(ent)
 		if ent.ExternalID != "" {
 			s.entByExtID[ent.ExternalID] = ent.ID
 		}
@@ -1081,38 +1839,64 @@ func (s *SessionStore) RestoreFromSnapshot(snapshot *SessionSnapshot) error {
 		s.commByLevel[comm.Level] = append(s.commByLevel[comm.Level], comm.ID)
 	}
 
+	// Restore full-text indices; older snapshots without them rebuild
+	// them from the restored object text
+	s.textUnitLexical = lexical.NewIndex()
+	if snapshot.TextUnitLexical != nil {
+		s.textUnitLexical.Restore(snapshot.TextUnitLexical)
+	} else {
+		for _, tu := range snapshot.TextUnits {
+			s.textUnitLexical.Add(tu.ID, tu.Content)
+		}
+	}
+	s.entityLexical = lexical.NewIndex()
+	if snapshot.EntityLexical != nil {
+		s.entityLexical.Restore(snapshot.EntityLexical)
+	} else {
+		for _, ent := range snapshot.Entities {
+			s.entityLexical.Add(ent.ID, ent.Title, ent.Description)
+		}
+	}
+	s.communityLexical = lexical.NewIndex()
+	if snapshot.CommunityLexical != nil {
+		s.communityLexical.Restore(snapshot.CommunityLexical)
+	} else {
+		for _, comm := range snapshot.Communities {
+			s.communityLexical.Add(comm.ID, comm.Title, comm.Summary)
+		}
+	}
+
 	// Restore ID generator
 	if snapshot.IDGeneratorState != nil {
 		s.idGen.RestoreState(snapshot.IDGeneratorState)
 	}
 
-	// Restore vector indices
+	// Restore vector indices. Snapshots without a per-session dimension
+	// keep the dimension the store was created with.
+	if snapshot.Options != nil {
+		s.options = *snapshot.Options
+		if s.options.VectorDim > 0 {
+			s.vectorDim = s.options.VectorDim
+		}
+		s.options.VectorDim = s.vectorDim
+	}
 	s.textUnitIndex = nil
 	s.entityIndex = nil
 	s.communityIndex = nil
 
-	if len(snapshot.TextUnitVectors) > 0 {
-		idx := s.getTextUnitIndex()
-		for id, vec := range snapshot.TextUnitVectors {
-			if err := idx.Add(id, vec); err != nil {
-				return err
-			}
+	if len(snapshot.TextUnitVectors) > 0 || snapshot.TextUnitQuantized != nil {
+		if err := restoreIndex(s.getTextUnitIndex(), snapshot.TextUnitVectors, snapshot.TextUnitQuantized); err != nil {
+			return fmt.Errorf("text unit index: %w", err)
 		}
 	}
-	if len(snapshot.EntityVectors) > 0 {
-		idx := s.getEntityIndex()
-		for id, vec := range snapshot.EntityVectors {
-			if err := idx.Add(id, vec); err != nil {
-				return err
-			}
+	if len(snapshot.EntityVectors) > 0 || snapshot.EntityQuantized != nil {
+		if err := restoreIndex(s.getEntityIndex(), snapshot.EntityVectors, snapshot.EntityQuantized); err != nil {
+			return fmt.Errorf("entity index: %w", err)
 		}
 	}
-	if len(snapshot.CommunityVectors) > 0 {
-		idx := s.getCommunityIndex()
-		for id, vec := range snapshot.CommunityVectors {
-			if err := idx.Add(id, vec); err != nil {
-				return err
-			}
+	if len(snapshot.CommunityVectors) > 0 || snapshot.CommunityQuantized != nil {
+		if err := restoreIndex(s.getCommunityIndex(), snapshot.CommunityVectors, snapshot.CommunityQuantized); err != nil {
+			return fmt.Errorf("community index: %w", err)
 		}
 	}
 
@@ -2,9 +2,14 @@
 package store
 
 import (
+	"encoding/json"
 	"fmt"
+	"reflect"
 	"testing"
 	"time"
+
+	"github.com/gibram-io/gibram/pkg/lexical"
+	"github.com/gibram-io/gibram/pkg/vector"
 )
 
 const testVectorDim = 64
@@ -403,6 +408,44 @@ func TestGetEntityByTitle(t *testing.T) {
 	}
 }
 
+func TestGetEntitiesByType(t *testing.T) {
+	store := NewSessionStore("test-session", testVectorDim)
+
+	embedding := make([]float32, testVectorDim)
+	alice := mustAddEntity(t, store, "ent-001", "Alice", "Person", "Description", embedding)
+	bob := mustAddEntity(t, store, "ent-002", "Bob", "person", "Description", embedding)
+	mustAddEntity(t, store, "ent-003", "Acme", "organization", "Description", embedding)
+
+	people := store.GetEntitiesByType("PERSON")
+	if len(people) != 2 || people[0].ID != alice.ID || people[1].ID != bob.ID {
+		t.Fatalf("Expected Alice and Bob in ID order, got %v", people)
+	}
+	if n := store.EntityTypeCount("organization"); n != 1 {
+		t.Errorf("Expected 1 organization, got %d", n)
+	}
+	if n := store.EntityTypeCount("location"); n != 0 {
+		t.Errorf("Expected 0 locations, got %d", n)
+	}
+
+	store.DeleteEntity(alice.ID)
+	if n := store.EntityTypeCount("person"); n != 1 {
+		t.Errorf("Expected 1 person after delete, got %d", n)
+	}
+
+	ent, ok := store.GetEntityByExternalID("ent-002")
+	if !ok || ent.ID != bob.ID {
+		t.Errorf("GetEntityByExternalID should find Bob, got %v", ent)
+	}
+	if _, ok := store.GetEntityByExternalID("ent-001"); ok {
+		t.Error("GetEntityByExternalID should not find a deleted entity")
+	}
+
+	store.Clear()
+	if n := store.EntityTypeCount("person"); n != 0 {
+		t.Errorf("Expected 0 people after clear, got %d", n)
+	}
+}
+
 func TestUpdateEntityDescription(t *testing.T) {
 	store := NewSessionStore("test-session", testVectorDim)
 
@@ -428,6 +471,35 @@ func TestUpdateEntityDescription(t *testing.T) {
 	}
 }
 
+func TestSetEntityAttrs(t *testing.T) {
+	store := NewSessionStore("test-session", testVectorDim)
+
+	embedding := make([]float32, testVectorDim)
+	entity := mustAddEntity(t, store, "ent-001", "Test Entity", "person", "Description", embedding)
+
+	attrs := map[string]string{"lang": "en"}
+	if !store.SetEntityAttrs(entity.ID, attrs) {
+		t.Error("SetEntityAttrs should return true")
+	}
+	attrs["lang"] = "id" // the store keeps its own copy
+
+	retrieved, _ := store.GetEntity(entity.ID)
+	if retrieved.Attrs["lang"] != "en" {
+		t.Errorf("Expected attrs lang=en, got %v", retrieved.Attrs)
+	}
+
+	if !store.SetEntityAttrs(entity.ID, nil) {
+		t.Error("SetEntityAttrs(nil) should return true")
+	}
+	if retrieved, _ = store.GetEntity(entity.ID); retrieved.Attrs != nil {
+		t.Errorf("Expected no attrs, got %v", retrieved.Attrs)
+	}
+
+	if store.SetEntityAttrs(99999, attrs) {
+		t.Error("SetEntityAttrs should return false for non-existent ID")
+	}
+}
+
 func TestDeleteEntity(t *testing.T) {
 	store := NewSessionStore("test-session", testVectorDim)
 
@@ -751,6 +823,94 @@ func TestDeleteCommunity(t *testing.T) {
 	}
 }
 
+func TestCommunityLevelCounts(t *testing.T) {
+	store := NewSessionStore("test-session", testVectorDim)
+
+	embedding := make([]float32, testVectorDim)
+	mustAddCommunity(t, store, "comm-001", "Root", "Summary", "Full content", 0, nil, nil, embedding)
+	leaf := mustAddCommunity(t, store, "comm-002", "Leaf A", "Summary", "Full content", 1, nil, nil, embedding)
+	mustAddCommunity(t, store, "comm-003", "Leaf B", "Summary", "Full content", 1, nil, nil, embedding)
+
+	if got := store.CommunityLevelCounts(); got[0] != 1 || got[1] != 2 || len(got) != 2 {
+		t.Errorf("Expected {0:1 1:2}, got %v", got)
+	}
+
+	store.DeleteCommunity(leaf.ID)
+	if got := store.CommunityLevelCounts(); got[1] != 1 {
+		t.Errorf("Expected 1 community at level 1 after delete, got %v", got)
+	}
+}
+
+func TestSessionStore_Epoch(t *testing.T) {
+	store := NewSessionStore("test-session", testVectorDim)
+	other := NewSessionStore("test-session", testVectorDim)
+	if store.Epoch() == other.Epoch() {
+		t.Error("Sessions sharing an ID should not share an epoch")
+	}
+
+	epoch := store.Epoch()
+	ent := mustAddEntity(t, store, "ent-001", "Entity", "type", "", make([]float32, testVectorDim))
+	if store.Epoch() == epoch {
+		t.Error("Expected AddEntity to change the epoch")
+	}
+
+	epoch = store.Epoch()
+	store.GetEntity(ent.ID)
+	store.GetInfo()
+	if store.Epoch() != epoch {
+		t.Error("Expected reads to keep the epoch")
+	}
+
+	store.DeleteEntity(ent.ID)
+	if store.Epoch() == epoch {
+		t.Error("Expected DeleteEntity to change the epoch")
+	}
+}
+
+func TestQueryCache(t *testing.T) {
+	cache := NewQueryCache(2, 0)
+	cache.Put("a", 1, "result a")
+	cache.Put("b", 1, "result b")
+
+	if v, ok := cache.Get("a", 1); !ok || v != "result a" {
+		t.Errorf("Get(a) = %v, %v; want result a", v, ok)
+	}
+	if _, ok := cache.Get("a", 2); ok {
+		t.Error("Expected an entry of another epoch to miss")
+	}
+	if _, ok := cache.Get("a", 1); ok {
+		t.Error("Expected the stale entry to be dropped")
+	}
+
+	// b is least recently used once c arrives
+	cache.Put("a", 1, "result a")
+	cache.Get("a", 1)
+	cache.Put("c", 1, "result c")
+	if _, ok := cache.Get("b", 1); ok || cache.Len() != 2 {
+		t.Errorf("Expected b evicted and 2 entries, got %d", cache.Len())
+	}
+	if cache.Hits() != 2 || cache.Misses() != 3 {
+		t.Errorf("Expected 2 hits and 3 misses, got %d and %d", cache.Hits(), cache.Misses())
+	}
+
+	expiring := NewQueryCache(10, time.Millisecond)
+	expiring.Put("a", 1, "result a")
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := expiring.Get("a", 1); ok {
+		t.Error("Expected an expired entry to miss")
+	}
+
+	store := NewSessionStore("test-session", testVectorDim)
+	store.SetQueryCache(5, time.Minute)
+	if info := store.GetInfo(); info.QueryCacheSize != 5 || info.QueryCacheTTL != 60 {
+		t.Errorf("Expected cache size 5 and ttl 60, got %d and %d", info.QueryCacheSize, info.QueryCacheTTL)
+	}
+	store.SetQueryCache(0, 0)
+	if store.QueryCache() != nil {
+		t.Error("Expected size 0 to turn the cache off")
+	}
+}
+
 // =============================================================================
 // Vector Index Tests
 // =============================================================================
@@ -800,6 +960,150 @@ func TestGetCommunityIndex(t *testing.T) {
 	}
 }
 
+func TestSessionStore_BulkLoad(t *testing.T) {
+	store := NewSessionStore("test-session", testVectorDim)
+	embedding := func(seed int) []float32 {
+		v := make([]float32, testVectorDim)
+		v[seed%testVectorDim] = 1
+		v[(seed+1)%testVectorDim] = float32(seed)
+		return v
+	}
+	before := mustAddEntity(t, store, "e0", "Before", "T", "", embedding(0))
+
+	if store.BeginBulkLoad() {
+		t.Error("BeginBulkLoad() on a fresh session reported bulk loading")
+	}
+	if !store.BulkLoading() {
+		t.Fatal("BulkLoading() = false after BeginBulkLoad")
+	}
+	ents := make([]uint64, 0)
+	for i := 1; i <= 20; i++ {
+		ents = append(ents, mustAddEntity(t, store, fmt.Sprintf("e%d", i), fmt.Sprintf("Entity %d", i), "T", "", embedding(i)).ID)
+	}
+	if _, err := store.AddEntity("bad", "Bad", "T", "", make([]float32, 3)); err == nil {
+		t.Error("AddEntity with a wrong dimension should fail while bulk loading")
+	}
+	if got := store.GetEntityIndex().Count(); got != 1 {
+		t.Errorf("entity index has %d vectors before the build, want 1", got)
+	}
+	if got := store.PendingVectors(); got != 20 {
+		t.Errorf("PendingVectors() = %d, want 20", got)
+	}
+
+	// Deferred vectors are updated, deleted and snapshotted like built ones
+	store.UpdateEntityDescription(ents[0], "updated", embedding(100))
+	store.UpdateEntityDescription(before.ID, "updated", embedding(101))
+	store.DeleteEntity(ents[1])
+	if snap := store.Snapshot(); len(snap.EntityVectors) != 20 {
+		t.Errorf("snapshot has %d entity vectors, want 20", len(snap.EntityVectors))
+	}
+
+	last := 0
+	err := store.BuildPendingVectors(2, func(index string, done, total int) {
+		if index != IndexEntity || total != 19 || done < last {
+			t.Errorf("progress(%s, %d, %d) after %d", index, done, total, last)
+		}
+		last = done
+	})
+	if err != nil {
+		t.Fatalf("BuildPendingVectors() error: %v", err)
+	}
+	if last != 19 {
+		t.Errorf("last progress = %d, want 19", last)
+	}
+	if store.BulkLoading() || store.PendingVectors() != 0 {
+		t.Error("bulk load should end after the build")
+	}
+	idx := store.GetEntityIndex()
+	if got := idx.Count(); got != 20 {
+		t.Errorf("entity index has %d vectors after the build, want 20", got)
+	}
+	if results := idx.Search(embedding(100), 1); len(results) != 1 || results[0].ID != ents[0] {
+		t.Errorf("Search for the updated deferred vector = %+v", results)
+	}
+	if results := idx.Search(embedding(101), 1); len(results) != 1 || results[0].ID != before.ID {
+		t.Errorf("Search for the updated built vector = %+v", results)
+	}
+
+	// Without a bulk load vectors are indexed immediately
+	mustAddEntity(t, store, "e99", "After", "T", "", embedding(99))
+	if got := idx.Count(); got != 21 {
+		t.Errorf("entity index has %d vectors, want 21", got)
+	}
+}
+
+func TestSnapshotPersistsLexicalIndices(t *testing.T) {
+	store := NewSessionStore("test-session", testVectorDim)
+	embedding := make([]float32, testVectorDim)
+	embedding[0] = 1
+
+	doc := mustAddDocument(t, store, "doc-001", "reg.pdf")
+	mustAddTextUnit(t, store, "tu-001", doc.ID, "Regulation PBI/2024 on payment systems", embedding, 6)
+	ent := mustAddEntity(t, store, "e1", "Bank Indonesia", "ORG", "issues PBI/2024", embedding)
+	mustAddCommunity(t, store, "c1", "Payments", "payment regulators", "", 0, []uint64{ent.ID}, nil, embedding)
+
+	data, err := json.Marshal(store.Snapshot())
+	if err != nil {
+		t.Fatalf("Marshal snapshot: %v", err)
+	}
+	var snap SessionSnapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		t.Fatalf("Unmarshal snapshot: %v", err)
+	}
+	if snap.TextUnitLexical == nil || snap.EntityLexical == nil || snap.CommunityLexical == nil {
+		t.Fatal("snapshot does not include the lexical indices")
+	}
+
+	check := func(name string, restored *SessionStore) {
+		t.Helper()
+		for _, c := range []struct {
+			index func(*SessionStore) *lexical.Index
+			query string
+		}{
+			{(*SessionStore).GetTextUnitLexicalIndex, "pbi 2024"},
+			{(*SessionStore).GetEntityLexicalIndex, "indonesia"},
+			{(*SessionStore).GetCommunityLexicalIndex, "regulators"},
+		} {
+			got := c.index(restored).Search(c.query, 10, nil)
+			want := c.index(store).Search(c.query, 10, nil)
+			if len(got) != 1 || !reflect.DeepEqual(got, want) {
+				t.Errorf("%s: Search(%q) = %+v, want %+v", name, c.query, got, want)
+			}
+		}
+	}
+
+	restored := NewSessionStore("test-session", testVectorDim)
+	if err := restored.RestoreFromSnapshot(&snap); err != nil {
+		t.Fatalf("RestoreFromSnapshot: %v", err)
+	}
+	check("persisted", restored)
+
+	// Snapshots written before the indices were persisted rebuild them
+	snap.TextUnitLexical, snap.EntityLexical, snap.CommunityLexical = nil, nil, nil
+	rebuilt := NewSessionStore("test-session", testVectorDim)
+	if err := rebuilt.RestoreFromSnapshot(&snap); err != nil {
+		t.Fatalf("RestoreFromSnapshot without lexical state: %v", err)
+	}
+	check("rebuilt", rebuilt)
+}
+
+func TestSnapshotCopiesOptions(t *testing.T) {
+	store := NewSessionStore("test-session", testVectorDim)
+	snap := store.Snapshot()
+
+	config := vector.DefaultHNSWConfig()
+	config.Metric = vector.MetricL2
+	if err := store.RebuildIndex(IndexEntity, config, nil); err != nil {
+		t.Fatalf("RebuildIndex failed: %v", err)
+	}
+	if got := snap.Options.EntityIndex.Metric; got == vector.MetricL2 {
+		t.Error("RebuildIndex changed the options of an earlier snapshot")
+	}
+	if got := store.Snapshot().Options.EntityIndex.Metric; got != vector.MetricL2 {
+		t.Errorf("snapshot after RebuildIndex has metric %s, want l2", got)
+	}
+}
+
 // =============================================================================
 // ID Generator Tests
 // =============================================================================
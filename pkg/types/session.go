@@ -294,4 +294,40 @@ type SessionInfo struct {
 	MaxRelationships  int    `json:"max_relationships,omitempty"`
 	MaxDocuments      int    `json:"max_documents,omitempty"`
 	MaxMemoryBytes    int64  `json:"max_memory_bytes,omitempty"`
+
+	// Vector index sizes (vectors currently indexed)
+	TextUnitIndexSize  int `json:"textunit_index_size"`
+	EntityIndexSize    int `json:"entity_index_size"`
+	CommunityIndexSize int `json:"community_index_size"`
+
+	// Bulk load state (vectors deferred until the bulk build)
+	BulkLoading    bool `json:"bulk_loading,omitempty"`
+	PendingVectors int  `json:"pending_vectors,omitempty"`
+
+	// Vector index configuration
+	VectorDim      int         `json:"vector_dim,omitempty"`
+	TextUnitIndex  IndexConfig `json:"textunit_index"`
+	EntityIndex    IndexConfig `json:"entity_index"`
+	CommunityIndex IndexConfig `json:"community_index"`
+
+	// Query result cache (size 0 = caching off)
+	QueryCacheSize    int    `json:"query_cache_size,omitempty"`
+	QueryCacheTTL     int64  `json:"query_cache_ttl,omitempty"` // seconds, 0 = no expiry
+	QueryCacheEntries int    `json:"query_cache_entries,omitempty"`
+	QueryCacheHits    uint64 `json:"query_cache_hits,omitempty"`
+	QueryCacheMisses  uint64 `json:"query_cache_misses,omitempty"`
+}
+
+// IndexConfig describes the configuration of one vector index of a session
+type IndexConfig struct {
+	Metric         string `json:"metric,omitempty"` // "cosine", "dot" or "l2"
+	M              int    `json:"m,omitempty"`
+	EfConstruction int    `json:"ef_construction,omitempty"`
+	EfSearch       int    `json:"ef_search,omitempty"`
+
+	Quantization    string `json:"quantization,omitempty"` // "none", "sq8" or "pq"
+	PQSubvectors    int    `json:"pq_subvectors,omitempty"`
+	KeepFullVectors bool   `json:"keep_full_vectors,omitempty"`
+
+	TombstoneRatio float64 `json:"tombstone_ratio,omitempty"` // deleted share that starts compaction
 }
@@ -4,6 +4,7 @@ package types
 import (
 	"sync/atomic"
 	"time"
+	"unicode/utf8"
 )
 
 // =============================================================================
@@ -314,6 +315,88 @@ const (
 	SearchTypeCommunity SearchType = "community"
 )
 
+// FusionMode selects how lexical and vector hits are combined
+type FusionMode string
+
+const (
+	FusionRRF      FusionMode = "rrf"      // reciprocal rank fusion (default)
+	FusionWeighted FusionMode = "weighted" // weighted sum of normalized scores
+)
+
+// DefaultLexicalWeight is the lexical share of weighted fusion
+const DefaultLexicalWeight = 0.5
+
+// ScoringStrategy selects how graph expansion scores traversed entities
+type ScoringStrategy string
+
+const (
+	ScoringHop      ScoringStrategy = "hop"      // 1/(1+hop), ignoring seeds and edges (default)
+	ScoringWeighted ScoringStrategy = "weighted" // seed score x decay^hop x normalized edge weights
+)
+
+// ScoreAggregation combines the scores of several paths reaching an entity
+type ScoreAggregation string
+
+const (
+	AggregateMax ScoreAggregation = "max" // best path (default)
+	AggregateSum ScoreAggregation = "sum" // sum over paths
+)
+
+// DefaultScoreDecay is the per-hop decay of weighted scoring
+const DefaultScoreDecay = 0.5
+
+// ExpansionMode selects how Query expands seeds through the graph
+type ExpansionMode string
+
+const (
+	ExpansionBFS ExpansionMode = "bfs" // breadth-first hops, scored by Scoring (default)
+	ExpansionPPR ExpansionMode = "ppr" // personalized PageRank over the k-hop neighbourhood
+)
+
+// RestartMode selects the restart distribution of personalized PageRank
+type RestartMode string
+
+const (
+	RestartScore   RestartMode = "score"   // proportional to seed scores (default)
+	RestartUniform RestartMode = "uniform" // equal for every seed
+)
+
+// Personalized PageRank defaults and bounds
+const (
+	DefaultPPRDamping    = 0.85
+	DefaultPPRIterations = 20
+	MaxPPRIterations     = 100
+)
+
+// RerankMode selects how Query re-ranks its result lists
+type RerankMode string
+
+const (
+	RerankNone RerankMode = "none" // score order (default)
+	RerankMMR  RerankMode = "mmr"  // maximal marginal relevance over stored embeddings
+)
+
+// DefaultMMRLambda is the relevance share of MMR re-ranking
+const DefaultMMRLambda = 0.7
+
+// ContextFormat selects how Query renders its prompt-ready context
+type ContextFormat string
+
+const (
+	ContextFormatMarkdown ContextFormat = "markdown" // markdown tables
+	ContextFormatCSV      ContextFormat = "csv"      // pipe-delimited tables, as in GraphRAG local search
+)
+
+// Token-budgeted context defaults and bounds. The default shares split
+// the budget when a query gives none.
+const (
+	MaxContextTokenBudget    = 1000000
+	DefaultEntityShare       = 0.2
+	DefaultRelationshipShare = 0.15
+	DefaultTextUnitShare     = 0.5
+	DefaultCommunityShare    = 0.15
+)
+
 type QuerySpec struct {
 	QueryVector    []float32    `json:"query_vector"`
 	SearchTypes    []SearchType `json:"search_types"` // which indices to search
@@ -323,6 +406,57 @@ type QuerySpec struct {
 	MaxTextUnits   int          `json:"max_text_units"`
 	MaxCommunities int          `json:"max_communities"`
 	DeadlineMs     int          `json:"deadline_ms"`
+
+	// Filters, applied during vector search and graph expansion. Empty
+	// filters match everything.
+	FilterEntityTypes []string          `json:"filter_entity_types,omitempty"` // entity types to keep
+	FilterDocumentIDs []uint64          `json:"filter_document_ids,omitempty"` // text units from these documents
+	FilterAttrs       map[string]string `json:"filter_attrs,omitempty"`        // entity attributes that must all match
+
+	// Hybrid retrieval. When QueryText is set, BM25 hits are fused with
+	// vector hits before graph expansion; QueryVector may then be empty.
+	QueryText     string     `json:"query_text,omitempty"`
+	Fusion        FusionMode `json:"fusion,omitempty"`         // default rrf
+	LexicalWeight float32    `json:"lexical_weight,omitempty"` // weighted fusion only, 0 = DefaultLexicalWeight
+
+	// Graph expansion scoring. Weighted scoring propagates seed scores
+	// along relationships, so strong neighbours of good seeds rank first.
+	Scoring     ScoringStrategy  `json:"scoring,omitempty"`     // default hop
+	Decay       float32          `json:"decay,omitempty"`       // weighted scoring only, 0 = DefaultScoreDecay
+	Aggregation ScoreAggregation `json:"aggregation,omitempty"` // weighted scoring only, default max
+
+	// Graph expansion mode. PPR ranks the k-hop neighbourhood of the seeds
+	// by personalized PageRank instead of BFS hop scores. RestartWeights,
+	// keyed by entity ID, overrides the restart distribution.
+	Expansion      ExpansionMode      `json:"expansion,omitempty"`       // default bfs
+	Damping        float32            `json:"damping,omitempty"`         // ppr only, 0 = DefaultPPRDamping
+	Iterations     int                `json:"iterations,omitempty"`      // ppr only, 0 = DefaultPPRIterations
+	Restart        RestartMode        `json:"restart,omitempty"`         // ppr only, default score
+	RestartWeights map[uint64]float32 `json:"restart_weights,omitempty"` // ppr only
+
+	// Diversity re-ranking of text units, entities and communities. MMR
+	// trades score against similarity to results ranked before; dedup
+	// drops results at least DedupThreshold similar to a kept one.
+	// Explain lists every result moved down or dropped.
+	Rerank         RerankMode `json:"rerank,omitempty"`           // default none
+	MMRLambda      float32    `json:"mmr_lambda,omitempty"`       // mmr only, 0 = DefaultMMRLambda
+	DedupThreshold float32    `json:"dedup_threshold,omitempty"`  // 0 = no dedup
+	MaxPerDocument int        `json:"max_per_document,omitempty"` // text units per document, 0 = no cap
+
+	// Token-budgeted context. With a TokenBudget each result list is
+	// packed greedily by score into its share of the budget instead of
+	// being cut at its Max* count; MaxEntities still bounds expansion.
+	// Shares are relative weights, all zero = the default split.
+	TokenBudget       int           `json:"token_budget,omitempty"`
+	EntityShare       float32       `json:"entity_share,omitempty"`
+	RelationshipShare float32       `json:"relationship_share,omitempty"`
+	TextUnitShare     float32       `json:"text_unit_share,omitempty"`
+	CommunityShare    float32       `json:"community_share,omitempty"`
+	ContextFormat     ContextFormat `json:"context_format,omitempty"` // renders Context, "" = none
+
+	// NoCache bypasses the session query cache: the query neither reads
+	// nor fills it
+	NoCache bool `json:"no_cache,omitempty"`
 }
 
 func DefaultQuerySpec() QuerySpec {
@@ -373,6 +507,7 @@ type QueryStats struct {
 	CommunitiesSearched int   `json:"communities_searched"`
 	EdgesScanned        int   `json:"edges_scanned"`
 	DurationMicros      int64 `json:"duration_micros"`
+	CacheHit            bool  `json:"cache_hit,omitempty"` // served from the session query cache
 }
 
 type ContextPack struct {
@@ -382,6 +517,254 @@ type ContextPack struct {
 	Communities   []CommunityResult    `json:"communities"`
 	Relationships []RelationshipResult `json:"relationships"`
 	Stats         QueryStats           `json:"stats"`
+	Context       string               `json:"context,omitempty"`     // rendered context, with ContextFormat
+	TokenUsage    *TokenUsage          `json:"token_usage,omitempty"` // with TokenBudget
+}
+
+// TokenUsage counts the estimated tokens packed into each section of a
+// token-budgeted ContextPack
+type TokenUsage struct {
+	Entities      int `json:"entities"`
+	Relationships int `json:"relationships"`
+	TextUnits     int `json:"text_units"`
+	Communities   int `json:"communities"`
+	Total         int `json:"total"`
+}
+
+// =============================================================================
+// Batch Query Types
+// =============================================================================
+
+// Batch query limits
+const (
+	MaxBatchQueries     = 256 // queries per QUERY_BATCH call
+	MaxBatchParallelism = 64  // queries of one batch run at once
+)
+
+// BatchQueryResult is the outcome of one query of a batch: its
+// ContextPack, or the error that query failed with
+type BatchQueryResult struct {
+	Pack  *ContextPack `json:"pack,omitempty"`
+	Error string       `json:"error,omitempty"`
+}
+
+// =============================================================================
+// Search Types (k-NN without graph expansion)
+// =============================================================================
+
+// Search limits
+const (
+	DefaultSearchK   = 10
+	MaxSearchK       = 10000 // also caps range search results
+	MaxSearchVectors = 256   // query vectors per SEARCH call
+)
+
+// SearchSpec runs k-NN or range search on one vector index. Each query
+// vector gets its own result list.
+type SearchSpec struct {
+	Index          SearchType  `json:"index"`
+	QueryVectors   [][]float32 `json:"query_vectors"`
+	K              int         `json:"k"`                        // 0 = DefaultSearchK; range search: 0 = MaxSearchK
+	Ef             int         `json:"ef,omitempty"`             // HNSW candidate list override (0 = index default)
+	MinSimilarity  *float32    `json:"min_similarity,omitempty"` // drop hits below (nil = no threshold)
+	Range          bool        `json:"range,omitempty"`          // every hit at or above MinSimilarity
+	IncludeObjects bool        `json:"include_objects,omitempty"`
+
+	FilterEntityTypes []string          `json:"filter_entity_types,omitempty"` // entity index only
+	FilterDocumentIDs []uint64          `json:"filter_document_ids,omitempty"` // textunit index only
+	FilterAttrs       map[string]string `json:"filter_attrs,omitempty"`        // entity index only
+}
+
+// SearchHit is one nearest neighbor. The object matching the index is set
+// when IncludeObjects was requested.
+type SearchHit struct {
+	ID         uint64     `json:"id"`
+	ExternalID string     `json:"external_id"`
+	Similarity float32    `json:"similarity"`
+	TextUnit   *TextUnit  `json:"text_unit,omitempty"`
+	Entity     *Entity    `json:"entity,omitempty"`
+	Community  *Community `json:"community,omitempty"`
+}
+
+// RecallSpec runs a recall self-check of a session's vector indices,
+// comparing HNSW search with exact search
+type RecallSpec struct {
+	Index        SearchType  `json:"index,omitempty"` // "" = every index
+	K            int         `json:"k,omitempty"`
+	Ef           int         `json:"ef,omitempty"`
+	Samples      int         `json:"samples,omitempty"` // stored vectors sampled as queries
+	QueryVectors [][]float32 `json:"query_vectors,omitempty"`
+}
+
+// IndexRecall reports HNSW recall@k against exact search for one index
+type IndexRecall struct {
+	Index          SearchType   `json:"index"`
+	Vectors        int          `json:"vectors"`
+	Queries        int          `json:"queries"`
+	K              int          `json:"k"`
+	Ef             int          `json:"ef"`
+	Recall         float64      `json:"recall"`
+	MinRecall      float64      `json:"min_recall"`
+	LatencyUs      Distribution `json:"latency_us"`
+	ExactLatencyUs Distribution `json:"exact_latency_us"`
+	Visited        Distribution `json:"visited"` // nodes scored per HNSW search
+}
+
+// Distribution summarises a per-query measurement
+type Distribution struct {
+	Avg float64 `json:"avg"`
+	P50 float64 `json:"p50"`
+	P95 float64 `json:"p95"`
+	P99 float64 `json:"p99"`
+	Max float64 `json:"max"`
+}
+
+// Path search defaults and bounds
+const (
+	DefaultPathDepth = 6
+	MaxPathDepth     = 16
+	MaxPaths         = 32
+)
+
+// PathSpec finds the shortest paths between two entities, each given by
+// ID or, when the ID is 0, by title
+type PathSpec struct {
+	SourceID    uint64   `json:"source_id,omitempty"`
+	SourceTitle string   `json:"source_title,omitempty"`
+	TargetID    uint64   `json:"target_id,omitempty"`
+	TargetTitle string   `json:"target_title,omitempty"`
+	K           int      `json:"k,omitempty"`         // paths to return, 0 = 1
+	MaxDepth    int      `json:"max_depth,omitempty"` // relationships per path, 0 = DefaultPathDepth
+	Directed    bool     `json:"directed,omitempty"`  // follow relationships from source to target only
+	Weighted    bool     `json:"weighted,omitempty"`  // cheapest by 1/weight instead of fewest hops
+	RelTypes    []string `json:"rel_types,omitempty"` // relationship types to follow (empty = all)
+}
+
+// EntityPath is one path between two entities. Entities has one more
+// element than Relationships.
+type EntityPath struct {
+	Entities      []*Entity       `json:"entities"`
+	Relationships []*Relationship `json:"relationships"`
+	Cost          float64         `json:"cost"` // hops, or sum of 1/weight when weighted
+}
+
+// Direction selects which relationships graph expansion follows
+type Direction string
+
+const (
+	DirectionBoth Direction = "both" // outgoing and incoming (default)
+	DirectionOut  Direction = "out"  // source to target only
+	DirectionIn   Direction = "in"   // target to source only
+)
+
+// Subgraph extraction defaults and bounds
+const (
+	DefaultSubgraphNodes = 100
+	DefaultSubgraphEdges = 1000
+	MaxSubgraphNodes     = 10000
+	MaxSubgraphEdges     = 100000
+	MaxSubgraphHops      = 6
+)
+
+// SubgraphSpec extracts the neighbourhood of a set of entities
+type SubgraphSpec struct {
+	EntityIDs   []uint64  `json:"entity_ids"`
+	Hops        int       `json:"hops"`                   // radius, 0 = only EntityIDs
+	Direction   Direction `json:"direction,omitempty"`    // default both
+	EntityTypes []string  `json:"entity_types,omitempty"` // types of entities reached (empty = all)
+	RelTypes    []string  `json:"rel_types,omitempty"`    // relationship types (empty = all)
+	MaxNodes    int       `json:"max_nodes,omitempty"`    // 0 = DefaultSubgraphNodes
+	MaxEdges    int       `json:"max_edges,omitempty"`    // 0 = DefaultSubgraphEdges
+}
+
+// SubgraphNode is an entity of a subgraph with its degrees
+type SubgraphNode struct {
+	Entity    *Entity `json:"entity"`
+	Hop       int     `json:"hop"`        // distance from the nearest requested entity
+	InDegree  int     `json:"in_degree"`  // incoming relationships in the whole graph
+	OutDegree int     `json:"out_degree"` // outgoing relationships in the whole graph
+	Degree    int     `json:"degree"`     // relationships within the subgraph
+}
+
+// Subgraph is the induced subgraph of a neighbourhood: its entities and
+// every relationship between them
+type Subgraph struct {
+	Nodes         []SubgraphNode  `json:"nodes"`
+	Relationships []*Relationship `json:"relationships"`
+	Truncated     bool            `json:"truncated,omitempty"` // node or edge limit reached
+}
+
+// Graph pattern query defaults and bounds
+const (
+	DefaultGraphQueryLimit = 100     // rows when the query has no LIMIT
+	MaxGraphQueryLimit     = 10000   // largest LIMIT accepted
+	MaxGraphQueryWork      = 1000000 // candidate bindings examined per query
+)
+
+// GraphValue is one cell of a graph query row: an entity, a relationship
+// or a scalar Value (string, float64, bool or nil)
+type GraphValue struct {
+	Entity       *Entity       `json:"entity,omitempty"`
+	Relationship *Relationship `json:"relationship,omitempty"`
+	Value        any           `json:"value,omitempty"`
+}
+
+// GraphQueryResult holds the rows of a graph pattern query and the plan
+// that produced them
+type GraphQueryResult struct {
+	Columns   []string       `json:"columns"`
+	Rows      [][]GraphValue `json:"rows"`
+	Plan      []string       `json:"plan"`                // one line per plan step
+	Truncated bool           `json:"truncated,omitempty"` // more rows matched than the limit
+}
+
+// Global search defaults and bounds
+const (
+	DefaultGlobalTokenBudget = 8000
+	DefaultGlobalBatchTokens = 2000
+	MaxGlobalTokenBudget     = 1000000
+)
+
+// EstimateTokens approximates the LLM token count of text at four
+// characters per token, for records that carry no tokenizer count
+func EstimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
+
+// GlobalSearchSpec selects the community reports of one hierarchy level
+// for a map-reduce answer over the whole graph
+type GlobalSearchSpec struct {
+	QueryVector    []float32 `json:"query_vector,omitempty"`    // empty = rank by community size
+	Level          int       `json:"level"`                     // community level to search
+	MinCommunities int       `json:"min_communities,omitempty"` // fall back to the nearest level with this many (0 = 1)
+	TokenBudget    int       `json:"token_budget,omitempty"`    // 0 = DefaultGlobalTokenBudget
+	BatchTokens    int       `json:"batch_tokens,omitempty"`    // tokens per map batch, 0 = DefaultGlobalBatchTokens
+	UseSummary     bool      `json:"use_summary,omitempty"`     // budget Summary instead of FullContent
+}
+
+// CommunityReport is a ranked community selected by global search
+type CommunityReport struct {
+	Community *Community `json:"community"`
+	Score     float32    `json:"score"`  // similarity to the query vector (0 without one)
+	Rank      int        `json:"rank"`   // 1-based position among the level's communities
+	Tokens    int        `json:"tokens"` // estimated tokens of title and report
+}
+
+// ReportBatch is the input of one map step
+type ReportBatch struct {
+	Reports []CommunityReport `json:"reports"`
+	Tokens  int               `json:"tokens"`
+}
+
+// GlobalSearchResult holds the selected reports grouped into map batches,
+// best first
+type GlobalSearchResult struct {
+	Level          int           `json:"level"`           // level searched, after fallback
+	RequestedLevel int           `json:"requested_level"` // level asked for
+	Candidates     int           `json:"candidates"`      // communities ranked at Level
+	Batches        []ReportBatch `json:"batches"`
+	TokensUsed     int           `json:"tokens_used"`
+	Skipped        int           `json:"skipped"` // ranked reports left out by the budget
 }
 
 // =============================================================================
@@ -393,6 +776,7 @@ type SeedInfo struct {
 	ID         uint64     `json:"id"`
 	ExternalID string     `json:"external_id"`
 	Similarity float32    `json:"similarity"`
+	Lexical    float32    `json:"lexical,omitempty"` // BM25 score, hybrid queries only
 	LinkedIDs  []uint64   `json:"linked_ids"`
 }
 
@@ -406,10 +790,32 @@ type TraversalStep struct {
 	Cumulative     float32 `json:"cumulative_score"`
 }
 
+// DemotionReason says why re-ranking moved a result down or dropped it
+type DemotionReason string
+
+const (
+	DemotedMMR         DemotionReason = "mmr"          // too similar to results ranked before it
+	DemotedDuplicate   DemotionReason = "duplicate"    // at least DedupThreshold similar to a kept result
+	DemotedDocumentCap DemotionReason = "document_cap" // its document already has MaxPerDocument text units
+)
+
+// Demotion records one result moved down or dropped by re-ranking
+type Demotion struct {
+	Type       SearchType     `json:"type"`
+	ID         uint64         `json:"id"`
+	ExternalID string         `json:"external_id"`
+	Reason     DemotionReason `json:"reason"`
+	Rank       int            `json:"rank"`                 // 1-based rank by score
+	NewRank    int            `json:"new_rank"`             // 1-based rank after re-ranking, 0 = dropped
+	SimilarTo  uint64         `json:"similar_to,omitempty"` // most similar result ranked before it
+	Similarity float32        `json:"similarity,omitempty"`
+}
+
 type ExplainPack struct {
 	QueryID   uint64          `json:"query_id"`
 	Seeds     []SeedInfo      `json:"seeds"`
 	Traversal []TraversalStep `json:"traversal"`
+	Demotions []Demotion      `json:"demotions,omitempty"`
 }
 
 // =============================================================================
@@ -425,6 +831,46 @@ type ServerInfo struct {
 	CommunityCount    int    `json:"community_count"`
 	VectorDim         int    `json:"vector_dim"`
 	SessionCount      int    `json:"session_count"`
+
+	VectorMemory VectorMemory `json:"vector_memory"`
+}
+
+// VectorMemory reports the memory held by vector indices and the estimated
+// recall cost of quantization
+type VectorMemory struct {
+	Vectors            int     `json:"vectors"`
+	QuantizedVectors   int     `json:"quantized_vectors"`
+	Tombstones         int     `json:"tombstones"`           // deleted vectors awaiting compaction
+	Bytes              int64   `json:"bytes"`                // vectors, codes and codebooks
+	FullPrecisionBytes int64   `json:"full_precision_bytes"` // the same vectors as float32
+	Recall             float32 `json:"recall"`               // estimated recall@10, weighted by vectors
+}
+
+// RebuildTask reports the progress of an online vector index rebuild
+type RebuildTask struct {
+	ID        string  `json:"id"`
+	SessionID string  `json:"session_id"`
+	Status    string  `json:"status"` // "running", "complete" or "failed"
+	Index     string  `json:"index"`  // index being rebuilt
+	Progress  float64 `json:"progress"`
+	StartTime int64   `json:"start_time"` // unix timestamp in nanoseconds
+	EndTime   int64   `json:"end_time"`   // 0 while running
+	Error     string  `json:"error,omitempty"`
+}
+
+// Add merges other into m, weighting recall by vector count
+func (m *VectorMemory) Add(other VectorMemory) {
+	total := m.Vectors + other.Vectors
+	if total == 0 {
+		m.Recall = 1
+		return
+	}
+	m.Recall = (m.Recall*float32(m.Vectors) + other.Recall*float32(other.Vectors)) / float32(total)
+	m.Vectors = total
+	m.QuantizedVectors += other.QuantizedVectors
+	m.Tombstones += other.Tombstones
+	m.Bytes += other.Bytes
+	m.FullPrecisionBytes += other.FullPrecisionBytes
 }
 
 // =============================================================================
@@ -3,12 +3,16 @@ package vector
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 	"math"
 	"math/rand"
+	"runtime"
+	"slices"
 	"sort"
 	"sync"
+	"sync/atomic"
 
 	"github.com/gibram-io/gibram/pkg/simd"
 )
@@ -17,24 +21,65 @@ import (
 // Index Interface
 // =============================================================================
 
+// SearchResult is a search hit. Similarity is higher for closer vectors;
+// its range depends on the index Metric.
 type SearchResult struct {
 	ID         uint64
 	Similarity float32
 }
 
+// Filter reports whether a vector ID may appear in search results
+type Filter func(id uint64) bool
+
+// SearchParams tunes a single k-NN or range search
+type SearchParams struct {
+	K             int      // max results; 0 = no limit in range mode
+	Ef            int      // candidate list size (0 = index default)
+	MinSimilarity *float32 // drop results below this similarity (nil = none)
+	Range         bool     // return every vector at or above MinSimilarity
+	Filter        Filter   // nil = no filter
+}
+
+// ProgressFunc reports rebuild progress as vectors inserted out of total
+type ProgressFunc func(done, total int)
+
+// ErrRebuildInProgress is returned when a rebuild is already running
+var ErrRebuildInProgress = errors.New("index rebuild already in progress")
+
 type Index interface {
 	Add(id uint64, vector []float32) error
+	AddBatch(ids []uint64, vectors [][]float32, workers int, progress ProgressFunc) error
 	Remove(id uint64) bool
 	Search(query []float32, k int) []SearchResult
+	SearchFiltered(query []float32, k int, filter Filter) []SearchResult // nil filter = Search
+	SearchWithParams(query []float32, params SearchParams) []SearchResult
 	Count() int
 	Dimension() int
+	Metric() Metric
 	Save(w io.Writer) error
 	Load(r io.Reader) error
 
 	// Rebuild functionality
-	GetAllVectors() map[uint64][]float32 // Get raw vectors for rebuild
-	Rebuild() error                      // Rebuild index from scratch
-	ValidateIntegrity() error            // Check if index is corrupted
+	GetAllVectors() map[uint64][]float32                              // Get raw vectors for rebuild
+	Vector(id uint64) ([]float32, bool)                               // Copy of one stored vector
+	Rebuild() error                                                   // Rebuild index from scratch
+	RebuildWithConfig(config HNSWConfig, progress ProgressFunc) error // Rebuild online with new parameters
+	ValidateIntegrity() error                                         // Check if index is corrupted
+	Compact() int                                                     // Purge deleted vectors, returning how many
+
+	MemoryStats() MemoryStats                              // Vector memory usage and quantization recall
+	CheckRecall(params RecallParams) (RecallReport, error) // Approximate vs exact search self-check
+}
+
+// MemoryStats reports the memory held by an index's vectors
+type MemoryStats struct {
+	Vectors            int          // vectors in the index
+	Tombstones         int          // deleted vectors awaiting compaction
+	QuantizedVectors   int          // vectors stored as quantized codes
+	Bytes              int64        // bytes held by vectors, codes and codebook
+	FullPrecisionBytes int64        // bytes the same vectors take as float32
+	Quantization       Quantization // quantization in effect (none until trained)
+	Recall             float32      // estimated recall@10 of quantized scoring (1 = exact)
 }
 
 // =============================================================================
@@ -42,11 +87,26 @@ type Index interface {
 // =============================================================================
 
 type HNSWConfig struct {
-	M              int     // max connections per node
-	EfConstruction int     // size of dynamic candidate list during construction
-	EfSearch       int     // size of dynamic candidate list during search
-	MaxLevel       int     // max layer
-	ML             float64 // level multiplier (1/ln(M))
+	M              int     `json:"m"`               // max connections per node
+	EfConstruction int     `json:"ef_construction"` // size of dynamic candidate list during construction
+	EfSearch       int     `json:"ef_search"`       // size of dynamic candidate list during search
+	MaxLevel       int     `json:"max_level"`       // max layer
+	ML             float64 `json:"ml"`              // level multiplier (1/ln(M))
+	Metric         Metric  `json:"metric"`          // distance metric (default cosine)
+
+	// Quantization compresses stored vectors once QuantTrainSize vectors
+	// have been added; the quantizer is trained in the background. Full-
+	// precision vectors are dropped unless KeepFullVectors is set, in which
+	// case they rescore final candidates.
+	Quantization    Quantization `json:"quantization,omitempty"`
+	PQSubvectors    int          `json:"pq_subvectors,omitempty"`     // product quantization subvectors (0 = auto)
+	KeepFullVectors bool         `json:"keep_full_vectors,omitempty"` // keep float32 vectors for rescoring
+	QuantTrainSize  int          `json:"quant_train_size,omitempty"`  // vectors used to train (0 = DefaultQuantTrainSize)
+
+	// Removed vectors are tombstoned: skipped in results but still used
+	// for navigation until background compaction purges them, which starts
+	// once tombstones make up TombstoneRatio of the graph.
+	TombstoneRatio float64 `json:"tombstone_ratio,omitempty"` // 0 = DefaultTombstoneRatio
 }
 
 func DefaultHNSWConfig() HNSWConfig {
@@ -59,20 +119,98 @@ func DefaultHNSWConfig() HNSWConfig {
 	}
 }
 
+// HNSW parameter limits accepted by Validate
+const (
+	MaxHNSWM  = 128
+	MaxHNSWEf = 4096
+)
+
+// DefaultTombstoneRatio is the share of tombstoned nodes that starts compaction
+const DefaultTombstoneRatio = 0.2
+
+// compactBatchSize bounds the tombstones purged per write lock acquisition
+const compactBatchSize = 256
+
+// SetM sets the max connections per node and the matching level multiplier
+func (c *HNSWConfig) SetM(m int) {
+	c.M = m
+	if m > 1 {
+		c.ML = 1.0 / math.Log(float64(m))
+	}
+}
+
+// Validate checks that the configuration is usable
+func (c HNSWConfig) Validate() error {
+	if c.M < 2 || c.M > MaxHNSWM {
+		return fmt.Errorf("m must be between 2 and %d, got %d", MaxHNSWM, c.M)
+	}
+	if c.EfConstruction < 1 || c.EfConstruction > MaxHNSWEf {
+		return fmt.Errorf("ef_construction must be between 1 and %d, got %d", MaxHNSWEf, c.EfConstruction)
+	}
+	if c.EfSearch < 1 || c.EfSearch > MaxHNSWEf {
+		return fmt.Errorf("ef_search must be between 1 and %d, got %d", MaxHNSWEf, c.EfSearch)
+	}
+	if !c.Metric.Valid() {
+		return fmt.Errorf("invalid distance metric: %d", uint8(c.Metric))
+	}
+	if !c.Quantization.Valid() {
+		return fmt.Errorf("invalid quantization: %d", uint8(c.Quantization))
+	}
+	if c.PQSubvectors < 0 {
+		return fmt.Errorf("pq_subvectors must not be negative, got %d", c.PQSubvectors)
+	}
+	if c.QuantTrainSize < 0 {
+		return fmt.Errorf("quant_train_size must not be negative, got %d", c.QuantTrainSize)
+	}
+	if c.TombstoneRatio < 0 || c.TombstoneRatio > 1 {
+		return fmt.Errorf("tombstone_ratio must be between 0 and 1, got %g", c.TombstoneRatio)
+	}
+	return nil
+}
+
+// ValidateDimension checks the configuration against a vector dimension
+func (c HNSWConfig) ValidateDimension(dim int) error {
+	if c.Quantization == QuantizationProduct {
+		if _, err := PQSubvectors(dim, c.PQSubvectors); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 type hnswNode struct {
 	id      uint64
-	vector  []float32
+	vector  []float32 // nil once quantized without KeepFullVectors
+	code    []byte    // quantized code (nil until the quantizer is trained)
+	norm    float32   // squared norm of the decoded code
+	gen     uint64    // write generation, to detect writes during a rebuild
+	deleted bool      // tombstoned: kept for navigation, never returned
 	level   int
 	friends [][]uint64 // friends[level] = list of connected node IDs
+	mu      sync.Mutex // guards friends during a concurrent build
 }
 
 type HNSWIndex struct {
-	mu        sync.RWMutex
-	config    HNSWConfig
-	dimension int
-	nodes     map[uint64]*hnswNode
-	entryID   uint64
-	maxLevel  int
+	mu         sync.RWMutex
+	config     HNSWConfig
+	dimension  int
+	nodes      map[uint64]*hnswNode
+	entryID    uint64
+	maxLevel   int
+	quant      quantizer // nil until trained
+	recall     float32   // estimated recall@10 of quant
+	gen        uint64    // last write generation
+	tombstones int       // deleted nodes not yet purged
+	concurrent bool      // links are read under node locks (concurrent build)
+	training   bool      // a quantizer is being trained in the background
+	trainErr   error     // last background training failure (no retry)
+	deferTrain bool      // shadow index: the index it replaces trains instead
+
+	trainWG sync.WaitGroup // background training goroutines
+
+	rebuildMu sync.Mutex // held for the duration of a rebuild
+	compactMu sync.Mutex // held for the duration of a compaction
+	entryMu   sync.Mutex // guards the entry point during a concurrent build
 }
 
 func NewHNSWIndex(dimension int, config HNSWConfig) *HNSWIndex {
@@ -89,10 +227,49 @@ func (h *HNSWIndex) Dimension() int {
 	return h.dimension
 }
 
+// Metric returns the distance metric of the index
+func (h *HNSWIndex) Metric() Metric {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.config.Metric
+}
+
+// Config returns the index configuration
+func (h *HNSWIndex) Config() HNSWConfig {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.config
+}
+
 func (h *HNSWIndex) Count() int {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
-	return len(h.nodes)
+	return len(h.nodes) - h.tombstones
+}
+
+// MemoryStats reports vector memory usage. Graph links are not counted.
+func (h *HNSWIndex) MemoryStats() MemoryStats {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	stats := MemoryStats{
+		Vectors:            len(h.nodes) - h.tombstones,
+		Tombstones:         h.tombstones,
+		FullPrecisionBytes: int64(len(h.nodes)) * int64(h.dimension) * 4,
+		Recall:             1,
+	}
+	for _, node := range h.nodes {
+		stats.Bytes += int64(len(node.vector))*4 + int64(len(node.code))
+		if node.code != nil {
+			stats.QuantizedVectors++
+		}
+	}
+	if h.quant != nil {
+		stats.Quantization = h.quant.kind()
+		stats.Bytes += int64(h.quant.codebookSize())
+		stats.Recall = h.recall
+	}
+	return stats
 }
 
 // randomLevel generates a random level for a new node
@@ -110,6 +287,63 @@ func cosineSimilarity(a, b []float32) float32 {
 	return simd.CosineSimilarity(a, b)
 }
 
+// similarity compares two vectors using the index metric
+func (h *HNSWIndex) similarity(a, b []float32) float32 {
+	return h.config.Metric.Similarity(a, b)
+}
+
+// queryScorer scores stored nodes against one query. Quantized nodes are
+// scored asymmetrically from their codes; exact uses the full-precision
+// vector when one is kept.
+type queryScorer struct {
+	h       *HNSWIndex
+	query   []float32
+	qNorm   float32
+	dot     func(code []byte) float32
+	visited int // nodes scored for traversal
+}
+
+// scorer prepares scoring of nodes against query
+func (h *HNSWIndex) scorer(query []float32) *queryScorer {
+	s := &queryScorer{h: h, query: query}
+	if h.quant != nil {
+		s.qNorm = squaredNorm(query)
+		s.dot = h.quant.dotTable(query)
+	}
+	return s
+}
+
+// score returns the (possibly approximate) similarity used for traversal
+func (s *queryScorer) score(node *hnswNode) float32 {
+	s.visited++
+	return s.approx(node)
+}
+
+// approx scores node from its code when quantized
+func (s *queryScorer) approx(node *hnswNode) float32 {
+	if node.code != nil && s.dot != nil {
+		return s.h.config.Metric.fromDot(s.dot(node.code), s.qNorm, node.norm)
+	}
+	return s.h.similarity(s.query, node.vector)
+}
+
+// exact rescores node with its full-precision vector when available
+func (s *queryScorer) exact(node *hnswNode) float32 {
+	if node.vector != nil {
+		return s.h.similarity(s.query, node.vector)
+	}
+	return s.approx(node)
+}
+
+// nodeVector returns the full-precision vector of node, or its decoded
+// code when the full vector was dropped
+func (h *HNSWIndex) nodeVector(node *hnswNode) []float32 {
+	if node.vector != nil || node.code == nil {
+		return node.vector
+	}
+	return h.quant.decode(node.code)
+}
+
 // Add inserts a vector into the index
 func (h *HNSWIndex) Add(id uint64, vector []float32) error {
 	if len(vector) != h.dimension {
@@ -119,47 +353,70 @@ func (h *HNSWIndex) Add(id uint64, vector []float32) error {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
+	return h.addLocked(id, vector)
+}
+
+// addLocked inserts a vector, first purging a tombstone with the same id
+func (h *HNSWIndex) addLocked(id uint64, vector []float32) error {
 	// Check if already exists
-	if _, exists := h.nodes[id]; exists {
-		return fmt.Errorf("vector with id %d already exists", id)
+	if existing, exists := h.nodes[id]; exists {
+		if !existing.deleted {
+			return fmt.Errorf("vector with id %d already exists", id)
+		}
+		h.purgeLocked(id)
 	}
 
 	// Create new node
-	level := h.randomLevel()
 	node := &hnswNode{
-		id:      id,
-		vector:  make([]float32, len(vector)),
-		level:   level,
-		friends: make([][]uint64, level+1),
+		id:     id,
+		vector: make([]float32, len(vector)),
 	}
 	copy(node.vector, vector)
+	h.gen++
+	node.gen = h.gen
+	h.insertLocked(node, vector)
 
+	if h.quant != nil {
+		h.quantizeLocked(node)
+	} else if err := h.maybeTrainLocked(); err != nil {
+		return fmt.Errorf("train quantizer: %w", err)
+	}
+
+	return nil
+}
+
+// insertLocked links node into the graph at a random level
+func (h *HNSWIndex) insertLocked(node *hnswNode, vector []float32) {
+	level := h.randomLevel()
+	node.level = level
+	node.friends = make([][]uint64, level+1)
 	for i := range node.friends {
 		node.friends[i] = make([]uint64, 0, h.config.M)
 	}
 
 	// First node
 	if len(h.nodes) == 0 {
-		h.nodes[id] = node
-		h.entryID = id
+		h.nodes[node.id] = node
+		h.entryID = node.id
 		h.maxLevel = level
-		return nil
+		return
 	}
 
 	// Find entry point and search down
 	currID := h.entryID
+	scorer := h.scorer(vector)
 
 	// Traverse from top level to node's level + 1
 	for l := h.maxLevel; l > level; l-- {
-		currID = h.searchLayerClosest(vector, currID, l)
+		currID = h.searchLayerClosest(scorer, currID, l)
 	}
 
 	// Insert at each level from level to 0
 	for l := min(level, h.maxLevel); l >= 0; l-- {
-		neighbors := h.searchLayer(vector, currID, h.config.EfConstruction, l)
+		neighbors := h.searchLayer(scorer, currID, h.config.EfConstruction, l, nil)
 
 		// Select M best neighbors
-		selectedNeighbors := h.selectNeighbors(vector, neighbors, h.config.M)
+		selectedNeighbors := h.selectNeighbors(scorer, neighbors, h.config.M)
 
 		// Connect node to neighbors
 		node.friends[l] = selectedNeighbors
@@ -168,11 +425,11 @@ func (h *HNSWIndex) Add(id uint64, vector []float32) error {
 		for _, neighborID := range selectedNeighbors {
 			neighbor := h.nodes[neighborID]
 			if neighbor != nil && l < len(neighbor.friends) {
-				neighbor.friends[l] = append(neighbor.friends[l], id)
+				neighbor.friends[l] = append(neighbor.friends[l], node.id)
 
 				// Prune if too many connections
 				if len(neighbor.friends[l]) > h.config.M*2 {
-					neighbor.friends[l] = h.selectNeighbors(neighbor.vector, neighbor.friends[l], h.config.M)
+					neighbor.friends[l] = h.selectNeighbors(h.scorer(h.nodeVector(neighbor)), neighbor.friends[l], h.config.M)
 				}
 			}
 		}
@@ -182,21 +439,146 @@ func (h *HNSWIndex) Add(id uint64, vector []float32) error {
 		}
 	}
 
-	h.nodes[id] = node
+	h.nodes[node.id] = node
 
 	// Update entry point if necessary
 	if level > h.maxLevel {
-		h.entryID = id
+		h.entryID = node.id
 		h.maxLevel = level
 	}
+}
+
+// trainSize returns the number of vectors collected before training
+func (h *HNSWIndex) trainSize() int {
+	if h.config.QuantTrainSize > 0 {
+		return h.config.QuantTrainSize
+	}
+	return DefaultQuantTrainSize
+}
 
+// trainSample is a live node sampled for training, with the generation
+// it had when sampled
+type trainSample struct {
+	node   *hnswNode
+	gen    uint64
+	vector []float32
+}
+
+// maybeTrainLocked starts training the quantizer once the index holds
+// enough live vectors. Training runs in the background so searches and
+// writes continue; vectors added meanwhile are encoded when the quantizer
+// is installed. Shadow indices leave training to the index they replace.
+func (h *HNSWIndex) maybeTrainLocked() error {
+	if h.quant != nil || h.training || h.trainErr != nil || h.deferTrain ||
+		h.config.Quantization == QuantizationNone || len(h.nodes)-h.tombstones < h.trainSize() {
+		return nil
+	}
+	if h.config.Quantization == QuantizationProduct {
+		if _, err := PQSubvectors(h.dimension, h.config.PQSubvectors); err != nil {
+			return err
+		}
+	}
+
+	// Node vectors are never modified in place, so the sample shares them
+	samples := make([]trainSample, 0, h.trainSize())
+	for _, node := range h.nodes {
+		if len(samples) == h.trainSize() {
+			break
+		}
+		if !node.deleted {
+			samples = append(samples, trainSample{node: node, gen: node.gen, vector: node.vector})
+		}
+	}
+
+	h.training = true
+	h.trainWG.Add(1)
+	go h.train(h.config, samples)
 	return nil
 }
 
+// train fits a quantizer to samples without holding the index lock, then
+// installs it. The sampled vectors are encoded before the lock is taken;
+// only nodes added or changed since are encoded under it. Tombstoned nodes
+// are encoded for navigation but do not shape the codebook.
+func (h *HNSWIndex) train(config HNSWConfig, samples []trainSample) {
+	defer h.trainWG.Done()
+
+	vectors := make([][]float32, len(samples))
+	for i, sample := range samples {
+		vectors[i] = sample.vector
+	}
+
+	// Hold out a fraction of the vectors so the recall estimate is not
+	// flattered by codes fitted to the very vectors being compared
+	held := len(vectors) / 4
+	q, err := trainQuantizer(config.Quantization, h.dimension, config.PQSubvectors, vectors[held:])
+	var recall float32
+	codes := make([][]byte, len(samples))
+	if err == nil {
+		rescore := 0
+		if config.KeepFullVectors {
+			rescore = config.EfSearch
+		}
+		queries := min(held/2, recallSampleQueries)
+		recall = estimateRecall(config.Metric, q, vectors[:queries], vectors[queries:held], rescore)
+		for i, vec := range vectors {
+			codes[i] = q.encode(vec)
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.training = false
+	if err != nil {
+		h.trainErr = err
+		return
+	}
+	// A rebuild may have changed the settings or installed a quantizer
+	if h.quant != nil || h.config.Quantization != config.Quantization || h.config.PQSubvectors != config.PQSubvectors {
+		return
+	}
+	h.quant = q
+	h.recall = recall
+
+	encoded := make(map[*hnswNode]bool, len(samples))
+	for i, sample := range samples {
+		if h.nodes[sample.node.id] == sample.node && sample.node.gen == sample.gen {
+			h.installCodeLocked(sample.node, codes[i])
+			encoded[sample.node] = true
+		}
+	}
+	for _, node := range h.nodes {
+		if !encoded[node] {
+			h.quantizeLocked(node)
+		}
+	}
+}
+
+// waitTraining waits for background training to finish
+func (h *HNSWIndex) waitTraining() {
+	h.trainWG.Wait()
+}
+
+// quantizeLocked encodes node and drops its full vector unless kept
+func (h *HNSWIndex) quantizeLocked(node *hnswNode) {
+	h.installCodeLocked(node, h.quant.encode(h.nodeVector(node)))
+}
+
+// installCodeLocked sets the code of node and drops its full vector unless
+// kept
+func (h *HNSWIndex) installCodeLocked(node *hnswNode, code []byte) {
+	node.code = code
+	node.norm = squaredNorm(h.quant.decode(code))
+	if !h.config.KeepFullVectors {
+		node.vector = nil
+	}
+}
+
 // searchLayerClosest finds the closest node to query in a single layer
-func (h *HNSWIndex) searchLayerClosest(query []float32, entryID uint64, level int) uint64 {
+func (h *HNSWIndex) searchLayerClosest(scorer *queryScorer, entryID uint64, level int) uint64 {
 	currID := entryID
-	currDist := cosineSimilarity(query, h.nodes[currID].vector)
+	currDist := scorer.score(h.nodes[currID])
 
 	changed := true
 	for changed {
@@ -206,12 +588,12 @@ func (h *HNSWIndex) searchLayerClosest(query []float32, entryID uint64, level in
 			break
 		}
 
-		for _, friendID := range currNode.friends[level] {
+		for _, friendID := range h.friendsAt(currNode, level) {
 			friend := h.nodes[friendID]
 			if friend == nil {
 				continue
 			}
-			dist := cosineSimilarity(query, friend.vector)
+			dist := scorer.score(friend)
 			if dist > currDist {
 				currID = friendID
 				currDist = dist
@@ -223,22 +605,36 @@ func (h *HNSWIndex) searchLayerClosest(query []float32, entryID uint64, level in
 	return currID
 }
 
-// searchLayer finds ef closest nodes to query starting from entry
-func (h *HNSWIndex) searchLayer(query []float32, entryID uint64, ef int, level int) []uint64 {
+// friendsAt returns node's links at level. During a concurrent build the
+// links are copied under the node lock.
+func (h *HNSWIndex) friendsAt(node *hnswNode, level int) []uint64 {
+	if !h.concurrent {
+		return node.friends[level]
+	}
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	return append([]uint64(nil), node.friends[level]...)
+}
+
+// searchLayer finds ef closest nodes to query starting from entry. Nodes
+// rejected by filter are traversed but never returned.
+func (h *HNSWIndex) searchLayer(scorer *queryScorer, entryID uint64, ef int, level int, filter Filter) []uint64 {
 	visited := make(map[uint64]bool)
-	candidates := &priorityQueue{}
-	result := &priorityQueue{}
+	candidates := &priorityQueue{} // best first
+	result := &priorityQueue{}     // worst first: priorities are negated
 
 	entry := h.nodes[entryID]
 	if entry == nil {
 		return nil
 	}
 
-	dist := cosineSimilarity(query, entry.vector)
+	dist := scorer.score(entry)
 	visited[entryID] = true
 
 	candidates.Push(pqItem{id: entryID, priority: dist})
-	result.Push(pqItem{id: entryID, priority: dist})
+	if filter == nil || filter(entryID) {
+		result.Push(pqItem{id: entryID, priority: -dist})
+	}
 
 	for candidates.Len() > 0 {
 		curr := candidates.Pop()
@@ -247,17 +643,14 @@ func (h *HNSWIndex) searchLayer(query []float32, entryID uint64, ef int, level i
 			continue
 		}
 
-		// Get worst result
-		worst := result.Peek()
-
-		// If current is farther than worst result and we have enough, stop
-		if curr.priority < worst.priority && result.Len() >= ef {
+		// If current is farther than the worst result and we have enough, stop
+		if result.Len() >= ef && curr.priority < -result.Peek().priority {
 			break
 		}
 
 		// Explore neighbors
 		if level < len(currNode.friends) {
-			for _, neighborID := range currNode.friends[level] {
+			for _, neighborID := range h.friendsAt(currNode, level) {
 				if visited[neighborID] {
 					continue
 				}
@@ -268,33 +661,33 @@ func (h *HNSWIndex) searchLayer(query []float32, entryID uint64, ef int, level i
 					continue
 				}
 
-				neighborDist := cosineSimilarity(query, neighbor.vector)
-				worst = result.Peek()
-
-				if result.Len() < ef || neighborDist > worst.priority {
+				neighborDist := scorer.score(neighbor)
+				if result.Len() < ef || neighborDist > -result.Peek().priority {
 					candidates.Push(pqItem{id: neighborID, priority: neighborDist})
-					result.Push(pqItem{id: neighborID, priority: neighborDist})
+					if filter != nil && !filter(neighborID) {
+						continue
+					}
+					result.Push(pqItem{id: neighborID, priority: -neighborDist})
 
 					if result.Len() > ef {
-						result.PopWorst()
+						result.Pop()
 					}
 				}
 			}
 		}
 	}
 
-	// Extract IDs from result
-	ids := make([]uint64, 0, result.Len())
-	for result.Len() > 0 {
-		item := result.Pop()
-		ids = append(ids, item.id)
+	// Extract IDs from result, best first
+	ids := make([]uint64, result.Len())
+	for i := len(ids) - 1; i >= 0; i-- {
+		ids[i] = result.Pop().id
 	}
 
 	return ids
 }
 
 // selectNeighbors selects the M best neighbors
-func (h *HNSWIndex) selectNeighbors(query []float32, candidates []uint64, M int) []uint64 {
+func (h *HNSWIndex) selectNeighbors(scorer *queryScorer, candidates []uint64, M int) []uint64 {
 	if len(candidates) <= M {
 		return candidates
 	}
@@ -308,7 +701,7 @@ func (h *HNSWIndex) selectNeighbors(query []float32, candidates []uint64, M int)
 	for _, id := range candidates {
 		node := h.nodes[id]
 		if node != nil {
-			scoredCandidates = append(scoredCandidates, scored{id: id, score: cosineSimilarity(query, node.vector)})
+			scoredCandidates = append(scoredCandidates, scored{id: id, score: scorer.score(node)})
 		}
 	}
 
@@ -324,8 +717,31 @@ func (h *HNSWIndex) selectNeighbors(query []float32, candidates []uint64, M int)
 	return result
 }
 
+// Filtered search tuning
+const (
+	filterSampleSize            = 64   // nodes sampled to estimate filter selectivity
+	filterBruteForceSelectivity = 0.02 // below this, scan matching nodes directly
+)
+
 // Search finds the k most similar vectors to query
 func (h *HNSWIndex) Search(query []float32, k int) []SearchResult {
+	return h.SearchFiltered(query, k, nil)
+}
+
+// SearchFiltered finds the k most similar vectors whose IDs pass filter.
+// The filter is applied during graph traversal, with ef widened by the
+// estimated selectivity so selective filters still fill k results. Very
+// selective filters fall back to an exact scan.
+func (h *HNSWIndex) SearchFiltered(query []float32, k int, filter Filter) []SearchResult {
+	return h.SearchWithParams(query, SearchParams{K: k, Filter: filter})
+}
+
+// SearchWithParams runs a k-NN search with an optional ef override and
+// similarity threshold, or a range search returning every vector at or
+// above the threshold. Range search widens ef until the candidate list
+// reaches below the threshold, falling back to an exact scan once ef
+// covers the whole index.
+func (h *HNSWIndex) SearchWithParams(query []float32, params SearchParams) []SearchResult {
 	if len(query) != h.dimension {
 		return nil
 	}
@@ -336,19 +752,127 @@ func (h *HNSWIndex) Search(query []float32, k int) []SearchResult {
 	if len(h.nodes) == 0 {
 		return nil
 	}
+	ef := params.Ef
+	if ef <= 0 {
+		ef = h.config.EfSearch
+	}
+	scorer := h.scorer(query)
+	if !params.Range || params.MinSimilarity == nil {
+		return aboveThreshold(h.searchLocked(scorer, params.K, max(ef, params.K), params.Filter), params.MinSimilarity)
+	}
+
+	live := len(h.nodes) - h.tombstones
+	limit := params.K
+	if limit <= 0 || limit > live {
+		limit = live
+	}
+	for {
+		var results []SearchResult
+		if ef >= live {
+			results = h.scanFiltered(scorer, live, h.liveFilter(params.Filter))
+		} else {
+			results = h.searchLocked(scorer, ef, ef, params.Filter)
+		}
+		if ef >= live || len(results) < ef || results[len(results)-1].Similarity < *params.MinSimilarity {
+			results = aboveThreshold(results, params.MinSimilarity)
+			if len(results) > limit {
+				results = results[:limit]
+			}
+			return results
+		}
+		ef = min(ef*2, live)
+	}
+}
+
+// aboveThreshold truncates results sorted by similarity at threshold (nil = none)
+func aboveThreshold(results []SearchResult, threshold *float32) []SearchResult {
+	if threshold == nil {
+		return results
+	}
+	for i, r := range results {
+		if r.Similarity < *threshold {
+			return results[:i]
+		}
+	}
+	return results
+}
+
+// searchLocked runs a filtered k-NN search with candidate list size ef;
+// caller holds the read lock
+func (h *HNSWIndex) searchLocked(scorer *queryScorer, k, ef int, filter Filter) []SearchResult {
+	filter = h.liveFilter(filter)
+	if filter == nil {
+		return h.search(scorer, k, ef, nil)
+	}
+
+	selectivity := h.estimateSelectivity(filter)
+	if selectivity < filterBruteForceSelectivity {
+		return h.scanFiltered(scorer, k, filter)
+	}
+	return h.search(scorer, k, min(int(float64(ef)/selectivity), len(h.nodes)), filter)
+}
+
+// liveFilter extends filter to reject tombstoned nodes; it returns nil when
+// nothing needs filtering. The result is only valid under the read lock.
+func (h *HNSWIndex) liveFilter(filter Filter) Filter {
+	if h.tombstones == 0 {
+		return filter
+	}
+	return func(id uint64) bool {
+		node := h.nodes[id]
+		return node != nil && !node.deleted && (filter == nil || filter(id))
+	}
+}
 
+// estimateSelectivity returns the fraction of sampled nodes passing filter
+func (h *HNSWIndex) estimateSelectivity(filter Filter) float64 {
+	sampled, matched := 0, 0
+	for id := range h.nodes {
+		if sampled == filterSampleSize {
+			break
+		}
+		sampled++
+		if filter(id) {
+			matched++
+		}
+	}
+	if sampled == 0 {
+		return 1
+	}
+	return float64(matched) / float64(sampled)
+}
+
+// scanFiltered scores every node passing filter (nil = every node)
+func (h *HNSWIndex) scanFiltered(scorer *queryScorer, k int, filter Filter) []SearchResult {
+	results := make([]SearchResult, 0)
+	for id, node := range h.nodes {
+		if filter == nil || filter(id) {
+			results = append(results, SearchResult{ID: id, Similarity: scorer.exact(node)})
+		}
+	}
+	scorer.visited += len(results)
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Similarity > results[j].Similarity
+	})
+	if len(results) > k {
+		results = results[:k]
+	}
+	return results
+}
+
+// search runs the layered HNSW search; caller holds the read lock
+func (h *HNSWIndex) search(scorer *queryScorer, k, ef int, filter Filter) []SearchResult {
 	// Start from entry point and traverse down
 	currID := h.entryID
 
 	for l := h.maxLevel; l > 0; l-- {
-		currID = h.searchLayerClosest(query, currID, l)
+		currID = h.searchLayerClosest(scorer, currID, l)
 	}
 
 	// Search at level 0 with ef neighbors
-	ef := max(h.config.EfSearch, k)
-	neighborIDs := h.searchLayer(query, currID, ef, 0)
+	neighborIDs := h.searchLayer(scorer, currID, ef, 0, filter)
 
-	// Score all neighbors
+	// Score all neighbors, rescoring with full vectors when kept
 	type scored struct {
 		id    uint64
 		score float32
@@ -357,7 +881,7 @@ func (h *HNSWIndex) Search(query []float32, k int) []SearchResult {
 	for _, id := range neighborIDs {
 		node := h.nodes[id]
 		if node != nil {
-			scoredNeighbors = append(scoredNeighbors, scored{id: id, score: cosineSimilarity(query, node.vector)})
+			scoredNeighbors = append(scoredNeighbors, scored{id: id, score: scorer.exact(node)})
 		}
 	}
 
@@ -378,15 +902,109 @@ func (h *HNSWIndex) Search(query []float32, k int) []SearchResult {
 	return results
 }
 
-// Remove deletes a vector from the index with proper neighbor reconnection
+// Remove tombstones a vector. It disappears from results at once but keeps
+// routing searches until compaction purges it, which is started in the
+// background once the configured tombstone ratio is reached.
 func (h *HNSWIndex) Remove(id uint64) bool {
 	h.mu.Lock()
-	defer h.mu.Unlock()
-
 	node, exists := h.nodes[id]
-	if !exists {
+	if !exists || node.deleted {
+		h.mu.Unlock()
 		return false
 	}
+	node.deleted = true
+	h.tombstones++
+	h.gen++
+	node.gen = h.gen
+	compact := h.needsCompactionLocked()
+	h.mu.Unlock()
+
+	if compact && h.compactMu.TryLock() {
+		go func() {
+			defer h.compactMu.Unlock()
+			h.compact()
+		}()
+	}
+	return true
+}
+
+// needsCompactionLocked reports whether tombstones reached the configured ratio
+func (h *HNSWIndex) needsCompactionLocked() bool {
+	ratio := h.config.TombstoneRatio
+	if ratio == 0 {
+		ratio = DefaultTombstoneRatio
+	}
+	return h.tombstones > 0 && float64(h.tombstones) >= ratio*float64(len(h.nodes))
+}
+
+// Compact purges all tombstoned vectors, rewiring their neighbors, and
+// returns how many were purged
+func (h *HNSWIndex) Compact() int {
+	h.compactMu.Lock()
+	defer h.compactMu.Unlock()
+	return h.compact()
+}
+
+// compact purges tombstones in batches so searches and writes interleave
+// with a long compaction; caller holds compactMu. Tombstones are collected
+// with one scan under the read lock, and tombstones added while a scan's
+// batches are purged are picked up by the next scan.
+func (h *HNSWIndex) compact() int {
+	purged := 0
+	for {
+		h.mu.RLock()
+		var tombstones []uint64
+		for id, node := range h.nodes {
+			if node.deleted {
+				tombstones = append(tombstones, id)
+			}
+		}
+		h.mu.RUnlock()
+		if len(tombstones) == 0 {
+			return purged
+		}
+
+		for start := 0; start < len(tombstones); start += compactBatchSize {
+			batch := tombstones[start:min(start+compactBatchSize, len(tombstones))]
+			h.mu.Lock()
+			entryPurged := false
+			for _, id := range batch {
+				// Skip ids purged or re-added since the scan
+				if node, ok := h.nodes[id]; ok && node.deleted {
+					entryPurged = entryPurged || id == h.entryID
+					h.unlinkLocked(id)
+					purged++
+				}
+			}
+			if entryPurged {
+				h.resetEntryLocked()
+			}
+			if h.tombstones == 0 {
+				h.dropDanglingLinksLocked()
+			}
+			h.mu.Unlock()
+		}
+	}
+}
+
+// purgeLocked deletes a node with proper neighbor reconnection
+func (h *HNSWIndex) purgeLocked(id uint64) {
+	if _, exists := h.nodes[id]; !exists {
+		return
+	}
+	h.unlinkLocked(id)
+	if h.entryID == id {
+		h.resetEntryLocked()
+	}
+}
+
+// unlinkLocked deletes an existing node and reconnects its neighbors; the
+// caller replaces the entry point if it was the deleted node
+func (h *HNSWIndex) unlinkLocked(id uint64) {
+	node := h.nodes[id]
+	if node.deleted {
+		h.tombstones--
+	}
 
 	// For each level, remove connections and reconnect orphaned neighbors
 	for level, friends := range node.friends {
@@ -416,28 +1034,20 @@ func (h *HNSWIndex) Remove(id uint64) bool {
 	}
 
 	delete(h.nodes, id)
+}
 
-	// Update entry point if necessary
-	if h.entryID == id {
-		if len(h.nodes) == 0 {
-			h.entryID = 0
-			h.maxLevel = -1
-		} else {
-			// Find new entry point (node with highest level)
-			var newEntry uint64
-			newMaxLevel := -1
-			for nid, n := range h.nodes {
-				if n.level > newMaxLevel {
-					newMaxLevel = n.level
-					newEntry = nid
-				}
-			}
-			h.entryID = newEntry
-			h.maxLevel = newMaxLevel
+// resetEntryLocked makes the node with the highest level the entry point
+func (h *HNSWIndex) resetEntryLocked() {
+	var newEntry uint64
+	newMaxLevel := -1
+	for nid, n := range h.nodes {
+		if n.level > newMaxLevel {
+			newMaxLevel = n.level
+			newEntry = nid
 		}
 	}
-
-	return true
+	h.entryID = newEntry
+	h.maxLevel = newMaxLevel
 }
 
 // reconnectNeighbors ensures affected neighbors maintain connectivity after node removal
@@ -486,7 +1096,7 @@ func (h *HNSWIndex) reconnectNeighbors(level int, affected map[uint64]bool, dele
 			}
 
 			// Select best candidates based on similarity
-			selected := h.selectNeighborsForReconnect(neighbor.vector, candidates, maxFriends-currentFriendCount)
+			selected := h.selectNeighborsForReconnect(h.scorer(h.nodeVector(neighbor)), candidates, maxFriends-currentFriendCount)
 
 			// Add bidirectional connections
 			for _, selectedID := range selected {
@@ -514,7 +1124,7 @@ func (h *HNSWIndex) reconnectNeighbors(level int, affected map[uint64]bool, dele
 }
 
 // selectNeighborsForReconnect selects best neighbors during reconnection (no pruning, just selection)
-func (h *HNSWIndex) selectNeighborsForReconnect(query []float32, candidates []uint64, maxCount int) []uint64 {
+func (h *HNSWIndex) selectNeighborsForReconnect(scorer *queryScorer, candidates []uint64, maxCount int) []uint64 {
 	if len(candidates) == 0 {
 		return nil
 	}
@@ -531,7 +1141,7 @@ func (h *HNSWIndex) selectNeighborsForReconnect(query []float32, candidates []ui
 	for _, id := range candidates {
 		node := h.nodes[id]
 		if node != nil {
-			scoredCandidates = append(scoredCandidates, scored{id: id, score: cosineSimilarity(query, node.vector)})
+			scoredCandidates = append(scoredCandidates, scored{id: id, score: scorer.score(node)})
 		}
 	}
 
@@ -547,30 +1157,96 @@ func (h *HNSWIndex) selectNeighborsForReconnect(query []float32, candidates []ui
 	return result
 }
 
-// Save serializes the index to a writer
+// Serialization magic numbers. Indices saved before the header carried a
+// metric start directly with the (small, positive) dimension and load as
+// cosine. Quantized HNSW indices are saved with their quantizer and codes.
+const (
+	hnswMagic       uint32 = 0x32534E48 // "HNS2"
+	hnswQuantMagic  uint32 = 0x33534E48 // "HNS3"
+	bruteForceMagic uint32 = 0x32465242 // "BRF2"
+)
+
+// maxCodebookSize bounds the codebook read from a saved index
+const maxCodebookSize = 1 << 28
+
+// savedQuantizer is the quantizer section of a saved quantized index
+type savedQuantizer struct {
+	Kind         uint8
+	PQSubvectors int32
+	KeepFull     uint8
+	Recall       float32
+	CodebookSize int32
+}
+
+// Save serializes the index to a writer. A quantized index is written with
+// its quantizer settings, codebook and codes, plus the full vectors it
+// keeps, so it loads quantized.
 func (h *HNSWIndex) Save(w io.Writer) error {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
+	// Tombstones are not persisted; a tombstoned entry point is replaced
+	// by the highest live node
+	entryID, maxLevel := h.entryID, h.maxLevel
+	if entry := h.nodes[entryID]; entry != nil && entry.deleted {
+		maxLevel = -1
+		for id, node := range h.nodes {
+			if !node.deleted && node.level > maxLevel {
+				entryID, maxLevel = id, node.level
+			}
+		}
+	}
+
 	// Write header
 	header := struct {
+		Magic     uint32
+		Metric    uint8
 		Dimension int32
 		Count     int32
 		EntryID   uint64
 		MaxLevel  int32
 	}{
+		Magic:     hnswMagic,
+		Metric:    uint8(h.config.Metric),
 		Dimension: int32(h.dimension),
-		Count:     int32(len(h.nodes)),
-		EntryID:   h.entryID,
-		MaxLevel:  int32(h.maxLevel),
+		Count:     int32(len(h.nodes) - h.tombstones),
+		EntryID:   entryID,
+		MaxLevel:  int32(maxLevel),
 	}
 
+	if h.quant != nil {
+		header.Magic = hnswQuantMagic
+	}
 	if err := binary.Write(w, binary.LittleEndian, &header); err != nil {
 		return err
 	}
 
+	// Write the quantizer
+	if h.quant != nil {
+		codebook := h.quant.marshal()
+		saved := savedQuantizer{
+			Kind:         uint8(h.quant.kind()),
+			PQSubvectors: int32(h.config.PQSubvectors),
+			Recall:       h.recall,
+			CodebookSize: int32(len(codebook)),
+		}
+		if h.config.KeepFullVectors {
+			saved.KeepFull = 1
+		}
+		if err := binary.Write(w, binary.LittleEndian, &saved); err != nil {
+			return err
+		}
+		if _, err := w.Write(codebook); err != nil {
+			return err
+		}
+	}
+
 	// Write each node
 	for _, node := range h.nodes {
+		if node.deleted {
+			continue
+		}
+
 		// Write node header
 		nodeHeader := struct {
 			ID    uint64
@@ -583,18 +1259,24 @@ func (h *HNSWIndex) Save(w io.Writer) error {
 			return err
 		}
 
-		// Write vector
-		if err := binary.Write(w, binary.LittleEndian, node.vector); err != nil {
+		// Write vector; a quantized index writes whether the full vector
+		// follows, then the code
+		if h.quant != nil {
+			if err := h.writeQuantizedNode(w, node); err != nil {
+				return err
+			}
+		} else if err := binary.Write(w, binary.LittleEndian, node.vector); err != nil {
 			return err
 		}
 
 		// Write friends for each level
 		for l := 0; l <= node.level; l++ {
-			friendCount := int32(len(node.friends[l]))
+			friends := h.liveFriends(node.friends[l])
+			friendCount := int32(len(friends))
 			if err := binary.Write(w, binary.LittleEndian, friendCount); err != nil {
 				return err
 			}
-			if err := binary.Write(w, binary.LittleEndian, node.friends[l]); err != nil {
+			if err := binary.Write(w, binary.LittleEndian, friends); err != nil {
 				return err
 			}
 		}
@@ -603,20 +1285,73 @@ func (h *HNSWIndex) Save(w io.Writer) error {
 	return nil
 }
 
+// writeQuantizedNode writes the full vector flag, the full vector if kept
+// and the code of node
+func (h *HNSWIndex) writeQuantizedNode(w io.Writer, node *hnswNode) error {
+	hasVector := uint8(0)
+	if node.vector != nil {
+		hasVector = 1
+	}
+	if err := binary.Write(w, binary.LittleEndian, hasVector); err != nil {
+		return err
+	}
+	if node.vector != nil {
+		if err := binary.Write(w, binary.LittleEndian, node.vector); err != nil {
+			return err
+		}
+	}
+	code := node.code
+	if code == nil {
+		code = h.quant.encode(node.vector)
+	}
+	_, err := w.Write(code)
+	return err
+}
+
+// liveFriends returns friends without tombstoned nodes
+func (h *HNSWIndex) liveFriends(friends []uint64) []uint64 {
+	if h.tombstones == 0 {
+		return friends
+	}
+	live := make([]uint64, 0, len(friends))
+	for _, id := range friends {
+		if node := h.nodes[id]; node != nil && !node.deleted {
+			live = append(live, id)
+		}
+	}
+	return live
+}
+
 // Load deserializes the index from a reader
 func (h *HNSWIndex) Load(r io.Reader) error {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
 	// Read and validate header
+	magic, metric, lead, err := readFormatHeader(r, hnswMagic, hnswQuantMagic)
+	if err != nil {
+		return fmt.Errorf("failed to read header: %w", err)
+	}
+
 	var header struct {
 		Dimension int32
 		Count     int32
 		EntryID   uint64
 		MaxLevel  int32
 	}
-
-	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+	if lead != nil {
+		header.Dimension = *lead
+		err = binary.Read(r, binary.LittleEndian, &header.Count)
+		if err == nil {
+			err = binary.Read(r, binary.LittleEndian, &header.EntryID)
+		}
+		if err == nil {
+			err = binary.Read(r, binary.LittleEndian, &header.MaxLevel)
+		}
+	} else {
+		err = binary.Read(r, binary.LittleEndian, &header)
+	}
+	if err != nil {
 		return fmt.Errorf("failed to read header: %w", err)
 	}
 
@@ -631,10 +1366,21 @@ func (h *HNSWIndex) Load(r io.Reader) error {
 		return fmt.Errorf("invalid max level in header: %d", header.MaxLevel)
 	}
 
+	h.config.Metric = metric
 	h.dimension = int(header.Dimension)
 	h.entryID = header.EntryID
 	h.maxLevel = int(header.MaxLevel)
 	h.nodes = make(map[uint64]*hnswNode, header.Count)
+	h.tombstones = 0
+	h.quant = nil
+	h.recall = 0
+
+	// Read the quantizer
+	if magic == hnswQuantMagic {
+		if err := h.readQuantizer(r); err != nil {
+			return fmt.Errorf("failed to read quantizer: %w", err)
+		}
+	}
 
 	// Read each node
 	for i := 0; i < int(header.Count); i++ {
@@ -654,13 +1400,19 @@ func (h *HNSWIndex) Load(r io.Reader) error {
 		node := &hnswNode{
 			id:      nodeHeader.ID,
 			level:   int(nodeHeader.Level),
-			vector:  make([]float32, h.dimension),
 			friends: make([][]uint64, nodeHeader.Level+1),
 		}
 
-		// Read vector
-		if err := binary.Read(r, binary.LittleEndian, node.vector); err != nil {
-			return fmt.Errorf("failed to read node %d vector: %w", i, err)
+		// Read vector, or the vector flag and code of a quantized index
+		if h.quant != nil {
+			if err := h.readQuantizedNode(r, node); err != nil {
+				return fmt.Errorf("failed to read node %d vector: %w", i, err)
+			}
+		} else {
+			node.vector = make([]float32, h.dimension)
+			if err := binary.Read(r, binary.LittleEndian, node.vector); err != nil {
+				return fmt.Errorf("failed to read node %d vector: %w", i, err)
+			}
 		}
 
 		// Read friends for each level
@@ -689,15 +1441,87 @@ func (h *HNSWIndex) Load(r io.Reader) error {
 	return nil
 }
 
+// readQuantizer reads the quantizer section of a saved quantized index and
+// installs it with its settings
+func (h *HNSWIndex) readQuantizer(r io.Reader) error {
+	var saved savedQuantizer
+	if err := binary.Read(r, binary.LittleEndian, &saved); err != nil {
+		return err
+	}
+	kind := Quantization(saved.Kind)
+	if !kind.Valid() || kind == QuantizationNone {
+		return fmt.Errorf("invalid quantization: %d", saved.Kind)
+	}
+	if saved.CodebookSize < 0 || saved.CodebookSize > maxCodebookSize {
+		return fmt.Errorf("invalid codebook size: %d", saved.CodebookSize)
+	}
+	codebook := make([]byte, saved.CodebookSize)
+	if _, err := io.ReadFull(r, codebook); err != nil {
+		return err
+	}
+	q, err := unmarshalQuantizer(kind, h.dimension, codebook)
+	if err != nil {
+		return err
+	}
+
+	h.quant = q
+	h.recall = saved.Recall
+	h.config.Quantization = kind
+	h.config.PQSubvectors = int(saved.PQSubvectors)
+	h.config.KeepFullVectors = saved.KeepFull != 0
+	return nil
+}
+
+// readQuantizedNode reads the full vector flag, the full vector if kept
+// and the code of node
+func (h *HNSWIndex) readQuantizedNode(r io.Reader, node *hnswNode) error {
+	var hasVector uint8
+	if err := binary.Read(r, binary.LittleEndian, &hasVector); err != nil {
+		return err
+	}
+	if hasVector != 0 {
+		node.vector = make([]float32, h.dimension)
+		if err := binary.Read(r, binary.LittleEndian, node.vector); err != nil {
+			return err
+		}
+	}
+	node.code = make([]byte, h.quant.codeSize())
+	if _, err := io.ReadFull(r, node.code); err != nil {
+		return err
+	}
+	node.norm = squaredNorm(h.quant.decode(node.code))
+	return nil
+}
+
+// Vector returns a copy of the vector stored for id, decoded from its
+// code when only the quantized form is kept
+func (h *HNSWIndex) Vector(id uint64) ([]float32, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	node, ok := h.nodes[id]
+	if !ok || node.deleted {
+		return nil, false
+	}
+	vec := h.nodeVector(node)
+	copied := make([]float32, len(vec))
+	copy(copied, vec)
+	return copied, true
+}
+
 // GetAllVectors returns all vectors in the index (for rebuild)
 func (h *HNSWIndex) GetAllVectors() map[uint64][]float32 {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
-	result := make(map[uint64][]float32, len(h.nodes))
+	result := make(map[uint64][]float32, len(h.nodes)-h.tombstones)
 	for id, node := range h.nodes {
-		copied := make([]float32, len(node.vector))
-		copy(copied, node.vector)
+		if node.deleted {
+			continue
+		}
+		vec := h.nodeVector(node)
+		copied := make([]float32, len(vec))
+		copy(copied, vec)
 		result[id] = copied
 	}
 	return result
@@ -706,110 +1530,406 @@ func (h *HNSWIndex) GetAllVectors() map[uint64][]float32 {
 // Rebuild rebuilds the HNSW graph from scratch using existing vectors
 // This is useful when the graph structure becomes corrupted after many deletions
 func (h *HNSWIndex) Rebuild() error {
+	return h.RebuildWithConfig(h.Config(), nil)
+}
+
+// rebuildCatchUpPasses bounds how often writes made during a rebuild are
+// replayed without the write lock before the final replay and swap
+const rebuildCatchUpPasses = 3
+
+// RebuildWithConfig builds a new graph with config on a shadow index while
+// searches and writes continue on the current one. Writes made during the
+// build are replayed onto the shadow, which is then swapped in under the
+// write lock. A quantizer is reused when the quantization settings are
+// unchanged and retrained otherwise.
+func (h *HNSWIndex) RebuildWithConfig(config HNSWConfig, progress ProgressFunc) error {
+	return h.rebuild(config, nil, 1, progress)
+}
+
+// rebuild builds a shadow index with config from the current vectors plus
+// extra and swaps it in; workers > 1 inserts concurrently
+func (h *HNSWIndex) rebuild(config HNSWConfig, extra []rebuildOp, workers int, progress ProgressFunc) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	if err := config.ValidateDimension(h.dimension); err != nil {
+		return err
+	}
+	if !h.rebuildMu.TryLock() {
+		return ErrRebuildInProgress
+	}
+	defer h.rebuildMu.Unlock()
+
+	// Copy the vectors and remember which version of each node was copied
+	h.mu.RLock()
+	seen := make(map[uint64]uint64, len(h.nodes))
+	pending := make([]rebuildOp, 0, len(h.nodes)+len(extra))
+	for id, node := range h.nodes {
+		if node.deleted {
+			continue
+		}
+		seen[id] = node.gen
+		pending = append(pending, rebuildOp{id: id, vector: copyVector(h.nodeVector(node))})
+	}
+	batch := make(map[uint64]bool, len(extra))
+	for _, op := range extra {
+		if _, ok := seen[op.id]; ok {
+			h.mu.RUnlock()
+			return fmt.Errorf("vector with id %d already exists", op.id)
+		}
+		batch[op.id] = true
+	}
+	pending = append(pending, extra...)
+	shadow := NewHNSWIndex(h.dimension, config)
+	shadow.deferTrain = true
+	if h.quant != nil && config.Quantization == h.config.Quantization && config.PQSubvectors == h.config.PQSubvectors {
+		shadow.quant = h.quant
+		shadow.recall = h.recall
+	}
+	h.mu.RUnlock()
+
+	if workers > 1 {
+		if err := shadow.buildConcurrent(pending, workers, progress); err != nil {
+			return err
+		}
+	} else {
+		for i, op := range pending {
+			if err := shadow.Add(op.id, op.vector); err != nil {
+				return err
+			}
+			if progress != nil {
+				progress(i+1, len(pending))
+			}
+		}
+	}
+
+	// Replay writes made during the build without blocking searches
+	for pass := 0; pass < rebuildCatchUpPasses; pass++ {
+		h.mu.RLock()
+		ops := h.changesSince(seen)
+		h.mu.RUnlock()
+		if len(ops) == 0 {
+			break
+		}
+		if err := addedDuring(ops, batch); err != nil {
+			return err
+		}
+		if err := shadow.apply(ops); err != nil {
+			return err
+		}
+	}
+
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
-	if len(h.nodes) == 0 {
-		return nil
+	ops := h.changesSince(seen)
+	if err := addedDuring(ops, batch); err != nil {
+		return err
+	}
+	if err := shadow.apply(ops); err != nil {
+		return err
+	}
+	if err := shadow.validateIntegrityLocked(); err != nil {
+		return fmt.Errorf("rebuild validation failed: %w", err)
 	}
 
-	// Extract all vectors (snapshot for transaction safety)
-	vectors := make(map[uint64][]float32, len(h.nodes))
-	for id, node := range h.nodes {
-		// Deep copy vector to prevent external modification during rebuild
-		vectorCopy := make([]float32, len(node.vector))
-		copy(vectorCopy, node.vector)
-		vectors[id] = vectorCopy
+	// A background training may have finished during the build
+	if shadow.quant == nil && h.quant != nil && config.Quantization == h.config.Quantization && config.PQSubvectors == h.config.PQSubvectors {
+		shadow.quant = h.quant
+		shadow.recall = h.recall
+		for _, node := range shadow.nodes {
+			shadow.quantizeLocked(node)
+		}
 	}
 
-	// Create backup of current graph state for rollback
-	backup := &struct {
-		nodes    map[uint64]*hnswNode
-		entryID  uint64
-		maxLevel int
-	}{
-		nodes:    h.nodes,
-		entryID:  h.entryID,
-		maxLevel: h.maxLevel,
+	h.config = config
+	h.nodes = shadow.nodes
+	h.entryID = shadow.entryID
+	h.maxLevel = shadow.maxLevel
+	h.quant = shadow.quant
+	h.recall = shadow.recall
+	h.tombstones = shadow.tombstones
+	h.gen = max(h.gen, shadow.gen)
+	if h.quant == nil {
+		h.trainErr = nil
+		return h.maybeTrainLocked()
+	}
+	return nil
+}
+
+// AddBatch inserts many vectors at once. A batch that is large next to the
+// index is built with workers goroutines (<= 0 = GOMAXPROCS) on a shadow
+// index that is swapped in like a rebuild; searches continue meanwhile.
+// Smaller batches are inserted one by one. The batch is rejected as a whole
+// if any vector is malformed or its id is already present, including an id
+// added by a concurrent write while the shadow index was built.
+func (h *HNSWIndex) AddBatch(ids []uint64, vectors [][]float32, workers int, progress ProgressFunc) error {
+	ops, err := batchOps(ids, vectors, h.dimension)
+	if err != nil {
+		return err
+	}
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	h.mu.Lock()
+	if len(ops) < (len(h.nodes)-h.tombstones)/4 || len(ops) < minConcurrentBatch {
+		defer h.mu.Unlock()
+		for _, op := range ops {
+			if node, ok := h.nodes[op.id]; ok && !node.deleted {
+				return fmt.Errorf("vector with id %d already exists", op.id)
+			}
+		}
+		for i, op := range ops {
+			if err := h.addLocked(op.id, op.vector); err != nil {
+				return err
+			}
+			if progress != nil {
+				progress(i+1, len(ops))
+			}
+		}
+		return nil
+	}
+	config := h.config
+	h.mu.Unlock()
+
+	return h.rebuild(config, ops, workers, progress)
+}
+
+// minConcurrentBatch is the smallest batch worth a concurrent build
+const minConcurrentBatch = 1024
+
+// batchOps validates a batch of vectors and copies it into insert ops
+func batchOps(ids []uint64, vectors [][]float32, dimension int) ([]rebuildOp, error) {
+	if len(ids) != len(vectors) {
+		return nil, fmt.Errorf("batch has %d ids but %d vectors", len(ids), len(vectors))
+	}
+	ops := make([]rebuildOp, len(ids))
+	seen := make(map[uint64]bool, len(ids))
+	for i, id := range ids {
+		if len(vectors[i]) != dimension {
+			return nil, fmt.Errorf("vector dimension mismatch: expected %d, got %d", dimension, len(vectors[i]))
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("duplicate id %d in batch", id)
+		}
+		seen[id] = true
+		ops[i] = rebuildOp{id: id, vector: copyVector(vectors[i])}
 	}
+	return ops, nil
+}
 
-	// Initialize new graph structure
-	h.nodes = make(map[uint64]*hnswNode)
-	h.entryID = 0
-	h.maxLevel = -1
+// buildConcurrent inserts ops into an empty index with workers goroutines.
+// All nodes are created first so the node map is read-only during the
+// build; links are guarded per node, and an insert that raises the top
+// level holds the entry point lock throughout.
+func (h *HNSWIndex) buildConcurrent(ops []rebuildOp, workers int, progress ProgressFunc) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
 
-	// Re-add all vectors with error handling
-	for id, vector := range vectors {
-		// Create new node with random level
+	if len(h.nodes) > 0 {
+		return fmt.Errorf("concurrent build requires an empty index")
+	}
+	if len(ops) == 0 {
+		return nil
+	}
+	nodes := make([]*hnswNode, len(ops))
+	for i, op := range ops {
+		if _, ok := h.nodes[op.id]; ok {
+			return fmt.Errorf("vector with id %d already exists", op.id)
+		}
+		h.gen++
 		level := h.randomLevel()
 		node := &hnswNode{
-			id:      id,
-			vector:  vector,
+			id:      op.id,
+			vector:  op.vector,
+			gen:     h.gen,
 			level:   level,
 			friends: make([][]uint64, level+1),
 		}
-		for i := range node.friends {
-			node.friends[i] = make([]uint64, 0, h.config.M)
+		for l := range node.friends {
+			node.friends[l] = make([]uint64, 0, h.config.M)
 		}
+		h.nodes[op.id] = node
+		nodes[i] = node
+	}
+	h.entryID = nodes[0].id
+	h.maxLevel = nodes[0].level
+
+	h.concurrent = true
+	var (
+		next       atomic.Int64
+		progressMu sync.Mutex
+		done       = 1
+		wg         sync.WaitGroup
+	)
+	next.Store(1) // nodes[0] is the entry point
+	for w := 0; w < min(workers, len(nodes)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= len(nodes) {
+					return
+				}
+				h.insertConcurrent(nodes[i])
+				if progress != nil {
+					progressMu.Lock()
+					done++
+					progress(done, len(nodes))
+					progressMu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	h.concurrent = false
 
-		// First node
-		if len(h.nodes) == 0 {
-			h.nodes[id] = node
-			h.entryID = id
-			h.maxLevel = level
-			continue
+	if h.quant != nil {
+		for _, node := range nodes {
+			h.quantizeLocked(node)
 		}
+	} else if err := h.maybeTrainLocked(); err != nil {
+		return fmt.Errorf("train quantizer: %w", err)
+	}
+	return nil
+}
 
-		// Find entry point and search down
-		currID := h.entryID
+// insertConcurrent links a pre-created node into the graph during a
+// concurrent build
+func (h *HNSWIndex) insertConcurrent(node *hnswNode) {
+	h.entryMu.Lock()
+	entryID, maxLevel := h.entryID, h.maxLevel
+	if node.level > maxLevel {
+		defer h.entryMu.Unlock()
+	} else {
+		h.entryMu.Unlock()
+	}
 
-		// Traverse from top level to node's level + 1
-		for l := h.maxLevel; l > level; l-- {
-			currID = h.searchLayerClosest(vector, currID, l)
-		}
+	currID := entryID
+	scorer := h.scorer(node.vector)
+	for l := maxLevel; l > node.level; l-- {
+		currID = h.searchLayerClosest(scorer, currID, l)
+	}
 
-		// Insert at each level from level to 0
-		for l := min(level, h.maxLevel); l >= 0; l-- {
-			neighbors := h.searchLayer(vector, currID, h.config.EfConstruction, l)
-			selectedNeighbors := h.selectNeighbors(vector, neighbors, h.config.M)
+	for l := min(node.level, maxLevel); l >= 0; l-- {
+		neighbors := h.searchLayer(scorer, currID, h.config.EfConstruction, l, nil)
+		selected := h.selectNeighbors(scorer, neighbors, h.config.M)
 
-			node.friends[l] = selectedNeighbors
+		node.mu.Lock()
+		node.friends[l] = append(node.friends[l], selected...)
+		node.mu.Unlock()
 
-			for _, neighborID := range selectedNeighbors {
-				neighbor := h.nodes[neighborID]
-				if neighbor != nil && l < len(neighbor.friends) {
-					neighbor.friends[l] = append(neighbor.friends[l], id)
-					if len(neighbor.friends[l]) > h.config.M*2 {
-						neighbor.friends[l] = h.selectNeighbors(neighbor.vector, neighbor.friends[l], h.config.M)
-					}
+		for _, neighborID := range selected {
+			neighbor := h.nodes[neighborID]
+			neighbor.mu.Lock()
+			if l < len(neighbor.friends) {
+				neighbor.friends[l] = append(neighbor.friends[l], node.id)
+				if len(neighbor.friends[l]) > h.config.M*2 {
+					neighbor.friends[l] = h.selectNeighbors(h.scorer(neighbor.vector), neighbor.friends[l], h.config.M)
 				}
 			}
+			neighbor.mu.Unlock()
+		}
 
-			if len(selectedNeighbors) > 0 {
-				currID = selectedNeighbors[0]
-			}
+		if len(selected) > 0 {
+			currID = selected[0]
 		}
+	}
 
-		h.nodes[id] = node
+	if node.level > maxLevel {
+		h.entryID = node.id
+		h.maxLevel = node.level
+	}
+}
+
+// rebuildOp is a write replayed onto a shadow index (nil vector = remove)
+type rebuildOp struct {
+	id     uint64
+	vector []float32
+}
 
-		if level > h.maxLevel {
-			h.entryID = id
-			h.maxLevel = level
+// changesSince returns the writes made since seen was recorded and updates
+// seen; caller holds the read lock
+func (h *HNSWIndex) changesSince(seen map[uint64]uint64) []rebuildOp {
+	var ops []rebuildOp
+	for id, node := range h.nodes {
+		if node.deleted {
+			continue
+		}
+		if gen, ok := seen[id]; !ok || gen != node.gen {
+			seen[id] = node.gen
+			ops = append(ops, rebuildOp{id: id, vector: copyVector(h.nodeVector(node))})
+		}
+	}
+	for id := range seen {
+		if node, ok := h.nodes[id]; !ok || node.deleted {
+			delete(seen, id)
+			ops = append(ops, rebuildOp{id: id})
 		}
 	}
+	return ops
+}
 
-	// Validate the rebuilt index
-	if err := h.validateIntegrityLocked(); err != nil {
-		// Rollback on validation failure
-		h.nodes = backup.nodes
-		h.entryID = backup.entryID
-		h.maxLevel = backup.maxLevel
-		return fmt.Errorf("rebuild validation failed, rolled back: %w", err)
+// addedDuring reports a replayed write to an id of the batch being built.
+// Replaying it would silently replace the batch vector, so the batch fails
+// as it would had the write come first.
+func addedDuring(ops []rebuildOp, batch map[uint64]bool) error {
+	for _, op := range ops {
+		if batch[op.id] {
+			return fmt.Errorf("vector with id %d already exists (added during the batch)", op.id)
+		}
 	}
+	return nil
+}
+
+// apply replays ops onto the index, purging replaced and removed vectors
+// eagerly so the index is left without tombstones
+func (h *HNSWIndex) apply(ops []rebuildOp) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
 
+	purged := false
+	for _, op := range ops {
+		if _, ok := h.nodes[op.id]; ok {
+			h.purgeLocked(op.id)
+			purged = true
+		}
+		if op.vector != nil {
+			if err := h.addLocked(op.id, op.vector); err != nil {
+				return err
+			}
+		}
+	}
+	if purged {
+		h.dropDanglingLinksLocked()
+	}
 	return nil
 }
 
+// dropDanglingLinksLocked removes links to purged nodes. Purging only
+// unlinks the node's own friends, so one-way links pointing at it survive.
+func (h *HNSWIndex) dropDanglingLinksLocked() {
+	for _, node := range h.nodes {
+		for level, friends := range node.friends {
+			kept := friends[:0]
+			for _, fid := range friends {
+				if _, ok := h.nodes[fid]; ok {
+					kept = append(kept, fid)
+				}
+			}
+			node.friends[level] = kept
+		}
+	}
+}
+
+func copyVector(v []float32) []float32 {
+	c := make([]float32, len(v))
+	copy(c, v)
+	return c
+}
+
 // ValidateIntegrity checks if the HNSW graph structure is valid
 // Returns an error describing the corruption if found
 func (h *HNSWIndex) ValidateIntegrity() error {
@@ -833,10 +1953,19 @@ func (h *HNSWIndex) validateIntegrityLocked() error {
 	// Check each node
 	orphanCount := 0
 	danglingRefCount := 0
+	tombstones := 0
 
 	for id, node := range h.nodes {
-		// Check vector dimension
-		if len(node.vector) != h.dimension {
+		if node.deleted {
+			tombstones++
+		}
+
+		// Check vector dimension (or code size when only the code is held)
+		if node.vector == nil && node.code != nil && h.quant != nil {
+			if len(node.code) != h.quant.codeSize() {
+				return fmt.Errorf("node %d has wrong code size: expected %d, got %d", id, h.quant.codeSize(), len(node.code))
+			}
+		} else if len(node.vector) != h.dimension {
 			return fmt.Errorf("node %d has wrong dimension: expected %d, got %d", id, h.dimension, len(node.vector))
 		}
 
@@ -864,10 +1993,14 @@ func (h *HNSWIndex) validateIntegrityLocked() error {
 		}
 	}
 
+	if tombstones != h.tombstones {
+		return fmt.Errorf("tombstone count mismatch: %d tombstoned nodes, %d counted", tombstones, h.tombstones)
+	}
+
 	// Report issues but allow small numbers (may be transient)
 	// Stricter threshold: 1% for production safety
 	if danglingRefCount > len(h.nodes)/100 {
-		return fmt.Errorf("high number of dangling references: %d (>1%% of nodes)", danglingRefCount)
+		return fmt.Errorf("high number of dangling references: %d (>1%% of nodes, %d tombstoned)", danglingRefCount, tombstones)
 	}
 
 	// 5% threshold for orphan nodes (more lenient as they're less critical)
@@ -996,12 +2129,19 @@ func (pq *priorityQueue) bubbleDown(i int) {
 type BruteForceIndex struct {
 	mu        sync.RWMutex
 	dimension int
+	metric    Metric
 	vectors   map[uint64][]float32
 }
 
 func NewBruteForceIndex(dimension int) *BruteForceIndex {
+	return NewBruteForceIndexWithMetric(dimension, MetricCosine)
+}
+
+// NewBruteForceIndexWithMetric creates a brute force index using metric
+func NewBruteForceIndexWithMetric(dimension int, metric Metric) *BruteForceIndex {
 	return &BruteForceIndex{
 		dimension: dimension,
+		metric:    metric,
 		vectors:   make(map[uint64][]float32),
 	}
 }
@@ -1010,6 +2150,13 @@ func (b *BruteForceIndex) Dimension() int {
 	return b.dimension
 }
 
+// Metric returns the distance metric of the index
+func (b *BruteForceIndex) Metric() Metric {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.metric
+}
+
 func (b *BruteForceIndex) Count() int {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
@@ -1034,6 +2181,31 @@ func (b *BruteForceIndex) Add(id uint64, vector []float32) error {
 	return nil
 }
 
+// AddBatch inserts vectors, rejecting the whole batch if any is malformed
+// or already present
+func (b *BruteForceIndex) AddBatch(ids []uint64, vectors [][]float32, workers int, progress ProgressFunc) error {
+	ops, err := batchOps(ids, vectors, b.dimension)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, op := range ops {
+		if _, exists := b.vectors[op.id]; exists {
+			return fmt.Errorf("vector with id %d already exists", op.id)
+		}
+	}
+	for i, op := range ops {
+		b.vectors[op.id] = op.vector
+		if progress != nil {
+			progress(i+1, len(ops))
+		}
+	}
+	return nil
+}
+
 func (b *BruteForceIndex) Remove(id uint64) bool {
 	b.mu.Lock()
 	defer b.mu.Unlock()
@@ -1046,6 +2218,16 @@ func (b *BruteForceIndex) Remove(id uint64) bool {
 }
 
 func (b *BruteForceIndex) Search(query []float32, k int) []SearchResult {
+	return b.SearchFiltered(query, k, nil)
+}
+
+// SearchFiltered scores only the vectors whose IDs pass filter
+func (b *BruteForceIndex) SearchFiltered(query []float32, k int, filter Filter) []SearchResult {
+	return b.SearchWithParams(query, SearchParams{K: k, Filter: filter})
+}
+
+// SearchWithParams scores every vector; Ef is ignored as the scan is exact
+func (b *BruteForceIndex) SearchWithParams(query []float32, params SearchParams) []SearchResult {
 	if len(query) != b.dimension {
 		return nil
 	}
@@ -1060,13 +2242,20 @@ func (b *BruteForceIndex) Search(query []float32, k int) []SearchResult {
 
 	scoredVectors := make([]scored, 0, len(b.vectors))
 	for id, vec := range b.vectors {
-		scoredVectors = append(scoredVectors, scored{id: id, score: cosineSimilarity(query, vec)})
+		if params.Filter != nil && !params.Filter(id) {
+			continue
+		}
+		scoredVectors = append(scoredVectors, scored{id: id, score: b.metric.Similarity(query, vec)})
 	}
 
 	sort.Slice(scoredVectors, func(i, j int) bool {
 		return scoredVectors[i].score > scoredVectors[j].score
 	})
 
+	k := params.K
+	if params.Range && params.MinSimilarity != nil && k <= 0 {
+		k = len(scoredVectors)
+	}
 	results := make([]SearchResult, 0, k)
 	for i := 0; i < k && i < len(scoredVectors); i++ {
 		results = append(results, SearchResult{
@@ -1075,7 +2264,7 @@ func (b *BruteForceIndex) Search(query []float32, k int) []SearchResult {
 		})
 	}
 
-	return results
+	return aboveThreshold(results, params.MinSimilarity)
 }
 
 func (b *BruteForceIndex) Save(w io.Writer) error {
@@ -1084,9 +2273,13 @@ func (b *BruteForceIndex) Save(w io.Writer) error {
 
 	// Write header
 	header := struct {
+		Magic     uint32
+		Metric    uint8
 		Dimension int32
 		Count     int32
 	}{
+		Magic:     bruteForceMagic,
+		Metric:    uint8(b.metric),
 		Dimension: int32(b.dimension),
 		Count:     int32(len(b.vectors)),
 	}
@@ -1112,15 +2305,26 @@ func (b *BruteForceIndex) Load(r io.Reader) error {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
+	_, metric, lead, err := readFormatHeader(r, bruteForceMagic)
+	if err != nil {
+		return err
+	}
+
 	var header struct {
 		Dimension int32
 		Count     int32
 	}
-
-	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+	if lead != nil {
+		header.Dimension = *lead
+		err = binary.Read(r, binary.LittleEndian, &header.Count)
+	} else {
+		err = binary.Read(r, binary.LittleEndian, &header)
+	}
+	if err != nil {
 		return err
 	}
 
+	b.metric = metric
 	b.dimension = int(header.Dimension)
 	b.vectors = make(map[uint64][]float32, header.Count)
 
@@ -1142,6 +2346,20 @@ func (b *BruteForceIndex) Load(r io.Reader) error {
 }
 
 // GetAllVectors returns all vectors in the index
+// Vector returns a copy of the vector stored for id
+func (b *BruteForceIndex) Vector(id uint64) ([]float32, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	vec, ok := b.vectors[id]
+	if !ok {
+		return nil, false
+	}
+	copied := make([]float32, len(vec))
+	copy(copied, vec)
+	return copied, true
+}
+
 func (b *BruteForceIndex) GetAllVectors() map[uint64][]float32 {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
@@ -1155,11 +2373,37 @@ func (b *BruteForceIndex) GetAllVectors() map[uint64][]float32 {
 	return result
 }
 
+// MemoryStats reports vector memory usage; brute force vectors are never
+// quantized
+func (b *BruteForceIndex) MemoryStats() MemoryStats {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	bytes := int64(len(b.vectors)) * int64(b.dimension) * 4
+	return MemoryStats{Vectors: len(b.vectors), Bytes: bytes, FullPrecisionBytes: bytes, Recall: 1}
+}
+
 // Rebuild rebuilds the index (no-op for brute force)
 func (b *BruteForceIndex) Rebuild() error {
 	return nil // Brute force doesn't need rebuild
 }
 
+// RebuildWithConfig switches the metric; brute force has no graph to build
+func (b *BruteForceIndex) RebuildWithConfig(config HNSWConfig, progress ProgressFunc) error {
+	if !config.Metric.Valid() {
+		return fmt.Errorf("invalid distance metric: %d", uint8(config.Metric))
+	}
+	b.mu.Lock()
+	b.metric = config.Metric
+	n := len(b.vectors)
+	b.mu.Unlock()
+
+	if progress != nil {
+		progress(n, n)
+	}
+	return nil
+}
+
 // ValidateIntegrity checks if the index is valid
 func (b *BruteForceIndex) ValidateIntegrity() error {
 	b.mu.RLock()
@@ -1172,3 +2416,33 @@ func (b *BruteForceIndex) ValidateIntegrity() error {
 	}
 	return nil
 }
+
+// Compact is a no-op for brute force, which deletes eagerly
+func (b *BruteForceIndex) Compact() int {
+	return 0
+}
+
+// readFormatHeader reads the leading magic and metric of a saved index and
+// returns which of magics matched. For legacy data without a magic it
+// returns the already consumed first field (the dimension) in lead, no
+// magic and cosine as the metric.
+func readFormatHeader(r io.Reader, magics ...uint32) (magic uint32, metric Metric, lead *int32, err error) {
+	var first uint32
+	if err := binary.Read(r, binary.LittleEndian, &first); err != nil {
+		return 0, MetricCosine, nil, err
+	}
+	if !slices.Contains(magics, first) {
+		dim := int32(first)
+		return 0, MetricCosine, &dim, nil
+	}
+
+	var raw uint8
+	if err := binary.Read(r, binary.LittleEndian, &raw); err != nil {
+		return 0, MetricCosine, nil, err
+	}
+	metric = Metric(raw)
+	if !metric.Valid() {
+		return 0, MetricCosine, nil, fmt.Errorf("invalid distance metric: %d", raw)
+	}
+	return first, metric, nil, nil
+}
@@ -5,8 +5,11 @@ import (
 	"bytes"
 	"math"
 	"math/rand"
+	"reflect"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 )
 
 var (
@@ -393,6 +396,27 @@ func TestHNSWIndex_GetAllVectors(t *testing.T) {
 	}
 }
 
+func TestHNSWIndex_Vector(t *testing.T) {
+	idx := NewHNSWIndex(4, DefaultHNSWConfig())
+	mustAdd(t, idx, 1, []float32{1.0, 2.0, 0.0, 0.0})
+
+	vec, ok := idx.Vector(1)
+	if !ok || vec[0] != 1.0 || vec[1] != 2.0 {
+		t.Fatalf("Vector(1) = %v, %v; want [1 2 0 0]", vec, ok)
+	}
+	vec[0] = 9
+	if again, _ := idx.Vector(1); again[0] != 1.0 {
+		t.Error("Vector() should return a copy")
+	}
+	idx.Remove(1)
+	if _, ok := idx.Vector(1); ok {
+		t.Error("Vector() of a removed ID should fail")
+	}
+	if _, ok := NewBruteForceIndex(4).Vector(1); ok {
+		t.Error("BruteForceIndex.Vector() of a missing ID should fail")
+	}
+}
+
 func TestHNSWIndex_Rebuild(t *testing.T) {
 	config := DefaultHNSWConfig()
 	idx := NewHNSWIndex(4, config)
@@ -1048,3 +1072,890 @@ func TestPriorityQueue_PopEmpty(t *testing.T) {
 		t.Error("Pop() on empty queue should return zero value")
 	}
 }
+
+// =============================================================================
+// Distance Metric Tests
+// =============================================================================
+
+func TestParseMetric(t *testing.T) {
+	tests := []struct {
+		name string
+		want Metric
+	}{
+		{"", MetricCosine},
+		{"cosine", MetricCosine},
+		{"DOT", MetricDot},
+		{"ip", MetricDot},
+		{"l2", MetricL2},
+		{"euclidean", MetricL2},
+	}
+	for _, tt := range tests {
+		got, err := ParseMetric(tt.name)
+		if err != nil {
+			t.Errorf("ParseMetric(%q) error = %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseMetric(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := ParseMetric("manhattan"); err == nil {
+		t.Error("ParseMetric(manhattan) should return error")
+	}
+}
+
+func TestHNSWIndex_MetricSearch(t *testing.T) {
+	// Same direction, different magnitudes: cosine cannot tell 1 and 2
+	// apart, dot prefers the longer vector, L2 the nearer one.
+	vectors := map[uint64][]float32{
+		1: {1, 0},
+		2: {4, 0},
+		3: {0, 1},
+	}
+	query := []float32{1.2, 0}
+
+	tests := []struct {
+		metric Metric
+		want   uint64
+	}{
+		{MetricDot, 2},
+		{MetricL2, 1},
+	}
+	for _, tt := range tests {
+		config := DefaultHNSWConfig()
+		config.Metric = tt.metric
+		idx := NewHNSWIndex(2, config)
+		for id, vec := range vectors {
+			mustAdd(t, idx, id, vec)
+		}
+
+		results := idx.Search(query, 1)
+		if len(results) != 1 || results[0].ID != tt.want {
+			t.Errorf("%v: Search() = %+v, want ID %d", tt.metric, results, tt.want)
+		}
+		if idx.Metric() != tt.metric {
+			t.Errorf("Metric() = %v, want %v", idx.Metric(), tt.metric)
+		}
+	}
+
+	// L2 similarity of an exact match is 1
+	config := DefaultHNSWConfig()
+	config.Metric = MetricL2
+	idx := NewHNSWIndex(2, config)
+	mustAdd(t, idx, 1, []float32{3, 4})
+	results := idx.Search([]float32{3, 4}, 1)
+	if len(results) != 1 || math.Abs(float64(results[0].Similarity)-1) > 1e-6 {
+		t.Errorf("L2 exact match = %+v, want similarity 1", results)
+	}
+}
+
+func TestHNSWIndex_SaveLoadMetric(t *testing.T) {
+	config := DefaultHNSWConfig()
+	config.Metric = MetricDot
+	idx := NewHNSWIndex(2, config)
+	mustAdd(t, idx, 1, []float32{1, 0})
+	mustAdd(t, idx, 2, []float32{4, 0})
+
+	var buf bytes.Buffer
+	if err := idx.Save(&buf); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	data := buf.Bytes()
+
+	// Loading into a cosine index adopts the saved metric
+	idx2 := NewHNSWIndex(2, DefaultHNSWConfig())
+	if err := idx2.Load(bytes.NewReader(data)); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if idx2.Metric() != MetricDot {
+		t.Errorf("After Load() Metric() = %v, want dot", idx2.Metric())
+	}
+
+	// Legacy files have no magic/metric prefix and load as cosine
+	legacy := NewHNSWIndex(2, config)
+	if err := legacy.Load(bytes.NewReader(data[5:])); err != nil {
+		t.Fatalf("Load(legacy) error = %v", err)
+	}
+	if legacy.Metric() != MetricCosine {
+		t.Errorf("After legacy Load() Metric() = %v, want cosine", legacy.Metric())
+	}
+	if legacy.Count() != 2 {
+		t.Errorf("After legacy Load() Count() = %d, want 2", legacy.Count())
+	}
+}
+
+func TestBruteForceIndex_Metric(t *testing.T) {
+	idx := NewBruteForceIndexWithMetric(2, MetricDot)
+	mustAdd(t, idx, 1, []float32{1, 0})
+	mustAdd(t, idx, 2, []float32{4, 0})
+
+	results := idx.Search([]float32{1, 0}, 1)
+	if len(results) != 1 || results[0].ID != 2 {
+		t.Errorf("Search() = %+v, want ID 2", results)
+	}
+
+	var buf bytes.Buffer
+	if err := idx.Save(&buf); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	idx2 := NewBruteForceIndex(2)
+	if err := idx2.Load(&buf); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if idx2.Metric() != MetricDot {
+		t.Errorf("After Load() Metric() = %v, want dot", idx2.Metric())
+	}
+}
+
+// =============================================================================
+// Filtered Search Tests
+// =============================================================================
+
+func TestHNSWIndex_SearchFiltered(t *testing.T) {
+	const n, dim, k = 2000, 16, 10
+	idx := NewHNSWIndex(dim, DefaultHNSWConfig())
+	exact := NewBruteForceIndex(dim)
+	for i := uint64(1); i <= n; i++ {
+		vec := randomVector(dim)
+		mustAdd(t, idx, i, vec)
+		mustAdd(t, exact, i, vec)
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   int
+	}{
+		{"10 percent", func(id uint64) bool { return id%10 == 0 }, k},
+		{"three ids", func(id uint64) bool { return id == 7 || id == 700 || id == 1400 }, 3},
+		{"none", func(id uint64) bool { return false }, 0},
+	}
+	for _, tt := range tests {
+		query := randomVector(dim)
+		results := idx.SearchFiltered(query, k, tt.filter)
+		if len(results) != tt.want {
+			t.Errorf("%s: got %d results, want %d", tt.name, len(results), tt.want)
+		}
+		for _, r := range results {
+			if !tt.filter(r.ID) {
+				t.Errorf("%s: result %d does not pass the filter", tt.name, r.ID)
+			}
+		}
+
+		// Recall against an exact filtered scan
+		want := make(map[uint64]bool)
+		for _, r := range exact.SearchFiltered(query, k, tt.filter) {
+			want[r.ID] = true
+		}
+		hits := 0
+		for _, r := range results {
+			if want[r.ID] {
+				hits++
+			}
+		}
+		if len(want) > 0 && float64(hits)/float64(len(want)) < 0.8 {
+			t.Errorf("%s: recall %d/%d, want >= 80%%", tt.name, hits, len(want))
+		}
+	}
+
+	// A nil filter behaves like Search
+	query := randomVector(dim)
+	if got, want := idx.SearchFiltered(query, k, nil), idx.Search(query, k); len(got) != len(want) {
+		t.Errorf("nil filter returned %d results, Search returned %d", len(got), len(want))
+	}
+}
+
+func TestParseQuantization(t *testing.T) {
+	for name, want := range map[string]Quantization{"": QuantizationNone, "SQ8": QuantizationScalar8, "pq": QuantizationProduct} {
+		got, err := ParseQuantization(name)
+		if err != nil || got != want {
+			t.Errorf("ParseQuantization(%q) = %v, %v; want %v", name, got, err, want)
+		}
+	}
+	if _, err := ParseQuantization("int4"); err == nil {
+		t.Error("ParseQuantization(int4) should fail")
+	}
+	if _, err := PQSubvectors(30, 4); err == nil {
+		t.Error("PQSubvectors(30, 4) should fail: 4 does not divide 30")
+	}
+	if m, err := PQSubvectors(30, 0); err != nil || 30%m != 0 {
+		t.Errorf("PQSubvectors(30, 0) = %d, %v", m, err)
+	}
+}
+
+func TestHNSWIndex_Quantization(t *testing.T) {
+	const n, dim, k = 2000, 32, 10
+	vectors := make(map[uint64][]float32, n)
+	exact := NewBruteForceIndex(dim)
+	for i := uint64(1); i <= n; i++ {
+		vectors[i] = randomVector(dim)
+		mustAdd(t, exact, i, vectors[i])
+	}
+
+	tests := []struct {
+		name      string
+		quant     Quantization
+		keep      bool
+		minRecall float64
+		maxMemory float64 // Bytes / FullPrecisionBytes
+	}{
+		{"sq8", QuantizationScalar8, false, 0.8, 0.3},
+		{"sq8 rescored", QuantizationScalar8, true, 0.9, 1.3},
+		{"pq", QuantizationProduct, false, 0.3, 0.2},
+		{"pq rescored", QuantizationProduct, true, 0.9, 1.2},
+	}
+	for _, tt := range tests {
+		config := DefaultHNSWConfig()
+		config.Quantization = tt.quant
+		config.KeepFullVectors = tt.keep
+		config.QuantTrainSize = 400
+		config.EfSearch = n // visit every node so recall reflects quantization only
+		idx := NewHNSWIndex(dim, config)
+		for i := uint64(1); i <= n; i++ {
+			mustAdd(t, idx, i, vectors[i])
+		}
+		idx.waitTraining()
+
+		stats := idx.MemoryStats()
+		if stats.Quantization != tt.quant || stats.QuantizedVectors != n {
+			t.Errorf("%s: stats = %+v, want %d vectors quantized as %s", tt.name, stats, n, tt.quant)
+		}
+		if ratio := float64(stats.Bytes) / float64(stats.FullPrecisionBytes); ratio > tt.maxMemory {
+			t.Errorf("%s: memory ratio %.2f, want <= %.2f", tt.name, ratio, tt.maxMemory)
+		}
+		if stats.Recall <= 0 || stats.Recall > 1 {
+			t.Errorf("%s: estimated recall %v out of range", tt.name, stats.Recall)
+		}
+		if err := idx.ValidateIntegrity(); err != nil {
+			t.Errorf("%s: ValidateIntegrity failed: %v", tt.name, err)
+		}
+		if got := len(idx.GetAllVectors()[1]); got != dim {
+			t.Errorf("%s: GetAllVectors returned %d dimensions, want %d", tt.name, got, dim)
+		}
+
+		hits := 0
+		for q := 0; q < 20; q++ {
+			query := randomVector(dim)
+			want := make(map[uint64]bool)
+			for _, r := range exact.Search(query, k) {
+				want[r.ID] = true
+			}
+			for _, r := range idx.Search(query, k) {
+				if want[r.ID] {
+					hits++
+				}
+			}
+		}
+		if recall := float64(hits) / (20 * k); recall < tt.minRecall {
+			t.Errorf("%s: recall %.2f, want >= %.2f", tt.name, recall, tt.minRecall)
+		}
+	}
+}
+
+func TestHNSWIndex_QuantizedStateRoundTrip(t *testing.T) {
+	const n, dim = 300, 16
+	config := DefaultHNSWConfig()
+	config.Quantization = QuantizationProduct
+	config.PQSubvectors = 4
+	config.QuantTrainSize = 200
+	idx := NewHNSWIndex(dim, config)
+	if idx.QuantizedState() != nil {
+		t.Error("QuantizedState() before training should be nil")
+	}
+	for i := uint64(1); i <= n; i++ {
+		mustAdd(t, idx, i, randomVector(dim))
+	}
+	idx.waitTraining()
+
+	state := idx.QuantizedState()
+	if state == nil || state.Quantization != QuantizationProduct || len(state.Codes) != n {
+		t.Fatalf("unexpected state: %+v", state)
+	}
+
+	restored := NewHNSWIndex(dim, config)
+	if err := restored.RestoreQuantized(state); err != nil {
+		t.Fatalf("RestoreQuantized failed: %v", err)
+	}
+	again := restored.QuantizedState()
+	if !reflect.DeepEqual(again.Codebook, state.Codebook) || !reflect.DeepEqual(again.Codes, state.Codes) {
+		t.Error("codes or codebook changed across restore")
+	}
+	if err := restored.RestoreQuantized(state); err == nil {
+		t.Error("RestoreQuantized into a non-empty index should fail")
+	}
+
+	// The graph is rebuilt from decoded vectors; codes survive
+	if err := restored.Rebuild(); err != nil {
+		t.Fatalf("Rebuild failed: %v", err)
+	}
+	if !reflect.DeepEqual(restored.QuantizedState().Codes, state.Codes) {
+		t.Error("codes changed across rebuild")
+	}
+}
+
+func TestHNSWIndex_SaveLoadQuantized(t *testing.T) {
+	const n, dim = 300, 16
+	for _, tt := range []struct {
+		name  string
+		quant Quantization
+		keep  bool
+	}{
+		{"sq8", QuantizationScalar8, false},
+		{"pq rescored", QuantizationProduct, true},
+	} {
+		config := DefaultHNSWConfig()
+		config.Quantization = tt.quant
+		config.PQSubvectors = 4
+		config.KeepFullVectors = tt.keep
+		config.QuantTrainSize = 200
+		idx := NewHNSWIndex(dim, config)
+		for i := uint64(1); i <= n; i++ {
+			mustAdd(t, idx, i, randomVector(dim))
+		}
+		idx.waitTraining()
+		idx.Remove(n)
+
+		var buf bytes.Buffer
+		if err := idx.Save(&buf); err != nil {
+			t.Fatalf("%s: Save() error: %v", tt.name, err)
+		}
+
+		// The loading index is unquantized; the saved settings win
+		loaded := NewHNSWIndex(dim, DefaultHNSWConfig())
+		if err := loaded.Load(&buf); err != nil {
+			t.Fatalf("%s: Load() error: %v", tt.name, err)
+		}
+		stats := loaded.MemoryStats()
+		if stats.Quantization != tt.quant || stats.QuantizedVectors != n-1 || stats.Recall != idx.MemoryStats().Recall {
+			t.Errorf("%s: stats after load = %+v", tt.name, stats)
+		}
+		if loaded.config.KeepFullVectors != tt.keep || loaded.config.PQSubvectors != 4 {
+			t.Errorf("%s: settings after load = %+v", tt.name, loaded.config)
+		}
+		want, got := idx.QuantizedState(), loaded.QuantizedState()
+		if !reflect.DeepEqual(got.Codebook, want.Codebook) || !reflect.DeepEqual(got.Codes, want.Codes) {
+			t.Errorf("%s: codebook or codes changed across Save/Load", tt.name)
+		}
+		if tt.keep && !reflect.DeepEqual(loaded.GetAllVectors(), idx.GetAllVectors()) {
+			t.Errorf("%s: kept full vectors changed across Save/Load", tt.name)
+		}
+		for q := 0; q < 5; q++ {
+			query := randomVector(dim)
+			if got, want := loaded.Search(query, 5), idx.Search(query, 5); !reflect.DeepEqual(got, want) {
+				t.Errorf("%s: Search after load = %v, want %v", tt.name, got, want)
+			}
+		}
+
+		// New vectors are encoded with the loaded codebook
+		mustAdd(t, loaded, n+1, randomVector(dim))
+		if stats := loaded.MemoryStats(); stats.QuantizedVectors != n {
+			t.Errorf("%s: quantized vectors after add = %d, want %d", tt.name, stats.QuantizedVectors, n)
+		}
+	}
+}
+
+func TestHNSWIndex_TrainSkipsTombstones(t *testing.T) {
+	const dim = 8
+	config := DefaultHNSWConfig()
+	config.Quantization = QuantizationScalar8
+	config.QuantTrainSize = 100
+	idx := NewHNSWIndex(dim, config)
+
+	for i := uint64(1); i <= 50; i++ {
+		mustAdd(t, idx, i, randomVector(dim))
+	}
+	outlier := make([]float32, dim)
+	for i := range outlier {
+		outlier[i] = 1000
+	}
+	mustAdd(t, idx, 51, outlier)
+	idx.Remove(51)
+
+	// Training waits for QuantTrainSize live vectors
+	for i := uint64(52); i <= 100; i++ {
+		mustAdd(t, idx, i, randomVector(dim))
+	}
+	idx.waitTraining()
+	if stats := idx.MemoryStats(); stats.Quantization != QuantizationNone {
+		t.Fatalf("trained with 99 live vectors and a tombstone: %+v", stats)
+	}
+	mustAdd(t, idx, 101, randomVector(dim))
+	idx.waitTraining()
+	if stats := idx.MemoryStats(); stats.Quantization != QuantizationScalar8 {
+		t.Fatalf("not trained with 100 live vectors: %+v", stats)
+	}
+
+	// The deleted outlier does not stretch the codebook
+	q, ok := idx.quant.(*scalar8Quantizer)
+	if !ok {
+		t.Fatalf("quantizer = %T, want scalar8", idx.quant)
+	}
+	for i, scale := range q.scale {
+		if scale > 2.0/255*1.01 {
+			t.Errorf("dimension %d scale = %v, trained on the tombstoned outlier", i, scale)
+		}
+	}
+}
+
+func TestHNSWIndex_TrainInBackground(t *testing.T) {
+	const n, dim = 1000, 32
+	config := DefaultHNSWConfig()
+	config.Quantization = QuantizationProduct
+	config.PQSubvectors = 8
+	config.QuantTrainSize = n
+	idx := NewHNSWIndex(dim, config)
+	for i := uint64(1); i <= n; i++ {
+		mustAdd(t, idx, i, randomVector(dim))
+	}
+
+	// Reads and writes proceed while the quantizer is trained
+	if results := idx.Search(randomVector(dim), 5); len(results) != 5 {
+		t.Errorf("Search during training returned %d results", len(results))
+	}
+	for i := uint64(n + 1); i <= n+50; i++ {
+		mustAdd(t, idx, i, randomVector(dim))
+	}
+	idx.Remove(1)
+
+	idx.waitTraining()
+	stats := idx.MemoryStats()
+	if stats.Quantization != QuantizationProduct || stats.QuantizedVectors != n+50 {
+		t.Errorf("stats after training = %+v, want %d vectors quantized", stats, n+50)
+	}
+	if err := idx.ValidateIntegrity(); err != nil {
+		t.Errorf("ValidateIntegrity after training: %v", err)
+	}
+
+	// An invalid subvector count is reported by the write that would train
+	config.PQSubvectors = 5
+	bad := NewHNSWIndex(dim, config)
+	for i := uint64(1); i < n; i++ {
+		mustAdd(t, bad, i, randomVector(dim))
+	}
+	if err := bad.Add(n, randomVector(dim)); err == nil {
+		t.Error("Add reaching the training size with 5 subvectors of 32 dimensions should fail")
+	}
+}
+
+func TestHNSWIndex_RebuildWithConfig(t *testing.T) {
+	const n, dim = 500, 16
+	idx := NewHNSWIndex(dim, DefaultHNSWConfig())
+	for i := uint64(1); i <= n; i++ {
+		mustAdd(t, idx, i, randomVector(dim))
+	}
+
+	config := DefaultHNSWConfig()
+	config.SetM(8)
+	config.Metric = MetricL2
+	calls := 0
+	progress := func(done, total int) {
+		calls++
+		if total != n {
+			t.Errorf("progress total = %d, want %d", total, n)
+		}
+		if done != n/2 {
+			return
+		}
+		// Reads and writes proceed while the shadow index is built
+		if results := idx.Search(randomVector(dim), 5); len(results) != 5 {
+			t.Errorf("Search during rebuild returned %d results", len(results))
+		}
+		if err := idx.Rebuild(); err != ErrRebuildInProgress {
+			t.Errorf("concurrent Rebuild() = %v, want ErrRebuildInProgress", err)
+		}
+		idx.Remove(1)
+		idx.Remove(2)
+		mustAdd(t, idx, 1, randomVector(dim)) // replaced
+		mustAdd(t, idx, n+1, randomVector(dim))
+	}
+	if err := idx.RebuildWithConfig(config, progress); err != nil {
+		t.Fatalf("RebuildWithConfig failed: %v", err)
+	}
+
+	if calls != n {
+		t.Errorf("progress called %d times, want %d", calls, n)
+	}
+	if got := idx.Config(); got.M != 8 || got.Metric != MetricL2 {
+		t.Errorf("config after rebuild = M %d metric %s", got.M, got.Metric)
+	}
+	vectors := idx.GetAllVectors()
+	if len(vectors) != n || vectors[2] != nil || vectors[1] == nil || vectors[n+1] == nil {
+		t.Errorf("writes during rebuild were not replayed: %d vectors", len(vectors))
+	}
+	if results := idx.Search(vectors[n+1], 1); len(results) != 1 || results[0].ID != n+1 {
+		t.Errorf("Search for the vector added during rebuild = %+v", results)
+	}
+	if err := idx.ValidateIntegrity(); err != nil {
+		t.Errorf("ValidateIntegrity after rebuild: %v", err)
+	}
+
+	bad := DefaultHNSWConfig()
+	bad.M = 1
+	if err := idx.RebuildWithConfig(bad, nil); err == nil {
+		t.Error("RebuildWithConfig with m=1 should fail")
+	}
+}
+
+func TestHNSWIndex_AddBatch(t *testing.T) {
+	const n, dim = 3000, 16
+	config := DefaultHNSWConfig()
+	config.Quantization = QuantizationScalar8
+	config.KeepFullVectors = true
+	idx := NewHNSWIndex(dim, config)
+	mustAdd(t, idx, 1, randomVector(dim))
+
+	ids := make([]uint64, n)
+	vectors := make([][]float32, n)
+	for i := range ids {
+		ids[i] = uint64(i + 2)
+		vectors[i] = randomVector(dim)
+	}
+	last := 0
+	progress := func(done, total int) {
+		if total != n+1 || done <= last {
+			t.Errorf("progress(%d, %d) after %d", done, total, last)
+		}
+		last = done
+	}
+	if err := idx.AddBatch(ids, vectors, 4, progress); err != nil {
+		t.Fatalf("AddBatch failed: %v", err)
+	}
+	if last != n+1 {
+		t.Errorf("last progress = %d, want %d", last, n+1)
+	}
+	if got := idx.Count(); got != n+1 {
+		t.Errorf("Count() = %d, want %d", got, n+1)
+	}
+	if err := idx.ValidateIntegrity(); err != nil {
+		t.Errorf("ValidateIntegrity after AddBatch: %v", err)
+	}
+	idx.waitTraining()
+	if stats := idx.MemoryStats(); stats.QuantizedVectors != n+1 {
+		t.Errorf("quantized vectors = %d, want %d", stats.QuantizedVectors, n+1)
+	}
+	found := 0
+	for i := 0; i < n; i += 30 {
+		if results := idx.Search(vectors[i], 1); len(results) == 1 && results[0].ID == ids[i] {
+			found++
+		}
+	}
+	if found < n/30*9/10 {
+		t.Errorf("self-recall after concurrent build = %d/%d", found, n/30)
+	}
+
+	// A small batch is inserted in place
+	if err := idx.AddBatch([]uint64{n + 2, n + 3}, [][]float32{randomVector(dim), randomVector(dim)}, 4, nil); err != nil {
+		t.Fatalf("small AddBatch failed: %v", err)
+	}
+
+	bad := []struct {
+		name    string
+		ids     []uint64
+		vectors [][]float32
+	}{
+		{"length mismatch", []uint64{9001}, nil},
+		{"dimension", []uint64{9001}, [][]float32{randomVector(dim + 1)}},
+		{"duplicate in batch", []uint64{9001, 9001}, [][]float32{randomVector(dim), randomVector(dim)}},
+		{"existing id", []uint64{9001, 1}, [][]float32{randomVector(dim), randomVector(dim)}},
+	}
+	for _, tc := range bad {
+		if err := idx.AddBatch(tc.ids, tc.vectors, 4, nil); err == nil {
+			t.Errorf("AddBatch with %s should fail", tc.name)
+		}
+	}
+	if got := idx.Count(); got != n+3 {
+		t.Errorf("Count() after rejected batches = %d, want %d", got, n+3)
+	}
+}
+
+func TestHNSWIndex_AddBatchConcurrentWrite(t *testing.T) {
+	const n, dim = minConcurrentBatch, 8
+	idx := NewHNSWIndex(dim, DefaultHNSWConfig())
+	mustAdd(t, idx, 1, randomVector(dim))
+
+	ids := make([]uint64, n)
+	vectors := make([][]float32, n)
+	for i := range ids {
+		ids[i] = uint64(i + 2)
+		vectors[i] = randomVector(dim)
+	}
+
+	// Write one of the batch ids while the shadow index is being built
+	concurrent := randomVector(dim)
+	written := false
+	progress := func(done, total int) {
+		if !written {
+			written = true
+			mustAdd(t, idx, ids[n-1], concurrent)
+		}
+	}
+	err := idx.AddBatch(ids, vectors, 1, progress)
+	if err == nil || !strings.Contains(err.Error(), "added during the batch") {
+		t.Fatalf("AddBatch with a concurrent write = %v, want an error", err)
+	}
+
+	// The batch is rejected as a whole; the concurrent write stands
+	if got := idx.Count(); got != 2 {
+		t.Errorf("Count() = %d, want 2", got)
+	}
+	stored := idx.GetAllVectors()
+	if !reflect.DeepEqual(stored[ids[n-1]], concurrent) {
+		t.Errorf("vector %d = %v, want the concurrent vector", ids[n-1], stored[ids[n-1]])
+	}
+	if _, ok := stored[ids[0]]; ok {
+		t.Errorf("batch vector %d stored after the batch failed", ids[0])
+	}
+}
+
+func TestHNSWIndex_CheckRecall(t *testing.T) {
+	const n, dim = 500, 16
+	idx := NewHNSWIndex(dim, DefaultHNSWConfig())
+	for i := uint64(1); i <= n; i++ {
+		mustAdd(t, idx, i, randomVector(dim))
+	}
+
+	report, err := idx.CheckRecall(RecallParams{Samples: 50})
+	if err != nil {
+		t.Fatalf("CheckRecall failed: %v", err)
+	}
+	if report.Queries != 50 || report.K != DefaultRecallK || report.Ef != DefaultHNSWConfig().EfSearch {
+		t.Errorf("report = %d queries, k %d, ef %d", report.Queries, report.K, report.Ef)
+	}
+	if report.Recall < 0.9 || report.MinRecall > report.Recall {
+		t.Errorf("recall = %.3f (min %.3f)", report.Recall, report.MinRecall)
+	}
+	if report.Visited.Avg <= 0 || report.Visited.Max < report.Visited.P50 || report.Micros.P99 < report.Micros.P50 {
+		t.Errorf("visited = %+v, latency = %+v", report.Visited, report.Micros)
+	}
+
+	// An exhaustive ef finds the exact neighbors of supplied queries
+	queries := [][]float32{randomVector(dim), randomVector(dim)}
+	report, err = idx.CheckRecall(RecallParams{K: 5, Ef: n, Queries: queries})
+	if err != nil {
+		t.Fatalf("CheckRecall with queries failed: %v", err)
+	}
+	if report.Queries != 2 || report.Recall != 1 {
+		t.Errorf("exhaustive recall = %.3f over %d queries, want 1 over 2", report.Recall, report.Queries)
+	}
+	if _, err := idx.CheckRecall(RecallParams{Queries: [][]float32{randomVector(dim + 1)}}); err == nil {
+		t.Error("CheckRecall with a wrong query dimension should fail")
+	}
+	if _, err := idx.CheckRecall(RecallParams{Samples: MaxRecallSamples + 1}); err == nil {
+		t.Error("CheckRecall with too many samples should fail")
+	}
+
+	empty, err := NewHNSWIndex(dim, DefaultHNSWConfig()).CheckRecall(RecallParams{})
+	if err != nil || empty.Queries != 0 || empty.Recall != 1 {
+		t.Errorf("CheckRecall on an empty index = %+v, %v", empty, err)
+	}
+}
+
+func TestHNSWIndex_Tombstones(t *testing.T) {
+	const n, dim = 200, 8
+	config := DefaultHNSWConfig()
+	config.EfSearch = n
+	config.TombstoneRatio = 1 // compact explicitly
+	idx := NewHNSWIndex(dim, config)
+	vectors := make(map[uint64][]float32, n)
+	for i := uint64(1); i <= n; i++ {
+		vectors[i] = randomVector(dim)
+		mustAdd(t, idx, i, vectors[i])
+	}
+
+	for i := uint64(1); i <= n/2; i++ {
+		if !idx.Remove(i) {
+			t.Fatalf("Remove(%d) = false", i)
+		}
+	}
+	if idx.Remove(1) {
+		t.Error("removing a tombstoned vector should return false")
+	}
+	if got := idx.Count(); got != n/2 {
+		t.Errorf("Count() = %d, want %d", got, n/2)
+	}
+	if stats := idx.MemoryStats(); stats.Vectors != n/2 || stats.Tombstones != n/2 {
+		t.Errorf("MemoryStats() = %d vectors, %d tombstones", stats.Vectors, stats.Tombstones)
+	}
+	if err := idx.ValidateIntegrity(); err != nil {
+		t.Errorf("ValidateIntegrity with tombstones: %v", err)
+	}
+	for i := uint64(1); i <= n/2; i++ {
+		for _, r := range idx.Search(vectors[i], 5) {
+			if r.ID <= n/2 {
+				t.Fatalf("Search returned tombstoned vector %d", r.ID)
+			}
+		}
+	}
+
+	// Tombstones are not saved
+	var buf bytes.Buffer
+	if err := idx.Save(&buf); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded := NewHNSWIndex(dim, config)
+	if err := loaded.Load(&buf); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got := loaded.Count(); got != n/2 {
+		t.Errorf("loaded Count() = %d, want %d", got, n/2)
+	}
+	if err := loaded.ValidateIntegrity(); err != nil {
+		t.Errorf("loaded ValidateIntegrity: %v", err)
+	}
+
+	// Re-adding a tombstoned ID replaces it
+	mustAdd(t, idx, 1, vectors[1])
+	if results := idx.Search(vectors[1], 1); len(results) != 1 || results[0].ID != 1 {
+		t.Errorf("Search for re-added vector = %+v", results)
+	}
+
+	if purged := idx.Compact(); purged != n/2-1 {
+		t.Errorf("Compact() = %d, want %d", purged, n/2-1)
+	}
+	if stats := idx.MemoryStats(); stats.Tombstones != 0 || stats.Vectors != n/2+1 {
+		t.Errorf("after Compact: %d vectors, %d tombstones", stats.Vectors, stats.Tombstones)
+	}
+	if err := idx.ValidateIntegrity(); err != nil {
+		t.Errorf("ValidateIntegrity after Compact: %v", err)
+	}
+	for id := uint64(n/2 + 1); id <= n; id++ {
+		if results := idx.Search(vectors[id], 1); len(results) != 1 || results[0].ID != id {
+			t.Fatalf("Search for %d after Compact = %+v", id, results)
+		}
+	}
+
+	bad := DefaultHNSWConfig()
+	bad.TombstoneRatio = 1.5
+	if err := bad.Validate(); err == nil {
+		t.Error("Validate should reject tombstone_ratio > 1")
+	}
+}
+
+func TestHNSWIndex_BackgroundCompaction(t *testing.T) {
+	config := DefaultHNSWConfig()
+	config.TombstoneRatio = 0.1
+	idx := NewHNSWIndex(8, config)
+	for i := uint64(1); i <= 100; i++ {
+		mustAdd(t, idx, i, randomVector(8))
+	}
+	for i := uint64(1); i <= 9; i++ {
+		idx.Remove(i)
+	}
+	if got := idx.MemoryStats().Tombstones; got != 9 {
+		t.Fatalf("Tombstones = %d before the ratio is reached, want 9", got)
+	}
+
+	idx.Remove(10) // reaches the ratio
+	deadline := time.Now().Add(5 * time.Second)
+	for idx.MemoryStats().Tombstones > 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("background compaction did not purge tombstones")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if got := idx.Count(); got != 90 {
+		t.Errorf("Count() = %d, want 90", got)
+	}
+	if err := idx.ValidateIntegrity(); err != nil {
+		t.Errorf("ValidateIntegrity after compaction: %v", err)
+	}
+}
+
+func TestHNSWIndex_CompactPurgesEntryPoint(t *testing.T) {
+	const n, dim = 1000, 8
+	config := DefaultHNSWConfig()
+	config.TombstoneRatio = 1 // compact explicitly
+	idx := NewHNSWIndex(dim, config)
+	for i := uint64(1); i <= n; i++ {
+		mustAdd(t, idx, i, randomVector(dim))
+	}
+
+	// Several batches of tombstones, including the entry point
+	entry := idx.entryID
+	idx.Remove(entry)
+	removed := 1
+	for i := uint64(1); removed < 3*compactBatchSize; i++ {
+		if idx.Remove(i) {
+			removed++
+		}
+	}
+	if purged := idx.Compact(); purged != removed {
+		t.Errorf("Compact() = %d, want %d", purged, removed)
+	}
+	if _, ok := idx.nodes[idx.entryID]; !ok || idx.entryID == entry {
+		t.Errorf("entry point %d after purging %d", idx.entryID, entry)
+	}
+	for _, node := range idx.nodes {
+		if node.level > idx.maxLevel {
+			t.Errorf("node %d at level %d above max level %d", node.id, node.level, idx.maxLevel)
+		}
+	}
+	if err := idx.ValidateIntegrity(); err != nil {
+		t.Errorf("ValidateIntegrity after compaction: %v", err)
+	}
+	if results := idx.Search(randomVector(dim), 10); len(results) != 10 {
+		t.Errorf("Search after compaction returned %d results", len(results))
+	}
+}
+
+func TestHNSWIndex_SearchWithParams(t *testing.T) {
+	const n, dim = 300, 8
+	idx := NewHNSWIndex(dim, DefaultHNSWConfig())
+	exact := NewBruteForceIndex(dim)
+	for i := uint64(1); i <= n; i++ {
+		vec := randomVector(dim)
+		mustAdd(t, idx, i, vec)
+		mustAdd(t, exact, i, vec)
+	}
+	query := randomVector(dim)
+	want := exact.Search(query, n)
+
+	// An ef covering the index makes k-NN exact
+	got := idx.SearchWithParams(query, SearchParams{K: 5, Ef: n})
+	if len(got) != 5 {
+		t.Fatalf("k-NN returned %d results, want 5", len(got))
+	}
+	for i := range got {
+		if got[i].ID != want[i].ID {
+			t.Errorf("k-NN result %d = %d, want %d", i, got[i].ID, want[i].ID)
+		}
+	}
+
+	// The threshold cuts k-NN results
+	threshold := want[2].Similarity
+	if got := idx.SearchWithParams(query, SearchParams{K: 5, Ef: n, MinSimilarity: &threshold}); len(got) != 3 {
+		t.Errorf("k-NN with threshold returned %d results, want 3", len(got))
+	}
+
+	// Range search returns every vector above the threshold, past ef
+	threshold = want[80].Similarity
+	got = idx.SearchWithParams(query, SearchParams{Range: true, Ef: 10, MinSimilarity: &threshold})
+	if len(got) != 81 {
+		t.Errorf("range search returned %d results, want 81", len(got))
+	}
+	for _, r := range got {
+		if r.Similarity < threshold {
+			t.Errorf("range result %d below threshold: %f", r.ID, r.Similarity)
+		}
+	}
+	if got := exact.SearchWithParams(query, SearchParams{Range: true, MinSimilarity: &threshold}); len(got) != 81 {
+		t.Errorf("brute force range search returned %d results, want 81", len(got))
+	}
+	lowest := want[n-1].Similarity
+	if got := idx.SearchWithParams(query, SearchParams{Range: true, MinSimilarity: &lowest}); len(got) != n {
+		t.Errorf("range search covering the index returned %d results, want %d", len(got), n)
+	}
+	if got := idx.SearchWithParams(query, SearchParams{Range: true, K: 7, MinSimilarity: &threshold}); len(got) != 7 {
+		t.Errorf("capped range search returned %d results, want 7", len(got))
+	}
+
+	// Range search honours filters and tombstones
+	idx.Remove(want[0].ID)
+	even := func(id uint64) bool { return id%2 == 0 }
+	for _, r := range idx.SearchWithParams(query, SearchParams{Range: true, MinSimilarity: &threshold, Filter: even}) {
+		if r.ID%2 != 0 || r.ID == want[0].ID {
+			t.Errorf("filtered range search returned %d", r.ID)
+		}
+	}
+}
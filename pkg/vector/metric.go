@@ -0,0 +1,108 @@
+package vector
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/gibram-io/gibram/pkg/simd"
+)
+
+// =============================================================================
+// Distance Metrics
+// =============================================================================
+
+// Metric selects how vectors are compared. The zero value is cosine.
+type Metric uint8
+
+const (
+	MetricCosine Metric = iota // cosine similarity, in [-1, 1]
+	MetricDot                  // inner product, unbounded
+	MetricL2                   // Euclidean distance, reported as 1/(1+distance)
+)
+
+// String returns the metric name used in configs and on the wire
+func (m Metric) String() string {
+	switch m {
+	case MetricCosine:
+		return "cosine"
+	case MetricDot:
+		return "dot"
+	case MetricL2:
+		return "l2"
+	}
+	return fmt.Sprintf("metric(%d)", uint8(m))
+}
+
+// ParseMetric parses a metric name. The empty string selects cosine.
+func ParseMetric(name string) (Metric, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "cosine", "cos":
+		return MetricCosine, nil
+	case "dot", "ip", "inner_product":
+		return MetricDot, nil
+	case "l2", "euclidean":
+		return MetricL2, nil
+	}
+	return MetricCosine, fmt.Errorf("unknown distance metric: %q (want cosine, dot or l2)", name)
+}
+
+// Valid reports whether m is a known metric
+func (m Metric) Valid() bool {
+	return m <= MetricL2
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (m Metric) MarshalText() ([]byte, error) {
+	if !m.Valid() {
+		return nil, fmt.Errorf("invalid distance metric: %d", uint8(m))
+	}
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (m *Metric) UnmarshalText(text []byte) error {
+	parsed, err := ParseMetric(string(text))
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Similarity compares two vectors; higher is more similar for every metric.
+// L2 distance d is mapped to 1/(1+d) so that identical vectors score 1.
+func (m Metric) Similarity(a, b []float32) float32 {
+	switch m {
+	case MetricDot:
+		return simd.DotProduct(a, b)
+	case MetricL2:
+		return 1 / (1 + simd.EuclideanDistance(a, b))
+	}
+	return simd.CosineSimilarity(a, b)
+}
+
+// Score maps a similarity to a ranking score comparable with the graph
+// expansion scores used by queries (1/(1+hop)). Cosine and L2 similarities
+// are used as-is; unbounded inner products are squashed with a logistic.
+func (m Metric) Score(similarity float32) float32 {
+	if m == MetricDot {
+		return float32(1 / (1 + math.Exp(-float64(similarity))))
+	}
+	return similarity
+}
+
+// fromDot derives the similarity from an inner product and the squared
+// norms of both vectors, for scoring quantized codes without decoding
+func (m Metric) fromDot(dot, aNorm2, bNorm2 float32) float32 {
+	switch m {
+	case MetricDot:
+		return dot
+	case MetricL2:
+		return 1 / (1 + float32(math.Sqrt(math.Max(float64(aNorm2-2*dot+bNorm2), 0))))
+	}
+	if aNorm2 == 0 || bNorm2 == 0 {
+		return 0
+	}
+	return dot / float32(math.Sqrt(float64(aNorm2)*float64(bNorm2)))
+}
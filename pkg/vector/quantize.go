@@ -0,0 +1,524 @@
+package vector
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// =============================================================================
+// Quantization
+// =============================================================================
+
+// Quantization selects how an index compresses stored vectors. The zero
+// value keeps full-precision float32 vectors.
+type Quantization uint8
+
+const (
+	QuantizationNone    Quantization = iota // float32, 4 bytes per dimension
+	QuantizationScalar8                     // int8 scalar, 1 byte per dimension
+	QuantizationProduct                     // product quantization, 1 byte per subvector
+)
+
+// Quantization defaults
+const (
+	DefaultQuantTrainSize = 1024 // vectors collected before the quantizer is trained
+	pqCentroids           = 256  // centroids per PQ subspace (one byte per code)
+	pqDefaultSubDim       = 8    // target dimensions per PQ subvector
+	pqTrainIterations     = 10   // k-means iterations per subspace
+	recallSampleQueries   = 50   // training vectors held out as queries for the recall estimate
+	recallK               = 10   // recall@k reported by MemoryStats
+)
+
+// String returns the quantization name used in configs and on the wire
+func (q Quantization) String() string {
+	switch q {
+	case QuantizationNone:
+		return "none"
+	case QuantizationScalar8:
+		return "sq8"
+	case QuantizationProduct:
+		return "pq"
+	}
+	return fmt.Sprintf("quantization(%d)", uint8(q))
+}
+
+// ParseQuantization parses a quantization name. The empty string selects none.
+func ParseQuantization(name string) (Quantization, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "none", "float32":
+		return QuantizationNone, nil
+	case "sq8", "int8", "scalar":
+		return QuantizationScalar8, nil
+	case "pq", "product":
+		return QuantizationProduct, nil
+	}
+	return QuantizationNone, fmt.Errorf("unknown quantization: %q (want none, sq8 or pq)", name)
+}
+
+// Valid reports whether q is a known quantization
+func (q Quantization) Valid() bool {
+	return q <= QuantizationProduct
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (q Quantization) MarshalText() ([]byte, error) {
+	if !q.Valid() {
+		return nil, fmt.Errorf("invalid quantization: %d", uint8(q))
+	}
+	return []byte(q.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (q *Quantization) UnmarshalText(text []byte) error {
+	parsed, err := ParseQuantization(string(text))
+	if err != nil {
+		return err
+	}
+	*q = parsed
+	return nil
+}
+
+// PQSubvectors returns the number of PQ subvectors for a dimension. A
+// requested count must divide dim; 0 picks about 8 dimensions per subvector.
+func PQSubvectors(dim, requested int) (int, error) {
+	if requested > 0 {
+		if requested > dim || dim%requested != 0 {
+			return 0, fmt.Errorf("pq_subvectors %d must divide the vector dimension %d", requested, dim)
+		}
+		return requested, nil
+	}
+	m := max(dim/pqDefaultSubDim, 1)
+	for dim%m != 0 {
+		m--
+	}
+	return m, nil
+}
+
+// quantizer compresses vectors into fixed-size codes
+type quantizer interface {
+	kind() Quantization
+	encode(vec []float32) []byte
+	decode(code []byte) []float32
+	// dotTable prepares asymmetric inner products between query and codes
+	dotTable(query []float32) func(code []byte) float32
+	codeSize() int
+	codebookSize() int // bytes held by the codebook
+	marshal() []byte
+}
+
+// trainQuantizer fits a quantizer of the given kind to samples
+func trainQuantizer(kind Quantization, dim, pqSubvectors int, samples [][]float32) (quantizer, error) {
+	switch kind {
+	case QuantizationScalar8:
+		return trainScalar8(dim, samples), nil
+	case QuantizationProduct:
+		m, err := PQSubvectors(dim, pqSubvectors)
+		if err != nil {
+			return nil, err
+		}
+		return trainProduct(dim, m, samples), nil
+	}
+	return nil, fmt.Errorf("cannot train quantizer %s", kind)
+}
+
+// unmarshalQuantizer restores a quantizer written by marshal
+func unmarshalQuantizer(kind Quantization, dim int, data []byte) (quantizer, error) {
+	r := bytes.NewReader(data)
+	switch kind {
+	case QuantizationScalar8:
+		q := &scalar8Quantizer{min: make([]float32, dim), scale: make([]float32, dim)}
+		if err := binary.Read(r, binary.LittleEndian, q.min); err != nil {
+			return nil, fmt.Errorf("read sq8 codebook: %w", err)
+		}
+		if err := binary.Read(r, binary.LittleEndian, q.scale); err != nil {
+			return nil, fmt.Errorf("read sq8 codebook: %w", err)
+		}
+		return q, nil
+	case QuantizationProduct:
+		var m uint32
+		if err := binary.Read(r, binary.LittleEndian, &m); err != nil {
+			return nil, fmt.Errorf("read pq codebook: %w", err)
+		}
+		if m == 0 || int(m) > dim || dim%int(m) != 0 {
+			return nil, fmt.Errorf("invalid pq subvector count %d for dimension %d", m, dim)
+		}
+		q := &productQuantizer{dim: dim, m: int(m), subDim: dim / int(m), centroids: make([]float32, dim*pqCentroids)}
+		if err := binary.Read(r, binary.LittleEndian, q.centroids); err != nil {
+			return nil, fmt.Errorf("read pq codebook: %w", err)
+		}
+		return q, nil
+	}
+	return nil, fmt.Errorf("unknown quantization %d", uint8(kind))
+}
+
+// =============================================================================
+// Scalar (int8) Quantizer
+// =============================================================================
+
+// scalar8Quantizer maps each dimension linearly onto 0..255 between the
+// minimum and maximum seen during training
+type scalar8Quantizer struct {
+	min   []float32
+	scale []float32 // (max-min)/255, 0 for constant dimensions
+}
+
+func trainScalar8(dim int, samples [][]float32) *scalar8Quantizer {
+	q := &scalar8Quantizer{min: make([]float32, dim), scale: make([]float32, dim)}
+	maxv := make([]float32, dim)
+	for i := 0; i < dim; i++ {
+		q.min[i] = float32(math.Inf(1))
+		maxv[i] = float32(math.Inf(-1))
+	}
+	for _, vec := range samples {
+		for i, v := range vec {
+			q.min[i] = min(q.min[i], v)
+			maxv[i] = max(maxv[i], v)
+		}
+	}
+	for i := 0; i < dim; i++ {
+		if len(samples) == 0 {
+			q.min[i] = 0
+			continue
+		}
+		q.scale[i] = (maxv[i] - q.min[i]) / 255
+	}
+	return q
+}
+
+func (q *scalar8Quantizer) kind() Quantization { return QuantizationScalar8 }
+func (q *scalar8Quantizer) codeSize() int      { return len(q.min) }
+func (q *scalar8Quantizer) codebookSize() int  { return 8 * len(q.min) }
+
+func (q *scalar8Quantizer) encode(vec []float32) []byte {
+	code := make([]byte, len(vec))
+	for i, v := range vec {
+		if q.scale[i] == 0 {
+			continue
+		}
+		c := math.Round(float64((v - q.min[i]) / q.scale[i]))
+		code[i] = byte(min(max(c, 0), 255))
+	}
+	return code
+}
+
+func (q *scalar8Quantizer) decode(code []byte) []float32 {
+	vec := make([]float32, len(code))
+	for i, c := range code {
+		vec[i] = q.min[i] + float32(c)*q.scale[i]
+	}
+	return vec
+}
+
+// dotTable uses q·x = Σ q_i·min_i + Σ (q_i·scale_i)·c_i
+func (q *scalar8Quantizer) dotTable(query []float32) func(code []byte) float32 {
+	var base float32
+	weights := make([]float32, len(query))
+	for i, v := range query {
+		base += v * q.min[i]
+		weights[i] = v * q.scale[i]
+	}
+	return func(code []byte) float32 {
+		sum := base
+		for i, c := range code {
+			sum += weights[i] * float32(c)
+		}
+		return sum
+	}
+}
+
+func (q *scalar8Quantizer) marshal() []byte {
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.LittleEndian, q.min)
+	_ = binary.Write(&buf, binary.LittleEndian, q.scale)
+	return buf.Bytes()
+}
+
+// =============================================================================
+// Product Quantizer
+// =============================================================================
+
+// productQuantizer splits vectors into m subvectors and stores the index of
+// the nearest of 256 k-means centroids for each
+type productQuantizer struct {
+	dim       int
+	m         int
+	subDim    int
+	centroids []float32 // [m][pqCentroids][subDim], flattened
+}
+
+func trainProduct(dim, m int, samples [][]float32) *productQuantizer {
+	q := &productQuantizer{dim: dim, m: m, subDim: dim / m, centroids: make([]float32, dim*pqCentroids)}
+	rng := rand.New(rand.NewSource(int64(dim)*31 + int64(m)))
+	for j := 0; j < m; j++ {
+		sub := make([][]float32, len(samples))
+		for i, vec := range samples {
+			sub[i] = vec[j*q.subDim : (j+1)*q.subDim]
+		}
+		copy(q.subspace(j), kmeans(sub, q.subDim, pqCentroids, pqTrainIterations, rng))
+	}
+	return q
+}
+
+// subspace returns the flattened centroids of subspace j
+func (q *productQuantizer) subspace(j int) []float32 {
+	size := pqCentroids * q.subDim
+	return q.centroids[j*size : (j+1)*size]
+}
+
+func (q *productQuantizer) kind() Quantization { return QuantizationProduct }
+func (q *productQuantizer) codeSize() int      { return q.m }
+func (q *productQuantizer) codebookSize() int  { return 4 * len(q.centroids) }
+
+func (q *productQuantizer) encode(vec []float32) []byte {
+	code := make([]byte, q.m)
+	for j := 0; j < q.m; j++ {
+		code[j] = byte(nearestCentroid(vec[j*q.subDim:(j+1)*q.subDim], q.subspace(j), q.subDim))
+	}
+	return code
+}
+
+func (q *productQuantizer) decode(code []byte) []float32 {
+	vec := make([]float32, 0, q.dim)
+	for j, c := range code {
+		cents := q.subspace(j)
+		vec = append(vec, cents[int(c)*q.subDim:(int(c)+1)*q.subDim]...)
+	}
+	return vec
+}
+
+// dotTable precomputes the inner product of each query subvector with
+// every centroid, so scoring a code is m table lookups
+func (q *productQuantizer) dotTable(query []float32) func(code []byte) float32 {
+	table := make([]float32, q.m*pqCentroids)
+	for j := 0; j < q.m; j++ {
+		qsub := query[j*q.subDim : (j+1)*q.subDim]
+		cents := q.subspace(j)
+		for c := 0; c < pqCentroids; c++ {
+			var dot float32
+			cent := cents[c*q.subDim : (c+1)*q.subDim]
+			for d, v := range qsub {
+				dot += v * cent[d]
+			}
+			table[j*pqCentroids+c] = dot
+		}
+	}
+	return func(code []byte) float32 {
+		var sum float32
+		for j, c := range code {
+			sum += table[j*pqCentroids+int(c)]
+		}
+		return sum
+	}
+}
+
+func (q *productQuantizer) marshal() []byte {
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(q.m))
+	_ = binary.Write(&buf, binary.LittleEndian, q.centroids)
+	return buf.Bytes()
+}
+
+// kmeans clusters points into k centroids (flattened). With fewer distinct
+// points than k, the remaining centroids duplicate existing ones.
+func kmeans(points [][]float32, dim, k, iterations int, rng *rand.Rand) []float32 {
+	centroids := make([]float32, k*dim)
+	if len(points) == 0 {
+		return centroids
+	}
+	perm := rng.Perm(len(points))
+	for c := 0; c < k; c++ {
+		copy(centroids[c*dim:(c+1)*dim], points[perm[c%len(points)]])
+	}
+
+	assign := make([]int, len(points))
+	sums := make([]float64, k*dim)
+	counts := make([]int, k)
+	for it := 0; it < iterations; it++ {
+		for i, p := range points {
+			assign[i] = nearestCentroid(p, centroids, dim)
+		}
+		clear(sums)
+		clear(counts)
+		for i, p := range points {
+			c := assign[i]
+			counts[c]++
+			for d, v := range p {
+				sums[c*dim+d] += float64(v)
+			}
+		}
+		for c := 0; c < k; c++ {
+			if counts[c] == 0 {
+				// Re-seed empty clusters from a random point
+				copy(centroids[c*dim:(c+1)*dim], points[rng.Intn(len(points))])
+				continue
+			}
+			for d := 0; d < dim; d++ {
+				centroids[c*dim+d] = float32(sums[c*dim+d] / float64(counts[c]))
+			}
+		}
+	}
+	return centroids
+}
+
+// nearestCentroid returns the index of the centroid closest (L2) to p
+func nearestCentroid(p, centroids []float32, dim int) int {
+	best, bestDist := 0, float32(math.Inf(1))
+	for c := 0; c*dim < len(centroids); c++ {
+		cent := centroids[c*dim : (c+1)*dim]
+		var dist float32
+		for d, v := range p {
+			diff := v - cent[d]
+			dist += diff * diff
+		}
+		if dist < bestDist {
+			best, bestDist = c, dist
+		}
+	}
+	return best
+}
+
+// =============================================================================
+// Recall Estimate
+// =============================================================================
+
+// estimateRecall measures recall@k of quantized search against exact
+// search for held-out queries over candidates. With rescore > 0 the top
+// rescore quantized hits are rescored exactly, as searches do when full
+// vectors are kept.
+func estimateRecall(metric Metric, q quantizer, queries, candidates [][]float32, rescore int) float32 {
+	if len(queries) == 0 || len(candidates) <= recallK {
+		return 1
+	}
+	codes := make([][]byte, len(candidates))
+	norms := make([]float32, len(candidates))
+	for i, vec := range candidates {
+		codes[i] = q.encode(vec)
+		norms[i] = squaredNorm(q.decode(codes[i]))
+	}
+
+	type scored struct {
+		idx   int
+		score float32
+	}
+	rank := func(ids []int, score func(i int) float32) []scored {
+		all := make([]scored, len(ids))
+		for n, i := range ids {
+			all[n] = scored{idx: i, score: score(i)}
+		}
+		sort.Slice(all, func(a, b int) bool { return all[a].score > all[b].score })
+		return all
+	}
+	allIDs := make([]int, len(candidates))
+	for i := range allIDs {
+		allIDs[i] = i
+	}
+
+	var total float32
+	for _, query := range queries {
+		qNorm := squaredNorm(query)
+		dot := q.dotTable(query)
+		exactScore := func(i int) float32 { return metric.Similarity(query, candidates[i]) }
+
+		exact := make(map[int]bool, recallK)
+		for _, s := range rank(allIDs, exactScore)[:recallK] {
+			exact[s.idx] = true
+		}
+		approx := rank(allIDs, func(i int) float32 { return metric.fromDot(dot(codes[i]), qNorm, norms[i]) })
+		if rescore > recallK {
+			ids := make([]int, 0, rescore)
+			for _, s := range approx[:min(rescore, len(approx))] {
+				ids = append(ids, s.idx)
+			}
+			approx = rank(ids, exactScore)
+		}
+
+		hits := 0
+		for _, s := range approx[:recallK] {
+			if exact[s.idx] {
+				hits++
+			}
+		}
+		total += float32(hits) / recallK
+	}
+	return total / float32(len(queries))
+}
+
+func squaredNorm(v []float32) float32 {
+	var sum float32
+	for _, x := range v {
+		sum += x * x
+	}
+	return sum
+}
+
+// =============================================================================
+// Quantized State
+// =============================================================================
+
+// QuantizedState is the persisted form of a trained quantizer. Codes holds
+// the code of every vector whose full-precision vector was dropped.
+type QuantizedState struct {
+	Quantization Quantization      `json:"quantization"`
+	Codebook     []byte            `json:"codebook"`
+	Recall       float32           `json:"recall"`
+	Codes        map[uint64][]byte `json:"codes,omitempty"`
+}
+
+// QuantizedState returns the trained quantizer and the codes of vectors
+// held only in quantized form, or nil while the index is not quantized
+func (h *HNSWIndex) QuantizedState() *QuantizedState {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.quant == nil {
+		return nil
+	}
+	state := &QuantizedState{
+		Quantization: h.quant.kind(),
+		Codebook:     h.quant.marshal(),
+		Recall:       h.recall,
+	}
+	for id, node := range h.nodes {
+		if node.vector == nil && node.code != nil && !node.deleted {
+			if state.Codes == nil {
+				state.Codes = make(map[uint64][]byte)
+			}
+			state.Codes[id] = node.code
+		}
+	}
+	return state
+}
+
+// RestoreQuantized installs a persisted quantizer into an empty index and
+// re-adds the vectors stored as codes. Vectors added afterwards are encoded
+// with the restored codebook, so their codes match the persisted ones.
+func (h *HNSWIndex) RestoreQuantized(state *QuantizedState) error {
+	q, err := unmarshalQuantizer(state.Quantization, h.dimension, state.Codebook)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	if len(h.nodes) > 0 {
+		h.mu.Unlock()
+		return fmt.Errorf("restore quantizer: index is not empty")
+	}
+	h.quant = q
+	h.recall = state.Recall
+	h.mu.Unlock()
+
+	for id, code := range state.Codes {
+		if len(code) != q.codeSize() {
+			return fmt.Errorf("vector %d has wrong code size: expected %d, got %d", id, q.codeSize(), len(code))
+		}
+		if err := h.Add(id, q.decode(code)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+package vector
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// =============================================================================
+// Recall Self-Check
+// =============================================================================
+
+// Recall self-check defaults and bounds
+const (
+	DefaultRecallK       = 10
+	DefaultRecallSamples = 100
+	MaxRecallSamples     = 10000
+)
+
+// RecallParams configures a recall self-check
+type RecallParams struct {
+	K       int         // neighbors compared per query (0 = DefaultRecallK)
+	Ef      int         // HNSW candidate list size (0 = index default)
+	Samples int         // stored vectors sampled as queries (0 = DefaultRecallSamples)
+	Queries [][]float32 // query vectors; when set nothing is sampled
+}
+
+// Distribution summarises a per-query measurement
+type Distribution struct {
+	Avg float64
+	P50 float64
+	P95 float64
+	P99 float64
+	Max float64
+}
+
+// RecallReport compares approximate search with exact search over the
+// same vectors
+type RecallReport struct {
+	Queries     int
+	K           int
+	Ef          int
+	Recall      float64      // mean recall@k
+	MinRecall   float64      // lowest recall@k of a single query
+	Micros      Distribution // approximate search latency
+	ExactMicros Distribution // exact search latency
+	Visited     Distribution // nodes scored per approximate search
+}
+
+// CheckRecall runs each query through HNSW search and an exact scan of the
+// index and reports recall@k, latency and nodes visited. Without queries
+// it samples stored vectors. The exact scan uses full-precision vectors
+// where they are kept and the quantized codes otherwise.
+func (h *HNSWIndex) CheckRecall(params RecallParams) (RecallReport, error) {
+	k, err := params.validate(h.dimension)
+	if err != nil {
+		return RecallReport{}, err
+	}
+	ef := params.Ef
+	if ef <= 0 {
+		ef = h.Config().EfSearch
+	}
+	queries := params.Queries
+	if len(queries) == 0 {
+		queries = h.sampleVectors(params.samples())
+	}
+
+	report := RecallReport{Queries: len(queries), K: k, Ef: ef, MinRecall: 1}
+	recalls := make([]float64, 0, len(queries))
+	micros := make([]float64, 0, len(queries))
+	exactMicros := make([]float64, 0, len(queries))
+	visited := make([]float64, 0, len(queries))
+	for _, query := range queries {
+		h.mu.RLock()
+		if len(h.nodes) == h.tombstones {
+			h.mu.RUnlock()
+			break
+		}
+		scorer := h.scorer(query)
+		start := time.Now()
+		approx := h.searchLocked(scorer, k, max(ef, k), nil)
+		micros = append(micros, sinceMicros(start))
+		visited = append(visited, float64(scorer.visited))
+
+		start = time.Now()
+		exact := h.scanFiltered(h.scorer(query), k, h.liveFilter(nil))
+		exactMicros = append(exactMicros, sinceMicros(start))
+		h.mu.RUnlock()
+
+		recalls = append(recalls, overlapRecall(approx, exact))
+	}
+
+	report.Queries = len(recalls)
+	if len(recalls) == 0 {
+		report.Recall = 1
+		return report, nil
+	}
+	for _, r := range recalls {
+		report.Recall += r
+		report.MinRecall = math.Min(report.MinRecall, r)
+	}
+	report.Recall /= float64(len(recalls))
+	report.Micros = distribution(micros)
+	report.ExactMicros = distribution(exactMicros)
+	report.Visited = distribution(visited)
+	return report, nil
+}
+
+// sampleVectors returns up to n randomly chosen live vectors
+func (h *HNSWIndex) sampleVectors(n int) [][]float32 {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	ids := make([]uint64, 0, len(h.nodes))
+	for id, node := range h.nodes {
+		if !node.deleted {
+			ids = append(ids, id)
+		}
+	}
+	rand.Shuffle(len(ids), func(i, j int) { ids[i], ids[j] = ids[j], ids[i] })
+	if len(ids) > n {
+		ids = ids[:n]
+	}
+	vectors := make([][]float32, len(ids))
+	for i, id := range ids {
+		vectors[i] = copyVector(h.nodeVector(h.nodes[id]))
+	}
+	return vectors
+}
+
+// CheckRecall reports exact search against itself: recall is always 1 and
+// every vector is visited
+func (b *BruteForceIndex) CheckRecall(params RecallParams) (RecallReport, error) {
+	k, err := params.validate(b.dimension)
+	if err != nil {
+		return RecallReport{}, err
+	}
+	queries := params.Queries
+	if len(queries) == 0 {
+		vectors := b.GetAllVectors()
+		for _, vec := range vectors {
+			if len(queries) == params.samples() {
+				break
+			}
+			queries = append(queries, vec)
+		}
+	}
+
+	report := RecallReport{Queries: len(queries), K: k, Recall: 1, MinRecall: 1}
+	micros := make([]float64, 0, len(queries))
+	visited := make([]float64, 0, len(queries))
+	for _, query := range queries {
+		start := time.Now()
+		b.Search(query, k)
+		micros = append(micros, sinceMicros(start))
+		visited = append(visited, float64(b.Count()))
+	}
+	report.Micros = distribution(micros)
+	report.ExactMicros = report.Micros
+	report.Visited = distribution(visited)
+	return report, nil
+}
+
+// validate checks the parameters against the index dimension and returns
+// the effective k
+func (p RecallParams) validate(dimension int) (int, error) {
+	if p.K < 0 || p.K > MaxHNSWEf {
+		return 0, fmt.Errorf("k must be between 0 and %d, got %d", MaxHNSWEf, p.K)
+	}
+	if p.Ef < 0 || p.Ef > MaxHNSWEf {
+		return 0, fmt.Errorf("ef must be between 0 and %d, got %d", MaxHNSWEf, p.Ef)
+	}
+	if p.Samples < 0 || p.Samples > MaxRecallSamples {
+		return 0, fmt.Errorf("samples must be between 0 and %d, got %d", MaxRecallSamples, p.Samples)
+	}
+	if len(p.Queries) > MaxRecallSamples {
+		return 0, fmt.Errorf("too many query vectors: %d (max %d)", len(p.Queries), MaxRecallSamples)
+	}
+	for i, query := range p.Queries {
+		if len(query) != dimension {
+			return 0, fmt.Errorf("query vector %d has dimension %d, want %d", i, len(query), dimension)
+		}
+	}
+	if p.K == 0 {
+		return DefaultRecallK, nil
+	}
+	return p.K, nil
+}
+
+// samples returns the number of stored vectors to sample
+func (p RecallParams) samples() int {
+	if p.Samples == 0 {
+		return DefaultRecallSamples
+	}
+	return p.Samples
+}
+
+// overlapRecall returns the fraction of exact results found by approx
+func overlapRecall(approx, exact []SearchResult) float64 {
+	if len(exact) == 0 {
+		return 1
+	}
+	want := make(map[uint64]bool, len(exact))
+	for _, r := range exact {
+		want[r.ID] = true
+	}
+	found := 0
+	for _, r := range approx {
+		if want[r.ID] {
+			found++
+		}
+	}
+	return float64(found) / float64(len(exact))
+}
+
+// distribution summarises values; it sorts them in place
+func distribution(values []float64) Distribution {
+	if len(values) == 0 {
+		return Distribution{}
+	}
+	sort.Float64s(values)
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	rank := func(q float64) float64 {
+		i := int(math.Ceil(q*float64(len(values)))) - 1
+		return values[max(i, 0)]
+	}
+	return Distribution{
+		Avg: sum / float64(len(values)),
+		P50: rank(0.50),
+		P95: rank(0.95),
+		P99: rank(0.99),
+		Max: values[len(values)-1],
+	}
+}
+
+// sinceMicros returns the time elapsed since start in microseconds
+func sinceMicros(start time.Time) float64 {
+	return float64(time.Since(start).Nanoseconds()) / 1e3
+}
@@ -58,19 +58,27 @@ const (
 	CommandType_CMD_DELETE_RELATIONSHIP   CommandType = 42
 	CommandType_CMD_RELATIONSHIP_RESPONSE CommandType = 43
 	// Community (50-59)
-	CommandType_CMD_ADD_COMMUNITY        CommandType = 50
-	CommandType_CMD_GET_COMMUNITY        CommandType = 51
-	CommandType_CMD_DELETE_COMMUNITY     CommandType = 52
-	CommandType_CMD_COMPUTE_COMMUNITIES  CommandType = 53
-	CommandType_CMD_HIERARCHICAL_LEIDEN  CommandType = 54
-	CommandType_CMD_REBUILD_INDEX        CommandType = 55
-	CommandType_CMD_COMMUNITY_RESPONSE   CommandType = 56
-	CommandType_CMD_COMMUNITIES_RESPONSE CommandType = 57
+	CommandType_CMD_ADD_COMMUNITY           CommandType = 50
+	CommandType_CMD_GET_COMMUNITY           CommandType = 51
+	CommandType_CMD_DELETE_COMMUNITY        CommandType = 52
+	CommandType_CMD_COMPUTE_COMMUNITIES     CommandType = 53
+	CommandType_CMD_HIERARCHICAL_LEIDEN     CommandType = 54
+	CommandType_CMD_REBUILD_INDEX           CommandType = 55
+	CommandType_CMD_COMMUNITY_RESPONSE      CommandType = 56
+	CommandType_CMD_COMMUNITIES_RESPONSE    CommandType = 57
+	CommandType_CMD_REBUILD_STATUS          CommandType = 58
+	CommandType_CMD_REBUILD_STATUS_RESPONSE CommandType = 59
 	// Query (60-69)
-	CommandType_CMD_QUERY            CommandType = 60
-	CommandType_CMD_QUERY_RESPONSE   CommandType = 61
-	CommandType_CMD_EXPLAIN          CommandType = 62
-	CommandType_CMD_EXPLAIN_RESPONSE CommandType = 63
+	CommandType_CMD_QUERY             CommandType = 60
+	CommandType_CMD_QUERY_RESPONSE    CommandType = 61
+	CommandType_CMD_EXPLAIN           CommandType = 62
+	CommandType_CMD_EXPLAIN_RESPONSE  CommandType = 63
+	CommandType_CMD_SEARCH            CommandType = 64
+	CommandType_CMD_SEARCH_RESPONSE   CommandType = 65
+	CommandType_CMD_PATH              CommandType = 66
+	CommandType_CMD_PATH_RESPONSE     CommandType = 67
+	CommandType_CMD_SUBGRAPH          CommandType = 68
+	CommandType_CMD_SUBGRAPH_RESPONSE CommandType = 69
 	// Session Management (70-79) - replaces per-object TTL
 	CommandType_CMD_LIST_SESSIONS         CommandType = 70
 	CommandType_CMD_DELETE_SESSION        CommandType = 71
@@ -79,6 +87,8 @@ const (
 	CommandType_CMD_TOUCH_SESSION         CommandType = 74
 	CommandType_CMD_SESSIONS_RESPONSE     CommandType = 75
 	CommandType_CMD_SESSION_INFO_RESPONSE CommandType = 76
+	CommandType_CMD_CREATE_SESSION        CommandType = 77
+	CommandType_CMD_SET_QUERY_CACHE       CommandType = 78
 	// Bulk Operations (80-99)
 	CommandType_CMD_MSET_ENTITIES          CommandType = 80
 	CommandType_CMD_MGET_ENTITIES          CommandType = 81
@@ -94,6 +104,8 @@ const (
 	CommandType_CMD_RELATIONSHIPS_RESPONSE CommandType = 91
 	CommandType_CMD_LIST_ENTITIES          CommandType = 92
 	CommandType_CMD_LIST_RELATIONSHIPS     CommandType = 93
+	CommandType_CMD_BULK_BEGIN             CommandType = 94
+	CommandType_CMD_BULK_COMMIT            CommandType = 95
 	// Pipeline (100-109)
 	CommandType_CMD_PIPELINE          CommandType = 100
 	CommandType_CMD_PIPELINE_RESPONSE CommandType = 101
@@ -111,6 +123,20 @@ const (
 	// Auth (120-129)
 	CommandType_CMD_AUTH          CommandType = 120
 	CommandType_CMD_AUTH_RESPONSE CommandType = 121
+	// Diagnostics (130-139)
+	CommandType_CMD_SLOWLOG_GET           CommandType = 130
+	CommandType_CMD_SLOWLOG_RESET         CommandType = 131
+	CommandType_CMD_SLOWLOG_LEN           CommandType = 132
+	CommandType_CMD_SLOWLOG_RESPONSE      CommandType = 133
+	CommandType_CMD_RECALL_CHECK          CommandType = 134
+	CommandType_CMD_RECALL_CHECK_RESPONSE CommandType = 135
+	// Graph Query (140-149)
+	CommandType_CMD_QUERY_GRAPH            CommandType = 140
+	CommandType_CMD_QUERY_GRAPH_RESPONSE   CommandType = 141
+	CommandType_CMD_GLOBAL_SEARCH          CommandType = 142
+	CommandType_CMD_GLOBAL_SEARCH_RESPONSE CommandType = 143
+	CommandType_CMD_QUERY_BATCH            CommandType = 144
+	CommandType_CMD_QUERY_BATCH_RESPONSE   CommandType = 145
 )
 
 // Enum value maps for CommandType.
@@ -152,10 +178,18 @@ var (
 		55:  "CMD_REBUILD_INDEX",
 		56:  "CMD_COMMUNITY_RESPONSE",
 		57:  "CMD_COMMUNITIES_RESPONSE",
+		58:  "CMD_REBUILD_STATUS",
+		59:  "CMD_REBUILD_STATUS_RESPONSE",
 		60:  "CMD_QUERY",
 		61:  "CMD_QUERY_RESPONSE",
 		62:  "CMD_EXPLAIN",
 		63:  "CMD_EXPLAIN_RESPONSE",
+		64:  "CMD_SEARCH",
+		65:  "CMD_SEARCH_RESPONSE",
+		66:  "CMD_PATH",
+		67:  "CMD_PATH_RESPONSE",
+		68:  "CMD_SUBGRAPH",
+		69:  "CMD_SUBGRAPH_RESPONSE",
 		70:  "CMD_LIST_SESSIONS",
 		71:  "CMD_DELETE_SESSION",
 		72:  "CMD_SESSION_INFO",
@@ -163,6 +197,8 @@ var (
 		74:  "CMD_TOUCH_SESSION",
 		75:  "CMD_SESSIONS_RESPONSE",
 		76:  "CMD_SESSION_INFO_RESPONSE",
+		77:  "CMD_CREATE_SESSION",
+		78:  "CMD_SET_QUERY_CACHE",
 		80:  "CMD_MSET_ENTITIES",
 		81:  "CMD_MGET_ENTITIES",
 		82:  "CMD_MSET_DOCUMENTS",
@@ -177,6 +213,8 @@ var (
 		91:  "CMD_RELATIONSHIPS_RESPONSE",
 		92:  "CMD_LIST_ENTITIES",
 		93:  "CMD_LIST_RELATIONSHIPS",
+		94:  "CMD_BULK_BEGIN",
+		95:  "CMD_BULK_COMMIT",
 		100: "CMD_PIPELINE",
 		101: "CMD_PIPELINE_RESPONSE",
 		110: "CMD_BGSAVE",
@@ -191,83 +229,119 @@ var (
 		119: "CMD_BACKUP_RESPONSE",
 		120: "CMD_AUTH",
 		121: "CMD_AUTH_RESPONSE",
+		130: "CMD_SLOWLOG_GET",
+		131: "CMD_SLOWLOG_RESET",
+		132: "CMD_SLOWLOG_LEN",
+		133: "CMD_SLOWLOG_RESPONSE",
+		134: "CMD_RECALL_CHECK",
+		135: "CMD_RECALL_CHECK_RESPONSE",
+		140: "CMD_QUERY_GRAPH",
+		141: "CMD_QUERY_GRAPH_RESPONSE",
+		142: "CMD_GLOBAL_SEARCH",
+		143: "CMD_GLOBAL_SEARCH_RESPONSE",
+		144: "CMD_QUERY_BATCH",
+		145: "CMD_QUERY_BATCH_RESPONSE",
 	}
 	CommandType_value = map[string]int32{
-		"CMD_UNKNOWN":                0,
-		"CMD_PING":                   1,
-		"CMD_PONG":                   2,
-		"CMD_INFO":                   3,
-		"CMD_INFO_RESPONSE":          4,
-		"CMD_ERROR":                  5,
-		"CMD_OK":                     6,
-		"CMD_HEALTH":                 7,
-		"CMD_HEALTH_RESPONSE":        8,
-		"CMD_ADD_DOCUMENT":           10,
-		"CMD_GET_DOCUMENT":           11,
-		"CMD_DELETE_DOCUMENT":        12,
-		"CMD_DOCUMENT_RESPONSE":      13,
-		"CMD_ADD_TEXTUNIT":           20,
-		"CMD_GET_TEXTUNIT":           21,
-		"CMD_DELETE_TEXTUNIT":        22,
-		"CMD_LINK_TEXTUNIT_ENTITY":   23,
-		"CMD_TEXTUNIT_RESPONSE":      24,
-		"CMD_ADD_ENTITY":             30,
-		"CMD_GET_ENTITY":             31,
-		"CMD_GET_ENTITY_BY_TITLE":    32,
-		"CMD_UPDATE_ENTITY_DESC":     33,
-		"CMD_DELETE_ENTITY":          34,
-		"CMD_ENTITY_RESPONSE":        35,
-		"CMD_ADD_RELATIONSHIP":       40,
-		"CMD_GET_RELATIONSHIP":       41,
-		"CMD_DELETE_RELATIONSHIP":    42,
-		"CMD_RELATIONSHIP_RESPONSE":  43,
-		"CMD_ADD_COMMUNITY":          50,
-		"CMD_GET_COMMUNITY":          51,
-		"CMD_DELETE_COMMUNITY":       52,
-		"CMD_COMPUTE_COMMUNITIES":    53,
-		"CMD_HIERARCHICAL_LEIDEN":    54,
-		"CMD_REBUILD_INDEX":          55,
-		"CMD_COMMUNITY_RESPONSE":     56,
-		"CMD_COMMUNITIES_RESPONSE":   57,
-		"CMD_QUERY":                  60,
-		"CMD_QUERY_RESPONSE":         61,
-		"CMD_EXPLAIN":                62,
-		"CMD_EXPLAIN_RESPONSE":       63,
-		"CMD_LIST_SESSIONS":          70,
-		"CMD_DELETE_SESSION":         71,
-		"CMD_SESSION_INFO":           72,
-		"CMD_SET_SESSION_TTL":        73,
-		"CMD_TOUCH_SESSION":          74,
-		"CMD_SESSIONS_RESPONSE":      75,
-		"CMD_SESSION_INFO_RESPONSE":  76,
-		"CMD_MSET_ENTITIES":          80,
-		"CMD_MGET_ENTITIES":          81,
-		"CMD_MSET_DOCUMENTS":         82,
-		"CMD_MGET_DOCUMENTS":         83,
-		"CMD_MSET_TEXTUNITS":         84,
-		"CMD_MGET_TEXTUNITS":         85,
-		"CMD_MSET_RELATIONSHIPS":     86,
-		"CMD_MGET_RELATIONSHIPS":     87,
-		"CMD_ENTITIES_RESPONSE":      88,
-		"CMD_DOCUMENTS_RESPONSE":     89,
-		"CMD_TEXTUNITS_RESPONSE":     90,
-		"CMD_RELATIONSHIPS_RESPONSE": 91,
-		"CMD_LIST_ENTITIES":          92,
-		"CMD_LIST_RELATIONSHIPS":     93,
-		"CMD_PIPELINE":               100,
-		"CMD_PIPELINE_RESPONSE":      101,
-		"CMD_BGSAVE":                 110,
-		"CMD_SAVE":                   111,
-		"CMD_LASTSAVE":               112,
-		"CMD_BGRESTORE":              113,
-		"CMD_BACKUP_STATUS":          114,
-		"CMD_WAL_CHECKPOINT":         115,
-		"CMD_WAL_TRUNCATE":           116,
-		"CMD_WAL_ROTATE":             117,
-		"CMD_WAL_STATUS":             118,
-		"CMD_BACKUP_RESPONSE":        119,
-		"CMD_AUTH":                   120,
-		"CMD_AUTH_RESPONSE":          121,
+		"CMD_UNKNOWN":                 0,
+		"CMD_PING":                    1,
+		"CMD_PONG":                    2,
+		"CMD_INFO":                    3,
+		"CMD_INFO_RESPONSE":           4,
+		"CMD_ERROR":                   5,
+		"CMD_OK":                      6,
+		"CMD_HEALTH":                  7,
+		"CMD_HEALTH_RESPONSE":         8,
+		"CMD_ADD_DOCUMENT":            10,
+		"CMD_GET_DOCUMENT":            11,
+		"CMD_DELETE_DOCUMENT":         12,
+		"CMD_DOCUMENT_RESPONSE":       13,
+		"CMD_ADD_TEXTUNIT":            20,
+		"CMD_GET_TEXTUNIT":            21,
+		"CMD_DELETE_TEXTUNIT":         22,
+		"CMD_LINK_TEXTUNIT_ENTITY":    23,
+		"CMD_TEXTUNIT_RESPONSE":       24,
+		"CMD_ADD_ENTITY":              30,
+		"CMD_GET_ENTITY":              31,
+		"CMD_GET_ENTITY_BY_TITLE":     32,
+		"CMD_UPDATE_ENTITY_DESC":      33,
+		"CMD_DELETE_ENTITY":           34,
+		"CMD_ENTITY_RESPONSE":         35,
+		"CMD_ADD_RELATIONSHIP":        40,
+		"CMD_GET_RELATIONSHIP":        41,
+		"CMD_DELETE_RELATIONSHIP":     42,
+		"CMD_RELATIONSHIP_RESPONSE":   43,
+		"CMD_ADD_COMMUNITY":           50,
+		"CMD_GET_COMMUNITY":           51,
+		"CMD_DELETE_COMMUNITY":        52,
+		"CMD_COMPUTE_COMMUNITIES":     53,
+		"CMD_HIERARCHICAL_LEIDEN":     54,
+		"CMD_REBUILD_INDEX":           55,
+		"CMD_COMMUNITY_RESPONSE":      56,
+		"CMD_COMMUNITIES_RESPONSE":    57,
+		"CMD_REBUILD_STATUS":          58,
+		"CMD_REBUILD_STATUS_RESPONSE": 59,
+		"CMD_QUERY":                   60,
+		"CMD_QUERY_RESPONSE":          61,
+		"CMD_EXPLAIN":                 62,
+		"CMD_EXPLAIN_RESPONSE":        63,
+		"CMD_SEARCH":                  64,
+		"CMD_SEARCH_RESPONSE":         65,
+		"CMD_PATH":                    66,
+		"CMD_PATH_RESPONSE":           67,
+		"CMD_SUBGRAPH":                68,
+		"CMD_SUBGRAPH_RESPONSE":       69,
+		"CMD_LIST_SESSIONS":           70,
+		"CMD_DELETE_SESSION":          71,
+		"CMD_SESSION_INFO":            72,
+		"CMD_SET_SESSION_TTL":         73,
+		"CMD_TOUCH_SESSION":           74,
+		"CMD_SESSIONS_RESPONSE":       75,
+		"CMD_SESSION_INFO_RESPONSE":   76,
+		"CMD_CREATE_SESSION":          77,
+		"CMD_SET_QUERY_CACHE":         78,
+		"CMD_MSET_ENTITIES":           80,
+		"CMD_MGET_ENTITIES":           81,
+		"CMD_MSET_DOCUMENTS":          82,
+		"CMD_MGET_DOCUMENTS":          83,
+		"CMD_MSET_TEXTUNITS":          84,
+		"CMD_MGET_TEXTUNITS":          85,
+		"CMD_MSET_RELATIONSHIPS":      86,
+		"CMD_MGET_RELATIONSHIPS":      87,
+		"CMD_ENTITIES_RESPONSE":       88,
+		"CMD_DOCUMENTS_RESPONSE":      89,
+		"CMD_TEXTUNITS_RESPONSE":      90,
+		"CMD_RELATIONSHIPS_RESPONSE":  91,
+		"CMD_LIST_ENTITIES":           92,
+		"CMD_LIST_RELATIONSHIPS":      93,
+		"CMD_BULK_BEGIN":              94,
+		"CMD_BULK_COMMIT":             95,
+		"CMD_PIPELINE":                100,
+		"CMD_PIPELINE_RESPONSE":       101,
+		"CMD_BGSAVE":                  110,
+		"CMD_SAVE":                    111,
+		"CMD_LASTSAVE":                112,
+		"CMD_BGRESTORE":               113,
+		"CMD_BACKUP_STATUS":           114,
+		"CMD_WAL_CHECKPOINT":          115,
+		"CMD_WAL_TRUNCATE":            116,
+		"CMD_WAL_ROTATE":              117,
+		"CMD_WAL_STATUS":              118,
+		"CMD_BACKUP_RESPONSE":         119,
+		"CMD_AUTH":                    120,
+		"CMD_AUTH_RESPONSE":           121,
+		"CMD_SLOWLOG_GET":             130,
+		"CMD_SLOWLOG_RESET":           131,
+		"CMD_SLOWLOG_LEN":             132,
+		"CMD_SLOWLOG_RESPONSE":        133,
+		"CMD_RECALL_CHECK":            134,
+		"CMD_RECALL_CHECK_RESPONSE":   135,
+		"CMD_QUERY_GRAPH":             140,
+		"CMD_QUERY_GRAPH_RESPONSE":    141,
+		"CMD_GLOBAL_SEARCH":           142,
+		"CMD_GLOBAL_SEARCH_RESPONSE":  143,
+		"CMD_QUERY_BATCH":             144,
+		"CMD_QUERY_BATCH_RESPONSE":    145,
 	}
 )
 
@@ -516,8 +590,15 @@ type InfoResponse struct {
 	CommunityCount    uint64                 `protobuf:"varint,6,opt,name=community_count,json=communityCount,proto3" json:"community_count,omitempty"`
 	VectorDim         int32                  `protobuf:"varint,7,opt,name=vector_dim,json=vectorDim,proto3" json:"vector_dim,omitempty"`
 	SessionCount      int32                  `protobuf:"varint,8,opt,name=session_count,json=sessionCount,proto3" json:"session_count,omitempty"` // number of active sessions
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+	// Vector memory and quantization trade-off
+	VectorCount              uint64  `protobuf:"varint,9,opt,name=vector_count,json=vectorCount,proto3" json:"vector_count,omitempty"`
+	QuantizedVectorCount     uint64  `protobuf:"varint,10,opt,name=quantized_vector_count,json=quantizedVectorCount,proto3" json:"quantized_vector_count,omitempty"`
+	VectorMemoryBytes        uint64  `protobuf:"varint,11,opt,name=vector_memory_bytes,json=vectorMemoryBytes,proto3" json:"vector_memory_bytes,omitempty"`                        // vectors, codes and codebooks
+	VectorFullPrecisionBytes uint64  `protobuf:"varint,12,opt,name=vector_full_precision_bytes,json=vectorFullPrecisionBytes,proto3" json:"vector_full_precision_bytes,omitempty"` // the same vectors as float32
+	QuantizationRecall       float32 `protobuf:"fixed32,13,opt,name=quantization_recall,json=quantizationRecall,proto3" json:"quantization_recall,omitempty"`                      // estimated recall@10 (1 = exact)
+	VectorTombstones         uint64  `protobuf:"varint,14,opt,name=vector_tombstones,json=vectorTombstones,proto3" json:"vector_tombstones,omitempty"`                             // deleted vectors awaiting compaction
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
 }
 
 func (x *InfoResponse) Reset() {
@@ -606,6 +687,48 @@ func (x *InfoResponse) GetSessionCount() int32 {
 	return 0
 }
 
+func (x *InfoResponse) GetVectorCount() uint64 {
+	if x != nil {
+		return x.VectorCount
+	}
+	return 0
+}
+
+func (x *InfoResponse) GetQuantizedVectorCount() uint64 {
+	if x != nil {
+		return x.QuantizedVectorCount
+	}
+	return 0
+}
+
+func (x *InfoResponse) GetVectorMemoryBytes() uint64 {
+	if x != nil {
+		return x.VectorMemoryBytes
+	}
+	return 0
+}
+
+func (x *InfoResponse) GetVectorFullPrecisionBytes() uint64 {
+	if x != nil {
+		return x.VectorFullPrecisionBytes
+	}
+	return 0
+}
+
+func (x *InfoResponse) GetQuantizationRecall() float32 {
+	if x != nil {
+		return x.QuantizationRecall
+	}
+	return 0
+}
+
+func (x *InfoResponse) GetVectorTombstones() uint64 {
+	if x != nil {
+		return x.VectorTombstones
+	}
+	return 0
+}
+
 type SessionInfo struct {
 	state             protoimpl.MessageState `protogen:"open.v1"`
 	SessionId         string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
@@ -618,6 +741,17 @@ type SessionInfo struct {
 	EntityCount       uint64                 `protobuf:"varint,8,opt,name=entity_count,json=entityCount,proto3" json:"entity_count,omitempty"`
 	RelationshipCount uint64                 `protobuf:"varint,9,opt,name=relationship_count,json=relationshipCount,proto3" json:"relationship_count,omitempty"`
 	CommunityCount    uint64                 `protobuf:"varint,10,opt,name=community_count,json=communityCount,proto3" json:"community_count,omitempty"`
+	TextunitIndex     *IndexOptions          `protobuf:"bytes,11,opt,name=textunit_index,json=textunitIndex,proto3" json:"textunit_index,omitempty"`
+	EntityIndex       *IndexOptions          `protobuf:"bytes,12,opt,name=entity_index,json=entityIndex,proto3" json:"entity_index,omitempty"`
+	CommunityIndex    *IndexOptions          `protobuf:"bytes,13,opt,name=community_index,json=communityIndex,proto3" json:"community_index,omitempty"`
+	VectorDim         uint32                 `protobuf:"varint,14,opt,name=vector_dim,json=vectorDim,proto3" json:"vector_dim,omitempty"`
+	BulkLoading       bool                   `protobuf:"varint,15,opt,name=bulk_loading,json=bulkLoading,proto3" json:"bulk_loading,omitempty"`            // vector insertion deferred by CMD_BULK_BEGIN
+	PendingVectors    uint64                 `protobuf:"varint,16,opt,name=pending_vectors,json=pendingVectors,proto3" json:"pending_vectors,omitempty"`   // deferred vectors not yet built
+	QueryCacheSize    uint32                 `protobuf:"varint,17,opt,name=query_cache_size,json=queryCacheSize,proto3" json:"query_cache_size,omitempty"` // 0 = query caching off
+	QueryCacheTtl     int64                  `protobuf:"varint,18,opt,name=query_cache_ttl,json=queryCacheTtl,proto3" json:"query_cache_ttl,omitempty"`    // seconds, 0 = no expiry
+	QueryCacheEntries uint64                 `protobuf:"varint,19,opt,name=query_cache_entries,json=queryCacheEntries,proto3" json:"query_cache_entries,omitempty"`
+	QueryCacheHits    uint64                 `protobuf:"varint,20,opt,name=query_cache_hits,json=queryCacheHits,proto3" json:"query_cache_hits,omitempty"`
+	QueryCacheMisses  uint64                 `protobuf:"varint,21,opt,name=query_cache_misses,json=queryCacheMisses,proto3" json:"query_cache_misses,omitempty"`
 	unknownFields     protoimpl.UnknownFields
 	sizeCache         protoimpl.SizeCache
 }
@@ -722,6 +856,262 @@ func (x *SessionInfo) GetCommunityCount() uint64 {
 	return 0
 }
 
+func (x *SessionInfo) GetTextunitIndex() *IndexOptions {
+	if x != nil {
+		return x.TextunitIndex
+	}
+	return nil
+}
+
+func (x *SessionInfo) GetEntityIndex() *IndexOptions {
+	if x != nil {
+		return x.EntityIndex
+	}
+	return nil
+}
+
+func (x *SessionInfo) GetCommunityIndex() *IndexOptions {
+	if x != nil {
+		return x.CommunityIndex
+	}
+	return nil
+}
+
+func (x *SessionInfo) GetVectorDim() uint32 {
+	if x != nil {
+		return x.VectorDim
+	}
+	return 0
+}
+
+func (x *SessionInfo) GetBulkLoading() bool {
+	if x != nil {
+		return x.BulkLoading
+	}
+	return false
+}
+
+func (x *SessionInfo) GetPendingVectors() uint64 {
+	if x != nil {
+		return x.PendingVectors
+	}
+	return 0
+}
+
+func (x *SessionInfo) GetQueryCacheSize() uint32 {
+	if x != nil {
+		return x.QueryCacheSize
+	}
+	return 0
+}
+
+func (x *SessionInfo) GetQueryCacheTtl() int64 {
+	if x != nil {
+		return x.QueryCacheTtl
+	}
+	return 0
+}
+
+func (x *SessionInfo) GetQueryCacheEntries() uint64 {
+	if x != nil {
+		return x.QueryCacheEntries
+	}
+	return 0
+}
+
+func (x *SessionInfo) GetQueryCacheHits() uint64 {
+	if x != nil {
+		return x.QueryCacheHits
+	}
+	return 0
+}
+
+func (x *SessionInfo) GetQueryCacheMisses() uint64 {
+	if x != nil {
+		return x.QueryCacheMisses
+	}
+	return 0
+}
+
+// IndexOptions configures one vector index of a session
+type IndexOptions struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Metric          string                 `protobuf:"bytes,1,opt,name=metric,proto3" json:"metric,omitempty"`                                             // "cosine" (default), "dot" or "l2"
+	M               uint32                 `protobuf:"varint,2,opt,name=m,proto3" json:"m,omitempty"`                                                      // HNSW max connections per node (0 = default)
+	EfConstruction  uint32                 `protobuf:"varint,3,opt,name=ef_construction,json=efConstruction,proto3" json:"ef_construction,omitempty"`      // HNSW build candidate list size (0 = default)
+	EfSearch        uint32                 `protobuf:"varint,4,opt,name=ef_search,json=efSearch,proto3" json:"ef_search,omitempty"`                        // HNSW search candidate list size (0 = default)
+	Quantization    string                 `protobuf:"bytes,5,opt,name=quantization,proto3" json:"quantization,omitempty"`                                 // "none" (default), "sq8" or "pq"
+	PqSubvectors    uint32                 `protobuf:"varint,6,opt,name=pq_subvectors,json=pqSubvectors,proto3" json:"pq_subvectors,omitempty"`            // product quantization subvectors (0 = auto)
+	KeepFullVectors bool                   `protobuf:"varint,7,opt,name=keep_full_vectors,json=keepFullVectors,proto3" json:"keep_full_vectors,omitempty"` // keep float32 vectors to rescore top candidates
+	TombstoneRatio  float64                `protobuf:"fixed64,8,opt,name=tombstone_ratio,json=tombstoneRatio,proto3" json:"tombstone_ratio,omitempty"`     // deleted share that starts compaction (0 = default)
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *IndexOptions) Reset() {
+	*x = IndexOptions{}
+	mi := &file_proto_gibram_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IndexOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IndexOptions) ProtoMessage() {}
+
+func (x *IndexOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IndexOptions.ProtoReflect.Descriptor instead.
+func (*IndexOptions) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *IndexOptions) GetMetric() string {
+	if x != nil {
+		return x.Metric
+	}
+	return ""
+}
+
+func (x *IndexOptions) GetM() uint32 {
+	if x != nil {
+		return x.M
+	}
+	return 0
+}
+
+func (x *IndexOptions) GetEfConstruction() uint32 {
+	if x != nil {
+		return x.EfConstruction
+	}
+	return 0
+}
+
+func (x *IndexOptions) GetEfSearch() uint32 {
+	if x != nil {
+		return x.EfSearch
+	}
+	return 0
+}
+
+func (x *IndexOptions) GetQuantization() string {
+	if x != nil {
+		return x.Quantization
+	}
+	return ""
+}
+
+func (x *IndexOptions) GetPqSubvectors() uint32 {
+	if x != nil {
+		return x.PqSubvectors
+	}
+	return 0
+}
+
+func (x *IndexOptions) GetKeepFullVectors() bool {
+	if x != nil {
+		return x.KeepFullVectors
+	}
+	return false
+}
+
+func (x *IndexOptions) GetTombstoneRatio() float64 {
+	if x != nil {
+		return x.TombstoneRatio
+	}
+	return 0
+}
+
+// CreateSessionRequest creates an empty session with explicit index options.
+// Sessions are otherwise created with defaults on first write.
+type CreateSessionRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SessionId      string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"` // optional, defaults to the envelope session
+	TextunitIndex  *IndexOptions          `protobuf:"bytes,2,opt,name=textunit_index,json=textunitIndex,proto3" json:"textunit_index,omitempty"`
+	EntityIndex    *IndexOptions          `protobuf:"bytes,3,opt,name=entity_index,json=entityIndex,proto3" json:"entity_index,omitempty"`
+	CommunityIndex *IndexOptions          `protobuf:"bytes,4,opt,name=community_index,json=communityIndex,proto3" json:"community_index,omitempty"`
+	VectorDim      uint32                 `protobuf:"varint,5,opt,name=vector_dim,json=vectorDim,proto3" json:"vector_dim,omitempty"` // 0 = server default (server.vector_dim)
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreateSessionRequest) Reset() {
+	*x = CreateSessionRequest{}
+	mi := &file_proto_gibram_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSessionRequest) ProtoMessage() {}
+
+func (x *CreateSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSessionRequest.ProtoReflect.Descriptor instead.
+func (*CreateSessionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CreateSessionRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *CreateSessionRequest) GetTextunitIndex() *IndexOptions {
+	if x != nil {
+		return x.TextunitIndex
+	}
+	return nil
+}
+
+func (x *CreateSessionRequest) GetEntityIndex() *IndexOptions {
+	if x != nil {
+		return x.EntityIndex
+	}
+	return nil
+}
+
+func (x *CreateSessionRequest) GetCommunityIndex() *IndexOptions {
+	if x != nil {
+		return x.CommunityIndex
+	}
+	return nil
+}
+
+func (x *CreateSessionRequest) GetVectorDim() uint32 {
+	if x != nil {
+		return x.VectorDim
+	}
+	return 0
+}
+
 type ListSessionsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Sessions      []*SessionInfo         `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`
@@ -731,7 +1121,7 @@ type ListSessionsResponse struct {
 
 func (x *ListSessionsResponse) Reset() {
 	*x = ListSessionsResponse{}
-	mi := &file_proto_gibram_proto_msgTypes[6]
+	mi := &file_proto_gibram_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -743,7 +1133,7 @@ func (x *ListSessionsResponse) String() string {
 func (*ListSessionsResponse) ProtoMessage() {}
 
 func (x *ListSessionsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[6]
+	mi := &file_proto_gibram_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -756,7 +1146,7 @@ func (x *ListSessionsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListSessionsResponse.ProtoReflect.Descriptor instead.
 func (*ListSessionsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{6}
+	return file_proto_gibram_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *ListSessionsResponse) GetSessions() []*SessionInfo {
@@ -775,7 +1165,7 @@ type DeleteSessionRequest struct {
 
 func (x *DeleteSessionRequest) Reset() {
 	*x = DeleteSessionRequest{}
-	mi := &file_proto_gibram_proto_msgTypes[7]
+	mi := &file_proto_gibram_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -787,7 +1177,7 @@ func (x *DeleteSessionRequest) String() string {
 func (*DeleteSessionRequest) ProtoMessage() {}
 
 func (x *DeleteSessionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[7]
+	mi := &file_proto_gibram_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -800,7 +1190,7 @@ func (x *DeleteSessionRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteSessionRequest.ProtoReflect.Descriptor instead.
 func (*DeleteSessionRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{7}
+	return file_proto_gibram_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *DeleteSessionRequest) GetSessionId() string {
@@ -819,7 +1209,7 @@ type SessionInfoRequest struct {
 
 func (x *SessionInfoRequest) Reset() {
 	*x = SessionInfoRequest{}
-	mi := &file_proto_gibram_proto_msgTypes[8]
+	mi := &file_proto_gibram_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -831,7 +1221,7 @@ func (x *SessionInfoRequest) String() string {
 func (*SessionInfoRequest) ProtoMessage() {}
 
 func (x *SessionInfoRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[8]
+	mi := &file_proto_gibram_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -844,7 +1234,7 @@ func (x *SessionInfoRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SessionInfoRequest.ProtoReflect.Descriptor instead.
 func (*SessionInfoRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{8}
+	return file_proto_gibram_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *SessionInfoRequest) GetSessionId() string {
@@ -865,7 +1255,7 @@ type SetSessionTTLRequest struct {
 
 func (x *SetSessionTTLRequest) Reset() {
 	*x = SetSessionTTLRequest{}
-	mi := &file_proto_gibram_proto_msgTypes[9]
+	mi := &file_proto_gibram_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -877,7 +1267,7 @@ func (x *SetSessionTTLRequest) String() string {
 func (*SetSessionTTLRequest) ProtoMessage() {}
 
 func (x *SetSessionTTLRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[9]
+	mi := &file_proto_gibram_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -890,7 +1280,7 @@ func (x *SetSessionTTLRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SetSessionTTLRequest.ProtoReflect.Descriptor instead.
 func (*SetSessionTTLRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{9}
+	return file_proto_gibram_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *SetSessionTTLRequest) GetSessionId() string {
@@ -914,28 +1304,29 @@ func (x *SetSessionTTLRequest) GetIdleTtl() int64 {
 	return 0
 }
 
-type TouchSessionRequest struct {
+type SetQueryCacheRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	MaxEntries    uint32                 `protobuf:"varint,1,opt,name=max_entries,json=maxEntries,proto3" json:"max_entries,omitempty"` // 0 = turn query caching off
+	Ttl           int64                  `protobuf:"varint,2,opt,name=ttl,proto3" json:"ttl,omitempty"`                                 // seconds, 0 = no expiry
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *TouchSessionRequest) Reset() {
-	*x = TouchSessionRequest{}
-	mi := &file_proto_gibram_proto_msgTypes[10]
+func (x *SetQueryCacheRequest) Reset() {
+	*x = SetQueryCacheRequest{}
+	mi := &file_proto_gibram_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *TouchSessionRequest) String() string {
+func (x *SetQueryCacheRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TouchSessionRequest) ProtoMessage() {}
+func (*SetQueryCacheRequest) ProtoMessage() {}
 
-func (x *TouchSessionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[10]
+func (x *SetQueryCacheRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -946,20 +1337,71 @@ func (x *TouchSessionRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TouchSessionRequest.ProtoReflect.Descriptor instead.
-func (*TouchSessionRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use SetQueryCacheRequest.ProtoReflect.Descriptor instead.
+func (*SetQueryCacheRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *TouchSessionRequest) GetSessionId() string {
+func (x *SetQueryCacheRequest) GetMaxEntries() uint32 {
 	if x != nil {
-		return x.SessionId
+		return x.MaxEntries
 	}
-	return ""
+	return 0
 }
 
-type Document struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+func (x *SetQueryCacheRequest) GetTtl() int64 {
+	if x != nil {
+		return x.Ttl
+	}
+	return 0
+}
+
+type TouchSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TouchSessionRequest) Reset() {
+	*x = TouchSessionRequest{}
+	mi := &file_proto_gibram_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TouchSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TouchSessionRequest) ProtoMessage() {}
+
+func (x *TouchSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TouchSessionRequest.ProtoReflect.Descriptor instead.
+func (*TouchSessionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *TouchSessionRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type Document struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	ExternalId    string                 `protobuf:"bytes,2,opt,name=external_id,json=externalId,proto3" json:"external_id,omitempty"`
 	Filename      string                 `protobuf:"bytes,3,opt,name=filename,proto3" json:"filename,omitempty"`
@@ -972,7 +1414,7 @@ type Document struct {
 
 func (x *Document) Reset() {
 	*x = Document{}
-	mi := &file_proto_gibram_proto_msgTypes[11]
+	mi := &file_proto_gibram_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -984,7 +1426,7 @@ func (x *Document) String() string {
 func (*Document) ProtoMessage() {}
 
 func (x *Document) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[11]
+	mi := &file_proto_gibram_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -997,7 +1439,7 @@ func (x *Document) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Document.ProtoReflect.Descriptor instead.
 func (*Document) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{11}
+	return file_proto_gibram_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *Document) GetId() uint64 {
@@ -1052,7 +1494,7 @@ type AddDocumentRequest struct {
 
 func (x *AddDocumentRequest) Reset() {
 	*x = AddDocumentRequest{}
-	mi := &file_proto_gibram_proto_msgTypes[12]
+	mi := &file_proto_gibram_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1064,7 +1506,7 @@ func (x *AddDocumentRequest) String() string {
 func (*AddDocumentRequest) ProtoMessage() {}
 
 func (x *AddDocumentRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[12]
+	mi := &file_proto_gibram_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1077,7 +1519,7 @@ func (x *AddDocumentRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AddDocumentRequest.ProtoReflect.Descriptor instead.
 func (*AddDocumentRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{12}
+	return file_proto_gibram_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *AddDocumentRequest) GetExternalId() string {
@@ -1109,7 +1551,7 @@ type TextUnit struct {
 
 func (x *TextUnit) Reset() {
 	*x = TextUnit{}
-	mi := &file_proto_gibram_proto_msgTypes[13]
+	mi := &file_proto_gibram_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1121,7 +1563,7 @@ func (x *TextUnit) String() string {
 func (*TextUnit) ProtoMessage() {}
 
 func (x *TextUnit) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[13]
+	mi := &file_proto_gibram_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1134,7 +1576,7 @@ func (x *TextUnit) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TextUnit.ProtoReflect.Descriptor instead.
 func (*TextUnit) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{13}
+	return file_proto_gibram_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *TextUnit) GetId() uint64 {
@@ -1199,7 +1641,7 @@ type AddTextUnitRequest struct {
 
 func (x *AddTextUnitRequest) Reset() {
 	*x = AddTextUnitRequest{}
-	mi := &file_proto_gibram_proto_msgTypes[14]
+	mi := &file_proto_gibram_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1211,7 +1653,7 @@ func (x *AddTextUnitRequest) String() string {
 func (*AddTextUnitRequest) ProtoMessage() {}
 
 func (x *AddTextUnitRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[14]
+	mi := &file_proto_gibram_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1224,7 +1666,7 @@ func (x *AddTextUnitRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AddTextUnitRequest.ProtoReflect.Descriptor instead.
 func (*AddTextUnitRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{14}
+	return file_proto_gibram_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *AddTextUnitRequest) GetExternalId() string {
@@ -1277,7 +1719,7 @@ type Entity struct {
 
 func (x *Entity) Reset() {
 	*x = Entity{}
-	mi := &file_proto_gibram_proto_msgTypes[15]
+	mi := &file_proto_gibram_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1289,7 +1731,7 @@ func (x *Entity) String() string {
 func (*Entity) ProtoMessage() {}
 
 func (x *Entity) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[15]
+	mi := &file_proto_gibram_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1302,7 +1744,7 @@ func (x *Entity) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Entity.ProtoReflect.Descriptor instead.
 func (*Entity) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{15}
+	return file_proto_gibram_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *Entity) GetId() uint64 {
@@ -1367,7 +1809,7 @@ type AddEntityRequest struct {
 
 func (x *AddEntityRequest) Reset() {
 	*x = AddEntityRequest{}
-	mi := &file_proto_gibram_proto_msgTypes[16]
+	mi := &file_proto_gibram_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1379,7 +1821,7 @@ func (x *AddEntityRequest) String() string {
 func (*AddEntityRequest) ProtoMessage() {}
 
 func (x *AddEntityRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[16]
+	mi := &file_proto_gibram_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1392,7 +1834,7 @@ func (x *AddEntityRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AddEntityRequest.ProtoReflect.Descriptor instead.
 func (*AddEntityRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{16}
+	return file_proto_gibram_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *AddEntityRequest) GetExternalId() string {
@@ -1439,7 +1881,7 @@ type GetEntityByTitleRequest struct {
 
 func (x *GetEntityByTitleRequest) Reset() {
 	*x = GetEntityByTitleRequest{}
-	mi := &file_proto_gibram_proto_msgTypes[17]
+	mi := &file_proto_gibram_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1451,7 +1893,7 @@ func (x *GetEntityByTitleRequest) String() string {
 func (*GetEntityByTitleRequest) ProtoMessage() {}
 
 func (x *GetEntityByTitleRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[17]
+	mi := &file_proto_gibram_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1464,7 +1906,7 @@ func (x *GetEntityByTitleRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetEntityByTitleRequest.ProtoReflect.Descriptor instead.
 func (*GetEntityByTitleRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{17}
+	return file_proto_gibram_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *GetEntityByTitleRequest) GetTitle() string {
@@ -1485,7 +1927,7 @@ type UpdateEntityDescRequest struct {
 
 func (x *UpdateEntityDescRequest) Reset() {
 	*x = UpdateEntityDescRequest{}
-	mi := &file_proto_gibram_proto_msgTypes[18]
+	mi := &file_proto_gibram_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1497,7 +1939,7 @@ func (x *UpdateEntityDescRequest) String() string {
 func (*UpdateEntityDescRequest) ProtoMessage() {}
 
 func (x *UpdateEntityDescRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[18]
+	mi := &file_proto_gibram_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1510,7 +1952,7 @@ func (x *UpdateEntityDescRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateEntityDescRequest.ProtoReflect.Descriptor instead.
 func (*UpdateEntityDescRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{18}
+	return file_proto_gibram_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *UpdateEntityDescRequest) GetId() uint64 {
@@ -1550,7 +1992,7 @@ type Relationship struct {
 
 func (x *Relationship) Reset() {
 	*x = Relationship{}
-	mi := &file_proto_gibram_proto_msgTypes[19]
+	mi := &file_proto_gibram_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1562,7 +2004,7 @@ func (x *Relationship) String() string {
 func (*Relationship) ProtoMessage() {}
 
 func (x *Relationship) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[19]
+	mi := &file_proto_gibram_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1575,7 +2017,7 @@ func (x *Relationship) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Relationship.ProtoReflect.Descriptor instead.
 func (*Relationship) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{19}
+	return file_proto_gibram_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *Relationship) GetId() uint64 {
@@ -1648,7 +2090,7 @@ type AddRelationshipRequest struct {
 
 func (x *AddRelationshipRequest) Reset() {
 	*x = AddRelationshipRequest{}
-	mi := &file_proto_gibram_proto_msgTypes[20]
+	mi := &file_proto_gibram_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1660,7 +2102,7 @@ func (x *AddRelationshipRequest) String() string {
 func (*AddRelationshipRequest) ProtoMessage() {}
 
 func (x *AddRelationshipRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[20]
+	mi := &file_proto_gibram_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1673,7 +2115,7 @@ func (x *AddRelationshipRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AddRelationshipRequest.ProtoReflect.Descriptor instead.
 func (*AddRelationshipRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{20}
+	return file_proto_gibram_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *AddRelationshipRequest) GetExternalId() string {
@@ -1735,7 +2177,7 @@ type Community struct {
 
 func (x *Community) Reset() {
 	*x = Community{}
-	mi := &file_proto_gibram_proto_msgTypes[21]
+	mi := &file_proto_gibram_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1747,7 +2189,7 @@ func (x *Community) String() string {
 func (*Community) ProtoMessage() {}
 
 func (x *Community) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[21]
+	mi := &file_proto_gibram_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1760,7 +2202,7 @@ func (x *Community) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Community.ProtoReflect.Descriptor instead.
 func (*Community) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{21}
+	return file_proto_gibram_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *Community) GetId() uint64 {
@@ -1842,7 +2284,7 @@ type AddCommunityRequest struct {
 
 func (x *AddCommunityRequest) Reset() {
 	*x = AddCommunityRequest{}
-	mi := &file_proto_gibram_proto_msgTypes[22]
+	mi := &file_proto_gibram_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1854,7 +2296,7 @@ func (x *AddCommunityRequest) String() string {
 func (*AddCommunityRequest) ProtoMessage() {}
 
 func (x *AddCommunityRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[22]
+	mi := &file_proto_gibram_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1867,7 +2309,7 @@ func (x *AddCommunityRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AddCommunityRequest.ProtoReflect.Descriptor instead.
 func (*AddCommunityRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{22}
+	return file_proto_gibram_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *AddCommunityRequest) GetExternalId() string {
@@ -1936,7 +2378,7 @@ type ComputeCommunitiesRequest struct {
 
 func (x *ComputeCommunitiesRequest) Reset() {
 	*x = ComputeCommunitiesRequest{}
-	mi := &file_proto_gibram_proto_msgTypes[23]
+	mi := &file_proto_gibram_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1948,7 +2390,7 @@ func (x *ComputeCommunitiesRequest) String() string {
 func (*ComputeCommunitiesRequest) ProtoMessage() {}
 
 func (x *ComputeCommunitiesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[23]
+	mi := &file_proto_gibram_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1961,7 +2403,7 @@ func (x *ComputeCommunitiesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ComputeCommunitiesRequest.ProtoReflect.Descriptor instead.
 func (*ComputeCommunitiesRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{23}
+	return file_proto_gibram_proto_rawDescGZIP(), []int{26}
 }
 
 func (x *ComputeCommunitiesRequest) GetResolution() float64 {
@@ -1988,7 +2430,7 @@ type ComputeCommunitiesResponse struct {
 
 func (x *ComputeCommunitiesResponse) Reset() {
 	*x = ComputeCommunitiesResponse{}
-	mi := &file_proto_gibram_proto_msgTypes[24]
+	mi := &file_proto_gibram_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2000,7 +2442,7 @@ func (x *ComputeCommunitiesResponse) String() string {
 func (*ComputeCommunitiesResponse) ProtoMessage() {}
 
 func (x *ComputeCommunitiesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[24]
+	mi := &file_proto_gibram_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2013,7 +2455,7 @@ func (x *ComputeCommunitiesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ComputeCommunitiesResponse.ProtoReflect.Descriptor instead.
 func (*ComputeCommunitiesResponse) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{24}
+	return file_proto_gibram_proto_rawDescGZIP(), []int{27}
 }
 
 func (x *ComputeCommunitiesResponse) GetCount() int32 {
@@ -2040,7 +2482,7 @@ type LinkTextUnitEntityRequest struct {
 
 func (x *LinkTextUnitEntityRequest) Reset() {
 	*x = LinkTextUnitEntityRequest{}
-	mi := &file_proto_gibram_proto_msgTypes[25]
+	mi := &file_proto_gibram_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2052,7 +2494,7 @@ func (x *LinkTextUnitEntityRequest) String() string {
 func (*LinkTextUnitEntityRequest) ProtoMessage() {}
 
 func (x *LinkTextUnitEntityRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[25]
+	mi := &file_proto_gibram_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2065,7 +2507,7 @@ func (x *LinkTextUnitEntityRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LinkTextUnitEntityRequest.ProtoReflect.Descriptor instead.
 func (*LinkTextUnitEntityRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{25}
+	return file_proto_gibram_proto_rawDescGZIP(), []int{28}
 }
 
 func (x *LinkTextUnitEntityRequest) GetTextunitId() uint64 {
@@ -2094,13 +2536,37 @@ type QueryRequest struct {
 	SeedEntityIds     []uint64               `protobuf:"varint,8,rep,packed,name=seed_entity_ids,json=seedEntityIds,proto3" json:"seed_entity_ids,omitempty"`
 	FilterEntityTypes []string               `protobuf:"bytes,9,rep,name=filter_entity_types,json=filterEntityTypes,proto3" json:"filter_entity_types,omitempty"`
 	FilterRelTypes    []string               `protobuf:"bytes,10,rep,name=filter_rel_types,json=filterRelTypes,proto3" json:"filter_rel_types,omitempty"`
+	FilterDocumentIds []uint64               `protobuf:"varint,11,rep,packed,name=filter_document_ids,json=filterDocumentIds,proto3" json:"filter_document_ids,omitempty"`                                                           // restrict text units to these documents
+	FilterAttrs       map[string]string      `protobuf:"bytes,12,rep,name=filter_attrs,json=filterAttrs,proto3" json:"filter_attrs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`             // entity attributes that must all match
+	QueryText         string                 `protobuf:"bytes,13,opt,name=query_text,json=queryText,proto3" json:"query_text,omitempty"`                                                                                             // enables hybrid BM25 + vector seeds
+	Fusion            string                 `protobuf:"bytes,14,opt,name=fusion,proto3" json:"fusion,omitempty"`                                                                                                                    // "rrf" (default) or "weighted"
+	LexicalWeight     float32                `protobuf:"fixed32,15,opt,name=lexical_weight,json=lexicalWeight,proto3" json:"lexical_weight,omitempty"`                                                                               // weighted fusion only (0 = 0.5)
+	Scoring           string                 `protobuf:"bytes,16,opt,name=scoring,proto3" json:"scoring,omitempty"`                                                                                                                  // "hop" (default) or "weighted"
+	Decay             float32                `protobuf:"fixed32,17,opt,name=decay,proto3" json:"decay,omitempty"`                                                                                                                    // weighted scoring only (0 = 0.5)
+	Aggregation       string                 `protobuf:"bytes,18,opt,name=aggregation,proto3" json:"aggregation,omitempty"`                                                                                                          // weighted scoring only: "max" (default) or "sum"
+	Expansion         string                 `protobuf:"bytes,19,opt,name=expansion,proto3" json:"expansion,omitempty"`                                                                                                              // "bfs" (default) or "ppr"
+	Damping           float32                `protobuf:"fixed32,20,opt,name=damping,proto3" json:"damping,omitempty"`                                                                                                                // ppr only (0 = 0.85)
+	Iterations        int32                  `protobuf:"varint,21,opt,name=iterations,proto3" json:"iterations,omitempty"`                                                                                                           // ppr only (0 = 20)
+	Restart           string                 `protobuf:"bytes,22,opt,name=restart,proto3" json:"restart,omitempty"`                                                                                                                  // ppr only: "score" (default) or "uniform"
+	RestartWeights    map[uint64]float32     `protobuf:"bytes,23,rep,name=restart_weights,json=restartWeights,proto3" json:"restart_weights,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"fixed32,2,opt,name=value"` // ppr only: restart distribution by entity ID
+	TokenBudget       int32                  `protobuf:"varint,24,opt,name=token_budget,json=tokenBudget,proto3" json:"token_budget,omitempty"`                                                                                      // pack results into this many tokens (0 = count limits)
+	EntityShare       float32                `protobuf:"fixed32,25,opt,name=entity_share,json=entityShare,proto3" json:"entity_share,omitempty"`                                                                                     // budget shares, all 0 = default split
+	RelationshipShare float32                `protobuf:"fixed32,26,opt,name=relationship_share,json=relationshipShare,proto3" json:"relationship_share,omitempty"`
+	TextunitShare     float32                `protobuf:"fixed32,27,opt,name=textunit_share,json=textunitShare,proto3" json:"textunit_share,omitempty"`
+	CommunityShare    float32                `protobuf:"fixed32,28,opt,name=community_share,json=communityShare,proto3" json:"community_share,omitempty"`
+	ContextFormat     string                 `protobuf:"bytes,29,opt,name=context_format,json=contextFormat,proto3" json:"context_format,omitempty"`       // "markdown" or "csv" renders context ("" = none)
+	Rerank            string                 `protobuf:"bytes,30,opt,name=rerank,proto3" json:"rerank,omitempty"`                                          // "none" (default) or "mmr"
+	MmrLambda         float32                `protobuf:"fixed32,31,opt,name=mmr_lambda,json=mmrLambda,proto3" json:"mmr_lambda,omitempty"`                 // mmr only (0 = 0.7)
+	DedupThreshold    float32                `protobuf:"fixed32,32,opt,name=dedup_threshold,json=dedupThreshold,proto3" json:"dedup_threshold,omitempty"`  // drop results this similar to a kept one (0 = off)
+	MaxPerDocument    int32                  `protobuf:"varint,33,opt,name=max_per_document,json=maxPerDocument,proto3" json:"max_per_document,omitempty"` // text units per document (0 = no cap)
+	NoCache           bool                   `protobuf:"varint,34,opt,name=no_cache,json=noCache,proto3" json:"no_cache,omitempty"`                        // bypass the session query cache
 	unknownFields     protoimpl.UnknownFields
 	sizeCache         protoimpl.SizeCache
 }
 
 func (x *QueryRequest) Reset() {
 	*x = QueryRequest{}
-	mi := &file_proto_gibram_proto_msgTypes[26]
+	mi := &file_proto_gibram_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2112,7 +2578,7 @@ func (x *QueryRequest) String() string {
 func (*QueryRequest) ProtoMessage() {}
 
 func (x *QueryRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[26]
+	mi := &file_proto_gibram_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2125,7 +2591,7 @@ func (x *QueryRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use QueryRequest.ProtoReflect.Descriptor instead.
 func (*QueryRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{26}
+	return file_proto_gibram_proto_rawDescGZIP(), []int{29}
 }
 
 func (x *QueryRequest) GetQueryVector() []float32 {
@@ -2198,137 +2664,322 @@ func (x *QueryRequest) GetFilterRelTypes() []string {
 	return nil
 }
 
-type TextUnitResult struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Textunit      *TextUnit              `protobuf:"bytes,1,opt,name=textunit,proto3" json:"textunit,omitempty"`
-	Similarity    float32                `protobuf:"fixed32,2,opt,name=similarity,proto3" json:"similarity,omitempty"`
-	Hop           int32                  `protobuf:"varint,3,opt,name=hop,proto3" json:"hop,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *QueryRequest) GetFilterDocumentIds() []uint64 {
+	if x != nil {
+		return x.FilterDocumentIds
+	}
+	return nil
 }
 
-func (x *TextUnitResult) Reset() {
-	*x = TextUnitResult{}
-	mi := &file_proto_gibram_proto_msgTypes[27]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *QueryRequest) GetFilterAttrs() map[string]string {
+	if x != nil {
+		return x.FilterAttrs
+	}
+	return nil
 }
 
-func (x *TextUnitResult) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *QueryRequest) GetQueryText() string {
+	if x != nil {
+		return x.QueryText
+	}
+	return ""
 }
 
-func (*TextUnitResult) ProtoMessage() {}
-
-func (x *TextUnitResult) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[27]
+func (x *QueryRequest) GetFusion() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Fusion
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use TextUnitResult.ProtoReflect.Descriptor instead.
-func (*TextUnitResult) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{27}
+func (x *QueryRequest) GetLexicalWeight() float32 {
+	if x != nil {
+		return x.LexicalWeight
+	}
+	return 0
 }
 
-func (x *TextUnitResult) GetTextunit() *TextUnit {
+func (x *QueryRequest) GetScoring() string {
 	if x != nil {
-		return x.Textunit
+		return x.Scoring
 	}
-	return nil
+	return ""
 }
 
-func (x *TextUnitResult) GetSimilarity() float32 {
+func (x *QueryRequest) GetDecay() float32 {
 	if x != nil {
-		return x.Similarity
+		return x.Decay
 	}
 	return 0
 }
 
-func (x *TextUnitResult) GetHop() int32 {
+func (x *QueryRequest) GetAggregation() string {
 	if x != nil {
-		return x.Hop
+		return x.Aggregation
 	}
-	return 0
+	return ""
 }
 
-type EntityResult struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Entity        *Entity                `protobuf:"bytes,1,opt,name=entity,proto3" json:"entity,omitempty"`
-	Similarity    float32                `protobuf:"fixed32,2,opt,name=similarity,proto3" json:"similarity,omitempty"`
-	Hop           int32                  `protobuf:"varint,3,opt,name=hop,proto3" json:"hop,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *QueryRequest) GetExpansion() string {
+	if x != nil {
+		return x.Expansion
+	}
+	return ""
 }
 
-func (x *EntityResult) Reset() {
-	*x = EntityResult{}
-	mi := &file_proto_gibram_proto_msgTypes[28]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *QueryRequest) GetDamping() float32 {
+	if x != nil {
+		return x.Damping
+	}
+	return 0
 }
 
-func (x *EntityResult) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *QueryRequest) GetIterations() int32 {
+	if x != nil {
+		return x.Iterations
+	}
+	return 0
 }
 
-func (*EntityResult) ProtoMessage() {}
-
-func (x *EntityResult) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[28]
+func (x *QueryRequest) GetRestart() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Restart
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use EntityResult.ProtoReflect.Descriptor instead.
-func (*EntityResult) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{28}
+func (x *QueryRequest) GetRestartWeights() map[uint64]float32 {
+	if x != nil {
+		return x.RestartWeights
+	}
+	return nil
 }
 
-func (x *EntityResult) GetEntity() *Entity {
+func (x *QueryRequest) GetTokenBudget() int32 {
 	if x != nil {
-		return x.Entity
+		return x.TokenBudget
 	}
-	return nil
+	return 0
 }
 
-func (x *EntityResult) GetSimilarity() float32 {
+func (x *QueryRequest) GetEntityShare() float32 {
 	if x != nil {
-		return x.Similarity
+		return x.EntityShare
 	}
 	return 0
 }
 
-func (x *EntityResult) GetHop() int32 {
+func (x *QueryRequest) GetRelationshipShare() float32 {
 	if x != nil {
-		return x.Hop
+		return x.RelationshipShare
 	}
 	return 0
 }
 
-type CommunityResult struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Community     *Community             `protobuf:"bytes,1,opt,name=community,proto3" json:"community,omitempty"`
-	Similarity    float32                `protobuf:"fixed32,2,opt,name=similarity,proto3" json:"similarity,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *QueryRequest) GetTextunitShare() float32 {
+	if x != nil {
+		return x.TextunitShare
+	}
+	return 0
+}
+
+func (x *QueryRequest) GetCommunityShare() float32 {
+	if x != nil {
+		return x.CommunityShare
+	}
+	return 0
+}
+
+func (x *QueryRequest) GetContextFormat() string {
+	if x != nil {
+		return x.ContextFormat
+	}
+	return ""
+}
+
+func (x *QueryRequest) GetRerank() string {
+	if x != nil {
+		return x.Rerank
+	}
+	return ""
+}
+
+func (x *QueryRequest) GetMmrLambda() float32 {
+	if x != nil {
+		return x.MmrLambda
+	}
+	return 0
+}
+
+func (x *QueryRequest) GetDedupThreshold() float32 {
+	if x != nil {
+		return x.DedupThreshold
+	}
+	return 0
+}
+
+func (x *QueryRequest) GetMaxPerDocument() int32 {
+	if x != nil {
+		return x.MaxPerDocument
+	}
+	return 0
+}
+
+func (x *QueryRequest) GetNoCache() bool {
+	if x != nil {
+		return x.NoCache
+	}
+	return false
+}
+
+type TextUnitResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Textunit      *TextUnit              `protobuf:"bytes,1,opt,name=textunit,proto3" json:"textunit,omitempty"`
+	Similarity    float32                `protobuf:"fixed32,2,opt,name=similarity,proto3" json:"similarity,omitempty"`
+	Hop           int32                  `protobuf:"varint,3,opt,name=hop,proto3" json:"hop,omitempty"`
+	Score         float32                `protobuf:"fixed32,4,opt,name=score,proto3" json:"score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TextUnitResult) Reset() {
+	*x = TextUnitResult{}
+	mi := &file_proto_gibram_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TextUnitResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TextUnitResult) ProtoMessage() {}
+
+func (x *TextUnitResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TextUnitResult.ProtoReflect.Descriptor instead.
+func (*TextUnitResult) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *TextUnitResult) GetTextunit() *TextUnit {
+	if x != nil {
+		return x.Textunit
+	}
+	return nil
+}
+
+func (x *TextUnitResult) GetSimilarity() float32 {
+	if x != nil {
+		return x.Similarity
+	}
+	return 0
+}
+
+func (x *TextUnitResult) GetHop() int32 {
+	if x != nil {
+		return x.Hop
+	}
+	return 0
+}
+
+func (x *TextUnitResult) GetScore() float32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+type EntityResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entity        *Entity                `protobuf:"bytes,1,opt,name=entity,proto3" json:"entity,omitempty"`
+	Similarity    float32                `protobuf:"fixed32,2,opt,name=similarity,proto3" json:"similarity,omitempty"`
+	Hop           int32                  `protobuf:"varint,3,opt,name=hop,proto3" json:"hop,omitempty"`
+	Score         float32                `protobuf:"fixed32,4,opt,name=score,proto3" json:"score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EntityResult) Reset() {
+	*x = EntityResult{}
+	mi := &file_proto_gibram_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EntityResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EntityResult) ProtoMessage() {}
+
+func (x *EntityResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EntityResult.ProtoReflect.Descriptor instead.
+func (*EntityResult) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *EntityResult) GetEntity() *Entity {
+	if x != nil {
+		return x.Entity
+	}
+	return nil
+}
+
+func (x *EntityResult) GetSimilarity() float32 {
+	if x != nil {
+		return x.Similarity
+	}
+	return 0
+}
+
+func (x *EntityResult) GetHop() int32 {
+	if x != nil {
+		return x.Hop
+	}
+	return 0
+}
+
+func (x *EntityResult) GetScore() float32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+type CommunityResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Community     *Community             `protobuf:"bytes,1,opt,name=community,proto3" json:"community,omitempty"`
+	Similarity    float32                `protobuf:"fixed32,2,opt,name=similarity,proto3" json:"similarity,omitempty"`
+	Score         float32                `protobuf:"fixed32,3,opt,name=score,proto3" json:"score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CommunityResult) Reset() {
 	*x = CommunityResult{}
-	mi := &file_proto_gibram_proto_msgTypes[29]
+	mi := &file_proto_gibram_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2340,7 +2991,7 @@ func (x *CommunityResult) String() string {
 func (*CommunityResult) ProtoMessage() {}
 
 func (x *CommunityResult) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[29]
+	mi := &file_proto_gibram_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2353,7 +3004,7 @@ func (x *CommunityResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CommunityResult.ProtoReflect.Descriptor instead.
 func (*CommunityResult) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{29}
+	return file_proto_gibram_proto_rawDescGZIP(), []int{32}
 }
 
 func (x *CommunityResult) GetCommunity() *Community {
@@ -2370,6 +3021,13 @@ func (x *CommunityResult) GetSimilarity() float32 {
 	return 0
 }
 
+func (x *CommunityResult) GetScore() float32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
 type RelationshipResult struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Relationship  *Relationship          `protobuf:"bytes,1,opt,name=relationship,proto3" json:"relationship,omitempty"`
@@ -2381,7 +3039,7 @@ type RelationshipResult struct {
 
 func (x *RelationshipResult) Reset() {
 	*x = RelationshipResult{}
-	mi := &file_proto_gibram_proto_msgTypes[30]
+	mi := &file_proto_gibram_proto_msgTypes[33]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2393,7 +3051,7 @@ func (x *RelationshipResult) String() string {
 func (*RelationshipResult) ProtoMessage() {}
 
 func (x *RelationshipResult) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[30]
+	mi := &file_proto_gibram_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2406,7 +3064,7 @@ func (x *RelationshipResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RelationshipResult.ProtoReflect.Descriptor instead.
 func (*RelationshipResult) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{30}
+	return file_proto_gibram_proto_rawDescGZIP(), []int{33}
 }
 
 func (x *RelationshipResult) GetRelationship() *Relationship {
@@ -2435,13 +3093,14 @@ type QueryStats struct {
 	DurationMicros  int64                  `protobuf:"varint,1,opt,name=duration_micros,json=durationMicros,proto3" json:"duration_micros,omitempty"`
 	VectorSearches  int32                  `protobuf:"varint,2,opt,name=vector_searches,json=vectorSearches,proto3" json:"vector_searches,omitempty"`
 	GraphTraversals int32                  `protobuf:"varint,3,opt,name=graph_traversals,json=graphTraversals,proto3" json:"graph_traversals,omitempty"`
+	CacheHit        bool                   `protobuf:"varint,4,opt,name=cache_hit,json=cacheHit,proto3" json:"cache_hit,omitempty"` // served from the session query cache
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
 
 func (x *QueryStats) Reset() {
 	*x = QueryStats{}
-	mi := &file_proto_gibram_proto_msgTypes[31]
+	mi := &file_proto_gibram_proto_msgTypes[34]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2453,7 +3112,7 @@ func (x *QueryStats) String() string {
 func (*QueryStats) ProtoMessage() {}
 
 func (x *QueryStats) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[31]
+	mi := &file_proto_gibram_proto_msgTypes[34]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2466,7 +3125,7 @@ func (x *QueryStats) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use QueryStats.ProtoReflect.Descriptor instead.
 func (*QueryStats) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{31}
+	return file_proto_gibram_proto_rawDescGZIP(), []int{34}
 }
 
 func (x *QueryStats) GetDurationMicros() int64 {
@@ -2490,6 +3149,13 @@ func (x *QueryStats) GetGraphTraversals() int32 {
 	return 0
 }
 
+func (x *QueryStats) GetCacheHit() bool {
+	if x != nil {
+		return x.CacheHit
+	}
+	return false
+}
+
 type QueryResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	QueryId       uint64                 `protobuf:"varint,1,opt,name=query_id,json=queryId,proto3" json:"query_id,omitempty"`
@@ -2498,13 +3164,15 @@ type QueryResponse struct {
 	Communities   []*CommunityResult     `protobuf:"bytes,4,rep,name=communities,proto3" json:"communities,omitempty"`
 	Relationships []*RelationshipResult  `protobuf:"bytes,5,rep,name=relationships,proto3" json:"relationships,omitempty"`
 	Stats         *QueryStats            `protobuf:"bytes,6,opt,name=stats,proto3" json:"stats,omitempty"`
+	Context       string                 `protobuf:"bytes,7,opt,name=context,proto3" json:"context,omitempty"`                         // rendered context, when context_format is set
+	TokenUsage    *TokenUsage            `protobuf:"bytes,8,opt,name=token_usage,json=tokenUsage,proto3" json:"token_usage,omitempty"` // set when token_budget is set
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *QueryResponse) Reset() {
 	*x = QueryResponse{}
-	mi := &file_proto_gibram_proto_msgTypes[32]
+	mi := &file_proto_gibram_proto_msgTypes[35]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2516,7 +3184,7 @@ func (x *QueryResponse) String() string {
 func (*QueryResponse) ProtoMessage() {}
 
 func (x *QueryResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[32]
+	mi := &file_proto_gibram_proto_msgTypes[35]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2529,7 +3197,7 @@ func (x *QueryResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use QueryResponse.ProtoReflect.Descriptor instead.
 func (*QueryResponse) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{32}
+	return file_proto_gibram_proto_rawDescGZIP(), []int{35}
 }
 
 func (x *QueryResponse) GetQueryId() uint64 {
@@ -2574,28 +3242,46 @@ func (x *QueryResponse) GetStats() *QueryStats {
 	return nil
 }
 
-type ExplainRequest struct {
+func (x *QueryResponse) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+func (x *QueryResponse) GetTokenUsage() *TokenUsage {
+	if x != nil {
+		return x.TokenUsage
+	}
+	return nil
+}
+
+type TokenUsage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	QueryId       uint64                 `protobuf:"varint,1,opt,name=query_id,json=queryId,proto3" json:"query_id,omitempty"`
+	Entities      int32                  `protobuf:"varint,1,opt,name=entities,proto3" json:"entities,omitempty"`
+	Relationships int32                  `protobuf:"varint,2,opt,name=relationships,proto3" json:"relationships,omitempty"`
+	Textunits     int32                  `protobuf:"varint,3,opt,name=textunits,proto3" json:"textunits,omitempty"`
+	Communities   int32                  `protobuf:"varint,4,opt,name=communities,proto3" json:"communities,omitempty"`
+	Total         int32                  `protobuf:"varint,5,opt,name=total,proto3" json:"total,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ExplainRequest) Reset() {
-	*x = ExplainRequest{}
-	mi := &file_proto_gibram_proto_msgTypes[33]
+func (x *TokenUsage) Reset() {
+	*x = TokenUsage{}
+	mi := &file_proto_gibram_proto_msgTypes[36]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ExplainRequest) String() string {
+func (x *TokenUsage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ExplainRequest) ProtoMessage() {}
+func (*TokenUsage) ProtoMessage() {}
 
-func (x *ExplainRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[33]
+func (x *TokenUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[36]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2606,113 +3292,72 @@ func (x *ExplainRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ExplainRequest.ProtoReflect.Descriptor instead.
-func (*ExplainRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{33}
+// Deprecated: Use TokenUsage.ProtoReflect.Descriptor instead.
+func (*TokenUsage) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{36}
 }
 
-func (x *ExplainRequest) GetQueryId() uint64 {
+func (x *TokenUsage) GetEntities() int32 {
 	if x != nil {
-		return x.QueryId
+		return x.Entities
 	}
 	return 0
 }
 
-type SeedInfo struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
-	Id            uint64                 `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
-	ExternalId    string                 `protobuf:"bytes,3,opt,name=external_id,json=externalId,proto3" json:"external_id,omitempty"`
-	Similarity    float32                `protobuf:"fixed32,4,opt,name=similarity,proto3" json:"similarity,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *TokenUsage) GetRelationships() int32 {
+	if x != nil {
+		return x.Relationships
+	}
+	return 0
 }
 
-func (x *SeedInfo) Reset() {
-	*x = SeedInfo{}
-	mi := &file_proto_gibram_proto_msgTypes[34]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *TokenUsage) GetTextunits() int32 {
+	if x != nil {
+		return x.Textunits
+	}
+	return 0
 }
 
-func (x *SeedInfo) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*SeedInfo) ProtoMessage() {}
-
-func (x *SeedInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[34]
-	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
-	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use SeedInfo.ProtoReflect.Descriptor instead.
-func (*SeedInfo) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{34}
-}
-
-func (x *SeedInfo) GetType() string {
+func (x *TokenUsage) GetCommunities() int32 {
 	if x != nil {
-		return x.Type
-	}
-	return ""
-}
-
-func (x *SeedInfo) GetId() uint64 {
-	if x != nil {
-		return x.Id
+		return x.Communities
 	}
 	return 0
 }
 
-func (x *SeedInfo) GetExternalId() string {
-	if x != nil {
-		return x.ExternalId
-	}
-	return ""
-}
-
-func (x *SeedInfo) GetSimilarity() float32 {
+func (x *TokenUsage) GetTotal() int32 {
 	if x != nil {
-		return x.Similarity
+		return x.Total
 	}
 	return 0
 }
 
-type TraversalStep struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	FromEntityId   uint64                 `protobuf:"varint,1,opt,name=from_entity_id,json=fromEntityId,proto3" json:"from_entity_id,omitempty"`
-	ToEntityId     uint64                 `protobuf:"varint,2,opt,name=to_entity_id,json=toEntityId,proto3" json:"to_entity_id,omitempty"`
-	RelationshipId uint64                 `protobuf:"varint,3,opt,name=relationship_id,json=relationshipId,proto3" json:"relationship_id,omitempty"`
-	RelType        string                 `protobuf:"bytes,4,opt,name=rel_type,json=relType,proto3" json:"rel_type,omitempty"`
-	Weight         float32                `protobuf:"fixed32,5,opt,name=weight,proto3" json:"weight,omitempty"`
-	Hop            int32                  `protobuf:"varint,6,opt,name=hop,proto3" json:"hop,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+// QUERY_BATCH: many queries of one session, run concurrently
+type QueryBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Queries       []*QueryRequest        `protobuf:"bytes,1,rep,name=queries,proto3" json:"queries,omitempty"`                               // run as given
+	Shared        *QueryRequest          `protobuf:"bytes,2,opt,name=shared,proto3" json:"shared,omitempty"`                                 // spec of the query_vectors queries
+	QueryVectors  []*SearchVector        `protobuf:"bytes,3,rep,name=query_vectors,json=queryVectors,proto3" json:"query_vectors,omitempty"` // one query per vector with the shared spec
+	Parallelism   int32                  `protobuf:"varint,4,opt,name=parallelism,proto3" json:"parallelism,omitempty"`                      // concurrent queries (0 = one per CPU)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *TraversalStep) Reset() {
-	*x = TraversalStep{}
-	mi := &file_proto_gibram_proto_msgTypes[35]
+func (x *QueryBatchRequest) Reset() {
+	*x = QueryBatchRequest{}
+	mi := &file_proto_gibram_proto_msgTypes[37]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *TraversalStep) String() string {
+func (x *QueryBatchRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TraversalStep) ProtoMessage() {}
+func (*QueryBatchRequest) ProtoMessage() {}
 
-func (x *TraversalStep) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[35]
+func (x *QueryBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[37]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2723,77 +3368,62 @@ func (x *TraversalStep) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TraversalStep.ProtoReflect.Descriptor instead.
-func (*TraversalStep) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{35}
-}
-
-func (x *TraversalStep) GetFromEntityId() uint64 {
-	if x != nil {
-		return x.FromEntityId
-	}
-	return 0
-}
-
-func (x *TraversalStep) GetToEntityId() uint64 {
-	if x != nil {
-		return x.ToEntityId
-	}
-	return 0
+// Deprecated: Use QueryBatchRequest.ProtoReflect.Descriptor instead.
+func (*QueryBatchRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{37}
 }
 
-func (x *TraversalStep) GetRelationshipId() uint64 {
+func (x *QueryBatchRequest) GetQueries() []*QueryRequest {
 	if x != nil {
-		return x.RelationshipId
+		return x.Queries
 	}
-	return 0
+	return nil
 }
 
-func (x *TraversalStep) GetRelType() string {
+func (x *QueryBatchRequest) GetShared() *QueryRequest {
 	if x != nil {
-		return x.RelType
+		return x.Shared
 	}
-	return ""
+	return nil
 }
 
-func (x *TraversalStep) GetWeight() float32 {
+func (x *QueryBatchRequest) GetQueryVectors() []*SearchVector {
 	if x != nil {
-		return x.Weight
+		return x.QueryVectors
 	}
-	return 0
+	return nil
 }
 
-func (x *TraversalStep) GetHop() int32 {
+func (x *QueryBatchRequest) GetParallelism() int32 {
 	if x != nil {
-		return x.Hop
+		return x.Parallelism
 	}
 	return 0
 }
 
-type ExplainResponse struct {
+type QueryBatchResult struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	QueryId       uint64                 `protobuf:"varint,1,opt,name=query_id,json=queryId,proto3" json:"query_id,omitempty"`
-	Seeds         []*SeedInfo            `protobuf:"bytes,2,rep,name=seeds,proto3" json:"seeds,omitempty"`
-	Traversal     []*TraversalStep       `protobuf:"bytes,3,rep,name=traversal,proto3" json:"traversal,omitempty"`
+	Response      *QueryResponse         `protobuf:"bytes,1,opt,name=response,proto3" json:"response,omitempty"` // unset when the query failed
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ExplainResponse) Reset() {
-	*x = ExplainResponse{}
-	mi := &file_proto_gibram_proto_msgTypes[36]
+func (x *QueryBatchResult) Reset() {
+	*x = QueryBatchResult{}
+	mi := &file_proto_gibram_proto_msgTypes[38]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ExplainResponse) String() string {
+func (x *QueryBatchResult) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ExplainResponse) ProtoMessage() {}
+func (*QueryBatchResult) ProtoMessage() {}
 
-func (x *ExplainResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[36]
+func (x *QueryBatchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[38]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2804,54 +3434,47 @@ func (x *ExplainResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ExplainResponse.ProtoReflect.Descriptor instead.
-func (*ExplainResponse) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{36}
-}
-
-func (x *ExplainResponse) GetQueryId() uint64 {
-	if x != nil {
-		return x.QueryId
-	}
-	return 0
+// Deprecated: Use QueryBatchResult.ProtoReflect.Descriptor instead.
+func (*QueryBatchResult) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{38}
 }
 
-func (x *ExplainResponse) GetSeeds() []*SeedInfo {
+func (x *QueryBatchResult) GetResponse() *QueryResponse {
 	if x != nil {
-		return x.Seeds
+		return x.Response
 	}
 	return nil
 }
 
-func (x *ExplainResponse) GetTraversal() []*TraversalStep {
+func (x *QueryBatchResult) GetError() string {
 	if x != nil {
-		return x.Traversal
+		return x.Error
 	}
-	return nil
+	return ""
 }
 
-type GetByIDRequest struct {
+type QueryBatchResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Results       []*QueryBatchResult    `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"` // queries, then query_vectors, in request order
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetByIDRequest) Reset() {
-	*x = GetByIDRequest{}
-	mi := &file_proto_gibram_proto_msgTypes[37]
+func (x *QueryBatchResponse) Reset() {
+	*x = QueryBatchResponse{}
+	mi := &file_proto_gibram_proto_msgTypes[39]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetByIDRequest) String() string {
+func (x *QueryBatchResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetByIDRequest) ProtoMessage() {}
+func (*QueryBatchResponse) ProtoMessage() {}
 
-func (x *GetByIDRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[37]
+func (x *QueryBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[39]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2862,40 +3485,40 @@ func (x *GetByIDRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetByIDRequest.ProtoReflect.Descriptor instead.
-func (*GetByIDRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{37}
+// Deprecated: Use QueryBatchResponse.ProtoReflect.Descriptor instead.
+func (*QueryBatchResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{39}
 }
 
-func (x *GetByIDRequest) GetId() uint64 {
+func (x *QueryBatchResponse) GetResults() []*QueryBatchResult {
 	if x != nil {
-		return x.Id
+		return x.Results
 	}
-	return 0
+	return nil
 }
 
-type DeleteByIDRequest struct {
+type SearchVector struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Values        []float32              `protobuf:"fixed32,1,rep,packed,name=values,proto3" json:"values,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteByIDRequest) Reset() {
-	*x = DeleteByIDRequest{}
-	mi := &file_proto_gibram_proto_msgTypes[38]
+func (x *SearchVector) Reset() {
+	*x = SearchVector{}
+	mi := &file_proto_gibram_proto_msgTypes[40]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteByIDRequest) String() string {
+func (x *SearchVector) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteByIDRequest) ProtoMessage() {}
+func (*SearchVector) ProtoMessage() {}
 
-func (x *DeleteByIDRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[38]
+func (x *SearchVector) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[40]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2906,41 +3529,50 @@ func (x *DeleteByIDRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteByIDRequest.ProtoReflect.Descriptor instead.
-func (*DeleteByIDRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{38}
+// Deprecated: Use SearchVector.ProtoReflect.Descriptor instead.
+func (*SearchVector) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{40}
 }
 
-func (x *DeleteByIDRequest) GetId() uint64 {
+func (x *SearchVector) GetValues() []float32 {
 	if x != nil {
-		return x.Id
+		return x.Values
 	}
-	return 0
+	return nil
 }
 
-type HealthResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"` // "ok", "degraded", "error"
-	Components    map[string]string      `protobuf:"bytes,2,rep,name=components,proto3" json:"components,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type SearchRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Index             string                 `protobuf:"bytes,1,opt,name=index,proto3" json:"index,omitempty"`                                   // "textunit", "entity" or "community"
+	QueryVectors      []*SearchVector        `protobuf:"bytes,2,rep,name=query_vectors,json=queryVectors,proto3" json:"query_vectors,omitempty"` // one result list per vector
+	K                 int32                  `protobuf:"varint,3,opt,name=k,proto3" json:"k,omitempty"`                                          // max hits per vector (0 = 10; range search: 0 = max)
+	Ef                int32                  `protobuf:"varint,4,opt,name=ef,proto3" json:"ef,omitempty"`                                        // HNSW candidate list override (0 = index default)
+	MinSimilarity     float32                `protobuf:"fixed32,5,opt,name=min_similarity,json=minSimilarity,proto3" json:"min_similarity,omitempty"`
+	HasMinSimilarity  bool                   `protobuf:"varint,6,opt,name=has_min_similarity,json=hasMinSimilarity,proto3" json:"has_min_similarity,omitempty"`                                                          // apply min_similarity (0 is a valid threshold)
+	Range             bool                   `protobuf:"varint,7,opt,name=range,proto3" json:"range,omitempty"`                                                                                                          // every hit at or above min_similarity
+	IncludeObjects    bool                   `protobuf:"varint,8,opt,name=include_objects,json=includeObjects,proto3" json:"include_objects,omitempty"`                                                                  // return the matched objects
+	FilterEntityTypes []string               `protobuf:"bytes,9,rep,name=filter_entity_types,json=filterEntityTypes,proto3" json:"filter_entity_types,omitempty"`                                                        // entity index only
+	FilterDocumentIds []uint64               `protobuf:"varint,10,rep,packed,name=filter_document_ids,json=filterDocumentIds,proto3" json:"filter_document_ids,omitempty"`                                               // textunit index only
+	FilterAttrs       map[string]string      `protobuf:"bytes,11,rep,name=filter_attrs,json=filterAttrs,proto3" json:"filter_attrs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // entity index only
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
-func (x *HealthResponse) Reset() {
-	*x = HealthResponse{}
-	mi := &file_proto_gibram_proto_msgTypes[39]
+func (x *SearchRequest) Reset() {
+	*x = SearchRequest{}
+	mi := &file_proto_gibram_proto_msgTypes[41]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *HealthResponse) String() string {
+func (x *SearchRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HealthResponse) ProtoMessage() {}
+func (*SearchRequest) ProtoMessage() {}
 
-func (x *HealthResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[39]
+func (x *SearchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[41]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2951,99 +3583,115 @@ func (x *HealthResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HealthResponse.ProtoReflect.Descriptor instead.
-func (*HealthResponse) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{39}
+// Deprecated: Use SearchRequest.ProtoReflect.Descriptor instead.
+func (*SearchRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{41}
 }
 
-func (x *HealthResponse) GetStatus() string {
+func (x *SearchRequest) GetIndex() string {
 	if x != nil {
-		return x.Status
+		return x.Index
 	}
 	return ""
 }
 
-func (x *HealthResponse) GetComponents() map[string]string {
+func (x *SearchRequest) GetQueryVectors() []*SearchVector {
 	if x != nil {
-		return x.Components
+		return x.QueryVectors
 	}
 	return nil
 }
 
-type ListEntitiesRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Cursor        uint64                 `protobuf:"varint,1,opt,name=cursor,proto3" json:"cursor,omitempty"` // last seen entity ID (0 = start)
-	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`   // max entities to return (0 = server default)
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *SearchRequest) GetK() int32 {
+	if x != nil {
+		return x.K
+	}
+	return 0
 }
 
-func (x *ListEntitiesRequest) Reset() {
-	*x = ListEntitiesRequest{}
-	mi := &file_proto_gibram_proto_msgTypes[40]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *SearchRequest) GetEf() int32 {
+	if x != nil {
+		return x.Ef
+	}
+	return 0
 }
 
-func (x *ListEntitiesRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *SearchRequest) GetMinSimilarity() float32 {
+	if x != nil {
+		return x.MinSimilarity
+	}
+	return 0
 }
 
-func (*ListEntitiesRequest) ProtoMessage() {}
+func (x *SearchRequest) GetHasMinSimilarity() bool {
+	if x != nil {
+		return x.HasMinSimilarity
+	}
+	return false
+}
 
-func (x *ListEntitiesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[40]
+func (x *SearchRequest) GetRange() bool {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Range
 	}
-	return mi.MessageOf(x)
+	return false
 }
 
-// Deprecated: Use ListEntitiesRequest.ProtoReflect.Descriptor instead.
-func (*ListEntitiesRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{40}
+func (x *SearchRequest) GetIncludeObjects() bool {
+	if x != nil {
+		return x.IncludeObjects
+	}
+	return false
 }
 
-func (x *ListEntitiesRequest) GetCursor() uint64 {
+func (x *SearchRequest) GetFilterEntityTypes() []string {
 	if x != nil {
-		return x.Cursor
+		return x.FilterEntityTypes
 	}
-	return 0
+	return nil
 }
 
-func (x *ListEntitiesRequest) GetLimit() int32 {
+func (x *SearchRequest) GetFilterDocumentIds() []uint64 {
 	if x != nil {
-		return x.Limit
+		return x.FilterDocumentIds
 	}
-	return 0
+	return nil
 }
 
-type MSetEntitiesRequest struct {
+func (x *SearchRequest) GetFilterAttrs() map[string]string {
+	if x != nil {
+		return x.FilterAttrs
+	}
+	return nil
+}
+
+type SearchHit struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Entities      []*AddEntityRequest    `protobuf:"bytes,1,rep,name=entities,proto3" json:"entities,omitempty"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ExternalId    string                 `protobuf:"bytes,2,opt,name=external_id,json=externalId,proto3" json:"external_id,omitempty"`
+	Similarity    float32                `protobuf:"fixed32,3,opt,name=similarity,proto3" json:"similarity,omitempty"`
+	Textunit      *TextUnit              `protobuf:"bytes,4,opt,name=textunit,proto3" json:"textunit,omitempty"` // with include_objects, per index
+	Entity        *Entity                `protobuf:"bytes,5,opt,name=entity,proto3" json:"entity,omitempty"`
+	Community     *Community             `protobuf:"bytes,6,opt,name=community,proto3" json:"community,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *MSetEntitiesRequest) Reset() {
-	*x = MSetEntitiesRequest{}
-	mi := &file_proto_gibram_proto_msgTypes[41]
+func (x *SearchHit) Reset() {
+	*x = SearchHit{}
+	mi := &file_proto_gibram_proto_msgTypes[42]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *MSetEntitiesRequest) String() string {
+func (x *SearchHit) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MSetEntitiesRequest) ProtoMessage() {}
+func (*SearchHit) ProtoMessage() {}
 
-func (x *MSetEntitiesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[41]
+func (x *SearchHit) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[42]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3054,85 +3702,74 @@ func (x *MSetEntitiesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MSetEntitiesRequest.ProtoReflect.Descriptor instead.
-func (*MSetEntitiesRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{41}
+// Deprecated: Use SearchHit.ProtoReflect.Descriptor instead.
+func (*SearchHit) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{42}
 }
 
-func (x *MSetEntitiesRequest) GetEntities() []*AddEntityRequest {
+func (x *SearchHit) GetId() uint64 {
 	if x != nil {
-		return x.Entities
+		return x.Id
 	}
-	return nil
-}
-
-type MGetEntitiesRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Ids           []uint64               `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	return 0
 }
 
-func (x *MGetEntitiesRequest) Reset() {
-	*x = MGetEntitiesRequest{}
-	mi := &file_proto_gibram_proto_msgTypes[42]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *SearchHit) GetExternalId() string {
+	if x != nil {
+		return x.ExternalId
+	}
+	return ""
 }
 
-func (x *MGetEntitiesRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *SearchHit) GetSimilarity() float32 {
+	if x != nil {
+		return x.Similarity
+	}
+	return 0
 }
 
-func (*MGetEntitiesRequest) ProtoMessage() {}
-
-func (x *MGetEntitiesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[42]
+func (x *SearchHit) GetTextunit() *TextUnit {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Textunit
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use MGetEntitiesRequest.ProtoReflect.Descriptor instead.
-func (*MGetEntitiesRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{42}
+func (x *SearchHit) GetEntity() *Entity {
+	if x != nil {
+		return x.Entity
+	}
+	return nil
 }
 
-func (x *MGetEntitiesRequest) GetIds() []uint64 {
+func (x *SearchHit) GetCommunity() *Community {
 	if x != nil {
-		return x.Ids
+		return x.Community
 	}
 	return nil
 }
 
-type EntitiesResponse struct {
+type SearchHits struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Entities      []*Entity              `protobuf:"bytes,1,rep,name=entities,proto3" json:"entities,omitempty"`
-	CreatedIds    []uint64               `protobuf:"varint,2,rep,packed,name=created_ids,json=createdIds,proto3" json:"created_ids,omitempty"` // for MSET responses
-	NextCursor    uint64                 `protobuf:"varint,3,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`        // for LIST responses (0 = no more)
+	Hits          []*SearchHit           `protobuf:"bytes,1,rep,name=hits,proto3" json:"hits,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *EntitiesResponse) Reset() {
-	*x = EntitiesResponse{}
+func (x *SearchHits) Reset() {
+	*x = SearchHits{}
 	mi := &file_proto_gibram_proto_msgTypes[43]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *EntitiesResponse) String() string {
+func (x *SearchHits) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*EntitiesResponse) ProtoMessage() {}
+func (*SearchHits) ProtoMessage() {}
 
-func (x *EntitiesResponse) ProtoReflect() protoreflect.Message {
+func (x *SearchHits) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_gibram_proto_msgTypes[43]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3144,53 +3781,39 @@ func (x *EntitiesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use EntitiesResponse.ProtoReflect.Descriptor instead.
-func (*EntitiesResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use SearchHits.ProtoReflect.Descriptor instead.
+func (*SearchHits) Descriptor() ([]byte, []int) {
 	return file_proto_gibram_proto_rawDescGZIP(), []int{43}
 }
 
-func (x *EntitiesResponse) GetEntities() []*Entity {
-	if x != nil {
-		return x.Entities
-	}
-	return nil
-}
-
-func (x *EntitiesResponse) GetCreatedIds() []uint64 {
+func (x *SearchHits) GetHits() []*SearchHit {
 	if x != nil {
-		return x.CreatedIds
+		return x.Hits
 	}
 	return nil
 }
 
-func (x *EntitiesResponse) GetNextCursor() uint64 {
-	if x != nil {
-		return x.NextCursor
-	}
-	return 0
-}
-
-type MSetDocumentsRequest struct {
+type SearchResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Documents     []*AddDocumentRequest  `protobuf:"bytes,1,rep,name=documents,proto3" json:"documents,omitempty"`
+	Results       []*SearchHits          `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"` // in query_vectors order
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *MSetDocumentsRequest) Reset() {
-	*x = MSetDocumentsRequest{}
+func (x *SearchResponse) Reset() {
+	*x = SearchResponse{}
 	mi := &file_proto_gibram_proto_msgTypes[44]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *MSetDocumentsRequest) String() string {
+func (x *SearchResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MSetDocumentsRequest) ProtoMessage() {}
+func (*SearchResponse) ProtoMessage() {}
 
-func (x *MSetDocumentsRequest) ProtoReflect() protoreflect.Message {
+func (x *SearchResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_gibram_proto_msgTypes[44]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3202,39 +3825,48 @@ func (x *MSetDocumentsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MSetDocumentsRequest.ProtoReflect.Descriptor instead.
-func (*MSetDocumentsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use SearchResponse.ProtoReflect.Descriptor instead.
+func (*SearchResponse) Descriptor() ([]byte, []int) {
 	return file_proto_gibram_proto_rawDescGZIP(), []int{44}
 }
 
-func (x *MSetDocumentsRequest) GetDocuments() []*AddDocumentRequest {
+func (x *SearchResponse) GetResults() []*SearchHits {
 	if x != nil {
-		return x.Documents
+		return x.Results
 	}
 	return nil
 }
 
-type MGetDocumentsRequest struct {
+// PATH: shortest paths between two entities, each given by ID or title
+type PathRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Ids           []uint64               `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"`
+	SourceId      uint64                 `protobuf:"varint,1,opt,name=source_id,json=sourceId,proto3" json:"source_id,omitempty"`
+	SourceTitle   string                 `protobuf:"bytes,2,opt,name=source_title,json=sourceTitle,proto3" json:"source_title,omitempty"` // used when source_id is 0
+	TargetId      uint64                 `protobuf:"varint,3,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"`
+	TargetTitle   string                 `protobuf:"bytes,4,opt,name=target_title,json=targetTitle,proto3" json:"target_title,omitempty"` // used when target_id is 0
+	K             int32                  `protobuf:"varint,5,opt,name=k,proto3" json:"k,omitempty"`                                       // paths to return (0 = 1)
+	MaxDepth      int32                  `protobuf:"varint,6,opt,name=max_depth,json=maxDepth,proto3" json:"max_depth,omitempty"`         // relationships per path (0 = 6)
+	Directed      bool                   `protobuf:"varint,7,opt,name=directed,proto3" json:"directed,omitempty"`                         // follow relationships source -> target only
+	Weighted      bool                   `protobuf:"varint,8,opt,name=weighted,proto3" json:"weighted,omitempty"`                         // cheapest by 1/weight instead of fewest hops
+	RelTypes      []string               `protobuf:"bytes,9,rep,name=rel_types,json=relTypes,proto3" json:"rel_types,omitempty"`          // relationship types to follow (empty = all)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *MGetDocumentsRequest) Reset() {
-	*x = MGetDocumentsRequest{}
+func (x *PathRequest) Reset() {
+	*x = PathRequest{}
 	mi := &file_proto_gibram_proto_msgTypes[45]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *MGetDocumentsRequest) String() string {
+func (x *PathRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MGetDocumentsRequest) ProtoMessage() {}
+func (*PathRequest) ProtoMessage() {}
 
-func (x *MGetDocumentsRequest) ProtoReflect() protoreflect.Message {
+func (x *PathRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_gibram_proto_msgTypes[45]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3246,40 +3878,97 @@ func (x *MGetDocumentsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MGetDocumentsRequest.ProtoReflect.Descriptor instead.
-func (*MGetDocumentsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use PathRequest.ProtoReflect.Descriptor instead.
+func (*PathRequest) Descriptor() ([]byte, []int) {
 	return file_proto_gibram_proto_rawDescGZIP(), []int{45}
 }
 
-func (x *MGetDocumentsRequest) GetIds() []uint64 {
+func (x *PathRequest) GetSourceId() uint64 {
 	if x != nil {
-		return x.Ids
+		return x.SourceId
+	}
+	return 0
+}
+
+func (x *PathRequest) GetSourceTitle() string {
+	if x != nil {
+		return x.SourceTitle
+	}
+	return ""
+}
+
+func (x *PathRequest) GetTargetId() uint64 {
+	if x != nil {
+		return x.TargetId
+	}
+	return 0
+}
+
+func (x *PathRequest) GetTargetTitle() string {
+	if x != nil {
+		return x.TargetTitle
+	}
+	return ""
+}
+
+func (x *PathRequest) GetK() int32 {
+	if x != nil {
+		return x.K
+	}
+	return 0
+}
+
+func (x *PathRequest) GetMaxDepth() int32 {
+	if x != nil {
+		return x.MaxDepth
+	}
+	return 0
+}
+
+func (x *PathRequest) GetDirected() bool {
+	if x != nil {
+		return x.Directed
+	}
+	return false
+}
+
+func (x *PathRequest) GetWeighted() bool {
+	if x != nil {
+		return x.Weighted
+	}
+	return false
+}
+
+func (x *PathRequest) GetRelTypes() []string {
+	if x != nil {
+		return x.RelTypes
 	}
 	return nil
 }
 
-type DocumentsResponse struct {
+type EntityPath struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Documents     []*Document            `protobuf:"bytes,1,rep,name=documents,proto3" json:"documents,omitempty"`
-	CreatedIds    []uint64               `protobuf:"varint,2,rep,packed,name=created_ids,json=createdIds,proto3" json:"created_ids,omitempty"`
+	Entities      []*Entity              `protobuf:"bytes,1,rep,name=entities,proto3" json:"entities,omitempty"` // one more than relationships
+	Relationships []*Relationship        `protobuf:"bytes,2,rep,name=relationships,proto3" json:"relationships,omitempty"`
+	Cost          float64                `protobuf:"fixed64,3,opt,name=cost,proto3" json:"cost,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DocumentsResponse) Reset() {
-	*x = DocumentsResponse{}
+func (x *EntityPath) Reset() {
+	*x = EntityPath{}
 	mi := &file_proto_gibram_proto_msgTypes[46]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DocumentsResponse) String() string {
+func (x *EntityPath) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DocumentsResponse) ProtoMessage() {}
+func (*EntityPath) ProtoMessage() {}
 
-func (x *DocumentsResponse) ProtoReflect() protoreflect.Message {
+func (x *EntityPath) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_gibram_proto_msgTypes[46]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3291,46 +3980,53 @@ func (x *DocumentsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DocumentsResponse.ProtoReflect.Descriptor instead.
-func (*DocumentsResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use EntityPath.ProtoReflect.Descriptor instead.
+func (*EntityPath) Descriptor() ([]byte, []int) {
 	return file_proto_gibram_proto_rawDescGZIP(), []int{46}
 }
 
-func (x *DocumentsResponse) GetDocuments() []*Document {
+func (x *EntityPath) GetEntities() []*Entity {
 	if x != nil {
-		return x.Documents
+		return x.Entities
 	}
 	return nil
 }
 
-func (x *DocumentsResponse) GetCreatedIds() []uint64 {
+func (x *EntityPath) GetRelationships() []*Relationship {
 	if x != nil {
-		return x.CreatedIds
+		return x.Relationships
 	}
 	return nil
 }
 
-type MSetTextUnitsRequest struct {
+func (x *EntityPath) GetCost() float64 {
+	if x != nil {
+		return x.Cost
+	}
+	return 0
+}
+
+type PathResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Textunits     []*AddTextUnitRequest  `protobuf:"bytes,1,rep,name=textunits,proto3" json:"textunits,omitempty"`
+	Paths         []*EntityPath          `protobuf:"bytes,1,rep,name=paths,proto3" json:"paths,omitempty"` // cheapest first
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *MSetTextUnitsRequest) Reset() {
-	*x = MSetTextUnitsRequest{}
+func (x *PathResponse) Reset() {
+	*x = PathResponse{}
 	mi := &file_proto_gibram_proto_msgTypes[47]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *MSetTextUnitsRequest) String() string {
+func (x *PathResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MSetTextUnitsRequest) ProtoMessage() {}
+func (*PathResponse) ProtoMessage() {}
 
-func (x *MSetTextUnitsRequest) ProtoReflect() protoreflect.Message {
+func (x *PathResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_gibram_proto_msgTypes[47]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3342,39 +4038,46 @@ func (x *MSetTextUnitsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MSetTextUnitsRequest.ProtoReflect.Descriptor instead.
-func (*MSetTextUnitsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use PathResponse.ProtoReflect.Descriptor instead.
+func (*PathResponse) Descriptor() ([]byte, []int) {
 	return file_proto_gibram_proto_rawDescGZIP(), []int{47}
 }
 
-func (x *MSetTextUnitsRequest) GetTextunits() []*AddTextUnitRequest {
+func (x *PathResponse) GetPaths() []*EntityPath {
 	if x != nil {
-		return x.Textunits
+		return x.Paths
 	}
 	return nil
 }
 
-type MGetTextUnitsRequest struct {
+// SUBGRAPH: entities within a hop radius and the relationships among them
+type SubgraphRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Ids           []uint64               `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"`
+	EntityIds     []uint64               `protobuf:"varint,1,rep,packed,name=entity_ids,json=entityIds,proto3" json:"entity_ids,omitempty"`
+	Hops          int32                  `protobuf:"varint,2,opt,name=hops,proto3" json:"hops,omitempty"`                                 // radius (0 = only entity_ids)
+	Direction     string                 `protobuf:"bytes,3,opt,name=direction,proto3" json:"direction,omitempty"`                        // "both" (default), "out" or "in"
+	EntityTypes   []string               `protobuf:"bytes,4,rep,name=entity_types,json=entityTypes,proto3" json:"entity_types,omitempty"` // types of entities reached (empty = all)
+	RelTypes      []string               `protobuf:"bytes,5,rep,name=rel_types,json=relTypes,proto3" json:"rel_types,omitempty"`          // relationship types (empty = all)
+	MaxNodes      int32                  `protobuf:"varint,6,opt,name=max_nodes,json=maxNodes,proto3" json:"max_nodes,omitempty"`         // 0 = 100
+	MaxEdges      int32                  `protobuf:"varint,7,opt,name=max_edges,json=maxEdges,proto3" json:"max_edges,omitempty"`         // 0 = 1000
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *MGetTextUnitsRequest) Reset() {
-	*x = MGetTextUnitsRequest{}
+func (x *SubgraphRequest) Reset() {
+	*x = SubgraphRequest{}
 	mi := &file_proto_gibram_proto_msgTypes[48]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *MGetTextUnitsRequest) String() string {
+func (x *SubgraphRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MGetTextUnitsRequest) ProtoMessage() {}
+func (*SubgraphRequest) ProtoMessage() {}
 
-func (x *MGetTextUnitsRequest) ProtoReflect() protoreflect.Message {
+func (x *SubgraphRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_gibram_proto_msgTypes[48]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3386,92 +4089,86 @@ func (x *MGetTextUnitsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MGetTextUnitsRequest.ProtoReflect.Descriptor instead.
-func (*MGetTextUnitsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use SubgraphRequest.ProtoReflect.Descriptor instead.
+func (*SubgraphRequest) Descriptor() ([]byte, []int) {
 	return file_proto_gibram_proto_rawDescGZIP(), []int{48}
 }
 
-func (x *MGetTextUnitsRequest) GetIds() []uint64 {
+func (x *SubgraphRequest) GetEntityIds() []uint64 {
 	if x != nil {
-		return x.Ids
+		return x.EntityIds
 	}
 	return nil
 }
 
-type TextUnitsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Textunits     []*TextUnit            `protobuf:"bytes,1,rep,name=textunits,proto3" json:"textunits,omitempty"`
-	CreatedIds    []uint64               `protobuf:"varint,2,rep,packed,name=created_ids,json=createdIds,proto3" json:"created_ids,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *TextUnitsResponse) Reset() {
-	*x = TextUnitsResponse{}
-	mi := &file_proto_gibram_proto_msgTypes[49]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *SubgraphRequest) GetHops() int32 {
+	if x != nil {
+		return x.Hops
+	}
+	return 0
 }
 
-func (x *TextUnitsResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *SubgraphRequest) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
 }
 
-func (*TextUnitsResponse) ProtoMessage() {}
-
-func (x *TextUnitsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[49]
+func (x *SubgraphRequest) GetEntityTypes() []string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.EntityTypes
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use TextUnitsResponse.ProtoReflect.Descriptor instead.
-func (*TextUnitsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{49}
+func (x *SubgraphRequest) GetRelTypes() []string {
+	if x != nil {
+		return x.RelTypes
+	}
+	return nil
 }
 
-func (x *TextUnitsResponse) GetTextunits() []*TextUnit {
+func (x *SubgraphRequest) GetMaxNodes() int32 {
 	if x != nil {
-		return x.Textunits
+		return x.MaxNodes
 	}
-	return nil
+	return 0
 }
 
-func (x *TextUnitsResponse) GetCreatedIds() []uint64 {
+func (x *SubgraphRequest) GetMaxEdges() int32 {
 	if x != nil {
-		return x.CreatedIds
+		return x.MaxEdges
 	}
-	return nil
+	return 0
 }
 
-type MSetRelationshipsRequest struct {
-	state         protoimpl.MessageState    `protogen:"open.v1"`
-	Relationships []*AddRelationshipRequest `protobuf:"bytes,1,rep,name=relationships,proto3" json:"relationships,omitempty"`
+type SubgraphNode struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entity        *Entity                `protobuf:"bytes,1,opt,name=entity,proto3" json:"entity,omitempty"`
+	Hop           int32                  `protobuf:"varint,2,opt,name=hop,proto3" json:"hop,omitempty"`
+	InDegree      int32                  `protobuf:"varint,3,opt,name=in_degree,json=inDegree,proto3" json:"in_degree,omitempty"`    // in the whole graph
+	OutDegree     int32                  `protobuf:"varint,4,opt,name=out_degree,json=outDegree,proto3" json:"out_degree,omitempty"` // in the whole graph
+	Degree        int32                  `protobuf:"varint,5,opt,name=degree,proto3" json:"degree,omitempty"`                        // within the subgraph
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *MSetRelationshipsRequest) Reset() {
-	*x = MSetRelationshipsRequest{}
-	mi := &file_proto_gibram_proto_msgTypes[50]
+func (x *SubgraphNode) Reset() {
+	*x = SubgraphNode{}
+	mi := &file_proto_gibram_proto_msgTypes[49]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *MSetRelationshipsRequest) String() string {
+func (x *SubgraphNode) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MSetRelationshipsRequest) ProtoMessage() {}
+func (*SubgraphNode) ProtoMessage() {}
 
-func (x *MSetRelationshipsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[50]
+func (x *SubgraphNode) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[49]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3482,39 +4179,129 @@ func (x *MSetRelationshipsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MSetRelationshipsRequest.ProtoReflect.Descriptor instead.
-func (*MSetRelationshipsRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{50}
+// Deprecated: Use SubgraphNode.ProtoReflect.Descriptor instead.
+func (*SubgraphNode) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{49}
 }
 
-func (x *MSetRelationshipsRequest) GetRelationships() []*AddRelationshipRequest {
+func (x *SubgraphNode) GetEntity() *Entity {
 	if x != nil {
-		return x.Relationships
+		return x.Entity
 	}
 	return nil
 }
 
-type MGetRelationshipsRequest struct {
+func (x *SubgraphNode) GetHop() int32 {
+	if x != nil {
+		return x.Hop
+	}
+	return 0
+}
+
+func (x *SubgraphNode) GetInDegree() int32 {
+	if x != nil {
+		return x.InDegree
+	}
+	return 0
+}
+
+func (x *SubgraphNode) GetOutDegree() int32 {
+	if x != nil {
+		return x.OutDegree
+	}
+	return 0
+}
+
+func (x *SubgraphNode) GetDegree() int32 {
+	if x != nil {
+		return x.Degree
+	}
+	return 0
+}
+
+type SubgraphResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Ids           []uint64               `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"`
+	Nodes         []*SubgraphNode        `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"` // by hop
+	Relationships []*Relationship        `protobuf:"bytes,2,rep,name=relationships,proto3" json:"relationships,omitempty"`
+	Truncated     bool                   `protobuf:"varint,3,opt,name=truncated,proto3" json:"truncated,omitempty"` // node or edge limit reached
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *MGetRelationshipsRequest) Reset() {
-	*x = MGetRelationshipsRequest{}
+func (x *SubgraphResponse) Reset() {
+	*x = SubgraphResponse{}
+	mi := &file_proto_gibram_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubgraphResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubgraphResponse) ProtoMessage() {}
+
+func (x *SubgraphResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubgraphResponse.ProtoReflect.Descriptor instead.
+func (*SubgraphResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *SubgraphResponse) GetNodes() []*SubgraphNode {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+func (x *SubgraphResponse) GetRelationships() []*Relationship {
+	if x != nil {
+		return x.Relationships
+	}
+	return nil
+}
+
+func (x *SubgraphResponse) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+// QUERY_GRAPH: Cypher-like pattern query
+// MATCH (a:Type {key: "v"})-[r:TYPE]->(b) [WHERE ...] RETURN ... [LIMIT n]
+type GraphQueryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Query         string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GraphQueryRequest) Reset() {
+	*x = GraphQueryRequest{}
 	mi := &file_proto_gibram_proto_msgTypes[51]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *MGetRelationshipsRequest) String() string {
+func (x *GraphQueryRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MGetRelationshipsRequest) ProtoMessage() {}
+func (*GraphQueryRequest) ProtoMessage() {}
 
-func (x *MGetRelationshipsRequest) ProtoReflect() protoreflect.Message {
+func (x *GraphQueryRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_gibram_proto_msgTypes[51]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3526,41 +4313,45 @@ func (x *MGetRelationshipsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MGetRelationshipsRequest.ProtoReflect.Descriptor instead.
-func (*MGetRelationshipsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use GraphQueryRequest.ProtoReflect.Descriptor instead.
+func (*GraphQueryRequest) Descriptor() ([]byte, []int) {
 	return file_proto_gibram_proto_rawDescGZIP(), []int{51}
 }
 
-func (x *MGetRelationshipsRequest) GetIds() []uint64 {
+func (x *GraphQueryRequest) GetQuery() string {
 	if x != nil {
-		return x.Ids
+		return x.Query
 	}
-	return nil
+	return ""
 }
 
-type RelationshipsResponse struct {
+// GraphValue is one cell of a result row; kind tells which field is set
+type GraphValue struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Relationships []*Relationship        `protobuf:"bytes,1,rep,name=relationships,proto3" json:"relationships,omitempty"`
-	CreatedIds    []uint64               `protobuf:"varint,2,rep,packed,name=created_ids,json=createdIds,proto3" json:"created_ids,omitempty"`
-	NextCursor    uint64                 `protobuf:"varint,3,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"` // for LIST responses (0 = no more)
+	Kind          string                 `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"` // "entity", "relationship", "string", "number", "bool" or "null"
+	Entity        *Entity                `protobuf:"bytes,2,opt,name=entity,proto3" json:"entity,omitempty"`
+	Relationship  *Relationship          `protobuf:"bytes,3,opt,name=relationship,proto3" json:"relationship,omitempty"`
+	StringValue   string                 `protobuf:"bytes,4,opt,name=string_value,json=stringValue,proto3" json:"string_value,omitempty"`
+	NumberValue   float64                `protobuf:"fixed64,5,opt,name=number_value,json=numberValue,proto3" json:"number_value,omitempty"`
+	BoolValue     bool                   `protobuf:"varint,6,opt,name=bool_value,json=boolValue,proto3" json:"bool_value,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RelationshipsResponse) Reset() {
-	*x = RelationshipsResponse{}
+func (x *GraphValue) Reset() {
+	*x = GraphValue{}
 	mi := &file_proto_gibram_proto_msgTypes[52]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RelationshipsResponse) String() string {
+func (x *GraphValue) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RelationshipsResponse) ProtoMessage() {}
+func (*GraphValue) ProtoMessage() {}
 
-func (x *RelationshipsResponse) ProtoReflect() protoreflect.Message {
+func (x *GraphValue) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_gibram_proto_msgTypes[52]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3572,54 +4363,74 @@ func (x *RelationshipsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RelationshipsResponse.ProtoReflect.Descriptor instead.
-func (*RelationshipsResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use GraphValue.ProtoReflect.Descriptor instead.
+func (*GraphValue) Descriptor() ([]byte, []int) {
 	return file_proto_gibram_proto_rawDescGZIP(), []int{52}
 }
 
-func (x *RelationshipsResponse) GetRelationships() []*Relationship {
+func (x *GraphValue) GetKind() string {
 	if x != nil {
-		return x.Relationships
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *GraphValue) GetEntity() *Entity {
+	if x != nil {
+		return x.Entity
 	}
 	return nil
 }
 
-func (x *RelationshipsResponse) GetCreatedIds() []uint64 {
+func (x *GraphValue) GetRelationship() *Relationship {
 	if x != nil {
-		return x.CreatedIds
+		return x.Relationship
 	}
 	return nil
 }
 
-func (x *RelationshipsResponse) GetNextCursor() uint64 {
+func (x *GraphValue) GetStringValue() string {
 	if x != nil {
-		return x.NextCursor
+		return x.StringValue
+	}
+	return ""
+}
+
+func (x *GraphValue) GetNumberValue() float64 {
+	if x != nil {
+		return x.NumberValue
 	}
 	return 0
 }
 
-type ListRelationshipsRequest struct {
+func (x *GraphValue) GetBoolValue() bool {
+	if x != nil {
+		return x.BoolValue
+	}
+	return false
+}
+
+type GraphRow struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Cursor        uint64                 `protobuf:"varint,1,opt,name=cursor,proto3" json:"cursor,omitempty"` // last seen relationship ID (0 = start)
-	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`   // max relationships to return (0 = server default)
+	Values        []*GraphValue          `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"` // one per column
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListRelationshipsRequest) Reset() {
-	*x = ListRelationshipsRequest{}
+func (x *GraphRow) Reset() {
+	*x = GraphRow{}
 	mi := &file_proto_gibram_proto_msgTypes[53]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListRelationshipsRequest) String() string {
+func (x *GraphRow) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListRelationshipsRequest) ProtoMessage() {}
+func (*GraphRow) ProtoMessage() {}
 
-func (x *ListRelationshipsRequest) ProtoReflect() protoreflect.Message {
+func (x *GraphRow) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_gibram_proto_msgTypes[53]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3631,46 +4442,42 @@ func (x *ListRelationshipsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListRelationshipsRequest.ProtoReflect.Descriptor instead.
-func (*ListRelationshipsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use GraphRow.ProtoReflect.Descriptor instead.
+func (*GraphRow) Descriptor() ([]byte, []int) {
 	return file_proto_gibram_proto_rawDescGZIP(), []int{53}
 }
 
-func (x *ListRelationshipsRequest) GetCursor() uint64 {
-	if x != nil {
-		return x.Cursor
-	}
-	return 0
-}
-
-func (x *ListRelationshipsRequest) GetLimit() int32 {
+func (x *GraphRow) GetValues() []*GraphValue {
 	if x != nil {
-		return x.Limit
+		return x.Values
 	}
-	return 0
+	return nil
 }
 
-type PipelineRequest struct {
+type GraphQueryResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Commands      []*Envelope            `protobuf:"bytes,1,rep,name=commands,proto3" json:"commands,omitempty"`
+	Columns       []string               `protobuf:"bytes,1,rep,name=columns,proto3" json:"columns,omitempty"`
+	Rows          []*GraphRow            `protobuf:"bytes,2,rep,name=rows,proto3" json:"rows,omitempty"`
+	Plan          []string               `protobuf:"bytes,3,rep,name=plan,proto3" json:"plan,omitempty"`            // one line per plan step
+	Truncated     bool                   `protobuf:"varint,4,opt,name=truncated,proto3" json:"truncated,omitempty"` // more rows matched than the limit
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PipelineRequest) Reset() {
-	*x = PipelineRequest{}
+func (x *GraphQueryResponse) Reset() {
+	*x = GraphQueryResponse{}
 	mi := &file_proto_gibram_proto_msgTypes[54]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PipelineRequest) String() string {
+func (x *GraphQueryResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PipelineRequest) ProtoMessage() {}
+func (*GraphQueryResponse) ProtoMessage() {}
 
-func (x *PipelineRequest) ProtoReflect() protoreflect.Message {
+func (x *GraphQueryResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_gibram_proto_msgTypes[54]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3682,39 +4489,67 @@ func (x *PipelineRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PipelineRequest.ProtoReflect.Descriptor instead.
-func (*PipelineRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use GraphQueryResponse.ProtoReflect.Descriptor instead.
+func (*GraphQueryResponse) Descriptor() ([]byte, []int) {
 	return file_proto_gibram_proto_rawDescGZIP(), []int{54}
 }
 
-func (x *PipelineRequest) GetCommands() []*Envelope {
+func (x *GraphQueryResponse) GetColumns() []string {
 	if x != nil {
-		return x.Commands
+		return x.Columns
 	}
 	return nil
 }
 
-type PipelineResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Responses     []*Envelope            `protobuf:"bytes,1,rep,name=responses,proto3" json:"responses,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *GraphQueryResponse) GetRows() []*GraphRow {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
 }
 
-func (x *PipelineResponse) Reset() {
-	*x = PipelineResponse{}
+func (x *GraphQueryResponse) GetPlan() []string {
+	if x != nil {
+		return x.Plan
+	}
+	return nil
+}
+
+func (x *GraphQueryResponse) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+// GLOBAL_SEARCH: community reports of one level, ranked and batched for
+// a map-reduce answer
+type GlobalSearchRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	QueryVector    []float32              `protobuf:"fixed32,1,rep,packed,name=query_vector,json=queryVector,proto3" json:"query_vector,omitempty"` // empty = rank by community size
+	Level          int32                  `protobuf:"varint,2,opt,name=level,proto3" json:"level,omitempty"`
+	MinCommunities int32                  `protobuf:"varint,3,opt,name=min_communities,json=minCommunities,proto3" json:"min_communities,omitempty"` // fall back to the nearest level with this many (0 = 1)
+	TokenBudget    int32                  `protobuf:"varint,4,opt,name=token_budget,json=tokenBudget,proto3" json:"token_budget,omitempty"`          // 0 = 8000
+	BatchTokens    int32                  `protobuf:"varint,5,opt,name=batch_tokens,json=batchTokens,proto3" json:"batch_tokens,omitempty"`          // tokens per map batch, 0 = 2000
+	UseSummary     bool                   `protobuf:"varint,6,opt,name=use_summary,json=useSummary,proto3" json:"use_summary,omitempty"`             // budget summaries instead of full reports
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GlobalSearchRequest) Reset() {
+	*x = GlobalSearchRequest{}
 	mi := &file_proto_gibram_proto_msgTypes[55]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PipelineResponse) String() string {
+func (x *GlobalSearchRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PipelineResponse) ProtoMessage() {}
+func (*GlobalSearchRequest) ProtoMessage() {}
 
-func (x *PipelineResponse) ProtoReflect() protoreflect.Message {
+func (x *GlobalSearchRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_gibram_proto_msgTypes[55]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3726,40 +4561,77 @@ func (x *PipelineResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PipelineResponse.ProtoReflect.Descriptor instead.
-func (*PipelineResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use GlobalSearchRequest.ProtoReflect.Descriptor instead.
+func (*GlobalSearchRequest) Descriptor() ([]byte, []int) {
 	return file_proto_gibram_proto_rawDescGZIP(), []int{55}
 }
 
-func (x *PipelineResponse) GetResponses() []*Envelope {
+func (x *GlobalSearchRequest) GetQueryVector() []float32 {
 	if x != nil {
-		return x.Responses
+		return x.QueryVector
 	}
 	return nil
 }
 
-type HierarchicalLeidenRequest struct {
+func (x *GlobalSearchRequest) GetLevel() int32 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
+func (x *GlobalSearchRequest) GetMinCommunities() int32 {
+	if x != nil {
+		return x.MinCommunities
+	}
+	return 0
+}
+
+func (x *GlobalSearchRequest) GetTokenBudget() int32 {
+	if x != nil {
+		return x.TokenBudget
+	}
+	return 0
+}
+
+func (x *GlobalSearchRequest) GetBatchTokens() int32 {
+	if x != nil {
+		return x.BatchTokens
+	}
+	return 0
+}
+
+func (x *GlobalSearchRequest) GetUseSummary() bool {
+	if x != nil {
+		return x.UseSummary
+	}
+	return false
+}
+
+type CommunityReport struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	MaxLevels     int32                  `protobuf:"varint,1,opt,name=max_levels,json=maxLevels,proto3" json:"max_levels,omitempty"`
-	Resolution    float64                `protobuf:"fixed64,2,opt,name=resolution,proto3" json:"resolution,omitempty"`
+	Community     *Community             `protobuf:"bytes,1,opt,name=community,proto3" json:"community,omitempty"`
+	Score         float32                `protobuf:"fixed32,2,opt,name=score,proto3" json:"score,omitempty"`  // similarity to the query vector
+	Rank          int32                  `protobuf:"varint,3,opt,name=rank,proto3" json:"rank,omitempty"`     // 1-based among the level's communities
+	Tokens        int32                  `protobuf:"varint,4,opt,name=tokens,proto3" json:"tokens,omitempty"` // estimated tokens of title and report
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *HierarchicalLeidenRequest) Reset() {
-	*x = HierarchicalLeidenRequest{}
+func (x *CommunityReport) Reset() {
+	*x = CommunityReport{}
 	mi := &file_proto_gibram_proto_msgTypes[56]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *HierarchicalLeidenRequest) String() string {
+func (x *CommunityReport) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HierarchicalLeidenRequest) ProtoMessage() {}
+func (*CommunityReport) ProtoMessage() {}
 
-func (x *HierarchicalLeidenRequest) ProtoReflect() protoreflect.Message {
+func (x *CommunityReport) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_gibram_proto_msgTypes[56]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3771,47 +4643,61 @@ func (x *HierarchicalLeidenRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HierarchicalLeidenRequest.ProtoReflect.Descriptor instead.
-func (*HierarchicalLeidenRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use CommunityReport.ProtoReflect.Descriptor instead.
+func (*CommunityReport) Descriptor() ([]byte, []int) {
 	return file_proto_gibram_proto_rawDescGZIP(), []int{56}
 }
 
-func (x *HierarchicalLeidenRequest) GetMaxLevels() int32 {
+func (x *CommunityReport) GetCommunity() *Community {
 	if x != nil {
-		return x.MaxLevels
+		return x.Community
+	}
+	return nil
+}
+
+func (x *CommunityReport) GetScore() float32 {
+	if x != nil {
+		return x.Score
 	}
 	return 0
 }
 
-func (x *HierarchicalLeidenRequest) GetResolution() float64 {
+func (x *CommunityReport) GetRank() int32 {
 	if x != nil {
-		return x.Resolution
+		return x.Rank
 	}
 	return 0
 }
 
-type HierarchicalLeidenResponse struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	LevelCounts      map[int32]int32        `protobuf:"bytes,1,rep,name=level_counts,json=levelCounts,proto3" json:"level_counts,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"` // level -> count
-	TotalCommunities int32                  `protobuf:"varint,2,opt,name=total_communities,json=totalCommunities,proto3" json:"total_communities,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+func (x *CommunityReport) GetTokens() int32 {
+	if x != nil {
+		return x.Tokens
+	}
+	return 0
 }
 
-func (x *HierarchicalLeidenResponse) Reset() {
-	*x = HierarchicalLeidenResponse{}
+type ReportBatch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reports       []*CommunityReport     `protobuf:"bytes,1,rep,name=reports,proto3" json:"reports,omitempty"`
+	Tokens        int32                  `protobuf:"varint,2,opt,name=tokens,proto3" json:"tokens,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportBatch) Reset() {
+	*x = ReportBatch{}
 	mi := &file_proto_gibram_proto_msgTypes[57]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *HierarchicalLeidenResponse) String() string {
+func (x *ReportBatch) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HierarchicalLeidenResponse) ProtoMessage() {}
+func (*ReportBatch) ProtoMessage() {}
 
-func (x *HierarchicalLeidenResponse) ProtoReflect() protoreflect.Message {
+func (x *ReportBatch) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_gibram_proto_msgTypes[57]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3823,46 +4709,51 @@ func (x *HierarchicalLeidenResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HierarchicalLeidenResponse.ProtoReflect.Descriptor instead.
-func (*HierarchicalLeidenResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ReportBatch.ProtoReflect.Descriptor instead.
+func (*ReportBatch) Descriptor() ([]byte, []int) {
 	return file_proto_gibram_proto_rawDescGZIP(), []int{57}
 }
 
-func (x *HierarchicalLeidenResponse) GetLevelCounts() map[int32]int32 {
+func (x *ReportBatch) GetReports() []*CommunityReport {
 	if x != nil {
-		return x.LevelCounts
+		return x.Reports
 	}
 	return nil
 }
 
-func (x *HierarchicalLeidenResponse) GetTotalCommunities() int32 {
+func (x *ReportBatch) GetTokens() int32 {
 	if x != nil {
-		return x.TotalCommunities
+		return x.Tokens
 	}
 	return 0
 }
 
-type SaveRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"` // optional, uses default if empty
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *SaveRequest) Reset() {
-	*x = SaveRequest{}
+type GlobalSearchResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Level          int32                  `protobuf:"varint,1,opt,name=level,proto3" json:"level,omitempty"` // level searched, after fallback
+	RequestedLevel int32                  `protobuf:"varint,2,opt,name=requested_level,json=requestedLevel,proto3" json:"requested_level,omitempty"`
+	Candidates     int32                  `protobuf:"varint,3,opt,name=candidates,proto3" json:"candidates,omitempty"` // communities ranked at level
+	Batches        []*ReportBatch         `protobuf:"bytes,4,rep,name=batches,proto3" json:"batches,omitempty"`        // best reports first
+	TokensUsed     int32                  `protobuf:"varint,5,opt,name=tokens_used,json=tokensUsed,proto3" json:"tokens_used,omitempty"`
+	Skipped        int32                  `protobuf:"varint,6,opt,name=skipped,proto3" json:"skipped,omitempty"` // ranked reports left out by the budget
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GlobalSearchResponse) Reset() {
+	*x = GlobalSearchResponse{}
 	mi := &file_proto_gibram_proto_msgTypes[58]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SaveRequest) String() string {
+func (x *GlobalSearchResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SaveRequest) ProtoMessage() {}
+func (*GlobalSearchResponse) ProtoMessage() {}
 
-func (x *SaveRequest) ProtoReflect() protoreflect.Message {
+func (x *GlobalSearchResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_gibram_proto_msgTypes[58]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3874,39 +4765,74 @@ func (x *SaveRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SaveRequest.ProtoReflect.Descriptor instead.
-func (*SaveRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use GlobalSearchResponse.ProtoReflect.Descriptor instead.
+func (*GlobalSearchResponse) Descriptor() ([]byte, []int) {
 	return file_proto_gibram_proto_rawDescGZIP(), []int{58}
 }
 
-func (x *SaveRequest) GetPath() string {
+func (x *GlobalSearchResponse) GetLevel() int32 {
 	if x != nil {
-		return x.Path
+		return x.Level
 	}
-	return ""
+	return 0
 }
 
-type RestoreRequest struct {
+func (x *GlobalSearchResponse) GetRequestedLevel() int32 {
+	if x != nil {
+		return x.RequestedLevel
+	}
+	return 0
+}
+
+func (x *GlobalSearchResponse) GetCandidates() int32 {
+	if x != nil {
+		return x.Candidates
+	}
+	return 0
+}
+
+func (x *GlobalSearchResponse) GetBatches() []*ReportBatch {
+	if x != nil {
+		return x.Batches
+	}
+	return nil
+}
+
+func (x *GlobalSearchResponse) GetTokensUsed() int32 {
+	if x != nil {
+		return x.TokensUsed
+	}
+	return 0
+}
+
+func (x *GlobalSearchResponse) GetSkipped() int32 {
+	if x != nil {
+		return x.Skipped
+	}
+	return 0
+}
+
+type ExplainRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	QueryId       uint64                 `protobuf:"varint,1,opt,name=query_id,json=queryId,proto3" json:"query_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RestoreRequest) Reset() {
-	*x = RestoreRequest{}
+func (x *ExplainRequest) Reset() {
+	*x = ExplainRequest{}
 	mi := &file_proto_gibram_proto_msgTypes[59]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RestoreRequest) String() string {
+func (x *ExplainRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RestoreRequest) ProtoMessage() {}
+func (*ExplainRequest) ProtoMessage() {}
 
-func (x *RestoreRequest) ProtoReflect() protoreflect.Message {
+func (x *ExplainRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_gibram_proto_msgTypes[59]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3918,46 +4844,215 @@ func (x *RestoreRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RestoreRequest.ProtoReflect.Descriptor instead.
-func (*RestoreRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ExplainRequest.ProtoReflect.Descriptor instead.
+func (*ExplainRequest) Descriptor() ([]byte, []int) {
 	return file_proto_gibram_proto_rawDescGZIP(), []int{59}
 }
 
-func (x *RestoreRequest) GetPath() string {
+func (x *ExplainRequest) GetQueryId() uint64 {
 	if x != nil {
-		return x.Path
+		return x.QueryId
+	}
+	return 0
+}
+
+type SeedInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Id            uint64                 `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	ExternalId    string                 `protobuf:"bytes,3,opt,name=external_id,json=externalId,proto3" json:"external_id,omitempty"`
+	Similarity    float32                `protobuf:"fixed32,4,opt,name=similarity,proto3" json:"similarity,omitempty"`
+	Lexical       float32                `protobuf:"fixed32,5,opt,name=lexical,proto3" json:"lexical,omitempty"` // BM25 score, hybrid queries only
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SeedInfo) Reset() {
+	*x = SeedInfo{}
+	mi := &file_proto_gibram_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SeedInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SeedInfo) ProtoMessage() {}
+
+func (x *SeedInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SeedInfo.ProtoReflect.Descriptor instead.
+func (*SeedInfo) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *SeedInfo) GetType() string {
+	if x != nil {
+		return x.Type
 	}
 	return ""
 }
 
-type BackupStatusResponse struct {
+func (x *SeedInfo) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *SeedInfo) GetExternalId() string {
+	if x != nil {
+		return x.ExternalId
+	}
+	return ""
+}
+
+func (x *SeedInfo) GetSimilarity() float32 {
+	if x != nil {
+		return x.Similarity
+	}
+	return 0
+}
+
+func (x *SeedInfo) GetLexical() float32 {
+	if x != nil {
+		return x.Lexical
+	}
+	return 0
+}
+
+type TraversalStep struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
-	InProgress      bool                   `protobuf:"varint,1,opt,name=in_progress,json=inProgress,proto3" json:"in_progress,omitempty"`
-	Type            string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"` // "save", "restore", ""
-	StartTime       int64                  `protobuf:"varint,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
-	ProgressPercent int32                  `protobuf:"varint,4,opt,name=progress_percent,json=progressPercent,proto3" json:"progress_percent,omitempty"`
-	Error           string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
-	LastSaveTime    int64                  `protobuf:"varint,6,opt,name=last_save_time,json=lastSaveTime,proto3" json:"last_save_time,omitempty"`
-	LastSavePath    string                 `protobuf:"bytes,7,opt,name=last_save_path,json=lastSavePath,proto3" json:"last_save_path,omitempty"`
+	FromEntityId    uint64                 `protobuf:"varint,1,opt,name=from_entity_id,json=fromEntityId,proto3" json:"from_entity_id,omitempty"`
+	ToEntityId      uint64                 `protobuf:"varint,2,opt,name=to_entity_id,json=toEntityId,proto3" json:"to_entity_id,omitempty"`
+	RelationshipId  uint64                 `protobuf:"varint,3,opt,name=relationship_id,json=relationshipId,proto3" json:"relationship_id,omitempty"`
+	RelType         string                 `protobuf:"bytes,4,opt,name=rel_type,json=relType,proto3" json:"rel_type,omitempty"`
+	Weight          float32                `protobuf:"fixed32,5,opt,name=weight,proto3" json:"weight,omitempty"`
+	Hop             int32                  `protobuf:"varint,6,opt,name=hop,proto3" json:"hop,omitempty"`
+	CumulativeScore float32                `protobuf:"fixed32,7,opt,name=cumulative_score,json=cumulativeScore,proto3" json:"cumulative_score,omitempty"`
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
 
-func (x *BackupStatusResponse) Reset() {
-	*x = BackupStatusResponse{}
-	mi := &file_proto_gibram_proto_msgTypes[60]
+func (x *TraversalStep) Reset() {
+	*x = TraversalStep{}
+	mi := &file_proto_gibram_proto_msgTypes[61]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *BackupStatusResponse) String() string {
+func (x *TraversalStep) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BackupStatusResponse) ProtoMessage() {}
+func (*TraversalStep) ProtoMessage() {}
 
-func (x *BackupStatusResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[60]
+func (x *TraversalStep) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TraversalStep.ProtoReflect.Descriptor instead.
+func (*TraversalStep) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *TraversalStep) GetFromEntityId() uint64 {
+	if x != nil {
+		return x.FromEntityId
+	}
+	return 0
+}
+
+func (x *TraversalStep) GetToEntityId() uint64 {
+	if x != nil {
+		return x.ToEntityId
+	}
+	return 0
+}
+
+func (x *TraversalStep) GetRelationshipId() uint64 {
+	if x != nil {
+		return x.RelationshipId
+	}
+	return 0
+}
+
+func (x *TraversalStep) GetRelType() string {
+	if x != nil {
+		return x.RelType
+	}
+	return ""
+}
+
+func (x *TraversalStep) GetWeight() float32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *TraversalStep) GetHop() int32 {
+	if x != nil {
+		return x.Hop
+	}
+	return 0
+}
+
+func (x *TraversalStep) GetCumulativeScore() float32 {
+	if x != nil {
+		return x.CumulativeScore
+	}
+	return 0
+}
+
+type Demotion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Id            uint64                 `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	ExternalId    string                 `protobuf:"bytes,3,opt,name=external_id,json=externalId,proto3" json:"external_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`                   // "mmr", "duplicate" or "document_cap"
+	Rank          int32                  `protobuf:"varint,5,opt,name=rank,proto3" json:"rank,omitempty"`                      // rank by score
+	NewRank       int32                  `protobuf:"varint,6,opt,name=new_rank,json=newRank,proto3" json:"new_rank,omitempty"` // rank after re-ranking, 0 = dropped
+	SimilarTo     uint64                 `protobuf:"varint,7,opt,name=similar_to,json=similarTo,proto3" json:"similar_to,omitempty"`
+	Similarity    float32                `protobuf:"fixed32,8,opt,name=similarity,proto3" json:"similarity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Demotion) Reset() {
+	*x = Demotion{}
+	mi := &file_proto_gibram_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Demotion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Demotion) ProtoMessage() {}
+
+func (x *Demotion) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[62]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3965,86 +5060,2111 @@ func (x *BackupStatusResponse) ProtoReflect() protoreflect.Message {
 		}
 		return ms
 	}
-	return mi.MessageOf(x)
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Demotion.ProtoReflect.Descriptor instead.
+func (*Demotion) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *Demotion) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Demotion) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Demotion) GetExternalId() string {
+	if x != nil {
+		return x.ExternalId
+	}
+	return ""
+}
+
+func (x *Demotion) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Demotion) GetRank() int32 {
+	if x != nil {
+		return x.Rank
+	}
+	return 0
+}
+
+func (x *Demotion) GetNewRank() int32 {
+	if x != nil {
+		return x.NewRank
+	}
+	return 0
+}
+
+func (x *Demotion) GetSimilarTo() uint64 {
+	if x != nil {
+		return x.SimilarTo
+	}
+	return 0
+}
+
+func (x *Demotion) GetSimilarity() float32 {
+	if x != nil {
+		return x.Similarity
+	}
+	return 0
+}
+
+type ExplainResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	QueryId       uint64                 `protobuf:"varint,1,opt,name=query_id,json=queryId,proto3" json:"query_id,omitempty"`
+	Seeds         []*SeedInfo            `protobuf:"bytes,2,rep,name=seeds,proto3" json:"seeds,omitempty"`
+	Traversal     []*TraversalStep       `protobuf:"bytes,3,rep,name=traversal,proto3" json:"traversal,omitempty"`
+	Demotions     []*Demotion            `protobuf:"bytes,4,rep,name=demotions,proto3" json:"demotions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExplainResponse) Reset() {
+	*x = ExplainResponse{}
+	mi := &file_proto_gibram_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExplainResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExplainResponse) ProtoMessage() {}
+
+func (x *ExplainResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExplainResponse.ProtoReflect.Descriptor instead.
+func (*ExplainResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *ExplainResponse) GetQueryId() uint64 {
+	if x != nil {
+		return x.QueryId
+	}
+	return 0
+}
+
+func (x *ExplainResponse) GetSeeds() []*SeedInfo {
+	if x != nil {
+		return x.Seeds
+	}
+	return nil
+}
+
+func (x *ExplainResponse) GetTraversal() []*TraversalStep {
+	if x != nil {
+		return x.Traversal
+	}
+	return nil
+}
+
+func (x *ExplainResponse) GetDemotions() []*Demotion {
+	if x != nil {
+		return x.Demotions
+	}
+	return nil
+}
+
+type GetByIDRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetByIDRequest) Reset() {
+	*x = GetByIDRequest{}
+	mi := &file_proto_gibram_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetByIDRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetByIDRequest) ProtoMessage() {}
+
+func (x *GetByIDRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetByIDRequest.ProtoReflect.Descriptor instead.
+func (*GetByIDRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *GetByIDRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DeleteByIDRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteByIDRequest) Reset() {
+	*x = DeleteByIDRequest{}
+	mi := &file_proto_gibram_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteByIDRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteByIDRequest) ProtoMessage() {}
+
+func (x *DeleteByIDRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteByIDRequest.ProtoReflect.Descriptor instead.
+func (*DeleteByIDRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *DeleteByIDRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type HealthResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"` // "ok", "degraded", "error"
+	Components    map[string]string      `protobuf:"bytes,2,rep,name=components,proto3" json:"components,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HealthResponse) Reset() {
+	*x = HealthResponse{}
+	mi := &file_proto_gibram_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthResponse) ProtoMessage() {}
+
+func (x *HealthResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthResponse.ProtoReflect.Descriptor instead.
+func (*HealthResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *HealthResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *HealthResponse) GetComponents() map[string]string {
+	if x != nil {
+		return x.Components
+	}
+	return nil
+}
+
+type ListEntitiesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cursor        uint64                 `protobuf:"varint,1,opt,name=cursor,proto3" json:"cursor,omitempty"` // last seen entity ID (0 = start)
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`   // max entities to return (0 = server default)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEntitiesRequest) Reset() {
+	*x = ListEntitiesRequest{}
+	mi := &file_proto_gibram_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEntitiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEntitiesRequest) ProtoMessage() {}
+
+func (x *ListEntitiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEntitiesRequest.ProtoReflect.Descriptor instead.
+func (*ListEntitiesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *ListEntitiesRequest) GetCursor() uint64 {
+	if x != nil {
+		return x.Cursor
+	}
+	return 0
+}
+
+func (x *ListEntitiesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type MSetEntitiesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entities      []*AddEntityRequest    `protobuf:"bytes,1,rep,name=entities,proto3" json:"entities,omitempty"`
+	Bulk          bool                   `protobuf:"varint,2,opt,name=bulk,proto3" json:"bulk,omitempty"` // build the vectors concurrently after inserting
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MSetEntitiesRequest) Reset() {
+	*x = MSetEntitiesRequest{}
+	mi := &file_proto_gibram_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MSetEntitiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MSetEntitiesRequest) ProtoMessage() {}
+
+func (x *MSetEntitiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MSetEntitiesRequest.ProtoReflect.Descriptor instead.
+func (*MSetEntitiesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *MSetEntitiesRequest) GetEntities() []*AddEntityRequest {
+	if x != nil {
+		return x.Entities
+	}
+	return nil
+}
+
+func (x *MSetEntitiesRequest) GetBulk() bool {
+	if x != nil {
+		return x.Bulk
+	}
+	return false
+}
+
+type MGetEntitiesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []uint64               `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MGetEntitiesRequest) Reset() {
+	*x = MGetEntitiesRequest{}
+	mi := &file_proto_gibram_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MGetEntitiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MGetEntitiesRequest) ProtoMessage() {}
+
+func (x *MGetEntitiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MGetEntitiesRequest.ProtoReflect.Descriptor instead.
+func (*MGetEntitiesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *MGetEntitiesRequest) GetIds() []uint64 {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type EntitiesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entities      []*Entity              `protobuf:"bytes,1,rep,name=entities,proto3" json:"entities,omitempty"`
+	CreatedIds    []uint64               `protobuf:"varint,2,rep,packed,name=created_ids,json=createdIds,proto3" json:"created_ids,omitempty"` // for MSET responses
+	NextCursor    uint64                 `protobuf:"varint,3,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`        // for LIST responses (0 = no more)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EntitiesResponse) Reset() {
+	*x = EntitiesResponse{}
+	mi := &file_proto_gibram_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EntitiesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EntitiesResponse) ProtoMessage() {}
+
+func (x *EntitiesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EntitiesResponse.ProtoReflect.Descriptor instead.
+func (*EntitiesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *EntitiesResponse) GetEntities() []*Entity {
+	if x != nil {
+		return x.Entities
+	}
+	return nil
+}
+
+func (x *EntitiesResponse) GetCreatedIds() []uint64 {
+	if x != nil {
+		return x.CreatedIds
+	}
+	return nil
+}
+
+func (x *EntitiesResponse) GetNextCursor() uint64 {
+	if x != nil {
+		return x.NextCursor
+	}
+	return 0
+}
+
+type MSetDocumentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Documents     []*AddDocumentRequest  `protobuf:"bytes,1,rep,name=documents,proto3" json:"documents,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MSetDocumentsRequest) Reset() {
+	*x = MSetDocumentsRequest{}
+	mi := &file_proto_gibram_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MSetDocumentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MSetDocumentsRequest) ProtoMessage() {}
+
+func (x *MSetDocumentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MSetDocumentsRequest.ProtoReflect.Descriptor instead.
+func (*MSetDocumentsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *MSetDocumentsRequest) GetDocuments() []*AddDocumentRequest {
+	if x != nil {
+		return x.Documents
+	}
+	return nil
+}
+
+type MGetDocumentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []uint64               `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MGetDocumentsRequest) Reset() {
+	*x = MGetDocumentsRequest{}
+	mi := &file_proto_gibram_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MGetDocumentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MGetDocumentsRequest) ProtoMessage() {}
+
+func (x *MGetDocumentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MGetDocumentsRequest.ProtoReflect.Descriptor instead.
+func (*MGetDocumentsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *MGetDocumentsRequest) GetIds() []uint64 {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type DocumentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Documents     []*Document            `protobuf:"bytes,1,rep,name=documents,proto3" json:"documents,omitempty"`
+	CreatedIds    []uint64               `protobuf:"varint,2,rep,packed,name=created_ids,json=createdIds,proto3" json:"created_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DocumentsResponse) Reset() {
+	*x = DocumentsResponse{}
+	mi := &file_proto_gibram_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DocumentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DocumentsResponse) ProtoMessage() {}
+
+func (x *DocumentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[73]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DocumentsResponse.ProtoReflect.Descriptor instead.
+func (*DocumentsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *DocumentsResponse) GetDocuments() []*Document {
+	if x != nil {
+		return x.Documents
+	}
+	return nil
+}
+
+func (x *DocumentsResponse) GetCreatedIds() []uint64 {
+	if x != nil {
+		return x.CreatedIds
+	}
+	return nil
+}
+
+type MSetTextUnitsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Textunits     []*AddTextUnitRequest  `protobuf:"bytes,1,rep,name=textunits,proto3" json:"textunits,omitempty"`
+	Bulk          bool                   `protobuf:"varint,2,opt,name=bulk,proto3" json:"bulk,omitempty"` // build the vectors concurrently after inserting
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MSetTextUnitsRequest) Reset() {
+	*x = MSetTextUnitsRequest{}
+	mi := &file_proto_gibram_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MSetTextUnitsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MSetTextUnitsRequest) ProtoMessage() {}
+
+func (x *MSetTextUnitsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[74]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MSetTextUnitsRequest.ProtoReflect.Descriptor instead.
+func (*MSetTextUnitsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *MSetTextUnitsRequest) GetTextunits() []*AddTextUnitRequest {
+	if x != nil {
+		return x.Textunits
+	}
+	return nil
+}
+
+func (x *MSetTextUnitsRequest) GetBulk() bool {
+	if x != nil {
+		return x.Bulk
+	}
+	return false
+}
+
+type MGetTextUnitsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []uint64               `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MGetTextUnitsRequest) Reset() {
+	*x = MGetTextUnitsRequest{}
+	mi := &file_proto_gibram_proto_msgTypes[75]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MGetTextUnitsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MGetTextUnitsRequest) ProtoMessage() {}
+
+func (x *MGetTextUnitsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[75]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MGetTextUnitsRequest.ProtoReflect.Descriptor instead.
+func (*MGetTextUnitsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *MGetTextUnitsRequest) GetIds() []uint64 {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type TextUnitsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Textunits     []*TextUnit            `protobuf:"bytes,1,rep,name=textunits,proto3" json:"textunits,omitempty"`
+	CreatedIds    []uint64               `protobuf:"varint,2,rep,packed,name=created_ids,json=createdIds,proto3" json:"created_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TextUnitsResponse) Reset() {
+	*x = TextUnitsResponse{}
+	mi := &file_proto_gibram_proto_msgTypes[76]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TextUnitsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TextUnitsResponse) ProtoMessage() {}
+
+func (x *TextUnitsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[76]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TextUnitsResponse.ProtoReflect.Descriptor instead.
+func (*TextUnitsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *TextUnitsResponse) GetTextunits() []*TextUnit {
+	if x != nil {
+		return x.Textunits
+	}
+	return nil
+}
+
+func (x *TextUnitsResponse) GetCreatedIds() []uint64 {
+	if x != nil {
+		return x.CreatedIds
+	}
+	return nil
+}
+
+type MSetRelationshipsRequest struct {
+	state         protoimpl.MessageState    `protogen:"open.v1"`
+	Relationships []*AddRelationshipRequest `protobuf:"bytes,1,rep,name=relationships,proto3" json:"relationships,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MSetRelationshipsRequest) Reset() {
+	*x = MSetRelationshipsRequest{}
+	mi := &file_proto_gibram_proto_msgTypes[77]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MSetRelationshipsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MSetRelationshipsRequest) ProtoMessage() {}
+
+func (x *MSetRelationshipsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[77]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MSetRelationshipsRequest.ProtoReflect.Descriptor instead.
+func (*MSetRelationshipsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{77}
+}
+
+func (x *MSetRelationshipsRequest) GetRelationships() []*AddRelationshipRequest {
+	if x != nil {
+		return x.Relationships
+	}
+	return nil
+}
+
+type MGetRelationshipsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []uint64               `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MGetRelationshipsRequest) Reset() {
+	*x = MGetRelationshipsRequest{}
+	mi := &file_proto_gibram_proto_msgTypes[78]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MGetRelationshipsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MGetRelationshipsRequest) ProtoMessage() {}
+
+func (x *MGetRelationshipsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[78]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MGetRelationshipsRequest.ProtoReflect.Descriptor instead.
+func (*MGetRelationshipsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{78}
+}
+
+func (x *MGetRelationshipsRequest) GetIds() []uint64 {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type RelationshipsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Relationships []*Relationship        `protobuf:"bytes,1,rep,name=relationships,proto3" json:"relationships,omitempty"`
+	CreatedIds    []uint64               `protobuf:"varint,2,rep,packed,name=created_ids,json=createdIds,proto3" json:"created_ids,omitempty"`
+	NextCursor    uint64                 `protobuf:"varint,3,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"` // for LIST responses (0 = no more)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RelationshipsResponse) Reset() {
+	*x = RelationshipsResponse{}
+	mi := &file_proto_gibram_proto_msgTypes[79]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RelationshipsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RelationshipsResponse) ProtoMessage() {}
+
+func (x *RelationshipsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[79]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RelationshipsResponse.ProtoReflect.Descriptor instead.
+func (*RelationshipsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{79}
+}
+
+func (x *RelationshipsResponse) GetRelationships() []*Relationship {
+	if x != nil {
+		return x.Relationships
+	}
+	return nil
+}
+
+func (x *RelationshipsResponse) GetCreatedIds() []uint64 {
+	if x != nil {
+		return x.CreatedIds
+	}
+	return nil
+}
+
+func (x *RelationshipsResponse) GetNextCursor() uint64 {
+	if x != nil {
+		return x.NextCursor
+	}
+	return 0
+}
+
+type ListRelationshipsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cursor        uint64                 `protobuf:"varint,1,opt,name=cursor,proto3" json:"cursor,omitempty"` // last seen relationship ID (0 = start)
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`   // max relationships to return (0 = server default)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRelationshipsRequest) Reset() {
+	*x = ListRelationshipsRequest{}
+	mi := &file_proto_gibram_proto_msgTypes[80]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRelationshipsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRelationshipsRequest) ProtoMessage() {}
+
+func (x *ListRelationshipsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[80]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRelationshipsRequest.ProtoReflect.Descriptor instead.
+func (*ListRelationshipsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{80}
+}
+
+func (x *ListRelationshipsRequest) GetCursor() uint64 {
+	if x != nil {
+		return x.Cursor
+	}
+	return 0
+}
+
+func (x *ListRelationshipsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// BulkCommitRequest ends a bulk load started by CMD_BULK_BEGIN and builds
+// the deferred vectors concurrently. Progress is reported by
+// CMD_REBUILD_STATUS; without async the command returns once built.
+type BulkCommitRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Workers       int32                  `protobuf:"varint,1,opt,name=workers,proto3" json:"workers,omitempty"` // build goroutines (0 = one per CPU)
+	Async         bool                   `protobuf:"varint,2,opt,name=async,proto3" json:"async,omitempty"`     // return the task immediately
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkCommitRequest) Reset() {
+	*x = BulkCommitRequest{}
+	mi := &file_proto_gibram_proto_msgTypes[81]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkCommitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkCommitRequest) ProtoMessage() {}
+
+func (x *BulkCommitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[81]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkCommitRequest.ProtoReflect.Descriptor instead.
+func (*BulkCommitRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{81}
+}
+
+func (x *BulkCommitRequest) GetWorkers() int32 {
+	if x != nil {
+		return x.Workers
+	}
+	return 0
+}
+
+func (x *BulkCommitRequest) GetAsync() bool {
+	if x != nil {
+		return x.Async
+	}
+	return false
+}
+
+type PipelineRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Commands      []*Envelope            `protobuf:"bytes,1,rep,name=commands,proto3" json:"commands,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PipelineRequest) Reset() {
+	*x = PipelineRequest{}
+	mi := &file_proto_gibram_proto_msgTypes[82]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PipelineRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PipelineRequest) ProtoMessage() {}
+
+func (x *PipelineRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[82]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PipelineRequest.ProtoReflect.Descriptor instead.
+func (*PipelineRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{82}
+}
+
+func (x *PipelineRequest) GetCommands() []*Envelope {
+	if x != nil {
+		return x.Commands
+	}
+	return nil
+}
+
+type PipelineResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Responses     []*Envelope            `protobuf:"bytes,1,rep,name=responses,proto3" json:"responses,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PipelineResponse) Reset() {
+	*x = PipelineResponse{}
+	mi := &file_proto_gibram_proto_msgTypes[83]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PipelineResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PipelineResponse) ProtoMessage() {}
+
+func (x *PipelineResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[83]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PipelineResponse.ProtoReflect.Descriptor instead.
+func (*PipelineResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{83}
+}
+
+func (x *PipelineResponse) GetResponses() []*Envelope {
+	if x != nil {
+		return x.Responses
+	}
+	return nil
+}
+
+type HierarchicalLeidenRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MaxLevels     int32                  `protobuf:"varint,1,opt,name=max_levels,json=maxLevels,proto3" json:"max_levels,omitempty"`
+	Resolution    float64                `protobuf:"fixed64,2,opt,name=resolution,proto3" json:"resolution,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HierarchicalLeidenRequest) Reset() {
+	*x = HierarchicalLeidenRequest{}
+	mi := &file_proto_gibram_proto_msgTypes[84]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HierarchicalLeidenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HierarchicalLeidenRequest) ProtoMessage() {}
+
+func (x *HierarchicalLeidenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[84]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HierarchicalLeidenRequest.ProtoReflect.Descriptor instead.
+func (*HierarchicalLeidenRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{84}
+}
+
+func (x *HierarchicalLeidenRequest) GetMaxLevels() int32 {
+	if x != nil {
+		return x.MaxLevels
+	}
+	return 0
+}
+
+func (x *HierarchicalLeidenRequest) GetResolution() float64 {
+	if x != nil {
+		return x.Resolution
+	}
+	return 0
+}
+
+type HierarchicalLeidenResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	LevelCounts      map[int32]int32        `protobuf:"bytes,1,rep,name=level_counts,json=levelCounts,proto3" json:"level_counts,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"` // level -> count
+	TotalCommunities int32                  `protobuf:"varint,2,opt,name=total_communities,json=totalCommunities,proto3" json:"total_communities,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *HierarchicalLeidenResponse) Reset() {
+	*x = HierarchicalLeidenResponse{}
+	mi := &file_proto_gibram_proto_msgTypes[85]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HierarchicalLeidenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HierarchicalLeidenResponse) ProtoMessage() {}
+
+func (x *HierarchicalLeidenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[85]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HierarchicalLeidenResponse.ProtoReflect.Descriptor instead.
+func (*HierarchicalLeidenResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{85}
+}
+
+func (x *HierarchicalLeidenResponse) GetLevelCounts() map[int32]int32 {
+	if x != nil {
+		return x.LevelCounts
+	}
+	return nil
+}
+
+func (x *HierarchicalLeidenResponse) GetTotalCommunities() int32 {
+	if x != nil {
+		return x.TotalCommunities
+	}
+	return 0
+}
+
+// RebuildIndexRequest rebuilds the session's vector indices online. A given
+// IndexOptions replaces that index's configuration; omitted indices keep
+// theirs. Without async the command returns once the rebuild completes.
+type RebuildIndexRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	TextunitIndex  *IndexOptions          `protobuf:"bytes,1,opt,name=textunit_index,json=textunitIndex,proto3" json:"textunit_index,omitempty"`
+	EntityIndex    *IndexOptions          `protobuf:"bytes,2,opt,name=entity_index,json=entityIndex,proto3" json:"entity_index,omitempty"`
+	CommunityIndex *IndexOptions          `protobuf:"bytes,3,opt,name=community_index,json=communityIndex,proto3" json:"community_index,omitempty"`
+	Async          bool                   `protobuf:"varint,4,opt,name=async,proto3" json:"async,omitempty"` // return the task immediately
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RebuildIndexRequest) Reset() {
+	*x = RebuildIndexRequest{}
+	mi := &file_proto_gibram_proto_msgTypes[86]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RebuildIndexRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RebuildIndexRequest) ProtoMessage() {}
+
+func (x *RebuildIndexRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[86]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RebuildIndexRequest.ProtoReflect.Descriptor instead.
+func (*RebuildIndexRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{86}
+}
+
+func (x *RebuildIndexRequest) GetTextunitIndex() *IndexOptions {
+	if x != nil {
+		return x.TextunitIndex
+	}
+	return nil
+}
+
+func (x *RebuildIndexRequest) GetEntityIndex() *IndexOptions {
+	if x != nil {
+		return x.EntityIndex
+	}
+	return nil
+}
+
+func (x *RebuildIndexRequest) GetCommunityIndex() *IndexOptions {
+	if x != nil {
+		return x.CommunityIndex
+	}
+	return nil
+}
+
+func (x *RebuildIndexRequest) GetAsync() bool {
+	if x != nil {
+		return x.Async
+	}
+	return false
+}
+
+type RebuildStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"` // empty = all tasks of the session
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RebuildStatusRequest) Reset() {
+	*x = RebuildStatusRequest{}
+	mi := &file_proto_gibram_proto_msgTypes[87]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RebuildStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RebuildStatusRequest) ProtoMessage() {}
+
+func (x *RebuildStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[87]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RebuildStatusRequest.ProtoReflect.Descriptor instead.
+func (*RebuildStatusRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{87}
+}
+
+func (x *RebuildStatusRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+type RebuildTask struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	SessionId     string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`                         // "running", "complete" or "failed"
+	Index         string                 `protobuf:"bytes,4,opt,name=index,proto3" json:"index,omitempty"`                           // index being rebuilt
+	Progress      float64                `protobuf:"fixed64,5,opt,name=progress,proto3" json:"progress,omitempty"`                   // 0.0 to 1.0
+	StartTime     int64                  `protobuf:"varint,6,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"` // unix nanoseconds
+	EndTime       int64                  `protobuf:"varint,7,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`       // unix nanoseconds, 0 while running
+	Error         string                 `protobuf:"bytes,8,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RebuildTask) Reset() {
+	*x = RebuildTask{}
+	mi := &file_proto_gibram_proto_msgTypes[88]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RebuildTask) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RebuildTask) ProtoMessage() {}
+
+func (x *RebuildTask) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[88]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RebuildTask.ProtoReflect.Descriptor instead.
+func (*RebuildTask) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{88}
+}
+
+func (x *RebuildTask) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *RebuildTask) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *RebuildTask) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *RebuildTask) GetIndex() string {
+	if x != nil {
+		return x.Index
+	}
+	return ""
+}
+
+func (x *RebuildTask) GetProgress() float64 {
+	if x != nil {
+		return x.Progress
+	}
+	return 0
+}
+
+func (x *RebuildTask) GetStartTime() int64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *RebuildTask) GetEndTime() int64 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+func (x *RebuildTask) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type RebuildStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tasks         []*RebuildTask         `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RebuildStatusResponse) Reset() {
+	*x = RebuildStatusResponse{}
+	mi := &file_proto_gibram_proto_msgTypes[89]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RebuildStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RebuildStatusResponse) ProtoMessage() {}
+
+func (x *RebuildStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[89]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RebuildStatusResponse.ProtoReflect.Descriptor instead.
+func (*RebuildStatusResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{89}
+}
+
+func (x *RebuildStatusResponse) GetTasks() []*RebuildTask {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+type SaveRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"` // optional, uses default if empty
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SaveRequest) Reset() {
+	*x = SaveRequest{}
+	mi := &file_proto_gibram_proto_msgTypes[90]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SaveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SaveRequest) ProtoMessage() {}
+
+func (x *SaveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[90]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SaveRequest.ProtoReflect.Descriptor instead.
+func (*SaveRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{90}
+}
+
+func (x *SaveRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type RestoreRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreRequest) Reset() {
+	*x = RestoreRequest{}
+	mi := &file_proto_gibram_proto_msgTypes[91]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreRequest) ProtoMessage() {}
+
+func (x *RestoreRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[91]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreRequest.ProtoReflect.Descriptor instead.
+func (*RestoreRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{91}
+}
+
+func (x *RestoreRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type BackupStatusResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	InProgress      bool                   `protobuf:"varint,1,opt,name=in_progress,json=inProgress,proto3" json:"in_progress,omitempty"`
+	Type            string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"` // "save", "restore", ""
+	StartTime       int64                  `protobuf:"varint,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	ProgressPercent int32                  `protobuf:"varint,4,opt,name=progress_percent,json=progressPercent,proto3" json:"progress_percent,omitempty"`
+	Error           string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	LastSaveTime    int64                  `protobuf:"varint,6,opt,name=last_save_time,json=lastSaveTime,proto3" json:"last_save_time,omitempty"`
+	LastSavePath    string                 `protobuf:"bytes,7,opt,name=last_save_path,json=lastSavePath,proto3" json:"last_save_path,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *BackupStatusResponse) Reset() {
+	*x = BackupStatusResponse{}
+	mi := &file_proto_gibram_proto_msgTypes[92]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackupStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackupStatusResponse) ProtoMessage() {}
+
+func (x *BackupStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[92]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackupStatusResponse.ProtoReflect.Descriptor instead.
+func (*BackupStatusResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{92}
+}
+
+func (x *BackupStatusResponse) GetInProgress() bool {
+	if x != nil {
+		return x.InProgress
+	}
+	return false
+}
+
+func (x *BackupStatusResponse) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *BackupStatusResponse) GetStartTime() int64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *BackupStatusResponse) GetProgressPercent() int32 {
+	if x != nil {
+		return x.ProgressPercent
+	}
+	return 0
+}
+
+func (x *BackupStatusResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *BackupStatusResponse) GetLastSaveTime() int64 {
+	if x != nil {
+		return x.LastSaveTime
+	}
+	return 0
+}
+
+func (x *BackupStatusResponse) GetLastSavePath() string {
+	if x != nil {
+		return x.LastSavePath
+	}
+	return ""
+}
+
+type LastSaveResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp     int64                  `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LastSaveResponse) Reset() {
+	*x = LastSaveResponse{}
+	mi := &file_proto_gibram_proto_msgTypes[93]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LastSaveResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LastSaveResponse) ProtoMessage() {}
+
+func (x *LastSaveResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[93]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LastSaveResponse.ProtoReflect.Descriptor instead.
+func (*LastSaveResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{93}
+}
+
+func (x *LastSaveResponse) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *LastSaveResponse) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type WALStatusResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	CurrentLsn     uint64                 `protobuf:"varint,1,opt,name=current_lsn,json=currentLsn,proto3" json:"current_lsn,omitempty"`
+	FlushedLsn     uint64                 `protobuf:"varint,2,opt,name=flushed_lsn,json=flushedLsn,proto3" json:"flushed_lsn,omitempty"`
+	SegmentCount   int32                  `protobuf:"varint,3,opt,name=segment_count,json=segmentCount,proto3" json:"segment_count,omitempty"`
+	TotalSizeBytes int64                  `protobuf:"varint,4,opt,name=total_size_bytes,json=totalSizeBytes,proto3" json:"total_size_bytes,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *WALStatusResponse) Reset() {
+	*x = WALStatusResponse{}
+	mi := &file_proto_gibram_proto_msgTypes[94]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WALStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WALStatusResponse) ProtoMessage() {}
+
+func (x *WALStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[94]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WALStatusResponse.ProtoReflect.Descriptor instead.
+func (*WALStatusResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{94}
+}
+
+func (x *WALStatusResponse) GetCurrentLsn() uint64 {
+	if x != nil {
+		return x.CurrentLsn
+	}
+	return 0
+}
+
+func (x *WALStatusResponse) GetFlushedLsn() uint64 {
+	if x != nil {
+		return x.FlushedLsn
+	}
+	return 0
+}
+
+func (x *WALStatusResponse) GetSegmentCount() int32 {
+	if x != nil {
+		return x.SegmentCount
+	}
+	return 0
+}
+
+func (x *WALStatusResponse) GetTotalSizeBytes() int64 {
+	if x != nil {
+		return x.TotalSizeBytes
+	}
+	return 0
+}
+
+type WALTruncateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TargetLsn     uint64                 `protobuf:"varint,1,opt,name=target_lsn,json=targetLsn,proto3" json:"target_lsn,omitempty"` // Truncate WAL entries before this LSN
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WALTruncateRequest) Reset() {
+	*x = WALTruncateRequest{}
+	mi := &file_proto_gibram_proto_msgTypes[95]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WALTruncateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WALTruncateRequest) ProtoMessage() {}
+
+func (x *WALTruncateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[95]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WALTruncateRequest.ProtoReflect.Descriptor instead.
+func (*WALTruncateRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{95}
+}
+
+func (x *WALTruncateRequest) GetTargetLsn() uint64 {
+	if x != nil {
+		return x.TargetLsn
+	}
+	return 0
+}
+
+type AuthRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuthRequest) Reset() {
+	*x = AuthRequest{}
+	mi := &file_proto_gibram_proto_msgTypes[96]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthRequest) ProtoMessage() {}
+
+func (x *AuthRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[96]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthRequest.ProtoReflect.Descriptor instead.
+func (*AuthRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{96}
+}
+
+func (x *AuthRequest) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+type AuthResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	KeyId         string                 `protobuf:"bytes,3,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"` // which key was used
+	Permissions   []string               `protobuf:"bytes,4,rep,name=permissions,proto3" json:"permissions,omitempty"`  // granted permissions
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuthResponse) Reset() {
+	*x = AuthResponse{}
+	mi := &file_proto_gibram_proto_msgTypes[97]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthResponse) ProtoMessage() {}
+
+func (x *AuthResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[97]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthResponse.ProtoReflect.Descriptor instead.
+func (*AuthResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{97}
+}
+
+func (x *AuthResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *AuthResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *AuthResponse) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *AuthResponse) GetPermissions() []string {
+	if x != nil {
+		return x.Permissions
+	}
+	return nil
+}
+
+type SlowLogGetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Count         int32                  `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"` // newest entries to return (0 = default, -1 = all)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SlowLogGetRequest) Reset() {
+	*x = SlowLogGetRequest{}
+	mi := &file_proto_gibram_proto_msgTypes[98]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SlowLogGetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SlowLogGetRequest) ProtoMessage() {}
+
+func (x *SlowLogGetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[98]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SlowLogGetRequest.ProtoReflect.Descriptor instead.
+func (*SlowLogGetRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{98}
+}
+
+func (x *SlowLogGetRequest) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type SlowLogEntry struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`               // monotonically increasing entry ID
+	Timestamp      int64                  `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // unix seconds when the command started
+	DurationMicros int64                  `protobuf:"varint,3,opt,name=duration_micros,json=durationMicros,proto3" json:"duration_micros,omitempty"`
+	SessionId      string                 `protobuf:"bytes,4,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	KeyId          string                 `protobuf:"bytes,5,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`        // API key ID ("" when auth is disabled)
+	Command        string                 `protobuf:"bytes,6,opt,name=command,proto3" json:"command,omitempty"`                 // e.g. "QUERY"
+	Args           string                 `protobuf:"bytes,7,opt,name=args,proto3" json:"args,omitempty"`                       // argument summary
+	QueryId        uint64                 `protobuf:"varint,8,opt,name=query_id,json=queryId,proto3" json:"query_id,omitempty"` // for QUERY; use with EXPLAIN
+	ClientAddr     string                 `protobuf:"bytes,9,opt,name=client_addr,json=clientAddr,proto3" json:"client_addr,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SlowLogEntry) Reset() {
+	*x = SlowLogEntry{}
+	mi := &file_proto_gibram_proto_msgTypes[99]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SlowLogEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SlowLogEntry) ProtoMessage() {}
+
+func (x *SlowLogEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[99]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SlowLogEntry.ProtoReflect.Descriptor instead.
+func (*SlowLogEntry) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{99}
+}
+
+func (x *SlowLogEntry) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
 }
 
-// Deprecated: Use BackupStatusResponse.ProtoReflect.Descriptor instead.
-func (*BackupStatusResponse) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{60}
+func (x *SlowLogEntry) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
 }
 
-func (x *BackupStatusResponse) GetInProgress() bool {
+func (x *SlowLogEntry) GetDurationMicros() int64 {
 	if x != nil {
-		return x.InProgress
+		return x.DurationMicros
 	}
-	return false
+	return 0
 }
 
-func (x *BackupStatusResponse) GetType() string {
+func (x *SlowLogEntry) GetSessionId() string {
 	if x != nil {
-		return x.Type
+		return x.SessionId
 	}
 	return ""
 }
 
-func (x *BackupStatusResponse) GetStartTime() int64 {
+func (x *SlowLogEntry) GetKeyId() string {
 	if x != nil {
-		return x.StartTime
+		return x.KeyId
 	}
-	return 0
+	return ""
 }
 
-func (x *BackupStatusResponse) GetProgressPercent() int32 {
+func (x *SlowLogEntry) GetCommand() string {
 	if x != nil {
-		return x.ProgressPercent
+		return x.Command
 	}
-	return 0
+	return ""
 }
 
-func (x *BackupStatusResponse) GetError() string {
+func (x *SlowLogEntry) GetArgs() string {
 	if x != nil {
-		return x.Error
+		return x.Args
 	}
 	return ""
 }
 
-func (x *BackupStatusResponse) GetLastSaveTime() int64 {
+func (x *SlowLogEntry) GetQueryId() uint64 {
 	if x != nil {
-		return x.LastSaveTime
+		return x.QueryId
 	}
 	return 0
 }
 
-func (x *BackupStatusResponse) GetLastSavePath() string {
+func (x *SlowLogEntry) GetClientAddr() string {
 	if x != nil {
-		return x.LastSavePath
+		return x.ClientAddr
 	}
 	return ""
 }
 
-type LastSaveResponse struct {
+type SlowLogResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Timestamp     int64                  `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Entries       []*SlowLogEntry        `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	Length        int32                  `protobuf:"varint,2,opt,name=length,proto3" json:"length,omitempty"` // entries currently held
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *LastSaveResponse) Reset() {
-	*x = LastSaveResponse{}
-	mi := &file_proto_gibram_proto_msgTypes[61]
+func (x *SlowLogResponse) Reset() {
+	*x = SlowLogResponse{}
+	mi := &file_proto_gibram_proto_msgTypes[100]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *LastSaveResponse) String() string {
+func (x *SlowLogResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LastSaveResponse) ProtoMessage() {}
+func (*SlowLogResponse) ProtoMessage() {}
 
-func (x *LastSaveResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[61]
+func (x *SlowLogResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[100]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4055,50 +7175,54 @@ func (x *LastSaveResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LastSaveResponse.ProtoReflect.Descriptor instead.
-func (*LastSaveResponse) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{61}
+// Deprecated: Use SlowLogResponse.ProtoReflect.Descriptor instead.
+func (*SlowLogResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{100}
 }
 
-func (x *LastSaveResponse) GetTimestamp() int64 {
+func (x *SlowLogResponse) GetEntries() []*SlowLogEntry {
 	if x != nil {
-		return x.Timestamp
+		return x.Entries
 	}
-	return 0
+	return nil
 }
 
-func (x *LastSaveResponse) GetPath() string {
+func (x *SlowLogResponse) GetLength() int32 {
 	if x != nil {
-		return x.Path
+		return x.Length
 	}
-	return ""
+	return 0
 }
 
-type WALStatusResponse struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	CurrentLsn     uint64                 `protobuf:"varint,1,opt,name=current_lsn,json=currentLsn,proto3" json:"current_lsn,omitempty"`
-	FlushedLsn     uint64                 `protobuf:"varint,2,opt,name=flushed_lsn,json=flushedLsn,proto3" json:"flushed_lsn,omitempty"`
-	SegmentCount   int32                  `protobuf:"varint,3,opt,name=segment_count,json=segmentCount,proto3" json:"segment_count,omitempty"`
-	TotalSizeBytes int64                  `protobuf:"varint,4,opt,name=total_size_bytes,json=totalSizeBytes,proto3" json:"total_size_bytes,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+// RecallCheckRequest compares HNSW search with exact search on the
+// session's vector indices. Stored vectors are sampled as queries unless
+// query_vectors is given.
+type RecallCheckRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         string                 `protobuf:"bytes,1,opt,name=index,proto3" json:"index,omitempty"`      // "textunit", "entity", "community" ("" = every non-empty index)
+	K             int32                  `protobuf:"varint,2,opt,name=k,proto3" json:"k,omitempty"`             // neighbors compared per query (0 = 10)
+	Ef            int32                  `protobuf:"varint,3,opt,name=ef,proto3" json:"ef,omitempty"`           // HNSW candidate list override (0 = index default)
+	Samples       int32                  `protobuf:"varint,4,opt,name=samples,proto3" json:"samples,omitempty"` // stored vectors sampled as queries (0 = 100)
+	QueryVectors  []*SearchVector        `protobuf:"bytes,5,rep,name=query_vectors,json=queryVectors,proto3" json:"query_vectors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *WALStatusResponse) Reset() {
-	*x = WALStatusResponse{}
-	mi := &file_proto_gibram_proto_msgTypes[62]
+func (x *RecallCheckRequest) Reset() {
+	*x = RecallCheckRequest{}
+	mi := &file_proto_gibram_proto_msgTypes[101]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *WALStatusResponse) String() string {
+func (x *RecallCheckRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WALStatusResponse) ProtoMessage() {}
+func (*RecallCheckRequest) ProtoMessage() {}
 
-func (x *WALStatusResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[62]
+func (x *RecallCheckRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[101]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4109,61 +7233,73 @@ func (x *WALStatusResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WALStatusResponse.ProtoReflect.Descriptor instead.
-func (*WALStatusResponse) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{62}
+// Deprecated: Use RecallCheckRequest.ProtoReflect.Descriptor instead.
+func (*RecallCheckRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{101}
 }
 
-func (x *WALStatusResponse) GetCurrentLsn() uint64 {
+func (x *RecallCheckRequest) GetIndex() string {
 	if x != nil {
-		return x.CurrentLsn
+		return x.Index
 	}
-	return 0
+	return ""
 }
 
-func (x *WALStatusResponse) GetFlushedLsn() uint64 {
+func (x *RecallCheckRequest) GetK() int32 {
 	if x != nil {
-		return x.FlushedLsn
+		return x.K
 	}
 	return 0
 }
 
-func (x *WALStatusResponse) GetSegmentCount() int32 {
+func (x *RecallCheckRequest) GetEf() int32 {
 	if x != nil {
-		return x.SegmentCount
+		return x.Ef
 	}
 	return 0
 }
 
-func (x *WALStatusResponse) GetTotalSizeBytes() int64 {
+func (x *RecallCheckRequest) GetSamples() int32 {
 	if x != nil {
-		return x.TotalSizeBytes
+		return x.Samples
 	}
 	return 0
 }
 
-type WALTruncateRequest struct {
+func (x *RecallCheckRequest) GetQueryVectors() []*SearchVector {
+	if x != nil {
+		return x.QueryVectors
+	}
+	return nil
+}
+
+// Distribution summarises a per-query measurement
+type Distribution struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	TargetLsn     uint64                 `protobuf:"varint,1,opt,name=target_lsn,json=targetLsn,proto3" json:"target_lsn,omitempty"` // Truncate WAL entries before this LSN
+	Avg           float64                `protobuf:"fixed64,1,opt,name=avg,proto3" json:"avg,omitempty"`
+	P50           float64                `protobuf:"fixed64,2,opt,name=p50,proto3" json:"p50,omitempty"`
+	P95           float64                `protobuf:"fixed64,3,opt,name=p95,proto3" json:"p95,omitempty"`
+	P99           float64                `protobuf:"fixed64,4,opt,name=p99,proto3" json:"p99,omitempty"`
+	Max           float64                `protobuf:"fixed64,5,opt,name=max,proto3" json:"max,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *WALTruncateRequest) Reset() {
-	*x = WALTruncateRequest{}
-	mi := &file_proto_gibram_proto_msgTypes[63]
+func (x *Distribution) Reset() {
+	*x = Distribution{}
+	mi := &file_proto_gibram_proto_msgTypes[102]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *WALTruncateRequest) String() string {
+func (x *Distribution) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WALTruncateRequest) ProtoMessage() {}
+func (*Distribution) ProtoMessage() {}
 
-func (x *WALTruncateRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[63]
+func (x *Distribution) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[102]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4174,40 +7310,77 @@ func (x *WALTruncateRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WALTruncateRequest.ProtoReflect.Descriptor instead.
-func (*WALTruncateRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{63}
+// Deprecated: Use Distribution.ProtoReflect.Descriptor instead.
+func (*Distribution) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{102}
 }
 
-func (x *WALTruncateRequest) GetTargetLsn() uint64 {
+func (x *Distribution) GetAvg() float64 {
 	if x != nil {
-		return x.TargetLsn
+		return x.Avg
 	}
 	return 0
 }
 
-type AuthRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ApiKey        string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *Distribution) GetP50() float64 {
+	if x != nil {
+		return x.P50
+	}
+	return 0
 }
 
-func (x *AuthRequest) Reset() {
-	*x = AuthRequest{}
-	mi := &file_proto_gibram_proto_msgTypes[64]
+func (x *Distribution) GetP95() float64 {
+	if x != nil {
+		return x.P95
+	}
+	return 0
+}
+
+func (x *Distribution) GetP99() float64 {
+	if x != nil {
+		return x.P99
+	}
+	return 0
+}
+
+func (x *Distribution) GetMax() float64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+type IndexRecall struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Index              string                 `protobuf:"bytes,1,opt,name=index,proto3" json:"index,omitempty"`
+	Vectors            uint64                 `protobuf:"varint,2,opt,name=vectors,proto3" json:"vectors,omitempty"` // vectors in the index
+	Queries            int32                  `protobuf:"varint,3,opt,name=queries,proto3" json:"queries,omitempty"`
+	K                  int32                  `protobuf:"varint,4,opt,name=k,proto3" json:"k,omitempty"`
+	Ef                 int32                  `protobuf:"varint,5,opt,name=ef,proto3" json:"ef,omitempty"`
+	Recall             float64                `protobuf:"fixed64,6,opt,name=recall,proto3" json:"recall,omitempty"`                                                   // mean recall@k
+	MinRecall          float64                `protobuf:"fixed64,7,opt,name=min_recall,json=minRecall,proto3" json:"min_recall,omitempty"`                            // lowest recall@k of one query
+	LatencyMicros      *Distribution          `protobuf:"bytes,8,opt,name=latency_micros,json=latencyMicros,proto3" json:"latency_micros,omitempty"`                  // HNSW search
+	ExactLatencyMicros *Distribution          `protobuf:"bytes,9,opt,name=exact_latency_micros,json=exactLatencyMicros,proto3" json:"exact_latency_micros,omitempty"` // exact scan
+	Visited            *Distribution          `protobuf:"bytes,10,opt,name=visited,proto3" json:"visited,omitempty"`                                                  // nodes scored per HNSW search
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *IndexRecall) Reset() {
+	*x = IndexRecall{}
+	mi := &file_proto_gibram_proto_msgTypes[103]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AuthRequest) String() string {
+func (x *IndexRecall) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AuthRequest) ProtoMessage() {}
+func (*IndexRecall) ProtoMessage() {}
 
-func (x *AuthRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[64]
+func (x *IndexRecall) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[103]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4218,82 +7391,121 @@ func (x *AuthRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AuthRequest.ProtoReflect.Descriptor instead.
-func (*AuthRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{64}
+// Deprecated: Use IndexRecall.ProtoReflect.Descriptor instead.
+func (*IndexRecall) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{103}
 }
 
-func (x *AuthRequest) GetApiKey() string {
+func (x *IndexRecall) GetIndex() string {
 	if x != nil {
-		return x.ApiKey
+		return x.Index
 	}
 	return ""
 }
 
-type AuthResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	KeyId         string                 `protobuf:"bytes,3,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"` // which key was used
-	Permissions   []string               `protobuf:"bytes,4,rep,name=permissions,proto3" json:"permissions,omitempty"`  // granted permissions
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *IndexRecall) GetVectors() uint64 {
+	if x != nil {
+		return x.Vectors
+	}
+	return 0
 }
 
-func (x *AuthResponse) Reset() {
-	*x = AuthResponse{}
-	mi := &file_proto_gibram_proto_msgTypes[65]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *IndexRecall) GetQueries() int32 {
+	if x != nil {
+		return x.Queries
+	}
+	return 0
 }
 
-func (x *AuthResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *IndexRecall) GetK() int32 {
+	if x != nil {
+		return x.K
+	}
+	return 0
 }
 
-func (*AuthResponse) ProtoMessage() {}
+func (x *IndexRecall) GetEf() int32 {
+	if x != nil {
+		return x.Ef
+	}
+	return 0
+}
 
-func (x *AuthResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gibram_proto_msgTypes[65]
+func (x *IndexRecall) GetRecall() float64 {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Recall
 	}
-	return mi.MessageOf(x)
+	return 0
 }
 
-// Deprecated: Use AuthResponse.ProtoReflect.Descriptor instead.
-func (*AuthResponse) Descriptor() ([]byte, []int) {
-	return file_proto_gibram_proto_rawDescGZIP(), []int{65}
+func (x *IndexRecall) GetMinRecall() float64 {
+	if x != nil {
+		return x.MinRecall
+	}
+	return 0
 }
 
-func (x *AuthResponse) GetSuccess() bool {
+func (x *IndexRecall) GetLatencyMicros() *Distribution {
 	if x != nil {
-		return x.Success
+		return x.LatencyMicros
 	}
-	return false
+	return nil
 }
 
-func (x *AuthResponse) GetMessage() string {
+func (x *IndexRecall) GetExactLatencyMicros() *Distribution {
 	if x != nil {
-		return x.Message
+		return x.ExactLatencyMicros
 	}
-	return ""
+	return nil
 }
 
-func (x *AuthResponse) GetKeyId() string {
+func (x *IndexRecall) GetVisited() *Distribution {
 	if x != nil {
-		return x.KeyId
+		return x.Visited
 	}
-	return ""
+	return nil
 }
 
-func (x *AuthResponse) GetPermissions() []string {
+type RecallCheckResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Indices       []*IndexRecall         `protobuf:"bytes,1,rep,name=indices,proto3" json:"indices,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecallCheckResponse) Reset() {
+	*x = RecallCheckResponse{}
+	mi := &file_proto_gibram_proto_msgTypes[104]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecallCheckResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecallCheckResponse) ProtoMessage() {}
+
+func (x *RecallCheckResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gibram_proto_msgTypes[104]
 	if x != nil {
-		return x.Permissions
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecallCheckResponse.ProtoReflect.Descriptor instead.
+func (*RecallCheckResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gibram_proto_rawDescGZIP(), []int{104}
+}
+
+func (x *RecallCheckResponse) GetIndices() []*IndexRecall {
+	if x != nil {
+		return x.Indices
 	}
 	return nil
 }
@@ -4316,7 +7528,7 @@ const file_proto_gibram_proto_rawDesc = "" +
 	"\amessage\x18\x01 \x01(\tR\amessage\x12\x12\n" +
 	"\x04code\x18\x02 \x01(\x05R\x04code\"\x1a\n" +
 	"\bOkWithID\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x04R\x02id\"\xb5\x02\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"\xdb\x04\n" +
 	"\fInfoResponse\x12\x18\n" +
 	"\aversion\x18\x01 \x01(\tR\aversion\x12%\n" +
 	"\x0edocument_count\x18\x02 \x01(\x04R\rdocumentCount\x12%\n" +
@@ -4326,7 +7538,14 @@ const file_proto_gibram_proto_rawDesc = "" +
 	"\x0fcommunity_count\x18\x06 \x01(\x04R\x0ecommunityCount\x12\x1d\n" +
 	"\n" +
 	"vector_dim\x18\a \x01(\x05R\tvectorDim\x12#\n" +
-	"\rsession_count\x18\b \x01(\x05R\fsessionCount\"\xe2\x02\n" +
+	"\rsession_count\x18\b \x01(\x05R\fsessionCount\x12!\n" +
+	"\fvector_count\x18\t \x01(\x04R\vvectorCount\x124\n" +
+	"\x16quantized_vector_count\x18\n" +
+	" \x01(\x04R\x14quantizedVectorCount\x12.\n" +
+	"\x13vector_memory_bytes\x18\v \x01(\x04R\x11vectorMemoryBytes\x12=\n" +
+	"\x1bvector_full_precision_bytes\x18\f \x01(\x04R\x18vectorFullPrecisionBytes\x12/\n" +
+	"\x13quantization_recall\x18\r \x01(\x02R\x12quantizationRecall\x12+\n" +
+	"\x11vector_tombstones\x18\x0e \x01(\x04R\x10vectorTombstones\"\xe5\x06\n" +
 	"\vSessionInfo\x12\x1d\n" +
 	"\n" +
 	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x1d\n" +
@@ -4341,7 +7560,36 @@ const file_proto_gibram_proto_rawDesc = "" +
 	"\fentity_count\x18\b \x01(\x04R\ventityCount\x12-\n" +
 	"\x12relationship_count\x18\t \x01(\x04R\x11relationshipCount\x12'\n" +
 	"\x0fcommunity_count\x18\n" +
-	" \x01(\x04R\x0ecommunityCount\"J\n" +
+	" \x01(\x04R\x0ecommunityCount\x12>\n" +
+	"\x0etextunit_index\x18\v \x01(\v2\x17.gibram.v1.IndexOptionsR\rtextunitIndex\x12:\n" +
+	"\fentity_index\x18\f \x01(\v2\x17.gibram.v1.IndexOptionsR\ventityIndex\x12@\n" +
+	"\x0fcommunity_index\x18\r \x01(\v2\x17.gibram.v1.IndexOptionsR\x0ecommunityIndex\x12\x1d\n" +
+	"\n" +
+	"vector_dim\x18\x0e \x01(\rR\tvectorDim\x12!\n" +
+	"\fbulk_loading\x18\x0f \x01(\bR\vbulkLoading\x12'\n" +
+	"\x0fpending_vectors\x18\x10 \x01(\x04R\x0ependingVectors\x12(\n" +
+	"\x10query_cache_size\x18\x11 \x01(\rR\x0equeryCacheSize\x12&\n" +
+	"\x0fquery_cache_ttl\x18\x12 \x01(\x03R\rqueryCacheTtl\x12.\n" +
+	"\x13query_cache_entries\x18\x13 \x01(\x04R\x11queryCacheEntries\x12(\n" +
+	"\x10query_cache_hits\x18\x14 \x01(\x04R\x0equeryCacheHits\x12,\n" +
+	"\x12query_cache_misses\x18\x15 \x01(\x04R\x10queryCacheMisses\"\x98\x02\n" +
+	"\fIndexOptions\x12\x16\n" +
+	"\x06metric\x18\x01 \x01(\tR\x06metric\x12\f\n" +
+	"\x01m\x18\x02 \x01(\rR\x01m\x12'\n" +
+	"\x0fef_construction\x18\x03 \x01(\rR\x0eefConstruction\x12\x1b\n" +
+	"\tef_search\x18\x04 \x01(\rR\befSearch\x12\"\n" +
+	"\fquantization\x18\x05 \x01(\tR\fquantization\x12#\n" +
+	"\rpq_subvectors\x18\x06 \x01(\rR\fpqSubvectors\x12*\n" +
+	"\x11keep_full_vectors\x18\a \x01(\bR\x0fkeepFullVectors\x12'\n" +
+	"\x0ftombstone_ratio\x18\b \x01(\x01R\x0etombstoneRatio\"\x92\x02\n" +
+	"\x14CreateSessionRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12>\n" +
+	"\x0etextunit_index\x18\x02 \x01(\v2\x17.gibram.v1.IndexOptionsR\rtextunitIndex\x12:\n" +
+	"\fentity_index\x18\x03 \x01(\v2\x17.gibram.v1.IndexOptionsR\ventityIndex\x12@\n" +
+	"\x0fcommunity_index\x18\x04 \x01(\v2\x17.gibram.v1.IndexOptionsR\x0ecommunityIndex\x12\x1d\n" +
+	"\n" +
+	"vector_dim\x18\x05 \x01(\rR\tvectorDim\"J\n" +
 	"\x14ListSessionsResponse\x122\n" +
 	"\bsessions\x18\x01 \x03(\v2\x16.gibram.v1.SessionInfoR\bsessions\"5\n" +
 	"\x14DeleteSessionRequest\x12\x1d\n" +
@@ -4354,7 +7602,11 @@ const file_proto_gibram_proto_rawDesc = "" +
 	"\n" +
 	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x10\n" +
 	"\x03ttl\x18\x02 \x01(\x03R\x03ttl\x12\x19\n" +
-	"\bidle_ttl\x18\x03 \x01(\x03R\aidleTtl\"4\n" +
+	"\bidle_ttl\x18\x03 \x01(\x03R\aidleTtl\"I\n" +
+	"\x14SetQueryCacheRequest\x12\x1f\n" +
+	"\vmax_entries\x18\x01 \x01(\rR\n" +
+	"maxEntries\x12\x10\n" +
+	"\x03ttl\x18\x02 \x01(\x03R\x03ttl\"4\n" +
 	"\x13TouchSessionRequest\x12\x1d\n" +
 	"\n" +
 	"session_id\x18\x01 \x01(\tR\tsessionId\"\xb1\x01\n" +
@@ -4472,7 +7724,8 @@ const file_proto_gibram_proto_rawDesc = "" +
 	"\x19LinkTextUnitEntityRequest\x12\x1f\n" +
 	"\vtextunit_id\x18\x01 \x01(\x04R\n" +
 	"textunitId\x12\x1b\n" +
-	"\tentity_id\x18\x02 \x01(\x04R\bentityId\"\xf3\x02\n" +
+	"\tentity_id\x18\x02 \x01(\x04R\bentityId\"\xfc\n" +
+	"\n" +
 	"\fQueryRequest\x12!\n" +
 	"\fquery_vector\x18\x01 \x03(\x02R\vqueryVector\x12!\n" +
 	"\fsearch_types\x18\x02 \x03(\tR\vsearchTypes\x12\x13\n" +
@@ -4484,42 +7737,214 @@ const file_proto_gibram_proto_rawDesc = "" +
 	"\x0fseed_entity_ids\x18\b \x03(\x04R\rseedEntityIds\x12.\n" +
 	"\x13filter_entity_types\x18\t \x03(\tR\x11filterEntityTypes\x12(\n" +
 	"\x10filter_rel_types\x18\n" +
-	" \x03(\tR\x0efilterRelTypes\"s\n" +
+	" \x03(\tR\x0efilterRelTypes\x12.\n" +
+	"\x13filter_document_ids\x18\v \x03(\x04R\x11filterDocumentIds\x12K\n" +
+	"\ffilter_attrs\x18\f \x03(\v2(.gibram.v1.QueryRequest.FilterAttrsEntryR\vfilterAttrs\x12\x1d\n" +
+	"\n" +
+	"query_text\x18\r \x01(\tR\tqueryText\x12\x16\n" +
+	"\x06fusion\x18\x0e \x01(\tR\x06fusion\x12%\n" +
+	"\x0elexical_weight\x18\x0f \x01(\x02R\rlexicalWeight\x12\x18\n" +
+	"\ascoring\x18\x10 \x01(\tR\ascoring\x12\x14\n" +
+	"\x05decay\x18\x11 \x01(\x02R\x05decay\x12 \n" +
+	"\vaggregation\x18\x12 \x01(\tR\vaggregation\x12\x1c\n" +
+	"\texpansion\x18\x13 \x01(\tR\texpansion\x12\x18\n" +
+	"\adamping\x18\x14 \x01(\x02R\adamping\x12\x1e\n" +
+	"\n" +
+	"iterations\x18\x15 \x01(\x05R\n" +
+	"iterations\x12\x18\n" +
+	"\arestart\x18\x16 \x01(\tR\arestart\x12T\n" +
+	"\x0frestart_weights\x18\x17 \x03(\v2+.gibram.v1.QueryRequest.RestartWeightsEntryR\x0erestartWeights\x12!\n" +
+	"\ftoken_budget\x18\x18 \x01(\x05R\vtokenBudget\x12!\n" +
+	"\fentity_share\x18\x19 \x01(\x02R\ventityShare\x12-\n" +
+	"\x12relationship_share\x18\x1a \x01(\x02R\x11relationshipShare\x12%\n" +
+	"\x0etextunit_share\x18\x1b \x01(\x02R\rtextunitShare\x12'\n" +
+	"\x0fcommunity_share\x18\x1c \x01(\x02R\x0ecommunityShare\x12%\n" +
+	"\x0econtext_format\x18\x1d \x01(\tR\rcontextFormat\x12\x16\n" +
+	"\x06rerank\x18\x1e \x01(\tR\x06rerank\x12\x1d\n" +
+	"\n" +
+	"mmr_lambda\x18\x1f \x01(\x02R\tmmrLambda\x12'\n" +
+	"\x0fdedup_threshold\x18  \x01(\x02R\x0ededupThreshold\x12(\n" +
+	"\x10max_per_document\x18! \x01(\x05R\x0emaxPerDocument\x12\x19\n" +
+	"\bno_cache\x18\" \x01(\bR\anoCache\x1a>\n" +
+	"\x10FilterAttrsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1aA\n" +
+	"\x13RestartWeightsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\x04R\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x02R\x05value:\x028\x01\"\x89\x01\n" +
 	"\x0eTextUnitResult\x12/\n" +
 	"\btextunit\x18\x01 \x01(\v2\x13.gibram.v1.TextUnitR\btextunit\x12\x1e\n" +
 	"\n" +
 	"similarity\x18\x02 \x01(\x02R\n" +
 	"similarity\x12\x10\n" +
-	"\x03hop\x18\x03 \x01(\x05R\x03hop\"k\n" +
+	"\x03hop\x18\x03 \x01(\x05R\x03hop\x12\x14\n" +
+	"\x05score\x18\x04 \x01(\x02R\x05score\"\x81\x01\n" +
 	"\fEntityResult\x12)\n" +
 	"\x06entity\x18\x01 \x01(\v2\x11.gibram.v1.EntityR\x06entity\x12\x1e\n" +
 	"\n" +
 	"similarity\x18\x02 \x01(\x02R\n" +
 	"similarity\x12\x10\n" +
-	"\x03hop\x18\x03 \x01(\x05R\x03hop\"e\n" +
+	"\x03hop\x18\x03 \x01(\x05R\x03hop\x12\x14\n" +
+	"\x05score\x18\x04 \x01(\x02R\x05score\"{\n" +
 	"\x0fCommunityResult\x122\n" +
 	"\tcommunity\x18\x01 \x01(\v2\x14.gibram.v1.CommunityR\tcommunity\x12\x1e\n" +
 	"\n" +
 	"similarity\x18\x02 \x01(\x02R\n" +
-	"similarity\"\x97\x01\n" +
+	"similarity\x12\x14\n" +
+	"\x05score\x18\x03 \x01(\x02R\x05score\"\x97\x01\n" +
 	"\x12RelationshipResult\x12;\n" +
 	"\frelationship\x18\x01 \x01(\v2\x17.gibram.v1.RelationshipR\frelationship\x12!\n" +
 	"\fsource_title\x18\x02 \x01(\tR\vsourceTitle\x12!\n" +
-	"\ftarget_title\x18\x03 \x01(\tR\vtargetTitle\"\x89\x01\n" +
+	"\ftarget_title\x18\x03 \x01(\tR\vtargetTitle\"\xa6\x01\n" +
 	"\n" +
 	"QueryStats\x12'\n" +
 	"\x0fduration_micros\x18\x01 \x01(\x03R\x0edurationMicros\x12'\n" +
 	"\x0fvector_searches\x18\x02 \x01(\x05R\x0evectorSearches\x12)\n" +
-	"\x10graph_traversals\x18\x03 \x01(\x05R\x0fgraphTraversals\"\xc8\x02\n" +
+	"\x10graph_traversals\x18\x03 \x01(\x05R\x0fgraphTraversals\x12\x1b\n" +
+	"\tcache_hit\x18\x04 \x01(\bR\bcacheHit\"\x9a\x03\n" +
 	"\rQueryResponse\x12\x19\n" +
 	"\bquery_id\x18\x01 \x01(\x04R\aqueryId\x127\n" +
 	"\ttextunits\x18\x02 \x03(\v2\x19.gibram.v1.TextUnitResultR\ttextunits\x123\n" +
 	"\bentities\x18\x03 \x03(\v2\x17.gibram.v1.EntityResultR\bentities\x12<\n" +
 	"\vcommunities\x18\x04 \x03(\v2\x1a.gibram.v1.CommunityResultR\vcommunities\x12C\n" +
 	"\rrelationships\x18\x05 \x03(\v2\x1d.gibram.v1.RelationshipResultR\rrelationships\x12+\n" +
-	"\x05stats\x18\x06 \x01(\v2\x15.gibram.v1.QueryStatsR\x05stats\"+\n" +
+	"\x05stats\x18\x06 \x01(\v2\x15.gibram.v1.QueryStatsR\x05stats\x12\x18\n" +
+	"\acontext\x18\a \x01(\tR\acontext\x126\n" +
+	"\vtoken_usage\x18\b \x01(\v2\x15.gibram.v1.TokenUsageR\n" +
+	"tokenUsage\"\xa4\x01\n" +
+	"\n" +
+	"TokenUsage\x12\x1a\n" +
+	"\bentities\x18\x01 \x01(\x05R\bentities\x12$\n" +
+	"\rrelationships\x18\x02 \x01(\x05R\rrelationships\x12\x1c\n" +
+	"\ttextunits\x18\x03 \x01(\x05R\ttextunits\x12 \n" +
+	"\vcommunities\x18\x04 \x01(\x05R\vcommunities\x12\x14\n" +
+	"\x05total\x18\x05 \x01(\x05R\x05total\"\xd7\x01\n" +
+	"\x11QueryBatchRequest\x121\n" +
+	"\aqueries\x18\x01 \x03(\v2\x17.gibram.v1.QueryRequestR\aqueries\x12/\n" +
+	"\x06shared\x18\x02 \x01(\v2\x17.gibram.v1.QueryRequestR\x06shared\x12<\n" +
+	"\rquery_vectors\x18\x03 \x03(\v2\x17.gibram.v1.SearchVectorR\fqueryVectors\x12 \n" +
+	"\vparallelism\x18\x04 \x01(\x05R\vparallelism\"^\n" +
+	"\x10QueryBatchResult\x124\n" +
+	"\bresponse\x18\x01 \x01(\v2\x18.gibram.v1.QueryResponseR\bresponse\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"K\n" +
+	"\x12QueryBatchResponse\x125\n" +
+	"\aresults\x18\x01 \x03(\v2\x1b.gibram.v1.QueryBatchResultR\aresults\"&\n" +
+	"\fSearchVector\x12\x16\n" +
+	"\x06values\x18\x01 \x03(\x02R\x06values\"\x83\x04\n" +
+	"\rSearchRequest\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\tR\x05index\x12<\n" +
+	"\rquery_vectors\x18\x02 \x03(\v2\x17.gibram.v1.SearchVectorR\fqueryVectors\x12\f\n" +
+	"\x01k\x18\x03 \x01(\x05R\x01k\x12\x0e\n" +
+	"\x02ef\x18\x04 \x01(\x05R\x02ef\x12%\n" +
+	"\x0emin_similarity\x18\x05 \x01(\x02R\rminSimilarity\x12,\n" +
+	"\x12has_min_similarity\x18\x06 \x01(\bR\x10hasMinSimilarity\x12\x14\n" +
+	"\x05range\x18\a \x01(\bR\x05range\x12'\n" +
+	"\x0finclude_objects\x18\b \x01(\bR\x0eincludeObjects\x12.\n" +
+	"\x13filter_entity_types\x18\t \x03(\tR\x11filterEntityTypes\x12.\n" +
+	"\x13filter_document_ids\x18\n" +
+	" \x03(\x04R\x11filterDocumentIds\x12L\n" +
+	"\ffilter_attrs\x18\v \x03(\v2).gibram.v1.SearchRequest.FilterAttrsEntryR\vfilterAttrs\x1a>\n" +
+	"\x10FilterAttrsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xec\x01\n" +
+	"\tSearchHit\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x1f\n" +
+	"\vexternal_id\x18\x02 \x01(\tR\n" +
+	"externalId\x12\x1e\n" +
+	"\n" +
+	"similarity\x18\x03 \x01(\x02R\n" +
+	"similarity\x12/\n" +
+	"\btextunit\x18\x04 \x01(\v2\x13.gibram.v1.TextUnitR\btextunit\x12)\n" +
+	"\x06entity\x18\x05 \x01(\v2\x11.gibram.v1.EntityR\x06entity\x122\n" +
+	"\tcommunity\x18\x06 \x01(\v2\x14.gibram.v1.CommunityR\tcommunity\"6\n" +
+	"\n" +
+	"SearchHits\x12(\n" +
+	"\x04hits\x18\x01 \x03(\v2\x14.gibram.v1.SearchHitR\x04hits\"A\n" +
+	"\x0eSearchResponse\x12/\n" +
+	"\aresults\x18\x01 \x03(\v2\x15.gibram.v1.SearchHitsR\aresults\"\x8d\x02\n" +
+	"\vPathRequest\x12\x1b\n" +
+	"\tsource_id\x18\x01 \x01(\x04R\bsourceId\x12!\n" +
+	"\fsource_title\x18\x02 \x01(\tR\vsourceTitle\x12\x1b\n" +
+	"\ttarget_id\x18\x03 \x01(\x04R\btargetId\x12!\n" +
+	"\ftarget_title\x18\x04 \x01(\tR\vtargetTitle\x12\f\n" +
+	"\x01k\x18\x05 \x01(\x05R\x01k\x12\x1b\n" +
+	"\tmax_depth\x18\x06 \x01(\x05R\bmaxDepth\x12\x1a\n" +
+	"\bdirected\x18\a \x01(\bR\bdirected\x12\x1a\n" +
+	"\bweighted\x18\b \x01(\bR\bweighted\x12\x1b\n" +
+	"\trel_types\x18\t \x03(\tR\brelTypes\"\x8e\x01\n" +
+	"\n" +
+	"EntityPath\x12-\n" +
+	"\bentities\x18\x01 \x03(\v2\x11.gibram.v1.EntityR\bentities\x12=\n" +
+	"\rrelationships\x18\x02 \x03(\v2\x17.gibram.v1.RelationshipR\rrelationships\x12\x12\n" +
+	"\x04cost\x18\x03 \x01(\x01R\x04cost\";\n" +
+	"\fPathResponse\x12+\n" +
+	"\x05paths\x18\x01 \x03(\v2\x15.gibram.v1.EntityPathR\x05paths\"\xdc\x01\n" +
+	"\x0fSubgraphRequest\x12\x1d\n" +
+	"\n" +
+	"entity_ids\x18\x01 \x03(\x04R\tentityIds\x12\x12\n" +
+	"\x04hops\x18\x02 \x01(\x05R\x04hops\x12\x1c\n" +
+	"\tdirection\x18\x03 \x01(\tR\tdirection\x12!\n" +
+	"\fentity_types\x18\x04 \x03(\tR\ventityTypes\x12\x1b\n" +
+	"\trel_types\x18\x05 \x03(\tR\brelTypes\x12\x1b\n" +
+	"\tmax_nodes\x18\x06 \x01(\x05R\bmaxNodes\x12\x1b\n" +
+	"\tmax_edges\x18\a \x01(\x05R\bmaxEdges\"\x9f\x01\n" +
+	"\fSubgraphNode\x12)\n" +
+	"\x06entity\x18\x01 \x01(\v2\x11.gibram.v1.EntityR\x06entity\x12\x10\n" +
+	"\x03hop\x18\x02 \x01(\x05R\x03hop\x12\x1b\n" +
+	"\tin_degree\x18\x03 \x01(\x05R\binDegree\x12\x1d\n" +
+	"\n" +
+	"out_degree\x18\x04 \x01(\x05R\toutDegree\x12\x16\n" +
+	"\x06degree\x18\x05 \x01(\x05R\x06degree\"\x9e\x01\n" +
+	"\x10SubgraphResponse\x12-\n" +
+	"\x05nodes\x18\x01 \x03(\v2\x17.gibram.v1.SubgraphNodeR\x05nodes\x12=\n" +
+	"\rrelationships\x18\x02 \x03(\v2\x17.gibram.v1.RelationshipR\rrelationships\x12\x1c\n" +
+	"\ttruncated\x18\x03 \x01(\bR\ttruncated\")\n" +
+	"\x11GraphQueryRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\"\xed\x01\n" +
+	"\n" +
+	"GraphValue\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12)\n" +
+	"\x06entity\x18\x02 \x01(\v2\x11.gibram.v1.EntityR\x06entity\x12;\n" +
+	"\frelationship\x18\x03 \x01(\v2\x17.gibram.v1.RelationshipR\frelationship\x12!\n" +
+	"\fstring_value\x18\x04 \x01(\tR\vstringValue\x12!\n" +
+	"\fnumber_value\x18\x05 \x01(\x01R\vnumberValue\x12\x1d\n" +
+	"\n" +
+	"bool_value\x18\x06 \x01(\bR\tboolValue\"9\n" +
+	"\bGraphRow\x12-\n" +
+	"\x06values\x18\x01 \x03(\v2\x15.gibram.v1.GraphValueR\x06values\"\x89\x01\n" +
+	"\x12GraphQueryResponse\x12\x18\n" +
+	"\acolumns\x18\x01 \x03(\tR\acolumns\x12'\n" +
+	"\x04rows\x18\x02 \x03(\v2\x13.gibram.v1.GraphRowR\x04rows\x12\x12\n" +
+	"\x04plan\x18\x03 \x03(\tR\x04plan\x12\x1c\n" +
+	"\ttruncated\x18\x04 \x01(\bR\ttruncated\"\xde\x01\n" +
+	"\x13GlobalSearchRequest\x12!\n" +
+	"\fquery_vector\x18\x01 \x03(\x02R\vqueryVector\x12\x14\n" +
+	"\x05level\x18\x02 \x01(\x05R\x05level\x12'\n" +
+	"\x0fmin_communities\x18\x03 \x01(\x05R\x0eminCommunities\x12!\n" +
+	"\ftoken_budget\x18\x04 \x01(\x05R\vtokenBudget\x12!\n" +
+	"\fbatch_tokens\x18\x05 \x01(\x05R\vbatchTokens\x12\x1f\n" +
+	"\vuse_summary\x18\x06 \x01(\bR\n" +
+	"useSummary\"\x87\x01\n" +
+	"\x0fCommunityReport\x122\n" +
+	"\tcommunity\x18\x01 \x01(\v2\x14.gibram.v1.CommunityR\tcommunity\x12\x14\n" +
+	"\x05score\x18\x02 \x01(\x02R\x05score\x12\x12\n" +
+	"\x04rank\x18\x03 \x01(\x05R\x04rank\x12\x16\n" +
+	"\x06tokens\x18\x04 \x01(\x05R\x06tokens\"[\n" +
+	"\vReportBatch\x124\n" +
+	"\areports\x18\x01 \x03(\v2\x1a.gibram.v1.CommunityReportR\areports\x12\x16\n" +
+	"\x06tokens\x18\x02 \x01(\x05R\x06tokens\"\xe2\x01\n" +
+	"\x14GlobalSearchResponse\x12\x14\n" +
+	"\x05level\x18\x01 \x01(\x05R\x05level\x12'\n" +
+	"\x0frequested_level\x18\x02 \x01(\x05R\x0erequestedLevel\x12\x1e\n" +
+	"\n" +
+	"candidates\x18\x03 \x01(\x05R\n" +
+	"candidates\x120\n" +
+	"\abatches\x18\x04 \x03(\v2\x16.gibram.v1.ReportBatchR\abatches\x12\x1f\n" +
+	"\vtokens_used\x18\x05 \x01(\x05R\n" +
+	"tokensUsed\x12\x18\n" +
+	"\askipped\x18\x06 \x01(\x05R\askipped\"+\n" +
 	"\x0eExplainRequest\x12\x19\n" +
-	"\bquery_id\x18\x01 \x01(\x04R\aqueryId\"o\n" +
+	"\bquery_id\x18\x01 \x01(\x04R\aqueryId\"\x89\x01\n" +
 	"\bSeedInfo\x12\x12\n" +
 	"\x04type\x18\x01 \x01(\tR\x04type\x12\x0e\n" +
 	"\x02id\x18\x02 \x01(\x04R\x02id\x12\x1f\n" +
@@ -4527,7 +7952,8 @@ const file_proto_gibram_proto_rawDesc = "" +
 	"externalId\x12\x1e\n" +
 	"\n" +
 	"similarity\x18\x04 \x01(\x02R\n" +
-	"similarity\"\xc5\x01\n" +
+	"similarity\x12\x18\n" +
+	"\alexical\x18\x05 \x01(\x02R\alexical\"\xf0\x01\n" +
 	"\rTraversalStep\x12$\n" +
 	"\x0efrom_entity_id\x18\x01 \x01(\x04R\ffromEntityId\x12 \n" +
 	"\fto_entity_id\x18\x02 \x01(\x04R\n" +
@@ -4535,11 +7961,26 @@ const file_proto_gibram_proto_rawDesc = "" +
 	"\x0frelationship_id\x18\x03 \x01(\x04R\x0erelationshipId\x12\x19\n" +
 	"\brel_type\x18\x04 \x01(\tR\arelType\x12\x16\n" +
 	"\x06weight\x18\x05 \x01(\x02R\x06weight\x12\x10\n" +
-	"\x03hop\x18\x06 \x01(\x05R\x03hop\"\x8f\x01\n" +
+	"\x03hop\x18\x06 \x01(\x05R\x03hop\x12)\n" +
+	"\x10cumulative_score\x18\a \x01(\x02R\x0fcumulativeScore\"\xd5\x01\n" +
+	"\bDemotion\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x04R\x02id\x12\x1f\n" +
+	"\vexternal_id\x18\x03 \x01(\tR\n" +
+	"externalId\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x12\x12\n" +
+	"\x04rank\x18\x05 \x01(\x05R\x04rank\x12\x19\n" +
+	"\bnew_rank\x18\x06 \x01(\x05R\anewRank\x12\x1d\n" +
+	"\n" +
+	"similar_to\x18\a \x01(\x04R\tsimilarTo\x12\x1e\n" +
+	"\n" +
+	"similarity\x18\b \x01(\x02R\n" +
+	"similarity\"\xc2\x01\n" +
 	"\x0fExplainResponse\x12\x19\n" +
 	"\bquery_id\x18\x01 \x01(\x04R\aqueryId\x12)\n" +
 	"\x05seeds\x18\x02 \x03(\v2\x13.gibram.v1.SeedInfoR\x05seeds\x126\n" +
-	"\ttraversal\x18\x03 \x03(\v2\x18.gibram.v1.TraversalStepR\ttraversal\" \n" +
+	"\ttraversal\x18\x03 \x03(\v2\x18.gibram.v1.TraversalStepR\ttraversal\x121\n" +
+	"\tdemotions\x18\x04 \x03(\v2\x13.gibram.v1.DemotionR\tdemotions\" \n" +
 	"\x0eGetByIDRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x04R\x02id\"#\n" +
 	"\x11DeleteByIDRequest\x12\x0e\n" +
@@ -4554,9 +7995,10 @@ const file_proto_gibram_proto_rawDesc = "" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"C\n" +
 	"\x13ListEntitiesRequest\x12\x16\n" +
 	"\x06cursor\x18\x01 \x01(\x04R\x06cursor\x12\x14\n" +
-	"\x05limit\x18\x02 \x01(\x05R\x05limit\"N\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"b\n" +
 	"\x13MSetEntitiesRequest\x127\n" +
-	"\bentities\x18\x01 \x03(\v2\x1b.gibram.v1.AddEntityRequestR\bentities\"'\n" +
+	"\bentities\x18\x01 \x03(\v2\x1b.gibram.v1.AddEntityRequestR\bentities\x12\x12\n" +
+	"\x04bulk\x18\x02 \x01(\bR\x04bulk\"'\n" +
 	"\x13MGetEntitiesRequest\x12\x10\n" +
 	"\x03ids\x18\x01 \x03(\x04R\x03ids\"\x83\x01\n" +
 	"\x10EntitiesResponse\x12-\n" +
@@ -4572,9 +8014,10 @@ const file_proto_gibram_proto_rawDesc = "" +
 	"\x11DocumentsResponse\x121\n" +
 	"\tdocuments\x18\x01 \x03(\v2\x13.gibram.v1.DocumentR\tdocuments\x12\x1f\n" +
 	"\vcreated_ids\x18\x02 \x03(\x04R\n" +
-	"createdIds\"S\n" +
+	"createdIds\"g\n" +
 	"\x14MSetTextUnitsRequest\x12;\n" +
-	"\ttextunits\x18\x01 \x03(\v2\x1d.gibram.v1.AddTextUnitRequestR\ttextunits\"(\n" +
+	"\ttextunits\x18\x01 \x03(\v2\x1d.gibram.v1.AddTextUnitRequestR\ttextunits\x12\x12\n" +
+	"\x04bulk\x18\x02 \x01(\bR\x04bulk\"(\n" +
 	"\x14MGetTextUnitsRequest\x12\x10\n" +
 	"\x03ids\x18\x01 \x03(\x04R\x03ids\"g\n" +
 	"\x11TextUnitsResponse\x121\n" +
@@ -4593,7 +8036,10 @@ const file_proto_gibram_proto_rawDesc = "" +
 	"nextCursor\"H\n" +
 	"\x18ListRelationshipsRequest\x12\x16\n" +
 	"\x06cursor\x18\x01 \x01(\x04R\x06cursor\x12\x14\n" +
-	"\x05limit\x18\x02 \x01(\x05R\x05limit\"B\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"C\n" +
+	"\x11BulkCommitRequest\x12\x18\n" +
+	"\aworkers\x18\x01 \x01(\x05R\aworkers\x12\x14\n" +
+	"\x05async\x18\x02 \x01(\bR\x05async\"B\n" +
 	"\x0fPipelineRequest\x12/\n" +
 	"\bcommands\x18\x01 \x03(\v2\x13.gibram.v1.EnvelopeR\bcommands\"E\n" +
 	"\x10PipelineResponse\x121\n" +
@@ -4609,7 +8055,27 @@ const file_proto_gibram_proto_rawDesc = "" +
 	"\x11total_communities\x18\x02 \x01(\x05R\x10totalCommunities\x1a>\n" +
 	"\x10LevelCountsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\x05R\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\"!\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\"\xe9\x01\n" +
+	"\x13RebuildIndexRequest\x12>\n" +
+	"\x0etextunit_index\x18\x01 \x01(\v2\x17.gibram.v1.IndexOptionsR\rtextunitIndex\x12:\n" +
+	"\fentity_index\x18\x02 \x01(\v2\x17.gibram.v1.IndexOptionsR\ventityIndex\x12@\n" +
+	"\x0fcommunity_index\x18\x03 \x01(\v2\x17.gibram.v1.IndexOptionsR\x0ecommunityIndex\x12\x14\n" +
+	"\x05async\x18\x04 \x01(\bR\x05async\"/\n" +
+	"\x14RebuildStatusRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\"\xdf\x01\n" +
+	"\vRebuildTask\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x14\n" +
+	"\x05index\x18\x04 \x01(\tR\x05index\x12\x1a\n" +
+	"\bprogress\x18\x05 \x01(\x01R\bprogress\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\x06 \x01(\x03R\tstartTime\x12\x19\n" +
+	"\bend_time\x18\a \x01(\x03R\aendTime\x12\x14\n" +
+	"\x05error\x18\b \x01(\tR\x05error\"E\n" +
+	"\x15RebuildStatusResponse\x12,\n" +
+	"\x05tasks\x18\x01 \x03(\v2\x16.gibram.v1.RebuildTaskR\x05tasks\"!\n" +
 	"\vSaveRequest\x12\x12\n" +
 	"\x04path\x18\x01 \x01(\tR\x04path\"$\n" +
 	"\x0eRestoreRequest\x12\x12\n" +
@@ -4643,7 +8109,51 @@ const file_proto_gibram_proto_rawDesc = "" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12\x15\n" +
 	"\x06key_id\x18\x03 \x01(\tR\x05keyId\x12 \n" +
-	"\vpermissions\x18\x04 \x03(\tR\vpermissions*\xdd\r\n" +
+	"\vpermissions\x18\x04 \x03(\tR\vpermissions\")\n" +
+	"\x11SlowLogGetRequest\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\x05R\x05count\"\x85\x02\n" +
+	"\fSlowLogEntry\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x1c\n" +
+	"\ttimestamp\x18\x02 \x01(\x03R\ttimestamp\x12'\n" +
+	"\x0fduration_micros\x18\x03 \x01(\x03R\x0edurationMicros\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x04 \x01(\tR\tsessionId\x12\x15\n" +
+	"\x06key_id\x18\x05 \x01(\tR\x05keyId\x12\x18\n" +
+	"\acommand\x18\x06 \x01(\tR\acommand\x12\x12\n" +
+	"\x04args\x18\a \x01(\tR\x04args\x12\x19\n" +
+	"\bquery_id\x18\b \x01(\x04R\aqueryId\x12\x1f\n" +
+	"\vclient_addr\x18\t \x01(\tR\n" +
+	"clientAddr\"\\\n" +
+	"\x0fSlowLogResponse\x121\n" +
+	"\aentries\x18\x01 \x03(\v2\x17.gibram.v1.SlowLogEntryR\aentries\x12\x16\n" +
+	"\x06length\x18\x02 \x01(\x05R\x06length\"\xa0\x01\n" +
+	"\x12RecallCheckRequest\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\tR\x05index\x12\f\n" +
+	"\x01k\x18\x02 \x01(\x05R\x01k\x12\x0e\n" +
+	"\x02ef\x18\x03 \x01(\x05R\x02ef\x12\x18\n" +
+	"\asamples\x18\x04 \x01(\x05R\asamples\x12<\n" +
+	"\rquery_vectors\x18\x05 \x03(\v2\x17.gibram.v1.SearchVectorR\fqueryVectors\"h\n" +
+	"\fDistribution\x12\x10\n" +
+	"\x03avg\x18\x01 \x01(\x01R\x03avg\x12\x10\n" +
+	"\x03p50\x18\x02 \x01(\x01R\x03p50\x12\x10\n" +
+	"\x03p95\x18\x03 \x01(\x01R\x03p95\x12\x10\n" +
+	"\x03p99\x18\x04 \x01(\x01R\x03p99\x12\x10\n" +
+	"\x03max\x18\x05 \x01(\x01R\x03max\"\xea\x02\n" +
+	"\vIndexRecall\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\tR\x05index\x12\x18\n" +
+	"\avectors\x18\x02 \x01(\x04R\avectors\x12\x18\n" +
+	"\aqueries\x18\x03 \x01(\x05R\aqueries\x12\f\n" +
+	"\x01k\x18\x04 \x01(\x05R\x01k\x12\x0e\n" +
+	"\x02ef\x18\x05 \x01(\x05R\x02ef\x12\x16\n" +
+	"\x06recall\x18\x06 \x01(\x01R\x06recall\x12\x1d\n" +
+	"\n" +
+	"min_recall\x18\a \x01(\x01R\tminRecall\x12>\n" +
+	"\x0elatency_micros\x18\b \x01(\v2\x17.gibram.v1.DistributionR\rlatencyMicros\x12I\n" +
+	"\x14exact_latency_micros\x18\t \x01(\v2\x17.gibram.v1.DistributionR\x12exactLatencyMicros\x121\n" +
+	"\avisited\x18\n" +
+	" \x01(\v2\x17.gibram.v1.DistributionR\avisited\"G\n" +
+	"\x13RecallCheckResponse\x120\n" +
+	"\aindices\x18\x01 \x03(\v2\x16.gibram.v1.IndexRecallR\aindices*\xa4\x12\n" +
 	"\vCommandType\x12\x0f\n" +
 	"\vCMD_UNKNOWN\x10\x00\x12\f\n" +
 	"\bCMD_PING\x10\x01\x12\f\n" +
@@ -4683,18 +8193,29 @@ const file_proto_gibram_proto_rawDesc = "" +
 	"\x17CMD_HIERARCHICAL_LEIDEN\x106\x12\x15\n" +
 	"\x11CMD_REBUILD_INDEX\x107\x12\x1a\n" +
 	"\x16CMD_COMMUNITY_RESPONSE\x108\x12\x1c\n" +
-	"\x18CMD_COMMUNITIES_RESPONSE\x109\x12\r\n" +
+	"\x18CMD_COMMUNITIES_RESPONSE\x109\x12\x16\n" +
+	"\x12CMD_REBUILD_STATUS\x10:\x12\x1f\n" +
+	"\x1bCMD_REBUILD_STATUS_RESPONSE\x10;\x12\r\n" +
 	"\tCMD_QUERY\x10<\x12\x16\n" +
 	"\x12CMD_QUERY_RESPONSE\x10=\x12\x0f\n" +
 	"\vCMD_EXPLAIN\x10>\x12\x18\n" +
-	"\x14CMD_EXPLAIN_RESPONSE\x10?\x12\x15\n" +
+	"\x14CMD_EXPLAIN_RESPONSE\x10?\x12\x0e\n" +
+	"\n" +
+	"CMD_SEARCH\x10@\x12\x17\n" +
+	"\x13CMD_SEARCH_RESPONSE\x10A\x12\f\n" +
+	"\bCMD_PATH\x10B\x12\x15\n" +
+	"\x11CMD_PATH_RESPONSE\x10C\x12\x10\n" +
+	"\fCMD_SUBGRAPH\x10D\x12\x19\n" +
+	"\x15CMD_SUBGRAPH_RESPONSE\x10E\x12\x15\n" +
 	"\x11CMD_LIST_SESSIONS\x10F\x12\x16\n" +
 	"\x12CMD_DELETE_SESSION\x10G\x12\x14\n" +
 	"\x10CMD_SESSION_INFO\x10H\x12\x17\n" +
 	"\x13CMD_SET_SESSION_TTL\x10I\x12\x15\n" +
 	"\x11CMD_TOUCH_SESSION\x10J\x12\x19\n" +
 	"\x15CMD_SESSIONS_RESPONSE\x10K\x12\x1d\n" +
-	"\x19CMD_SESSION_INFO_RESPONSE\x10L\x12\x15\n" +
+	"\x19CMD_SESSION_INFO_RESPONSE\x10L\x12\x16\n" +
+	"\x12CMD_CREATE_SESSION\x10M\x12\x17\n" +
+	"\x13CMD_SET_QUERY_CACHE\x10N\x12\x15\n" +
 	"\x11CMD_MSET_ENTITIES\x10P\x12\x15\n" +
 	"\x11CMD_MGET_ENTITIES\x10Q\x12\x16\n" +
 	"\x12CMD_MSET_DOCUMENTS\x10R\x12\x16\n" +
@@ -4708,7 +8229,9 @@ const file_proto_gibram_proto_rawDesc = "" +
 	"\x16CMD_TEXTUNITS_RESPONSE\x10Z\x12\x1e\n" +
 	"\x1aCMD_RELATIONSHIPS_RESPONSE\x10[\x12\x15\n" +
 	"\x11CMD_LIST_ENTITIES\x10\\\x12\x1a\n" +
-	"\x16CMD_LIST_RELATIONSHIPS\x10]\x12\x10\n" +
+	"\x16CMD_LIST_RELATIONSHIPS\x10]\x12\x12\n" +
+	"\x0eCMD_BULK_BEGIN\x10^\x12\x13\n" +
+	"\x0fCMD_BULK_COMMIT\x10_\x12\x10\n" +
 	"\fCMD_PIPELINE\x10d\x12\x19\n" +
 	"\x15CMD_PIPELINE_RESPONSE\x10e\x12\x0e\n" +
 	"\n" +
@@ -4723,7 +8246,19 @@ const file_proto_gibram_proto_rawDesc = "" +
 	"\x0eCMD_WAL_STATUS\x10v\x12\x17\n" +
 	"\x13CMD_BACKUP_RESPONSE\x10w\x12\f\n" +
 	"\bCMD_AUTH\x10x\x12\x15\n" +
-	"\x11CMD_AUTH_RESPONSE\x10yB,Z*github.com/gibram-io/gibram/proto/gibrampbb\x06proto3"
+	"\x11CMD_AUTH_RESPONSE\x10y\x12\x14\n" +
+	"\x0fCMD_SLOWLOG_GET\x10\x82\x01\x12\x16\n" +
+	"\x11CMD_SLOWLOG_RESET\x10\x83\x01\x12\x14\n" +
+	"\x0fCMD_SLOWLOG_LEN\x10\x84\x01\x12\x19\n" +
+	"\x14CMD_SLOWLOG_RESPONSE\x10\x85\x01\x12\x15\n" +
+	"\x10CMD_RECALL_CHECK\x10\x86\x01\x12\x1e\n" +
+	"\x19CMD_RECALL_CHECK_RESPONSE\x10\x87\x01\x12\x14\n" +
+	"\x0fCMD_QUERY_GRAPH\x10\x8c\x01\x12\x1d\n" +
+	"\x18CMD_QUERY_GRAPH_RESPONSE\x10\x8d\x01\x12\x16\n" +
+	"\x11CMD_GLOBAL_SEARCH\x10\x8e\x01\x12\x1f\n" +
+	"\x1aCMD_GLOBAL_SEARCH_RESPONSE\x10\x8f\x01\x12\x14\n" +
+	"\x0fCMD_QUERY_BATCH\x10\x90\x01\x12\x1d\n" +
+	"\x18CMD_QUERY_BATCH_RESPONSE\x10\x91\x01B,Z*github.com/gibram-io/gibram/proto/gibrampbb\x06proto3"
 
 var (
 	file_proto_gibram_proto_rawDescOnce sync.Once
@@ -4738,7 +8273,7 @@ func file_proto_gibram_proto_rawDescGZIP() []byte {
 }
 
 var file_proto_gibram_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_proto_gibram_proto_msgTypes = make([]protoimpl.MessageInfo, 68)
+var file_proto_gibram_proto_msgTypes = make([]protoimpl.MessageInfo, 110)
 var file_proto_gibram_proto_goTypes = []any{
 	(CommandType)(0),                   // 0: gibram.v1.CommandType
 	(*Envelope)(nil),                   // 1: gibram.v1.Envelope
@@ -4747,101 +8282,188 @@ var file_proto_gibram_proto_goTypes = []any{
 	(*OkWithID)(nil),                   // 4: gibram.v1.OkWithID
 	(*InfoResponse)(nil),               // 5: gibram.v1.InfoResponse
 	(*SessionInfo)(nil),                // 6: gibram.v1.SessionInfo
-	(*ListSessionsResponse)(nil),       // 7: gibram.v1.ListSessionsResponse
-	(*DeleteSessionRequest)(nil),       // 8: gibram.v1.DeleteSessionRequest
-	(*SessionInfoRequest)(nil),         // 9: gibram.v1.SessionInfoRequest
-	(*SetSessionTTLRequest)(nil),       // 10: gibram.v1.SetSessionTTLRequest
-	(*TouchSessionRequest)(nil),        // 11: gibram.v1.TouchSessionRequest
-	(*Document)(nil),                   // 12: gibram.v1.Document
-	(*AddDocumentRequest)(nil),         // 13: gibram.v1.AddDocumentRequest
-	(*TextUnit)(nil),                   // 14: gibram.v1.TextUnit
-	(*AddTextUnitRequest)(nil),         // 15: gibram.v1.AddTextUnitRequest
-	(*Entity)(nil),                     // 16: gibram.v1.Entity
-	(*AddEntityRequest)(nil),           // 17: gibram.v1.AddEntityRequest
-	(*GetEntityByTitleRequest)(nil),    // 18: gibram.v1.GetEntityByTitleRequest
-	(*UpdateEntityDescRequest)(nil),    // 19: gibram.v1.UpdateEntityDescRequest
-	(*Relationship)(nil),               // 20: gibram.v1.Relationship
-	(*AddRelationshipRequest)(nil),     // 21: gibram.v1.AddRelationshipRequest
-	(*Community)(nil),                  // 22: gibram.v1.Community
-	(*AddCommunityRequest)(nil),        // 23: gibram.v1.AddCommunityRequest
-	(*ComputeCommunitiesRequest)(nil),  // 24: gibram.v1.ComputeCommunitiesRequest
-	(*ComputeCommunitiesResponse)(nil), // 25: gibram.v1.ComputeCommunitiesResponse
-	(*LinkTextUnitEntityRequest)(nil),  // 26: gibram.v1.LinkTextUnitEntityRequest
-	(*QueryRequest)(nil),               // 27: gibram.v1.QueryRequest
-	(*TextUnitResult)(nil),             // 28: gibram.v1.TextUnitResult
-	(*EntityResult)(nil),               // 29: gibram.v1.EntityResult
-	(*CommunityResult)(nil),            // 30: gibram.v1.CommunityResult
-	(*RelationshipResult)(nil),         // 31: gibram.v1.RelationshipResult
-	(*QueryStats)(nil),                 // 32: gibram.v1.QueryStats
-	(*QueryResponse)(nil),              // 33: gibram.v1.QueryResponse
-	(*ExplainRequest)(nil),             // 34: gibram.v1.ExplainRequest
-	(*SeedInfo)(nil),                   // 35: gibram.v1.SeedInfo
-	(*TraversalStep)(nil),              // 36: gibram.v1.TraversalStep
-	(*ExplainResponse)(nil),            // 37: gibram.v1.ExplainResponse
-	(*GetByIDRequest)(nil),             // 38: gibram.v1.GetByIDRequest
-	(*DeleteByIDRequest)(nil),          // 39: gibram.v1.DeleteByIDRequest
-	(*HealthResponse)(nil),             // 40: gibram.v1.HealthResponse
-	(*ListEntitiesRequest)(nil),        // 41: gibram.v1.ListEntitiesRequest
-	(*MSetEntitiesRequest)(nil),        // 42: gibram.v1.MSetEntitiesRequest
-	(*MGetEntitiesRequest)(nil),        // 43: gibram.v1.MGetEntitiesRequest
-	(*EntitiesResponse)(nil),           // 44: gibram.v1.EntitiesResponse
-	(*MSetDocumentsRequest)(nil),       // 45: gibram.v1.MSetDocumentsRequest
-	(*MGetDocumentsRequest)(nil),       // 46: gibram.v1.MGetDocumentsRequest
-	(*DocumentsResponse)(nil),          // 47: gibram.v1.DocumentsResponse
-	(*MSetTextUnitsRequest)(nil),       // 48: gibram.v1.MSetTextUnitsRequest
-	(*MGetTextUnitsRequest)(nil),       // 49: gibram.v1.MGetTextUnitsRequest
-	(*TextUnitsResponse)(nil),          // 50: gibram.v1.TextUnitsResponse
-	(*MSetRelationshipsRequest)(nil),   // 51: gibram.v1.MSetRelationshipsRequest
-	(*MGetRelationshipsRequest)(nil),   // 52: gibram.v1.MGetRelationshipsRequest
-	(*RelationshipsResponse)(nil),      // 53: gibram.v1.RelationshipsResponse
-	(*ListRelationshipsRequest)(nil),   // 54: gibram.v1.ListRelationshipsRequest
-	(*PipelineRequest)(nil),            // 55: gibram.v1.PipelineRequest
-	(*PipelineResponse)(nil),           // 56: gibram.v1.PipelineResponse
-	(*HierarchicalLeidenRequest)(nil),  // 57: gibram.v1.HierarchicalLeidenRequest
-	(*HierarchicalLeidenResponse)(nil), // 58: gibram.v1.HierarchicalLeidenResponse
-	(*SaveRequest)(nil),                // 59: gibram.v1.SaveRequest
-	(*RestoreRequest)(nil),             // 60: gibram.v1.RestoreRequest
-	(*BackupStatusResponse)(nil),       // 61: gibram.v1.BackupStatusResponse
-	(*LastSaveResponse)(nil),           // 62: gibram.v1.LastSaveResponse
-	(*WALStatusResponse)(nil),          // 63: gibram.v1.WALStatusResponse
-	(*WALTruncateRequest)(nil),         // 64: gibram.v1.WALTruncateRequest
-	(*AuthRequest)(nil),                // 65: gibram.v1.AuthRequest
-	(*AuthResponse)(nil),               // 66: gibram.v1.AuthResponse
-	nil,                                // 67: gibram.v1.HealthResponse.ComponentsEntry
-	nil,                                // 68: gibram.v1.HierarchicalLeidenResponse.LevelCountsEntry
+	(*IndexOptions)(nil),               // 7: gibram.v1.IndexOptions
+	(*CreateSessionRequest)(nil),       // 8: gibram.v1.CreateSessionRequest
+	(*ListSessionsResponse)(nil),       // 9: gibram.v1.ListSessionsResponse
+	(*DeleteSessionRequest)(nil),       // 10: gibram.v1.DeleteSessionRequest
+	(*SessionInfoRequest)(nil),         // 11: gibram.v1.SessionInfoRequest
+	(*SetSessionTTLRequest)(nil),       // 12: gibram.v1.SetSessionTTLRequest
+	(*SetQueryCacheRequest)(nil),       // 13: gibram.v1.SetQueryCacheRequest
+	(*TouchSessionRequest)(nil),        // 14: gibram.v1.TouchSessionRequest
+	(*Document)(nil),                   // 15: gibram.v1.Document
+	(*AddDocumentRequest)(nil),         // 16: gibram.v1.AddDocumentRequest
+	(*TextUnit)(nil),                   // 17: gibram.v1.TextUnit
+	(*AddTextUnitRequest)(nil),         // 18: gibram.v1.AddTextUnitRequest
+	(*Entity)(nil),                     // 19: gibram.v1.Entity
+	(*AddEntityRequest)(nil),           // 20: gibram.v1.AddEntityRequest
+	(*GetEntityByTitleRequest)(nil),    // 21: gibram.v1.GetEntityByTitleRequest
+	(*UpdateEntityDescRequest)(nil),    // 22: gibram.v1.UpdateEntityDescRequest
+	(*Relationship)(nil),               // 23: gibram.v1.Relationship
+	(*AddRelationshipRequest)(nil),     // 24: gibram.v1.AddRelationshipRequest
+	(*Community)(nil),                  // 25: gibram.v1.Community
+	(*AddCommunityRequest)(nil),        // 26: gibram.v1.AddCommunityRequest
+	(*ComputeCommunitiesRequest)(nil),  // 27: gibram.v1.ComputeCommunitiesRequest
+	(*ComputeCommunitiesResponse)(nil), // 28: gibram.v1.ComputeCommunitiesResponse
+	(*LinkTextUnitEntityRequest)(nil),  // 29: gibram.v1.LinkTextUnitEntityRequest
+	(*QueryRequest)(nil),               // 30: gibram.v1.QueryRequest
+	(*TextUnitResult)(nil),             // 31: gibram.v1.TextUnitResult
+	(*EntityResult)(nil),               // 32: gibram.v1.EntityResult
+	(*CommunityResult)(nil),            // 33: gibram.v1.CommunityResult
+	(*RelationshipResult)(nil),         // 34: gibram.v1.RelationshipResult
+	(*QueryStats)(nil),                 // 35: gibram.v1.QueryStats
+	(*QueryResponse)(nil),              // 36: gibram.v1.QueryResponse
+	(*TokenUsage)(nil),                 // 37: gibram.v1.TokenUsage
+	(*QueryBatchRequest)(nil),          // 38: gibram.v1.QueryBatchRequest
+	(*QueryBatchResult)(nil),           // 39: gibram.v1.QueryBatchResult
+	(*QueryBatchResponse)(nil),         // 40: gibram.v1.QueryBatchResponse
+	(*SearchVector)(nil),               // 41: gibram.v1.SearchVector
+	(*SearchRequest)(nil),              // 42: gibram.v1.SearchRequest
+	(*SearchHit)(nil),                  // 43: gibram.v1.SearchHit
+	(*SearchHits)(nil),                 // 44: gibram.v1.SearchHits
+	(*SearchResponse)(nil),             // 45: gibram.v1.SearchResponse
+	(*PathRequest)(nil),                // 46: gibram.v1.PathRequest
+	(*EntityPath)(nil),                 // 47: gibram.v1.EntityPath
+	(*PathResponse)(nil),               // 48: gibram.v1.PathResponse
+	(*SubgraphRequest)(nil),            // 49: gibram.v1.SubgraphRequest
+	(*SubgraphNode)(nil),               // 50: gibram.v1.SubgraphNode
+	(*SubgraphResponse)(nil),           // 51: gibram.v1.SubgraphResponse
+	(*GraphQueryRequest)(nil),          // 52: gibram.v1.GraphQueryRequest
+	(*GraphValue)(nil),                 // 53: gibram.v1.GraphValue
+	(*GraphRow)(nil),                   // 54: gibram.v1.GraphRow
+	(*GraphQueryResponse)(nil),         // 55: gibram.v1.GraphQueryResponse
+	(*GlobalSearchRequest)(nil),        // 56: gibram.v1.GlobalSearchRequest
+	(*CommunityReport)(nil),            // 57: gibram.v1.CommunityReport
+	(*ReportBatch)(nil),                // 58: gibram.v1.ReportBatch
+	(*GlobalSearchResponse)(nil),       // 59: gibram.v1.GlobalSearchResponse
+	(*ExplainRequest)(nil),             // 60: gibram.v1.ExplainRequest
+	(*SeedInfo)(nil),                   // 61: gibram.v1.SeedInfo
+	(*TraversalStep)(nil),              // 62: gibram.v1.TraversalStep
+	(*Demotion)(nil),                   // 63: gibram.v1.Demotion
+	(*ExplainResponse)(nil),            // 64: gibram.v1.ExplainResponse
+	(*GetByIDRequest)(nil),             // 65: gibram.v1.GetByIDRequest
+	(*DeleteByIDRequest)(nil),          // 66: gibram.v1.DeleteByIDRequest
+	(*HealthResponse)(nil),             // 67: gibram.v1.HealthResponse
+	(*ListEntitiesRequest)(nil),        // 68: gibram.v1.ListEntitiesRequest
+	(*MSetEntitiesRequest)(nil),        // 69: gibram.v1.MSetEntitiesRequest
+	(*MGetEntitiesRequest)(nil),        // 70: gibram.v1.MGetEntitiesRequest
+	(*EntitiesResponse)(nil),           // 71: gibram.v1.EntitiesResponse
+	(*MSetDocumentsRequest)(nil),       // 72: gibram.v1.MSetDocumentsRequest
+	(*MGetDocumentsRequest)(nil),       // 73: gibram.v1.MGetDocumentsRequest
+	(*DocumentsResponse)(nil),          // 74: gibram.v1.DocumentsResponse
+	(*MSetTextUnitsRequest)(nil),       // 75: gibram.v1.MSetTextUnitsRequest
+	(*MGetTextUnitsRequest)(nil),       // 76: gibram.v1.MGetTextUnitsRequest
+	(*TextUnitsResponse)(nil),          // 77: gibram.v1.TextUnitsResponse
+	(*MSetRelationshipsRequest)(nil),   // 78: gibram.v1.MSetRelationshipsRequest
+	(*MGetRelationshipsRequest)(nil),   // 79: gibram.v1.MGetRelationshipsRequest
+	(*RelationshipsResponse)(nil),      // 80: gibram.v1.RelationshipsResponse
+	(*ListRelationshipsRequest)(nil),   // 81: gibram.v1.ListRelationshipsRequest
+	(*BulkCommitRequest)(nil),          // 82: gibram.v1.BulkCommitRequest
+	(*PipelineRequest)(nil),            // 83: gibram.v1.PipelineRequest
+	(*PipelineResponse)(nil),           // 84: gibram.v1.PipelineResponse
+	(*HierarchicalLeidenRequest)(nil),  // 85: gibram.v1.HierarchicalLeidenRequest
+	(*HierarchicalLeidenResponse)(nil), // 86: gibram.v1.HierarchicalLeidenResponse
+	(*RebuildIndexRequest)(nil),        // 87: gibram.v1.RebuildIndexRequest
+	(*RebuildStatusRequest)(nil),       // 88: gibram.v1.RebuildStatusRequest
+	(*RebuildTask)(nil),                // 89: gibram.v1.RebuildTask
+	(*RebuildStatusResponse)(nil),      // 90: gibram.v1.RebuildStatusResponse
+	(*SaveRequest)(nil),                // 91: gibram.v1.SaveRequest
+	(*RestoreRequest)(nil),             // 92: gibram.v1.RestoreRequest
+	(*BackupStatusResponse)(nil),       // 93: gibram.v1.BackupStatusResponse
+	(*LastSaveResponse)(nil),           // 94: gibram.v1.LastSaveResponse
+	(*WALStatusResponse)(nil),          // 95: gibram.v1.WALStatusResponse
+	(*WALTruncateRequest)(nil),         // 96: gibram.v1.WALTruncateRequest
+	(*AuthRequest)(nil),                // 97: gibram.v1.AuthRequest
+	(*AuthResponse)(nil),               // 98: gibram.v1.AuthResponse
+	(*SlowLogGetRequest)(nil),          // 99: gibram.v1.SlowLogGetRequest
+	(*SlowLogEntry)(nil),               // 100: gibram.v1.SlowLogEntry
+	(*SlowLogResponse)(nil),            // 101: gibram.v1.SlowLogResponse
+	(*RecallCheckRequest)(nil),         // 102: gibram.v1.RecallCheckRequest
+	(*Distribution)(nil),               // 103: gibram.v1.Distribution
+	(*IndexRecall)(nil),                // 104: gibram.v1.IndexRecall
+	(*RecallCheckResponse)(nil),        // 105: gibram.v1.RecallCheckResponse
+	nil,                                // 106: gibram.v1.QueryRequest.FilterAttrsEntry
+	nil,                                // 107: gibram.v1.QueryRequest.RestartWeightsEntry
+	nil,                                // 108: gibram.v1.SearchRequest.FilterAttrsEntry
+	nil,                                // 109: gibram.v1.HealthResponse.ComponentsEntry
+	nil,                                // 110: gibram.v1.HierarchicalLeidenResponse.LevelCountsEntry
 }
 var file_proto_gibram_proto_depIdxs = []int32{
-	0,  // 0: gibram.v1.Envelope.cmd_type:type_name -> gibram.v1.CommandType
-	6,  // 1: gibram.v1.ListSessionsResponse.sessions:type_name -> gibram.v1.SessionInfo
-	22, // 2: gibram.v1.ComputeCommunitiesResponse.communities:type_name -> gibram.v1.Community
-	14, // 3: gibram.v1.TextUnitResult.textunit:type_name -> gibram.v1.TextUnit
-	16, // 4: gibram.v1.EntityResult.entity:type_name -> gibram.v1.Entity
-	22, // 5: gibram.v1.CommunityResult.community:type_name -> gibram.v1.Community
-	20, // 6: gibram.v1.RelationshipResult.relationship:type_name -> gibram.v1.Relationship
-	28, // 7: gibram.v1.QueryResponse.textunits:type_name -> gibram.v1.TextUnitResult
-	29, // 8: gibram.v1.QueryResponse.entities:type_name -> gibram.v1.EntityResult
-	30, // 9: gibram.v1.QueryResponse.communities:type_name -> gibram.v1.CommunityResult
-	31, // 10: gibram.v1.QueryResponse.relationships:type_name -> gibram.v1.RelationshipResult
-	32, // 11: gibram.v1.QueryResponse.stats:type_name -> gibram.v1.QueryStats
-	35, // 12: gibram.v1.ExplainResponse.seeds:type_name -> gibram.v1.SeedInfo
-	36, // 13: gibram.v1.ExplainResponse.traversal:type_name -> gibram.v1.TraversalStep
-	67, // 14: gibram.v1.HealthResponse.components:type_name -> gibram.v1.HealthResponse.ComponentsEntry
-	17, // 15: gibram.v1.MSetEntitiesRequest.entities:type_name -> gibram.v1.AddEntityRequest
-	16, // 16: gibram.v1.EntitiesResponse.entities:type_name -> gibram.v1.Entity
-	13, // 17: gibram.v1.MSetDocumentsRequest.documents:type_name -> gibram.v1.AddDocumentRequest
-	12, // 18: gibram.v1.DocumentsResponse.documents:type_name -> gibram.v1.Document
-	15, // 19: gibram.v1.MSetTextUnitsRequest.textunits:type_name -> gibram.v1.AddTextUnitRequest
-	14, // 20: gibram.v1.TextUnitsResponse.textunits:type_name -> gibram.v1.TextUnit
-	21, // 21: gibram.v1.MSetRelationshipsRequest.relationships:type_name -> gibram.v1.AddRelationshipRequest
-	20, // 22: gibram.v1.RelationshipsResponse.relationships:type_name -> gibram.v1.Relationship
-	1,  // 23: gibram.v1.PipelineRequest.commands:type_name -> gibram.v1.Envelope
-	1,  // 24: gibram.v1.PipelineResponse.responses:type_name -> gibram.v1.Envelope
-	68, // 25: gibram.v1.HierarchicalLeidenResponse.level_counts:type_name -> gibram.v1.HierarchicalLeidenResponse.LevelCountsEntry
-	26, // [26:26] is the sub-list for method output_type
-	26, // [26:26] is the sub-list for method input_type
-	26, // [26:26] is the sub-list for extension type_name
-	26, // [26:26] is the sub-list for extension extendee
-	0,  // [0:26] is the sub-list for field type_name
+	0,   // 0: gibram.v1.Envelope.cmd_type:type_name -> gibram.v1.CommandType
+	7,   // 1: gibram.v1.SessionInfo.textunit_index:type_name -> gibram.v1.IndexOptions
+	7,   // 2: gibram.v1.SessionInfo.entity_index:type_name -> gibram.v1.IndexOptions
+	7,   // 3: gibram.v1.SessionInfo.community_index:type_name -> gibram.v1.IndexOptions
+	7,   // 4: gibram.v1.CreateSessionRequest.textunit_index:type_name -> gibram.v1.IndexOptions
+	7,   // 5: gibram.v1.CreateSessionRequest.entity_index:type_name -> gibram.v1.IndexOptions
+	7,   // 6: gibram.v1.CreateSessionRequest.community_index:type_name -> gibram.v1.IndexOptions
+	6,   // 7: gibram.v1.ListSessionsResponse.sessions:type_name -> gibram.v1.SessionInfo
+	25,  // 8: gibram.v1.ComputeCommunitiesResponse.communities:type_name -> gibram.v1.Community
+	106, // 9: gibram.v1.QueryRequest.filter_attrs:type_name -> gibram.v1.QueryRequest.FilterAttrsEntry
+	107, // 10: gibram.v1.QueryRequest.restart_weights:type_name -> gibram.v1.QueryRequest.RestartWeightsEntry
+	17,  // 11: gibram.v1.TextUnitResult.textunit:type_name -> gibram.v1.TextUnit
+	19,  // 12: gibram.v1.EntityResult.entity:type_name -> gibram.v1.Entity
+	25,  // 13: gibram.v1.CommunityResult.community:type_name -> gibram.v1.Community
+	23,  // 14: gibram.v1.RelationshipResult.relationship:type_name -> gibram.v1.Relationship
+	31,  // 15: gibram.v1.QueryResponse.textunits:type_name -> gibram.v1.TextUnitResult
+	32,  // 16: gibram.v1.QueryResponse.entities:type_name -> gibram.v1.EntityResult
+	33,  // 17: gibram.v1.QueryResponse.communities:type_name -> gibram.v1.CommunityResult
+	34,  // 18: gibram.v1.QueryResponse.relationships:type_name -> gibram.v1.RelationshipResult
+	35,  // 19: gibram.v1.QueryResponse.stats:type_name -> gibram.v1.QueryStats
+	37,  // 20: gibram.v1.QueryResponse.token_usage:type_name -> gibram.v1.TokenUsage
+	30,  // 21: gibram.v1.QueryBatchRequest.queries:type_name -> gibram.v1.QueryRequest
+	30,  // 22: gibram.v1.QueryBatchRequest.shared:type_name -> gibram.v1.QueryRequest
+	41,  // 23: gibram.v1.QueryBatchRequest.query_vectors:type_name -> gibram.v1.SearchVector
+	36,  // 24: gibram.v1.QueryBatchResult.response:type_name -> gibram.v1.QueryResponse
+	39,  // 25: gibram.v1.QueryBatchResponse.results:type_name -> gibram.v1.QueryBatchResult
+	41,  // 26: gibram.v1.SearchRequest.query_vectors:type_name -> gibram.v1.SearchVector
+	108, // 27: gibram.v1.SearchRequest.filter_attrs:type_name -> gibram.v1.SearchRequest.FilterAttrsEntry
+	17,  // 28: gibram.v1.SearchHit.textunit:type_name -> gibram.v1.TextUnit
+	19,  // 29: gibram.v1.SearchHit.entity:type_name -> gibram.v1.Entity
+	25,  // 30: gibram.v1.SearchHit.community:type_name -> gibram.v1.Community
+	43,  // 31: gibram.v1.SearchHits.hits:type_name -> gibram.v1.SearchHit
+	44,  // 32: gibram.v1.SearchResponse.results:type_name -> gibram.v1.SearchHits
+	19,  // 33: gibram.v1.EntityPath.entities:type_name -> gibram.v1.Entity
+	23,  // 34: gibram.v1.EntityPath.relationships:type_name -> gibram.v1.Relationship
+	47,  // 35: gibram.v1.PathResponse.paths:type_name -> gibram.v1.EntityPath
+	19,  // 36: gibram.v1.SubgraphNode.entity:type_name -> gibram.v1.Entity
+	50,  // 37: gibram.v1.SubgraphResponse.nodes:type_name -> gibram.v1.SubgraphNode
+	23,  // 38: gibram.v1.SubgraphResponse.relationships:type_name -> gibram.v1.Relationship
+	19,  // 39: gibram.v1.GraphValue.entity:type_name -> gibram.v1.Entity
+	23,  // 40: gibram.v1.GraphValue.relationship:type_name -> gibram.v1.Relationship
+	53,  // 41: gibram.v1.GraphRow.values:type_name -> gibram.v1.GraphValue
+	54,  // 42: gibram.v1.GraphQueryResponse.rows:type_name -> gibram.v1.GraphRow
+	25,  // 43: gibram.v1.CommunityReport.community:type_name -> gibram.v1.Community
+	57,  // 44: gibram.v1.ReportBatch.reports:type_name -> gibram.v1.CommunityReport
+	58,  // 45: gibram.v1.GlobalSearchResponse.batches:type_name -> gibram.v1.ReportBatch
+	61,  // 46: gibram.v1.ExplainResponse.seeds:type_name -> gibram.v1.SeedInfo
+	62,  // 47: gibram.v1.ExplainResponse.traversal:type_name -> gibram.v1.TraversalStep
+	63,  // 48: gibram.v1.ExplainResponse.demotions:type_name -> gibram.v1.Demotion
+	109, // 49: gibram.v1.HealthResponse.components:type_name -> gibram.v1.HealthResponse.ComponentsEntry
+	20,  // 50: gibram.v1.MSetEntitiesRequest.entities:type_name -> gibram.v1.AddEntityRequest
+	19,  // 51: gibram.v1.EntitiesResponse.entities:type_name -> gibram.v1.Entity
+	16,  // 52: gibram.v1.MSetDocumentsRequest.documents:type_name -> gibram.v1.AddDocumentRequest
+	15,  // 53: gibram.v1.DocumentsResponse.documents:type_name -> gibram.v1.Document
+	18,  // 54: gibram.v1.MSetTextUnitsRequest.textunits:type_name -> gibram.v1.AddTextUnitRequest
+	17,  // 55: gibram.v1.TextUnitsResponse.textunits:type_name -> gibram.v1.TextUnit
+	24,  // 56: gibram.v1.MSetRelationshipsRequest.relationships:type_name -> gibram.v1.AddRelationshipRequest
+	23,  // 57: gibram.v1.RelationshipsResponse.relationships:type_name -> gibram.v1.Relationship
+	1,   // 58: gibram.v1.PipelineRequest.commands:type_name -> gibram.v1.Envelope
+	1,   // 59: gibram.v1.PipelineResponse.responses:type_name -> gibram.v1.Envelope
+	110, // 60: gibram.v1.HierarchicalLeidenResponse.level_counts:type_name -> gibram.v1.HierarchicalLeidenResponse.LevelCountsEntry
+	7,   // 61: gibram.v1.RebuildIndexRequest.textunit_index:type_name -> gibram.v1.IndexOptions
+	7,   // 62: gibram.v1.RebuildIndexRequest.entity_index:type_name -> gibram.v1.IndexOptions
+	7,   // 63: gibram.v1.RebuildIndexRequest.community_index:type_name -> gibram.v1.IndexOptions
+	89,  // 64: gibram.v1.RebuildStatusResponse.tasks:type_name -> gibram.v1.RebuildTask
+	100, // 65: gibram.v1.SlowLogResponse.entries:type_name -> gibram.v1.SlowLogEntry
+	41,  // 66: gibram.v1.RecallCheckRequest.query_vectors:type_name -> gibram.v1.SearchVector
+	103, // 67: gibram.v1.IndexRecall.latency_micros:type_name -> gibram.v1.Distribution
+	103, // 68: gibram.v1.IndexRecall.exact_latency_micros:type_name -> gibram.v1.Distribution
+	103, // 69: gibram.v1.IndexRecall.visited:type_name -> gibram.v1.Distribution
+	104, // 70: gibram.v1.RecallCheckResponse.indices:type_name -> gibram.v1.IndexRecall
+	71,  // [71:71] is the sub-list for method output_type
+	71,  // [71:71] is the sub-list for method input_type
+	71,  // [71:71] is the sub-list for extension type_name
+	71,  // [71:71] is the sub-list for extension extendee
+	0,   // [0:71] is the sub-list for field type_name
 }
 
 func init() { file_proto_gibram_proto_init() }
@@ -4855,7 +8477,7 @@ func file_proto_gibram_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_gibram_proto_rawDesc), len(file_proto_gibram_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   68,
+			NumMessages:   110,
 			NumExtensions: 0,
 			NumServices:   0,
 		},